@@ -0,0 +1,86 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	catalogimport "github.com/Kaushik1766/LibraryManagement/internal/catalog_import"
+	"github.com/Kaushik1766/LibraryManagement/internal/config"
+	"github.com/Kaushik1766/LibraryManagement/internal/db"
+	bookrepo "github.com/Kaushik1766/LibraryManagement/internal/repository/book_repo"
+)
+
+const usage = `usage: import [-dry-run] [-format csv|jsonl|marc|marcxml] <file>
+
+adds the titles in a CSV, JSON lines, MARC 21 or MARCXML file to the catalog
+and prints a report for every row. the format is taken from the file
+extension unless -format is given. nothing is written if any row is invalid.`
+
+func main() {
+	flag.Usage = func() { fmt.Println(usage) }
+	dryRun := flag.Bool("dry-run", false, "check the file without writing anything")
+	format := flag.String("format", "", "csv, jsonl, marc or marcxml")
+	flag.Parse()
+
+	if flag.NArg() != 1 {
+		flag.Usage()
+		os.Exit(2)
+	}
+	path := flag.Arg(0)
+
+	if *format == "" {
+		*format = filepath.Ext(path)
+	}
+	importFormat, err := catalogimport.FormatOf(*format)
+	if err != nil {
+		fmt.Println("unknown format, use -format csv, jsonl, marc or marcxml")
+		os.Exit(2)
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		fmt.Println(err.Error())
+		os.Exit(1)
+	}
+	defer file.Close()
+
+	rows, err := catalogimport.Parse(importFormat, file)
+	if err != nil {
+		fmt.Println(err.Error())
+		os.Exit(1)
+	}
+
+	// only the database settings matter here
+	cfg, err := config.Read(os.Getenv(config.PathEnv))
+	if err == nil {
+		err = cfg.Database.Validate()
+	}
+	if err != nil {
+		fmt.Println(err.Error())
+		os.Exit(1)
+	}
+
+	dbCon := db.GetDB(cfg.Database)
+	defer dbCon.Close()
+
+	report, err := catalogimport.Run(bookrepo.NewBookRepository(dbCon), rows, *dryRun)
+	if err != nil {
+		fmt.Println(err.Error())
+		os.Exit(1)
+	}
+
+	for _, row := range report.Rows {
+		fmt.Printf("line %-6d %-10s %s %s\n", row.Line, row.Status, row.BookID, row.Error)
+	}
+	fmt.Printf("%d rows: %d imported, %d duplicates, %d invalid\n",
+		report.Total, report.Imported, report.Duplicates, report.Invalid)
+	if !report.Committed {
+		fmt.Println("nothing was written")
+	}
+
+	if report.Invalid > 0 {
+		os.Exit(1)
+	}
+}
@@ -1,20 +1,55 @@
 package main
 
 import (
+	"context"
 	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
 
 	"github.com/Kaushik1766/LibraryManagement/internal/app"
+	"github.com/Kaushik1766/LibraryManagement/internal/config"
 	"github.com/Kaushik1766/LibraryManagement/internal/db"
+	jwtkeys "github.com/Kaushik1766/LibraryManagement/internal/jwt_keys"
+	"github.com/Kaushik1766/LibraryManagement/internal/migrations"
 )
 
 func main() {
-	dbCon := db.GetDB()
+	cfg, err := config.Load(os.Getenv(config.PathEnv))
+	if err != nil {
+		fmt.Println(err.Error())
+		os.Exit(1)
+	}
+
+	keys, err := jwtkeys.Load(cfg.Auth.JWT.KeysDir, cfg.Auth.JWT.SigningKey)
+	if err != nil {
+		fmt.Println(err.Error())
+		os.Exit(1)
+	}
+
+	dbCon := db.GetDB(cfg.Database)
 
-	err := db.CreateTables(dbCon)
+	migrator, err := migrations.NewMigrator(dbCon, os.DirFS(migrations.Dir))
 	if err != nil {
 		fmt.Println(err.Error())
+		os.Exit(1)
 	}
 
-	App := app.NewApp(dbCon)
-	App.Run()
+	applied, err := migrator.Up(context.Background())
+	if err != nil {
+		fmt.Println(err.Error())
+		os.Exit(1)
+	}
+	for _, m := range applied {
+		fmt.Printf("applied migration %04d_%s\n", m.Version, m.Name)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	App := app.NewApp(dbCon, cfg, keys)
+	if err := App.Run(ctx); err != nil {
+		fmt.Println(err.Error())
+		os.Exit(1)
+	}
 }
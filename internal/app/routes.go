@@ -4,24 +4,74 @@ import (
 	"net/http"
 
 	"github.com/Kaushik1766/LibraryManagement/internal/middleware"
+	"github.com/Kaushik1766/LibraryManagement/internal/rbac"
 )
 
 var routes map[string]func(w http.ResponseWriter, r *http.Request)
 
 func (app *App) registerRoutes() {
 
-	authMiddleware := middleware.AuthMiddleware
+	authMiddleware := app.auth.AuthMiddleware
+	requirePermission := middleware.RequirePermission
 
 	routes = map[string]func(w http.ResponseWriter, r *http.Request){
-		"POST /auth/signup":                 app.AuthHandler.Signup,
-		"POST /auth/login":                  app.AuthHandler.Login,
-		"POST /books":                       authMiddleware(app.BookHandler.AddBook),
-		"GET /books":                        authMiddleware(app.BookHandler.GetAllBooks),
-		"POST /transactions/issue":          authMiddleware(app.TransactionHandler.IssueBook),
-		"POST /transactions/return":         authMiddleware(app.TransactionHandler.ReturnBook),
-		"GET /transactions/overdue":         authMiddleware(app.TransactionHandler.GetOverdueTransactions),
-		"GET /transactions":                 authMiddleware(app.TransactionHandler.GetAllTransactions),
-		"GET /transactions/{transactionId}": authMiddleware(app.TransactionHandler.GetTransactionById),
+		"GET /.well-known/jwks.json":               app.AuthHandler.JWKS,
+		"POST /auth/signup":                        app.AuthHandler.Signup,
+		"POST /auth/login":                         app.AuthHandler.Login,
+		"POST /auth/refresh":                       app.AuthHandler.Refresh,
+		"POST /auth/mfa/verify":                    app.AuthHandler.VerifyMFA,
+		"POST /auth/mfa/setup":                     app.AuthHandler.SetupMFAForLogin,
+		"POST /auth/verify":                        app.AuthHandler.VerifyEmail,
+		"POST /auth/verify/resend":                 authMiddleware(app.AuthHandler.ResendVerification),
+		"POST /auth/password/forgot":               app.AuthHandler.ForgotPassword,
+		"POST /auth/password/reset":                app.AuthHandler.ResetPassword,
+		"POST /auth/logout":                        authMiddleware(app.AuthHandler.Logout),
+		"POST /auth/logout-all":                    authMiddleware(app.AuthHandler.LogoutAll),
+		"GET /me":                                  authMiddleware(app.AuthHandler.GetProfile),
+		"PATCH /me":                                authMiddleware(app.AuthHandler.UpdateProfile),
+		"POST /me/password":                        authMiddleware(app.AuthHandler.ChangePassword),
+		"POST /me/mfa":                             authMiddleware(app.AuthHandler.SetupMFA),
+		"POST /me/mfa/confirm":                     authMiddleware(app.AuthHandler.ConfirmMFA),
+		"POST /me/mfa/disable":                     authMiddleware(app.AuthHandler.DisableMFA),
+		"POST /me/mfa/recovery-codes":              authMiddleware(app.AuthHandler.RegenerateRecoveryCodes),
+		"POST /books":                              authMiddleware(requirePermission(rbac.BookCreate, app.BookHandler.AddBook)),
+		"GET /books":                               authMiddleware(app.BookHandler.GetAllBooks),
+		"POST /books/import":                       authMiddleware(requirePermission(rbac.BookImport, app.BookHandler.ImportBooks)),
+		"GET /books/export":                        authMiddleware(requirePermission(rbac.BookExport, app.BookHandler.ExportCatalog)),
+		"GET /books/search":                        authMiddleware(app.BookHandler.SearchBooks),
+		"GET /books/{bookId}":                      authMiddleware(app.BookHandler.GetBook),
+		"PATCH /books/{bookId}":                    authMiddleware(requirePermission(rbac.BookUpdate, app.BookHandler.UpdateBook)),
+		"DELETE /books/{bookId}":                   authMiddleware(requirePermission(rbac.BookDelete, app.BookHandler.WithdrawBook)),
+		"GET /books/{bookId}/marc":                 authMiddleware(app.BookHandler.ExportBook),
+		"GET /books/{bookId}/copies":               authMiddleware(app.BookHandler.GetCopies),
+		"POST /books/{bookId}/copies":              authMiddleware(requirePermission(rbac.BookCreate, app.BookHandler.AddCopies)),
+		"DELETE /books/{bookId}/copies/{copyId}":   authMiddleware(requirePermission(rbac.BookDelete, app.BookHandler.WithdrawCopy)),
+		"POST /transactions/issue":                 authMiddleware(requirePermission(rbac.LoanBorrow, app.TransactionHandler.IssueBook)),
+		"POST /transactions/return":                authMiddleware(requirePermission(rbac.LoanBorrow, app.TransactionHandler.ReturnBook)),
+		"GET /transactions/overdue":                authMiddleware(app.TransactionHandler.GetOverdueTransactions),
+		"GET /transactions":                        authMiddleware(app.TransactionHandler.GetAllTransactions),
+		"GET /transactions/{transactionId}":        authMiddleware(app.TransactionHandler.GetTransactionById),
+		"POST /transactions/{transactionId}/renew": authMiddleware(requirePermission(rbac.LoanBorrow, app.TransactionHandler.RenewBook)),
+		"POST /desk/issue":                         authMiddleware(requirePermission(rbac.LoanIssueForOthers, app.TransactionHandler.DeskIssue)),
+		"POST /desk/return":                        authMiddleware(requirePermission(rbac.LoanIssueForOthers, app.TransactionHandler.DeskReturn)),
+		"POST /holds":                              authMiddleware(requirePermission(rbac.HoldPlace, app.HoldHandler.PlaceHold)),
+		"GET /holds":                               authMiddleware(app.HoldHandler.GetHolds),
+		"DELETE /holds/{holdId}":                   authMiddleware(app.HoldHandler.CancelHold),
+		"GET /fines":                               authMiddleware(app.FineHandler.GetFines),
+		"POST /fines/{fineId}/payments":            authMiddleware(requirePermission(rbac.FinesCollect, app.FineHandler.RecordPayment)),
+		"POST /fines/{fineId}/waive":               authMiddleware(requirePermission(rbac.FinesWaive, app.FineHandler.WaiveFine)),
+		"GET /users":                               authMiddleware(requirePermission(rbac.PatronView, app.UserHandler.GetUsers)),
+		"GET /users/{userId}":                      authMiddleware(requirePermission(rbac.PatronView, app.UserHandler.GetUser)),
+		"PATCH /users/{userId}":                    authMiddleware(requirePermission(rbac.UsersUpdate, app.UserHandler.UpdateUser)),
+		"PUT /users/{userId}/role":                 authMiddleware(requirePermission(rbac.UsersManage, app.UserHandler.ChangeRole)),
+		"POST /users/{userId}/deactivate":          authMiddleware(requirePermission(rbac.UsersUpdate, app.UserHandler.DeactivateUser)),
+		"POST /users/{userId}/reactivate":          authMiddleware(requirePermission(rbac.UsersUpdate, app.UserHandler.ReactivateUser)),
+		"POST /users/{userId}/unlock":              authMiddleware(requirePermission(rbac.UsersManage, app.UserHandler.UnlockUser)),
+		"DELETE /users/{userId}/mfa":               authMiddleware(requirePermission(rbac.UsersManage, app.UserHandler.ResetMFA)),
+		"GET /failed-logins":                       authMiddleware(requirePermission(rbac.UsersManage, app.UserHandler.GetFailedLogins)),
+		"GET /users/{userId}/blocks":               authMiddleware(app.BlockHandler.GetBlocks),
+		"POST /users/{userId}/blocks":              authMiddleware(requirePermission(rbac.PatronBlock, app.BlockHandler.PlaceBlock)),
+		"DELETE /users/{userId}/blocks/{blockId}":  authMiddleware(requirePermission(rbac.PatronBlock, app.BlockHandler.LiftBlock)),
 	}
 
 	for route, handler := range routes {
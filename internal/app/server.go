@@ -1,63 +1,273 @@
 package app
 
 import (
+	"context"
 	"database/sql"
+	"errors"
 	"fmt"
+	"log"
+	"net"
 	"net/http"
+	"sync"
+	"time"
 
+	"github.com/Kaushik1766/LibraryManagement/internal/config"
 	authhandler "github.com/Kaushik1766/LibraryManagement/internal/handlers/auth_handler"
+	blockhandler "github.com/Kaushik1766/LibraryManagement/internal/handlers/block_handler"
 	"github.com/Kaushik1766/LibraryManagement/internal/handlers/book_handler"
+	finehandler "github.com/Kaushik1766/LibraryManagement/internal/handlers/fine_handler"
+	holdhandler "github.com/Kaushik1766/LibraryManagement/internal/handlers/hold_handler"
 	transactionhandler "github.com/Kaushik1766/LibraryManagement/internal/handlers/transaction_handler"
+	userhandler "github.com/Kaushik1766/LibraryManagement/internal/handlers/user_handler"
+	jwtkeys "github.com/Kaushik1766/LibraryManagement/internal/jwt_keys"
+	"github.com/Kaushik1766/LibraryManagement/internal/mailer"
+	"github.com/Kaushik1766/LibraryManagement/internal/middleware"
+	blockrepo "github.com/Kaushik1766/LibraryManagement/internal/repository/block_repo"
 	bookrepo "github.com/Kaushik1766/LibraryManagement/internal/repository/book_repo"
+	failedloginrepo "github.com/Kaushik1766/LibraryManagement/internal/repository/failed_login_repo"
+	finerepo "github.com/Kaushik1766/LibraryManagement/internal/repository/fine_repo"
+	holdrepo "github.com/Kaushik1766/LibraryManagement/internal/repository/hold_repo"
+	mfarepo "github.com/Kaushik1766/LibraryManagement/internal/repository/mfa_repo"
+	resetrepo "github.com/Kaushik1766/LibraryManagement/internal/repository/reset_repo"
+	sessionrepo "github.com/Kaushik1766/LibraryManagement/internal/repository/session_repo"
 	transactionrepo "github.com/Kaushik1766/LibraryManagement/internal/repository/transaction_repo"
 	userrepo "github.com/Kaushik1766/LibraryManagement/internal/repository/user_repo"
 	authservice "github.com/Kaushik1766/LibraryManagement/internal/service/auth_service"
+	blockservice "github.com/Kaushik1766/LibraryManagement/internal/service/block_service"
 	bookservice "github.com/Kaushik1766/LibraryManagement/internal/service/book_service"
+	fineservice "github.com/Kaushik1766/LibraryManagement/internal/service/fine_service"
+	holdservice "github.com/Kaushik1766/LibraryManagement/internal/service/hold_service"
 	transactionservice "github.com/Kaushik1766/LibraryManagement/internal/service/transaction_service"
+	userservice "github.com/Kaushik1766/LibraryManagement/internal/service/user_service"
 )
 
 var (
 	userRepo        userrepo.UserStorage               = nil
 	bookRepo        bookrepo.BookStorage               = nil
 	transactionRepo transactionrepo.TransactionStorage = nil
+	holdRepo        holdrepo.HoldStorage               = nil
+	fineRepo        finerepo.FineStorage               = nil
+	sessionRepo     sessionrepo.SessionStorage         = nil
+	blockRepo       blockrepo.BlockStorage             = nil
+	resetRepo       resetrepo.ResetStorage             = nil
+	failedLoginRepo failedloginrepo.FailedLoginStorage = nil
+	mfaRepo         mfarepo.MFAStorage                 = nil
 
 	authService        authservice.AuthManager               = nil
 	bookService        bookservice.BookManager               = nil
 	transactionService transactionservice.TransactionManager = nil
+	holdService        holdservice.HoldManager               = nil
+	fineService        fineservice.FineManager               = nil
+	blockService       blockservice.BlockManager             = nil
+	userService        userservice.UserManager               = nil
 )
 
 type App struct {
-	mux *http.ServeMux
-	db  *sql.DB
+	mux     *http.ServeMux
+	db      *sql.DB
+	cfg     config.Config
+	auth    *middleware.Authenticator
+	workers []worker
 
 	AuthHandler        *authhandler.AuthHandler
 	BookHandler        *bookhandler.BookHandler
 	TransactionHandler *transactionhandler.TransactionHandler
+	HoldHandler        *holdhandler.HoldHandler
+	FineHandler        *finehandler.FineHandler
+	BlockHandler       *blockhandler.BlockHandler
+	UserHandler        *userhandler.UserHandler
 }
 
-func NewApp(db *sql.DB) *App {
+func NewApp(db *sql.DB, cfg config.Config, keys *jwtkeys.KeySet) *App {
 	app := App{
 		mux: http.NewServeMux(),
 		db:  db,
+		cfg: cfg,
 	}
 
 	userRepo = userrepo.NewUserRepository(db)
 	bookRepo = bookrepo.NewBookRepository(db)
 	transactionRepo = transactionrepo.NewTransactionRepository(db)
+	holdRepo = holdrepo.NewHoldRepository(db)
+	fineRepo = finerepo.NewFineRepository(db)
+	sessionRepo = sessionrepo.NewSessionRepository(db)
+	blockRepo = blockrepo.NewBlockRepository(db)
+	resetRepo = resetrepo.NewResetRepository(db)
+	failedLoginRepo = failedloginrepo.NewFailedLoginRepository(db)
+	mfaRepo = mfarepo.NewMFARepository(db)
 
-	authService = authservice.NewAuthService(userRepo)
+	app.auth = middleware.NewAuthenticator(keys, cfg.Auth.JWT.Issuer, cfg.Auth.JWT.Audience, sessionRepo)
+
+	authService = authservice.NewAuthService(userRepo, sessionRepo, resetRepo, failedLoginRepo, mfaRepo, mailer.New(cfg.Mail), keys, cfg.Auth)
 	bookService = bookservice.NewBookService(bookRepo)
-	transactionService = transactionservice.NewTransactionService(bookRepo, transactionRepo)
+	transactionService = transactionservice.NewTransactionService(bookRepo, transactionRepo, holdRepo, fineRepo, userRepo, blockRepo, cfg.Circulation)
+	holdService = holdservice.NewHoldService(holdRepo, cfg.Circulation)
+	fineService = fineservice.NewFineService(fineRepo, cfg.Circulation)
+	blockService = blockservice.NewBlockService(blockRepo, userRepo, cfg.Circulation)
+	userService = userservice.NewUserService(userRepo, sessionRepo, failedLoginRepo, mfaRepo)
 
 	app.AuthHandler = authhandler.NewAuthHandler(authService)
 	app.BookHandler = bookhandler.NewBookHandler(bookService)
 	app.TransactionHandler = transactionhandler.NewTransactionHandler(transactionService)
+	app.HoldHandler = holdhandler.NewHoldHandler(holdService)
+	app.FineHandler = finehandler.NewFineHandler(fineService)
+	app.BlockHandler = blockhandler.NewBlockHandler(blockService)
+	app.UserHandler = userhandler.NewUserHandler(userService)
 
 	app.registerRoutes()
 	return &app
 }
 
-func (app *App) Run() {
-	fmt.Println("server started at port 3000")
-	http.ListenAndServe("localhost:3000", app.mux)
+// every runs job on a fixed interval until ctx is cancelled. A job that is
+// already running is allowed to finish.
+func every(ctx context.Context, interval time.Duration, job func()) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			job()
+		}
+	}
+}
+
+// worker is a background job started by Run.
+type worker struct {
+	name string
+	stop context.CancelFunc
+	done chan struct{}
+}
+
+func (app *App) startWorker(name string, interval time.Duration, job func()) {
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+		every(ctx, interval, job)
+	}()
+
+	app.workers = append(app.workers, worker{name: name, stop: cancel, done: done})
+}
+
+// stopWorkers stops the background jobs one at a time in the order they were
+// started, waiting for each to finish its current run.
+func (app *App) stopWorkers() {
+	for _, w := range app.workers {
+		w.stop()
+		<-w.done
+		log.Printf("stopped %s\n", w.name)
+	}
+	app.workers = nil
+}
+
+// expireHolds rolls uncollected holds over to the next patron.
+func expireHolds() {
+	expired, err := holdService.ProcessExpiredHolds()
+	if err != nil {
+		log.Println(err)
+		return
+	}
+	if expired > 0 {
+		log.Printf("expired %d uncollected holds\n", expired)
+	}
+}
+
+// accrueFines keeps fines on loans that are still out up to date.
+func accrueFines() {
+	accrued, err := fineService.AccrueFines()
+	if err != nil {
+		log.Println(err)
+		return
+	}
+	if accrued > 0 {
+		log.Printf("accrued fines on %d overdue loans\n", accrued)
+	}
+}
+
+// purgeSessions drops sessions, revocations and password reset tokens that
+// have run out.
+func purgeSessions() {
+	purged, err := sessionRepo.PurgeExpired()
+	if err != nil {
+		log.Println(err)
+	} else if purged > 0 {
+		log.Printf("purged %d expired sessions and revocations\n", purged)
+	}
+
+	purged, err = resetRepo.PurgeExpired()
+	if err != nil {
+		log.Println(err)
+	} else if purged > 0 {
+		log.Printf("purged %d expired password resets\n", purged)
+	}
+}
+
+// Run serves until ctx is cancelled or the server fails. On the way out
+// in-flight requests are drained for up to the shutdown timeout, after which
+// their connections are closed and the handlers still running are waited
+// for. Then the background jobs are stopped, the password reset mails still
+// being sent are waited for, and the database is closed last.
+func (app *App) Run(ctx context.Context) error {
+	cfg := app.cfg.Server
+
+	listener, err := net.Listen("tcp", cfg.Addr)
+	if err != nil {
+		return errors.Join(err, app.db.Close())
+	}
+
+	// handlers keep running after server.Close drops their connections
+	var inFlight sync.WaitGroup
+	handler := middleware.RequestID(app.mux)
+
+	server := &http.Server{
+		Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			inFlight.Add(1)
+			defer inFlight.Done()
+			handler.ServeHTTP(w, r)
+		}),
+		ReadTimeout:  cfg.ReadTimeout,
+		WriteTimeout: cfg.WriteTimeout,
+		IdleTimeout:  cfg.IdleTimeout,
+	}
+
+	app.startWorker("hold expiry", app.cfg.Jobs.HoldExpiryInterval, expireHolds)
+	app.startWorker("fine accrual", app.cfg.Jobs.FineAccrualInterval, accrueFines)
+	app.startWorker("session purge", app.cfg.Jobs.SessionPurgeInterval, purgeSessions)
+
+	serveErr := make(chan error, 1)
+	go func() {
+		if cfg.TLS.Enabled() {
+			serveErr <- server.ServeTLS(listener, cfg.TLS.CertFile, cfg.TLS.KeyFile)
+		} else {
+			serveErr <- server.Serve(listener)
+		}
+	}()
+	fmt.Printf("server started at %s\n", listener.Addr())
+
+	select {
+	case err = <-serveErr:
+	case <-ctx.Done():
+		log.Println("shutting down")
+
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
+		defer cancel()
+		err = server.Shutdown(shutdownCtx)
+		if errors.Is(err, context.DeadlineExceeded) {
+			log.Println("shutdown timed out, closing open connections")
+			err = errors.Join(err, server.Close())
+			inFlight.Wait()
+		}
+	}
+	if errors.Is(err, http.ErrServerClosed) {
+		err = nil
+	}
+
+	app.stopWorkers()
+	authService.Wait()
+
+	return errors.Join(err, app.db.Close())
 }
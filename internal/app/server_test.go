@@ -0,0 +1,172 @@
+package app
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"errors"
+	"net"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/Kaushik1766/LibraryManagement/internal/config"
+	jwtkeys "github.com/Kaushik1766/LibraryManagement/internal/jwt_keys"
+)
+
+func testConfig(addr string) config.Config {
+	cfg := config.Default()
+	cfg.Server.Addr = addr
+	cfg.Auth.JWTSecret = "app-test-secret-value"
+	return cfg
+}
+
+func testKeys(t *testing.T) *jwtkeys.KeySet {
+	_, private, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	key, _ := jwtkeys.NewKey("test", private)
+	keys, err := jwtkeys.NewKeySet("", key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return keys
+}
+
+func TestApp_Run(t *testing.T) {
+	busy, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer busy.Close()
+
+	tests := []struct {
+		name    string
+		addr    string
+		wantErr bool
+	}{
+		{
+			name:    "shuts down when cancelled",
+			addr:    "127.0.0.1:0",
+			wantErr: false,
+		},
+		{
+			name:    "address already in use",
+			addr:    busy.Addr().String(),
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, _ := sqlmock.New()
+			mock.ExpectClose()
+
+			app := NewApp(db, testConfig(tt.addr), testKeys(t))
+
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+
+			if err := app.Run(ctx); (err != nil) != tt.wantErr {
+				t.Errorf("Run() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if len(app.workers) != 0 {
+				t.Errorf("Run() left %d workers running", len(app.workers))
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("database was not closed: %v", err)
+			}
+		})
+	}
+}
+
+func TestApp_Run_shutdownTimeout(t *testing.T) {
+	free, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := free.Addr().String()
+	free.Close()
+
+	db, mock, _ := sqlmock.New()
+	mock.ExpectClose()
+
+	cfg := testConfig(addr)
+	cfg.Server.ShutdownTimeout = 50 * time.Millisecond
+	app := NewApp(db, cfg, testKeys(t))
+
+	started := make(chan struct{})
+	var closedEarly atomic.Bool
+	app.mux.HandleFunc("GET /slow", func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		time.Sleep(200 * time.Millisecond)
+		// the database must outlive the handler
+		closedEarly.Store(mock.ExpectationsWereMet() == nil)
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- app.Run(ctx) }()
+
+	go func() {
+		for {
+			resp, err := http.Get("http://" + addr + "/slow")
+			if err == nil {
+				resp.Body.Close()
+				return
+			}
+			select {
+			case <-started:
+				return
+			case <-time.After(10 * time.Millisecond):
+			}
+		}
+	}()
+
+	select {
+	case <-started:
+	case <-time.After(2 * time.Second):
+		t.Fatal("request never reached the handler")
+	}
+	cancel()
+
+	select {
+	case err := <-done:
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("Run() error = %v, want %v", err, context.DeadlineExceeded)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Run() did not return")
+	}
+	if closedEarly.Load() {
+		t.Error("Run() closed the database while a handler was still running")
+	}
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("database was not closed: %v", err)
+	}
+}
+
+func TestEvery(t *testing.T) {
+	var runs atomic.Int32
+	ctx, cancel := context.WithCancel(context.Background())
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		every(ctx, time.Millisecond, func() { runs.Add(1) })
+	}()
+
+	time.Sleep(20 * time.Millisecond)
+	cancel()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("every() did not stop after cancel")
+	}
+	if runs.Load() == 0 {
+		t.Error("every() never ran the job")
+	}
+}
@@ -0,0 +1,135 @@
+package apperrors
+
+import (
+	"errors"
+
+	"github.com/lib/pq"
+)
+
+// Kind is the category of a domain error. The web layer picks the http status
+// from it.
+type Kind int
+
+const (
+	KindInternal Kind = iota
+	KindValidation
+	KindNotFound
+	KindConflict
+	KindUnauthorized
+	KindForbidden
+	KindTooManyRequests
+)
+
+// Error is returned by services and repositories for failures the caller can
+// act on. Code is stable and meant for clients to branch on, Message is for
+// people.
+type Error struct {
+	Kind    Kind
+	Code    string
+	Message string
+	// Details holds per field problems for validation errors.
+	Details map[string]string
+	Err     error
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// WithDetail returns a copy of e with a problem recorded against field.
+func (e *Error) WithDetail(field, problem string) *Error {
+	details := make(map[string]string, len(e.Details)+1)
+	for k, v := range e.Details {
+		details[k] = v
+	}
+	details[field] = problem
+
+	copied := *e
+	copied.Details = details
+	return &copied
+}
+
+// Wrap returns a copy of e that keeps err as its cause.
+func (e *Error) Wrap(err error) *Error {
+	copied := *e
+	copied.Err = err
+	return &copied
+}
+
+func newError(kind Kind, code, message string) *Error {
+	return &Error{Kind: kind, Code: code, Message: message}
+}
+
+func Validation(code, message string) *Error {
+	return newError(KindValidation, code, message)
+}
+
+func NotFound(code, message string) *Error {
+	return newError(KindNotFound, code, message)
+}
+
+func Conflict(code, message string) *Error {
+	return newError(KindConflict, code, message)
+}
+
+func Unauthorized(code, message string) *Error {
+	return newError(KindUnauthorized, code, message)
+}
+
+func Forbidden(code, message string) *Error {
+	return newError(KindForbidden, code, message)
+}
+
+func TooManyRequests(code, message string) *Error {
+	return newError(KindTooManyRequests, code, message)
+}
+
+func Internal(code, message string) *Error {
+	return newError(KindInternal, code, message)
+}
+
+// KindOf reports the kind of the first Error in err's chain, KindInternal when
+// there is none.
+func KindOf(err error) Kind {
+	var appErr *Error
+	if errors.As(err, &appErr) {
+		return appErr.Kind
+	}
+	return KindInternal
+}
+
+var (
+	// ErrInvalidUser is returned when a request reaches a service without an
+	// authenticated user in its context.
+	ErrInvalidUser = Unauthorized("invalid_user", "invalid user")
+	// ErrUnauthorisedUser is returned when the caller's role lacks the
+	// permission an action needs.
+	ErrUnauthorisedUser = Forbidden("forbidden", "unauthorised user")
+)
+
+// FromPostgres turns constraint violations reported by postgres into domain
+// errors and returns anything else unchanged.
+func FromPostgres(err error) error {
+	var pqErr *pq.Error
+	if !errors.As(err, &pqErr) {
+		return err
+	}
+
+	switch pqErr.Code.Name() {
+	case "unique_violation":
+		return Conflict("already_exists", "resource already exists").Wrap(err)
+	case "foreign_key_violation":
+		return NotFound("reference_not_found", "referenced resource does not exist").Wrap(err)
+	case "invalid_text_representation":
+		return Validation("invalid_id", "invalid id").Wrap(err)
+	case "check_violation", "not_null_violation":
+		return Validation("invalid_value", "invalid value").Wrap(err)
+	case "string_data_right_truncation":
+		return Validation("value_too_long", "value too long").Wrap(err)
+	}
+	return err
+}
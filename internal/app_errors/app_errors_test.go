@@ -0,0 +1,77 @@
+package apperrors
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/lib/pq"
+)
+
+func TestError_WithDetail(t *testing.T) {
+	base := Validation("invalid_input", "invalid input")
+	withEmail := base.WithDetail("email", "required")
+	withBoth := withEmail.WithDetail("name", "required")
+
+	if len(base.Details) != 0 {
+		t.Errorf("WithDetail() changed the original, details = %v", base.Details)
+	}
+	if len(withEmail.Details) != 1 {
+		t.Errorf("WithDetail() details = %v, want only email", withEmail.Details)
+	}
+	if withBoth.Details["email"] != "required" || withBoth.Details["name"] != "required" {
+		t.Errorf("WithDetail() details = %v, want email and name", withBoth.Details)
+	}
+}
+
+func TestKindOf(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want Kind
+	}{
+		{name: "plain error", err: errors.New("boom"), want: KindInternal},
+		{name: "nil", err: nil, want: KindInternal},
+		{name: "not found", err: NotFound("book_not_found", "book not found"), want: KindNotFound},
+		{name: "wrapped conflict", err: fmt.Errorf("ctx: %w", Conflict("taken", "taken")), want: KindConflict},
+		{name: "sentinel", err: ErrUnauthorisedUser, want: KindForbidden},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := KindOf(tt.err); got != tt.want {
+				t.Errorf("KindOf() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFromPostgres(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      error
+		wantKind Kind
+		wantSame bool
+	}{
+		{name: "unique violation", err: &pq.Error{Code: "23505"}, wantKind: KindConflict},
+		{name: "foreign key violation", err: &pq.Error{Code: "23503"}, wantKind: KindNotFound},
+		{name: "bad uuid", err: &pq.Error{Code: "22P02"}, wantKind: KindValidation},
+		{name: "check violation", err: &pq.Error{Code: "23514"}, wantKind: KindValidation},
+		{name: "value too long", err: &pq.Error{Code: "22001"}, wantKind: KindValidation},
+		{name: "other postgres error", err: &pq.Error{Code: "40001"}, wantKind: KindInternal, wantSame: true},
+		{name: "not a postgres error", err: errors.New("db down"), wantKind: KindInternal, wantSame: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := FromPostgres(tt.err)
+			if KindOf(got) != tt.wantKind {
+				t.Errorf("FromPostgres() kind = %v, want %v", KindOf(got), tt.wantKind)
+			}
+			if (got == tt.err) != tt.wantSame {
+				t.Errorf("FromPostgres() = %v, wantSame %v", got, tt.wantSame)
+			}
+			if !errors.Is(got, tt.err) {
+				t.Errorf("FromPostgres() lost the cause %v", tt.err)
+			}
+		})
+	}
+}
@@ -0,0 +1,377 @@
+package catalogimport
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	apperrors "github.com/Kaushik1766/LibraryManagement/internal/app_errors"
+	"github.com/Kaushik1766/LibraryManagement/internal/marc"
+	"github.com/Kaushik1766/LibraryManagement/internal/models"
+	"github.com/Kaushik1766/LibraryManagement/internal/models/enums/importstatus"
+)
+
+// MaxRows is the most rows a single import may contain.
+const MaxRows = 10000
+
+type Format string
+
+const (
+	CSV       Format = "csv"
+	JSONLines Format = "jsonl"
+	MARC      Format = Format(marc.ISO2709)
+	MARCXML   Format = Format(marc.XML)
+)
+
+// FormatOf resolves a format name, file extension or content type.
+func FormatOf(name string) (Format, error) {
+	switch strings.ToLower(strings.TrimPrefix(name, ".")) {
+	case "csv", "text/csv":
+		return CSV, nil
+	case "jsonl", "ndjson", "application/jsonl", "application/x-ndjson", "application/x-jsonlines":
+		return JSONLines, nil
+	}
+	if format, err := marc.FormatOf(name); err == nil {
+		return Format(format), nil
+	}
+	return "", apperrors.Validation("invalid_format", "invalid import format").WithDetail("format", "must be csv, jsonl, marc or marcxml")
+}
+
+// Importer writes validated rows, bookrepo.BookStorage is one.
+type Importer interface {
+	ImportBooks(rows []models.ImportRow, dryRun bool) ([]models.ImportRowResult, error)
+}
+
+// columns are the CSV header names, the same as the json fields of
+// models.AddBookDTO.
+var columns = map[string]func(book *models.AddBookDTO, value string) error{
+	"title":       func(book *models.AddBookDTO, value string) error { book.Title = value; return nil },
+	"author":      func(book *models.AddBookDTO, value string) error { book.Author = value; return nil },
+	"isbn":        func(book *models.AddBookDTO, value string) error { book.ISBN = value; return nil },
+	"publisher":   func(book *models.AddBookDTO, value string) error { book.Publisher = value; return nil },
+	"year":        func(book *models.AddBookDTO, value string) error { return parseInt("year", value, &book.Year) },
+	"language":    func(book *models.AddBookDTO, value string) error { book.Language = value; return nil },
+	"subject":     func(book *models.AddBookDTO, value string) error { book.Subject = value; return nil },
+	"description": func(book *models.AddBookDTO, value string) error { book.Description = value; return nil },
+	"item_type":   func(book *models.AddBookDTO, value string) error { book.ItemType = value; return nil },
+	"copies":      func(book *models.AddBookDTO, value string) error { return parseInt("copies", value, &book.Copies) },
+	"location":    func(book *models.AddBookDTO, value string) error { book.Location = value; return nil },
+}
+
+func parseInt(field, value string, dst *int) error {
+	if value == "" {
+		return nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return fmt.Errorf("%s must be a whole number", field)
+	}
+	*dst = n
+	return nil
+}
+
+// Parse reads the rows of an import file and validates each of them. Rows
+// that can't be read or fail validation come back with Error set, problems
+// with the file as a whole are returned as an error.
+func Parse(format Format, data io.Reader) ([]models.ImportRow, error) {
+	var rows []models.ImportRow
+	var err error
+
+	switch format {
+	case CSV:
+		rows, err = parseCSV(data)
+	case JSONLines:
+		rows, err = parseJSONLines(data)
+	case MARC, MARCXML:
+		rows, err = parseMARC(marc.NewDecoder(marc.Format(format), data))
+	default:
+		_, err = FormatOf(string(format))
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if len(rows) == 0 {
+		return nil, apperrors.Validation("empty_import", "import contains no rows")
+	}
+	if len(rows) > MaxRows {
+		return nil, apperrors.Validation("import_too_large", "import contains too many rows").
+			WithDetail("rows", fmt.Sprintf("at most %d", MaxRows))
+	}
+
+	for i := range rows {
+		if rows[i].Error == "" {
+			rows[i].Error = validate(&rows[i].Book)
+		}
+	}
+	return rows, nil
+}
+
+func parseCSV(data io.Reader) ([]models.ImportRow, error) {
+	reader := csv.NewReader(data)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, apperrors.Validation("invalid_csv", "invalid csv").WithDetail("header", err.Error())
+	}
+
+	setters := make([]func(*models.AddBookDTO, string) error, len(header))
+	seen := map[string]bool{}
+	for i, name := range header {
+		// spreadsheets like to start files with a byte order mark
+		name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\uFEFF")))
+		set, ok := columns[name]
+		if !ok || seen[name] {
+			return nil, apperrors.Validation("invalid_csv", "invalid csv").
+				WithDetail("header", fmt.Sprintf("unknown or repeated column %q", name))
+		}
+		seen[name] = true
+		setters[i] = set
+	}
+	if !seen["title"] || !seen["author"] {
+		return nil, apperrors.Validation("invalid_csv", "invalid csv").
+			WithDetail("header", "title and author columns are required")
+	}
+
+	var rows []models.ImportRow
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return rows, nil
+		}
+
+		var parseErr *csv.ParseError
+		if errors.As(err, &parseErr) && !errors.Is(err, csv.ErrFieldCount) {
+			// quoting errors leave the reader unable to find the next record
+			return nil, apperrors.Validation("invalid_csv", "invalid csv").
+				WithDetail("line", fmt.Sprintf("%d: %v", parseErr.Line, parseErr.Err))
+		}
+
+		line, _ := reader.FieldPos(0)
+		row := models.ImportRow{Line: line}
+
+		switch {
+		case errors.Is(err, csv.ErrFieldCount):
+			row.Error = fmt.Sprintf("expected %d fields, got %d", len(header), len(record))
+		case err != nil:
+			return nil, err
+		default:
+			for i, value := range record {
+				if err := setters[i](&row.Book, strings.TrimSpace(value)); err != nil {
+					row.Error = err.Error()
+					break
+				}
+			}
+		}
+
+		rows = append(rows, row)
+	}
+}
+
+func parseJSONLines(data io.Reader) ([]models.ImportRow, error) {
+	scanner := bufio.NewScanner(data)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	var rows []models.ImportRow
+	for line := 1; scanner.Scan(); line++ {
+		text := bytes.TrimSpace(scanner.Bytes())
+		if len(text) == 0 {
+			continue
+		}
+
+		row := models.ImportRow{Line: line}
+
+		decoder := json.NewDecoder(bytes.NewReader(text))
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(&row.Book); err != nil {
+			row.Error = "invalid json: " + err.Error()
+		}
+		trimBook(&row.Book)
+
+		rows = append(rows, row)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, apperrors.Validation("invalid_json", "invalid json lines").WithDetail("body", err.Error())
+	}
+	return rows, nil
+}
+
+// parseMARC numbers rows by record rather than by line.
+func parseMARC(decoder marc.Decoder) ([]models.ImportRow, error) {
+	var rows []models.ImportRow
+	for n := 1; ; n++ {
+		record, err := decoder.Decode()
+		if errors.Is(err, io.EOF) {
+			return rows, nil
+		}
+
+		row := models.ImportRow{Line: n}
+		switch {
+		case errors.Is(err, marc.ErrInvalidRecord):
+			row.Error = err.Error()
+		case err != nil:
+			return nil, apperrors.Validation("invalid_marc", "invalid marc").
+				WithDetail("record", fmt.Sprintf("%d: %v", n, err))
+		default:
+			book := marc.ToBook(record)
+			row.Book = models.AddBookDTO{
+				Title:       book.Title,
+				Author:      book.Author,
+				ISBN:        book.ISBN,
+				Publisher:   book.Publisher,
+				Year:        book.Year,
+				Language:    book.Language,
+				Subject:     book.Subject,
+				Description: book.Description,
+			}
+		}
+
+		rows = append(rows, row)
+	}
+}
+
+func trimBook(book *models.AddBookDTO) {
+	for _, field := range []*string{&book.Title, &book.Author, &book.ISBN, &book.Publisher, &book.Language, &book.Subject, &book.Description, &book.ItemType, &book.Location} {
+		*field = strings.TrimSpace(*field)
+	}
+	book.ItemType = strings.ToLower(book.ItemType)
+}
+
+// validate applies the rules AddBook does, plus the column sizes of the
+// titles table so a long value fails its row instead of the whole import.
+// Copies defaults to one.
+func validate(book *models.AddBookDTO) string {
+	if book.Copies == 0 {
+		book.Copies = 1
+	}
+
+	switch {
+	case book.Title == "":
+		return "title is required"
+	case book.Author == "":
+		return "author is required"
+	case tooLong(255, book.Title, book.Author, book.Publisher, book.Subject):
+		return "title, author, publisher and subject must be at most 255 characters"
+	case tooLong(35, book.Language):
+		return "language must be at most 35 characters"
+	case tooLong(32, book.ItemType):
+		return "item type must be at most 32 characters"
+	case book.ISBN != "" && !validISBN(book.ISBN):
+		return "isbn must have 10 or 13 digits"
+	case book.Year < 0:
+		return "year must not be negative"
+	case book.Copies < 0:
+		return "copies must be positive"
+	}
+	return ""
+}
+
+func tooLong(limit int, values ...string) bool {
+	for _, value := range values {
+		if utf8.RuneCountInString(value) > limit {
+			return true
+		}
+	}
+	return false
+}
+
+func validISBN(isbn string) bool {
+	if len(isbn) > 17 {
+		return false
+	}
+
+	digits := strings.ReplaceAll(isbn, "-", "")
+	for i, r := range digits {
+		if !unicode.IsDigit(r) && !(i == 9 && len(digits) == 10 && (r == 'X' || r == 'x')) {
+			return false
+		}
+	}
+	return len(digits) == 10 || len(digits) == 13
+}
+
+// Run imports the valid rows and reports on every row. If any row is invalid
+// the valid ones are still checked for duplicates but nothing is written,
+// the same as a dry run.
+func Run(store Importer, rows []models.ImportRow, dryRun bool) (models.ImportReport, error) {
+	report := models.ImportReport{
+		DryRun: dryRun,
+		Total:  len(rows),
+		Rows:   make([]models.ImportRowResult, 0, len(rows)),
+	}
+
+	var valid []models.ImportRow
+	for _, row := range rows {
+		if row.Error != "" {
+			report.Invalid++
+			continue
+		}
+		valid = append(valid, row)
+	}
+
+	rollback := dryRun || report.Invalid > 0
+
+	var written []models.ImportRowResult
+	if len(valid) > 0 {
+		var err error
+		written, err = store.ImportBooks(valid, rollback)
+		if err != nil {
+			return models.ImportReport{}, err
+		}
+	}
+	report.Committed = !rollback
+
+	// ids handed out inside a rolled back transaction don't exist, but they
+	// still tell which earlier row a duplicate matched
+	importedAt := map[string]int{}
+	for _, result := range written {
+		if result.Status == importstatus.Imported {
+			importedAt[result.BookID] = result.Line
+		}
+	}
+
+	next := 0
+	for _, row := range rows {
+		if row.Error != "" {
+			report.Rows = append(report.Rows, models.ImportRowResult{
+				Line:   row.Line,
+				Status: importstatus.Invalid,
+				Error:  row.Error,
+			})
+			continue
+		}
+
+		result := written[next]
+		next++
+
+		switch result.Status {
+		case importstatus.Imported:
+			report.Imported++
+		case importstatus.Duplicate:
+			report.Duplicates++
+			if line, ok := importedAt[result.BookID]; ok {
+				result.Error = fmt.Sprintf("same book as line %d", line)
+			} else {
+				result.Error = "book already in catalog"
+			}
+		}
+		if rollback && importedAt[result.BookID] != 0 {
+			result.BookID = ""
+		}
+
+		report.Rows = append(report.Rows, result)
+	}
+
+	return report, nil
+}
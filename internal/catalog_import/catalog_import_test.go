@@ -0,0 +1,268 @@
+package catalogimport
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/Kaushik1766/LibraryManagement/internal/models"
+	"github.com/Kaushik1766/LibraryManagement/internal/models/enums/importstatus"
+	"github.com/Kaushik1766/LibraryManagement/mocks"
+	"go.uber.org/mock/gomock"
+)
+
+func TestFormatOf(t *testing.T) {
+	tests := []struct {
+		name    string
+		want    Format
+		wantErr bool
+	}{
+		{name: "csv", want: CSV},
+		{name: ".CSV", want: CSV},
+		{name: "text/csv", want: CSV},
+		{name: "ndjson", want: JSONLines},
+		{name: "application/x-ndjson", want: JSONLines},
+		{name: "mrc", want: MARC},
+		{name: "application/marcxml+xml", want: MARCXML},
+		{name: "application/json", wantErr: true},
+		{name: "", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := FormatOf(tt.name)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("FormatOf() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("FormatOf() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name    string
+		format  Format
+		data    string
+		want    []models.ImportRow
+		wantErr bool
+	}{
+		{
+			name:   "csv",
+			format: CSV,
+			data: "\uFEFFTitle, Author ,isbn,year,copies,description\n" +
+				"harry potter,jk rowling,978-0-7475-3269-9,1997,2,\"wizards, \"\"muggles\"\"\"\n" +
+				"dune,frank herbert,,,,\n",
+			want: []models.ImportRow{
+				{Line: 2, Book: models.AddBookDTO{Title: "harry potter", Author: "jk rowling", ISBN: "978-0-7475-3269-9", Year: 1997, Copies: 2, Description: `wizards, "muggles"`}},
+				{Line: 3, Book: models.AddBookDTO{Title: "dune", Author: "frank herbert", Copies: 1}},
+			},
+		},
+		{
+			name:   "csv row errors",
+			format: CSV,
+			data:   "title,author,year\nharry potter,jk rowling\n,frank herbert,1965\ndune,frank herbert,soon\n",
+			want: []models.ImportRow{
+				{Line: 2, Book: models.AddBookDTO{}, Error: "expected 3 fields, got 2"},
+				{Line: 3, Book: models.AddBookDTO{Author: "frank herbert", Year: 1965, Copies: 1}, Error: "title is required"},
+				{Line: 4, Book: models.AddBookDTO{Title: "dune", Author: "frank herbert"}, Error: "year must be a whole number"},
+			},
+		},
+		{
+			name:    "csv unknown column",
+			format:  CSV,
+			data:    "title,author,pages\ndune,frank herbert,412\n",
+			wantErr: true,
+		},
+		{
+			name:    "csv without author column",
+			format:  CSV,
+			data:    "title\ndune\n",
+			wantErr: true,
+		},
+		{
+			name:    "csv bad quoting",
+			format:  CSV,
+			data:    "title,author\n\"dune,frank herbert\n",
+			wantErr: true,
+		},
+		{
+			name:   "json lines",
+			format: JSONLines,
+			data: `{"title":" dune ","author":"frank herbert","isbn":"0-441-17271-7"}` + "\n\n" +
+				`{"title":"dune","author":"frank herbert","pages":412}` + "\n" +
+				`{"title":"emma","author":"jane austen","isbn":"12345"}` + "\n" +
+				`{"title":"emma","author":"jane austen","copies":-1}`,
+			want: []models.ImportRow{
+				{Line: 1, Book: models.AddBookDTO{Title: "dune", Author: "frank herbert", ISBN: "0-441-17271-7", Copies: 1}},
+				{Line: 3, Book: models.AddBookDTO{Title: "dune", Author: "frank herbert"}, Error: `invalid json: json: unknown field "pages"`},
+				{Line: 4, Book: models.AddBookDTO{Title: "emma", Author: "jane austen", ISBN: "12345", Copies: 1}, Error: "isbn must have 10 or 13 digits"},
+				{Line: 5, Book: models.AddBookDTO{Title: "emma", Author: "jane austen", Copies: -1}, Error: "copies must be positive"},
+			},
+		},
+		{
+			name:   "marcxml",
+			format: MARCXML,
+			data: `<collection xmlns="http://www.loc.gov/MARC21/slim">
+  <record>
+    <datafield tag="100" ind1="1" ind2=" "><subfield code="a">Herbert, Frank.</subfield></datafield>
+    <datafield tag="245" ind1="1" ind2="0"><subfield code="a">Dune /</subfield></datafield>
+  </record>
+  <record>
+    <datafield tag="245" ind1="1" ind2="0"><subfield code="ab">Emma</subfield></datafield>
+  </record>
+  <record>
+    <datafield tag="245" ind1="1" ind2="0"><subfield code="a">Emma</subfield></datafield>
+  </record>
+</collection>`,
+			want: []models.ImportRow{
+				{Line: 1, Book: models.AddBookDTO{Title: "Dune", Author: "Herbert, Frank", Copies: 1}},
+				{Line: 2, Error: `invalid marc record: invalid subfield code "ab" in 245`},
+				{Line: 3, Book: models.AddBookDTO{Title: "Emma", Copies: 1}, Error: "author is required"},
+			},
+		},
+		{
+			name:    "broken marcxml",
+			format:  MARCXML,
+			data:    `<collection><record>`,
+			wantErr: true,
+		},
+		{
+			name:    "empty file",
+			format:  JSONLines,
+			data:    "\n\n",
+			wantErr: true,
+		},
+		{
+			name:    "unknown format",
+			format:  "xml",
+			data:    "<books/>",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Parse(tt.format, strings.NewReader(tt.data))
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Parse() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Parse() got = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRun(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockBookRepo := mocks.NewMockBookStorage(ctrl)
+
+	dune := models.ImportRow{Line: 2, Book: models.AddBookDTO{Title: "dune", Author: "frank herbert", Copies: 1}}
+	again := models.ImportRow{Line: 3, Book: models.AddBookDTO{Title: "Dune", Author: "Frank Herbert", Copies: 1}}
+	emma := models.ImportRow{Line: 4, Book: models.AddBookDTO{Title: "emma", Author: "jane austen", Copies: 1}}
+	invalid := models.ImportRow{Line: 5, Error: "title is required"}
+
+	written := []models.ImportRowResult{
+		{Line: 2, Status: importstatus.Imported, BookID: "new"},
+		{Line: 3, Status: importstatus.Duplicate, BookID: "new"},
+		{Line: 4, Status: importstatus.Duplicate, BookID: "existing"},
+	}
+
+	tests := []struct {
+		name      string
+		rows      []models.ImportRow
+		dryRun    bool
+		want      models.ImportReport
+		wantErr   bool
+		setupMock func()
+	}{
+		{
+			name: "committed",
+			rows: []models.ImportRow{dune, again, emma},
+			want: models.ImportReport{
+				Committed:  true,
+				Total:      3,
+				Imported:   1,
+				Duplicates: 2,
+				Rows: []models.ImportRowResult{
+					{Line: 2, Status: importstatus.Imported, BookID: "new"},
+					{Line: 3, Status: importstatus.Duplicate, BookID: "new", Error: "same book as line 2"},
+					{Line: 4, Status: importstatus.Duplicate, BookID: "existing", Error: "book already in catalog"},
+				},
+			},
+			setupMock: func() {
+				mockBookRepo.EXPECT().ImportBooks([]models.ImportRow{dune, again, emma}, false).Return(written, nil)
+			},
+		},
+		{
+			name:   "dry run hides ids that were never written",
+			rows:   []models.ImportRow{dune, again, emma},
+			dryRun: true,
+			want: models.ImportReport{
+				DryRun:     true,
+				Total:      3,
+				Imported:   1,
+				Duplicates: 2,
+				Rows: []models.ImportRowResult{
+					{Line: 2, Status: importstatus.Imported},
+					{Line: 3, Status: importstatus.Duplicate, Error: "same book as line 2"},
+					{Line: 4, Status: importstatus.Duplicate, BookID: "existing", Error: "book already in catalog"},
+				},
+			},
+			setupMock: func() {
+				mockBookRepo.EXPECT().ImportBooks([]models.ImportRow{dune, again, emma}, true).Return(written, nil)
+			},
+		},
+		{
+			name: "invalid row rolls back",
+			rows: []models.ImportRow{dune, invalid},
+			want: models.ImportReport{
+				Total:    2,
+				Imported: 1,
+				Invalid:  1,
+				Rows: []models.ImportRowResult{
+					{Line: 2, Status: importstatus.Imported},
+					{Line: 5, Status: importstatus.Invalid, Error: "title is required"},
+				},
+			},
+			setupMock: func() {
+				mockBookRepo.EXPECT().ImportBooks([]models.ImportRow{dune}, true).Return(written[:1], nil)
+			},
+		},
+		{
+			name: "only invalid rows",
+			rows: []models.ImportRow{invalid},
+			want: models.ImportReport{
+				Total:   1,
+				Invalid: 1,
+				Rows:    []models.ImportRowResult{{Line: 5, Status: importstatus.Invalid, Error: "title is required"}},
+			},
+			setupMock: func() {},
+		},
+		{
+			name:    "repository error",
+			rows:    []models.ImportRow{dune},
+			wantErr: true,
+			setupMock: func() {
+				mockBookRepo.EXPECT().ImportBooks([]models.ImportRow{dune}, false).Return(nil, errors.New("db error"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setupMock()
+			got, err := Run(mockBookRepo, tt.rows, tt.dryRun)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Run() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Run() got = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -1,5 +1,459 @@
 package config
 
-const (
-	JWTSecret = "adfasdffadfasd"
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/Kaushik1766/LibraryManagement/internal/models/enums/roles"
 )
+
+type Config struct {
+	Server      ServerConfig      `yaml:"server"`
+	Database    DatabaseConfig    `yaml:"database"`
+	Auth        AuthConfig        `yaml:"auth"`
+	Circulation CirculationConfig `yaml:"circulation"`
+	Jobs        JobsConfig        `yaml:"jobs"`
+	Mail        MailConfig        `yaml:"mail"`
+}
+
+type ServerConfig struct {
+	Addr         string        `yaml:"addr"`
+	TLS          TLSConfig     `yaml:"tls"`
+	ReadTimeout  time.Duration `yaml:"read_timeout"`
+	WriteTimeout time.Duration `yaml:"write_timeout"`
+	IdleTimeout  time.Duration `yaml:"idle_timeout"`
+	// ShutdownTimeout is how long in-flight requests get to finish once the
+	// server is asked to stop.
+	ShutdownTimeout time.Duration `yaml:"shutdown_timeout"`
+}
+
+// TLSConfig turns on HTTPS when both files are set.
+type TLSConfig struct {
+	CertFile string `yaml:"cert_file"`
+	KeyFile  string `yaml:"key_file"`
+}
+
+func (tls TLSConfig) Enabled() bool {
+	return tls.CertFile != "" && tls.KeyFile != ""
+}
+
+type DatabaseConfig struct {
+	URL             string        `yaml:"url"`
+	MaxOpenConns    int           `yaml:"max_open_conns"`
+	MaxIdleConns    int           `yaml:"max_idle_conns"`
+	ConnMaxLifetime time.Duration `yaml:"conn_max_lifetime"`
+}
+
+type AuthConfig struct {
+	// JWTSecret signs the links and MFA challenges handed out by the service
+	// itself. Access tokens are signed with the keys in JWT.
+	JWTSecret string    `yaml:"jwt_secret"`
+	JWT       JWTConfig `yaml:"jwt"`
+	// AccessTokenTTL is how long a JWT is accepted for. RefreshTokenTTL bounds
+	// how long a login can be kept alive by rotating refresh tokens.
+	AccessTokenTTL  time.Duration `yaml:"access_token_ttl"`
+	RefreshTokenTTL time.Duration `yaml:"refresh_token_ttl"`
+	// PasswordResetTTL is how long a password reset email stays usable.
+	PasswordResetTTL time.Duration `yaml:"password_reset_ttl"`
+	// PasswordResetURL is the page reset emails link to, with the token added
+	// as the token query parameter. Empty sends the bare token.
+	PasswordResetURL string `yaml:"password_reset_url"`
+	// EmailVerificationTTL is how long the link mailed at signup works, and
+	// EmailVerificationURL the page it points at, like PasswordResetURL.
+	EmailVerificationTTL time.Duration `yaml:"email_verification_ttl"`
+	EmailVerificationURL string        `yaml:"email_verification_url"`
+	// VerificationResendInterval is how long a user waits between
+	// verification emails.
+	VerificationResendInterval time.Duration `yaml:"verification_resend_interval"`
+	Lockout                    LockoutConfig `yaml:"lockout"`
+	MFA                        MFAConfig     `yaml:"mfa"`
+}
+
+// JWTConfig is how access tokens are signed. Every .pem file in KeysDir is a
+// key named after the file, SigningKey names the one that signs and may be
+// left empty when there is only one private key. The others still verify
+// tokens, so a key can be rotated without signing anybody out. Issuer and
+// Audience go in the iss and aud claims and are checked on every request.
+type JWTConfig struct {
+	KeysDir    string `yaml:"keys_dir"`
+	SigningKey string `yaml:"signing_key"`
+	Issuer     string `yaml:"issuer"`
+	Audience   string `yaml:"audience"`
+}
+
+// MFAConfig is two-factor login with authenticator app codes. Any account can
+// turn it on, accounts with one of RequiredRoles have to set it up before
+// they can sign in and can't turn it off. Issuer labels the account in the
+// app, and ChallengeTTL is how long a login has to enter its code once the
+// password was accepted.
+type MFAConfig struct {
+	Issuer        string        `yaml:"issuer"`
+	RequiredRoles []string      `yaml:"required_roles"`
+	ChallengeTTL  time.Duration `yaml:"challenge_ttl"`
+}
+
+// Required reports whether accounts with role have to use MFA.
+func (mfa MFAConfig) Required(role roles.UserRoles) bool {
+	for _, name := range mfa.RequiredRoles {
+		if required, ok := roles.Parse(name); ok && required == role {
+			return true
+		}
+	}
+	return false
+}
+
+// LockoutConfig slows down password guessing. After DelayAfter failed logins
+// in a row an account waits Delay before its password is checked again,
+// doubling with every further failure, and after LockAfter it is locked for
+// LockFor or until an admin unlocks it. An IP address can fail IPMaxFailures
+// times per IPWindow across all accounts. Zero turns a limit off.
+type LockoutConfig struct {
+	DelayAfter    int           `yaml:"delay_after"`
+	Delay         time.Duration `yaml:"delay"`
+	LockAfter     int           `yaml:"lock_after"`
+	LockFor       time.Duration `yaml:"lock_for"`
+	IPMaxFailures int           `yaml:"ip_max_failures"`
+	IPWindow      time.Duration `yaml:"ip_window"`
+}
+
+// CirculationConfig is the lending policy. Periods are postgres intervals
+// since they are applied in the database, see ParsePeriod for the syntax.
+// The fields are the defaults, Rules can change them for a role or an item
+// type.
+type CirculationConfig struct {
+	// MaxLoans is how many loans a patron can have open at once.
+	MaxLoans int `yaml:"max_loans"`
+	// LoanPeriod is used when a loan is issued without an explicit period,
+	// a longer one than MaxLoanPeriod is refused.
+	LoanPeriod    string `yaml:"loan_period"`
+	MaxLoanPeriod string `yaml:"max_loan_period"`
+	// RenewalPeriod is how far each renewal pushes a loan's due date.
+	RenewalPeriod string `yaml:"renewal_period"`
+	MaxRenewals   int    `yaml:"max_renewals"`
+	// FinePerDay is charged for every started day a loan is overdue, in the
+	// smallest currency unit. A single loan never accrues more than FineCap.
+	FinePerDay int `yaml:"fine_per_day"`
+	FineCap    int `yaml:"fine_cap"`
+	// HoldPickupWindow is how long a returned copy stays set aside for the
+	// patron at the head of the hold queue.
+	HoldPickupWindow string `yaml:"hold_pickup_window"`
+	// A patron with BlockOverdueLoans overdue loans or BlockUnpaidFines in
+	// unpaid fines is blocked from borrowing until under the limit again.
+	// Zero turns the block off.
+	BlockOverdueLoans int `yaml:"block_overdue_loans"`
+	BlockUnpaidFines  int `yaml:"block_unpaid_fines"`
+	// Rules are applied from least to most specific, see LoanRule.
+	Rules []LoanRule `yaml:"rules"`
+}
+
+// LoanRule overrides part of the lending policy for a role, an item type or
+// both. An empty or "*" role or item type matches anything. A rule for an
+// item type beats one for a role, and a rule naming both beats either, so
+// only the fields a rule sets replace those of less specific ones.
+type LoanRule struct {
+	Role     string `yaml:"role"`
+	ItemType string `yaml:"item_type"`
+	// MaxLoans on a rule with an item type only counts loans of that type.
+	MaxLoans      *int   `yaml:"max_loans"`
+	LoanPeriod    string `yaml:"loan_period"`
+	MaxLoanPeriod string `yaml:"max_loan_period"`
+	RenewalPeriod string `yaml:"renewal_period"`
+	MaxRenewals   *int   `yaml:"max_renewals"`
+	FinePerDay    *int   `yaml:"fine_per_day"`
+	FineCap       *int   `yaml:"fine_cap"`
+}
+
+// JobsConfig is how often each background job runs.
+type JobsConfig struct {
+	HoldExpiryInterval   time.Duration `yaml:"hold_expiry_interval"`
+	FineAccrualInterval  time.Duration `yaml:"fine_accrual_interval"`
+	SessionPurgeInterval time.Duration `yaml:"session_purge_interval"`
+}
+
+// MailConfig is how outgoing email is sent. With an SMTP host mail goes
+// through that server, otherwise it is appended to File, or written to the
+// log when File is empty too, which is enough for running locally.
+type MailConfig struct {
+	From string     `yaml:"from"`
+	SMTP SMTPConfig `yaml:"smtp"`
+	File string     `yaml:"file"`
+}
+
+type SMTPConfig struct {
+	Host     string `yaml:"host"`
+	Port     int    `yaml:"port"`
+	Username string `yaml:"username"`
+	Password string `yaml:"password"`
+}
+
+// Default is the configuration before any file or environment is applied.
+// There is no default database url or jwt secret.
+func Default() Config {
+	return Config{
+		Server: ServerConfig{
+			Addr:            "localhost:3000",
+			ReadTimeout:     15 * time.Second,
+			WriteTimeout:    30 * time.Second,
+			IdleTimeout:     2 * time.Minute,
+			ShutdownTimeout: 20 * time.Second,
+		},
+		Database: DatabaseConfig{
+			MaxOpenConns:    25,
+			MaxIdleConns:    5,
+			ConnMaxLifetime: 30 * time.Minute,
+		},
+		Auth: AuthConfig{
+			JWT: JWTConfig{
+				KeysDir:  "keys",
+				Issuer:   "library",
+				Audience: "library",
+			},
+			AccessTokenTTL:             2 * time.Hour,
+			RefreshTokenTTL:            30 * 24 * time.Hour,
+			PasswordResetTTL:           time.Hour,
+			EmailVerificationTTL:       72 * time.Hour,
+			VerificationResendInterval: 5 * time.Minute,
+			Lockout: LockoutConfig{
+				DelayAfter:    3,
+				Delay:         time.Second,
+				LockAfter:     10,
+				LockFor:       15 * time.Minute,
+				IPMaxFailures: 50,
+				IPWindow:      15 * time.Minute,
+			},
+			MFA: MFAConfig{
+				Issuer:       "Library",
+				ChallengeTTL: 5 * time.Minute,
+			},
+		},
+		Circulation: CirculationConfig{
+			MaxLoans:          5,
+			LoanPeriod:        "1 day",
+			MaxLoanPeriod:     "30 days",
+			RenewalPeriod:     "7 days",
+			MaxRenewals:       2,
+			FinePerDay:        50,
+			FineCap:           2000,
+			HoldPickupWindow:  "2 days",
+			BlockOverdueLoans: 3,
+			BlockUnpaidFines:  1000,
+		},
+		Jobs: JobsConfig{
+			HoldExpiryInterval:   15 * time.Minute,
+			FineAccrualInterval:  time.Hour,
+			SessionPurgeInterval: time.Hour,
+		},
+		Mail: MailConfig{
+			From: "library@localhost",
+			SMTP: SMTPConfig{Port: 587},
+		},
+	}
+}
+
+func (cfg Config) Validate() error {
+	return errors.Join(
+		cfg.Server.Validate(),
+		cfg.Database.Validate(),
+		cfg.Auth.Validate(),
+		cfg.Circulation.Validate(),
+		cfg.Jobs.Validate(),
+		cfg.Mail.Validate(),
+	)
+}
+
+func (server ServerConfig) Validate() error {
+	if server.Addr == "" {
+		return errors.New("server.addr is required")
+	}
+	if (server.TLS.CertFile == "") != (server.TLS.KeyFile == "") {
+		return errors.New("server.tls needs both cert_file and key_file")
+	}
+	if server.ReadTimeout <= 0 || server.WriteTimeout <= 0 || server.IdleTimeout <= 0 || server.ShutdownTimeout <= 0 {
+		return errors.New("server timeouts must be positive")
+	}
+	return nil
+}
+
+func (db DatabaseConfig) Validate() error {
+	if db.URL == "" {
+		return errors.New("database.url is required")
+	}
+	if db.MaxOpenConns < 0 || db.MaxIdleConns < 0 || db.ConnMaxLifetime < 0 {
+		return errors.New("database pool settings cant be negative")
+	}
+	if db.MaxOpenConns > 0 && db.MaxIdleConns > db.MaxOpenConns {
+		return errors.New("database.max_idle_conns cant exceed max_open_conns")
+	}
+	return nil
+}
+
+func (auth AuthConfig) Validate() error {
+	if len(auth.JWTSecret) < 16 {
+		return errors.New("auth.jwt_secret must be at least 16 characters")
+	}
+	if auth.AccessTokenTTL <= 0 || auth.RefreshTokenTTL <= 0 {
+		return errors.New("auth token ttls must be positive")
+	}
+	if auth.RefreshTokenTTL < auth.AccessTokenTTL {
+		return errors.New("auth.refresh_token_ttl cant be shorter than access_token_ttl")
+	}
+	if auth.PasswordResetTTL <= 0 || auth.EmailVerificationTTL <= 0 {
+		return errors.New("auth.password_reset_ttl and email_verification_ttl must be positive")
+	}
+	if auth.VerificationResendInterval < 0 {
+		return errors.New("auth.verification_resend_interval cant be negative")
+	}
+	if !absoluteURL(auth.PasswordResetURL) {
+		return errors.New("auth.password_reset_url must be an absolute url")
+	}
+	if !absoluteURL(auth.EmailVerificationURL) {
+		return errors.New("auth.email_verification_url must be an absolute url")
+	}
+	return errors.Join(auth.JWT.Validate(), auth.Lockout.Validate(), auth.MFA.Validate())
+}
+
+func (jwt JWTConfig) Validate() error {
+	if jwt.KeysDir == "" {
+		return errors.New("auth.jwt.keys_dir is required")
+	}
+	if jwt.Issuer == "" || jwt.Audience == "" {
+		return errors.New("auth.jwt.issuer and audience are required")
+	}
+	return nil
+}
+
+func (lockout LockoutConfig) Validate() error {
+	if lockout.DelayAfter < 0 || lockout.LockAfter < 0 || lockout.IPMaxFailures < 0 {
+		return errors.New("auth.lockout limits cant be negative")
+	}
+	if (lockout.DelayAfter > 0 && lockout.Delay <= 0) ||
+		(lockout.LockAfter > 0 && lockout.LockFor <= 0) ||
+		(lockout.IPMaxFailures > 0 && lockout.IPWindow <= 0) {
+		return errors.New("auth.lockout durations must be positive for the limits in use")
+	}
+	return nil
+}
+
+func (mfa MFAConfig) Validate() error {
+	if mfa.Issuer == "" || strings.Contains(mfa.Issuer, ":") {
+		return errors.New("auth.mfa.issuer is required and cant contain a colon")
+	}
+	if mfa.ChallengeTTL <= 0 {
+		return errors.New("auth.mfa.challenge_ttl must be positive")
+	}
+	for _, name := range mfa.RequiredRoles {
+		if _, ok := roles.Parse(name); !ok {
+			return fmt.Errorf("auth.mfa.required_roles: unknown role %q", name)
+		}
+	}
+	return nil
+}
+
+// absoluteURL reports whether raw is empty or a url with a scheme and host.
+func absoluteURL(raw string) bool {
+	if raw == "" {
+		return true
+	}
+	u, err := url.Parse(raw)
+	return err == nil && u.Scheme != "" && u.Host != ""
+}
+
+func (circulation CirculationConfig) Validate() error {
+	if circulation.LoanPeriod == "" || circulation.MaxLoanPeriod == "" || circulation.RenewalPeriod == "" || circulation.HoldPickupWindow == "" {
+		return errors.New("circulation periods cant be empty")
+	}
+	if circulation.MaxLoans < 0 || circulation.MaxRenewals < 0 {
+		return errors.New("circulation.max_loans and max_renewals cant be negative")
+	}
+	if circulation.FinePerDay < 0 || circulation.FineCap < circulation.FinePerDay {
+		return errors.New("circulation.fine_cap must be at least fine_per_day")
+	}
+	if circulation.BlockOverdueLoans < 0 || circulation.BlockUnpaidFines < 0 {
+		return errors.New("circulation block thresholds cant be negative")
+	}
+
+	loanPeriod, err := ParsePeriod(circulation.LoanPeriod)
+	if err != nil {
+		return fmt.Errorf("circulation.loan_period: %w", err)
+	}
+	maxLoanPeriod, err := ParsePeriod(circulation.MaxLoanPeriod)
+	if err != nil {
+		return fmt.Errorf("circulation.max_loan_period: %w", err)
+	}
+	if loanPeriod <= 0 || loanPeriod > maxLoanPeriod {
+		return errors.New("circulation.loan_period must be positive and at most max_loan_period")
+	}
+	if _, err := ParsePeriod(circulation.RenewalPeriod); err != nil {
+		return fmt.Errorf("circulation.renewal_period: %w", err)
+	}
+	holdPickupWindow, err := ParsePeriod(circulation.HoldPickupWindow)
+	if err != nil {
+		return fmt.Errorf("circulation.hold_pickup_window: %w", err)
+	}
+	if holdPickupWindow <= 0 {
+		return errors.New("circulation.hold_pickup_window must be positive")
+	}
+
+	var errs []error
+	for i, rule := range circulation.Rules {
+		if err := rule.Validate(); err != nil {
+			errs = append(errs, fmt.Errorf("circulation.rules[%d]: %w", i, err))
+			continue
+		}
+
+		// a rule setting only one of the two is held against the other's default
+		finePerDay, fineCap := circulation.FinePerDay, circulation.FineCap
+		if rule.FinePerDay != nil {
+			finePerDay = *rule.FinePerDay
+		}
+		if rule.FineCap != nil {
+			fineCap = *rule.FineCap
+		}
+		if fineCap > 0 && fineCap < finePerDay {
+			errs = append(errs, fmt.Errorf("circulation.rules[%d]: fine_cap must be at least fine_per_day", i))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (rule LoanRule) Validate() error {
+	if rule.Role != "" && rule.Role != "*" {
+		if _, ok := roles.Parse(rule.Role); !ok {
+			return fmt.Errorf("unknown role %q", rule.Role)
+		}
+	}
+	for _, n := range []*int{rule.MaxLoans, rule.MaxRenewals, rule.FinePerDay, rule.FineCap} {
+		if n != nil && *n < 0 {
+			return errors.New("limits and fines cant be negative")
+		}
+	}
+	for _, period := range []string{rule.LoanPeriod, rule.MaxLoanPeriod, rule.RenewalPeriod} {
+		if period == "" {
+			continue
+		}
+		if _, err := ParsePeriod(period); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (jobs JobsConfig) Validate() error {
+	if jobs.HoldExpiryInterval <= 0 || jobs.FineAccrualInterval <= 0 || jobs.SessionPurgeInterval <= 0 {
+		return errors.New("job intervals must be positive")
+	}
+	return nil
+}
+
+func (mail MailConfig) Validate() error {
+	if mail.From == "" {
+		return errors.New("mail.from is required")
+	}
+	if mail.SMTP.Host != "" && (mail.SMTP.Port <= 0 || mail.SMTP.Port > 65535) {
+		return errors.New("mail.smtp.port must be between 1 and 65535")
+	}
+	return nil
+}
@@ -0,0 +1,418 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/Kaushik1766/LibraryManagement/internal/models/enums/roles"
+)
+
+func writeConfig(t *testing.T, contents string) string {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte(contents), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoad(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		env     map[string]string
+		check   func(cfg Config) bool
+		wantErr bool
+	}{
+		{
+			name: "environment only",
+			env: map[string]string{
+				"DATABASE_URL":       "postgres://localhost/library",
+				"LIBRARY_JWT_SECRET": "a-long-enough-secret",
+			},
+			check: func(cfg Config) bool {
+				return cfg.Server.Addr == "localhost:3000" && cfg.Circulation.LoanPeriod == "1 day" && cfg.Auth.AccessTokenTTL == 2*time.Hour
+			},
+			wantErr: false,
+		},
+		{
+			name: "file with environment override",
+			file: `
+server:
+  addr: ":8080"
+database:
+  url: postgres://localhost/library
+  max_open_conns: 10
+auth:
+  jwt_secret: file-secret-long-enough
+  access_token_ttl: 15m
+circulation:
+  loan_period: 14 days
+  fine_per_day: 25
+`,
+			env: map[string]string{
+				"LIBRARY_FINE_PER_DAY": "30",
+			},
+			check: func(cfg Config) bool {
+				return cfg.Server.Addr == ":8080" &&
+					cfg.Database.MaxOpenConns == 10 &&
+					cfg.Auth.AccessTokenTTL == 15*time.Minute &&
+					cfg.Circulation.LoanPeriod == "14 days" &&
+					cfg.Circulation.FinePerDay == 30 &&
+					cfg.Circulation.FineCap == 2000
+			},
+			wantErr: false,
+		},
+		{
+			name: "loan rules from the file",
+			file: `
+database:
+  url: postgres://localhost/library
+auth:
+  jwt_secret: file-secret-long-enough
+circulation:
+  max_loan_period: 60 days
+  rules:
+    - role: customer
+      item_type: dvd
+      max_loans: 2
+      loan_period: 3 days
+    - item_type: reference
+      max_loans: 0
+`,
+			env: map[string]string{
+				"LIBRARY_MAX_LOANS": "8",
+			},
+			check: func(cfg Config) bool {
+				rules := cfg.Circulation.Rules
+				return cfg.Circulation.MaxLoans == 8 &&
+					cfg.Circulation.MaxLoanPeriod == "60 days" &&
+					len(rules) == 2 &&
+					rules[0].Role == "customer" && *rules[0].MaxLoans == 2 && rules[0].LoanPeriod == "3 days" &&
+					rules[1].MaxLoans != nil && *rules[1].MaxLoans == 0 && rules[1].MaxRenewals == nil
+			},
+			wantErr: false,
+		},
+		{
+			name: "mfa roles from the environment",
+			env: map[string]string{
+				"DATABASE_URL":               "postgres://localhost/library",
+				"LIBRARY_JWT_SECRET":         "a-long-enough-secret",
+				"LIBRARY_MFA_REQUIRED_ROLES": "staff, admin",
+			},
+			check: func(cfg Config) bool {
+				return cfg.Auth.MFA.Required(roles.Staff) && cfg.Auth.MFA.Required(roles.Admin) && !cfg.Auth.MFA.Required(roles.Customer)
+			},
+			wantErr: false,
+		},
+		{
+			name: "jwt keys from the file",
+			file: `
+database:
+  url: postgres://localhost/library
+auth:
+  jwt_secret: file-secret-long-enough
+  jwt:
+    keys_dir: /etc/library/keys
+    signing_key: 2024-06
+    issuer: https://library.example.com
+`,
+			env: map[string]string{
+				"LIBRARY_JWT_AUDIENCE": "library-api",
+			},
+			check: func(cfg Config) bool {
+				return cfg.Auth.JWT == JWTConfig{
+					KeysDir:    "/etc/library/keys",
+					SigningKey: "2024-06",
+					Issuer:     "https://library.example.com",
+					Audience:   "library-api",
+				}
+			},
+			wantErr: false,
+		},
+		{
+			name:    "missing secret and database url",
+			env:     map[string]string{},
+			wantErr: true,
+		},
+		{
+			name: "invalid number in environment",
+			env: map[string]string{
+				"DATABASE_URL":         "postgres://localhost/library",
+				"LIBRARY_JWT_SECRET":   "a-long-enough-secret",
+				"LIBRARY_MAX_RENEWALS": "two",
+			},
+			wantErr: true,
+		},
+		{
+			name: "half configured tls",
+			env: map[string]string{
+				"DATABASE_URL":          "postgres://localhost/library",
+				"LIBRARY_JWT_SECRET":    "a-long-enough-secret",
+				"LIBRARY_TLS_CERT_FILE": "cert.pem",
+			},
+			wantErr: true,
+		},
+		{
+			name:    "malformed file",
+			file:    "server: [",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, name := range []string{"DATABASE_URL", "LIBRARY_JWT_SECRET"} {
+				t.Setenv(name, "")
+				os.Unsetenv(name)
+			}
+			for name, value := range tt.env {
+				t.Setenv(name, value)
+			}
+
+			var path string
+			if tt.file != "" {
+				path = writeConfig(t, tt.file)
+			}
+
+			got, err := Load(path)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Load() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && !tt.check(got) {
+				t.Errorf("Load() got = %+v", got)
+			}
+		})
+	}
+}
+
+func TestConfig_Validate(t *testing.T) {
+	valid := Default()
+	valid.Database.URL = "postgres://localhost/library"
+	valid.Auth.JWTSecret = "a-long-enough-secret"
+
+	tests := []struct {
+		name    string
+		modify  func(cfg *Config)
+		wantErr bool
+	}{
+		{
+			name:    "defaults with secrets",
+			modify:  func(cfg *Config) {},
+			wantErr: false,
+		},
+		{
+			name:    "short secret",
+			modify:  func(cfg *Config) { cfg.Auth.JWTSecret = "short" },
+			wantErr: true,
+		},
+		{
+			name:    "no jwt keys directory",
+			modify:  func(cfg *Config) { cfg.Auth.JWT.KeysDir = "" },
+			wantErr: true,
+		},
+		{
+			name:    "no jwt audience",
+			modify:  func(cfg *Config) { cfg.Auth.JWT.Audience = "" },
+			wantErr: true,
+		},
+		{
+			name:    "idle connections above open",
+			modify:  func(cfg *Config) { cfg.Database.MaxIdleConns = 50 },
+			wantErr: true,
+		},
+		{
+			name:    "fine cap below daily fine",
+			modify:  func(cfg *Config) { cfg.Circulation.FineCap = 10 },
+			wantErr: true,
+		},
+		{
+			name:    "refresh shorter than access",
+			modify:  func(cfg *Config) { cfg.Auth.RefreshTokenTTL = time.Minute },
+			wantErr: true,
+		},
+		{
+			name:    "zero shutdown timeout",
+			modify:  func(cfg *Config) { cfg.Server.ShutdownTimeout = 0 },
+			wantErr: true,
+		},
+		{
+			name:    "loan period above the maximum",
+			modify:  func(cfg *Config) { cfg.Circulation.LoanPeriod = "45 days" },
+			wantErr: true,
+		},
+		{
+			name:    "unparseable hold pickup window",
+			modify:  func(cfg *Config) { cfg.Circulation.HoldPickupWindow = "two days" },
+			wantErr: true,
+		},
+		{
+			name:    "zero hold pickup window",
+			modify:  func(cfg *Config) { cfg.Circulation.HoldPickupWindow = "0 days" },
+			wantErr: true,
+		},
+		{
+			name:    "negative overdue block threshold",
+			modify:  func(cfg *Config) { cfg.Circulation.BlockOverdueLoans = -1 },
+			wantErr: true,
+		},
+		{
+			name:    "fine block disabled",
+			modify:  func(cfg *Config) { cfg.Circulation.BlockUnpaidFines = 0 },
+			wantErr: false,
+		},
+		{
+			name:    "loan period in months",
+			modify:  func(cfg *Config) { cfg.Circulation.LoanPeriod = "1 mon" },
+			wantErr: true,
+		},
+		{
+			name: "loan rule for an unknown role",
+			modify: func(cfg *Config) {
+				cfg.Circulation.Rules = []LoanRule{{Role: "visitor", LoanPeriod: "7 days"}}
+			},
+			wantErr: true,
+		},
+		{
+			name: "loan rule with a negative limit",
+			modify: func(cfg *Config) {
+				limit := -1
+				cfg.Circulation.Rules = []LoanRule{{ItemType: "dvd", MaxLoans: &limit}}
+			},
+			wantErr: true,
+		},
+		{
+			name: "loan rule capping fines below a day",
+			modify: func(cfg *Config) {
+				perDay, fineCap := 100, 50
+				cfg.Circulation.Rules = []LoanRule{{ItemType: "dvd", FinePerDay: &perDay, FineCap: &fineCap}}
+			},
+			wantErr: true,
+		},
+		{
+			name: "loan rule raising fines past the default cap",
+			modify: func(cfg *Config) {
+				perDay := 5000
+				cfg.Circulation.Rules = []LoanRule{{ItemType: "dvd", FinePerDay: &perDay}}
+			},
+			wantErr: true,
+		},
+		{
+			name: "loan rule waiving fines",
+			modify: func(cfg *Config) {
+				fineCap := 0
+				cfg.Circulation.Rules = []LoanRule{{Role: "staff", FineCap: &fineCap}}
+			},
+			wantErr: false,
+		},
+		{
+			name: "valid loan rule",
+			modify: func(cfg *Config) {
+				cfg.Circulation.Rules = []LoanRule{{Role: "Customer", ItemType: "*", MaxLoanPeriod: "2 weeks"}}
+			},
+			wantErr: false,
+		},
+		{
+			name:    "zero job interval",
+			modify:  func(cfg *Config) { cfg.Jobs.FineAccrualInterval = 0 },
+			wantErr: true,
+		},
+		{
+			name:    "zero password reset ttl",
+			modify:  func(cfg *Config) { cfg.Auth.PasswordResetTTL = 0 },
+			wantErr: true,
+		},
+		{
+			name:    "relative password reset url",
+			modify:  func(cfg *Config) { cfg.Auth.PasswordResetURL = "/reset-password" },
+			wantErr: true,
+		},
+		{
+			name:    "zero email verification ttl",
+			modify:  func(cfg *Config) { cfg.Auth.EmailVerificationTTL = 0 },
+			wantErr: true,
+		},
+		{
+			name:    "no resend interval",
+			modify:  func(cfg *Config) { cfg.Auth.VerificationResendInterval = 0 },
+			wantErr: false,
+		},
+		{
+			name:    "lockout without a duration",
+			modify:  func(cfg *Config) { cfg.Auth.Lockout.LockFor = 0 },
+			wantErr: true,
+		},
+		{
+			name: "lockout turned off",
+			modify: func(cfg *Config) {
+				cfg.Auth.Lockout = LockoutConfig{}
+			},
+			wantErr: false,
+		},
+		{
+			name:    "mfa for an unknown role",
+			modify:  func(cfg *Config) { cfg.Auth.MFA.RequiredRoles = []string{"janitor"} },
+			wantErr: true,
+		},
+		{
+			name:    "mfa issuer with a colon",
+			modify:  func(cfg *Config) { cfg.Auth.MFA.Issuer = "City:Library" },
+			wantErr: true,
+		},
+		{
+			name:    "smtp host without a port",
+			modify:  func(cfg *Config) { cfg.Mail.SMTP = SMTPConfig{Host: "smtp.example.com"} },
+			wantErr: true,
+		},
+		{
+			name:    "missing mail sender",
+			modify:  func(cfg *Config) { cfg.Mail.From = "" },
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := valid
+			tt.modify(&cfg)
+			if err := cfg.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestParsePeriod(t *testing.T) {
+	tests := []struct {
+		period  string
+		want    time.Duration
+		wantErr bool
+	}{
+		{period: "1 day", want: 24 * time.Hour},
+		{period: "14 days", want: 14 * 24 * time.Hour},
+		{period: "2 Weeks 1 day", want: 15 * 24 * time.Hour},
+		{period: "90 mins", want: 90 * time.Minute},
+		{period: "36 hours", want: 36 * time.Hour},
+		{period: "", wantErr: true},
+		{period: "days", wantErr: true},
+		{period: "1 month", wantErr: true},
+		{period: "-1 day", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.period, func(t *testing.T) {
+			got, err := ParsePeriod(tt.period)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParsePeriod() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("ParsePeriod() = %v, want %v", got, tt.want)
+			}
+			if !tt.wantErr {
+				if back, _ := ParsePeriod(FormatPeriod(got)); back != got {
+					t.Errorf("ParsePeriod(FormatPeriod()) = %v, want %v", back, got)
+				}
+			}
+		})
+	}
+}
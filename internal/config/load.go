@@ -0,0 +1,162 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// PathEnv names the environment variable holding the config file path.
+const PathEnv = "LIBRARY_CONFIG"
+
+// Load reads the configuration and validates it.
+func Load(path string) (Config, error) {
+	cfg, err := Read(path)
+	if err != nil {
+		return cfg, err
+	}
+	return cfg, cfg.Validate()
+}
+
+// Read starts from Default, applies the YAML file at path if one is given and
+// then the environment, which wins over the file. Nothing is validated.
+func Read(path string) (Config, error) {
+	cfg := Default()
+
+	if path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return cfg, err
+		}
+		if err := yaml.Unmarshal(data, &cfg); err != nil {
+			return cfg, fmt.Errorf("%s: %w", path, err)
+		}
+	}
+
+	return cfg, applyEnv(&cfg)
+}
+
+func applyEnv(cfg *Config) error {
+	envString("LIBRARY_ADDR", &cfg.Server.Addr)
+	envString("LIBRARY_TLS_CERT_FILE", &cfg.Server.TLS.CertFile)
+	envString("LIBRARY_TLS_KEY_FILE", &cfg.Server.TLS.KeyFile)
+
+	envString("DATABASE_URL", &cfg.Database.URL)
+	envString("LIBRARY_JWT_SECRET", &cfg.Auth.JWTSecret)
+	envString("LIBRARY_JWT_KEYS_DIR", &cfg.Auth.JWT.KeysDir)
+	envString("LIBRARY_JWT_SIGNING_KEY", &cfg.Auth.JWT.SigningKey)
+	envString("LIBRARY_JWT_ISSUER", &cfg.Auth.JWT.Issuer)
+	envString("LIBRARY_JWT_AUDIENCE", &cfg.Auth.JWT.Audience)
+	envString("LIBRARY_PASSWORD_RESET_URL", &cfg.Auth.PasswordResetURL)
+	envString("LIBRARY_EMAIL_VERIFICATION_URL", &cfg.Auth.EmailVerificationURL)
+	envString("LIBRARY_MFA_ISSUER", &cfg.Auth.MFA.Issuer)
+	envList("LIBRARY_MFA_REQUIRED_ROLES", &cfg.Auth.MFA.RequiredRoles)
+
+	envString("LIBRARY_MAIL_FROM", &cfg.Mail.From)
+	envString("LIBRARY_MAIL_FILE", &cfg.Mail.File)
+	envString("LIBRARY_SMTP_HOST", &cfg.Mail.SMTP.Host)
+	envString("LIBRARY_SMTP_USERNAME", &cfg.Mail.SMTP.Username)
+	envString("LIBRARY_SMTP_PASSWORD", &cfg.Mail.SMTP.Password)
+
+	envString("LIBRARY_LOAN_PERIOD", &cfg.Circulation.LoanPeriod)
+	envString("LIBRARY_MAX_LOAN_PERIOD", &cfg.Circulation.MaxLoanPeriod)
+	envString("LIBRARY_RENEWAL_PERIOD", &cfg.Circulation.RenewalPeriod)
+	envString("LIBRARY_HOLD_PICKUP_WINDOW", &cfg.Circulation.HoldPickupWindow)
+
+	ints := map[string]*int{
+		"LIBRARY_DB_MAX_OPEN_CONNS":       &cfg.Database.MaxOpenConns,
+		"LIBRARY_DB_MAX_IDLE_CONNS":       &cfg.Database.MaxIdleConns,
+		"LIBRARY_MAX_LOANS":               &cfg.Circulation.MaxLoans,
+		"LIBRARY_MAX_RENEWALS":            &cfg.Circulation.MaxRenewals,
+		"LIBRARY_FINE_PER_DAY":            &cfg.Circulation.FinePerDay,
+		"LIBRARY_FINE_CAP":                &cfg.Circulation.FineCap,
+		"LIBRARY_BLOCK_OVERDUE_LOANS":     &cfg.Circulation.BlockOverdueLoans,
+		"LIBRARY_BLOCK_UNPAID_FINES":      &cfg.Circulation.BlockUnpaidFines,
+		"LIBRARY_SMTP_PORT":               &cfg.Mail.SMTP.Port,
+		"LIBRARY_LOCKOUT_DELAY_AFTER":     &cfg.Auth.Lockout.DelayAfter,
+		"LIBRARY_LOCKOUT_LOCK_AFTER":      &cfg.Auth.Lockout.LockAfter,
+		"LIBRARY_LOCKOUT_IP_MAX_FAILURES": &cfg.Auth.Lockout.IPMaxFailures,
+	}
+	for name, dst := range ints {
+		if err := envInt(name, dst); err != nil {
+			return err
+		}
+	}
+
+	durations := map[string]*time.Duration{
+		"LIBRARY_READ_TIMEOUT":                 &cfg.Server.ReadTimeout,
+		"LIBRARY_WRITE_TIMEOUT":                &cfg.Server.WriteTimeout,
+		"LIBRARY_IDLE_TIMEOUT":                 &cfg.Server.IdleTimeout,
+		"LIBRARY_SHUTDOWN_TIMEOUT":             &cfg.Server.ShutdownTimeout,
+		"LIBRARY_DB_CONN_MAX_LIFETIME":         &cfg.Database.ConnMaxLifetime,
+		"LIBRARY_ACCESS_TOKEN_TTL":             &cfg.Auth.AccessTokenTTL,
+		"LIBRARY_REFRESH_TOKEN_TTL":            &cfg.Auth.RefreshTokenTTL,
+		"LIBRARY_PASSWORD_RESET_TTL":           &cfg.Auth.PasswordResetTTL,
+		"LIBRARY_EMAIL_VERIFICATION_TTL":       &cfg.Auth.EmailVerificationTTL,
+		"LIBRARY_VERIFICATION_RESEND_INTERVAL": &cfg.Auth.VerificationResendInterval,
+		"LIBRARY_LOCKOUT_DELAY":                &cfg.Auth.Lockout.Delay,
+		"LIBRARY_LOCKOUT_LOCK_FOR":             &cfg.Auth.Lockout.LockFor,
+		"LIBRARY_LOCKOUT_IP_WINDOW":            &cfg.Auth.Lockout.IPWindow,
+		"LIBRARY_MFA_CHALLENGE_TTL":            &cfg.Auth.MFA.ChallengeTTL,
+		"LIBRARY_HOLD_EXPIRY_INTERVAL":         &cfg.Jobs.HoldExpiryInterval,
+		"LIBRARY_FINE_ACCRUAL_INTERVAL":        &cfg.Jobs.FineAccrualInterval,
+		"LIBRARY_SESSION_PURGE_INTERVAL":       &cfg.Jobs.SessionPurgeInterval,
+	}
+	for name, dst := range durations {
+		if err := envDuration(name, dst); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func envString(name string, dst *string) {
+	if value, ok := os.LookupEnv(name); ok {
+		*dst = value
+	}
+}
+
+// envList reads a comma separated list. Set but empty clears the list.
+func envList(name string, dst *[]string) {
+	value, ok := os.LookupEnv(name)
+	if !ok {
+		return
+	}
+	*dst = nil
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			*dst = append(*dst, item)
+		}
+	}
+}
+
+func envInt(name string, dst *int) error {
+	value, ok := os.LookupEnv(name)
+	if !ok {
+		return nil
+	}
+	parsed, err := strconv.Atoi(value)
+	if err != nil {
+		return fmt.Errorf("%s: %q is not a number", name, value)
+	}
+	*dst = parsed
+	return nil
+}
+
+func envDuration(name string, dst *time.Duration) error {
+	value, ok := os.LookupEnv(name)
+	if !ok {
+		return nil
+	}
+	parsed, err := time.ParseDuration(value)
+	if err != nil {
+		return fmt.Errorf("%s: %q is not a duration", name, value)
+	}
+	*dst = parsed
+	return nil
+}
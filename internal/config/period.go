@@ -0,0 +1,54 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var periodUnits = map[string]time.Duration{
+	"second": time.Second,
+	"sec":    time.Second,
+	"minute": time.Minute,
+	"min":    time.Minute,
+	"hour":   time.Hour,
+	"day":    24 * time.Hour,
+	"week":   7 * 24 * time.Hour,
+}
+
+// ParsePeriod reads the subset of postgres interval syntax the circulation
+// periods use, a list of "<n> <unit>" pairs such as "1 week 3 days". Months
+// and years are not accepted since their length depends on the date.
+func ParsePeriod(period string) (time.Duration, error) {
+	fields := strings.Fields(strings.ToLower(period))
+	if len(fields) == 0 || len(fields)%2 != 0 {
+		return 0, fmt.Errorf("%q is not a period like \"14 days\"", period)
+	}
+
+	var total time.Duration
+	for i := 0; i < len(fields); i += 2 {
+		n, err := strconv.Atoi(fields[i])
+		if err != nil || n < 0 {
+			return 0, fmt.Errorf("%q is not a period like \"14 days\"", period)
+		}
+		unit, ok := periodUnits[strings.TrimSuffix(fields[i+1], "s")]
+		if !ok {
+			return 0, fmt.Errorf("%q: unknown unit %q", period, fields[i+1])
+		}
+		total += time.Duration(n) * unit
+	}
+	return total, nil
+}
+
+// FormatPeriod writes d as an interval postgres and ParsePeriod both accept.
+func FormatPeriod(d time.Duration) string {
+	switch {
+	case d == 24*time.Hour:
+		return "1 day"
+	case d%(24*time.Hour) == 0:
+		return fmt.Sprintf("%d days", d/(24*time.Hour))
+	default:
+		return fmt.Sprintf("%d seconds", d/time.Second)
+	}
+}
@@ -2,36 +2,24 @@ package db
 
 import (
 	"database/sql"
-	"fmt"
-	"os"
 
+	"github.com/Kaushik1766/LibraryManagement/internal/config"
 	_ "github.com/lib/pq"
 )
 
-func GetDB() *sql.DB {
-	url := os.Getenv("DATABASE_URL")
-	if url == "" {
+func GetDB(cfg config.DatabaseConfig) *sql.DB {
+	if cfg.URL == "" {
 		panic("database url not specified")
 	}
 
-	db, err := sql.Open("postgres", url)
+	db, err := sql.Open("postgres", cfg.URL)
 	if err != nil {
 		panic("cant connect to database" + err.Error())
 	}
 
-	return db
-}
-
-func CreateTables(db *sql.DB) error {
-	data, err := os.ReadFile("./sql/InitDB.sql")
-	if err != nil {
-		panic("error reading sql file" + err.Error())
-	}
+	db.SetMaxOpenConns(cfg.MaxOpenConns)
+	db.SetMaxIdleConns(cfg.MaxIdleConns)
+	db.SetConnMaxLifetime(cfg.ConnMaxLifetime)
 
-	_, err = db.Exec(string(data))
-	if err != nil {
-		return fmt.Errorf("error creating tables: %s", err.Error())
-	}
-
-	return nil
+	return db
 }
@@ -1,9 +1,10 @@
 package authhandler
 
 import (
+	"context"
 	"encoding/json"
-	"fmt"
 	"io"
+	"net"
 	"net/http"
 
 	"github.com/Kaushik1766/LibraryManagement/internal/models"
@@ -52,12 +53,302 @@ func (handler *AuthHandler) Login(w http.ResponseWriter, r *http.Request) {
 		weberrors.SendError(err, http.StatusBadRequest, w)
 		return
 	}
-	token, err := handler.authService.Login(req)
+	tokens, err := handler.authService.Login(req, clientIP(r))
 	if err != nil {
 		weberrors.SendError(err, http.StatusInternalServerError, w)
 		return
 	}
 
 	w.WriteHeader(http.StatusOK)
-	w.Write([]byte(fmt.Sprintf(`{"jwt":"%s"}`, token)))
+	json.NewEncoder(w).Encode(tokens)
+}
+
+func (handler *AuthHandler) Refresh(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	var req models.RefreshDTO
+	data, _ := io.ReadAll(r.Body)
+
+	err := json.Unmarshal(data, &req)
+	if err != nil {
+		weberrors.SendError(err, http.StatusBadRequest, w)
+		return
+	}
+	tokens, err := handler.authService.Refresh(req.RefreshToken)
+	if err != nil {
+		weberrors.SendError(err, http.StatusUnauthorized, w)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(tokens)
+}
+
+func (handler *AuthHandler) Logout(ctx context.Context, w http.ResponseWriter, r *http.Request) {
+	err := handler.authService.Logout(ctx)
+	if err != nil {
+		weberrors.SendError(err, http.StatusInternalServerError, w)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+func (handler *AuthHandler) LogoutAll(ctx context.Context, w http.ResponseWriter, r *http.Request) {
+	err := handler.authService.LogoutAll(ctx)
+	if err != nil {
+		weberrors.SendError(err, http.StatusInternalServerError, w)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+func (handler *AuthHandler) GetProfile(ctx context.Context, w http.ResponseWriter, r *http.Request) {
+	profile, err := handler.authService.GetProfile(ctx)
+	if err != nil {
+		weberrors.SendError(err, http.StatusInternalServerError, w)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(profile)
+}
+
+func (handler *AuthHandler) UpdateProfile(ctx context.Context, w http.ResponseWriter, r *http.Request) {
+	var req models.UpdateProfileDTO
+	data, _ := io.ReadAll(r.Body)
+
+	err := json.Unmarshal(data, &req)
+	if err != nil {
+		weberrors.SendError(err, http.StatusBadRequest, w)
+		return
+	}
+	profile, err := handler.authService.UpdateProfile(ctx, req)
+	if err != nil {
+		weberrors.SendError(err, http.StatusInternalServerError, w)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(profile)
+}
+
+func (handler *AuthHandler) ChangePassword(ctx context.Context, w http.ResponseWriter, r *http.Request) {
+	var req models.ChangePasswordDTO
+	data, _ := io.ReadAll(r.Body)
+
+	err := json.Unmarshal(data, &req)
+	if err != nil {
+		weberrors.SendError(err, http.StatusBadRequest, w)
+		return
+	}
+	err = handler.authService.ChangePassword(ctx, req)
+	if err != nil {
+		weberrors.SendError(err, http.StatusInternalServerError, w)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+// ForgotPassword answers the same way whether or not the email has an
+// account.
+func (handler *AuthHandler) ForgotPassword(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	var req models.ForgotPasswordDTO
+	data, _ := io.ReadAll(r.Body)
+
+	err := json.Unmarshal(data, &req)
+	if err != nil {
+		weberrors.SendError(err, http.StatusBadRequest, w)
+		return
+	}
+	err = handler.authService.ForgotPassword(req)
+	if err != nil {
+		weberrors.SendError(err, http.StatusInternalServerError, w)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+func (handler *AuthHandler) ResetPassword(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	var req models.ResetPasswordDTO
+	data, _ := io.ReadAll(r.Body)
+
+	err := json.Unmarshal(data, &req)
+	if err != nil {
+		weberrors.SendError(err, http.StatusBadRequest, w)
+		return
+	}
+	err = handler.authService.ResetPassword(req)
+	if err != nil {
+		weberrors.SendError(err, http.StatusInternalServerError, w)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+func (handler *AuthHandler) VerifyEmail(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	var req models.VerifyEmailDTO
+	data, _ := io.ReadAll(r.Body)
+
+	err := json.Unmarshal(data, &req)
+	if err != nil {
+		weberrors.SendError(err, http.StatusBadRequest, w)
+		return
+	}
+	err = handler.authService.VerifyEmail(req)
+	if err != nil {
+		weberrors.SendError(err, http.StatusInternalServerError, w)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+func (handler *AuthHandler) ResendVerification(ctx context.Context, w http.ResponseWriter, r *http.Request) {
+	err := handler.authService.ResendVerification(ctx)
+	if err != nil {
+		weberrors.SendError(err, http.StatusInternalServerError, w)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+func (handler *AuthHandler) VerifyMFA(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	var req models.VerifyMFADTO
+	data, _ := io.ReadAll(r.Body)
+
+	err := json.Unmarshal(data, &req)
+	if err != nil {
+		weberrors.SendError(err, http.StatusBadRequest, w)
+		return
+	}
+	tokens, err := handler.authService.VerifyMFA(req, clientIP(r))
+	if err != nil {
+		weberrors.SendError(err, http.StatusInternalServerError, w)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(tokens)
+}
+
+func (handler *AuthHandler) SetupMFAForLogin(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+
+	var req models.MFATokenDTO
+	data, _ := io.ReadAll(r.Body)
+
+	err := json.Unmarshal(data, &req)
+	if err != nil {
+		weberrors.SendError(err, http.StatusBadRequest, w)
+		return
+	}
+	setup, err := handler.authService.SetupMFAForLogin(req)
+	if err != nil {
+		weberrors.SendError(err, http.StatusInternalServerError, w)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(setup)
+}
+
+func (handler *AuthHandler) SetupMFA(ctx context.Context, w http.ResponseWriter, r *http.Request) {
+	setup, err := handler.authService.SetupMFA(ctx)
+	if err != nil {
+		weberrors.SendError(err, http.StatusInternalServerError, w)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(setup)
+}
+
+func (handler *AuthHandler) ConfirmMFA(ctx context.Context, w http.ResponseWriter, r *http.Request) {
+	var req models.MFACodeDTO
+	data, _ := io.ReadAll(r.Body)
+
+	err := json.Unmarshal(data, &req)
+	if err != nil {
+		weberrors.SendError(err, http.StatusBadRequest, w)
+		return
+	}
+	codes, err := handler.authService.ConfirmMFA(ctx, req)
+	if err != nil {
+		weberrors.SendError(err, http.StatusInternalServerError, w)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(codes)
+}
+
+func (handler *AuthHandler) DisableMFA(ctx context.Context, w http.ResponseWriter, r *http.Request) {
+	var req models.DisableMFADTO
+	data, _ := io.ReadAll(r.Body)
+
+	err := json.Unmarshal(data, &req)
+	if err != nil {
+		weberrors.SendError(err, http.StatusBadRequest, w)
+		return
+	}
+	err = handler.authService.DisableMFA(ctx, req)
+	if err != nil {
+		weberrors.SendError(err, http.StatusInternalServerError, w)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+func (handler *AuthHandler) RegenerateRecoveryCodes(ctx context.Context, w http.ResponseWriter, r *http.Request) {
+	var req models.MFACodeDTO
+	data, _ := io.ReadAll(r.Body)
+
+	err := json.Unmarshal(data, &req)
+	if err != nil {
+		weberrors.SendError(err, http.StatusBadRequest, w)
+		return
+	}
+	codes, err := handler.authService.RegenerateRecoveryCodes(ctx, req)
+	if err != nil {
+		weberrors.SendError(err, http.StatusInternalServerError, w)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(codes)
+}
+
+// JWKS serves the public keys for GET /.well-known/jwks.json. Clients may
+// cache them for a few minutes, a new key is added well before it signs.
+func (handler *AuthHandler) JWKS(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "public, max-age=300")
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(handler.authService.JWKS())
+}
+
+// clientIP is the address a request came from, without its port. Failed
+// logins are counted per address.
+func clientIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
 }
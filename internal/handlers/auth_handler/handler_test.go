@@ -2,6 +2,7 @@ package authhandler
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"io"
@@ -10,6 +11,7 @@ import (
 	"reflect"
 	"testing"
 
+	apperrors "github.com/Kaushik1766/LibraryManagement/internal/app_errors"
 	"github.com/Kaushik1766/LibraryManagement/internal/models"
 	authservice "github.com/Kaushik1766/LibraryManagement/internal/service/auth_service"
 	"github.com/Kaushik1766/LibraryManagement/mocks"
@@ -56,23 +58,21 @@ func TestAuthHandler_Login(t *testing.T) {
 					return errors.New("wrong status code")
 				}
 				data, _ := io.ReadAll(resp.Body)
-				var token struct {
-					Jwt string `json:"jwt"`
-				}
+				var tokens models.TokenPairDTO
 
-				err := json.Unmarshal(data, &token)
+				err := json.Unmarshal(data, &tokens)
 				if err != nil {
 					return errors.New("invalid response body: " + err.Error())
 				}
 
-				if token.Jwt == "validToken" {
+				if tokens.AccessToken == "validToken" && tokens.RefreshToken == "refreshToken" {
 					return nil
 				} else {
 					return errors.New("invalid token")
 				}
 			},
 			mockSetup: func() {
-				authService.EXPECT().Login(gomock.Any()).Return("validToken", nil)
+				authService.EXPECT().Login(gomock.Any(), "192.0.2.1").Return(models.TokenPairDTO{AccessToken: "validToken", RefreshToken: "refreshToken"}, nil)
 			},
 		},
 		{
@@ -110,7 +110,7 @@ func TestAuthHandler_Login(t *testing.T) {
 				return nil
 			},
 			mockSetup: func() {
-				authService.EXPECT().Login(gomock.Any()).Return("", errors.New("invalid credentials"))
+				authService.EXPECT().Login(gomock.Any(), gomock.Any()).Return(models.TokenPairDTO{}, errors.New("invalid credentials"))
 			},
 		},
 	}
@@ -249,3 +249,669 @@ func TestNewAuthHandler(t *testing.T) {
 		})
 	}
 }
+
+func TestAuthHandler_Refresh(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	authService := mocks.NewMockAuthManager(ctrl)
+
+	tests := []struct {
+		name           string
+		body           io.Reader
+		expectedStatus int
+		mockSetup      func()
+	}{
+		{
+			name:           "valid refresh",
+			body:           anyToReader(models.RefreshDTO{RefreshToken: "refreshToken"}),
+			expectedStatus: http.StatusOK,
+			mockSetup: func() {
+				authService.EXPECT().Refresh("refreshToken").Return(models.TokenPairDTO{AccessToken: "jwt", RefreshToken: "next"}, nil)
+			},
+		},
+		{
+			name:           "invalid json",
+			body:           anyToReader("invalid json"),
+			expectedStatus: http.StatusBadRequest,
+			mockSetup:      func() {},
+		},
+		{
+			name:           "reused token",
+			body:           anyToReader(models.RefreshDTO{RefreshToken: "refreshToken"}),
+			expectedStatus: http.StatusUnauthorized,
+			mockSetup: func() {
+				authService.EXPECT().Refresh("refreshToken").Return(models.TokenPairDTO{}, errors.New("refresh token reuse detected"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := &AuthHandler{
+				authService: authService,
+			}
+			tt.mockSetup()
+			recorder := httptest.NewRecorder()
+			handler.Refresh(recorder, httptest.NewRequest(http.MethodPost, "/auth/refresh", tt.body))
+			if recorder.Code != tt.expectedStatus {
+				t.Errorf("Refresh() status = %v, want %v", recorder.Code, tt.expectedStatus)
+			}
+		})
+	}
+}
+
+func TestAuthHandler_Logout(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	authService := mocks.NewMockAuthManager(ctrl)
+
+	tests := []struct {
+		name           string
+		all            bool
+		expectedStatus int
+		mockSetup      func()
+	}{
+		{
+			name:           "valid logout",
+			expectedStatus: http.StatusOK,
+			mockSetup: func() {
+				authService.EXPECT().Logout(gomock.Any()).Return(nil)
+			},
+		},
+		{
+			name:           "logout failed",
+			expectedStatus: http.StatusInternalServerError,
+			mockSetup: func() {
+				authService.EXPECT().Logout(gomock.Any()).Return(errors.New("session not found"))
+			},
+		},
+		{
+			name:           "valid logout all",
+			all:            true,
+			expectedStatus: http.StatusOK,
+			mockSetup: func() {
+				authService.EXPECT().LogoutAll(gomock.Any()).Return(nil)
+			},
+		},
+		{
+			name:           "logout all failed",
+			all:            true,
+			expectedStatus: http.StatusInternalServerError,
+			mockSetup: func() {
+				authService.EXPECT().LogoutAll(gomock.Any()).Return(errors.New("db error"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := &AuthHandler{
+				authService: authService,
+			}
+			tt.mockSetup()
+			recorder := httptest.NewRecorder()
+			if tt.all {
+				handler.LogoutAll(context.Background(), recorder, httptest.NewRequest(http.MethodPost, "/auth/logout-all", nil))
+			} else {
+				handler.Logout(context.Background(), recorder, httptest.NewRequest(http.MethodPost, "/auth/logout", nil))
+			}
+			if recorder.Code != tt.expectedStatus {
+				t.Errorf("Logout() status = %v, want %v", recorder.Code, tt.expectedStatus)
+			}
+		})
+	}
+}
+
+func TestAuthHandler_Profile(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	authService := mocks.NewMockAuthManager(ctrl)
+
+	tests := []struct {
+		name           string
+		r              *http.Request
+		expectedStatus int
+		mockSetup      func()
+	}{
+		{
+			name:           "valid get profile",
+			r:              httptest.NewRequest(http.MethodGet, "/me", nil),
+			expectedStatus: http.StatusOK,
+			mockSetup: func() {
+				authService.EXPECT().GetProfile(gomock.Any()).Return(models.ProfileDTO{Name: "kaushik"}, nil)
+			},
+		},
+		{
+			name:           "valid update profile",
+			r:              httptest.NewRequest(http.MethodPatch, "/me", anyToReader(map[string]string{"name": "kaushik"})),
+			expectedStatus: http.StatusOK,
+			mockSetup: func() {
+				authService.EXPECT().UpdateProfile(gomock.Any(), gomock.Any()).Return(models.ProfileDTO{Name: "kaushik"}, nil)
+			},
+		},
+		{
+			name:           "invalid json",
+			r:              httptest.NewRequest(http.MethodPatch, "/me", bytes.NewReader([]byte("invalid json"))),
+			expectedStatus: http.StatusBadRequest,
+			mockSetup:      func() {},
+		},
+		{
+			name:           "update failed",
+			r:              httptest.NewRequest(http.MethodPatch, "/me", anyToReader(map[string]string{"email": "kaushik"})),
+			expectedStatus: http.StatusInternalServerError,
+			mockSetup: func() {
+				authService.EXPECT().UpdateProfile(gomock.Any(), gomock.Any()).Return(models.ProfileDTO{}, errors.New("invalid email"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := &AuthHandler{
+				authService: authService,
+			}
+			tt.mockSetup()
+			recorder := httptest.NewRecorder()
+			if tt.r.Method == http.MethodGet {
+				handler.GetProfile(context.Background(), recorder, tt.r)
+			} else {
+				handler.UpdateProfile(context.Background(), recorder, tt.r)
+			}
+			if recorder.Code != tt.expectedStatus {
+				t.Errorf("Profile() status = %v, want %v", recorder.Code, tt.expectedStatus)
+			}
+		})
+	}
+}
+
+func TestAuthHandler_ChangePassword(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	authService := mocks.NewMockAuthManager(ctrl)
+
+	tests := []struct {
+		name           string
+		body           io.Reader
+		expectedStatus int
+		mockSetup      func()
+	}{
+		{
+			name:           "valid change",
+			body:           anyToReader(map[string]string{"current_password": "old", "new_password": "new"}),
+			expectedStatus: http.StatusOK,
+			mockSetup: func() {
+				authService.EXPECT().ChangePassword(gomock.Any(), models.ChangePasswordDTO{CurrentPassword: "old", NewPassword: "new"}).Return(nil)
+			},
+		},
+		{
+			name:           "invalid json",
+			body:           bytes.NewReader([]byte("invalid json")),
+			expectedStatus: http.StatusBadRequest,
+			mockSetup:      func() {},
+		},
+		{
+			name:           "wrong password",
+			body:           anyToReader(map[string]string{"current_password": "guess", "new_password": "new"}),
+			expectedStatus: http.StatusInternalServerError,
+			mockSetup: func() {
+				authService.EXPECT().ChangePassword(gomock.Any(), gomock.Any()).Return(errors.New("invalid password"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := &AuthHandler{
+				authService: authService,
+			}
+			tt.mockSetup()
+			recorder := httptest.NewRecorder()
+			handler.ChangePassword(context.Background(), recorder, httptest.NewRequest(http.MethodPost, "/me/password", tt.body))
+			if recorder.Code != tt.expectedStatus {
+				t.Errorf("ChangePassword() status = %v, want %v", recorder.Code, tt.expectedStatus)
+			}
+		})
+	}
+}
+
+func TestAuthHandler_ForgotPassword(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	authService := mocks.NewMockAuthManager(ctrl)
+
+	tests := []struct {
+		name           string
+		body           io.Reader
+		expectedStatus int
+		mockSetup      func()
+	}{
+		{
+			name:           "valid request",
+			body:           anyToReader(map[string]string{"email": "kaushik@a.com"}),
+			expectedStatus: http.StatusOK,
+			mockSetup: func() {
+				authService.EXPECT().ForgotPassword(models.ForgotPasswordDTO{Email: "kaushik@a.com"}).Return(nil)
+			},
+		},
+		{
+			name:           "invalid json",
+			body:           bytes.NewReader([]byte("invalid json")),
+			expectedStatus: http.StatusBadRequest,
+			mockSetup:      func() {},
+		},
+		{
+			name:           "service error",
+			body:           anyToReader(map[string]string{"email": "kaushik@a.com"}),
+			expectedStatus: http.StatusInternalServerError,
+			mockSetup: func() {
+				authService.EXPECT().ForgotPassword(gomock.Any()).Return(errors.New("database error"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := &AuthHandler{
+				authService: authService,
+			}
+			tt.mockSetup()
+			recorder := httptest.NewRecorder()
+			handler.ForgotPassword(recorder, httptest.NewRequest(http.MethodPost, "/auth/password/forgot", tt.body))
+			if recorder.Code != tt.expectedStatus {
+				t.Errorf("ForgotPassword() status = %v, want %v", recorder.Code, tt.expectedStatus)
+			}
+		})
+	}
+}
+
+func TestAuthHandler_ResetPassword(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	authService := mocks.NewMockAuthManager(ctrl)
+
+	tests := []struct {
+		name           string
+		body           io.Reader
+		expectedStatus int
+		mockSetup      func()
+	}{
+		{
+			name:           "valid reset",
+			body:           anyToReader(map[string]string{"token": "abc", "new_password": "new"}),
+			expectedStatus: http.StatusOK,
+			mockSetup: func() {
+				authService.EXPECT().ResetPassword(models.ResetPasswordDTO{Token: "abc", NewPassword: "new"}).Return(nil)
+			},
+		},
+		{
+			name:           "invalid json",
+			body:           bytes.NewReader([]byte("invalid json")),
+			expectedStatus: http.StatusBadRequest,
+			mockSetup:      func() {},
+		},
+		{
+			name:           "invalid token",
+			body:           anyToReader(map[string]string{"token": "used", "new_password": "new"}),
+			expectedStatus: http.StatusInternalServerError,
+			mockSetup: func() {
+				authService.EXPECT().ResetPassword(gomock.Any()).Return(errors.New("reset token is invalid or expired"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := &AuthHandler{
+				authService: authService,
+			}
+			tt.mockSetup()
+			recorder := httptest.NewRecorder()
+			handler.ResetPassword(recorder, httptest.NewRequest(http.MethodPost, "/auth/password/reset", tt.body))
+			if recorder.Code != tt.expectedStatus {
+				t.Errorf("ResetPassword() status = %v, want %v", recorder.Code, tt.expectedStatus)
+			}
+		})
+	}
+}
+
+func TestAuthHandler_VerifyEmail(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	authService := mocks.NewMockAuthManager(ctrl)
+
+	tests := []struct {
+		name           string
+		body           io.Reader
+		expectedStatus int
+		mockSetup      func()
+	}{
+		{
+			name:           "valid verification",
+			body:           anyToReader(map[string]string{"token": "abc"}),
+			expectedStatus: http.StatusOK,
+			mockSetup: func() {
+				authService.EXPECT().VerifyEmail(models.VerifyEmailDTO{Token: "abc"}).Return(nil)
+			},
+		},
+		{
+			name:           "invalid json",
+			body:           bytes.NewReader([]byte("invalid json")),
+			expectedStatus: http.StatusBadRequest,
+			mockSetup:      func() {},
+		},
+		{
+			name:           "invalid token",
+			body:           anyToReader(map[string]string{"token": "forged"}),
+			expectedStatus: http.StatusInternalServerError,
+			mockSetup: func() {
+				authService.EXPECT().VerifyEmail(gomock.Any()).Return(errors.New("verification link is invalid or expired"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := &AuthHandler{
+				authService: authService,
+			}
+			tt.mockSetup()
+			recorder := httptest.NewRecorder()
+			handler.VerifyEmail(recorder, httptest.NewRequest(http.MethodPost, "/auth/verify", tt.body))
+			if recorder.Code != tt.expectedStatus {
+				t.Errorf("VerifyEmail() status = %v, want %v", recorder.Code, tt.expectedStatus)
+			}
+		})
+	}
+}
+
+func TestAuthHandler_ResendVerification(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	authService := mocks.NewMockAuthManager(ctrl)
+
+	tests := []struct {
+		name           string
+		expectedStatus int
+		mockSetup      func()
+	}{
+		{
+			name:           "valid resend",
+			expectedStatus: http.StatusOK,
+			mockSetup: func() {
+				authService.EXPECT().ResendVerification(gomock.Any()).Return(nil)
+			},
+		},
+		{
+			name:           "sent recently",
+			expectedStatus: http.StatusTooManyRequests,
+			mockSetup: func() {
+				authService.EXPECT().ResendVerification(gomock.Any()).
+					Return(apperrors.TooManyRequests("verification_rate_limited", "a verification email was sent recently, try again later"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := &AuthHandler{
+				authService: authService,
+			}
+			tt.mockSetup()
+			recorder := httptest.NewRecorder()
+			handler.ResendVerification(context.Background(), recorder, httptest.NewRequest(http.MethodPost, "/auth/verify/resend", nil))
+			if recorder.Code != tt.expectedStatus {
+				t.Errorf("ResendVerification() status = %v, want %v", recorder.Code, tt.expectedStatus)
+			}
+		})
+	}
+}
+
+func TestAuthHandler_VerifyMFA(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	authService := mocks.NewMockAuthManager(ctrl)
+
+	tests := []struct {
+		name           string
+		body           io.Reader
+		expectedStatus int
+		mockSetup      func()
+	}{
+		{
+			name:           "valid code",
+			body:           anyToReader(map[string]string{"mfa_token": "abc", "code": "123456"}),
+			expectedStatus: http.StatusOK,
+			mockSetup: func() {
+				authService.EXPECT().VerifyMFA(models.VerifyMFADTO{MFAToken: "abc", Code: "123456"}, "192.0.2.1").
+					Return(models.TokenPairDTO{AccessToken: "jwt", RefreshToken: "refresh"}, nil)
+			},
+		},
+		{
+			name:           "invalid json",
+			body:           bytes.NewReader([]byte("invalid json")),
+			expectedStatus: http.StatusBadRequest,
+			mockSetup:      func() {},
+		},
+		{
+			name:           "wrong code",
+			body:           anyToReader(map[string]string{"mfa_token": "abc", "code": "000000"}),
+			expectedStatus: http.StatusInternalServerError,
+			mockSetup: func() {
+				authService.EXPECT().VerifyMFA(gomock.Any(), gomock.Any()).Return(models.TokenPairDTO{}, errors.New("invalid authentication code"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := &AuthHandler{
+				authService: authService,
+			}
+			tt.mockSetup()
+			recorder := httptest.NewRecorder()
+			handler.VerifyMFA(recorder, httptest.NewRequest(http.MethodPost, "/auth/mfa/verify", tt.body))
+			if recorder.Code != tt.expectedStatus {
+				t.Errorf("VerifyMFA() status = %v, want %v", recorder.Code, tt.expectedStatus)
+			}
+		})
+	}
+}
+
+func TestAuthHandler_SetupMFA(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	authService := mocks.NewMockAuthManager(ctrl)
+
+	handler := &AuthHandler{
+		authService: authService,
+	}
+	setup := models.MFASetupDTO{Secret: "SECRET", URI: "otpauth://totp/Library:kaushik@a.com?secret=SECRET"}
+
+	t.Run("signed in", func(t *testing.T) {
+		authService.EXPECT().SetupMFA(gomock.Any()).Return(setup, nil)
+		recorder := httptest.NewRecorder()
+		handler.SetupMFA(context.Background(), recorder, httptest.NewRequest(http.MethodPost, "/me/mfa", nil))
+		if recorder.Code != http.StatusOK {
+			t.Errorf("SetupMFA() status = %v, want %v", recorder.Code, http.StatusOK)
+		}
+		var got models.MFASetupDTO
+		if err := json.NewDecoder(recorder.Body).Decode(&got); err != nil || got != setup {
+			t.Errorf("SetupMFA() body = %+v, want %+v", got, setup)
+		}
+	})
+
+	t.Run("already enabled", func(t *testing.T) {
+		authService.EXPECT().SetupMFA(gomock.Any()).Return(models.MFASetupDTO{}, errors.New("mfa is already enabled"))
+		recorder := httptest.NewRecorder()
+		handler.SetupMFA(context.Background(), recorder, httptest.NewRequest(http.MethodPost, "/me/mfa", nil))
+		if recorder.Code != http.StatusInternalServerError {
+			t.Errorf("SetupMFA() status = %v, want %v", recorder.Code, http.StatusInternalServerError)
+		}
+	})
+
+	t.Run("during login", func(t *testing.T) {
+		authService.EXPECT().SetupMFAForLogin(models.MFATokenDTO{MFAToken: "abc"}).Return(setup, nil)
+		recorder := httptest.NewRecorder()
+		handler.SetupMFAForLogin(recorder, httptest.NewRequest(http.MethodPost, "/auth/mfa/setup", anyToReader(map[string]string{"mfa_token": "abc"})))
+		if recorder.Code != http.StatusOK {
+			t.Errorf("SetupMFAForLogin() status = %v, want %v", recorder.Code, http.StatusOK)
+		}
+	})
+
+	t.Run("during login with invalid json", func(t *testing.T) {
+		recorder := httptest.NewRecorder()
+		handler.SetupMFAForLogin(recorder, httptest.NewRequest(http.MethodPost, "/auth/mfa/setup", bytes.NewReader([]byte("invalid json"))))
+		if recorder.Code != http.StatusBadRequest {
+			t.Errorf("SetupMFAForLogin() status = %v, want %v", recorder.Code, http.StatusBadRequest)
+		}
+	})
+}
+
+func TestAuthHandler_ConfirmMFA(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	authService := mocks.NewMockAuthManager(ctrl)
+
+	tests := []struct {
+		name           string
+		body           io.Reader
+		expectedStatus int
+		mockSetup      func()
+	}{
+		{
+			name:           "valid code",
+			body:           anyToReader(map[string]string{"code": "123456"}),
+			expectedStatus: http.StatusOK,
+			mockSetup: func() {
+				authService.EXPECT().ConfirmMFA(gomock.Any(), models.MFACodeDTO{Code: "123456"}).
+					Return(models.RecoveryCodesDTO{RecoveryCodes: []string{"abcd-2345"}}, nil)
+			},
+		},
+		{
+			name:           "invalid json",
+			body:           bytes.NewReader([]byte("invalid json")),
+			expectedStatus: http.StatusBadRequest,
+			mockSetup:      func() {},
+		},
+		{
+			name:           "wrong code",
+			body:           anyToReader(map[string]string{"code": "000000"}),
+			expectedStatus: http.StatusInternalServerError,
+			mockSetup: func() {
+				authService.EXPECT().ConfirmMFA(gomock.Any(), gomock.Any()).Return(models.RecoveryCodesDTO{}, errors.New("invalid authentication code"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := &AuthHandler{
+				authService: authService,
+			}
+			tt.mockSetup()
+			recorder := httptest.NewRecorder()
+			handler.ConfirmMFA(context.Background(), recorder, httptest.NewRequest(http.MethodPost, "/me/mfa/confirm", tt.body))
+			if recorder.Code != tt.expectedStatus {
+				t.Errorf("ConfirmMFA() status = %v, want %v", recorder.Code, tt.expectedStatus)
+			}
+		})
+	}
+}
+
+func TestAuthHandler_DisableMFA(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	authService := mocks.NewMockAuthManager(ctrl)
+
+	tests := []struct {
+		name           string
+		body           io.Reader
+		expectedStatus int
+		mockSetup      func()
+	}{
+		{
+			name:           "valid password and code",
+			body:           anyToReader(map[string]string{"password": "123", "code": "123456"}),
+			expectedStatus: http.StatusOK,
+			mockSetup: func() {
+				authService.EXPECT().DisableMFA(gomock.Any(), models.DisableMFADTO{Password: "123", Code: "123456"}).Return(nil)
+			},
+		},
+		{
+			name:           "invalid json",
+			body:           bytes.NewReader([]byte("invalid json")),
+			expectedStatus: http.StatusBadRequest,
+			mockSetup:      func() {},
+		},
+		{
+			name:           "required for the role",
+			body:           anyToReader(map[string]string{"password": "123", "code": "123456"}),
+			expectedStatus: http.StatusInternalServerError,
+			mockSetup: func() {
+				authService.EXPECT().DisableMFA(gomock.Any(), gomock.Any()).Return(errors.New("mfa is required for your role"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := &AuthHandler{
+				authService: authService,
+			}
+			tt.mockSetup()
+			recorder := httptest.NewRecorder()
+			handler.DisableMFA(context.Background(), recorder, httptest.NewRequest(http.MethodPost, "/me/mfa/disable", tt.body))
+			if recorder.Code != tt.expectedStatus {
+				t.Errorf("DisableMFA() status = %v, want %v", recorder.Code, tt.expectedStatus)
+			}
+		})
+	}
+}
+
+func TestAuthHandler_RegenerateRecoveryCodes(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	authService := mocks.NewMockAuthManager(ctrl)
+
+	tests := []struct {
+		name           string
+		body           io.Reader
+		expectedStatus int
+		mockSetup      func()
+	}{
+		{
+			name:           "valid code",
+			body:           anyToReader(map[string]string{"code": "123456"}),
+			expectedStatus: http.StatusOK,
+			mockSetup: func() {
+				authService.EXPECT().RegenerateRecoveryCodes(gomock.Any(), models.MFACodeDTO{Code: "123456"}).
+					Return(models.RecoveryCodesDTO{RecoveryCodes: []string{"abcd-2345"}}, nil)
+			},
+		},
+		{
+			name:           "invalid json",
+			body:           bytes.NewReader([]byte("invalid json")),
+			expectedStatus: http.StatusBadRequest,
+			mockSetup:      func() {},
+		},
+		{
+			name:           "mfa not enabled",
+			body:           anyToReader(map[string]string{"code": "123456"}),
+			expectedStatus: http.StatusInternalServerError,
+			mockSetup: func() {
+				authService.EXPECT().RegenerateRecoveryCodes(gomock.Any(), gomock.Any()).Return(models.RecoveryCodesDTO{}, errors.New("mfa is not enabled"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := &AuthHandler{
+				authService: authService,
+			}
+			tt.mockSetup()
+			recorder := httptest.NewRecorder()
+			handler.RegenerateRecoveryCodes(context.Background(), recorder, httptest.NewRequest(http.MethodPost, "/me/mfa/recovery-codes", tt.body))
+			if recorder.Code != tt.expectedStatus {
+				t.Errorf("RegenerateRecoveryCodes() status = %v, want %v", recorder.Code, tt.expectedStatus)
+			}
+		})
+	}
+}
+
+func TestAuthHandler_JWKS(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	authService := mocks.NewMockAuthManager(ctrl)
+
+	jwks := models.JWKSDTO{Keys: []models.JWKDTO{{KeyType: "OKP", ID: "2024-06", Use: "sig", Algorithm: "EdDSA", Curve: "Ed25519", X: "abc"}}}
+	authService.EXPECT().JWKS().Return(jwks)
+
+	handler := &AuthHandler{
+		authService: authService,
+	}
+	recorder := httptest.NewRecorder()
+	handler.JWKS(recorder, httptest.NewRequest(http.MethodGet, "/.well-known/jwks.json", nil))
+
+	if recorder.Code != http.StatusOK {
+		t.Errorf("JWKS() status = %v, want %v", recorder.Code, http.StatusOK)
+	}
+	var got models.JWKSDTO
+	if err := json.NewDecoder(recorder.Body).Decode(&got); err != nil || !reflect.DeepEqual(got, jwks) {
+		t.Errorf("JWKS() body = %+v, want %+v", got, jwks)
+	}
+}
@@ -0,0 +1,66 @@
+package blockhandler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/Kaushik1766/LibraryManagement/internal/models"
+	blockservice "github.com/Kaushik1766/LibraryManagement/internal/service/block_service"
+	weberrors "github.com/Kaushik1766/LibraryManagement/internal/web_errors"
+)
+
+type BlockHandler struct {
+	blockService blockservice.BlockManager
+}
+
+func NewBlockHandler(blockService blockservice.BlockManager) *BlockHandler {
+	return &BlockHandler{
+		blockService: blockService,
+	}
+}
+
+func (handler *BlockHandler) GetBlocks(ctx context.Context, w http.ResponseWriter, r *http.Request) {
+
+	blocks, err := handler.blockService.GetBlocks(ctx, r.PathValue("userId"))
+	if err != nil {
+		weberrors.SendError(err, http.StatusInternalServerError, w)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(blocks)
+}
+
+func (handler *BlockHandler) PlaceBlock(ctx context.Context, w http.ResponseWriter, r *http.Request) {
+	var req models.PlaceBlockDTO
+
+	data, _ := io.ReadAll(r.Body)
+	err := json.Unmarshal(data, &req)
+	if err != nil {
+		weberrors.SendError(err, http.StatusBadRequest, w)
+		return
+	}
+
+	blockId, err := handler.blockService.PlaceBlock(ctx, r.PathValue("userId"), req)
+	if err != nil {
+		weberrors.SendError(err, http.StatusInternalServerError, w)
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	w.Write([]byte(fmt.Sprintf(`{"block_id":"%s"}`, blockId)))
+}
+
+func (handler *BlockHandler) LiftBlock(ctx context.Context, w http.ResponseWriter, r *http.Request) {
+
+	err := handler.blockService.LiftBlock(ctx, r.PathValue("userId"), r.PathValue("blockId"))
+	if err != nil {
+		weberrors.SendError(err, http.StatusInternalServerError, w)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
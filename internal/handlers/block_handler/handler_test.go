@@ -0,0 +1,177 @@
+package blockhandler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/Kaushik1766/LibraryManagement/internal/models"
+	"github.com/Kaushik1766/LibraryManagement/mocks"
+	"go.uber.org/mock/gomock"
+)
+
+func anyToReader(data any) io.Reader {
+	dataJsonBytes, _ := json.Marshal(data)
+	return bytes.NewReader(dataJsonBytes)
+}
+
+func TestNewBlockHandler(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockBlockService := mocks.NewMockBlockManager(ctrl)
+
+	want := &BlockHandler{blockService: mockBlockService}
+	if got := NewBlockHandler(mockBlockService); !reflect.DeepEqual(got, want) {
+		t.Errorf("NewBlockHandler() = %v, want %v", got, want)
+	}
+}
+
+func TestBlockHandler_GetBlocks(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockBlockService := mocks.NewMockBlockManager(ctrl)
+
+	tests := []struct {
+		name           string
+		expectedStatus int
+		mockSetup      func()
+	}{
+		{
+			name:           "valid get blocks",
+			expectedStatus: http.StatusOK,
+			mockSetup: func() {
+				mockBlockService.EXPECT().GetBlocks(gomock.Any(), "550e8400-e29b-41d4-a716-446655440000").Return([]models.BlockDTO{{Kind: "overdue"}}, nil)
+			},
+		},
+		{
+			name:           "service error",
+			expectedStatus: http.StatusInternalServerError,
+			mockSetup: func() {
+				mockBlockService.EXPECT().GetBlocks(gomock.Any(), gomock.Any()).Return(nil, errors.New("database error"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := &BlockHandler{
+				blockService: mockBlockService,
+			}
+			tt.mockSetup()
+			r := httptest.NewRequest(http.MethodGet, "/users/550e8400-e29b-41d4-a716-446655440000/blocks", nil)
+			r.SetPathValue("userId", "550e8400-e29b-41d4-a716-446655440000")
+			recorder := httptest.NewRecorder()
+			handler.GetBlocks(context.Background(), recorder, r)
+
+			if recorder.Code != tt.expectedStatus {
+				t.Errorf("GetBlocks() status = %v, want %v", recorder.Code, tt.expectedStatus)
+			}
+		})
+	}
+}
+
+func TestBlockHandler_PlaceBlock(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockBlockService := mocks.NewMockBlockManager(ctrl)
+
+	tests := []struct {
+		name           string
+		body           io.Reader
+		expectedStatus int
+		mockSetup      func()
+	}{
+		{
+			name:           "valid place block",
+			body:           anyToReader(map[string]string{"reason": "lost a book"}),
+			expectedStatus: http.StatusCreated,
+			mockSetup: func() {
+				mockBlockService.EXPECT().PlaceBlock(gomock.Any(), "550e8400-e29b-41d4-a716-446655440000", models.PlaceBlockDTO{Reason: "lost a book"}).Return("550e8400-e29b-41d4-a716-446655440001", nil)
+			},
+		},
+		{
+			name:           "invalid json",
+			body:           bytes.NewReader([]byte("invalid json")),
+			expectedStatus: http.StatusBadRequest,
+			mockSetup: func() {
+			},
+		},
+		{
+			name:           "service error",
+			body:           anyToReader(map[string]string{"reason": "lost a book"}),
+			expectedStatus: http.StatusInternalServerError,
+			mockSetup: func() {
+				mockBlockService.EXPECT().PlaceBlock(gomock.Any(), gomock.Any(), gomock.Any()).Return("", errors.New("database error"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := &BlockHandler{
+				blockService: mockBlockService,
+			}
+			tt.mockSetup()
+			r := httptest.NewRequest(http.MethodPost, "/users/550e8400-e29b-41d4-a716-446655440000/blocks", tt.body)
+			r.SetPathValue("userId", "550e8400-e29b-41d4-a716-446655440000")
+			recorder := httptest.NewRecorder()
+			handler.PlaceBlock(context.Background(), recorder, r)
+
+			if recorder.Code != tt.expectedStatus {
+				t.Errorf("PlaceBlock() status = %v, want %v", recorder.Code, tt.expectedStatus)
+			}
+		})
+	}
+}
+
+func TestBlockHandler_LiftBlock(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockBlockService := mocks.NewMockBlockManager(ctrl)
+
+	tests := []struct {
+		name           string
+		expectedStatus int
+		mockSetup      func()
+	}{
+		{
+			name:           "valid lift block",
+			expectedStatus: http.StatusOK,
+			mockSetup: func() {
+				mockBlockService.EXPECT().LiftBlock(gomock.Any(), "550e8400-e29b-41d4-a716-446655440000", "550e8400-e29b-41d4-a716-446655440001").Return(nil)
+			},
+		},
+		{
+			name:           "service error",
+			expectedStatus: http.StatusInternalServerError,
+			mockSetup: func() {
+				mockBlockService.EXPECT().LiftBlock(gomock.Any(), gomock.Any(), gomock.Any()).Return(errors.New("database error"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := &BlockHandler{
+				blockService: mockBlockService,
+			}
+			tt.mockSetup()
+			r := httptest.NewRequest(http.MethodDelete, "/users/550e8400-e29b-41d4-a716-446655440000/blocks/550e8400-e29b-41d4-a716-446655440001", nil)
+			r.SetPathValue("userId", "550e8400-e29b-41d4-a716-446655440000")
+			r.SetPathValue("blockId", "550e8400-e29b-41d4-a716-446655440001")
+			recorder := httptest.NewRecorder()
+			handler.LiftBlock(context.Background(), recorder, r)
+
+			if recorder.Code != tt.expectedStatus {
+				t.Errorf("LiftBlock() status = %v, want %v", recorder.Code, tt.expectedStatus)
+			}
+		})
+	}
+}
@@ -1,16 +1,28 @@
 package bookhandler
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"io"
+	"log"
+	"mime"
 	"net/http"
+	"strconv"
 
+	apperrors "github.com/Kaushik1766/LibraryManagement/internal/app_errors"
+	"github.com/Kaushik1766/LibraryManagement/internal/marc"
 	"github.com/Kaushik1766/LibraryManagement/internal/models"
+	"github.com/Kaushik1766/LibraryManagement/internal/pagination"
 	bookservice "github.com/Kaushik1766/LibraryManagement/internal/service/book_service"
 	weberrors "github.com/Kaushik1766/LibraryManagement/internal/web_errors"
 )
 
+// maxImportSize caps the body of an import request.
+const maxImportSize = 10 << 20
+
 type BookHandler struct {
 	bookService bookservice.BookManager
 }
@@ -31,25 +43,290 @@ func (handler *BookHandler) AddBook(ctx context.Context, w http.ResponseWriter,
 		return
 	}
 
-	err = handler.bookService.AddBook(ctx, req)
+	bookId, err := handler.bookService.AddBook(ctx, req)
 	if err != nil {
 		weberrors.SendError(err, http.StatusInternalServerError, w)
 		return
 	}
 
 	w.WriteHeader(http.StatusCreated)
+	w.Write([]byte(fmt.Sprintf(`{"book_id":"%s"}`, bookId)))
+}
+
+// ImportBooks takes a CSV or JSON lines file as the request body. The format
+// comes from the format query parameter or else the content type, and
+// dry_run=true reports what would happen without writing anything.
+func (handler *BookHandler) ImportBooks(ctx context.Context, w http.ResponseWriter, r *http.Request) {
+
+	query := r.URL.Query()
+
+	dryRun := false
+	if raw := query.Get("dry_run"); raw != "" {
+		var err error
+		dryRun, err = strconv.ParseBool(raw)
+		if err != nil {
+			weberrors.SendError(apperrors.Validation("invalid_input", "invalid input").WithDetail("dry_run", "must be true or false"), http.StatusBadRequest, w)
+			return
+		}
+	}
+
+	format := query.Get("format")
+	if format == "" {
+		format, _, _ = mime.ParseMediaType(r.Header.Get("Content-Type"))
+	}
+
+	data, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxImportSize))
+	if err != nil {
+		weberrors.SendError(errors.New("import file too large"), http.StatusRequestEntityTooLarge, w)
+		return
+	}
+
+	report, err := handler.bookService.ImportBooks(ctx, format, bytes.NewReader(data), dryRun)
+	if err != nil {
+		weberrors.SendError(err, http.StatusInternalServerError, w)
+		return
+	}
+
+	switch {
+	case report.Committed:
+		w.WriteHeader(http.StatusCreated)
+	case report.Invalid > 0 && !report.DryRun:
+		w.WriteHeader(http.StatusUnprocessableEntity)
+	default:
+		w.WriteHeader(http.StatusOK)
+	}
+	json.NewEncoder(w).Encode(report)
 }
 
 func (handler *BookHandler) GetAllBooks(ctx context.Context, w http.ResponseWriter, r *http.Request) {
 
 	query := r.URL.Query()
 
-	books, err := handler.bookService.GetAllBooks(ctx, query.Get("title"), query.Get("author"))
+	page, err := pagination.FromQuery(query)
+	if err != nil {
+		weberrors.SendError(err, http.StatusBadRequest, w)
+		return
+	}
+
+	books, err := handler.bookService.GetAllBooks(ctx, query.Get("title"), query.Get("author"), page)
 	if err != nil {
 		weberrors.SendError(err, http.StatusInternalServerError, w)
 		return
 	}
 
+	pagination.SetLink(w, r, books.NextCursor)
 	w.WriteHeader(http.StatusOK)
 	json.NewEncoder(w).Encode(books)
 }
+
+func (handler *BookHandler) SearchBooks(ctx context.Context, w http.ResponseWriter, r *http.Request) {
+
+	query := r.URL.Query()
+
+	page, err := pagination.FromQuery(query)
+	if err != nil {
+		weberrors.SendError(err, http.StatusBadRequest, w)
+		return
+	}
+
+	result, err := handler.bookService.SearchBooks(ctx, models.BookSearch{
+		Query:     query.Get("q"),
+		Author:    query.Get("author"),
+		Subject:   query.Get("subject"),
+		Language:  query.Get("language"),
+		Available: query.Get("available"),
+	}, page)
+	if err != nil {
+		weberrors.SendError(err, http.StatusInternalServerError, w)
+		return
+	}
+
+	pagination.SetLink(w, r, result.NextCursor)
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(result)
+}
+
+func (handler *BookHandler) GetCopies(ctx context.Context, w http.ResponseWriter, r *http.Request) {
+
+	bookId := r.PathValue("bookId")
+
+	copies, err := handler.bookService.GetCopies(ctx, bookId)
+	if err != nil {
+		weberrors.SendError(err, http.StatusInternalServerError, w)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(copies)
+}
+
+func (handler *BookHandler) AddCopies(ctx context.Context, w http.ResponseWriter, r *http.Request) {
+	var req models.AddCopiesDTO
+
+	data, _ := io.ReadAll(r.Body)
+	err := json.Unmarshal(data, &req)
+	if err != nil {
+		weberrors.SendError(err, http.StatusBadRequest, w)
+		return
+	}
+
+	err = handler.bookService.AddCopies(ctx, r.PathValue("bookId"), req)
+	if err != nil {
+		weberrors.SendError(err, http.StatusInternalServerError, w)
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+}
+
+func (handler *BookHandler) GetBook(ctx context.Context, w http.ResponseWriter, r *http.Request) {
+
+	book, err := handler.bookService.GetBook(ctx, r.PathValue("bookId"))
+	if err != nil {
+		weberrors.SendError(err, http.StatusInternalServerError, w)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(book)
+}
+
+// ExportBook responds with the MARC record of a title, as MARCXML unless
+// format=marc asks for ISO 2709.
+func (handler *BookHandler) ExportBook(ctx context.Context, w http.ResponseWriter, r *http.Request) {
+
+	format, err := exportFormat(r)
+	if err != nil {
+		weberrors.SendError(err, http.StatusBadRequest, w)
+		return
+	}
+
+	record, err := handler.bookService.ExportBook(ctx, r.PathValue("bookId"))
+	if err != nil {
+		weberrors.SendError(err, http.StatusInternalServerError, w)
+		return
+	}
+
+	var body bytes.Buffer
+	enc := marc.NewEncoder(format, &body)
+	if err := enc.Encode(record); err == nil {
+		err = enc.Close()
+	}
+	if err != nil {
+		weberrors.SendError(err, http.StatusInternalServerError, w)
+		return
+	}
+
+	w.Header().Set("Content-Type", format.ContentType())
+	w.WriteHeader(http.StatusOK)
+	w.Write(body.Bytes())
+}
+
+// ExportCatalog streams every title as one MARC file. Once the dump has
+// started an error can only cut it short.
+func (handler *BookHandler) ExportCatalog(ctx context.Context, w http.ResponseWriter, r *http.Request) {
+
+	format, err := exportFormat(r)
+	if err != nil {
+		weberrors.SendError(err, http.StatusBadRequest, w)
+		return
+	}
+
+	w.Header().Set("Content-Type", format.ContentType())
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="catalog%s"`, format.Extension()))
+
+	body := &watchedWriter{Writer: w}
+	enc := marc.NewEncoder(format, body)
+	err = handler.bookService.ExportCatalog(ctx, enc)
+	if err == nil {
+		err = enc.Close()
+	}
+	if err == nil {
+		return
+	}
+
+	if body.written {
+		log.Printf("catalog export cut short: %v\n", err)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Del("Content-Disposition")
+	weberrors.SendError(err, http.StatusInternalServerError, w)
+}
+
+func exportFormat(r *http.Request) (marc.Format, error) {
+	format := r.URL.Query().Get("format")
+	if format == "" {
+		return marc.XML, nil
+	}
+	return marc.FormatOf(format)
+}
+
+// watchedWriter remembers whether any of the response body has been sent.
+type watchedWriter struct {
+	io.Writer
+	written bool
+}
+
+func (w *watchedWriter) Write(p []byte) (int, error) {
+	w.written = true
+	return w.Writer.Write(p)
+}
+
+func (handler *BookHandler) UpdateBook(ctx context.Context, w http.ResponseWriter, r *http.Request) {
+	var req models.UpdateBookDTO
+
+	data, _ := io.ReadAll(r.Body)
+	err := json.Unmarshal(data, &req)
+	if err != nil {
+		weberrors.SendError(err, http.StatusBadRequest, w)
+		return
+	}
+
+	book, err := handler.bookService.UpdateBook(ctx, r.PathValue("bookId"), req)
+	if err != nil {
+		weberrors.SendError(err, http.StatusInternalServerError, w)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(book)
+}
+
+func (handler *BookHandler) WithdrawBook(ctx context.Context, w http.ResponseWriter, r *http.Request) {
+	var req models.WithdrawDTO
+
+	data, _ := io.ReadAll(r.Body)
+	err := json.Unmarshal(data, &req)
+	if err != nil {
+		weberrors.SendError(err, http.StatusBadRequest, w)
+		return
+	}
+
+	err = handler.bookService.WithdrawBook(ctx, r.PathValue("bookId"), req.Reason)
+	if err != nil {
+		weberrors.SendError(err, http.StatusInternalServerError, w)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+func (handler *BookHandler) WithdrawCopy(ctx context.Context, w http.ResponseWriter, r *http.Request) {
+	var req models.WithdrawDTO
+
+	data, _ := io.ReadAll(r.Body)
+	err := json.Unmarshal(data, &req)
+	if err != nil {
+		weberrors.SendError(err, http.StatusBadRequest, w)
+		return
+	}
+
+	err = handler.bookService.WithdrawCopy(ctx, r.PathValue("bookId"), r.PathValue("copyId"), req.Reason)
+	if err != nil {
+		weberrors.SendError(err, http.StatusInternalServerError, w)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
@@ -9,9 +9,13 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"reflect"
+	"strings"
 	"testing"
 
+	apperrors "github.com/Kaushik1766/LibraryManagement/internal/app_errors"
+	"github.com/Kaushik1766/LibraryManagement/internal/marc"
 	"github.com/Kaushik1766/LibraryManagement/internal/models"
+	"github.com/Kaushik1766/LibraryManagement/internal/pagination"
 	bookservice "github.com/Kaushik1766/LibraryManagement/internal/service/book_service"
 	"github.com/Kaushik1766/LibraryManagement/mocks"
 	"go.uber.org/mock/gomock"
@@ -90,7 +94,7 @@ func TestBookHandler_AddBook(t *testing.T) {
 			},
 			expectedStatus: http.StatusCreated,
 			mockSetup: func() {
-				mockBookService.EXPECT().AddBook(gomock.Any(), gomock.Any()).Return(nil)
+				mockBookService.EXPECT().AddBook(gomock.Any(), gomock.Any()).Return("550e8400-e29b-41d4-a716-446655440000", nil)
 			},
 		},
 		{
@@ -121,7 +125,7 @@ func TestBookHandler_AddBook(t *testing.T) {
 			},
 			expectedStatus: http.StatusInternalServerError,
 			mockSetup: func() {
-				mockBookService.EXPECT().AddBook(gomock.Any(), gomock.Any()).Return(errors.New("service error"))
+				mockBookService.EXPECT().AddBook(gomock.Any(), gomock.Any()).Return("", errors.New("service error"))
 			},
 		},
 	}
@@ -147,6 +151,7 @@ func TestBookHandler_GetAllBooks(t *testing.T) {
 	defer ctrl.Finish()
 
 	mockBookService := mocks.NewMockBookManager(ctrl)
+	defaultPage := pagination.Request{Limit: pagination.DefaultLimit}
 
 	type fields struct {
 		bookService bookservice.BookManager
@@ -160,6 +165,7 @@ func TestBookHandler_GetAllBooks(t *testing.T) {
 		fields         fields
 		args           args
 		expectedStatus int
+		expectedLink   string
 		mockSetup      func()
 	}{
 		{
@@ -173,14 +179,15 @@ func TestBookHandler_GetAllBooks(t *testing.T) {
 			},
 			expectedStatus: http.StatusOK,
 			mockSetup: func() {
-				mockBookService.EXPECT().GetAllBooks(gomock.Any(), "", "").Return([]models.BookDTO{
+				mockBookService.EXPECT().GetAllBooks(gomock.Any(), "", "", defaultPage).Return(pagination.Page[models.BookDTO]{Items: []models.BookDTO{
 					{
-						ID:       "550e8400-e29b-41d4-a716-446655440000",
-						Title:    "Harry Potter",
-						Author:   "J.K. Rowling",
-						IssuedTo: "",
+						ID:              "550e8400-e29b-41d4-a716-446655440000",
+						Title:           "Harry Potter",
+						Author:          "J.K. Rowling",
+						TotalCopies:     2,
+						AvailableCopies: 1,
 					},
-				}, nil)
+				}}, nil)
 			},
 		},
 		{
@@ -194,14 +201,15 @@ func TestBookHandler_GetAllBooks(t *testing.T) {
 			},
 			expectedStatus: http.StatusOK,
 			mockSetup: func() {
-				mockBookService.EXPECT().GetAllBooks(gomock.Any(), "Harry Potter", "").Return([]models.BookDTO{
+				mockBookService.EXPECT().GetAllBooks(gomock.Any(), "Harry Potter", "", defaultPage).Return(pagination.Page[models.BookDTO]{Items: []models.BookDTO{
 					{
-						ID:       "550e8400-e29b-41d4-a716-446655440000",
-						Title:    "Harry Potter",
-						Author:   "J.K. Rowling",
-						IssuedTo: "",
+						ID:              "550e8400-e29b-41d4-a716-446655440000",
+						Title:           "Harry Potter",
+						Author:          "J.K. Rowling",
+						TotalCopies:     2,
+						AvailableCopies: 1,
 					},
-				}, nil)
+				}}, nil)
 			},
 		},
 		{
@@ -215,14 +223,15 @@ func TestBookHandler_GetAllBooks(t *testing.T) {
 			},
 			expectedStatus: http.StatusOK,
 			mockSetup: func() {
-				mockBookService.EXPECT().GetAllBooks(gomock.Any(), "", "J.K. Rowling").Return([]models.BookDTO{
+				mockBookService.EXPECT().GetAllBooks(gomock.Any(), "", "J.K. Rowling", defaultPage).Return(pagination.Page[models.BookDTO]{Items: []models.BookDTO{
 					{
-						ID:       "550e8400-e29b-41d4-a716-446655440000",
-						Title:    "Harry Potter",
-						Author:   "J.K. Rowling",
-						IssuedTo: "",
+						ID:              "550e8400-e29b-41d4-a716-446655440000",
+						Title:           "Harry Potter",
+						Author:          "J.K. Rowling",
+						TotalCopies:     2,
+						AvailableCopies: 1,
 					},
-				}, nil)
+				}}, nil)
 			},
 		},
 		{
@@ -236,16 +245,48 @@ func TestBookHandler_GetAllBooks(t *testing.T) {
 			},
 			expectedStatus: http.StatusOK,
 			mockSetup: func() {
-				mockBookService.EXPECT().GetAllBooks(gomock.Any(), "Harry Potter", "J.K. Rowling").Return([]models.BookDTO{
+				mockBookService.EXPECT().GetAllBooks(gomock.Any(), "Harry Potter", "J.K. Rowling", defaultPage).Return(pagination.Page[models.BookDTO]{Items: []models.BookDTO{
 					{
-						ID:       "550e8400-e29b-41d4-a716-446655440000",
-						Title:    "Harry Potter",
-						Author:   "J.K. Rowling",
-						IssuedTo: "",
+						ID:              "550e8400-e29b-41d4-a716-446655440000",
+						Title:           "Harry Potter",
+						Author:          "J.K. Rowling",
+						TotalCopies:     2,
+						AvailableCopies: 1,
 					},
+				}}, nil)
+			},
+		},
+		{
+			name: "next page link",
+			fields: fields{
+				bookService: mockBookService,
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: httptest.NewRequest(http.MethodGet, "/books?limit=1&sort=-author", nil),
+			},
+			expectedStatus: http.StatusOK,
+			expectedLink:   `</books?cursor=abc&limit=1&sort=-author>; rel="next"`,
+			mockSetup: func() {
+				mockBookService.EXPECT().GetAllBooks(gomock.Any(), "", "", pagination.Request{Limit: 1, Sort: "-author"}).Return(pagination.Page[models.BookDTO]{
+					Items:      []models.BookDTO{{ID: "550e8400-e29b-41d4-a716-446655440000", Title: "Harry Potter"}},
+					NextCursor: "abc",
+					Total:      2,
 				}, nil)
 			},
 		},
+		{
+			name: "invalid limit",
+			fields: fields{
+				bookService: mockBookService,
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: httptest.NewRequest(http.MethodGet, "/books?limit=0", nil),
+			},
+			expectedStatus: http.StatusBadRequest,
+			mockSetup:      func() {},
+		},
 		{
 			name: "service error",
 			fields: fields{
@@ -257,7 +298,7 @@ func TestBookHandler_GetAllBooks(t *testing.T) {
 			},
 			expectedStatus: http.StatusInternalServerError,
 			mockSetup: func() {
-				mockBookService.EXPECT().GetAllBooks(gomock.Any(), "", "").Return(nil, errors.New("service error"))
+				mockBookService.EXPECT().GetAllBooks(gomock.Any(), "", "", defaultPage).Return(pagination.Page[models.BookDTO]{}, errors.New("service error"))
 			},
 		},
 	}
@@ -273,6 +314,629 @@ func TestBookHandler_GetAllBooks(t *testing.T) {
 				if recorder.Code != tt.expectedStatus {
 					t.Errorf("GetAllBooks() status = %v, want %v", recorder.Code, tt.expectedStatus)
 				}
+				if link := recorder.Header().Get("Link"); link != tt.expectedLink {
+					t.Errorf("GetAllBooks() link = %v, want %v", link, tt.expectedLink)
+				}
+			}
+		})
+	}
+}
+
+func TestBookHandler_SearchBooks(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockBookService := mocks.NewMockBookManager(ctrl)
+
+	tests := []struct {
+		name           string
+		target         string
+		expectedStatus int
+		expectedBody   string
+		mockSetup      func()
+	}{
+		{
+			name:           "valid search",
+			target:         "/books/search?q=harry&language=en&available=true",
+			expectedStatus: http.StatusOK,
+			expectedBody:   `{"items":[{"book_id":"550e8400-e29b-41d4-a716-446655440000","title":"Harry Potter","author":"J.K. Rowling","total_copies":2,"available_copies":1,"rank":0.5,"snippet":"\u003cmark\u003eHarry\u003c/mark\u003e Potter"}],"total":1,"facets":{"authors":[{"value":"J.K. Rowling","count":1}],"subjects":[],"languages":[],"availability":[]}}`,
+			mockSetup: func() {
+				mockBookService.EXPECT().SearchBooks(gomock.Any(), models.BookSearch{Query: "harry", Language: "en", Available: "true"}, pagination.Request{Limit: pagination.DefaultLimit}).Return(models.SearchResultDTO{
+					Page: pagination.Page[models.SearchHitDTO]{
+						Items: []models.SearchHitDTO{{
+							BookDTO: models.BookDTO{
+								ID:              "550e8400-e29b-41d4-a716-446655440000",
+								Title:           "Harry Potter",
+								Author:          "J.K. Rowling",
+								TotalCopies:     2,
+								AvailableCopies: 1,
+							},
+							Rank:    0.5,
+							Snippet: "<mark>Harry</mark> Potter",
+						}},
+						Total: 1,
+					},
+					Facets: models.SearchFacets{
+						Authors:      []models.FacetCount{{Value: "J.K. Rowling", Count: 1}},
+						Subjects:     []models.FacetCount{},
+						Languages:    []models.FacetCount{},
+						Availability: []models.FacetCount{},
+					},
+				}, nil)
+			},
+		},
+		{
+			name:           "invalid limit",
+			target:         "/books/search?q=harry&limit=1000",
+			expectedStatus: http.StatusBadRequest,
+			mockSetup:      func() {},
+		},
+		{
+			name:           "missing query",
+			target:         "/books/search",
+			expectedStatus: http.StatusBadRequest,
+			mockSetup: func() {
+				mockBookService.EXPECT().SearchBooks(gomock.Any(), models.BookSearch{}, gomock.Any()).Return(models.SearchResultDTO{}, apperrors.Validation("missing_query", "search query is required"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := &BookHandler{
+				bookService: mockBookService,
+			}
+			tt.mockSetup()
+
+			w := httptest.NewRecorder()
+			handler.SearchBooks(context.Background(), w, httptest.NewRequest(http.MethodGet, tt.target, nil))
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("SearchBooks() status = %v, want %v", w.Code, tt.expectedStatus)
+			}
+			if body := strings.TrimSpace(w.Body.String()); tt.expectedBody != "" && body != tt.expectedBody {
+				t.Errorf("SearchBooks() body = %v, want %v", body, tt.expectedBody)
+			}
+		})
+	}
+}
+
+func TestBookHandler_ImportBooks(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockBookService := mocks.NewMockBookManager(ctrl)
+
+	file := "title,author\ndune,frank herbert\n"
+
+	tests := []struct {
+		name           string
+		target         string
+		contentType    string
+		expectedStatus int
+		mockSetup      func()
+	}{
+		{
+			name:           "committed",
+			target:         "/books/import",
+			contentType:    "text/csv; charset=utf-8",
+			expectedStatus: http.StatusCreated,
+			mockSetup: func() {
+				mockBookService.EXPECT().ImportBooks(gomock.Any(), "text/csv", gomock.Any(), false).
+					Return(models.ImportReport{Committed: true, Total: 1, Imported: 1}, nil)
+			},
+		},
+		{
+			name:           "dry run with format parameter",
+			target:         "/books/import?format=csv&dry_run=true",
+			contentType:    "application/octet-stream",
+			expectedStatus: http.StatusOK,
+			mockSetup: func() {
+				mockBookService.EXPECT().ImportBooks(gomock.Any(), "csv", gomock.Any(), true).
+					Return(models.ImportReport{DryRun: true, Total: 1, Imported: 1}, nil)
+			},
+		},
+		{
+			name:           "invalid rows",
+			target:         "/books/import",
+			contentType:    "text/csv",
+			expectedStatus: http.StatusUnprocessableEntity,
+			mockSetup: func() {
+				mockBookService.EXPECT().ImportBooks(gomock.Any(), "text/csv", gomock.Any(), false).
+					Return(models.ImportReport{Total: 1, Invalid: 1}, nil)
+			},
+		},
+		{
+			name:           "invalid dry run",
+			target:         "/books/import?dry_run=maybe",
+			contentType:    "text/csv",
+			expectedStatus: http.StatusBadRequest,
+			mockSetup:      func() {},
+		},
+		{
+			name:           "service error",
+			target:         "/books/import",
+			contentType:    "text/csv",
+			expectedStatus: http.StatusForbidden,
+			mockSetup: func() {
+				mockBookService.EXPECT().ImportBooks(gomock.Any(), "text/csv", gomock.Any(), false).
+					Return(models.ImportReport{}, apperrors.ErrUnauthorisedUser)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := &BookHandler{
+				bookService: mockBookService,
+			}
+			tt.mockSetup()
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodPost, tt.target, strings.NewReader(file))
+			r.Header.Set("Content-Type", tt.contentType)
+			handler.ImportBooks(context.Background(), w, r)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("ImportBooks() status = %v, want %v", w.Code, tt.expectedStatus)
+			}
+		})
+	}
+}
+
+func TestBookHandler_ExportBook(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockBookService := mocks.NewMockBookManager(ctrl)
+
+	bookId := "550e8400-e29b-41d4-a716-446655440000"
+	record := marc.FromBook(models.Book{Title: "dune", Author: "frank herbert"})
+
+	tests := []struct {
+		name            string
+		target          string
+		expectedStatus  int
+		expectedType    string
+		expectedContent string
+		mockSetup       func()
+	}{
+		{
+			name:            "marcxml by default",
+			target:          "/books/" + bookId + "/marc",
+			expectedStatus:  http.StatusOK,
+			expectedType:    "application/marcxml+xml",
+			expectedContent: `<subfield code="a">dune</subfield>`,
+			mockSetup: func() {
+				mockBookService.EXPECT().ExportBook(gomock.Any(), bookId).Return(record, nil)
+			},
+		},
+		{
+			name:            "iso 2709",
+			target:          "/books/" + bookId + "/marc?format=marc",
+			expectedStatus:  http.StatusOK,
+			expectedType:    "application/marc",
+			expectedContent: "\x1fadune\x1e",
+			mockSetup: func() {
+				mockBookService.EXPECT().ExportBook(gomock.Any(), bookId).Return(record, nil)
+			},
+		},
+		{
+			name:           "unknown format",
+			target:         "/books/" + bookId + "/marc?format=pdf",
+			expectedStatus: http.StatusBadRequest,
+			mockSetup:      func() {},
+		},
+		{
+			name:           "book not found",
+			target:         "/books/" + bookId + "/marc",
+			expectedStatus: http.StatusNotFound,
+			mockSetup: func() {
+				mockBookService.EXPECT().ExportBook(gomock.Any(), bookId).Return(marc.Record{}, apperrors.NotFound("book_not_found", "book not found"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := &BookHandler{
+				bookService: mockBookService,
+			}
+			tt.mockSetup()
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, tt.target, nil)
+			r.SetPathValue("bookId", bookId)
+			handler.ExportBook(context.Background(), w, r)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("ExportBook() status = %v, want %v", w.Code, tt.expectedStatus)
+			}
+			if tt.expectedType != "" && w.Header().Get("Content-Type") != tt.expectedType {
+				t.Errorf("ExportBook() content type = %v, want %v", w.Header().Get("Content-Type"), tt.expectedType)
+			}
+			if !strings.Contains(w.Body.String(), tt.expectedContent) {
+				t.Errorf("ExportBook() body = %q, want it to contain %q", w.Body.String(), tt.expectedContent)
+			}
+		})
+	}
+}
+
+func TestBookHandler_ExportCatalog(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockBookService := mocks.NewMockBookManager(ctrl)
+
+	record := marc.FromBook(models.Book{Title: "dune", Author: "frank herbert"})
+
+	tests := []struct {
+		name           string
+		expectedStatus int
+		expectedType   string
+		mockSetup      func()
+	}{
+		{
+			name:           "dump",
+			expectedStatus: http.StatusOK,
+			expectedType:   "application/marcxml+xml",
+			mockSetup: func() {
+				mockBookService.EXPECT().ExportCatalog(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, enc marc.Encoder) error {
+					return enc.Encode(record)
+				})
+			},
+		},
+		{
+			name:           "not allowed",
+			expectedStatus: http.StatusForbidden,
+			expectedType:   "application/json",
+			mockSetup: func() {
+				mockBookService.EXPECT().ExportCatalog(gomock.Any(), gomock.Any()).Return(apperrors.ErrUnauthorisedUser)
+			},
+		},
+		{
+			name:           "failure part way through",
+			expectedStatus: http.StatusOK,
+			expectedType:   "application/marcxml+xml",
+			mockSetup: func() {
+				mockBookService.EXPECT().ExportCatalog(gomock.Any(), gomock.Any()).DoAndReturn(func(_ context.Context, enc marc.Encoder) error {
+					enc.Encode(record)
+					return errors.New("db error")
+				})
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := &BookHandler{
+				bookService: mockBookService,
+			}
+			tt.mockSetup()
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, "/books/export", nil)
+			handler.ExportCatalog(context.Background(), w, r)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("ExportCatalog() status = %v, want %v", w.Code, tt.expectedStatus)
+			}
+			if got := w.Header().Get("Content-Type"); got != tt.expectedType {
+				t.Errorf("ExportCatalog() content type = %v, want %v", got, tt.expectedType)
+			}
+		})
+	}
+}
+
+func TestBookHandler_GetCopies(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockBookService := mocks.NewMockBookManager(ctrl)
+
+	bookId := "550e8400-e29b-41d4-a716-446655440000"
+
+	tests := []struct {
+		name           string
+		expectedStatus int
+		mockSetup      func()
+	}{
+		{
+			name:           "valid get copies",
+			expectedStatus: http.StatusOK,
+			mockSetup: func() {
+				mockBookService.EXPECT().GetCopies(gomock.Any(), bookId).Return([]models.CopyDTO{
+					{
+						ID:      "8f14e45f-ceea-467f-a8f5-6a3b0a3c1f2e",
+						BookID:  bookId,
+						Barcode: "ABC123",
+						Status:  "available",
+					},
+				}, nil)
+			},
+		},
+		{
+			name:           "service error",
+			expectedStatus: http.StatusInternalServerError,
+			mockSetup: func() {
+				mockBookService.EXPECT().GetCopies(gomock.Any(), bookId).Return(nil, errors.New("service error"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := &BookHandler{
+				bookService: mockBookService,
+			}
+			tt.mockSetup()
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, "/books/"+bookId+"/copies", nil)
+			r.SetPathValue("bookId", bookId)
+			handler.GetCopies(context.Background(), w, r)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("GetCopies() status = %v, want %v", w.Code, tt.expectedStatus)
+			}
+		})
+	}
+}
+
+func TestBookHandler_AddCopies(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockBookService := mocks.NewMockBookManager(ctrl)
+
+	bookId := "550e8400-e29b-41d4-a716-446655440000"
+
+	tests := []struct {
+		name           string
+		body           io.Reader
+		expectedStatus int
+		mockSetup      func()
+	}{
+		{
+			name:           "valid add copies",
+			body:           anyToReader(models.AddCopiesDTO{Copies: 2}),
+			expectedStatus: http.StatusCreated,
+			mockSetup: func() {
+				mockBookService.EXPECT().AddCopies(gomock.Any(), bookId, models.AddCopiesDTO{Copies: 2}).Return(nil)
+			},
+		},
+		{
+			name:           "invalid json",
+			body:           bytes.NewReader([]byte("invalid json")),
+			expectedStatus: http.StatusBadRequest,
+			mockSetup:      func() {},
+		},
+		{
+			name:           "service error",
+			body:           anyToReader(models.AddCopiesDTO{Copies: 2}),
+			expectedStatus: http.StatusInternalServerError,
+			mockSetup: func() {
+				mockBookService.EXPECT().AddCopies(gomock.Any(), bookId, gomock.Any()).Return(errors.New("service error"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := &BookHandler{
+				bookService: mockBookService,
+			}
+			tt.mockSetup()
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodPost, "/books/"+bookId+"/copies", tt.body)
+			r.SetPathValue("bookId", bookId)
+			handler.AddCopies(context.Background(), w, r)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("AddCopies() status = %v, want %v", w.Code, tt.expectedStatus)
+			}
+		})
+	}
+}
+
+func TestBookHandler_GetBook(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockBookService := mocks.NewMockBookManager(ctrl)
+
+	bookId := "550e8400-e29b-41d4-a716-446655440000"
+
+	tests := []struct {
+		name           string
+		expectedStatus int
+		mockSetup      func()
+	}{
+		{
+			name:           "valid get book",
+			expectedStatus: http.StatusOK,
+			mockSetup: func() {
+				mockBookService.EXPECT().GetBook(gomock.Any(), bookId).Return(models.BookDTO{ID: bookId, Title: "Dune"}, nil)
+			},
+		},
+		{
+			name:           "service error",
+			expectedStatus: http.StatusInternalServerError,
+			mockSetup: func() {
+				mockBookService.EXPECT().GetBook(gomock.Any(), bookId).Return(models.BookDTO{}, errors.New("book not found"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := &BookHandler{
+				bookService: mockBookService,
+			}
+			tt.mockSetup()
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, "/books/"+bookId, nil)
+			r.SetPathValue("bookId", bookId)
+			handler.GetBook(context.Background(), w, r)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("GetBook() status = %v, want %v", w.Code, tt.expectedStatus)
+			}
+		})
+	}
+}
+
+func TestBookHandler_UpdateBook(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockBookService := mocks.NewMockBookManager(ctrl)
+
+	bookId := "550e8400-e29b-41d4-a716-446655440000"
+
+	tests := []struct {
+		name           string
+		body           io.Reader
+		expectedStatus int
+		mockSetup      func()
+	}{
+		{
+			name:           "valid update",
+			body:           bytes.NewReader([]byte(`{"title":"Dune"}`)),
+			expectedStatus: http.StatusOK,
+			mockSetup: func() {
+				mockBookService.EXPECT().UpdateBook(gomock.Any(), bookId, gomock.Any()).Return(models.BookDTO{ID: bookId, Title: "Dune"}, nil)
+			},
+		},
+		{
+			name:           "invalid json",
+			body:           bytes.NewReader([]byte("invalid json")),
+			expectedStatus: http.StatusBadRequest,
+			mockSetup:      func() {},
+		},
+		{
+			name:           "service error",
+			body:           bytes.NewReader([]byte(`{"title":"Dune"}`)),
+			expectedStatus: http.StatusInternalServerError,
+			mockSetup: func() {
+				mockBookService.EXPECT().UpdateBook(gomock.Any(), bookId, gomock.Any()).Return(models.BookDTO{}, errors.New("unauthorised user"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := &BookHandler{
+				bookService: mockBookService,
+			}
+			tt.mockSetup()
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodPatch, "/books/"+bookId, tt.body)
+			r.SetPathValue("bookId", bookId)
+			handler.UpdateBook(context.Background(), w, r)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("UpdateBook() status = %v, want %v", w.Code, tt.expectedStatus)
+			}
+		})
+	}
+}
+
+func TestBookHandler_WithdrawBook(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockBookService := mocks.NewMockBookManager(ctrl)
+
+	bookId := "550e8400-e29b-41d4-a716-446655440000"
+
+	tests := []struct {
+		name           string
+		body           io.Reader
+		expectedStatus int
+		mockSetup      func()
+	}{
+		{
+			name:           "valid withdraw",
+			body:           anyToReader(models.WithdrawDTO{Reason: "duplicate"}),
+			expectedStatus: http.StatusOK,
+			mockSetup: func() {
+				mockBookService.EXPECT().WithdrawBook(gomock.Any(), bookId, "duplicate").Return(nil)
+			},
+		},
+		{
+			name:           "invalid json",
+			body:           bytes.NewReader([]byte("invalid json")),
+			expectedStatus: http.StatusBadRequest,
+			mockSetup:      func() {},
+		},
+		{
+			name:           "service error",
+			body:           anyToReader(models.WithdrawDTO{Reason: "duplicate"}),
+			expectedStatus: http.StatusInternalServerError,
+			mockSetup: func() {
+				mockBookService.EXPECT().WithdrawBook(gomock.Any(), bookId, "duplicate").Return(errors.New("book has copies issued and cannot be withdrawn"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := &BookHandler{
+				bookService: mockBookService,
+			}
+			tt.mockSetup()
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodDelete, "/books/"+bookId, tt.body)
+			r.SetPathValue("bookId", bookId)
+			handler.WithdrawBook(context.Background(), w, r)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("WithdrawBook() status = %v, want %v", w.Code, tt.expectedStatus)
+			}
+		})
+	}
+}
+
+func TestBookHandler_WithdrawCopy(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockBookService := mocks.NewMockBookManager(ctrl)
+
+	bookId := "550e8400-e29b-41d4-a716-446655440000"
+	copyId := "8f14e45f-ceea-467f-a8f5-6a3b0a3c1f2e"
+
+	tests := []struct {
+		name           string
+		expectedStatus int
+		mockSetup      func()
+	}{
+		{
+			name:           "valid withdraw",
+			expectedStatus: http.StatusOK,
+			mockSetup: func() {
+				mockBookService.EXPECT().WithdrawCopy(gomock.Any(), bookId, copyId, "damaged").Return(nil)
+			},
+		},
+		{
+			name:           "copy issued",
+			expectedStatus: http.StatusInternalServerError,
+			mockSetup: func() {
+				mockBookService.EXPECT().WithdrawCopy(gomock.Any(), bookId, copyId, "damaged").Return(errors.New("copy is issued and cannot be withdrawn"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := &BookHandler{
+				bookService: mockBookService,
+			}
+			tt.mockSetup()
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodDelete, "/books/"+bookId+"/copies/"+copyId, anyToReader(models.WithdrawDTO{Reason: "damaged"}))
+			r.SetPathValue("bookId", bookId)
+			r.SetPathValue("copyId", copyId)
+			handler.WithdrawCopy(context.Background(), w, r)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("WithdrawCopy() status = %v, want %v", w.Code, tt.expectedStatus)
 			}
 		})
 	}
@@ -0,0 +1,72 @@
+package finehandler
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"github.com/Kaushik1766/LibraryManagement/internal/models"
+	fineservice "github.com/Kaushik1766/LibraryManagement/internal/service/fine_service"
+	weberrors "github.com/Kaushik1766/LibraryManagement/internal/web_errors"
+)
+
+type FineHandler struct {
+	fineService fineservice.FineManager
+}
+
+func NewFineHandler(fineService fineservice.FineManager) *FineHandler {
+	return &FineHandler{
+		fineService: fineService,
+	}
+}
+
+func (handler *FineHandler) GetFines(ctx context.Context, w http.ResponseWriter, r *http.Request) {
+
+	balance, err := handler.fineService.GetFines(ctx, r.URL.Query().Get("userId"))
+	if err != nil {
+		weberrors.SendError(err, http.StatusInternalServerError, w)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(balance)
+}
+
+func (handler *FineHandler) RecordPayment(ctx context.Context, w http.ResponseWriter, r *http.Request) {
+	var req models.FinePaymentDTO
+
+	data, _ := io.ReadAll(r.Body)
+	err := json.Unmarshal(data, &req)
+	if err != nil {
+		weberrors.SendError(err, http.StatusBadRequest, w)
+		return
+	}
+
+	err = handler.fineService.RecordPayment(ctx, r.PathValue("fineId"), req.Amount)
+	if err != nil {
+		weberrors.SendError(err, http.StatusInternalServerError, w)
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+}
+
+func (handler *FineHandler) WaiveFine(ctx context.Context, w http.ResponseWriter, r *http.Request) {
+	var req models.FinePaymentDTO
+
+	data, _ := io.ReadAll(r.Body)
+	err := json.Unmarshal(data, &req)
+	if err != nil {
+		weberrors.SendError(err, http.StatusBadRequest, w)
+		return
+	}
+
+	err = handler.fineService.WaiveFine(ctx, r.PathValue("fineId"), req.Amount, req.Reason)
+	if err != nil {
+		weberrors.SendError(err, http.StatusInternalServerError, w)
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+}
@@ -0,0 +1,209 @@
+package finehandler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/Kaushik1766/LibraryManagement/internal/models"
+	fineservice "github.com/Kaushik1766/LibraryManagement/internal/service/fine_service"
+	"github.com/Kaushik1766/LibraryManagement/mocks"
+	"go.uber.org/mock/gomock"
+)
+
+func anyToReader(data any) io.Reader {
+	dataJsonBytes, _ := json.Marshal(data)
+	return bytes.NewReader(dataJsonBytes)
+}
+
+func TestNewFineHandler(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockFineService := mocks.NewMockFineManager(ctrl)
+
+	type args struct {
+		fineService fineservice.FineManager
+	}
+	tests := []struct {
+		name string
+		args args
+		want *FineHandler
+	}{
+		{
+			name: "valid",
+			args: args{
+				fineService: mockFineService,
+			},
+			want: &FineHandler{
+				fineService: mockFineService,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NewFineHandler(tt.args.fineService); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("NewFineHandler() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFineHandler_GetFines(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockFineService := mocks.NewMockFineManager(ctrl)
+
+	tests := []struct {
+		name           string
+		r              *http.Request
+		expectedStatus int
+		mockSetup      func()
+	}{
+		{
+			name:           "valid get fines",
+			r:              httptest.NewRequest(http.MethodGet, "/fines?userId=550e8400-e29b-41d4-a716-446655440000", nil),
+			expectedStatus: http.StatusOK,
+			mockSetup: func() {
+				mockFineService.EXPECT().GetFines(gomock.Any(), "550e8400-e29b-41d4-a716-446655440000").Return(models.FineBalanceDTO{Outstanding: 100}, nil)
+			},
+		},
+		{
+			name:           "service error",
+			r:              httptest.NewRequest(http.MethodGet, "/fines", nil),
+			expectedStatus: http.StatusInternalServerError,
+			mockSetup: func() {
+				mockFineService.EXPECT().GetFines(gomock.Any(), "").Return(models.FineBalanceDTO{}, errors.New("service error"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := &FineHandler{
+				fineService: mockFineService,
+			}
+			tt.mockSetup()
+			recorder := httptest.NewRecorder()
+			handler.GetFines(context.Background(), recorder, tt.r)
+
+			if recorder.Code != tt.expectedStatus {
+				t.Errorf("GetFines() status = %v, want %v", recorder.Code, tt.expectedStatus)
+			}
+		})
+	}
+}
+
+func TestFineHandler_RecordPayment(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockFineService := mocks.NewMockFineManager(ctrl)
+
+	tests := []struct {
+		name           string
+		body           io.Reader
+		expectedStatus int
+		mockSetup      func()
+	}{
+		{
+			name:           "valid payment",
+			body:           anyToReader(map[string]int{"amount": 100}),
+			expectedStatus: http.StatusCreated,
+			mockSetup: func() {
+				mockFineService.EXPECT().RecordPayment(gomock.Any(), "550e8400-e29b-41d4-a716-446655440001", 100).Return(nil)
+			},
+		},
+		{
+			name:           "invalid json",
+			body:           bytes.NewReader([]byte("invalid json")),
+			expectedStatus: http.StatusBadRequest,
+			mockSetup: func() {
+			},
+		},
+		{
+			name:           "service error",
+			body:           anyToReader(map[string]int{"amount": 100}),
+			expectedStatus: http.StatusInternalServerError,
+			mockSetup: func() {
+				mockFineService.EXPECT().RecordPayment(gomock.Any(), gomock.Any(), 100).Return(errors.New("service error"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := &FineHandler{
+				fineService: mockFineService,
+			}
+			tt.mockSetup()
+			r := httptest.NewRequest(http.MethodPost, "/fines/550e8400-e29b-41d4-a716-446655440001/payments", tt.body)
+			r.SetPathValue("fineId", "550e8400-e29b-41d4-a716-446655440001")
+			recorder := httptest.NewRecorder()
+			handler.RecordPayment(context.Background(), recorder, r)
+
+			if recorder.Code != tt.expectedStatus {
+				t.Errorf("RecordPayment() status = %v, want %v", recorder.Code, tt.expectedStatus)
+			}
+		})
+	}
+}
+
+func TestFineHandler_WaiveFine(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockFineService := mocks.NewMockFineManager(ctrl)
+
+	tests := []struct {
+		name           string
+		body           io.Reader
+		expectedStatus int
+		mockSetup      func()
+	}{
+		{
+			name:           "valid waive",
+			body:           anyToReader(map[string]any{"amount": 50, "reason": "first offence"}),
+			expectedStatus: http.StatusCreated,
+			mockSetup: func() {
+				mockFineService.EXPECT().WaiveFine(gomock.Any(), "550e8400-e29b-41d4-a716-446655440001", 50, "first offence").Return(nil)
+			},
+		},
+		{
+			name:           "invalid json",
+			body:           bytes.NewReader([]byte("invalid json")),
+			expectedStatus: http.StatusBadRequest,
+			mockSetup: func() {
+			},
+		},
+		{
+			name:           "service error",
+			body:           anyToReader(map[string]any{"reason": "first offence"}),
+			expectedStatus: http.StatusInternalServerError,
+			mockSetup: func() {
+				mockFineService.EXPECT().WaiveFine(gomock.Any(), gomock.Any(), 0, "first offence").Return(errors.New("service error"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := &FineHandler{
+				fineService: mockFineService,
+			}
+			tt.mockSetup()
+			r := httptest.NewRequest(http.MethodPost, "/fines/550e8400-e29b-41d4-a716-446655440001/waive", tt.body)
+			r.SetPathValue("fineId", "550e8400-e29b-41d4-a716-446655440001")
+			recorder := httptest.NewRecorder()
+			handler.WaiveFine(context.Background(), recorder, r)
+
+			if recorder.Code != tt.expectedStatus {
+				t.Errorf("WaiveFine() status = %v, want %v", recorder.Code, tt.expectedStatus)
+			}
+		})
+	}
+}
@@ -0,0 +1,69 @@
+package holdhandler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	holdservice "github.com/Kaushik1766/LibraryManagement/internal/service/hold_service"
+	weberrors "github.com/Kaushik1766/LibraryManagement/internal/web_errors"
+)
+
+type HoldHandler struct {
+	holdService holdservice.HoldManager
+}
+
+func NewHoldHandler(holdService holdservice.HoldManager) *HoldHandler {
+	return &HoldHandler{
+		holdService: holdService,
+	}
+}
+
+func (handler *HoldHandler) PlaceHold(ctx context.Context, w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		BookId string `json:"book_id"`
+	}
+
+	data, _ := io.ReadAll(r.Body)
+	err := json.Unmarshal(data, &req)
+	if err != nil {
+		weberrors.SendError(err, http.StatusBadRequest, w)
+		return
+	}
+
+	holdId, err := handler.holdService.PlaceHold(ctx, req.BookId)
+	if err != nil {
+		weberrors.SendError(err, http.StatusInternalServerError, w)
+		return
+	}
+
+	w.WriteHeader(http.StatusCreated)
+	w.Write([]byte(fmt.Sprintf(`{"hold_id":"%s"}`, holdId)))
+}
+
+func (handler *HoldHandler) GetHolds(ctx context.Context, w http.ResponseWriter, r *http.Request) {
+
+	holds, err := handler.holdService.GetHolds(ctx)
+	if err != nil {
+		weberrors.SendError(err, http.StatusInternalServerError, w)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(holds)
+}
+
+func (handler *HoldHandler) CancelHold(ctx context.Context, w http.ResponseWriter, r *http.Request) {
+
+	holdId := r.PathValue("holdId")
+
+	err := handler.holdService.CancelHold(ctx, holdId)
+	if err != nil {
+		weberrors.SendError(err, http.StatusInternalServerError, w)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
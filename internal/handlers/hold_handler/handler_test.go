@@ -0,0 +1,207 @@
+package holdhandler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/Kaushik1766/LibraryManagement/internal/models"
+	holdservice "github.com/Kaushik1766/LibraryManagement/internal/service/hold_service"
+	"github.com/Kaushik1766/LibraryManagement/mocks"
+	"go.uber.org/mock/gomock"
+)
+
+func anyToReader(data any) io.Reader {
+	dataJsonBytes, _ := json.Marshal(data)
+	return bytes.NewReader(dataJsonBytes)
+}
+
+func TestNewHoldHandler(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockHoldService := mocks.NewMockHoldManager(ctrl)
+
+	type args struct {
+		holdService holdservice.HoldManager
+	}
+	tests := []struct {
+		name string
+		args args
+		want *HoldHandler
+	}{
+		{
+			name: "valid",
+			args: args{
+				holdService: mockHoldService,
+			},
+			want: &HoldHandler{
+				holdService: mockHoldService,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NewHoldHandler(tt.args.holdService); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("NewHoldHandler() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHoldHandler_PlaceHold(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockHoldService := mocks.NewMockHoldManager(ctrl)
+
+	tests := []struct {
+		name           string
+		r              *http.Request
+		expectedStatus int
+		mockSetup      func()
+	}{
+		{
+			name: "valid place hold",
+			r: httptest.NewRequest(http.MethodPost, "/holds", anyToReader(map[string]string{
+				"book_id": "550e8400-e29b-41d4-a716-446655440000",
+			})),
+			expectedStatus: http.StatusCreated,
+			mockSetup: func() {
+				mockHoldService.EXPECT().PlaceHold(gomock.Any(), "550e8400-e29b-41d4-a716-446655440000").Return("550e8400-e29b-41d4-a716-446655440001", nil)
+			},
+		},
+		{
+			name:           "invalid json",
+			r:              httptest.NewRequest(http.MethodPost, "/holds", bytes.NewReader([]byte("invalid json"))),
+			expectedStatus: http.StatusBadRequest,
+			mockSetup: func() {
+			},
+		},
+		{
+			name: "service error",
+			r: httptest.NewRequest(http.MethodPost, "/holds", anyToReader(map[string]string{
+				"book_id": "550e8400-e29b-41d4-a716-446655440000",
+			})),
+			expectedStatus: http.StatusInternalServerError,
+			mockSetup: func() {
+				mockHoldService.EXPECT().PlaceHold(gomock.Any(), gomock.Any()).Return("", errors.New("hold not allowed"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := &HoldHandler{
+				holdService: mockHoldService,
+			}
+			tt.mockSetup()
+			recorder := httptest.NewRecorder()
+			handler.PlaceHold(context.Background(), recorder, tt.r)
+
+			if recorder.Code != tt.expectedStatus {
+				t.Errorf("PlaceHold() status = %v, want %v", recorder.Code, tt.expectedStatus)
+			}
+		})
+	}
+}
+
+func TestHoldHandler_GetHolds(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockHoldService := mocks.NewMockHoldManager(ctrl)
+
+	tests := []struct {
+		name           string
+		expectedStatus int
+		mockSetup      func()
+	}{
+		{
+			name:           "valid get holds",
+			expectedStatus: http.StatusOK,
+			mockSetup: func() {
+				mockHoldService.EXPECT().GetHolds(gomock.Any()).Return([]models.HoldDTO{
+					{
+						ID:            "550e8400-e29b-41d4-a716-446655440001",
+						BookID:        "550e8400-e29b-41d4-a716-446655440000",
+						BookName:      "Harry Potter",
+						Status:        "waiting",
+						QueuePosition: 1,
+						PlacedAt:      "2025-09-04 03:00:43 +0530 IST",
+					},
+				}, nil)
+			},
+		},
+		{
+			name:           "service error",
+			expectedStatus: http.StatusInternalServerError,
+			mockSetup: func() {
+				mockHoldService.EXPECT().GetHolds(gomock.Any()).Return(nil, errors.New("service error"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := &HoldHandler{
+				holdService: mockHoldService,
+			}
+			tt.mockSetup()
+			recorder := httptest.NewRecorder()
+			handler.GetHolds(context.Background(), recorder, httptest.NewRequest(http.MethodGet, "/holds", nil))
+
+			if recorder.Code != tt.expectedStatus {
+				t.Errorf("GetHolds() status = %v, want %v", recorder.Code, tt.expectedStatus)
+			}
+		})
+	}
+}
+
+func TestHoldHandler_CancelHold(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockHoldService := mocks.NewMockHoldManager(ctrl)
+
+	tests := []struct {
+		name           string
+		expectedStatus int
+		mockSetup      func()
+	}{
+		{
+			name:           "valid cancel hold",
+			expectedStatus: http.StatusOK,
+			mockSetup: func() {
+				mockHoldService.EXPECT().CancelHold(gomock.Any(), "550e8400-e29b-41d4-a716-446655440001").Return(nil)
+			},
+		},
+		{
+			name:           "service error",
+			expectedStatus: http.StatusInternalServerError,
+			mockSetup: func() {
+				mockHoldService.EXPECT().CancelHold(gomock.Any(), "550e8400-e29b-41d4-a716-446655440001").Return(errors.New("no active hold found"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := &HoldHandler{
+				holdService: mockHoldService,
+			}
+			tt.mockSetup()
+			r := httptest.NewRequest(http.MethodDelete, "/holds/550e8400-e29b-41d4-a716-446655440001", nil)
+			r.SetPathValue("holdId", "550e8400-e29b-41d4-a716-446655440001")
+			recorder := httptest.NewRecorder()
+			handler.CancelHold(context.Background(), recorder, r)
+
+			if recorder.Code != tt.expectedStatus {
+				t.Errorf("CancelHold() status = %v, want %v", recorder.Code, tt.expectedStatus)
+			}
+		})
+	}
+}
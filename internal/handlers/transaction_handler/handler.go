@@ -7,7 +7,9 @@ import (
 	"io"
 	"net/http"
 
+	apperrors "github.com/Kaushik1766/LibraryManagement/internal/app_errors"
 	"github.com/Kaushik1766/LibraryManagement/internal/models"
+	"github.com/Kaushik1766/LibraryManagement/internal/pagination"
 	transactionservice "github.com/Kaushik1766/LibraryManagement/internal/service/transaction_service"
 	weberrors "github.com/Kaushik1766/LibraryManagement/internal/web_errors"
 )
@@ -66,35 +68,63 @@ func (handler *TransactionHandler) ReturnBook(ctx context.Context, w http.Respon
 	w.WriteHeader(http.StatusOK)
 }
 
+func (handler *TransactionHandler) RenewBook(ctx context.Context, w http.ResponseWriter, r *http.Request) {
+
+	transactionId := r.PathValue("transactionId")
+
+	renewal, err := handler.transactionService.RenewBook(ctx, transactionId)
+	if err != nil {
+		weberrors.SendError(err, http.StatusInternalServerError, w)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(renewal)
+}
+
 func (handler *TransactionHandler) GetAllTransactions(ctx context.Context, w http.ResponseWriter, r *http.Request) {
 
 	query := r.URL.Query()
 
+	page, err := pagination.FromQuery(query)
+	if err != nil {
+		weberrors.SendError(err, http.StatusBadRequest, w)
+		return
+	}
+
 	transactions, err := handler.transactionService.GetTransactions(ctx, models.GetTransactionRequestDTO{
 		UserId:    "",
 		StartTime: query.Get("startTime"),
 		EndTime:   query.Get("endTime"),
 		Returned:  query.Get("returned"),
 		BookName:  query.Get("title"),
-	})
+	}, page)
 
 	if err != nil {
 		weberrors.SendError(err, http.StatusInternalServerError, w)
 		return
 	}
 
+	pagination.SetLink(w, r, transactions.NextCursor)
 	w.WriteHeader(http.StatusOK)
 	json.NewEncoder(w).Encode(transactions)
 }
 
 func (handler *TransactionHandler) GetOverdueTransactions(ctx context.Context, w http.ResponseWriter, r *http.Request) {
 
-	overdueTransactions, err := handler.transactionService.GetOverdueTransactions(ctx)
+	page, err := pagination.FromQuery(r.URL.Query())
+	if err != nil {
+		weberrors.SendError(err, http.StatusBadRequest, w)
+		return
+	}
+
+	overdueTransactions, err := handler.transactionService.GetOverdueTransactions(ctx, page)
 	if err != nil {
 		weberrors.SendError(err, http.StatusInternalServerError, w)
 		return
 	}
 
+	pagination.SetLink(w, r, overdueTransactions.NextCursor)
 	w.WriteHeader(http.StatusOK)
 	json.NewEncoder(w).Encode(overdueTransactions)
 }
@@ -105,13 +135,56 @@ func (handler *TransactionHandler) GetTransactionById(ctx context.Context, w htt
 
 	transactions, err := handler.transactionService.GetTransactions(ctx, models.GetTransactionRequestDTO{
 		TransactionId: transactionId,
-	})
+	}, pagination.Request{})
 
+	if err != nil {
+		weberrors.SendError(err, http.StatusInternalServerError, w)
+		return
+	}
+	if len(transactions.Items) == 0 {
+		weberrors.SendError(apperrors.NotFound("transaction_not_found", "transaction not found"), http.StatusNotFound, w)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(transactions.Items)
+}
+
+func (handler *TransactionHandler) DeskIssue(ctx context.Context, w http.ResponseWriter, r *http.Request) {
+	var req models.DeskIssueDTO
+
+	data, _ := io.ReadAll(r.Body)
+	err := json.Unmarshal(data, &req)
+	if err != nil {
+		weberrors.SendError(err, http.StatusBadRequest, w)
+		return
+	}
+
+	transactionId, err := handler.transactionService.DeskIssue(ctx, req)
+	if err != nil {
+		weberrors.SendError(err, http.StatusInternalServerError, w)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte(fmt.Sprintf(`{"transaction_id":"%s"}`, transactionId)))
+}
+
+func (handler *TransactionHandler) DeskReturn(ctx context.Context, w http.ResponseWriter, r *http.Request) {
+	var req models.DeskReturnDTO
+
+	data, _ := io.ReadAll(r.Body)
+	err := json.Unmarshal(data, &req)
+	if err != nil {
+		weberrors.SendError(err, http.StatusBadRequest, w)
+		return
+	}
+
+	err = handler.transactionService.DeskReturn(ctx, req)
 	if err != nil {
 		weberrors.SendError(err, http.StatusInternalServerError, w)
 		return
 	}
 
 	w.WriteHeader(http.StatusOK)
-	json.NewEncoder(w).Encode(transactions)
 }
@@ -12,6 +12,7 @@ import (
 	"testing"
 
 	"github.com/Kaushik1766/LibraryManagement/internal/models"
+	"github.com/Kaushik1766/LibraryManagement/internal/pagination"
 	transactionservice "github.com/Kaushik1766/LibraryManagement/internal/service/transaction_service"
 	"github.com/Kaushik1766/LibraryManagement/mocks"
 	"go.uber.org/mock/gomock"
@@ -254,7 +255,7 @@ func TestTransactionHandler_GetAllTransactions(t *testing.T) {
 			},
 			expectedStatus: http.StatusOK,
 			mockSetup: func() {
-				mockTransactionService.EXPECT().GetTransactions(gomock.Any(), gomock.Any()).Return([]models.TransactionDTO{
+				mockTransactionService.EXPECT().GetTransactions(gomock.Any(), gomock.Any(), gomock.Any()).Return(pagination.Page[models.TransactionDTO]{Items: []models.TransactionDTO{
 					{
 						ID:         "550e8400-e29b-41d4-a716-446655440001",
 						BookID:     "550e8400-e29b-41d4-a716-446655440000",
@@ -264,7 +265,7 @@ func TestTransactionHandler_GetAllTransactions(t *testing.T) {
 						IssuedTill: "2025-09-11 03:00:43 +0530 IST",
 						ReturnedAt: "",
 					},
-				}, nil)
+				}}, nil)
 			},
 		},
 		{
@@ -278,7 +279,7 @@ func TestTransactionHandler_GetAllTransactions(t *testing.T) {
 			},
 			expectedStatus: http.StatusOK,
 			mockSetup: func() {
-				mockTransactionService.EXPECT().GetTransactions(gomock.Any(), gomock.Any()).Return([]models.TransactionDTO{
+				mockTransactionService.EXPECT().GetTransactions(gomock.Any(), gomock.Any(), gomock.Any()).Return(pagination.Page[models.TransactionDTO]{Items: []models.TransactionDTO{
 					{
 						ID:         "550e8400-e29b-41d4-a716-446655440001",
 						BookID:     "550e8400-e29b-41d4-a716-446655440000",
@@ -288,7 +289,7 @@ func TestTransactionHandler_GetAllTransactions(t *testing.T) {
 						IssuedTill: "2025-09-11 03:00:43 +0530 IST",
 						ReturnedAt: "",
 					},
-				}, nil)
+				}}, nil)
 			},
 		},
 		{
@@ -302,7 +303,7 @@ func TestTransactionHandler_GetAllTransactions(t *testing.T) {
 			},
 			expectedStatus: http.StatusInternalServerError,
 			mockSetup: func() {
-				mockTransactionService.EXPECT().GetTransactions(gomock.Any(), gomock.Any()).Return(nil, errors.New("service error"))
+				mockTransactionService.EXPECT().GetTransactions(gomock.Any(), gomock.Any(), gomock.Any()).Return(pagination.Page[models.TransactionDTO]{}, errors.New("service error"))
 			},
 		},
 	}
@@ -354,7 +355,7 @@ func TestTransactionHandler_GetOverdueTransactions(t *testing.T) {
 			},
 			expectedStatus: http.StatusOK,
 			mockSetup: func() {
-				mockTransactionService.EXPECT().GetOverdueTransactions(gomock.Any()).Return([]models.OverdueTransactionDTO{
+				mockTransactionService.EXPECT().GetOverdueTransactions(gomock.Any(), gomock.Any()).Return(pagination.Page[models.OverdueTransactionDTO]{Items: []models.OverdueTransactionDTO{
 					{
 						ID:         "550e8400-e29b-41d4-a716-446655440001",
 						BookID:     "550e8400-e29b-41d4-a716-446655440000",
@@ -363,9 +364,21 @@ func TestTransactionHandler_GetOverdueTransactions(t *testing.T) {
 						IssuedTill: "2025-09-04 03:00:43 +0530 IST",
 						ReturnedAt: "not yet returned",
 					},
-				}, nil)
+				}}, nil)
 			},
 		},
+		{
+			name: "invalid limit",
+			fields: fields{
+				transactionService: mockTransactionService,
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: httptest.NewRequest(http.MethodGet, "/transactions/overdue?limit=abc", nil),
+			},
+			expectedStatus: http.StatusBadRequest,
+			mockSetup:      func() {},
+		},
 		{
 			name: "service error",
 			fields: fields{
@@ -377,7 +390,7 @@ func TestTransactionHandler_GetOverdueTransactions(t *testing.T) {
 			},
 			expectedStatus: http.StatusInternalServerError,
 			mockSetup: func() {
-				mockTransactionService.EXPECT().GetOverdueTransactions(gomock.Any()).Return(nil, errors.New("service error"))
+				mockTransactionService.EXPECT().GetOverdueTransactions(gomock.Any(), gomock.Any()).Return(pagination.Page[models.OverdueTransactionDTO]{}, errors.New("service error"))
 			},
 		},
 	}
@@ -433,7 +446,7 @@ func TestTransactionHandler_GetTransactionById(t *testing.T) {
 			},
 			expectedStatus: http.StatusOK,
 			mockSetup: func() {
-				mockTransactionService.EXPECT().GetTransactions(gomock.Any(), gomock.Any()).Return([]models.TransactionDTO{
+				mockTransactionService.EXPECT().GetTransactions(gomock.Any(), gomock.Any(), gomock.Any()).Return(pagination.Page[models.TransactionDTO]{Items: []models.TransactionDTO{
 					{
 						ID:         "550e8400-e29b-41d4-a716-446655440001",
 						BookID:     "550e8400-e29b-41d4-a716-446655440000",
@@ -443,7 +456,25 @@ func TestTransactionHandler_GetTransactionById(t *testing.T) {
 						IssuedTill: "2025-09-11 03:00:43 +0530 IST",
 						ReturnedAt: "",
 					},
-				}, nil)
+				}}, nil)
+			},
+		},
+		{
+			name: "transaction not found",
+			fields: fields{
+				transactionService: mockTransactionService,
+			},
+			args: args{
+				w: httptest.NewRecorder(),
+				r: func() *http.Request {
+					req := httptest.NewRequest(http.MethodGet, "/transactions/550e8400-e29b-41d4-a716-446655440001", nil)
+					req.SetPathValue("transactionId", "550e8400-e29b-41d4-a716-446655440001")
+					return req
+				}(),
+			},
+			expectedStatus: http.StatusNotFound,
+			mockSetup: func() {
+				mockTransactionService.EXPECT().GetTransactions(gomock.Any(), gomock.Any(), gomock.Any()).Return(pagination.Page[models.TransactionDTO]{}, nil)
 			},
 		},
 		{
@@ -461,7 +492,7 @@ func TestTransactionHandler_GetTransactionById(t *testing.T) {
 			},
 			expectedStatus: http.StatusInternalServerError,
 			mockSetup: func() {
-				mockTransactionService.EXPECT().GetTransactions(gomock.Any(), gomock.Any()).Return(nil, errors.New("service error"))
+				mockTransactionService.EXPECT().GetTransactions(gomock.Any(), gomock.Any(), gomock.Any()).Return(pagination.Page[models.TransactionDTO]{}, errors.New("service error"))
 			},
 		},
 	}
@@ -481,3 +512,160 @@ func TestTransactionHandler_GetTransactionById(t *testing.T) {
 		})
 	}
 }
+
+func TestTransactionHandler_RenewBook(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTransactionService := mocks.NewMockTransactionManager(ctrl)
+
+	tests := []struct {
+		name           string
+		expectedStatus int
+		mockSetup      func()
+	}{
+		{
+			name:           "valid renew",
+			expectedStatus: http.StatusOK,
+			mockSetup: func() {
+				mockTransactionService.EXPECT().RenewBook(gomock.Any(), "550e8400-e29b-41d4-a716-446655440001").Return(models.RenewalDTO{
+					TransactionID: "550e8400-e29b-41d4-a716-446655440001",
+					IssuedTill:    "2025-09-11 03:00:43 +0530 IST",
+					RenewalsUsed:  1,
+					RenewalsLeft:  1,
+				}, nil)
+			},
+		},
+		{
+			name:           "service error",
+			expectedStatus: http.StatusInternalServerError,
+			mockSetup: func() {
+				mockTransactionService.EXPECT().RenewBook(gomock.Any(), "550e8400-e29b-41d4-a716-446655440001").Return(models.RenewalDTO{}, errors.New("renewal limit reached"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := &TransactionHandler{
+				transactionService: mockTransactionService,
+			}
+			tt.mockSetup()
+			r := httptest.NewRequest(http.MethodPost, "/transactions/550e8400-e29b-41d4-a716-446655440001/renew", nil)
+			r.SetPathValue("transactionId", "550e8400-e29b-41d4-a716-446655440001")
+			recorder := httptest.NewRecorder()
+			handler.RenewBook(context.Background(), recorder, r)
+
+			if recorder.Code != tt.expectedStatus {
+				t.Errorf("RenewBook() status = %v, want %v", recorder.Code, tt.expectedStatus)
+			}
+		})
+	}
+}
+
+func TestTransactionHandler_DeskIssue(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTransactionService := mocks.NewMockTransactionManager(ctrl)
+
+	req := models.DeskIssueDTO{Patron: "LC00000042", Copy: "LIB-0001", IssueFor: "14 days"}
+
+	tests := []struct {
+		name           string
+		body           io.Reader
+		expectedStatus int
+		mockSetup      func()
+	}{
+		{
+			name:           "valid desk issue",
+			body:           anyToReader(req),
+			expectedStatus: http.StatusOK,
+			mockSetup: func() {
+				mockTransactionService.EXPECT().DeskIssue(gomock.Any(), req).Return("550e8400-e29b-41d4-a716-446655440001", nil)
+			},
+		},
+		{
+			name:           "invalid json",
+			body:           bytes.NewReader([]byte("invalid json")),
+			expectedStatus: http.StatusBadRequest,
+			mockSetup:      func() {},
+		},
+		{
+			name:           "service error",
+			body:           anyToReader(req),
+			expectedStatus: http.StatusInternalServerError,
+			mockSetup: func() {
+				mockTransactionService.EXPECT().DeskIssue(gomock.Any(), req).Return("", errors.New("copy not available"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := &TransactionHandler{
+				transactionService: mockTransactionService,
+			}
+			tt.mockSetup()
+			r := httptest.NewRequest(http.MethodPost, "/desk/issue", tt.body)
+			recorder := httptest.NewRecorder()
+			handler.DeskIssue(context.Background(), recorder, r)
+
+			if recorder.Code != tt.expectedStatus {
+				t.Errorf("DeskIssue() status = %v, want %v", recorder.Code, tt.expectedStatus)
+			}
+		})
+	}
+}
+
+func TestTransactionHandler_DeskReturn(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTransactionService := mocks.NewMockTransactionManager(ctrl)
+
+	req := models.DeskReturnDTO{Copy: "LIB-0001"}
+
+	tests := []struct {
+		name           string
+		body           io.Reader
+		expectedStatus int
+		mockSetup      func()
+	}{
+		{
+			name:           "valid desk return",
+			body:           anyToReader(req),
+			expectedStatus: http.StatusOK,
+			mockSetup: func() {
+				mockTransactionService.EXPECT().DeskReturn(gomock.Any(), req).Return(nil)
+			},
+		},
+		{
+			name:           "invalid json",
+			body:           bytes.NewReader([]byte("invalid json")),
+			expectedStatus: http.StatusBadRequest,
+			mockSetup:      func() {},
+		},
+		{
+			name:           "service error",
+			body:           anyToReader(req),
+			expectedStatus: http.StatusInternalServerError,
+			mockSetup: func() {
+				mockTransactionService.EXPECT().DeskReturn(gomock.Any(), req).Return(errors.New("copy is not on loan"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := &TransactionHandler{
+				transactionService: mockTransactionService,
+			}
+			tt.mockSetup()
+			r := httptest.NewRequest(http.MethodPost, "/desk/return", tt.body)
+			recorder := httptest.NewRecorder()
+			handler.DeskReturn(context.Background(), recorder, r)
+
+			if recorder.Code != tt.expectedStatus {
+				t.Errorf("DeskReturn() status = %v, want %v", recorder.Code, tt.expectedStatus)
+			}
+		})
+	}
+}
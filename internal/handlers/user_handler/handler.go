@@ -0,0 +1,161 @@
+package userhandler
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+
+	"github.com/Kaushik1766/LibraryManagement/internal/models"
+	"github.com/Kaushik1766/LibraryManagement/internal/pagination"
+	userservice "github.com/Kaushik1766/LibraryManagement/internal/service/user_service"
+	weberrors "github.com/Kaushik1766/LibraryManagement/internal/web_errors"
+)
+
+type UserHandler struct {
+	userService userservice.UserManager
+}
+
+func NewUserHandler(userService userservice.UserManager) *UserHandler {
+	return &UserHandler{
+		userService: userService,
+	}
+}
+
+func (handler *UserHandler) GetUsers(ctx context.Context, w http.ResponseWriter, r *http.Request) {
+
+	query := r.URL.Query()
+
+	page, err := pagination.FromQuery(query)
+	if err != nil {
+		weberrors.SendError(err, http.StatusBadRequest, w)
+		return
+	}
+
+	users, err := handler.userService.GetUsers(ctx, query.Get("q"), query.Get("role"), query.Get("status"), page)
+	if err != nil {
+		weberrors.SendError(err, http.StatusInternalServerError, w)
+		return
+	}
+
+	pagination.SetLink(w, r, users.NextCursor)
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(users)
+}
+
+func (handler *UserHandler) GetUser(ctx context.Context, w http.ResponseWriter, r *http.Request) {
+
+	user, err := handler.userService.GetUser(ctx, r.PathValue("userId"))
+	if err != nil {
+		weberrors.SendError(err, http.StatusInternalServerError, w)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(user)
+}
+
+func (handler *UserHandler) UpdateUser(ctx context.Context, w http.ResponseWriter, r *http.Request) {
+	var req models.UpdateUserDTO
+
+	data, _ := io.ReadAll(r.Body)
+	err := json.Unmarshal(data, &req)
+	if err != nil {
+		weberrors.SendError(err, http.StatusBadRequest, w)
+		return
+	}
+
+	user, err := handler.userService.UpdateUser(ctx, r.PathValue("userId"), req)
+	if err != nil {
+		weberrors.SendError(err, http.StatusInternalServerError, w)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(user)
+}
+
+func (handler *UserHandler) ChangeRole(ctx context.Context, w http.ResponseWriter, r *http.Request) {
+	var req models.ChangeRoleDTO
+
+	data, _ := io.ReadAll(r.Body)
+	err := json.Unmarshal(data, &req)
+	if err != nil {
+		weberrors.SendError(err, http.StatusBadRequest, w)
+		return
+	}
+
+	user, err := handler.userService.ChangeRole(ctx, r.PathValue("userId"), req)
+	if err != nil {
+		weberrors.SendError(err, http.StatusInternalServerError, w)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(user)
+}
+
+func (handler *UserHandler) DeactivateUser(ctx context.Context, w http.ResponseWriter, r *http.Request) {
+
+	err := handler.userService.DeactivateUser(ctx, r.PathValue("userId"))
+	if err != nil {
+		weberrors.SendError(err, http.StatusInternalServerError, w)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+func (handler *UserHandler) ReactivateUser(ctx context.Context, w http.ResponseWriter, r *http.Request) {
+
+	err := handler.userService.ReactivateUser(ctx, r.PathValue("userId"))
+	if err != nil {
+		weberrors.SendError(err, http.StatusInternalServerError, w)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+func (handler *UserHandler) UnlockUser(ctx context.Context, w http.ResponseWriter, r *http.Request) {
+
+	err := handler.userService.UnlockUser(ctx, r.PathValue("userId"))
+	if err != nil {
+		weberrors.SendError(err, http.StatusInternalServerError, w)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+func (handler *UserHandler) ResetMFA(ctx context.Context, w http.ResponseWriter, r *http.Request) {
+
+	err := handler.userService.ResetMFA(ctx, r.PathValue("userId"))
+	if err != nil {
+		weberrors.SendError(err, http.StatusInternalServerError, w)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+}
+
+func (handler *UserHandler) GetFailedLogins(ctx context.Context, w http.ResponseWriter, r *http.Request) {
+
+	query := r.URL.Query()
+
+	page, err := pagination.FromQuery(query)
+	if err != nil {
+		weberrors.SendError(err, http.StatusBadRequest, w)
+		return
+	}
+
+	attempts, err := handler.userService.GetFailedLogins(ctx, query.Get("email"), query.Get("ip"), page)
+	if err != nil {
+		weberrors.SendError(err, http.StatusInternalServerError, w)
+		return
+	}
+
+	pagination.SetLink(w, r, attempts.NextCursor)
+	w.WriteHeader(http.StatusOK)
+	json.NewEncoder(w).Encode(attempts)
+}
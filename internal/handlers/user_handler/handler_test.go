@@ -0,0 +1,460 @@
+package userhandler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/Kaushik1766/LibraryManagement/internal/models"
+	"github.com/Kaushik1766/LibraryManagement/internal/pagination"
+	"github.com/Kaushik1766/LibraryManagement/mocks"
+	"go.uber.org/mock/gomock"
+)
+
+const userId = "550e8400-e29b-41d4-a716-446655440000"
+
+func anyToReader(data any) io.Reader {
+	dataJsonBytes, _ := json.Marshal(data)
+	return bytes.NewReader(dataJsonBytes)
+}
+
+func withUserId(r *http.Request) *http.Request {
+	r.SetPathValue("userId", userId)
+	return r
+}
+
+func TestNewUserHandler(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUserService := mocks.NewMockUserManager(ctrl)
+
+	want := &UserHandler{userService: mockUserService}
+	if got := NewUserHandler(mockUserService); !reflect.DeepEqual(got, want) {
+		t.Errorf("NewUserHandler() = %v, want %v", got, want)
+	}
+}
+
+func TestUserHandler_GetUsers(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUserService := mocks.NewMockUserManager(ctrl)
+
+	tests := []struct {
+		name           string
+		r              *http.Request
+		expectedStatus int
+		mockSetup      func()
+	}{
+		{
+			name:           "valid get users",
+			r:              httptest.NewRequest(http.MethodGet, "/users?q=kaushik&role=customer&status=active&limit=10", nil),
+			expectedStatus: http.StatusOK,
+			mockSetup: func() {
+				mockUserService.EXPECT().GetUsers(gomock.Any(), "kaushik", "customer", "active", pagination.Request{Limit: 10}).
+					Return(pagination.Page[models.UserDTO]{Items: []models.UserDTO{}}, nil)
+			},
+		},
+		{
+			name:           "invalid limit",
+			r:              httptest.NewRequest(http.MethodGet, "/users?limit=0", nil),
+			expectedStatus: http.StatusBadRequest,
+			mockSetup:      func() {},
+		},
+		{
+			name:           "service error",
+			r:              httptest.NewRequest(http.MethodGet, "/users", nil),
+			expectedStatus: http.StatusInternalServerError,
+			mockSetup: func() {
+				mockUserService.EXPECT().GetUsers(gomock.Any(), "", "", "", gomock.Any()).
+					Return(pagination.Page[models.UserDTO]{}, errors.New("database error"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := &UserHandler{
+				userService: mockUserService,
+			}
+			tt.mockSetup()
+			recorder := httptest.NewRecorder()
+			handler.GetUsers(context.Background(), recorder, tt.r)
+
+			if recorder.Code != tt.expectedStatus {
+				t.Errorf("GetUsers() status = %v, want %v", recorder.Code, tt.expectedStatus)
+			}
+		})
+	}
+}
+
+func TestUserHandler_GetUser(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUserService := mocks.NewMockUserManager(ctrl)
+
+	tests := []struct {
+		name           string
+		expectedStatus int
+		mockSetup      func()
+	}{
+		{
+			name:           "valid get user",
+			expectedStatus: http.StatusOK,
+			mockSetup: func() {
+				mockUserService.EXPECT().GetUser(gomock.Any(), userId).Return(models.UserDTO{ID: userId}, nil)
+			},
+		},
+		{
+			name:           "service error",
+			expectedStatus: http.StatusInternalServerError,
+			mockSetup: func() {
+				mockUserService.EXPECT().GetUser(gomock.Any(), userId).Return(models.UserDTO{}, errors.New("database error"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := &UserHandler{
+				userService: mockUserService,
+			}
+			tt.mockSetup()
+			recorder := httptest.NewRecorder()
+			handler.GetUser(context.Background(), recorder, withUserId(httptest.NewRequest(http.MethodGet, "/users/"+userId, nil)))
+
+			if recorder.Code != tt.expectedStatus {
+				t.Errorf("GetUser() status = %v, want %v", recorder.Code, tt.expectedStatus)
+			}
+		})
+	}
+}
+
+func TestUserHandler_UpdateUser(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUserService := mocks.NewMockUserManager(ctrl)
+
+	tests := []struct {
+		name           string
+		body           io.Reader
+		expectedStatus int
+		mockSetup      func()
+	}{
+		{
+			name:           "valid update",
+			body:           anyToReader(map[string]string{"name": "kaushik"}),
+			expectedStatus: http.StatusOK,
+			mockSetup: func() {
+				mockUserService.EXPECT().UpdateUser(gomock.Any(), userId, gomock.Any()).Return(models.UserDTO{ID: userId}, nil)
+			},
+		},
+		{
+			name:           "invalid json",
+			body:           bytes.NewReader([]byte("invalid json")),
+			expectedStatus: http.StatusBadRequest,
+			mockSetup:      func() {},
+		},
+		{
+			name:           "service error",
+			body:           anyToReader(map[string]string{"name": "kaushik"}),
+			expectedStatus: http.StatusInternalServerError,
+			mockSetup: func() {
+				mockUserService.EXPECT().UpdateUser(gomock.Any(), userId, gomock.Any()).Return(models.UserDTO{}, errors.New("database error"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := &UserHandler{
+				userService: mockUserService,
+			}
+			tt.mockSetup()
+			recorder := httptest.NewRecorder()
+			handler.UpdateUser(context.Background(), recorder, withUserId(httptest.NewRequest(http.MethodPatch, "/users/"+userId, tt.body)))
+
+			if recorder.Code != tt.expectedStatus {
+				t.Errorf("UpdateUser() status = %v, want %v", recorder.Code, tt.expectedStatus)
+			}
+		})
+	}
+}
+
+func TestUserHandler_ChangeRole(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUserService := mocks.NewMockUserManager(ctrl)
+
+	tests := []struct {
+		name           string
+		body           io.Reader
+		expectedStatus int
+		mockSetup      func()
+	}{
+		{
+			name:           "valid change role",
+			body:           anyToReader(map[string]string{"role": "librarian"}),
+			expectedStatus: http.StatusOK,
+			mockSetup: func() {
+				mockUserService.EXPECT().ChangeRole(gomock.Any(), userId, models.ChangeRoleDTO{Role: "librarian"}).Return(models.UserDTO{Role: "Librarian"}, nil)
+			},
+		},
+		{
+			name:           "invalid json",
+			body:           bytes.NewReader([]byte("invalid json")),
+			expectedStatus: http.StatusBadRequest,
+			mockSetup:      func() {},
+		},
+		{
+			name:           "service error",
+			body:           anyToReader(map[string]string{"role": "wizard"}),
+			expectedStatus: http.StatusInternalServerError,
+			mockSetup: func() {
+				mockUserService.EXPECT().ChangeRole(gomock.Any(), userId, gomock.Any()).Return(models.UserDTO{}, errors.New("invalid role"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := &UserHandler{
+				userService: mockUserService,
+			}
+			tt.mockSetup()
+			recorder := httptest.NewRecorder()
+			handler.ChangeRole(context.Background(), recorder, withUserId(httptest.NewRequest(http.MethodPut, "/users/"+userId+"/role", tt.body)))
+
+			if recorder.Code != tt.expectedStatus {
+				t.Errorf("ChangeRole() status = %v, want %v", recorder.Code, tt.expectedStatus)
+			}
+		})
+	}
+}
+
+func TestUserHandler_DeactivateUser(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUserService := mocks.NewMockUserManager(ctrl)
+
+	tests := []struct {
+		name           string
+		expectedStatus int
+		mockSetup      func()
+	}{
+		{
+			name:           "valid deactivate",
+			expectedStatus: http.StatusOK,
+			mockSetup: func() {
+				mockUserService.EXPECT().DeactivateUser(gomock.Any(), userId).Return(nil)
+			},
+		},
+		{
+			name:           "service error",
+			expectedStatus: http.StatusInternalServerError,
+			mockSetup: func() {
+				mockUserService.EXPECT().DeactivateUser(gomock.Any(), userId).Return(errors.New("database error"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := &UserHandler{
+				userService: mockUserService,
+			}
+			tt.mockSetup()
+			recorder := httptest.NewRecorder()
+			handler.DeactivateUser(context.Background(), recorder, withUserId(httptest.NewRequest(http.MethodPost, "/users/"+userId+"/deactivate", nil)))
+
+			if recorder.Code != tt.expectedStatus {
+				t.Errorf("DeactivateUser() status = %v, want %v", recorder.Code, tt.expectedStatus)
+			}
+		})
+	}
+}
+
+func TestUserHandler_ReactivateUser(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUserService := mocks.NewMockUserManager(ctrl)
+
+	tests := []struct {
+		name           string
+		expectedStatus int
+		mockSetup      func()
+	}{
+		{
+			name:           "valid reactivate",
+			expectedStatus: http.StatusOK,
+			mockSetup: func() {
+				mockUserService.EXPECT().ReactivateUser(gomock.Any(), userId).Return(nil)
+			},
+		},
+		{
+			name:           "service error",
+			expectedStatus: http.StatusInternalServerError,
+			mockSetup: func() {
+				mockUserService.EXPECT().ReactivateUser(gomock.Any(), userId).Return(errors.New("database error"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := &UserHandler{
+				userService: mockUserService,
+			}
+			tt.mockSetup()
+			recorder := httptest.NewRecorder()
+			handler.ReactivateUser(context.Background(), recorder, withUserId(httptest.NewRequest(http.MethodPost, "/users/"+userId+"/reactivate", nil)))
+
+			if recorder.Code != tt.expectedStatus {
+				t.Errorf("ReactivateUser() status = %v, want %v", recorder.Code, tt.expectedStatus)
+			}
+		})
+	}
+}
+
+func TestUserHandler_UnlockUser(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUserService := mocks.NewMockUserManager(ctrl)
+
+	tests := []struct {
+		name           string
+		expectedStatus int
+		mockSetup      func()
+	}{
+		{
+			name:           "valid unlock",
+			expectedStatus: http.StatusOK,
+			mockSetup: func() {
+				mockUserService.EXPECT().UnlockUser(gomock.Any(), userId).Return(nil)
+			},
+		},
+		{
+			name:           "service error",
+			expectedStatus: http.StatusInternalServerError,
+			mockSetup: func() {
+				mockUserService.EXPECT().UnlockUser(gomock.Any(), userId).Return(errors.New("database error"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := &UserHandler{
+				userService: mockUserService,
+			}
+			tt.mockSetup()
+			recorder := httptest.NewRecorder()
+			handler.UnlockUser(context.Background(), recorder, withUserId(httptest.NewRequest(http.MethodPost, "/users/"+userId+"/unlock", nil)))
+
+			if recorder.Code != tt.expectedStatus {
+				t.Errorf("UnlockUser() status = %v, want %v", recorder.Code, tt.expectedStatus)
+			}
+		})
+	}
+}
+
+func TestUserHandler_ResetMFA(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUserService := mocks.NewMockUserManager(ctrl)
+
+	tests := []struct {
+		name           string
+		expectedStatus int
+		mockSetup      func()
+	}{
+		{
+			name:           "valid reset",
+			expectedStatus: http.StatusOK,
+			mockSetup: func() {
+				mockUserService.EXPECT().ResetMFA(gomock.Any(), userId).Return(nil)
+			},
+		},
+		{
+			name:           "service error",
+			expectedStatus: http.StatusInternalServerError,
+			mockSetup: func() {
+				mockUserService.EXPECT().ResetMFA(gomock.Any(), userId).Return(errors.New("database error"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := &UserHandler{
+				userService: mockUserService,
+			}
+			tt.mockSetup()
+			recorder := httptest.NewRecorder()
+			handler.ResetMFA(context.Background(), recorder, withUserId(httptest.NewRequest(http.MethodDelete, "/users/"+userId+"/mfa", nil)))
+
+			if recorder.Code != tt.expectedStatus {
+				t.Errorf("ResetMFA() status = %v, want %v", recorder.Code, tt.expectedStatus)
+			}
+		})
+	}
+}
+
+func TestUserHandler_GetFailedLogins(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUserService := mocks.NewMockUserManager(ctrl)
+
+	tests := []struct {
+		name           string
+		r              *http.Request
+		expectedStatus int
+		mockSetup      func()
+	}{
+		{
+			name:           "valid get failed logins",
+			r:              httptest.NewRequest(http.MethodGet, "/failed-logins?email=kaushik@a.com&ip=203.0.113.7", nil),
+			expectedStatus: http.StatusOK,
+			mockSetup: func() {
+				mockUserService.EXPECT().GetFailedLogins(gomock.Any(), "kaushik@a.com", "203.0.113.7", pagination.Request{Limit: pagination.DefaultLimit}).
+					Return(pagination.Page[models.FailedLoginDTO]{Items: []models.FailedLoginDTO{}}, nil)
+			},
+		},
+		{
+			name:           "invalid limit",
+			r:              httptest.NewRequest(http.MethodGet, "/failed-logins?limit=0", nil),
+			expectedStatus: http.StatusBadRequest,
+			mockSetup:      func() {},
+		},
+		{
+			name:           "service error",
+			r:              httptest.NewRequest(http.MethodGet, "/failed-logins", nil),
+			expectedStatus: http.StatusInternalServerError,
+			mockSetup: func() {
+				mockUserService.EXPECT().GetFailedLogins(gomock.Any(), "", "", gomock.Any()).
+					Return(pagination.Page[models.FailedLoginDTO]{}, errors.New("database error"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := &UserHandler{
+				userService: mockUserService,
+			}
+			tt.mockSetup()
+			recorder := httptest.NewRecorder()
+			handler.GetFailedLogins(context.Background(), recorder, tt.r)
+
+			if recorder.Code != tt.expectedStatus {
+				t.Errorf("GetFailedLogins() status = %v, want %v", recorder.Code, tt.expectedStatus)
+			}
+		})
+	}
+}
@@ -0,0 +1,229 @@
+package jwtkeys
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/Kaushik1766/LibraryManagement/internal/models"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// minRSABits is the smallest RSA key accepted.
+const minRSABits = 2048
+
+// Key is one key of a KeySet. A key made from a public key can only verify,
+// which is how a retired signing key is kept until its tokens have expired.
+type Key struct {
+	ID     string
+	method jwt.SigningMethod
+	signer crypto.Signer
+	public crypto.PublicKey
+}
+
+// NewKey makes a key named id from an RSA or Ed25519 private or public key.
+// RSA keys sign with RS256 and Ed25519 keys with EdDSA.
+func NewKey(id string, key any) (Key, error) {
+	if id == "" {
+		return Key{}, errors.New("key id cant be empty")
+	}
+
+	k := Key{ID: id}
+	if signer, ok := key.(crypto.Signer); ok {
+		k.signer = signer
+		key = signer.Public()
+	}
+
+	switch public := key.(type) {
+	case *rsa.PublicKey:
+		if public.N.BitLen() < minRSABits {
+			return Key{}, fmt.Errorf("key %s: rsa keys need at least %d bits", id, minRSABits)
+		}
+		k.method = jwt.SigningMethodRS256
+	case ed25519.PublicKey:
+		k.method = jwt.SigningMethodEdDSA
+	default:
+		return Key{}, fmt.Errorf("key %s: only rsa and ed25519 keys are supported", id)
+	}
+	k.public = key
+	return k, nil
+}
+
+// KeySet signs access tokens with one key and verifies them with any of its
+// keys, so tokens signed before a rotation keep working until they expire.
+type KeySet struct {
+	signing Key
+	keys    map[string]Key
+}
+
+// NewKeySet makes a set of keys signing with the key named signingKey. When
+// signingKey is empty the set must hold exactly one private key, which signs.
+func NewKeySet(signingKey string, keys ...Key) (*KeySet, error) {
+	set := &KeySet{keys: make(map[string]Key, len(keys))}
+
+	var private []Key
+	for _, key := range keys {
+		if _, ok := set.keys[key.ID]; ok {
+			return nil, fmt.Errorf("key %s is there twice", key.ID)
+		}
+		set.keys[key.ID] = key
+		if key.signer != nil {
+			private = append(private, key)
+		}
+	}
+
+	if signingKey == "" {
+		if len(private) != 1 {
+			return nil, fmt.Errorf("found %d private keys, name the one to sign with", len(private))
+		}
+		set.signing = private[0]
+		return set, nil
+	}
+
+	signing, ok := set.keys[signingKey]
+	if !ok {
+		return nil, fmt.Errorf("signing key %s not found", signingKey)
+	}
+	if signing.signer == nil {
+		return nil, fmt.Errorf("signing key %s is a public key", signingKey)
+	}
+	set.signing = signing
+	return set, nil
+}
+
+// Load reads every .pem file in dir as a key named after the file, so
+// keys/2024-06.pem is key 2024-06. Private keys are PKCS #8 or PKCS #1, and
+// public keys PKIX or PKCS #1.
+func Load(dir, signingKey string) (*KeySet, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var keys []Key
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".pem" {
+			continue
+		}
+
+		path := filepath.Join(dir, entry.Name())
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+
+		parsed, err := parsePEM(data)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+
+		key, err := NewKey(strings.TrimSuffix(entry.Name(), ".pem"), parsed)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		keys = append(keys, key)
+	}
+
+	set, err := NewKeySet(signingKey, keys...)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", dir, err)
+	}
+	return set, nil
+}
+
+func parsePEM(data []byte) (any, error) {
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("no PEM data found")
+	}
+
+	switch block.Type {
+	case "PRIVATE KEY":
+		return x509.ParsePKCS8PrivateKey(block.Bytes)
+	case "RSA PRIVATE KEY":
+		return x509.ParsePKCS1PrivateKey(block.Bytes)
+	case "PUBLIC KEY":
+		return x509.ParsePKIXPublicKey(block.Bytes)
+	case "RSA PUBLIC KEY":
+		return x509.ParsePKCS1PublicKey(block.Bytes)
+	}
+	return nil, fmt.Errorf("unsupported PEM block %q", block.Type)
+}
+
+// Sign signs claims with the signing key and names it in the kid header.
+func (set *KeySet) Sign(claims jwt.Claims) (string, error) {
+	token := jwt.NewWithClaims(set.signing.method, claims)
+	token.Header["kid"] = set.signing.ID
+	return token.SignedString(set.signing.signer)
+}
+
+// Keyfunc is the jwt.Keyfunc for tokens from Sign. The token has to name a
+// key of the set and use that key's algorithm, so a public key can never be
+// taken for an HMAC secret.
+func (set *KeySet) Keyfunc(token *jwt.Token) (any, error) {
+	kid, _ := token.Header["kid"].(string)
+
+	key, ok := set.keys[kid]
+	if !ok {
+		return nil, fmt.Errorf("unknown signing key %q", kid)
+	}
+	if token.Method.Alg() != key.method.Alg() {
+		return nil, fmt.Errorf("key %s doesn't sign with %s", kid, token.Method.Alg())
+	}
+	return key.public, nil
+}
+
+// Algorithms lists the algorithms the keys of the set use, for
+// jwt.WithValidMethods.
+func (set *KeySet) Algorithms() []string {
+	var algs []string
+	for _, key := range set.keys {
+		if !slices.Contains(algs, key.method.Alg()) {
+			algs = append(algs, key.method.Alg())
+		}
+	}
+	slices.Sort(algs)
+	return algs
+}
+
+// JWKS returns the public half of every key, ordered by id.
+func (set *KeySet) JWKS() models.JWKSDTO {
+	jwks := models.JWKSDTO{Keys: make([]models.JWKDTO, 0, len(set.keys))}
+	for _, key := range set.keys {
+		jwks.Keys = append(jwks.Keys, key.jwk())
+	}
+	slices.SortFunc(jwks.Keys, func(a, b models.JWKDTO) int {
+		return strings.Compare(a.ID, b.ID)
+	})
+	return jwks
+}
+
+func (key Key) jwk() models.JWKDTO {
+	jwk := models.JWKDTO{
+		ID:        key.ID,
+		Use:       "sig",
+		Algorithm: key.method.Alg(),
+	}
+
+	switch public := key.public.(type) {
+	case *rsa.PublicKey:
+		jwk.KeyType = "RSA"
+		jwk.N = base64.RawURLEncoding.EncodeToString(public.N.Bytes())
+		jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(public.E)).Bytes())
+	case ed25519.PublicKey:
+		jwk.KeyType = "OKP"
+		jwk.Curve = "Ed25519"
+		jwk.X = base64.RawURLEncoding.EncodeToString(public)
+	}
+	return jwk
+}
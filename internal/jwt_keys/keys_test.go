@@ -0,0 +1,259 @@
+package jwtkeys
+
+import (
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+func writePEM(t *testing.T, path, blockType string, der []byte) {
+	t.Helper()
+	if err := os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func newEd25519Key(t *testing.T, id string) Key {
+	t.Helper()
+	_, private, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	key, err := NewKey(id, private)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return key
+}
+
+func TestNewKey(t *testing.T) {
+	rsaKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	smallRSAKey, _ := rsa.GenerateKey(rand.Reader, 1024)
+	edPublic, edPrivate, _ := ed25519.GenerateKey(rand.Reader)
+	ecKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+
+	tests := []struct {
+		name       string
+		key        any
+		wantAlg    string
+		wantSigner bool
+		wantErr    bool
+	}{
+		{name: "rsa private key", key: rsaKey, wantAlg: "RS256", wantSigner: true},
+		{name: "rsa public key", key: &rsaKey.PublicKey, wantAlg: "RS256"},
+		{name: "ed25519 private key", key: edPrivate, wantAlg: "EdDSA", wantSigner: true},
+		{name: "ed25519 public key", key: edPublic, wantAlg: "EdDSA"},
+		{name: "short rsa key", key: smallRSAKey, wantErr: true},
+		{name: "ecdsa key", key: ecKey, wantErr: true},
+		{name: "hmac secret", key: []byte("secret"), wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewKey("k1", tt.key)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NewKey() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if got.method.Alg() != tt.wantAlg || (got.signer != nil) != tt.wantSigner {
+				t.Errorf("NewKey() = %v signer %v, want %v signer %v", got.method.Alg(), got.signer != nil, tt.wantAlg, tt.wantSigner)
+			}
+		})
+	}
+}
+
+func TestNewKeySet(t *testing.T) {
+	old := newEd25519Key(t, "old")
+	current := newEd25519Key(t, "current")
+	retired, _ := NewKey("retired", old.public)
+
+	tests := []struct {
+		name        string
+		signingKey  string
+		keys        []Key
+		wantSigning string
+		wantErr     bool
+	}{
+		{name: "named signing key", signingKey: "current", keys: []Key{old, current}, wantSigning: "current"},
+		{name: "only private key signs", keys: []Key{retired, current}, wantSigning: "current"},
+		{name: "several private keys", keys: []Key{old, current}, wantErr: true},
+		{name: "no keys", wantErr: true},
+		{name: "unknown signing key", signingKey: "next", keys: []Key{current}, wantErr: true},
+		{name: "public signing key", signingKey: "retired", keys: []Key{retired, current}, wantErr: true},
+		{name: "same id twice", signingKey: "current", keys: []Key{current, current}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewKeySet(tt.signingKey, tt.keys...)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NewKeySet() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && got.signing.ID != tt.wantSigning {
+				t.Errorf("NewKeySet() signs with %v, want %v", got.signing.ID, tt.wantSigning)
+			}
+		})
+	}
+}
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+
+	rsaKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	writePEM(t, filepath.Join(dir, "2024-01.pem"), "RSA PRIVATE KEY", x509.MarshalPKCS1PrivateKey(rsaKey))
+
+	_, edKey, _ := ed25519.GenerateKey(rand.Reader)
+	der, _ := x509.MarshalPKCS8PrivateKey(edKey)
+	writePEM(t, filepath.Join(dir, "2024-06.pem"), "PRIVATE KEY", der)
+
+	oldPublic, _, _ := ed25519.GenerateKey(rand.Reader)
+	der, _ = x509.MarshalPKIXPublicKey(oldPublic)
+	writePEM(t, filepath.Join(dir, "2023-06.pem"), "PUBLIC KEY", der)
+
+	if err := os.WriteFile(filepath.Join(dir, "README"), []byte("not a key"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	set, err := Load(dir, "2024-06")
+	if err != nil {
+		t.Fatalf("Load() error = %v", err)
+	}
+
+	var ids []string
+	for _, jwk := range set.JWKS().Keys {
+		ids = append(ids, jwk.ID)
+	}
+	if want := []string{"2023-06", "2024-01", "2024-06"}; !reflect.DeepEqual(ids, want) {
+		t.Errorf("Load() keys = %v, want %v", ids, want)
+	}
+	if want := []string{"EdDSA", "RS256"}; !reflect.DeepEqual(set.Algorithms(), want) {
+		t.Errorf("Algorithms() = %v, want %v", set.Algorithms(), want)
+	}
+
+	t.Run("several private keys", func(t *testing.T) {
+		if _, err := Load(dir, ""); err == nil {
+			t.Errorf("Load() without a signing key accepted two private keys")
+		}
+	})
+
+	t.Run("not a key", func(t *testing.T) {
+		bad := t.TempDir()
+		if err := os.WriteFile(filepath.Join(bad, "k1.pem"), []byte("not a key"), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := Load(bad, ""); err == nil {
+			t.Errorf("Load() accepted a file without a key")
+		}
+	})
+
+	t.Run("missing directory", func(t *testing.T) {
+		if _, err := Load(filepath.Join(dir, "missing"), ""); err == nil {
+			t.Errorf("Load() accepted a missing directory")
+		}
+	})
+}
+
+func TestKeySet_Keyfunc(t *testing.T) {
+	old := newEd25519Key(t, "old")
+	rsaKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	current, _ := NewKey("current", rsaKey)
+
+	oldSet, _ := NewKeySet("old", old)
+	retired, _ := NewKey("old", old.public)
+	set, _ := NewKeySet("current", retired, current)
+
+	claims := jwt.RegisteredClaims{ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour))}
+	sign := func(method jwt.SigningMethod, kid string, key any) string {
+		token := jwt.NewWithClaims(method, claims)
+		token.Header["kid"] = kid
+		signed, _ := token.SignedString(key)
+		return signed
+	}
+
+	tests := []struct {
+		name    string
+		token   string
+		wantErr bool
+	}{
+		{
+			name:    "current key",
+			token:   func() string { token, _ := set.Sign(claims); return token }(),
+			wantErr: false,
+		},
+		{
+			name:    "retired key still verifies",
+			token:   func() string { token, _ := oldSet.Sign(claims); return token }(),
+			wantErr: false,
+		},
+		{
+			name:    "unknown key",
+			token:   sign(jwt.SigningMethodRS256, "other", rsaKey),
+			wantErr: true,
+		},
+		{
+			name:    "no kid",
+			token:   sign(jwt.SigningMethodRS256, "", rsaKey),
+			wantErr: true,
+		},
+		{
+			name:    "algorithm of another key",
+			token:   sign(jwt.SigningMethodRS512, "current", rsaKey),
+			wantErr: true,
+		},
+		{
+			name: "public key used as an hmac secret",
+			token: sign(jwt.SigningMethodHS256, "current",
+				pem.EncodeToMemory(&pem.Block{Type: "RSA PUBLIC KEY", Bytes: x509.MarshalPKCS1PublicKey(&rsaKey.PublicKey)})),
+			wantErr: true,
+		},
+		{
+			name:    "unsigned",
+			token:   sign(jwt.SigningMethodNone, "current", jwt.UnsafeAllowNoneSignatureType),
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := jwt.Parse(tt.token, set.Keyfunc, jwt.WithValidMethods(set.Algorithms()))
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Parse() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestKeySet_JWKS(t *testing.T) {
+	public, private, _ := ed25519.GenerateKey(rand.Reader)
+	key, _ := NewKey("k1", private)
+	set, _ := NewKeySet("", key)
+
+	got := set.JWKS()
+	if len(got.Keys) != 1 {
+		t.Fatalf("JWKS() = %+v, want one key", got)
+	}
+	jwk := got.Keys[0]
+	if jwk.KeyType != "OKP" || jwk.Curve != "Ed25519" || jwk.Algorithm != "EdDSA" || jwk.Use != "sig" || jwk.ID != "k1" {
+		t.Errorf("JWKS() = %+v", jwk)
+	}
+	if x, err := jwt.NewParser().DecodeSegment(jwk.X); err != nil || !reflect.DeepEqual(x, []byte(public)) {
+		t.Errorf("JWKS() x = %v, want the public key", jwk.X)
+	}
+
+	rsaKey, _ := rsa.GenerateKey(rand.Reader, 2048)
+	key, _ = NewKey("k2", rsaKey)
+	set, _ = NewKeySet("", key)
+	if jwk := set.JWKS().Keys[0]; jwk.KeyType != "RSA" || jwk.E != "AQAB" || jwk.N == "" || jwk.Algorithm != "RS256" {
+		t.Errorf("JWKS() = %+v", jwk)
+	}
+}
@@ -0,0 +1,181 @@
+package loanpolicy
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	apperrors "github.com/Kaushik1766/LibraryManagement/internal/app_errors"
+	"github.com/Kaushik1766/LibraryManagement/internal/config"
+	"github.com/Kaushik1766/LibraryManagement/internal/models/enums/roles"
+)
+
+// Policy is what a patron of one role may do with items of one type.
+type Policy struct {
+	Role     roles.UserRoles
+	ItemType string
+	// MaxLoans bounds all the patron's open loans. When HasItemLimit is set
+	// MaxItemLoans bounds those of ItemType as well, it never raises
+	// MaxLoans. Zero in either means the item can't be borrowed at all.
+	MaxLoans      int
+	MaxItemLoans  int
+	HasItemLimit  bool
+	LoanPeriod    time.Duration
+	MaxLoanPeriod time.Duration
+	RenewalPeriod time.Duration
+	MaxRenewals   int
+	FinePerDay    int
+	FineCap       int
+}
+
+// Engine resolves the policy for a role and item type from the circulation
+// defaults and rules.
+type Engine struct {
+	defaults Policy
+	rules    []config.LoanRule
+}
+
+// New builds an engine from a circulation config that has passed Validate,
+// periods that don't parse are ignored.
+func New(cfg config.CirculationConfig) *Engine {
+	engine := &Engine{
+		defaults: Policy{
+			MaxLoans:      cfg.MaxLoans,
+			LoanPeriod:    period(cfg.LoanPeriod),
+			MaxLoanPeriod: period(cfg.MaxLoanPeriod),
+			RenewalPeriod: period(cfg.RenewalPeriod),
+			MaxRenewals:   cfg.MaxRenewals,
+			FinePerDay:    cfg.FinePerDay,
+			FineCap:       cfg.FineCap,
+		},
+		rules: append([]config.LoanRule(nil), cfg.Rules...),
+	}
+
+	// rules of equal weight keep the order they were configured in
+	sort.SliceStable(engine.rules, func(i, j int) bool {
+		return specificity(engine.rules[i]) < specificity(engine.rules[j])
+	})
+
+	return engine
+}
+
+func period(value string) time.Duration {
+	d, _ := config.ParsePeriod(value)
+	return d
+}
+
+func wildcard(value string) bool {
+	return value == "" || value == "*"
+}
+
+// specificity orders rules so an item type rule beats a role rule and a rule
+// naming both beats either.
+func specificity(rule config.LoanRule) int {
+	weight := 0
+	if !wildcard(rule.Role) {
+		weight++
+	}
+	if !wildcard(rule.ItemType) {
+		weight += 2
+	}
+	return weight
+}
+
+// For layers every matching rule over the defaults, least specific first.
+func (engine *Engine) For(role roles.UserRoles, itemType string) Policy {
+	policy := engine.defaults
+	policy.Role = role
+	policy.ItemType = itemType
+
+	for _, rule := range engine.rules {
+		if !wildcard(rule.Role) && !strings.EqualFold(rule.Role, role.String()) {
+			continue
+		}
+		if !wildcard(rule.ItemType) && !strings.EqualFold(rule.ItemType, itemType) {
+			continue
+		}
+
+		if rule.MaxLoans != nil {
+			if wildcard(rule.ItemType) {
+				policy.MaxLoans = *rule.MaxLoans
+			} else {
+				policy.MaxItemLoans = *rule.MaxLoans
+				policy.HasItemLimit = true
+			}
+		}
+		if rule.LoanPeriod != "" {
+			policy.LoanPeriod = period(rule.LoanPeriod)
+		}
+		if rule.MaxLoanPeriod != "" {
+			policy.MaxLoanPeriod = period(rule.MaxLoanPeriod)
+		}
+		if rule.RenewalPeriod != "" {
+			policy.RenewalPeriod = period(rule.RenewalPeriod)
+		}
+		if rule.MaxRenewals != nil {
+			policy.MaxRenewals = *rule.MaxRenewals
+		}
+		if rule.FinePerDay != nil {
+			policy.FinePerDay = *rule.FinePerDay
+		}
+		if rule.FineCap != nil {
+			policy.FineCap = *rule.FineCap
+		}
+	}
+
+	// a rule can shorten the maximum below the default period
+	if policy.LoanPeriod > policy.MaxLoanPeriod {
+		policy.LoanPeriod = policy.MaxLoanPeriod
+	}
+
+	return policy
+}
+
+// LoanPeriodFor checks a requested loan period, an empty one gets the
+// default period.
+func (policy Policy) LoanPeriodFor(requested string) (time.Duration, error) {
+	if requested == "" {
+		return policy.LoanPeriod, nil
+	}
+
+	d, err := config.ParsePeriod(requested)
+	if err != nil || d <= 0 {
+		return 0, apperrors.Validation("invalid_period", "invalid loan period").
+			WithDetail("issue_for", `must be a period like "14 days"`)
+	}
+
+	if d > policy.MaxLoanPeriod {
+		limit := config.FormatPeriod(policy.MaxLoanPeriod)
+		return 0, apperrors.Validation("loan_period_too_long", fmt.Sprintf("%s loans can be at most %s", policy.ItemType, limit)).
+			WithDetail("issue_for", "at most "+limit)
+	}
+
+	return d, nil
+}
+
+// CheckLoans refuses a new loan to a patron who already has openLoans open
+// loans, openItemLoans of them of ItemType.
+func (policy Policy) CheckLoans(openLoans, openItemLoans int) error {
+	if policy.MaxLoans == 0 || (policy.HasItemLimit && policy.MaxItemLoans == 0) {
+		return apperrors.Conflict("not_loanable", fmt.Sprintf("%s items cannot be borrowed by %s users", policy.ItemType, strings.ToLower(policy.Role.String())))
+	}
+
+	if openLoans >= policy.MaxLoans {
+		return apperrors.Conflict("loan_limit_reached", fmt.Sprintf("loan limit of %d books reached", policy.MaxLoans))
+	}
+
+	if policy.HasItemLimit && openItemLoans >= policy.MaxItemLoans {
+		return apperrors.Conflict("loan_limit_reached", fmt.Sprintf("loan limit of %d %s items reached", policy.MaxItemLoans, policy.ItemType))
+	}
+
+	return nil
+}
+
+// CheckRenewal refuses to renew a loan that already used its renewals.
+func (policy Policy) CheckRenewal(renewals int) error {
+	if renewals >= policy.MaxRenewals {
+		return apperrors.Conflict("renewal_limit_reached", fmt.Sprintf("renewal limit of %d reached", policy.MaxRenewals))
+	}
+	return nil
+}
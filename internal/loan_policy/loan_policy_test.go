@@ -0,0 +1,173 @@
+package loanpolicy
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	apperrors "github.com/Kaushik1766/LibraryManagement/internal/app_errors"
+	"github.com/Kaushik1766/LibraryManagement/internal/config"
+	"github.com/Kaushik1766/LibraryManagement/internal/models/enums/roles"
+)
+
+const day = 24 * time.Hour
+
+func intPtr(n int) *int {
+	return &n
+}
+
+func TestEngine_For(t *testing.T) {
+	cfg := config.Default().Circulation
+	cfg.Rules = []config.LoanRule{
+		{Role: "customer", ItemType: "dvd", MaxLoans: intPtr(1)},
+		{ItemType: "dvd", MaxLoans: intPtr(3), LoanPeriod: "3 days", MaxLoanPeriod: "7 days", FinePerDay: intPtr(100)},
+		{Role: "Librarian", MaxLoans: intPtr(20), MaxLoanPeriod: "90 days", MaxRenewals: intPtr(5)},
+		{ItemType: "reference", MaxLoans: intPtr(0)},
+		{Role: "*", ItemType: "*", FineCap: intPtr(1500)},
+	}
+	engine := New(cfg)
+
+	tests := []struct {
+		name     string
+		role     roles.UserRoles
+		itemType string
+		want     Policy
+	}{
+		{
+			name:     "defaults",
+			role:     roles.Customer,
+			itemType: "book",
+			want: Policy{
+				Role: roles.Customer, ItemType: "book",
+				MaxLoans: 5, LoanPeriod: day, MaxLoanPeriod: 30 * day, RenewalPeriod: 7 * day,
+				MaxRenewals: 2, FinePerDay: 50, FineCap: 1500,
+			},
+		},
+		{
+			name:     "role rule",
+			role:     roles.Librarian,
+			itemType: "book",
+			want: Policy{
+				Role: roles.Librarian, ItemType: "book",
+				MaxLoans: 20, LoanPeriod: day, MaxLoanPeriod: 90 * day, RenewalPeriod: 7 * day,
+				MaxRenewals: 5, FinePerDay: 50, FineCap: 1500,
+			},
+		},
+		{
+			name:     "item type rule beats role rule",
+			role:     roles.Librarian,
+			itemType: "dvd",
+			want: Policy{
+				Role: roles.Librarian, ItemType: "dvd",
+				MaxLoans: 20, MaxItemLoans: 3, HasItemLimit: true, LoanPeriod: 3 * day, MaxLoanPeriod: 7 * day, RenewalPeriod: 7 * day,
+				MaxRenewals: 5, FinePerDay: 100, FineCap: 1500,
+			},
+		},
+		{
+			name:     "role and item type rule beats both",
+			role:     roles.Customer,
+			itemType: "DVD",
+			want: Policy{
+				Role: roles.Customer, ItemType: "DVD",
+				MaxLoans: 5, MaxItemLoans: 1, HasItemLimit: true, LoanPeriod: 3 * day, MaxLoanPeriod: 7 * day, RenewalPeriod: 7 * day,
+				MaxRenewals: 2, FinePerDay: 100, FineCap: 1500,
+			},
+		},
+		{
+			name:     "item that cant be borrowed",
+			role:     roles.Customer,
+			itemType: "reference",
+			want: Policy{
+				Role: roles.Customer, ItemType: "reference",
+				MaxLoans: 5, MaxItemLoans: 0, HasItemLimit: true, LoanPeriod: day, MaxLoanPeriod: 30 * day, RenewalPeriod: 7 * day,
+				MaxRenewals: 2, FinePerDay: 50, FineCap: 1500,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := engine.For(tt.role, tt.itemType); got != tt.want {
+				t.Errorf("For() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPolicy_LoanPeriodFor(t *testing.T) {
+	policy := Policy{ItemType: "book", LoanPeriod: 14 * day, MaxLoanPeriod: 30 * day}
+
+	tests := []struct {
+		name      string
+		requested string
+		want      time.Duration
+		wantCode  string
+	}{
+		{name: "default period", requested: "", want: 14 * day},
+		{name: "requested period", requested: "1 week 3 days", want: 10 * day},
+		{name: "the maximum", requested: "30 days", want: 30 * day},
+		{name: "above the maximum", requested: "31 days", wantCode: "loan_period_too_long"},
+		{name: "not a period", requested: "forever", wantCode: "invalid_period"},
+		{name: "zero", requested: "0 days", wantCode: "invalid_period"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := policy.LoanPeriodFor(tt.requested)
+			if code := codeOf(err); code != tt.wantCode {
+				t.Errorf("LoanPeriodFor() error = %v, want code %q", err, tt.wantCode)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("LoanPeriodFor() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPolicy_CheckLoans(t *testing.T) {
+	dvd := Policy{ItemType: "dvd", MaxLoans: 5, MaxItemLoans: 3, HasItemLimit: true}
+
+	tests := []struct {
+		name          string
+		policy        Policy
+		openLoans     int
+		openItemLoans int
+		wantCode      string
+	}{
+		{name: "under the limit", policy: Policy{MaxLoans: 5}, openLoans: 4},
+		{name: "at the limit", policy: Policy{MaxLoans: 5}, openLoans: 5, wantCode: "loan_limit_reached"},
+		{name: "under both limits", policy: dvd, openLoans: 4, openItemLoans: 2},
+		{name: "per item type limit", policy: dvd, openLoans: 3, openItemLoans: 3, wantCode: "loan_limit_reached"},
+		{name: "per item type rule cant pass the overall limit", policy: dvd, openLoans: 5, openItemLoans: 0, wantCode: "loan_limit_reached"},
+		{name: "not loanable", policy: Policy{ItemType: "reference", MaxLoans: 5, HasItemLimit: true}, wantCode: "not_loanable"},
+		{name: "role that cant borrow", policy: Policy{ItemType: "book", MaxLoans: 0}, wantCode: "not_loanable"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.policy.CheckLoans(tt.openLoans, tt.openItemLoans)
+			if code := codeOf(err); code != tt.wantCode {
+				t.Errorf("CheckLoans() error = %v, want code %q", err, tt.wantCode)
+			}
+			if err != nil && apperrors.KindOf(err) != apperrors.KindConflict {
+				t.Errorf("CheckLoans() error kind = %v, want conflict", apperrors.KindOf(err))
+			}
+		})
+	}
+}
+
+func TestPolicy_CheckRenewal(t *testing.T) {
+	policy := Policy{MaxRenewals: 2}
+	if err := policy.CheckRenewal(1); err != nil {
+		t.Errorf("CheckRenewal(1) error = %v", err)
+	}
+	if code := codeOf(policy.CheckRenewal(2)); code != "renewal_limit_reached" {
+		t.Errorf("CheckRenewal(2) code = %q, want renewal_limit_reached", code)
+	}
+}
+
+func codeOf(err error) string {
+	var appErr *apperrors.Error
+	if errors.As(err, &appErr) {
+		return appErr.Code
+	}
+	return ""
+}
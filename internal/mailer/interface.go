@@ -0,0 +1,6 @@
+package mailer
+
+//go:generate mockgen -source=interface.go -destination=../../mocks/mock_mailer.go -package=mocks
+type Mailer interface {
+	Send(to, subject, body string) error
+}
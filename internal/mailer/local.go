@@ -0,0 +1,67 @@
+package mailer
+
+import (
+	"log"
+	"os"
+	"sync"
+	"time"
+)
+
+// FileMailer appends every message to a file instead of sending it, so a
+// developer can read the links the app would have mailed.
+type FileMailer struct {
+	from string
+	path string
+	mu   sync.Mutex
+}
+
+func NewFileMailer(from, path string) *FileMailer {
+	return &FileMailer{
+		from: from,
+		path: path,
+	}
+}
+
+func (mailer *FileMailer) Send(to, subject, body string) error {
+	msg, err := compose(mailer.from, to, subject, body, time.Now())
+	if err != nil {
+		return err
+	}
+
+	mailer.mu.Lock()
+	defer mailer.mu.Unlock()
+
+	file, err := os.OpenFile(mailer.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+	if _, err := file.Write(append(msg, '\n')); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// LogMailer writes every message to a logger. It is the default when no mail
+// is configured.
+type LogMailer struct {
+	from   string
+	logger *log.Logger
+}
+
+func NewLogMailer(from string, logger *log.Logger) *LogMailer {
+	return &LogMailer{
+		from:   from,
+		logger: logger,
+	}
+}
+
+func (mailer *LogMailer) Send(to, subject, body string) error {
+	msg, err := compose(mailer.from, to, subject, body, time.Now())
+	if err != nil {
+		return err
+	}
+
+	mailer.logger.Printf("mail not sent, no smtp server configured:\n%s", msg)
+	return nil
+}
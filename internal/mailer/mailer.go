@@ -0,0 +1,95 @@
+package mailer
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"log"
+	"mime"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Kaushik1766/LibraryManagement/internal/config"
+)
+
+// New picks the mailer cfg describes: SMTP when a host is set, otherwise a
+// file when one is named, otherwise the log.
+func New(cfg config.MailConfig) Mailer {
+	switch {
+	case cfg.SMTP.Host != "":
+		return NewSMTPMailer(cfg.From, cfg.SMTP)
+	case cfg.File != "":
+		return NewFileMailer(cfg.From, cfg.File)
+	default:
+		return NewLogMailer(cfg.From, log.Default())
+	}
+}
+
+// SMTPMailer sends through an SMTP server, authenticating when a username is
+// set. net/smtp upgrades to TLS whenever the server offers STARTTLS.
+type SMTPMailer struct {
+	from string
+	addr string
+	auth smtp.Auth
+	send func(addr string, a smtp.Auth, from string, to []string, msg []byte) error
+}
+
+func NewSMTPMailer(from string, cfg config.SMTPConfig) *SMTPMailer {
+	mailer := &SMTPMailer{
+		from: from,
+		addr: net.JoinHostPort(cfg.Host, strconv.Itoa(cfg.Port)),
+		send: smtp.SendMail,
+	}
+	if cfg.Username != "" {
+		mailer.auth = smtp.PlainAuth("", cfg.Username, cfg.Password, cfg.Host)
+	}
+	return mailer
+}
+
+func (mailer *SMTPMailer) Send(to, subject, body string) error {
+	msg, err := compose(mailer.from, to, subject, body, time.Now())
+	if err != nil {
+		return err
+	}
+
+	return mailer.send(mailer.addr, mailer.auth, envelope(mailer.from), []string{envelope(to)}, msg)
+}
+
+// compose renders a plain text message. Addresses and the subject are
+// checked for line breaks so a caller can't smuggle in headers.
+func compose(from, to, subject, body string, date time.Time) ([]byte, error) {
+	for _, header := range []string{from, to, subject} {
+		if strings.ContainsAny(header, "\r\n") {
+			return nil, errors.New("mail headers cant contain line breaks")
+		}
+	}
+	if _, err := mail.ParseAddress(to); err != nil {
+		return nil, fmt.Errorf("invalid recipient %q: %w", to, err)
+	}
+
+	var msg bytes.Buffer
+	fmt.Fprintf(&msg, "From: %s\r\n", from)
+	fmt.Fprintf(&msg, "To: %s\r\n", to)
+	fmt.Fprintf(&msg, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", subject))
+	fmt.Fprintf(&msg, "Date: %s\r\n", date.Format(time.RFC1123Z))
+	msg.WriteString("MIME-Version: 1.0\r\n")
+	msg.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	msg.WriteString("\r\n")
+	msg.WriteString(strings.ReplaceAll(strings.ReplaceAll(body, "\r\n", "\n"), "\n", "\r\n"))
+	msg.WriteString("\r\n")
+	return msg.Bytes(), nil
+}
+
+// envelope is the bare address SMTP wants for a header address like
+// "Library <library@example.com>".
+func envelope(address string) string {
+	parsed, err := mail.ParseAddress(address)
+	if err != nil {
+		return address
+	}
+	return parsed.Address
+}
@@ -0,0 +1,170 @@
+package mailer
+
+import (
+	"bytes"
+	"errors"
+	"log"
+	"net/smtp"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Kaushik1766/LibraryManagement/internal/config"
+)
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  config.MailConfig
+		want reflect.Type
+	}{
+		{
+			name: "smtp host set",
+			cfg:  config.MailConfig{From: "library@a.com", SMTP: config.SMTPConfig{Host: "smtp.a.com", Port: 587}, File: "mail.log"},
+			want: reflect.TypeOf(&SMTPMailer{}),
+		},
+		{
+			name: "file set",
+			cfg:  config.MailConfig{From: "library@a.com", File: "mail.log"},
+			want: reflect.TypeOf(&FileMailer{}),
+		},
+		{
+			name: "nothing configured",
+			cfg:  config.MailConfig{From: "library@a.com"},
+			want: reflect.TypeOf(&LogMailer{}),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := reflect.TypeOf(New(tt.cfg)); got != tt.want {
+				t.Errorf("New() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompose(t *testing.T) {
+	date := time.Date(2024, 3, 10, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		to      string
+		subject string
+		want    []string
+		wantErr bool
+	}{
+		{
+			name:    "valid message",
+			to:      "kaushik@a.com",
+			subject: "Reset your password",
+			want: []string{
+				"From: Library <library@a.com>\r\n",
+				"To: kaushik@a.com\r\n",
+				"Subject: Reset your password\r\n",
+				"Date: Sun, 10 Mar 2024 12:00:00 +0000\r\n",
+				"\r\nline one\r\nline two\r\n",
+			},
+			wantErr: false,
+		},
+		{
+			name:    "line break in subject",
+			to:      "kaushik@a.com",
+			subject: "hi\r\nBcc: someone@b.com",
+			wantErr: true,
+		},
+		{
+			name:    "invalid recipient",
+			to:      "not an address",
+			subject: "hi",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := compose("Library <library@a.com>", tt.to, tt.subject, "line one\nline two", date)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("compose() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			for _, part := range tt.want {
+				if !strings.Contains(string(got), part) {
+					t.Errorf("compose() = %q, missing %q", got, part)
+				}
+			}
+		})
+	}
+}
+
+func TestSMTPMailer_Send(t *testing.T) {
+	tests := []struct {
+		name    string
+		sendErr error
+		wantErr bool
+	}{
+		{
+			name:    "valid send",
+			wantErr: false,
+		},
+		{
+			name:    "server error",
+			sendErr: errors.New("connection refused"),
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mailer := NewSMTPMailer("Library <library@a.com>", config.SMTPConfig{Host: "smtp.a.com", Port: 2525, Username: "user", Password: "pass"})
+
+			var gotAddr, gotFrom string
+			var gotTo []string
+			mailer.send = func(addr string, a smtp.Auth, from string, to []string, msg []byte) error {
+				gotAddr, gotFrom, gotTo = addr, from, to
+				return tt.sendErr
+			}
+
+			if err := mailer.Send("kaushik@a.com", "hi", "body"); (err != nil) != tt.wantErr {
+				t.Errorf("Send() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if gotAddr != "smtp.a.com:2525" || gotFrom != "library@a.com" || !reflect.DeepEqual(gotTo, []string{"kaushik@a.com"}) {
+				t.Errorf("Send() sent to %v from %v via %v", gotTo, gotFrom, gotAddr)
+			}
+			if mailer.auth == nil {
+				t.Errorf("NewSMTPMailer() auth not set with a username")
+			}
+		})
+	}
+}
+
+func TestFileMailer_Send(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "mail.log")
+	mailer := NewFileMailer("library@a.com", path)
+
+	for _, subject := range []string{"first", "second"} {
+		if err := mailer.Send("kaushik@a.com", subject, "body"); err != nil {
+			t.Fatalf("Send() error = %v", err)
+		}
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), "Subject: first") || !strings.Contains(string(data), "Subject: second") {
+		t.Errorf("Send() wrote %q, want both messages", data)
+	}
+}
+
+func TestLogMailer_Send(t *testing.T) {
+	var buf bytes.Buffer
+	mailer := NewLogMailer("library@a.com", log.New(&buf, "", 0))
+
+	if err := mailer.Send("kaushik@a.com", "hi", "https://a.com/reset?token=abc"); err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+	if !strings.Contains(buf.String(), "https://a.com/reset?token=abc") {
+		t.Errorf("Send() logged %q, want the body", buf.String())
+	}
+}
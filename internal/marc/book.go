@@ -0,0 +1,176 @@
+package marc
+
+import (
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/Kaushik1766/LibraryManagement/internal/models"
+	"github.com/google/uuid"
+)
+
+// FromBook builds the record of a title. 001 carries the title id, 020 the
+// isbn, 041 the language, 100 the author, 245 the title, 264 the publisher
+// and year, 520 the description and 650 the subject.
+func FromBook(book models.Book) Record {
+	rec := Record{Leader: DefaultLeader}
+
+	if book.ID != uuid.Nil {
+		rec.Fields = append(rec.Fields, Field{Tag: "001", Value: book.ID.String()})
+	}
+	rec.Fields = append(rec.Fields, Field{Tag: "008", Value: fixedData(book)})
+
+	if book.ISBN != "" {
+		rec.Fields = append(rec.Fields, dataField("020", ' ', ' ', 'a', book.ISBN))
+	}
+	if book.Language != "" {
+		rec.Fields = append(rec.Fields, dataField("041", ' ', ' ', 'a', book.Language))
+	}
+	rec.Fields = append(rec.Fields,
+		dataField("100", '1', ' ', 'a', book.Author),
+		dataField("245", '1', '0', 'a', book.Title),
+	)
+
+	if book.Publisher != "" || book.Year > 0 {
+		publication := Field{Tag: "264", Ind1: ' ', Ind2: '1'}
+		if book.Publisher != "" {
+			publication.Subfields = append(publication.Subfields, Subfield{Code: 'b', Value: book.Publisher})
+		}
+		if book.Year > 0 {
+			publication.Subfields = append(publication.Subfields, Subfield{Code: 'c', Value: strconv.Itoa(book.Year)})
+		}
+		rec.Fields = append(rec.Fields, publication)
+	}
+
+	if book.Description != "" {
+		rec.Fields = append(rec.Fields, dataField("520", ' ', ' ', 'a', book.Description))
+	}
+	if book.Subject != "" {
+		rec.Fields = append(rec.Fields, dataField("650", ' ', '4', 'a', book.Subject))
+	}
+
+	return rec
+}
+
+// fixedData is the 008 field, only the date and language positions are
+// filled in, the rest is left blank.
+func fixedData(book models.Book) string {
+	data := []byte(strings.Repeat(" ", 40))
+	if book.Year > 0 && book.Year <= 9999 {
+		data[6] = 's'
+		copy(data[7:11], strconv.Itoa(book.Year))
+	}
+	if len(book.Language) == 3 {
+		copy(data[35:38], strings.ToLower(book.Language))
+	}
+	return string(data)
+}
+
+func dataField(tag string, ind1, ind2, code byte, value string) Field {
+	return Field{Tag: tag, Ind1: ind1, Ind2: ind2, Subfields: []Subfield{{Code: code, Value: value}}}
+}
+
+// ToBook reads the core fields of a record. Records from other catalogs use
+// ISBD punctuation, which is stripped, and may describe publication in 260
+// instead of 264. A title has one subject so only the first 650 is kept.
+func ToBook(rec Record) models.Book {
+	var book models.Book
+
+	if field, ok := rec.Field("001"); ok {
+		book.ID, _ = uuid.Parse(strings.TrimSpace(field.Value))
+	}
+
+	title := trimISBD(rec.Subfield("245", 'a'))
+	if subtitle := trimISBD(rec.Subfield("245", 'b')); subtitle != "" {
+		title += ": " + subtitle
+	}
+	book.Title = title
+
+	book.Author = trimISBD(firstOf(rec.Subfield("100", 'a'), rec.Subfield("110", 'a'), rec.Subfield("111", 'a')))
+
+	// 020 $a may be followed by a qualifier such as "(pbk.)"
+	if fields := strings.Fields(rec.Subfield("020", 'a')); len(fields) > 0 {
+		book.ISBN = fields[0]
+	}
+
+	publication := publicationField(rec)
+	book.Publisher = trimISBD(publication.Subfield('b'))
+	book.Year = yearOf(publication.Subfield('c'))
+
+	fixed, _ := rec.Field("008")
+	if book.Year == 0 && len(fixed.Value) >= 11 {
+		book.Year = yearOf(fixed.Value[7:11])
+	}
+
+	book.Language = strings.TrimSpace(rec.Subfield("041", 'a'))
+	if book.Language == "" && len(fixed.Value) >= 38 {
+		book.Language = strings.Trim(fixed.Value[35:38], " |")
+	}
+
+	book.Subject = trimISBD(rec.Subfield("650", 'a'))
+	book.Description = strings.TrimSpace(rec.Subfield("520", 'a'))
+
+	return book
+}
+
+// publicationField prefers a 264 describing publication, then 260, then a
+// 264 for production, distribution or copyright.
+func publicationField(rec Record) Field {
+	var found Field
+	for _, field := range rec.Fields {
+		switch {
+		case field.Tag == "264" && field.Ind2 == '1':
+			return field
+		case field.Tag == "260" && found.Tag != "260":
+			found = field
+		case field.Tag == "264" && found.Tag == "":
+			found = field
+		}
+	}
+	return found
+}
+
+func firstOf(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+// trimISBD drops the punctuation cataloguers put at the end of subfields to
+// separate them, as in "Dune /" or "Chilton Books,".
+func trimISBD(value string) string {
+	value = strings.TrimSpace(value)
+	value = strings.TrimRight(value, " /:;,=")
+	// keep the period of initials like "Herbert, F."
+	if strings.HasSuffix(value, ".") && !hasInitialBefore(value) {
+		value = strings.TrimSuffix(value, ".")
+	}
+	return strings.TrimSpace(value)
+}
+
+func hasInitialBefore(value string) bool {
+	runes := []rune(strings.TrimSuffix(value, "."))
+	n := len(runes)
+	return n >= 2 && unicode.IsUpper(runes[n-1]) && !unicode.IsLetter(runes[n-2])
+}
+
+// yearOf finds the first four digit year in a date such as "c1965." or
+// "[2001?]".
+func yearOf(date string) int {
+	run := 0
+	for i, r := range date {
+		if r < '0' || r > '9' {
+			run = 0
+			continue
+		}
+		run++
+		if run == 4 {
+			year, _ := strconv.Atoi(date[i-3 : i+1])
+			return year
+		}
+	}
+	return 0
+}
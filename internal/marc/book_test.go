@@ -0,0 +1,93 @@
+package marc
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/Kaushik1766/LibraryManagement/internal/models"
+	"github.com/google/uuid"
+)
+
+func TestFromBook_RoundTrip(t *testing.T) {
+	book := models.Book{
+		ID:          uuid.New(),
+		Title:       "Dune",
+		Author:      "Herbert, Frank",
+		ISBN:        "0-441-17271-7",
+		Publisher:   "Chilton Books",
+		Year:        1965,
+		Language:    "eng",
+		Subject:     "Science fiction",
+		Description: "A desert planet.",
+	}
+
+	rec := FromBook(book)
+
+	fixed, _ := rec.Field("008")
+	if got := fixed.Value[7:11] + fixed.Value[35:38]; got != "1965eng" {
+		t.Errorf("008 date and language = %q, want %q", got, "1965eng")
+	}
+
+	if got := ToBook(rec); !reflect.DeepEqual(got, book) {
+		t.Errorf("ToBook(FromBook()) = %+v, want %+v", got, book)
+	}
+}
+
+func TestToBook(t *testing.T) {
+	tests := []struct {
+		name string
+		rec  Record
+		want models.Book
+	}{
+		{
+			name: "catalog record with isbd punctuation",
+			rec:  dune,
+			want: models.Book{
+				Title:   "Dune",
+				Author:  "Herbert, Frank",
+				ISBN:    "0-441-17271-7",
+				Subject: "Science fiction",
+			},
+		},
+		{
+			name: "260 publication and 008 language",
+			rec: Record{Fields: []Field{
+				{Tag: "008", Value: "920219s1992    nyu           000 1 fre d"},
+				{Tag: "020", Subfields: []Subfield{{Code: 'a', Value: "9780140439588 (pbk.)"}}},
+				{Tag: "110", Subfields: []Subfield{{Code: 'a', Value: "Austen Society."}}},
+				{Tag: "245", Subfields: []Subfield{{Code: 'a', Value: "Emma :"}, {Code: 'b', Value: "a novel /"}}},
+				{Tag: "260", Subfields: []Subfield{{Code: 'a', Value: "New York :"}, {Code: 'b', Value: "Penguin,"}, {Code: 'c', Value: "c1996."}}},
+				{Tag: "264", Ind2: '4', Subfields: []Subfield{{Code: 'c', Value: "©1815"}}},
+			}},
+			want: models.Book{
+				Title:     "Emma: a novel",
+				Author:    "Austen Society",
+				ISBN:      "9780140439588",
+				Publisher: "Penguin",
+				Year:      1996,
+				Language:  "fre",
+			},
+		},
+		{
+			name: "year from 008",
+			rec: Record{Fields: []Field{
+				{Tag: "008", Value: "920219s1992    nyu           000 1 eng d"},
+				{Tag: "100", Subfields: []Subfield{{Code: 'a', Value: "Tolkien, J. R. R."}}},
+				{Tag: "245", Subfields: []Subfield{{Code: 'a', Value: "The hobbit."}}},
+			}},
+			want: models.Book{
+				Title:    "The hobbit",
+				Author:   "Tolkien, J. R. R.",
+				Year:     1992,
+				Language: "eng",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ToBook(tt.rec); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ToBook() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
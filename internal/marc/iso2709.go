@@ -0,0 +1,200 @@
+package marc
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+const (
+	subfieldDelimiter = 0x1f
+	fieldTerminator   = 0x1e
+	recordTerminator  = 0x1d
+
+	leaderLength    = 24
+	directoryEntry  = 12
+	maxRecordLength = 99999
+)
+
+type isoEncoder struct {
+	w io.Writer
+}
+
+func newISOEncoder(w io.Writer) *isoEncoder {
+	return &isoEncoder{w: w}
+}
+
+// Encode writes rec in ISO 2709 framing, recomputing the record length and
+// base address in its leader.
+func (enc *isoEncoder) Encode(rec Record) error {
+	var directory, data bytes.Buffer
+
+	for _, field := range rec.Fields {
+		if len(field.Tag) != 3 {
+			return fmt.Errorf("%w: tag %q is not three characters", ErrInvalidRecord, field.Tag)
+		}
+
+		start := data.Len()
+		if field.IsControl() {
+			data.WriteString(field.Value)
+		} else {
+			data.WriteByte(indicator(field.Ind1))
+			data.WriteByte(indicator(field.Ind2))
+			for _, sub := range field.Subfields {
+				data.WriteByte(subfieldDelimiter)
+				data.WriteByte(sub.Code)
+				data.WriteString(sub.Value)
+			}
+		}
+		data.WriteByte(fieldTerminator)
+
+		length := data.Len() - start
+		if length > 9999 {
+			return fmt.Errorf("%w: field %s is too long", ErrInvalidRecord, field.Tag)
+		}
+		fmt.Fprintf(&directory, "%s%04d%05d", field.Tag, length, start)
+	}
+	directory.WriteByte(fieldTerminator)
+
+	base := leaderLength + directory.Len()
+	total := base + data.Len() + 1
+	if total > maxRecordLength {
+		return fmt.Errorf("%w: record is longer than %d bytes", ErrInvalidRecord, maxRecordLength)
+	}
+
+	leader := []byte(normaliseLeader(rec.Leader))
+	copy(leader[0:5], fmt.Sprintf("%05d", total))
+	copy(leader[12:17], fmt.Sprintf("%05d", base))
+
+	out := make([]byte, 0, total)
+	out = append(out, leader...)
+	out = append(out, directory.Bytes()...)
+	out = append(out, data.Bytes()...)
+	out = append(out, recordTerminator)
+
+	_, err := enc.w.Write(out)
+	return err
+}
+
+func (enc *isoEncoder) Close() error {
+	return nil
+}
+
+type isoDecoder struct {
+	r *bufio.Reader
+}
+
+func newISODecoder(r io.Reader) *isoDecoder {
+	return &isoDecoder{r: bufio.NewReader(r)}
+}
+
+// Decode reads the next record. The record length at the start of the leader
+// is what finds the next record, so a damaged length ends the stream while
+// damage anywhere else only loses the one record.
+func (dec *isoDecoder) Decode() (Record, error) {
+	// files often separate records with line breaks
+	for {
+		b, err := dec.r.ReadByte()
+		if err != nil {
+			return Record{}, err
+		}
+		if b != '\n' && b != '\r' {
+			dec.r.UnreadByte()
+			break
+		}
+	}
+
+	head := make([]byte, 5)
+	if _, err := io.ReadFull(dec.r, head); err != nil {
+		return Record{}, fmt.Errorf("truncated record: %w", err)
+	}
+	length, err := strconv.Atoi(string(head))
+	if err != nil || length < leaderLength+1 {
+		return Record{}, fmt.Errorf("invalid record length %q", head)
+	}
+
+	buf := make([]byte, length)
+	copy(buf, head)
+	if _, err := io.ReadFull(dec.r, buf[5:]); err != nil {
+		if errors.Is(err, io.EOF) {
+			err = io.ErrUnexpectedEOF
+		}
+		return Record{}, fmt.Errorf("truncated record: %w", err)
+	}
+
+	return parseISO(buf)
+}
+
+func parseISO(buf []byte) (Record, error) {
+	if buf[len(buf)-1] != recordTerminator {
+		return Record{}, fmt.Errorf("%w: missing record terminator", ErrInvalidRecord)
+	}
+
+	rec := Record{Leader: string(buf[:leaderLength])}
+
+	base, err := strconv.Atoi(rec.Leader[12:17])
+	if err != nil || base <= leaderLength || base > len(buf) || buf[base-1] != fieldTerminator {
+		return Record{}, fmt.Errorf("%w: invalid base address", ErrInvalidRecord)
+	}
+
+	directory := buf[leaderLength : base-1]
+	if len(directory)%directoryEntry != 0 {
+		return Record{}, fmt.Errorf("%w: invalid directory", ErrInvalidRecord)
+	}
+
+	data := buf[base:]
+	for i := 0; i < len(directory); i += directoryEntry {
+		entry := directory[i : i+directoryEntry]
+		tag := string(entry[0:3])
+		length, err1 := strconv.Atoi(string(entry[3:7]))
+		start, err2 := strconv.Atoi(string(entry[7:12]))
+		if err1 != nil || err2 != nil || length < 1 || start+length > len(data) {
+			return Record{}, fmt.Errorf("%w: invalid directory entry for %s", ErrInvalidRecord, tag)
+		}
+
+		content := bytes.TrimSuffix(data[start:start+length], []byte{fieldTerminator})
+		field := Field{Tag: tag}
+
+		if field.IsControl() {
+			field.Value = string(content)
+			rec.Fields = append(rec.Fields, field)
+			continue
+		}
+
+		if len(content) < 2 {
+			return Record{}, fmt.Errorf("%w: field %s has no indicators", ErrInvalidRecord, tag)
+		}
+		field.Ind1, field.Ind2 = content[0], content[1]
+		for _, part := range bytes.Split(content[2:], []byte{subfieldDelimiter}) {
+			if len(part) == 0 {
+				continue
+			}
+			field.Subfields = append(field.Subfields, Subfield{Code: part[0], Value: string(part[1:])})
+		}
+		rec.Fields = append(rec.Fields, field)
+	}
+
+	return rec, nil
+}
+
+// normaliseLeader pads or cuts a leader to 24 characters and fixes the parts
+// that describe the framing, which every MARC 21 record shares.
+func normaliseLeader(leader string) string {
+	if leader == "" {
+		leader = DefaultLeader
+	}
+	out := []byte(fmt.Sprintf("%-24.24s", leader))
+	out[10], out[11] = '2', '2'
+	copy(out[20:24], "4500")
+	return string(out)
+}
+
+func indicator(b byte) byte {
+	if b == 0 {
+		return ' '
+	}
+	return b
+}
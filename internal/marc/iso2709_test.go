@@ -0,0 +1,104 @@
+package marc
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+var dune = Record{
+	Leader: "00000cam a2200000 i 4500",
+	Fields: []Field{
+		{Tag: "001", Value: "ocm00123"},
+		{Tag: "020", Ind1: ' ', Ind2: ' ', Subfields: []Subfield{{Code: 'a', Value: "0-441-17271-7"}}},
+		{Tag: "100", Ind1: '1', Ind2: ' ', Subfields: []Subfield{{Code: 'a', Value: "Herbert, Frank."}}},
+		{Tag: "245", Ind1: '1', Ind2: '0', Subfields: []Subfield{{Code: 'a', Value: "Dune /"}, {Code: 'c', Value: "Frank Herbert."}}},
+		{Tag: "650", Ind1: ' ', Ind2: '0', Subfields: []Subfield{{Code: 'a', Value: "Science fiction."}, {Code: 'a', Value: "Ökologie"}}},
+	},
+}
+
+func TestISO2709_RoundTrip(t *testing.T) {
+	var buf bytes.Buffer
+	enc := NewEncoder(ISO2709, &buf)
+	for i := 0; i < 2; i++ {
+		if err := enc.Encode(dune); err != nil {
+			t.Fatalf("Encode() error = %v", err)
+		}
+	}
+	if err := enc.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	raw := buf.Bytes()
+	if got := string(raw[:5]); got != "00192" {
+		t.Errorf("record length = %v, want 00192", got)
+	}
+	if got := string(raw[12:17]); got != "00085" {
+		t.Errorf("base address = %v, want 00085", got)
+	}
+
+	dec := NewDecoder(ISO2709, &buf)
+	for i := 0; i < 2; i++ {
+		got, err := dec.Decode()
+		if err != nil {
+			t.Fatalf("Decode() error = %v", err)
+		}
+		want := dune
+		want.Leader = "00192cam a2200085 i 4500"
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("Decode() got = %+v, want %+v", got, want)
+		}
+	}
+	if _, err := dec.Decode(); !errors.Is(err, io.EOF) {
+		t.Errorf("Decode() after last record error = %v, want EOF", err)
+	}
+}
+
+func TestISO2709_Decode(t *testing.T) {
+	var good bytes.Buffer
+	NewEncoder(ISO2709, &good).Encode(dune)
+
+	// same length, but the terminator is gone so only this record is lost
+	damaged := append([]byte{}, good.Bytes()...)
+	damaged[len(damaged)-1] = ' '
+
+	tests := []struct {
+		name    string
+		data    []byte
+		wantErr error
+		fatal   bool
+	}{
+		{name: "good record", data: good.Bytes()},
+		{name: "damaged record", data: damaged, wantErr: ErrInvalidRecord},
+		{name: "bad length", data: []byte("abcde" + strings.Repeat(" ", 30)), fatal: true},
+		{name: "truncated", data: good.Bytes()[:100], fatal: true},
+		{name: "empty", data: nil, wantErr: io.EOF},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewDecoder(ISO2709, bytes.NewReader(append([]byte("\n"), tt.data...))).Decode()
+			switch {
+			case tt.fatal:
+				if err == nil || errors.Is(err, ErrInvalidRecord) {
+					t.Errorf("Decode() error = %v, want an error ending the stream", err)
+				}
+			case !errors.Is(err, tt.wantErr):
+				t.Errorf("Decode() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestISO2709_EncodeInvalid(t *testing.T) {
+	rec := Record{Fields: []Field{{Tag: "24", Subfields: []Subfield{{Code: 'a', Value: "Dune"}}}}}
+	var buf bytes.Buffer
+	if err := NewEncoder(ISO2709, &buf).Encode(rec); !errors.Is(err, ErrInvalidRecord) {
+		t.Errorf("Encode() error = %v, want %v", err, ErrInvalidRecord)
+	}
+	if buf.Len() != 0 {
+		t.Errorf("Encode() wrote %d bytes of an invalid record", buf.Len())
+	}
+}
@@ -0,0 +1,135 @@
+package marc
+
+import (
+	"errors"
+	"io"
+	"strings"
+
+	apperrors "github.com/Kaushik1766/LibraryManagement/internal/app_errors"
+)
+
+// DefaultLeader is the leader of a new bibliographic record: a new, unicode
+// encoded monograph. Lengths and the base address are filled in on encoding.
+const DefaultLeader = "00000nam a2200000   4500"
+
+// ErrInvalidRecord is wrapped by decoding errors confined to one record, the
+// decoder can carry on with the next.
+var ErrInvalidRecord = errors.New("invalid marc record")
+
+// Record is a MARC 21 bibliographic record.
+type Record struct {
+	Leader string
+	Fields []Field
+}
+
+// Field is a variable field. Control fields (001-009) only have a Value,
+// data fields have indicators and subfields.
+type Field struct {
+	Tag       string
+	Value     string
+	Ind1      byte
+	Ind2      byte
+	Subfields []Subfield
+}
+
+type Subfield struct {
+	Code  byte
+	Value string
+}
+
+func (field Field) IsControl() bool {
+	return strings.HasPrefix(field.Tag, "00")
+}
+
+// Subfield returns the first subfield with code, or "".
+func (field Field) Subfield(code byte) string {
+	for _, sub := range field.Subfields {
+		if sub.Code == code {
+			return sub.Value
+		}
+	}
+	return ""
+}
+
+// Field returns the first field with tag.
+func (rec Record) Field(tag string) (Field, bool) {
+	for _, field := range rec.Fields {
+		if field.Tag == tag {
+			return field, true
+		}
+	}
+	return Field{}, false
+}
+
+// Subfield returns the first subfield code of the first field with tag that
+// has one, or "".
+func (rec Record) Subfield(tag string, code byte) string {
+	for _, field := range rec.Fields {
+		if field.Tag != tag {
+			continue
+		}
+		if value := field.Subfield(code); value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+// Format is a serialisation of MARC records.
+type Format string
+
+const (
+	ISO2709 Format = "marc"
+	XML     Format = "marcxml"
+)
+
+// FormatOf resolves a format name, file extension or content type.
+func FormatOf(name string) (Format, error) {
+	switch strings.ToLower(strings.TrimPrefix(name, ".")) {
+	case "marc", "mrc", "iso2709", "application/marc":
+		return ISO2709, nil
+	case "marcxml", "xml", "application/marcxml+xml", "application/xml", "text/xml":
+		return XML, nil
+	}
+	return "", apperrors.Validation("invalid_format", "invalid marc format").WithDetail("format", "must be marc or marcxml")
+}
+
+func (format Format) ContentType() string {
+	if format == ISO2709 {
+		return "application/marc"
+	}
+	return "application/marcxml+xml"
+}
+
+func (format Format) Extension() string {
+	if format == ISO2709 {
+		return ".mrc"
+	}
+	return ".xml"
+}
+
+// Encoder writes records one at a time. Close finishes the stream and must
+// be called even when no record was written.
+type Encoder interface {
+	Encode(rec Record) error
+	Close() error
+}
+
+// Decoder reads records one at a time, returning io.EOF after the last.
+type Decoder interface {
+	Decode() (Record, error)
+}
+
+func NewEncoder(format Format, w io.Writer) Encoder {
+	if format == ISO2709 {
+		return newISOEncoder(w)
+	}
+	return newXMLEncoder(w)
+}
+
+func NewDecoder(format Format, r io.Reader) Decoder {
+	if format == ISO2709 {
+		return newISODecoder(r)
+	}
+	return newXMLDecoder(r)
+}
@@ -0,0 +1,158 @@
+package marc
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+)
+
+// Namespace is the MARCXML slim schema namespace.
+const Namespace = "http://www.loc.gov/MARC21/slim"
+
+type xmlRecord struct {
+	XMLName       xml.Name          `xml:"record"`
+	Leader        string            `xml:"leader"`
+	ControlFields []xmlControlField `xml:"controlfield"`
+	DataFields    []xmlDataField    `xml:"datafield"`
+}
+
+type xmlControlField struct {
+	Tag   string `xml:"tag,attr"`
+	Value string `xml:",chardata"`
+}
+
+type xmlDataField struct {
+	Tag       string        `xml:"tag,attr"`
+	Ind1      string        `xml:"ind1,attr"`
+	Ind2      string        `xml:"ind2,attr"`
+	Subfields []xmlSubfield `xml:"subfield"`
+}
+
+type xmlSubfield struct {
+	Code  string `xml:"code,attr"`
+	Value string `xml:",chardata"`
+}
+
+// xmlEncoder writes a collection. Nothing is written until the first record
+// or Close, so a caller can still report an error in another format.
+type xmlEncoder struct {
+	w       io.Writer
+	enc     *xml.Encoder
+	started bool
+}
+
+func newXMLEncoder(w io.Writer) *xmlEncoder {
+	enc := xml.NewEncoder(w)
+	enc.Indent("  ", "  ")
+	return &xmlEncoder{w: w, enc: enc}
+}
+
+func (enc *xmlEncoder) start() error {
+	if enc.started {
+		return nil
+	}
+	enc.started = true
+	_, err := fmt.Fprintf(enc.w, "%s<collection xmlns=%q>", xml.Header, Namespace)
+	return err
+}
+
+func (enc *xmlEncoder) Encode(rec Record) error {
+	out := xmlRecord{Leader: normaliseLeader(rec.Leader)}
+	for _, field := range rec.Fields {
+		if len(field.Tag) != 3 {
+			return fmt.Errorf("%w: tag %q is not three characters", ErrInvalidRecord, field.Tag)
+		}
+		if field.IsControl() {
+			out.ControlFields = append(out.ControlFields, xmlControlField{Tag: field.Tag, Value: field.Value})
+			continue
+		}
+
+		data := xmlDataField{
+			Tag:  field.Tag,
+			Ind1: string(indicator(field.Ind1)),
+			Ind2: string(indicator(field.Ind2)),
+		}
+		for _, sub := range field.Subfields {
+			data.Subfields = append(data.Subfields, xmlSubfield{Code: string(sub.Code), Value: sub.Value})
+		}
+		out.DataFields = append(out.DataFields, data)
+	}
+
+	if err := enc.start(); err != nil {
+		return err
+	}
+	return enc.enc.Encode(out)
+}
+
+func (enc *xmlEncoder) Close() error {
+	if err := enc.start(); err != nil {
+		return err
+	}
+	_, err := io.WriteString(enc.w, "\n</collection>\n")
+	return err
+}
+
+// xmlDecoder reads the records of a collection, or a document holding a
+// single record.
+type xmlDecoder struct {
+	dec *xml.Decoder
+}
+
+func newXMLDecoder(r io.Reader) *xmlDecoder {
+	return &xmlDecoder{dec: xml.NewDecoder(r)}
+}
+
+func (dec *xmlDecoder) Decode() (Record, error) {
+	for {
+		token, err := dec.dec.Token()
+		if err != nil {
+			return Record{}, err
+		}
+
+		start, ok := token.(xml.StartElement)
+		if !ok || start.Name.Local != "record" {
+			continue
+		}
+
+		var in xmlRecord
+		if err := dec.dec.DecodeElement(&in, &start); err != nil {
+			return Record{}, err
+		}
+		return fromXML(in)
+	}
+}
+
+func fromXML(in xmlRecord) (Record, error) {
+	rec := Record{Leader: in.Leader}
+
+	for _, control := range in.ControlFields {
+		if len(control.Tag) != 3 {
+			return Record{}, fmt.Errorf("%w: tag %q is not three characters", ErrInvalidRecord, control.Tag)
+		}
+		rec.Fields = append(rec.Fields, Field{Tag: control.Tag, Value: control.Value})
+	}
+
+	for _, data := range in.DataFields {
+		if len(data.Tag) != 3 || len(data.Ind1) > 1 || len(data.Ind2) > 1 {
+			return Record{}, fmt.Errorf("%w: invalid datafield %q", ErrInvalidRecord, data.Tag)
+		}
+
+		field := Field{Tag: data.Tag, Ind1: firstByte(data.Ind1), Ind2: firstByte(data.Ind2)}
+		for _, sub := range data.Subfields {
+			if len(sub.Code) != 1 {
+				return Record{}, fmt.Errorf("%w: invalid subfield code %q in %s", ErrInvalidRecord, sub.Code, data.Tag)
+			}
+			field.Subfields = append(field.Subfields, Subfield{Code: sub.Code[0], Value: sub.Value})
+		}
+		rec.Fields = append(rec.Fields, field)
+	}
+
+	return rec, nil
+}
+
+func firstByte(s string) byte {
+	if s == "" {
+		return ' '
+	}
+	return s[0]
+}
@@ -0,0 +1,104 @@
+package marc
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestXML_RoundTrip(t *testing.T) {
+	var buf bytes.Buffer
+	enc := NewEncoder(XML, &buf)
+	if err := enc.Encode(dune); err != nil {
+		t.Fatalf("Encode() error = %v", err)
+	}
+	if err := enc.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	if !strings.Contains(buf.String(), `<collection xmlns="http://www.loc.gov/MARC21/slim">`) {
+		t.Errorf("Encode() output has no collection element:\n%s", buf.String())
+	}
+
+	dec := NewDecoder(XML, &buf)
+	got, err := dec.Decode()
+	if err != nil {
+		t.Fatalf("Decode() error = %v", err)
+	}
+	if !reflect.DeepEqual(got, dune) {
+		t.Errorf("Decode() got = %+v, want %+v", got, dune)
+	}
+	if _, err := dec.Decode(); !errors.Is(err, io.EOF) {
+		t.Errorf("Decode() after last record error = %v, want EOF", err)
+	}
+}
+
+func TestXML_EmptyCollection(t *testing.T) {
+	var buf bytes.Buffer
+	if err := NewEncoder(XML, &buf).Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	if _, err := NewDecoder(XML, &buf).Decode(); !errors.Is(err, io.EOF) {
+		t.Errorf("Decode() error = %v, want EOF", err)
+	}
+}
+
+func TestXML_Decode(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		want    Record
+		wantErr error
+		fatal   bool
+	}{
+		{
+			name: "single record document",
+			data: `<?xml version="1.0"?>
+<marc:record xmlns:marc="http://www.loc.gov/MARC21/slim">
+  <marc:leader>01142cam  2200301 a 4500</marc:leader>
+  <marc:controlfield tag="001">92005291</marc:controlfield>
+  <marc:datafield tag="245" ind1="1" ind2="0">
+    <marc:subfield code="a">Emma /</marc:subfield>
+  </marc:datafield>
+</marc:record>`,
+			want: Record{
+				Leader: "01142cam  2200301 a 4500",
+				Fields: []Field{
+					{Tag: "001", Value: "92005291"},
+					{Tag: "245", Ind1: '1', Ind2: '0', Subfields: []Subfield{{Code: 'a', Value: "Emma /"}}},
+				},
+			},
+		},
+		{
+			name:    "bad subfield code",
+			data:    `<collection><record><datafield tag="245" ind1="1" ind2="0"><subfield code="ab">Emma</subfield></datafield></record></collection>`,
+			wantErr: ErrInvalidRecord,
+		},
+		{
+			name:  "not xml",
+			data:  `<collection><record>`,
+			fatal: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewDecoder(XML, strings.NewReader(tt.data)).Decode()
+			switch {
+			case tt.fatal:
+				if err == nil || errors.Is(err, ErrInvalidRecord) {
+					t.Errorf("Decode() error = %v, want an error ending the stream", err)
+				}
+				return
+			case !errors.Is(err, tt.wantErr):
+				t.Errorf("Decode() error = %v, want %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr == nil && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Decode() got = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
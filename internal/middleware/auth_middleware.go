@@ -6,19 +6,46 @@ import (
 	"net/http"
 	"time"
 
-	"github.com/Kaushik1766/LibraryManagement/internal/config"
+	jwtkeys "github.com/Kaushik1766/LibraryManagement/internal/jwt_keys"
 	"github.com/Kaushik1766/LibraryManagement/internal/models"
 	weberrors "github.com/Kaushik1766/LibraryManagement/internal/web_errors"
 	"github.com/golang-jwt/jwt/v5"
 )
 
-func ParseToken(token string) (context.Context, error) {
+// RevocationList reports access tokens that were revoked before they expired.
+type RevocationList interface {
+	IsRevoked(jti string) (bool, error)
+}
+
+// Authenticator verifies bearer tokens signed with one of keys and issued by
+// issuer for audience. When revocations is set every token must carry a jti
+// that isn't on it.
+type Authenticator struct {
+	keys        *jwtkeys.KeySet
+	issuer      string
+	audience    string
+	revocations RevocationList
+}
+
+func NewAuthenticator(keys *jwtkeys.KeySet, issuer, audience string, revocations RevocationList) *Authenticator {
+	return &Authenticator{
+		keys:        keys,
+		issuer:      issuer,
+		audience:    audience,
+		revocations: revocations,
+	}
+}
+
+func (auth *Authenticator) ParseToken(token string) (context.Context, error) {
 
 	var userJwt models.UserJwt
 
-	parsedToken, err := jwt.ParseWithClaims(token, &userJwt, func(token *jwt.Token) (any, error) {
-		return []byte(config.JWTSecret), nil
-	})
+	parsedToken, err := jwt.ParseWithClaims(token, &userJwt, auth.keys.Keyfunc,
+		jwt.WithValidMethods(auth.keys.Algorithms()),
+		jwt.WithIssuer(auth.issuer),
+		jwt.WithAudience(auth.audience),
+		jwt.WithExpirationRequired(),
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -31,10 +58,23 @@ func ParseToken(token string) (context.Context, error) {
 		return nil, errors.New("token expired")
 	}
 
+	if auth.revocations != nil {
+		if userJwt.ID == "" {
+			return nil, errors.New("invalid token")
+		}
+		revoked, err := auth.revocations.IsRevoked(userJwt.ID)
+		if err != nil {
+			return nil, err
+		}
+		if revoked {
+			return nil, errors.New("token revoked")
+		}
+	}
+
 	return context.WithValue(context.Background(), "user", userJwt), nil
 }
 
-func AuthMiddleware(next func(ctx context.Context, w http.ResponseWriter, r *http.Request)) func(w http.ResponseWriter, r *http.Request) {
+func (auth *Authenticator) AuthMiddleware(next func(ctx context.Context, w http.ResponseWriter, r *http.Request)) func(w http.ResponseWriter, r *http.Request) {
 	return func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Content-Type", "application/json")
 
@@ -51,7 +91,7 @@ func AuthMiddleware(next func(ctx context.Context, w http.ResponseWriter, r *htt
 
 		jwtToken := token[7:]
 
-		ctx, err := ParseToken(jwtToken)
+		ctx, err := auth.ParseToken(jwtToken)
 		if err != nil {
 			weberrors.SendError(err, http.StatusUnauthorized, w)
 			return
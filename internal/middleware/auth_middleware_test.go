@@ -2,19 +2,58 @@ package middleware
 
 import (
 	"context"
+	"crypto/ed25519"
+	"crypto/rand"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"reflect"
 	"testing"
 	"time"
 
-	"github.com/Kaushik1766/LibraryManagement/internal/config"
+	jwtkeys "github.com/Kaushik1766/LibraryManagement/internal/jwt_keys"
 	"github.com/Kaushik1766/LibraryManagement/internal/models"
 	weberrors "github.com/Kaushik1766/LibraryManagement/internal/web_errors"
+	"github.com/Kaushik1766/LibraryManagement/mocks"
 	"github.com/golang-jwt/jwt/v5"
+	"go.uber.org/mock/gomock"
 )
 
+const (
+	testIssuer   = "library"
+	testAudience = "library-api"
+)
+
+var (
+	testKeys  = newTestKeys("current")
+	otherKeys = newTestKeys("current")
+)
+
+func newTestKeys(id string) *jwtkeys.KeySet {
+	_, private, _ := ed25519.GenerateKey(rand.Reader)
+	key, _ := jwtkeys.NewKey(id, private)
+	keys, _ := jwtkeys.NewKeySet(id, key)
+	return keys
+}
+
+// signTestToken signs claims the way the auth service does, adding the
+// issuer and audience when claims has none.
+func signTestToken(keys *jwtkeys.KeySet, claims models.UserJwt) string {
+	if claims.Issuer == "" {
+		claims.Issuer = testIssuer
+	}
+	if claims.Audience == nil {
+		claims.Audience = jwt.ClaimStrings{testAudience}
+	}
+	token, _ := keys.Sign(claims)
+	return token
+}
+
+func newTestAuthenticator(revocations RevocationList) *Authenticator {
+	return NewAuthenticator(testKeys, testIssuer, testAudience, revocations)
+}
+
 func TestParseToken(t *testing.T) {
 	type args struct {
 		token string
@@ -35,13 +74,10 @@ func TestParseToken(t *testing.T) {
 			name: "wrong signature",
 			args: args{
 				token: func() string {
-					claims := jwt.NewWithClaims(jwt.SigningMethodHS256, models.UserJwt{
+					return signTestToken(otherKeys, models.UserJwt{
 						Email: "kaushik@a.com",
 						Role:  0,
 					})
-
-					signedToken, _ := claims.SignedString([]byte("ddafs"))
-					return signedToken
 				}(),
 			},
 			want:    nil,
@@ -51,16 +87,13 @@ func TestParseToken(t *testing.T) {
 			name: "expired token",
 			args: args{
 				token: func() string {
-					claims := jwt.NewWithClaims(jwt.SigningMethodHS256, models.UserJwt{
+					return signTestToken(testKeys, models.UserJwt{
 						Email: "kaushik@a.com",
 						Role:  0,
 						RegisteredClaims: jwt.RegisteredClaims{
 							ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Minute * -10)),
 						},
 					})
-
-					signedToken, _ := claims.SignedString([]byte(config.JWTSecret))
-					return signedToken
 				}(),
 			},
 			want:    nil,
@@ -70,31 +103,83 @@ func TestParseToken(t *testing.T) {
 			name: "valid token",
 			args: args{
 				token: func() string {
-					claims := jwt.NewWithClaims(jwt.SigningMethodHS256, models.UserJwt{
+					return signTestToken(testKeys, models.UserJwt{
 						Email: "kaushik@a.com",
 						Role:  0,
 						RegisteredClaims: jwt.RegisteredClaims{
 							ExpiresAt: jwt.NewNumericDate(time.Date(10000, 1, 1, 1, 1, 1, 1, time.Local)),
 						},
 					})
-
-					signedToken, _ := claims.SignedString([]byte(config.JWTSecret))
-					return signedToken
 				}(),
 			},
 			want: context.WithValue(context.Background(), "user", models.UserJwt{
 				Email: "kaushik@a.com",
 				Role:  0,
 				RegisteredClaims: jwt.RegisteredClaims{
+					Issuer:    testIssuer,
+					Audience:  jwt.ClaimStrings{testAudience},
 					ExpiresAt: jwt.NewNumericDate(time.Date(10000, 1, 1, 1, 1, 1, 1, time.Local)),
 				},
 			}),
 			wantErr: false,
 		},
+		{
+			name: "another issuer",
+			args: args{
+				token: signTestToken(testKeys, models.UserJwt{
+					RegisteredClaims: jwt.RegisteredClaims{
+						Issuer:    "someone-else",
+						ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+					},
+				}),
+			},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "another audience",
+			args: args{
+				token: signTestToken(testKeys, models.UserJwt{
+					RegisteredClaims: jwt.RegisteredClaims{
+						Audience:  jwt.ClaimStrings{"billing-api"},
+						ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+					},
+				}),
+			},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "no expiry",
+			args: args{
+				token: signTestToken(testKeys, models.UserJwt{Email: "kaushik@a.com"}),
+			},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "hmac token",
+			args: args{
+				token: func() string {
+					token := jwt.NewWithClaims(jwt.SigningMethodHS256, models.UserJwt{
+						RegisteredClaims: jwt.RegisteredClaims{
+							Issuer:    testIssuer,
+							Audience:  jwt.ClaimStrings{testAudience},
+							ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+						},
+					})
+					token.Header["kid"] = "current"
+					signedToken, _ := token.SignedString([]byte("middleware-test-secret"))
+					return signedToken
+				}(),
+			},
+			want:    nil,
+			wantErr: true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got, err := ParseToken(tt.args.token)
+			got, err := newTestAuthenticator(nil).ParseToken(tt.args.token)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("ParseToken() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -108,36 +193,30 @@ func TestParseToken(t *testing.T) {
 
 func TestAuthMiddleware(t *testing.T) {
 	createValidToken := func() string {
-		claims := jwt.NewWithClaims(jwt.SigningMethodHS256, models.UserJwt{
+		return signTestToken(testKeys, models.UserJwt{
 			Email: "test@example.com",
 			Role:  0,
 			RegisteredClaims: jwt.RegisteredClaims{
 				ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
 			},
 		})
-		token, _ := claims.SignedString([]byte(config.JWTSecret))
-		return token
 	}
 
 	createExpiredToken := func() string {
-		claims := jwt.NewWithClaims(jwt.SigningMethodHS256, models.UserJwt{
+		return signTestToken(testKeys, models.UserJwt{
 			Email: "test@example.com",
 			Role:  0,
 			RegisteredClaims: jwt.RegisteredClaims{
 				ExpiresAt: jwt.NewNumericDate(time.Now().Add(-time.Hour)),
 			},
 		})
-		token, _ := claims.SignedString([]byte(config.JWTSecret))
-		return token
 	}
 
 	createInvalidToken := func() string {
-		claims := jwt.NewWithClaims(jwt.SigningMethodHS256, models.UserJwt{
+		return signTestToken(otherKeys, models.UserJwt{
 			Email: "test@example.com",
 			Role:  0,
 		})
-		token, _ := claims.SignedString([]byte("wrong-secret"))
-		return token
 	}
 
 	tests := []struct {
@@ -198,7 +277,7 @@ func TestAuthMiddleware(t *testing.T) {
 			},
 			nextCalled:     false,
 			expectedStatus: http.StatusUnauthorized,
-			expectedBody:   "token signature is invalid: signature is invalid",
+			expectedBody:   "token signature is invalid: ed25519: verification error",
 		},
 		{
 			name: "expired JWT token",
@@ -240,7 +319,7 @@ func TestAuthMiddleware(t *testing.T) {
 				w.Write([]byte("next handler called"))
 			}
 
-			middleware := AuthMiddleware(nextHandler)
+			middleware := newTestAuthenticator(nil).AuthMiddleware(nextHandler)
 			middleware(w, r)
 
 			if nextCalled != tt.nextCalled {
@@ -282,3 +361,68 @@ func TestAuthMiddleware(t *testing.T) {
 		})
 	}
 }
+
+func TestParseToken_Revocation(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSessionRepo := mocks.NewMockSessionStorage(ctrl)
+	auth := newTestAuthenticator(mockSessionRepo)
+
+	signToken := func(jti string) string {
+		return signTestToken(testKeys, models.UserJwt{
+			Email: "kaushik@a.com",
+			RegisteredClaims: jwt.RegisteredClaims{
+				ID:        jti,
+				ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+			},
+		})
+	}
+
+	tests := []struct {
+		name      string
+		token     string
+		wantErr   bool
+		mockSetup func()
+	}{
+		{
+			name:    "token not revoked",
+			token:   signToken("550e8400-e29b-41d4-a716-446655440000"),
+			wantErr: false,
+			mockSetup: func() {
+				mockSessionRepo.EXPECT().IsRevoked("550e8400-e29b-41d4-a716-446655440000").Return(false, nil)
+			},
+		},
+		{
+			name:    "revoked token",
+			token:   signToken("550e8400-e29b-41d4-a716-446655440000"),
+			wantErr: true,
+			mockSetup: func() {
+				mockSessionRepo.EXPECT().IsRevoked("550e8400-e29b-41d4-a716-446655440000").Return(true, nil)
+			},
+		},
+		{
+			name:      "token without jti",
+			token:     signToken(""),
+			wantErr:   true,
+			mockSetup: func() {},
+		},
+		{
+			name:    "revocation list unavailable",
+			token:   signToken("550e8400-e29b-41d4-a716-446655440000"),
+			wantErr: true,
+			mockSetup: func() {
+				mockSessionRepo.EXPECT().IsRevoked("550e8400-e29b-41d4-a716-446655440000").Return(false, errors.New("db error"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockSetup()
+			_, err := auth.ParseToken(tt.token)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseToken() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
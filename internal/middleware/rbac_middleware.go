@@ -0,0 +1,30 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+
+	apperrors "github.com/Kaushik1766/LibraryManagement/internal/app_errors"
+	"github.com/Kaushik1766/LibraryManagement/internal/models"
+	"github.com/Kaushik1766/LibraryManagement/internal/rbac"
+	weberrors "github.com/Kaushik1766/LibraryManagement/internal/web_errors"
+)
+
+// RequirePermission rejects requests whose user lacks perm before they reach
+// next. It expects the context built by AuthMiddleware.
+func RequirePermission(perm rbac.Permission, next func(ctx context.Context, w http.ResponseWriter, r *http.Request)) func(ctx context.Context, w http.ResponseWriter, r *http.Request) {
+	return func(ctx context.Context, w http.ResponseWriter, r *http.Request) {
+		userCtx, ok := ctx.Value("user").(models.UserJwt)
+		if !ok {
+			weberrors.SendError(apperrors.ErrInvalidUser, http.StatusUnauthorized, w)
+			return
+		}
+
+		if !rbac.Can(userCtx.Role, perm) {
+			weberrors.SendError(apperrors.Forbidden("forbidden", "forbidden"), http.StatusForbidden, w)
+			return
+		}
+
+		next(ctx, w, r)
+	}
+}
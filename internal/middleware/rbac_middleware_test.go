@@ -0,0 +1,64 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Kaushik1766/LibraryManagement/internal/models"
+	"github.com/Kaushik1766/LibraryManagement/internal/models/enums/roles"
+	"github.com/Kaushik1766/LibraryManagement/internal/rbac"
+)
+
+func TestRequirePermission(t *testing.T) {
+	tests := []struct {
+		name           string
+		ctx            context.Context
+		perm           rbac.Permission
+		expectedStatus int
+		wantCalled     bool
+	}{
+		{
+			name:           "permitted role",
+			ctx:            context.WithValue(context.Background(), "user", models.UserJwt{Role: roles.Librarian}),
+			perm:           rbac.BookCreate,
+			expectedStatus: http.StatusOK,
+			wantCalled:     true,
+		},
+		{
+			name:           "forbidden role",
+			ctx:            context.WithValue(context.Background(), "user", models.UserJwt{Role: roles.Customer}),
+			perm:           rbac.BookCreate,
+			expectedStatus: http.StatusForbidden,
+			wantCalled:     false,
+		},
+		{
+			name:           "missing user",
+			ctx:            context.Background(),
+			perm:           rbac.BookCreate,
+			expectedStatus: http.StatusUnauthorized,
+			wantCalled:     false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			called := false
+			next := func(ctx context.Context, w http.ResponseWriter, r *http.Request) {
+				called = true
+				w.WriteHeader(http.StatusOK)
+			}
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodPost, "/books", nil)
+			RequirePermission(tt.perm, next)(tt.ctx, w, r)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("RequirePermission() status = %v, want %v", w.Code, tt.expectedStatus)
+			}
+			if called != tt.wantCalled {
+				t.Errorf("RequirePermission() called next = %v, want %v", called, tt.wantCalled)
+			}
+		})
+	}
+}
@@ -0,0 +1,22 @@
+package middleware
+
+import (
+	"net/http"
+
+	weberrors "github.com/Kaushik1766/LibraryManagement/internal/web_errors"
+	"github.com/google/uuid"
+)
+
+// RequestID tags every response with a request id, keeping one the client
+// sent if it looks sane.
+func RequestID(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(weberrors.RequestIDHeader)
+		if id == "" || len(id) > 64 {
+			id = uuid.NewString()
+		}
+
+		w.Header().Set(weberrors.RequestIDHeader, id)
+		next.ServeHTTP(w, r)
+	})
+}
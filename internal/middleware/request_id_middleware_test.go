@@ -0,0 +1,59 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	weberrors "github.com/Kaushik1766/LibraryManagement/internal/web_errors"
+)
+
+func TestRequestID(t *testing.T) {
+	tests := []struct {
+		name     string
+		incoming string
+		wantSame bool
+	}{
+		{
+			name:     "generates id when missing",
+			incoming: "",
+			wantSame: false,
+		},
+		{
+			name:     "keeps incoming id",
+			incoming: "client-request-1",
+			wantSame: true,
+		},
+		{
+			name:     "replaces oversized id",
+			incoming: strings.Repeat("a", 65),
+			wantSame: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", "/test", nil)
+			if tt.incoming != "" {
+				r.Header.Set(weberrors.RequestIDHeader, tt.incoming)
+			}
+			w := httptest.NewRecorder()
+
+			var seen string
+			RequestID(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				seen = w.Header().Get(weberrors.RequestIDHeader)
+			})).ServeHTTP(w, r)
+
+			got := w.Header().Get(weberrors.RequestIDHeader)
+			if got == "" {
+				t.Fatal("RequestID() response header not set")
+			}
+			if seen != got {
+				t.Errorf("RequestID() header seen by handler = %v, want %v", seen, got)
+			}
+			if (got == tt.incoming) != tt.wantSame {
+				t.Errorf("RequestID() id = %v, incoming %v, wantSame %v", got, tt.incoming, tt.wantSame)
+			}
+		})
+	}
+}
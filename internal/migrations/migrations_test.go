@@ -0,0 +1,276 @@
+package migrations
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+)
+
+var testFS = fstest.MapFS{
+	"0001_init.up.sql":          {Data: []byte("create table a();")},
+	"0001_init.down.sql":        {Data: []byte("drop table a;")},
+	"0002_add_b.up.sql":         {Data: []byte("create table b();")},
+	"0002_add_b.down.sql":       {Data: []byte("drop table b;")},
+	"README.md":                 {Data: []byte("ignored")},
+	"0003_no_down.up.sql.bak":   {Data: []byte("ignored")},
+	"nested/0004_skip.up.sql":   {Data: []byte("ignored")},
+	"nested/0004_skip.down.sql": {Data: []byte("ignored")},
+}
+
+func expectLock(mock sqlmock.Sqlmock) {
+	mock.ExpectExec(`select pg_advisory_lock`).WithArgs(advisoryLockKey).WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec(`create table if not exists schema_migrations`).WillReturnResult(sqlmock.NewResult(0, 0))
+}
+
+func expectUnlock(mock sqlmock.Sqlmock) {
+	mock.ExpectExec(`select pg_advisory_unlock`).WithArgs(advisoryLockKey).WillReturnResult(sqlmock.NewResult(0, 0))
+}
+
+func TestLoadMigrations(t *testing.T) {
+	tests := []struct {
+		name    string
+		fsys    fstest.MapFS
+		want    []Migration
+		wantErr bool
+	}{
+		{
+			name: "valid migrations sorted by version",
+			fsys: testFS,
+			want: []Migration{
+				{Version: 1, Name: "init", Up: "create table a();", Down: "drop table a;"},
+				{Version: 2, Name: "add_b", Up: "create table b();", Down: "drop table b;"},
+			},
+			wantErr: false,
+		},
+		{
+			name: "missing up file",
+			fsys: fstest.MapFS{
+				"0001_init.down.sql": {Data: []byte("drop table a;")},
+			},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "conflicting names",
+			fsys: fstest.MapFS{
+				"0001_init.up.sql":  {Data: []byte("create table a();")},
+				"0001_other.up.sql": {Data: []byte("create table b();")},
+			},
+			want:    nil,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := LoadMigrations(tt.fsys)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("LoadMigrations() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("LoadMigrations() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMigrator_Up(t *testing.T) {
+	tests := []struct {
+		name        string
+		mockSetup   func(mock sqlmock.Sqlmock)
+		wantApplied []int
+		wantErr     bool
+	}{
+		{
+			name: "applies only pending migrations",
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				expectLock(mock)
+				mock.ExpectQuery(`select version, applied_at from schema_migrations`).
+					WillReturnRows(sqlmock.NewRows([]string{"version", "applied_at"}).AddRow(1, time.Now()))
+				mock.ExpectBegin()
+				mock.ExpectExec(`create table b`).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec(`insert into schema_migrations`).WithArgs(2, "add_b").WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectCommit()
+				expectUnlock(mock)
+			},
+			wantApplied: []int{2},
+			wantErr:     false,
+		},
+		{
+			name: "nothing pending",
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				expectLock(mock)
+				mock.ExpectQuery(`select version, applied_at from schema_migrations`).
+					WillReturnRows(sqlmock.NewRows([]string{"version", "applied_at"}).AddRow(1, time.Now()).AddRow(2, time.Now()))
+				expectUnlock(mock)
+			},
+			wantApplied: nil,
+			wantErr:     false,
+		},
+		{
+			name: "failing migration is rolled back",
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				expectLock(mock)
+				mock.ExpectQuery(`select version, applied_at from schema_migrations`).
+					WillReturnRows(sqlmock.NewRows([]string{"version", "applied_at"}))
+				mock.ExpectBegin()
+				mock.ExpectExec(`create table a`).WillReturnError(errors.New("syntax error"))
+				mock.ExpectRollback()
+				expectUnlock(mock)
+			},
+			wantApplied: nil,
+			wantErr:     true,
+		},
+		{
+			name: "lock error",
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				mock.ExpectExec(`select pg_advisory_lock`).WillReturnError(errors.New("connection refused"))
+			},
+			wantApplied: nil,
+			wantErr:     true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, _ := sqlmock.New()
+			defer db.Close()
+
+			migrator, err := NewMigrator(db, testFS)
+			if err != nil {
+				t.Fatalf("NewMigrator() error = %v", err)
+			}
+
+			tt.mockSetup(mock)
+			applied, err := migrator.Up(context.Background())
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Up() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			var versions []int
+			for _, m := range applied {
+				versions = append(versions, m.Version)
+			}
+			if !reflect.DeepEqual(versions, tt.wantApplied) {
+				t.Errorf("Up() applied = %v, want %v", versions, tt.wantApplied)
+			}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("unmet expectations: %v", err)
+			}
+		})
+	}
+}
+
+func TestMigrator_Down(t *testing.T) {
+	tests := []struct {
+		name         string
+		steps        int
+		mockSetup    func(mock sqlmock.Sqlmock)
+		wantReverted []int
+		wantErr      bool
+	}{
+		{
+			name:  "reverts latest migration",
+			steps: 1,
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				expectLock(mock)
+				mock.ExpectQuery(`select version, applied_at from schema_migrations`).
+					WillReturnRows(sqlmock.NewRows([]string{"version", "applied_at"}).AddRow(1, time.Now()).AddRow(2, time.Now()))
+				mock.ExpectBegin()
+				mock.ExpectExec(`drop table b`).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec(`delete from schema_migrations`).WithArgs(2).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+				expectUnlock(mock)
+			},
+			wantReverted: []int{2},
+			wantErr:      false,
+		},
+		{
+			name:  "skips unapplied migrations",
+			steps: 5,
+			mockSetup: func(mock sqlmock.Sqlmock) {
+				expectLock(mock)
+				mock.ExpectQuery(`select version, applied_at from schema_migrations`).
+					WillReturnRows(sqlmock.NewRows([]string{"version", "applied_at"}).AddRow(1, time.Now()))
+				mock.ExpectBegin()
+				mock.ExpectExec(`drop table a`).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec(`delete from schema_migrations`).WithArgs(1).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+				expectUnlock(mock)
+			},
+			wantReverted: []int{1},
+			wantErr:      false,
+		},
+		{
+			name:  "invalid steps",
+			steps: 0,
+			mockSetup: func(mock sqlmock.Sqlmock) {
+			},
+			wantReverted: nil,
+			wantErr:      true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, mock, _ := sqlmock.New()
+			defer db.Close()
+
+			migrator, err := NewMigrator(db, testFS)
+			if err != nil {
+				t.Fatalf("NewMigrator() error = %v", err)
+			}
+
+			tt.mockSetup(mock)
+			reverted, err := migrator.Down(context.Background(), tt.steps)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Down() error = %v, wantErr %v", err, tt.wantErr)
+			}
+
+			var versions []int
+			for _, m := range reverted {
+				versions = append(versions, m.Version)
+			}
+			if !reflect.DeepEqual(versions, tt.wantReverted) {
+				t.Errorf("Down() reverted = %v, want %v", versions, tt.wantReverted)
+			}
+
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("unmet expectations: %v", err)
+			}
+		})
+	}
+}
+
+func TestMigrator_Status(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	migrator, err := NewMigrator(db, testFS)
+	if err != nil {
+		t.Fatalf("NewMigrator() error = %v", err)
+	}
+
+	appliedAt := time.Date(2025, 9, 1, 0, 0, 0, 0, time.UTC)
+	expectLock(mock)
+	mock.ExpectQuery(`select version, applied_at from schema_migrations`).
+		WillReturnRows(sqlmock.NewRows([]string{"version", "applied_at"}).AddRow(1, appliedAt))
+	expectUnlock(mock)
+
+	got, err := migrator.Status(context.Background())
+	if err != nil {
+		t.Fatalf("Status() error = %v", err)
+	}
+
+	want := []MigrationStatus{
+		{Version: 1, Name: "init", AppliedAt: &appliedAt},
+		{Version: 2, Name: "add_b", AppliedAt: nil},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Status() got = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,44 @@
+package models
+
+import (
+	"time"
+
+	"github.com/Kaushik1766/LibraryManagement/internal/models/enums/blockkind"
+	"github.com/google/uuid"
+)
+
+// Block stops a patron from borrowing and renewing. Only manual blocks have
+// an id and who placed them.
+type Block struct {
+	ID        uuid.UUID
+	User      User
+	Kind      blockkind.BlockKind
+	Reason    string
+	PlacedBy  *User
+	PlacedAt  time.Time
+	ExpiresAt *time.Time
+}
+
+// Standing is what automatic blocks are decided on. Unpaid fines are in the
+// smallest currency unit and include fines still accruing.
+type Standing struct {
+	OverdueLoans        int
+	UnpaidFines         int
+	MembershipExpiresAt *time.Time
+}
+
+type BlockDTO struct {
+	ID        string `json:"block_id,omitempty"`
+	Kind      string `json:"kind"`
+	Reason    string `json:"reason"`
+	PlacedBy  string `json:"placed_by,omitempty"`
+	PlacedAt  string `json:"placed_at,omitempty"`
+	ExpiresAt string `json:"expires_at,omitempty"`
+}
+
+// PlaceBlockDTO places a manual block, ExpiresAt is RFC 3339 and a block
+// without it stays until lifted.
+type PlaceBlockDTO struct {
+	Reason    string `json:"reason"`
+	ExpiresAt string `json:"expires_at"`
+}
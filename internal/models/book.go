@@ -1,23 +1,149 @@
 package models
 
-import "github.com/google/uuid"
+import (
+	"time"
 
+	"github.com/Kaushik1766/LibraryManagement/internal/models/enums/copystatus"
+	"github.com/Kaushik1766/LibraryManagement/internal/pagination"
+	"github.com/google/uuid"
+)
+
+// DefaultItemType is the item type of titles added without one.
+const DefaultItemType = "book"
+
+// Book is a bibliographic record (a title). The physical items that circulate
+// are its copies. ItemType picks the loan rules that apply to them.
 type Book struct {
-	ID       uuid.UUID
-	Title    string
-	Author   string
-	IssuedTo *User
+	ID              uuid.UUID
+	Title           string
+	Author          string
+	ISBN            string
+	Publisher       string
+	Year            int
+	Language        string
+	Subject         string
+	Description     string
+	ItemType        string
+	TotalCopies     int
+	AvailableCopies int
+}
+
+type Copy struct {
+	ID              uuid.UUID
+	BookID          uuid.UUID
+	Barcode         string
+	Status          copystatus.CopyStatus
+	Location        string
+	IssuedTo        *User
+	WithdrawnAt     *time.Time
+	WithdrawnReason string
 }
 
 type AddBookDTO struct {
-	Title  string `json:"title"`
-	Author string `json:"author"`
-	Copies int    `json:"copies"`
+	Title       string `json:"title"`
+	Author      string `json:"author"`
+	ISBN        string `json:"isbn"`
+	Publisher   string `json:"publisher"`
+	Year        int    `json:"year"`
+	Language    string `json:"language"`
+	Subject     string `json:"subject"`
+	Description string `json:"description"`
+	ItemType    string `json:"item_type"`
+	Copies      int    `json:"copies"`
+	Location    string `json:"location"`
+}
+
+// UpdateBookDTO is a partial update, fields left out of the request are kept.
+type UpdateBookDTO struct {
+	Title       *string `json:"title"`
+	Author      *string `json:"author"`
+	ISBN        *string `json:"isbn"`
+	Publisher   *string `json:"publisher"`
+	Year        *int    `json:"year"`
+	Language    *string `json:"language"`
+	Subject     *string `json:"subject"`
+	Description *string `json:"description"`
+	ItemType    *string `json:"item_type"`
+}
+
+type WithdrawDTO struct {
+	Reason string `json:"reason"`
+}
+
+type AddCopiesDTO struct {
+	Copies   int    `json:"copies"`
+	Location string `json:"location"`
 }
 
 type BookDTO struct {
-	ID       string `json:"book_id"`
-	Title    string `json:"title"`
-	Author   string `json:"author"`
-	IssuedTo string `json:"issued_to,omitempty"`
+	ID              string `json:"book_id"`
+	Title           string `json:"title"`
+	Author          string `json:"author"`
+	ISBN            string `json:"isbn,omitempty"`
+	Publisher       string `json:"publisher,omitempty"`
+	Year            int    `json:"year,omitempty"`
+	Language        string `json:"language,omitempty"`
+	Subject         string `json:"subject,omitempty"`
+	Description     string `json:"description,omitempty"`
+	ItemType        string `json:"item_type,omitempty"`
+	TotalCopies     int    `json:"total_copies"`
+	AvailableCopies int    `json:"available_copies"`
+}
+
+type CopyDTO struct {
+	ID              string `json:"copy_id"`
+	BookID          string `json:"book_id"`
+	Barcode         string `json:"barcode"`
+	Status          string `json:"status"`
+	Location        string `json:"location,omitempty"`
+	IssuedTo        string `json:"issued_to,omitempty"`
+	WithdrawnAt     string `json:"withdrawn_at,omitempty"`
+	WithdrawnReason string `json:"withdrawn_reason,omitempty"`
+}
+
+// BookSearch is a catalog search. Query accepts quoted phrases, trailing *
+// for prefixes and a leading - to exclude a word. The other fields narrow
+// the results to one facet value and are optional.
+type BookSearch struct {
+	Query     string
+	Author    string
+	Subject   string
+	Language  string
+	Available string
+}
+
+type SearchHit struct {
+	Book    Book
+	Rank    float64
+	Snippet string
+}
+
+type FacetCount struct {
+	Value string `json:"value"`
+	Count int    `json:"count"`
+}
+
+// SearchFacets counts the matching titles by each value of a facet, most
+// common first.
+type SearchFacets struct {
+	Authors      []FacetCount `json:"authors"`
+	Subjects     []FacetCount `json:"subjects"`
+	Languages    []FacetCount `json:"languages"`
+	Availability []FacetCount `json:"availability"`
+}
+
+type SearchResult struct {
+	Hits   pagination.Page[SearchHit]
+	Facets SearchFacets
+}
+
+type SearchHitDTO struct {
+	BookDTO
+	Rank    float64 `json:"rank"`
+	Snippet string  `json:"snippet,omitempty"`
+}
+
+type SearchResultDTO struct {
+	pagination.Page[SearchHitDTO]
+	Facets SearchFacets `json:"facets"`
 }
@@ -0,0 +1,12 @@
+package blockkind
+
+type BlockKind string
+
+const (
+	// Manual blocks are placed and lifted by staff, the others come and go
+	// with the patron's standing.
+	Manual     BlockKind = "manual"
+	Overdue    BlockKind = "overdue"
+	Fines      BlockKind = "fines"
+	Membership BlockKind = "membership"
+)
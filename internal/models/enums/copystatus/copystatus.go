@@ -0,0 +1,13 @@
+package copystatus
+
+type CopyStatus string
+
+const (
+	Available CopyStatus = "available"
+	Withdrawn CopyStatus = "withdrawn"
+
+	// OnLoan and OnHold are never stored, they are derived from open
+	// transactions and ready holds when copies are listed.
+	OnLoan CopyStatus = "on_loan"
+	OnHold CopyStatus = "on_hold"
+)
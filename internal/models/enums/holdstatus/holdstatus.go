@@ -0,0 +1,13 @@
+package holdstatus
+
+type HoldStatus string
+
+const (
+	// Waiting holds are queued behind a copy that is still issued.
+	Waiting HoldStatus = "waiting"
+	// Ready holds have a returned copy set aside until the pickup deadline.
+	Ready     HoldStatus = "ready"
+	Fulfilled HoldStatus = "fulfilled"
+	Cancelled HoldStatus = "cancelled"
+	Expired   HoldStatus = "expired"
+)
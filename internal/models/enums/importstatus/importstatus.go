@@ -0,0 +1,10 @@
+package importstatus
+
+type ImportStatus string
+
+const (
+	// Imported rows were written, or would have been on a dry run.
+	Imported  ImportStatus = "imported"
+	Duplicate ImportStatus = "duplicate"
+	Invalid   ImportStatus = "invalid"
+)
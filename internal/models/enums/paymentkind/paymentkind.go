@@ -0,0 +1,9 @@
+package paymentkind
+
+type PaymentKind string
+
+const (
+	Payment PaymentKind = "payment"
+	// Waiver reduces what is owed without any money changing hands.
+	Waiver PaymentKind = "waiver"
+)
@@ -1,18 +1,37 @@
 package roles
 
+import "strings"
+
 type UserRoles int
 
+// roles are stored as ints, new ones must only ever be appended
 const (
 	Staff UserRoles = iota
 	Customer
+	Admin
+	Librarian
 )
 
+// Parse finds a role by its name, ignoring case.
+func Parse(name string) (UserRoles, bool) {
+	for _, role := range []UserRoles{Staff, Customer, Admin, Librarian} {
+		if strings.EqualFold(name, role.String()) {
+			return role, true
+		}
+	}
+	return 0, false
+}
+
 func (role UserRoles) String() string {
 	switch role {
 	case Staff:
 		return "Staff"
 	case Customer:
 		return "Customer"
+	case Admin:
+		return "Admin"
+	case Librarian:
+		return "Librarian"
 	default:
 		return "Invalid Role"
 	}
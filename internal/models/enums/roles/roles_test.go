@@ -18,6 +18,16 @@ func TestUserRoles_String(t *testing.T) {
 			role: Customer,
 			want: "Customer",
 		},
+		{
+			name: "Admin role",
+			role: Admin,
+			want: "Admin",
+		},
+		{
+			name: "Librarian role",
+			role: Librarian,
+			want: "Librarian",
+		},
 		{
 			name: "Invalid role",
 			role: UserRoles(999),
@@ -0,0 +1,41 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Why a login was refused.
+const (
+	LoginUnknownEmail    = "unknown_email"
+	LoginInvalidPassword = "invalid_password"
+	LoginInvalidMFACode  = "invalid_mfa_code"
+	LoginLocked          = "locked"
+)
+
+// FailedLogin is the audit record of a refused login. UserID is nil when the
+// email matched no account.
+type FailedLogin struct {
+	ID          uuid.UUID
+	Email       string
+	UserID      *uuid.UUID
+	IP          string
+	Reason      string
+	AttemptedAt time.Time
+}
+
+// FailedLoginFilter narrows the audit log, empty fields match everything.
+type FailedLoginFilter struct {
+	Email string
+	IP    string
+}
+
+type FailedLoginDTO struct {
+	ID          string `json:"failed_login_id"`
+	Email       string `json:"email"`
+	UserID      string `json:"user_id,omitempty"`
+	IP          string `json:"ip"`
+	Reason      string `json:"reason"`
+	AttemptedAt string `json:"attempted_at"`
+}
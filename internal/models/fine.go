@@ -0,0 +1,43 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Fine amounts are in the smallest currency unit.
+type Fine struct {
+	ID          uuid.UUID
+	Transaction Transaction
+	User        User
+	Amount      int
+	Paid        int
+	Waived      int
+	DaysOverdue int
+	Final       bool
+	AssessedAt  time.Time
+}
+
+type FineDTO struct {
+	ID            string `json:"fine_id"`
+	TransactionID string `json:"transaction_id"`
+	BookName      string `json:"book_name"`
+	UserEmail     string `json:"user_email,omitempty"`
+	DaysOverdue   int    `json:"days_overdue"`
+	Amount        int    `json:"amount"`
+	Paid          int    `json:"paid"`
+	Waived        int    `json:"waived"`
+	Outstanding   int    `json:"outstanding"`
+	Status        string `json:"status"`
+}
+
+type FineBalanceDTO struct {
+	Outstanding int       `json:"outstanding"`
+	Fines       []FineDTO `json:"fines"`
+}
+
+type FinePaymentDTO struct {
+	Amount int    `json:"amount"`
+	Reason string `json:"reason"`
+}
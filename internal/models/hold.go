@@ -0,0 +1,31 @@
+package models
+
+import (
+	"time"
+
+	"github.com/Kaushik1766/LibraryManagement/internal/models/enums/holdstatus"
+	"github.com/google/uuid"
+)
+
+type Hold struct {
+	ID            uuid.UUID
+	Book          Book
+	Copy          *Copy
+	User          User
+	Status        holdstatus.HoldStatus
+	QueuePosition int
+	PlacedAt      time.Time
+	ExpiresAt     *time.Time
+}
+
+type HoldDTO struct {
+	ID            string `json:"hold_id"`
+	BookID        string `json:"book_id"`
+	CopyID        string `json:"copy_id,omitempty"`
+	BookName      string `json:"book_name"`
+	UserEmail     string `json:"user_email,omitempty"`
+	Status        string `json:"status"`
+	QueuePosition int    `json:"queue_position,omitempty"`
+	PlacedAt      string `json:"placed_at"`
+	PickupBy      string `json:"pickup_by,omitempty"`
+}
@@ -0,0 +1,32 @@
+package models
+
+import "github.com/Kaushik1766/LibraryManagement/internal/models/enums/importstatus"
+
+// ImportRow is one record read from a bulk import file. Line counts from 1
+// and includes the CSV header, for MARC files it is the record number. Error
+// is set when the row can't be imported.
+type ImportRow struct {
+	Line  int
+	Book  AddBookDTO
+	Error string
+}
+
+type ImportRowResult struct {
+	Line   int                       `json:"line"`
+	Status importstatus.ImportStatus `json:"status"`
+	BookID string                    `json:"book_id,omitempty"`
+	Error  string                    `json:"error,omitempty"`
+}
+
+// ImportReport describes what a bulk import did with every row. Nothing is
+// written unless Committed is set, which a dry run or a single invalid row
+// prevents.
+type ImportReport struct {
+	DryRun     bool              `json:"dry_run"`
+	Committed  bool              `json:"committed"`
+	Total      int               `json:"total"`
+	Imported   int               `json:"imported"`
+	Duplicates int               `json:"duplicates"`
+	Invalid    int               `json:"invalid"`
+	Rows       []ImportRowResult `json:"rows"`
+}
@@ -0,0 +1,18 @@
+package models
+
+// JWKDTO is a public key in the JSON Web Key format (RFC 7517). RSA keys set
+// N and E, Ed25519 keys Curve and X.
+type JWKDTO struct {
+	KeyType   string `json:"kty"`
+	ID        string `json:"kid"`
+	Use       string `json:"use"`
+	Algorithm string `json:"alg"`
+	N         string `json:"n,omitempty"`
+	E         string `json:"e,omitempty"`
+	Curve     string `json:"crv,omitempty"`
+	X         string `json:"x,omitempty"`
+}
+
+type JWKSDTO struct {
+	Keys []JWKDTO `json:"keys"`
+}
@@ -0,0 +1,34 @@
+package models
+
+// MFASetupDTO is a new authenticator secret, as text and as the otpauth://
+// URI to show as a QR code.
+type MFASetupDTO struct {
+	Secret string `json:"secret"`
+	URI    string `json:"otpauth_uri"`
+}
+
+// MFATokenDTO names the login an MFA request belongs to.
+type MFATokenDTO struct {
+	MFAToken string `json:"mfa_token"`
+}
+
+// VerifyMFADTO finishes a login. Code is from the authenticator app or one of
+// the recovery codes.
+type VerifyMFADTO struct {
+	MFAToken string `json:"mfa_token"`
+	Code     string `json:"code"`
+}
+
+type MFACodeDTO struct {
+	Code string `json:"code"`
+}
+
+// DisableMFADTO confirms turning MFA off with the password and a current code.
+type DisableMFADTO struct {
+	Password string `json:"password"`
+	Code     string `json:"code"`
+}
+
+type RecoveryCodesDTO struct {
+	RecoveryCodes []string `json:"recovery_codes"`
+}
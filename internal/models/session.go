@@ -0,0 +1,51 @@
+package models
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Session is a single refresh token. Every token minted by rotating another
+// shares its FamilyID with the login it came from.
+type Session struct {
+	ID              uuid.UUID
+	UserID          uuid.UUID
+	FamilyID        uuid.UUID
+	TokenHash       string
+	AccessJTI       uuid.UUID
+	AccessExpiresAt time.Time
+	ExpiresAt       time.Time
+	RotatedAt       *time.Time
+	RevokedAt       *time.Time
+}
+
+// TokenPairDTO is what a login gets back. When the account uses MFA the
+// tokens are left out and MFAToken has to be sent back with a code first.
+type TokenPairDTO struct {
+	AccessToken  string `json:"jwt,omitempty"`
+	RefreshToken string `json:"refresh_token,omitempty"`
+	MFAToken     string `json:"mfa_token,omitempty"`
+	// MFAEnrollmentRequired is set when the account's role needs MFA but it
+	// was never set up, it has to be before the login can finish.
+	MFAEnrollmentRequired bool `json:"mfa_enrollment_required,omitempty"`
+	// RecoveryCodes are handed out once, when a login finishes setting up MFA.
+	RecoveryCodes []string `json:"recovery_codes,omitempty"`
+}
+
+type RefreshDTO struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
+type ForgotPasswordDTO struct {
+	Email string `json:"email"`
+}
+
+type ResetPasswordDTO struct {
+	Token       string `json:"token"`
+	NewPassword string `json:"new_password"`
+}
+
+type VerifyEmailDTO struct {
+	Token string `json:"token"`
+}
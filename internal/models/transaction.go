@@ -9,20 +9,37 @@ import (
 type Transaction struct {
 	ID         uuid.UUID
 	Book       Book
+	Copy       Copy
 	User       User
 	IssuedAt   time.Time
 	IssuedTill time.Time
 	ReturnedAt *time.Time
+	Renewals   int
+	// IssuedBy and ReturnedBy are the staff who handled the loan at the
+	// desk, nil for self service.
+	IssuedBy   *User
+	ReturnedBy *User
+}
+
+// LoanTerms are fixed when a loan is issued. Period is a postgres interval
+// and the fine rates are those of the loan policy in force at the time.
+type LoanTerms struct {
+	Period     string
+	FinePerDay int
+	FineCap    int
 }
 
 type TransactionDTO struct {
 	ID         string `json:"transaction_id"`
 	BookID     string `json:"book_id"`
+	CopyID     string `json:"copy_id"`
 	BookName   string `json:"book_name"`
 	UserEmail  string `json:"user_email"`
 	IssuedAt   string `json:"issued_at"`
 	IssuedTill string `json:"issued_till"`
 	ReturnedAt string `json:"returned_at"`
+	IssuedBy   string `json:"issued_by,omitempty"`
+	ReturnedBy string `json:"returned_by,omitempty"`
 }
 
 type GetTransactionRequestDTO struct {
@@ -37,8 +54,31 @@ type GetTransactionRequestDTO struct {
 type OverdueTransactionDTO struct {
 	ID         string `json:"transaction_id"`
 	BookID     string `json:"book_id"`
+	CopyID     string `json:"copy_id"`
 	BookName   string `json:"book_name"`
 	IssuedAt   string `json:"issued_at"`
 	IssuedTill string `json:"issued_till"`
 	ReturnedAt string `json:"returned_at"`
 }
+
+type RenewalDTO struct {
+	TransactionID string `json:"transaction_id"`
+	IssuedTill    string `json:"issued_till"`
+	RenewalsUsed  int    `json:"renewals_used"`
+	RenewalsLeft  int    `json:"renewals_left"`
+}
+
+// DeskIssueDTO identifies the patron by user id, email or card number and the
+// copy by id or barcode.
+type DeskIssueDTO struct {
+	Patron   string `json:"patron"`
+	Copy     string `json:"copy"`
+	IssueFor string `json:"issue_for"`
+}
+
+// DeskReturnDTO checks a copy in. Patron is optional, when given the copy
+// must be on loan to them.
+type DeskReturnDTO struct {
+	Patron string `json:"patron"`
+	Copy   string `json:"copy"`
+}
@@ -1,17 +1,28 @@
 package models
 
 import (
+	"time"
+
 	"github.com/Kaushik1766/LibraryManagement/internal/models/enums/roles"
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
 )
 
 type User struct {
-	ID       uuid.UUID
-	Name     string
-	Password string
-	Email    string
-	Role     roles.UserRoles
+	ID                  uuid.UUID
+	Name                string
+	Password            string
+	Email               string
+	Role                roles.UserRoles
+	CardNumber          string
+	MembershipExpiresAt *time.Time
+	DeactivatedAt       *time.Time
+	EmailVerifiedAt     *time.Time
+	FailedLoginCount    int
+	LockedUntil         *time.Time
+	TOTPSecret          string
+	MFAEnabledAt        *time.Time
+	CreatedAt           time.Time
 }
 
 type UserJwt struct {
@@ -30,3 +41,62 @@ type LoginDTO struct {
 	Email    string `json:"email"`
 	Password string `json:"password"`
 }
+
+// UserFilter narrows the user list. Query matches part of the name or email,
+// or a whole card number. Nil fields match everyone.
+type UserFilter struct {
+	Query  string
+	Role   *roles.UserRoles
+	Active *bool
+}
+
+type UserDTO struct {
+	ID                  string `json:"user_id"`
+	Name                string `json:"name"`
+	Email               string `json:"email"`
+	Role                string `json:"role"`
+	CardNumber          string `json:"card_number"`
+	Active              bool   `json:"active"`
+	EmailVerified       bool   `json:"email_verified"`
+	MFAEnabled          bool   `json:"mfa_enabled"`
+	MembershipExpiresAt string `json:"membership_expires_at,omitempty"`
+	DeactivatedAt       string `json:"deactivated_at,omitempty"`
+	LockedUntil         string `json:"locked_until,omitempty"`
+	CreatedAt           string `json:"created_at"`
+}
+
+// UpdateUserDTO is a partial update, fields left out of the request are kept.
+// An empty MembershipExpiresAt clears it, otherwise it is RFC 3339.
+type UpdateUserDTO struct {
+	Name                *string `json:"name"`
+	Email               *string `json:"email"`
+	MembershipExpiresAt *string `json:"membership_expires_at"`
+}
+
+type ChangeRoleDTO struct {
+	Role string `json:"role"`
+}
+
+// ProfileDTO is an account as its owner sees it.
+type ProfileDTO struct {
+	ID                  string `json:"user_id"`
+	Name                string `json:"name"`
+	Email               string `json:"email"`
+	Role                string `json:"role"`
+	CardNumber          string `json:"card_number"`
+	EmailVerified       bool   `json:"email_verified"`
+	MFAEnabled          bool   `json:"mfa_enabled"`
+	MembershipExpiresAt string `json:"membership_expires_at,omitempty"`
+}
+
+// UpdateProfileDTO is a partial update, fields left out of the request are
+// kept.
+type UpdateProfileDTO struct {
+	Name  *string `json:"name"`
+	Email *string `json:"email"`
+}
+
+type ChangePasswordDTO struct {
+	CurrentPassword string `json:"current_password"`
+	NewPassword     string `json:"new_password"`
+}
@@ -0,0 +1,216 @@
+package pagination
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+
+	apperrors "github.com/Kaushik1766/LibraryManagement/internal/app_errors"
+)
+
+const (
+	DefaultLimit = 20
+	MaxLimit     = 100
+)
+
+// Request is what a client asked for on a list endpoint. Cursor is opaque to
+// the client and only meaningful together with the Sort it was issued for.
+type Request struct {
+	Limit  int
+	Cursor string
+	Sort   string
+}
+
+// FromQuery reads limit, cursor and sort from query parameters. A missing
+// limit means DefaultLimit.
+func FromQuery(query url.Values) (Request, error) {
+	req := Request{
+		Limit:  DefaultLimit,
+		Cursor: query.Get("cursor"),
+		Sort:   query.Get("sort"),
+	}
+
+	if raw := query.Get("limit"); raw != "" {
+		limit, err := strconv.Atoi(raw)
+		if err != nil || limit < 1 || limit > MaxLimit {
+			return req, apperrors.Validation("invalid_limit", "invalid limit").
+				WithDetail("limit", fmt.Sprintf("must be between 1 and %d", MaxLimit))
+		}
+		req.Limit = limit
+	}
+
+	return req, nil
+}
+
+func (req Request) limit() int {
+	if req.Limit < 1 || req.Limit > MaxLimit {
+		return DefaultLimit
+	}
+	return req.Limit
+}
+
+// Page is the envelope list endpoints respond with. Total counts every match,
+// not just the ones on this page.
+type Page[T any] struct {
+	Items      []T    `json:"items"`
+	NextCursor string `json:"next_cursor,omitempty"`
+	Total      int    `json:"total"`
+}
+
+// Map converts the items of a page keeping its cursor and total.
+func Map[T, U any](page Page[T], convert func(T) U) Page[U] {
+	items := make([]U, 0, len(page.Items))
+	for _, item := range page.Items {
+		items = append(items, convert(item))
+	}
+	return Page[U]{Items: items, NextCursor: page.NextCursor, Total: page.Total}
+}
+
+// Column is a sortable column of a list query. Key reads the column's value
+// back out of a row so the next cursor can start after it.
+type Column[T any] struct {
+	Expr string
+	// Type is the postgres type the cursor key is cast to when compared.
+	Type string
+	Key  func(T) string
+}
+
+// cursor remembers the sort key and id of the last row handed out.
+type cursor struct {
+	Sort string `json:"s"`
+	Key  string `json:"k"`
+	ID   string `json:"id"`
+}
+
+// Order is a parsed sort together with the position to resume from.
+type Order[T any] struct {
+	column Column[T]
+	desc   bool
+	sort   string
+	after  cursor
+	limit  int
+}
+
+// NewOrder resolves req against the columns a query can be sorted by. Sort
+// names a column, prefixed with - for descending. An empty sort uses def.
+func NewOrder[T any](req Request, columns map[string]Column[T], def string) (Order[T], error) {
+	sort := req.Sort
+	if sort == "" {
+		sort = def
+	}
+
+	column, ok := columns[strings.TrimPrefix(sort, "-")]
+	if !ok {
+		names := make([]string, 0, len(columns))
+		for name := range columns {
+			names = append(names, name)
+		}
+		slices.Sort(names)
+		return Order[T]{}, apperrors.Validation("invalid_sort", "invalid sort").
+			WithDetail("sort", "must be one of "+strings.Join(names, ", "))
+	}
+
+	order := Order[T]{
+		column: column,
+		desc:   strings.HasPrefix(sort, "-"),
+		sort:   sort,
+		limit:  req.limit(),
+	}
+
+	if req.Cursor != "" {
+		after, err := decodeCursor(req.Cursor)
+		if err != nil || after.Sort != sort {
+			return Order[T]{}, apperrors.Validation("invalid_cursor", "invalid cursor").
+				WithDetail("cursor", "does not belong to this sort")
+		}
+		order.after = after
+	}
+
+	return order, nil
+}
+
+// After is the keyset condition for a query whose rows are identified by
+// idExpr. keyArg and idArg are the placeholders bound to AfterArgs. With no
+// cursor the condition is always true.
+func (order Order[T]) After(idExpr string, keyArg, idArg int) string {
+	op := ">"
+	if order.desc {
+		op = "<"
+	}
+	return fmt.Sprintf("($%d='' or (%s, %s) %s (cast($%d as %s), cast($%d as uuid)))",
+		idArg, order.column.Expr, idExpr, op, keyArg, order.column.Type, idArg)
+}
+
+// AfterArgs are the values for the placeholders used in After.
+func (order Order[T]) AfterArgs() []any {
+	return []any{order.after.Key, order.after.ID}
+}
+
+// By is the order by clause. idExpr breaks ties so the keyset is total.
+func (order Order[T]) By(idExpr string) string {
+	dir := "asc"
+	if order.desc {
+		dir = "desc"
+	}
+	return fmt.Sprintf("%s %s, %s %s", order.column.Expr, dir, idExpr, dir)
+}
+
+// Fetch is how many rows to select, one more than the page holds so Page can
+// tell whether there is a next one.
+func (order Order[T]) Fetch() int {
+	return order.limit + 1
+}
+
+// Page trims rows to the page size and sets the cursor for the next page.
+func (order Order[T]) Page(rows []T, id func(T) string, total int) Page[T] {
+	page := Page[T]{Items: rows, Total: total}
+	if page.Items == nil {
+		page.Items = []T{}
+	}
+
+	if len(rows) > order.limit {
+		page.Items = rows[:order.limit]
+		last := page.Items[order.limit-1]
+		page.NextCursor = encodeCursor(cursor{
+			Sort: order.sort,
+			Key:  order.column.Key(last),
+			ID:   id(last),
+		})
+	}
+
+	return page
+}
+
+func encodeCursor(c cursor) string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodeCursor(raw string) (cursor, error) {
+	var c cursor
+	data, err := base64.RawURLEncoding.DecodeString(raw)
+	if err != nil {
+		return c, err
+	}
+	err = json.Unmarshal(data, &c)
+	return c, err
+}
+
+// SetLink adds a Link header pointing at the next page of r.
+func SetLink(w http.ResponseWriter, r *http.Request, nextCursor string) {
+	if nextCursor == "" {
+		return
+	}
+
+	next := *r.URL
+	query := next.Query()
+	query.Set("cursor", nextCursor)
+	next.RawQuery = query.Encode()
+
+	w.Header().Set("Link", fmt.Sprintf(`<%s>; rel="next"`, next.String()))
+}
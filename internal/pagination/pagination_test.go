@@ -0,0 +1,206 @@
+package pagination
+
+import (
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+type row struct {
+	ID   string
+	Name string
+}
+
+var rowColumns = map[string]Column[row]{
+	"name": {Expr: "r.name", Type: "text", Key: func(r row) string { return r.Name }},
+}
+
+func rowId(r row) string {
+	return r.ID
+}
+
+func TestFromQuery(t *testing.T) {
+	tests := []struct {
+		name    string
+		query   string
+		want    Request
+		wantErr bool
+	}{
+		{
+			name:  "defaults",
+			query: "",
+			want:  Request{Limit: DefaultLimit},
+		},
+		{
+			name:  "all parameters",
+			query: "limit=5&cursor=abc&sort=-name",
+			want:  Request{Limit: 5, Cursor: "abc", Sort: "-name"},
+		},
+		{
+			name:    "limit not a number",
+			query:   "limit=ten",
+			wantErr: true,
+		},
+		{
+			name:    "limit too large",
+			query:   "limit=" + strconv.Itoa(MaxLimit+1),
+			wantErr: true,
+		},
+		{
+			name:    "limit zero",
+			query:   "limit=0",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			query, _ := url.ParseQuery(tt.query)
+			got, err := FromQuery(query)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("FromQuery() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && got != tt.want {
+				t.Errorf("FromQuery() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewOrder(t *testing.T) {
+	tests := []struct {
+		name      string
+		req       Request
+		wantBy    string
+		wantAfter string
+		wantErr   bool
+	}{
+		{
+			name:      "default sort",
+			req:       Request{},
+			wantBy:    "r.name asc, r.id asc",
+			wantAfter: "($3='' or (r.name, r.id) > (cast($2 as text), cast($3 as uuid)))",
+		},
+		{
+			name:      "descending",
+			req:       Request{Sort: "-name"},
+			wantBy:    "r.name desc, r.id desc",
+			wantAfter: "($3='' or (r.name, r.id) < (cast($2 as text), cast($3 as uuid)))",
+		},
+		{
+			name:    "unknown column",
+			req:     Request{Sort: "created_at"},
+			wantErr: true,
+		},
+		{
+			name:    "garbage cursor",
+			req:     Request{Cursor: "%%%"},
+			wantErr: true,
+		},
+		{
+			name:    "cursor from another sort",
+			req:     Request{Sort: "name", Cursor: encodeCursor(cursor{Sort: "-name", Key: "b", ID: "2"})},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			order, err := NewOrder(tt.req, rowColumns, "name")
+			if (err != nil) != tt.wantErr {
+				t.Errorf("NewOrder() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				return
+			}
+			if got := order.By("r.id"); got != tt.wantBy {
+				t.Errorf("By() = %v, want %v", got, tt.wantBy)
+			}
+			if got := order.After("r.id", 2, 3); got != tt.wantAfter {
+				t.Errorf("After() = %v, want %v", got, tt.wantAfter)
+			}
+		})
+	}
+}
+
+func TestOrder_Page(t *testing.T) {
+	rows := []row{{ID: "1", Name: "a"}, {ID: "2", Name: "b"}, {ID: "3", Name: "c"}}
+
+	first, _ := NewOrder(Request{Limit: 2}, rowColumns, "name")
+	if first.Fetch() != 3 {
+		t.Fatalf("Fetch() = %v, want 3", first.Fetch())
+	}
+
+	page := first.Page(rows, rowId, 3)
+	if !reflect.DeepEqual(page.Items, rows[:2]) || page.Total != 3 || page.NextCursor == "" {
+		t.Fatalf("Page() = %+v, want first two rows with a cursor", page)
+	}
+
+	next, err := NewOrder(Request{Limit: 2, Cursor: page.NextCursor}, rowColumns, "name")
+	if err != nil {
+		t.Fatalf("NewOrder() with next cursor error = %v", err)
+	}
+	if got := next.AfterArgs(); !reflect.DeepEqual(got, []any{"b", "2"}) {
+		t.Errorf("AfterArgs() = %v, want [b 2]", got)
+	}
+
+	last := next.Page(rows[2:], rowId, 3)
+	if last.NextCursor != "" || len(last.Items) != 1 {
+		t.Errorf("Page() = %+v, want the last row and no cursor", last)
+	}
+
+	empty := first.Page(nil, rowId, 0)
+	if empty.Items == nil {
+		t.Error("Page() items should encode as an empty list")
+	}
+}
+
+func TestMap(t *testing.T) {
+	page := Page[row]{Items: []row{{ID: "1", Name: "a"}}, NextCursor: "next", Total: 4}
+
+	got := Map(page, func(r row) string { return r.Name })
+
+	want := Page[string]{Items: []string{"a"}, NextCursor: "next", Total: 4}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Map() = %v, want %v", got, want)
+	}
+}
+
+func TestSetLink(t *testing.T) {
+	tests := []struct {
+		name       string
+		target     string
+		nextCursor string
+		want       string
+	}{
+		{
+			name:       "adds cursor",
+			target:     "/books?limit=2&title=harry",
+			nextCursor: "abc",
+			want:       `</books?cursor=abc&limit=2&title=harry>; rel="next"`,
+		},
+		{
+			name:       "replaces cursor",
+			target:     "/books?cursor=old",
+			nextCursor: "new",
+			want:       `</books?cursor=new>; rel="next"`,
+		},
+		{
+			name:       "last page",
+			target:     "/books",
+			nextCursor: "",
+			want:       "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			SetLink(w, httptest.NewRequest("GET", tt.target, nil), tt.nextCursor)
+			if got := w.Header().Get("Link"); got != tt.want {
+				t.Errorf("SetLink() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
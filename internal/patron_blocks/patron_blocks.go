@@ -0,0 +1,90 @@
+package patronblocks
+
+import (
+	"fmt"
+	"time"
+
+	apperrors "github.com/Kaushik1766/LibraryManagement/internal/app_errors"
+	"github.com/Kaushik1766/LibraryManagement/internal/config"
+	"github.com/Kaushik1766/LibraryManagement/internal/models"
+	"github.com/Kaushik1766/LibraryManagement/internal/models/enums/blockkind"
+	blockrepo "github.com/Kaushik1766/LibraryManagement/internal/repository/block_repo"
+	"github.com/google/uuid"
+)
+
+// Checker works out the blocks on a patron from the ones staff placed and
+// the patron's standing.
+type Checker struct {
+	blockRepo blockrepo.BlockStorage
+	policy    config.CirculationConfig
+}
+
+func NewChecker(blockRepo blockrepo.BlockStorage, policy config.CirculationConfig) *Checker {
+	return &Checker{
+		blockRepo: blockRepo,
+		policy:    policy,
+	}
+}
+
+// Blocks returns the manual blocks on userId followed by the automatic ones.
+func (checker *Checker) Blocks(userId string) ([]models.Block, error) {
+	blocks, err := checker.blockRepo.GetActiveBlocks(userId)
+	if err != nil {
+		return nil, err
+	}
+
+	standing, err := checker.blockRepo.GetStanding(userId)
+	if err != nil {
+		return nil, err
+	}
+
+	return append(blocks, Automatic(standing, checker.policy, time.Now())...), nil
+}
+
+// Check refuses with the first block on userId, nil when there is none.
+func (checker *Checker) Check(userId string) error {
+	blocks, err := checker.Blocks(userId)
+	if err != nil {
+		return err
+	}
+	if len(blocks) == 0 {
+		return nil
+	}
+
+	block := blocks[0]
+	blocked := apperrors.Conflict("patron_blocked", "borrowing is blocked: "+block.Reason).
+		WithDetail("kind", string(block.Kind))
+	if block.ID != uuid.Nil {
+		blocked = blocked.WithDetail("block_id", block.ID.String())
+	}
+	return blocked
+}
+
+// Automatic lists the blocks standing triggers under the thresholds of
+// policy at time now.
+func Automatic(standing models.Standing, policy config.CirculationConfig, now time.Time) []models.Block {
+	var blocks []models.Block
+
+	if policy.BlockOverdueLoans > 0 && standing.OverdueLoans >= policy.BlockOverdueLoans {
+		blocks = append(blocks, models.Block{
+			Kind:   blockkind.Overdue,
+			Reason: fmt.Sprintf("%d overdue loans reach the limit of %d", standing.OverdueLoans, policy.BlockOverdueLoans),
+		})
+	}
+
+	if policy.BlockUnpaidFines > 0 && standing.UnpaidFines >= policy.BlockUnpaidFines {
+		blocks = append(blocks, models.Block{
+			Kind:   blockkind.Fines,
+			Reason: fmt.Sprintf("unpaid fines of %d reach the limit of %d", standing.UnpaidFines, policy.BlockUnpaidFines),
+		})
+	}
+
+	if standing.MembershipExpiresAt != nil && !standing.MembershipExpiresAt.After(now) {
+		blocks = append(blocks, models.Block{
+			Kind:   blockkind.Membership,
+			Reason: "membership expired on " + standing.MembershipExpiresAt.Format(time.DateOnly),
+		})
+	}
+
+	return blocks
+}
@@ -0,0 +1,140 @@
+package patronblocks
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	apperrors "github.com/Kaushik1766/LibraryManagement/internal/app_errors"
+	"github.com/Kaushik1766/LibraryManagement/internal/config"
+	"github.com/Kaushik1766/LibraryManagement/internal/models"
+	"github.com/Kaushik1766/LibraryManagement/internal/models/enums/blockkind"
+	"github.com/Kaushik1766/LibraryManagement/mocks"
+	"github.com/google/uuid"
+	"go.uber.org/mock/gomock"
+)
+
+func TestAutomatic(t *testing.T) {
+	policy := config.Default().Circulation
+	now := time.Date(2024, 3, 10, 12, 0, 0, 0, time.UTC)
+	expired := time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC)
+	current := time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		standing models.Standing
+		policy   func(*config.CirculationConfig)
+		want     []blockkind.BlockKind
+	}{
+		{
+			name:     "good standing",
+			standing: models.Standing{OverdueLoans: 2, UnpaidFines: 999, MembershipExpiresAt: &current},
+			want:     nil,
+		},
+		{
+			name:     "too many overdue loans",
+			standing: models.Standing{OverdueLoans: 3},
+			want:     []blockkind.BlockKind{blockkind.Overdue},
+		},
+		{
+			name:     "unpaid fines and expired membership",
+			standing: models.Standing{UnpaidFines: 1000, MembershipExpiresAt: &expired},
+			want:     []blockkind.BlockKind{blockkind.Fines, blockkind.Membership},
+		},
+		{
+			name:     "thresholds disabled",
+			standing: models.Standing{OverdueLoans: 10, UnpaidFines: 10000},
+			policy: func(policy *config.CirculationConfig) {
+				policy.BlockOverdueLoans = 0
+				policy.BlockUnpaidFines = 0
+			},
+			want: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policy := policy
+			if tt.policy != nil {
+				tt.policy(&policy)
+			}
+			var got []blockkind.BlockKind
+			for _, block := range Automatic(tt.standing, policy, now) {
+				got = append(got, block.Kind)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Automatic() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestChecker_Check(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockBlockRepo := mocks.NewMockBlockStorage(ctrl)
+	checker := NewChecker(mockBlockRepo, config.Default().Circulation)
+	userId := uuid.New().String()
+	manual := models.Block{ID: uuid.New(), Kind: blockkind.Manual, Reason: "lost a book"}
+
+	tests := []struct {
+		name       string
+		wantErr    bool
+		wantDetail map[string]string
+		mockSetup  func()
+	}{
+		{
+			name:    "not blocked",
+			wantErr: false,
+			mockSetup: func() {
+				mockBlockRepo.EXPECT().GetActiveBlocks(userId).Return(nil, nil)
+				mockBlockRepo.EXPECT().GetStanding(userId).Return(models.Standing{}, nil)
+			},
+		},
+		{
+			name:       "manual block comes first",
+			wantErr:    true,
+			wantDetail: map[string]string{"kind": "manual", "block_id": manual.ID.String()},
+			mockSetup: func() {
+				mockBlockRepo.EXPECT().GetActiveBlocks(userId).Return([]models.Block{manual}, nil)
+				mockBlockRepo.EXPECT().GetStanding(userId).Return(models.Standing{OverdueLoans: 5}, nil)
+			},
+		},
+		{
+			name:       "automatic block",
+			wantErr:    true,
+			wantDetail: map[string]string{"kind": "overdue"},
+			mockSetup: func() {
+				mockBlockRepo.EXPECT().GetActiveBlocks(userId).Return(nil, nil)
+				mockBlockRepo.EXPECT().GetStanding(userId).Return(models.Standing{OverdueLoans: 5}, nil)
+			},
+		},
+		{
+			name:    "repository error",
+			wantErr: true,
+			mockSetup: func() {
+				mockBlockRepo.EXPECT().GetActiveBlocks(userId).Return(nil, errors.New("database error"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockSetup()
+			err := checker.Check(userId)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Check() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantDetail == nil {
+				return
+			}
+			var appErr *apperrors.Error
+			if !errors.As(err, &appErr) || appErr.Code != "patron_blocked" {
+				t.Fatalf("Check() error = %v, want patron_blocked", err)
+			}
+			if !reflect.DeepEqual(appErr.Details, tt.wantDetail) {
+				t.Errorf("Check() details = %v, want %v", appErr.Details, tt.wantDetail)
+			}
+		})
+	}
+}
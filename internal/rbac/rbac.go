@@ -0,0 +1,78 @@
+package rbac
+
+import "github.com/Kaushik1766/LibraryManagement/internal/models/enums/roles"
+
+type Permission string
+
+const (
+	BookCreate Permission = "book:create"
+	BookUpdate Permission = "book:update"
+	BookDelete Permission = "book:delete"
+	// BookImport adds titles in bulk from a file.
+	BookImport Permission = "book:import"
+	// BookExport dumps the whole catalog as MARC.
+	BookExport Permission = "book:export"
+
+	// LoanBorrow lets a patron issue, return and renew books for themselves.
+	LoanBorrow         Permission = "loan:borrow"
+	LoanIssueForOthers Permission = "loan:issue-for-others"
+	LoanViewAll        Permission = "loan:view-all"
+
+	HoldPlace  Permission = "hold:place"
+	HoldManage Permission = "hold:manage"
+
+	FinesViewAll Permission = "fines:view-all"
+	FinesCollect Permission = "fines:collect"
+	FinesWaive   Permission = "fines:waive"
+
+	// PatronView shows who a copy, hold or fine belongs to.
+	PatronView Permission = "patron:view"
+	// PatronBlock places and lifts blocks on borrowing.
+	PatronBlock Permission = "patron:block"
+	// UsersUpdate edits and deactivates patron accounts.
+	UsersUpdate Permission = "users:update"
+	// UsersManage changes roles and edits accounts of any role.
+	UsersManage Permission = "users:manage"
+)
+
+var librarian = []Permission{
+	BookCreate,
+	BookUpdate,
+	BookExport,
+	LoanIssueForOthers,
+	LoanViewAll,
+	HoldManage,
+	FinesViewAll,
+	FinesCollect,
+	PatronView,
+}
+
+var staff = append([]Permission{
+	BookDelete,
+	BookImport,
+	FinesWaive,
+	PatronBlock,
+	UsersUpdate,
+}, librarian...)
+
+var admin = append([]Permission{
+	UsersManage,
+}, staff...)
+
+var matrix = map[roles.UserRoles][]Permission{
+	roles.Customer:  {LoanBorrow, HoldPlace},
+	roles.Librarian: librarian,
+	roles.Staff:     staff,
+	roles.Admin:     admin,
+}
+
+// Can reports whether role has been granted perm. Unknown roles have no
+// permissions.
+func Can(role roles.UserRoles, perm Permission) bool {
+	for _, granted := range matrix[role] {
+		if granted == perm {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,108 @@
+package rbac
+
+import (
+	"testing"
+
+	"github.com/Kaushik1766/LibraryManagement/internal/models/enums/roles"
+)
+
+func TestCan(t *testing.T) {
+	tests := []struct {
+		name string
+		role roles.UserRoles
+		perm Permission
+		want bool
+	}{
+		{
+			name: "customer can borrow",
+			role: roles.Customer,
+			perm: LoanBorrow,
+			want: true,
+		},
+		{
+			name: "customer cannot add books",
+			role: roles.Customer,
+			perm: BookCreate,
+			want: false,
+		},
+		{
+			name: "librarian can collect fines",
+			role: roles.Librarian,
+			perm: FinesCollect,
+			want: true,
+		},
+		{
+			name: "librarian cannot waive fines",
+			role: roles.Librarian,
+			perm: FinesWaive,
+			want: false,
+		},
+		{
+			name: "staff can waive fines",
+			role: roles.Staff,
+			perm: FinesWaive,
+			want: true,
+		},
+		{
+			name: "librarian can export the catalog",
+			role: roles.Librarian,
+			perm: BookExport,
+			want: true,
+		},
+		{
+			name: "librarian cannot import books",
+			role: roles.Librarian,
+			perm: BookImport,
+			want: false,
+		},
+		{
+			name: "librarian cannot block patrons",
+			role: roles.Librarian,
+			perm: PatronBlock,
+			want: false,
+		},
+		{
+			name: "staff can block patrons",
+			role: roles.Staff,
+			perm: PatronBlock,
+			want: true,
+		},
+		{
+			name: "staff can update patrons",
+			role: roles.Staff,
+			perm: UsersUpdate,
+			want: true,
+		},
+		{
+			name: "staff cannot manage users",
+			role: roles.Staff,
+			perm: UsersManage,
+			want: false,
+		},
+		{
+			name: "admin can manage users",
+			role: roles.Admin,
+			perm: UsersManage,
+			want: true,
+		},
+		{
+			name: "admin cannot borrow",
+			role: roles.Admin,
+			perm: LoanBorrow,
+			want: false,
+		},
+		{
+			name: "unknown role",
+			role: roles.UserRoles(999),
+			perm: LoanBorrow,
+			want: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Can(tt.role, tt.perm); got != tt.want {
+				t.Errorf("Can() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,15 @@
+package blockrepo
+
+import (
+	"time"
+
+	"github.com/Kaushik1766/LibraryManagement/internal/models"
+)
+
+//go:generate mockgen -source=interface.go -destination=../../../mocks/mock_block_storage.go -package=mocks
+type BlockStorage interface {
+	PlaceBlock(userId, reason string, expiresAt *time.Time, placedBy string) (string, error)
+	LiftBlock(userId, blockId, liftedBy string) error
+	GetActiveBlocks(userId string) ([]models.Block, error)
+	GetStanding(userId string) (models.Standing, error)
+}
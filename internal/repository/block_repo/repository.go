@@ -0,0 +1,108 @@
+package blockrepo
+
+import (
+	"database/sql"
+	"time"
+
+	apperrors "github.com/Kaushik1766/LibraryManagement/internal/app_errors"
+	"github.com/Kaushik1766/LibraryManagement/internal/models"
+	"github.com/Kaushik1766/LibraryManagement/internal/models/enums/blockkind"
+)
+
+type BlockRepository struct {
+	db *sql.DB
+}
+
+func NewBlockRepository(db *sql.DB) *BlockRepository {
+	return &BlockRepository{
+		db: db,
+	}
+}
+
+func (repo *BlockRepository) PlaceBlock(userId, reason string, expiresAt *time.Time, placedBy string) (string, error) {
+	var id string
+	err := repo.db.QueryRow(`
+		insert into patron_blocks (user_id, reason, placed_by, expires_at)
+		values ($1, $2, $3, $4)
+		returning id
+`, userId, reason, placedBy, expiresAt).Scan(&id)
+	if err != nil {
+		return "", apperrors.FromPostgres(err)
+	}
+	return id, nil
+}
+
+// LiftBlock ends a manual block of userId early. Blocks that already expired
+// or were lifted are not found.
+func (repo *BlockRepository) LiftBlock(userId, blockId, liftedBy string) error {
+	res, err := repo.db.Exec(`
+		update patron_blocks set lifted_at = now(), lifted_by = $3
+		where id = $2 and user_id = $1
+		and lifted_at is null
+		and (expires_at is null or expires_at > now())
+`, userId, blockId, liftedBy)
+	if err != nil {
+		return apperrors.FromPostgres(err)
+	}
+
+	rowsAffected, _ := res.RowsAffected()
+	if rowsAffected == 0 {
+		return apperrors.NotFound("block_not_found", "block not found")
+	}
+	return nil
+}
+
+// GetActiveBlocks returns the manual blocks of userId in force right now,
+// oldest first.
+func (repo *BlockRepository) GetActiveBlocks(userId string) ([]models.Block, error) {
+	rows, err := repo.db.Query(`
+	select b.id, b.user_id, b.reason, b.placed_at, b.expires_at, p.id, p.email
+	from patron_blocks as b
+	join users as p on b.placed_by = p.id
+	where b.user_id = $1
+	and b.lifted_at is null
+	and (b.expires_at is null or b.expires_at > now())
+	order by b.placed_at
+`, userId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var blocks []models.Block
+	for rows.Next() {
+		block := models.Block{Kind: blockkind.Manual, PlacedBy: &models.User{}}
+		var expiresAt sql.Null[time.Time]
+		err := rows.Scan(&block.ID, &block.User.ID, &block.Reason, &block.PlacedAt, &expiresAt, &block.PlacedBy.ID, &block.PlacedBy.Email)
+		if err != nil {
+			return nil, err
+		}
+		if expiresAt.Valid {
+			block.ExpiresAt = &expiresAt.V
+		}
+		blocks = append(blocks, block)
+	}
+	return blocks, rows.Err()
+}
+
+// GetStanding counts the overdue loans and unpaid fines of userId.
+func (repo *BlockRepository) GetStanding(userId string) (models.Standing, error) {
+	var standing models.Standing
+	var membershipExpiresAt sql.Null[time.Time]
+	err := repo.db.QueryRow(`
+	select
+	    (select count(*) from transactions
+	     where user_id = $1 and returned_at is null and issued_till < now()),
+	    coalesce((select sum(f.amount) from fines as f where f.user_id = $1), 0) -
+	    coalesce((select sum(p.amount) from payments as p join fines as f on p.fine_id = f.id where f.user_id = $1), 0),
+	    (select membership_expires_at from users where id = $1)
+`, userId).Scan(&standing.OverdueLoans, &standing.UnpaidFines, &membershipExpiresAt)
+	if err != nil {
+		return standing, apperrors.FromPostgres(err)
+	}
+
+	if membershipExpiresAt.Valid {
+		standing.MembershipExpiresAt = &membershipExpiresAt.V
+	}
+	return standing, nil
+}
@@ -0,0 +1,265 @@
+package blockrepo
+
+import (
+	"database/sql"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/Kaushik1766/LibraryManagement/internal/models"
+	"github.com/Kaushik1766/LibraryManagement/internal/models/enums/blockkind"
+	"github.com/google/uuid"
+)
+
+func TestNewBlockRepository(t *testing.T) {
+	db, _, _ := sqlmock.New()
+	defer db.Close()
+
+	if got := NewBlockRepository(db); !reflect.DeepEqual(got, &BlockRepository{db: db}) {
+		t.Errorf("NewBlockRepository() = %v, want %v", got, &BlockRepository{db: db})
+	}
+}
+
+func TestBlockRepository_PlaceBlock(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	blockId := uuid.New().String()
+	userId := uuid.New().String()
+	staffId := uuid.New().String()
+	expiresAt := time.Now().Add(24 * time.Hour)
+
+	tests := []struct {
+		name      string
+		expiresAt *time.Time
+		want      string
+		wantErr   bool
+		mockSetup func()
+	}{
+		{
+			name:      "valid block",
+			expiresAt: &expiresAt,
+			want:      blockId,
+			wantErr:   false,
+			mockSetup: func() {
+				mock.ExpectQuery("(?i)insert into patron_blocks .*").
+					WithArgs(userId, "lost a book", staffId, &expiresAt).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(blockId))
+			},
+		},
+		{
+			name:    "unknown user",
+			want:    "",
+			wantErr: true,
+			mockSetup: func() {
+				mock.ExpectQuery("(?i)insert into patron_blocks .*").
+					WillReturnError(errors.New("database error"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &BlockRepository{
+				db: db,
+			}
+			tt.mockSetup()
+			got, err := repo.PlaceBlock(userId, "lost a book", tt.expiresAt, staffId)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("PlaceBlock() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("PlaceBlock() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBlockRepository_LiftBlock(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	tests := []struct {
+		name      string
+		wantErr   bool
+		mockSetup func()
+	}{
+		{
+			name:    "valid lift",
+			wantErr: false,
+			mockSetup: func() {
+				mock.ExpectExec("(?i)update patron_blocks set lifted_at = now().*").WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+		},
+		{
+			name:    "block not active",
+			wantErr: true,
+			mockSetup: func() {
+				mock.ExpectExec("(?i)update patron_blocks set lifted_at = now().*").WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+		},
+		{
+			name:    "database error",
+			wantErr: true,
+			mockSetup: func() {
+				mock.ExpectExec("(?i)update patron_blocks set lifted_at = now().*").WillReturnError(errors.New("database error"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &BlockRepository{
+				db: db,
+			}
+			tt.mockSetup()
+			if err := repo.LiftBlock(uuid.New().String(), uuid.New().String(), uuid.New().String()); (err != nil) != tt.wantErr {
+				t.Errorf("LiftBlock() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestBlockRepository_GetActiveBlocks(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	userId := uuid.New()
+	staff := &models.User{ID: uuid.New(), Email: "librarian@a.com"}
+	expiresAt := time.Now().Add(24 * time.Hour)
+	block1 := models.Block{
+		ID:       uuid.New(),
+		User:     models.User{ID: userId},
+		Kind:     blockkind.Manual,
+		Reason:   "lost a book",
+		PlacedBy: staff,
+		PlacedAt: time.Now(),
+	}
+	block2 := models.Block{
+		ID:        uuid.New(),
+		User:      models.User{ID: userId},
+		Kind:      blockkind.Manual,
+		Reason:    "damaged a dvd",
+		PlacedBy:  staff,
+		PlacedAt:  time.Now(),
+		ExpiresAt: &expiresAt,
+	}
+	columns := []string{"id", "user_id", "reason", "placed_at", "expires_at", "staff_id", "email"}
+
+	tests := []struct {
+		name      string
+		want      []models.Block
+		wantErr   bool
+		mockSetup func()
+	}{
+		{
+			name:    "valid get blocks",
+			want:    []models.Block{block1, block2},
+			wantErr: false,
+			mockSetup: func() {
+				mock.ExpectQuery("(?i)select .* from patron_blocks .* join users").
+					WithArgs(userId.String()).
+					WillReturnRows(sqlmock.NewRows(columns).
+						AddRow(block1.ID, userId, block1.Reason, block1.PlacedAt, nil, staff.ID, staff.Email).
+						AddRow(block2.ID, userId, block2.Reason, block2.PlacedAt, expiresAt, staff.ID, staff.Email))
+			},
+		},
+		{
+			name:    "no blocks",
+			want:    nil,
+			wantErr: false,
+			mockSetup: func() {
+				mock.ExpectQuery("(?i)select .* from patron_blocks .* join users").
+					WillReturnRows(sqlmock.NewRows(columns))
+			},
+		},
+		{
+			name:    "query error",
+			want:    nil,
+			wantErr: true,
+			mockSetup: func() {
+				mock.ExpectQuery("(?i)select .* from patron_blocks .* join users").
+					WillReturnError(errors.New("invalid query"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &BlockRepository{
+				db: db,
+			}
+			tt.mockSetup()
+			got, err := repo.GetActiveBlocks(userId.String())
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetActiveBlocks() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetActiveBlocks() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBlockRepository_GetStanding(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	userId := uuid.New().String()
+	expiresAt := time.Now().Add(-24 * time.Hour)
+	columns := []string{"overdue", "unpaid", "membership_expires_at"}
+
+	tests := []struct {
+		name      string
+		want      models.Standing
+		wantErr   bool
+		mockSetup func()
+	}{
+		{
+			name:    "good standing",
+			want:    models.Standing{},
+			wantErr: false,
+			mockSetup: func() {
+				mock.ExpectQuery("(?i)select .* from transactions .* from fines .* from payments .* from users").
+					WithArgs(userId).
+					WillReturnRows(sqlmock.NewRows(columns).AddRow(0, 0, nil))
+			},
+		},
+		{
+			name:    "overdue with expired membership",
+			want:    models.Standing{OverdueLoans: 2, UnpaidFines: 350, MembershipExpiresAt: &expiresAt},
+			wantErr: false,
+			mockSetup: func() {
+				mock.ExpectQuery("(?i)select .* from transactions .* from fines .* from payments .* from users").
+					WithArgs(userId).
+					WillReturnRows(sqlmock.NewRows(columns).AddRow(2, 350, expiresAt))
+			},
+		},
+		{
+			name:    "database error",
+			want:    models.Standing{},
+			wantErr: true,
+			mockSetup: func() {
+				mock.ExpectQuery("(?i)select .* from transactions .* from fines .* from payments .* from users").
+					WillReturnError(sql.ErrConnDone)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &BlockRepository{
+				db: db,
+			}
+			tt.mockSetup()
+			got, err := repo.GetStanding(userId)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetStanding() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetStanding() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
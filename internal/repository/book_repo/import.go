@@ -0,0 +1,83 @@
+package bookrepo
+
+import (
+	"database/sql"
+	"errors"
+	"strconv"
+
+	apperrors "github.com/Kaushik1766/LibraryManagement/internal/app_errors"
+	"github.com/Kaushik1766/LibraryManagement/internal/models"
+	"github.com/Kaushik1766/LibraryManagement/internal/models/enums/importstatus"
+)
+
+// findDuplicate looks for a title with isbn $1, ignoring hyphens, or the same
+// $2 title and $3 author ignoring case. Withdrawn titles still hold on to
+// their isbn so they are only skipped for the title and author match.
+const findDuplicate = `
+	select id from titles
+	where ($1<>'' and replace(isbn, '-', '') = replace($1, '-', ''))
+	or (withdrawn_at is null and lower(title) = lower($2) and lower(author) = lower($3))
+	limit 1
+`
+
+// ImportBooks adds the rows as new titles in a single transaction, skipping
+// any that duplicate an existing title or an earlier row. With dryRun the
+// transaction is rolled back once every row has been checked.
+func (repo *BookRepository) ImportBooks(rows []models.ImportRow, dryRun bool) ([]models.ImportRowResult, error) {
+	tx, err := repo.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	results := make([]models.ImportRowResult, 0, len(rows))
+	for _, row := range rows {
+		result := models.ImportRowResult{Line: row.Line}
+
+		var existing string
+		err := tx.QueryRow(findDuplicate, row.Book.ISBN, row.Book.Title, row.Book.Author).Scan(&existing)
+		switch {
+		case err == nil:
+			result.Status = importstatus.Duplicate
+			result.BookID = existing
+		case errors.Is(err, sql.ErrNoRows):
+			result.Status = importstatus.Imported
+			result.BookID, err = insertTitle(tx, toBook(row.Book), row.Book.Copies, row.Book.Location)
+			if err != nil {
+				return nil, lineError(row.Line, err)
+			}
+		default:
+			return nil, err
+		}
+
+		results = append(results, result)
+	}
+
+	if dryRun {
+		return results, nil
+	}
+	return results, tx.Commit()
+}
+
+// lineError points constraint violations at the row that caused them.
+func lineError(line int, err error) error {
+	var appErr *apperrors.Error
+	if errors.As(err, &appErr) || errors.As(apperrors.FromPostgres(err), &appErr) {
+		return appErr.WithDetail("line", strconv.Itoa(line))
+	}
+	return err
+}
+
+func toBook(dto models.AddBookDTO) models.Book {
+	return models.Book{
+		Title:       dto.Title,
+		Author:      dto.Author,
+		ISBN:        dto.ISBN,
+		Publisher:   dto.Publisher,
+		Year:        dto.Year,
+		Language:    dto.Language,
+		Subject:     dto.Subject,
+		Description: dto.Description,
+		ItemType:    dto.ItemType,
+	}
+}
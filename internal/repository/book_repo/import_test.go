@@ -0,0 +1,141 @@
+package bookrepo
+
+import (
+	"database/sql"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	apperrors "github.com/Kaushik1766/LibraryManagement/internal/app_errors"
+	"github.com/Kaushik1766/LibraryManagement/internal/models"
+	"github.com/Kaushik1766/LibraryManagement/internal/models/enums/importstatus"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+func TestBookRepository_ImportBooks(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	newId := uuid.New().String()
+	existingId := uuid.New().String()
+
+	rows := []models.ImportRow{
+		{Line: 2, Book: models.AddBookDTO{Title: "dune", Author: "frank herbert", Copies: 2, Location: "shelf a"}},
+		{Line: 3, Book: models.AddBookDTO{Title: "emma", Author: "jane austen", ISBN: "0-14-143958-7", Copies: 1}},
+	}
+
+	type fields struct {
+		db *sql.DB
+	}
+	type args struct {
+		rows   []models.ImportRow
+		dryRun bool
+	}
+	tests := []struct {
+		name      string
+		fields    fields
+		args      args
+		want      []models.ImportRowResult
+		wantErr   bool
+		wantKind  apperrors.Kind
+		mockSetup func()
+	}{
+		{
+			name:   "imports new titles and skips duplicates",
+			fields: fields{db: db},
+			args:   args{rows: rows},
+			want: []models.ImportRowResult{
+				{Line: 2, Status: importstatus.Imported, BookID: newId},
+				{Line: 3, Status: importstatus.Duplicate, BookID: existingId},
+			},
+			wantErr: false,
+			mockSetup: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery(`(?i)select id from titles.*replace\(isbn`).
+					WithArgs("", "dune", "frank herbert").
+					WillReturnError(sql.ErrNoRows)
+				mock.ExpectQuery(`(?i)insert into titles.*`).
+					WithArgs("dune", "frank herbert", "", "", 0, "", "", "", "").
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(newId))
+				mock.ExpectExec(`(?i)insert into copies.*`).
+					WithArgs(newId, 2, "shelf a").
+					WillReturnResult(sqlmock.NewResult(2, 2))
+				mock.ExpectQuery(`(?i)select id from titles.*replace\(isbn`).
+					WithArgs("0-14-143958-7", "emma", "jane austen").
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(existingId))
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name:   "dry run rolls back",
+			fields: fields{db: db},
+			args:   args{rows: rows[:1], dryRun: true},
+			want: []models.ImportRowResult{
+				{Line: 2, Status: importstatus.Imported, BookID: newId},
+			},
+			wantErr: false,
+			mockSetup: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery(`(?i)select id from titles.*`).
+					WillReturnError(sql.ErrNoRows)
+				mock.ExpectQuery(`(?i)insert into titles.*`).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(newId))
+				mock.ExpectExec(`(?i)insert into copies.*`).
+					WillReturnResult(sqlmock.NewResult(2, 2))
+				mock.ExpectRollback()
+			},
+		},
+		{
+			name:     "constraint violation names the line",
+			fields:   fields{db: db},
+			args:     args{rows: rows[:1]},
+			wantErr:  true,
+			wantKind: apperrors.KindConflict,
+			mockSetup: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery(`(?i)select id from titles.*`).
+					WillReturnError(sql.ErrNoRows)
+				mock.ExpectQuery(`(?i)insert into titles.*`).
+					WillReturnError(&pq.Error{Code: "23505"})
+				mock.ExpectRollback()
+			},
+		},
+		{
+			name:     "duplicate lookup fails",
+			fields:   fields{db: db},
+			args:     args{rows: rows[:1]},
+			wantErr:  true,
+			wantKind: apperrors.KindInternal,
+			mockSetup: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery(`(?i)select id from titles.*`).
+					WillReturnError(errors.New("db error"))
+				mock.ExpectRollback()
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &BookRepository{
+				db: tt.fields.db,
+			}
+			tt.mockSetup()
+			got, err := repo.ImportBooks(tt.args.rows, tt.args.dryRun)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ImportBooks() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr && apperrors.KindOf(err) != tt.wantKind {
+				t.Errorf("ImportBooks() error kind = %v, want %v", apperrors.KindOf(err), tt.wantKind)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ImportBooks() got = %+v, want %+v", got, tt.want)
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
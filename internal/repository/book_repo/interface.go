@@ -1,9 +1,23 @@
 package bookrepo
 
-import "github.com/Kaushik1766/LibraryManagement/internal/models"
+import (
+	"github.com/Kaushik1766/LibraryManagement/internal/models"
+	"github.com/Kaushik1766/LibraryManagement/internal/pagination"
+)
 
 //go:generate mockgen -source=interface.go -destination=../../../mocks/mock_book_storage.go -package=mocks
 type BookStorage interface {
-	AddBook(title, author string, copies int) error
-	GetAllBooks(title, author string) ([]models.Book, error)
+	AddBook(book models.Book, copies int, location string) (string, error)
+	AddCopies(bookId string, copies int, location string) error
+	ImportBooks(rows []models.ImportRow, dryRun bool) ([]models.ImportRowResult, error)
+	GetAllBooks(title, author string, page pagination.Request) (pagination.Page[models.Book], error)
+	GetBookById(bookId string) (models.Book, error)
+	EachBook(fn func(models.Book) error) error
+	SearchBooks(search models.BookSearch, page pagination.Request) (models.SearchResult, error)
+	UpdateBook(book models.Book) error
+	WithdrawBook(bookId, reason string) error
+	GetCopies(bookId string) ([]models.Copy, error)
+	GetCopyById(copyId string) (models.Copy, error)
+	GetCopyByBarcode(barcode string) (models.Copy, error)
+	WithdrawCopy(bookId, copyId, reason string) error
 }
@@ -2,10 +2,54 @@ package bookrepo
 
 import (
 	"database/sql"
+	"errors"
+	"time"
 
+	apperrors "github.com/Kaushik1766/LibraryManagement/internal/app_errors"
 	"github.com/Kaushik1766/LibraryManagement/internal/models"
+	"github.com/Kaushik1766/LibraryManagement/internal/pagination"
 )
 
+// insertCopies adds $2 copies of title $1 at location $3. Barcodes are derived
+// from the generated copy ids.
+const insertCopies = `
+	insert into copies (id, title_id, barcode, location)
+	select g.id, $1, upper(substr(replace(cast(g.id as text), '-', ''), 1, 12)), nullif($3, '')
+	from (select uuid_generate_v4() as id from generate_series(1, $2)) as g
+`
+
+// bookColumns are the columns scanBook reads. The counts are how many copies
+// of a title are in circulation and how many can be issued right now, so
+// queries selecting them join copies as c and group by b.id.
+const bookColumns = `
+	b.id, b.title, b.author, b.isbn, b.publisher, b.published_year, b.language, b.subject, b.description,
+	b.item_type,
+	count(c.id) filter (where c.status <> 'withdrawn'),
+	count(c.id) filter (
+	    where c.status = 'available'
+	    and not exists(select 1 from transactions as t where t.copy_id = c.id and t.returned_at is null)
+	    and not exists(select 1 from holds as h where h.copy_id = c.id and h.status = 'ready')
+	)
+`
+
+// selectBooks returns one row per title. Callers append the where clause.
+const selectBooks = `select ` + bookColumns + `
+	from titles as b
+	left join copies as c on c.title_id = b.id
+`
+
+// bookFilter narrows titles by $1 title and $2 author, both optional.
+const bookFilter = `
+	where b.withdrawn_at is null
+	and ($1='' or b.title ilike '%'||$1||'%')
+	and ($2='' or b.author ilike '%'||$2||'%')
+`
+
+var bookSorts = map[string]pagination.Column[models.Book]{
+	"title":  {Expr: "b.title", Type: "text", Key: func(b models.Book) string { return b.Title }},
+	"author": {Expr: "b.author", Type: "text", Key: func(b models.Book) string { return b.Author }},
+}
+
 type BookRepository struct {
 	db *sql.DB
 }
@@ -16,49 +60,346 @@ func NewBookRepository(db *sql.DB) *BookRepository {
 	}
 }
 
-func (repo *BookRepository) AddBook(title, author string, copies int) error {
-	_, err := repo.db.Exec(
-		`insert into books(title, author) 
-		select $1, $2
-		from generate_series(1,$3)`,
-		title, author, copies)
-	return err
+// AddBook creates the title and its copies in one transaction and returns the
+// new title id.
+func (repo *BookRepository) AddBook(book models.Book, copies int, location string) (string, error) {
+	tx, err := repo.db.Begin()
+	if err != nil {
+		return "", err
+	}
+	defer tx.Rollback()
+
+	id, err := insertTitle(tx, book, copies, location)
+	if err != nil {
+		return "", err
+	}
+
+	return id, tx.Commit()
+}
+
+// errISBNTaken is returned when a title is saved with the isbn of another.
+var errISBNTaken = apperrors.Conflict("isbn_taken", "isbn already registered").WithDetail("isbn", "already registered")
+
+func insertTitle(tx *sql.Tx, book models.Book, copies int, location string) (string, error) {
+	var id string
+	err := tx.QueryRow(`
+		insert into titles (title, author, isbn, publisher, published_year, language, subject, description, item_type)
+		values ($1, $2, nullif($3, ''), nullif($4, ''), nullif($5, 0), nullif($6, ''), nullif($7, ''), nullif($8, ''),
+		        coalesce(nullif($9, ''), 'book'))
+		returning id
+`, book.Title, book.Author, book.ISBN, book.Publisher, book.Year, book.Language, book.Subject, book.Description, book.ItemType).Scan(&id)
+	if apperrors.KindOf(apperrors.FromPostgres(err)) == apperrors.KindConflict {
+		return "", errISBNTaken
+	}
+	if err != nil {
+		return "", apperrors.FromPostgres(err)
+	}
+
+	if _, err := tx.Exec(insertCopies, id, copies, location); err != nil {
+		return "", apperrors.FromPostgres(err)
+	}
+
+	return id, nil
+}
+
+func (repo *BookRepository) AddCopies(bookId string, copies int, location string) error {
+	res, err := repo.db.Exec(insertCopies+`
+	where exists(select 1 from titles where id = $1 and withdrawn_at is null)
+`, bookId, copies, location)
+	if err != nil {
+		return err
+	}
+
+	rowsAffected, _ := res.RowsAffected()
+	if rowsAffected == 0 {
+		return apperrors.NotFound("book_not_found", "book not found")
+	}
+	return nil
 }
 
-func (repo *BookRepository) GetAllBooks(title, author string) ([]models.Book, error) {
+func (repo *BookRepository) GetAllBooks(title, author string, page pagination.Request) (pagination.Page[models.Book], error) {
+	order, err := pagination.NewOrder(page, bookSorts, "title")
+	if err != nil {
+		return pagination.Page[models.Book]{}, err
+	}
+
+	var total int
+	err = repo.db.QueryRow(`select count(*) from titles as b`+bookFilter, title, author).Scan(&total)
+	if err != nil {
+		return pagination.Page[models.Book]{}, err
+	}
+
+	args := append([]any{title, author}, order.AfterArgs()...)
+	rows, err := repo.db.Query(selectBooks+bookFilter+`
+	and `+order.After("b.id", 3, 4)+`
+	group by b.id
+	order by `+order.By("b.id")+`
+	limit $5
+`, append(args, order.Fetch())...)
+	if err != nil {
+		return pagination.Page[models.Book]{}, err
+	}
+	defer rows.Close()
+
 	var books []models.Book
+	for rows.Next() {
+		b, err := scanBook(rows)
+		if err != nil {
+			return pagination.Page[models.Book]{}, err
+		}
+		books = append(books, b)
+	}
+	if err := rows.Err(); err != nil {
+		return pagination.Page[models.Book]{}, err
+	}
+	return order.Page(books, func(b models.Book) string { return b.ID.String() }, total), nil
+}
+
+func (repo *BookRepository) GetBookById(bookId string) (models.Book, error) {
+	row := repo.db.QueryRow(selectBooks+`
+	where b.id = $1 and b.withdrawn_at is null
+	group by b.id
+`, bookId)
+
+	book, err := scanBook(row)
+	if errors.Is(err, sql.ErrNoRows) {
+		return book, apperrors.NotFound("book_not_found", "book not found")
+	}
+	return book, err
+}
+
+// EachBook calls fn with every title in the catalog in title order, stopping
+// at the first error. Titles are streamed so a full dump isn't held in memory.
+func (repo *BookRepository) EachBook(fn func(models.Book) error) error {
+	rows, err := repo.db.Query(selectBooks + `
+	where b.withdrawn_at is null
+	group by b.id
+	order by b.title, b.id
+`)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		book, err := scanBook(rows)
+		if err != nil {
+			return err
+		}
+		if err := fn(book); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// UpdateBook overwrites the bibliographic details of a title.
+func (repo *BookRepository) UpdateBook(book models.Book) error {
+	res, err := repo.db.Exec(`
+		update titles set title = $2, author = $3, isbn = nullif($4, ''), publisher = nullif($5, ''),
+		                  published_year = nullif($6, 0), language = nullif($7, ''),
+		                  subject = nullif($8, ''), description = nullif($9, ''),
+		                  item_type = coalesce(nullif($10, ''), item_type)
+		where id = $1 and withdrawn_at is null
+`, book.ID, book.Title, book.Author, book.ISBN, book.Publisher, book.Year, book.Language, book.Subject, book.Description, book.ItemType)
+	if apperrors.KindOf(apperrors.FromPostgres(err)) == apperrors.KindConflict {
+		return errISBNTaken
+	}
+	if err != nil {
+		return apperrors.FromPostgres(err)
+	}
+
+	rowsAffected, _ := res.RowsAffected()
+	if rowsAffected == 0 {
+		return apperrors.NotFound("book_not_found", "book not found")
+	}
+	return nil
+}
+
+// WithdrawBook takes a title and every copy of it out of circulation and
+// cancels the holds queued on it. Titles with a copy on loan are refused.
+func (repo *BookRepository) WithdrawBook(bookId, reason string) error {
+	tx, err := repo.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var issued bool
+	err = tx.QueryRow(`
+		select exists(
+		    select 1 from transactions as t join copies as c on t.copy_id = c.id
+		    where c.title_id = $1 and t.returned_at is null
+		)
+		from titles where id = $1 and withdrawn_at is null
+		for update
+`, bookId).Scan(&issued)
+	if errors.Is(err, sql.ErrNoRows) {
+		return apperrors.NotFound("book_not_found", "book not found")
+	}
+	if err != nil {
+		return err
+	}
+	if issued {
+		return apperrors.Conflict("copies_on_loan", "book has copies issued and cannot be withdrawn")
+	}
+
+	if _, err := tx.Exec(`
+		update titles set withdrawn_at = now(), withdrawn_reason = $2 where id = $1
+`, bookId, reason); err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec(`
+		update copies set status = 'withdrawn', withdrawn_at = now(), withdrawn_reason = $2
+		where title_id = $1 and status <> 'withdrawn'
+`, bookId, reason); err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec(`
+		update holds set status = 'cancelled', closed_at = now()
+		where title_id = $1 and status in ('waiting', 'ready')
+`, bookId); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// WithdrawCopy takes a single copy out of circulation. Copies on loan are
+// refused, a hold the copy was set aside for goes back to waiting.
+func (repo *BookRepository) WithdrawCopy(bookId, copyId, reason string) error {
+	tx, err := repo.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var issued bool
+	err = tx.QueryRow(`
+		select exists(select 1 from transactions where copy_id = $1 and returned_at is null)
+		from copies where id = $1 and title_id = $2 and status <> 'withdrawn'
+		for update
+`, copyId, bookId).Scan(&issued)
+	if errors.Is(err, sql.ErrNoRows) {
+		return apperrors.NotFound("copy_not_found", "copy not found")
+	}
+	if err != nil {
+		return err
+	}
+	if issued {
+		return apperrors.Conflict("copy_on_loan", "copy is issued and cannot be withdrawn")
+	}
+
+	if _, err := tx.Exec(`
+		update copies set status = 'withdrawn', withdrawn_at = now(), withdrawn_reason = $2 where id = $1
+`, copyId, reason); err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec(`
+		update holds set status = 'waiting', copy_id = null, ready_at = null, expires_at = null
+		where copy_id = $1 and status = 'ready'
+`, copyId); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// GetCopies lists the copies of a title with their effective status and, for
+// copies on loan, who has them.
+func (repo *BookRepository) GetCopies(bookId string) ([]models.Copy, error) {
+	var copies []models.Copy
 	rows, err := repo.db.Query(`
-	select b.id, b.title, b.author, u.email from books as b left join transactions as t
-	on t.id = (
-	    select t1.id from transactions as t1
-	                where t1.book_id = b.id
-					order by t1.issued_at desc 
-					limit 1
-	)
-	left join users as u
-	on t.user_id = u.id and t.returned_at is null
-	where ($1='' or b.title ilike '%'||$1||'%')
-	and ($2='' or b.author ilike '%'||$2||'%')
-`, title, author)
+	select c.id, c.title_id, c.barcode, c.location, u.email, c.withdrawn_at, c.withdrawn_reason,
+	       case
+	           when t.id is not null then 'on_loan'
+	           when exists(select 1 from holds as h where h.copy_id = c.id and h.status = 'ready') then 'on_hold'
+	           else c.status
+	       end
+	from copies as c
+	left join transactions as t on t.copy_id = c.id and t.returned_at is null
+	left join users as u on t.user_id = u.id
+	where c.title_id = $1
+	order by c.barcode
+`, bookId)
 	if err != nil {
 		return nil, err
 	}
+	defer rows.Close()
 
 	for rows.Next() {
-		var b models.Book
-		b.IssuedTo = &models.User{}
-		var email sql.NullString
-		err := rows.Scan(&b.ID, &b.Title, &b.Author, &email)
+		var c models.Copy
+		var location, email, withdrawnReason sql.NullString
+		var withdrawnAt sql.Null[time.Time]
+		err := rows.Scan(&c.ID, &c.BookID, &c.Barcode, &location, &email, &withdrawnAt, &withdrawnReason, &c.Status)
 		if err != nil {
 			return nil, err
 		}
 
+		c.Location = location.String
 		if email.Valid {
-			b.IssuedTo.Email = email.String
-		} else {
-			b.IssuedTo = nil
+			c.IssuedTo = &models.User{Email: email.String}
 		}
-		books = append(books, b)
+		if withdrawnAt.Valid {
+			c.WithdrawnAt = &withdrawnAt.V
+		}
+		c.WithdrawnReason = withdrawnReason.String
+		copies = append(copies, c)
 	}
-	return books, nil
+	return copies, rows.Err()
+}
+
+func (repo *BookRepository) GetCopyById(copyId string) (models.Copy, error) {
+	var c models.Copy
+	var location sql.NullString
+	err := repo.db.QueryRow(`
+	select id, title_id, barcode, status, location from copies where id = $1
+`, copyId).Scan(&c.ID, &c.BookID, &c.Barcode, &c.Status, &location)
+	if errors.Is(err, sql.ErrNoRows) {
+		return c, apperrors.NotFound("copy_not_found", "copy not found")
+	}
+
+	c.Location = location.String
+	return c, err
+}
+
+func (repo *BookRepository) GetCopyByBarcode(barcode string) (models.Copy, error) {
+	var c models.Copy
+	var location sql.NullString
+	err := repo.db.QueryRow(`
+	select id, title_id, barcode, status, location from copies where barcode = $1
+`, barcode).Scan(&c.ID, &c.BookID, &c.Barcode, &c.Status, &location)
+	if errors.Is(err, sql.ErrNoRows) {
+		return c, apperrors.NotFound("copy_not_found", "copy not found")
+	}
+
+	c.Location = location.String
+	return c, err
+}
+
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+// scanBook reads bookColumns followed by any extra columns into extra.
+func scanBook(row scanner, extra ...any) (models.Book, error) {
+	var b models.Book
+	var isbn, publisher, language, subject, description sql.NullString
+	var year sql.NullInt64
+	dest := []any{&b.ID, &b.Title, &b.Author, &isbn, &publisher, &year, &language, &subject, &description, &b.ItemType, &b.TotalCopies, &b.AvailableCopies}
+	err := row.Scan(append(dest, extra...)...)
+	if err != nil {
+		return b, err
+	}
+
+	b.ISBN = isbn.String
+	b.Publisher = publisher.String
+	b.Year = int(year.Int64)
+	b.Language = language.String
+	b.Subject = subject.String
+	b.Description = description.String
+	return b, nil
 }
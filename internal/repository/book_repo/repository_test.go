@@ -5,10 +5,15 @@ import (
 	"errors"
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
+	apperrors "github.com/Kaushik1766/LibraryManagement/internal/app_errors"
 	"github.com/Kaushik1766/LibraryManagement/internal/models"
+	"github.com/Kaushik1766/LibraryManagement/internal/models/enums/copystatus"
+	"github.com/Kaushik1766/LibraryManagement/internal/pagination"
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 )
 
 func TestBookRepository_AddBook(t *testing.T) {
@@ -16,18 +21,21 @@ func TestBookRepository_AddBook(t *testing.T) {
 	db, mock, _ := sqlmock.New()
 	defer db.Close()
 
+	bookId := uuid.New().String()
+
 	type fields struct {
 		db *sql.DB
 	}
 	type args struct {
-		title  string
-		author string
-		copies int
+		book     models.Book
+		copies   int
+		location string
 	}
 	tests := []struct {
 		name      string
 		fields    fields
 		args      args
+		want      string
 		wantErr   bool
 		mockSetup func()
 	}{
@@ -37,15 +45,21 @@ func TestBookRepository_AddBook(t *testing.T) {
 				db: db,
 			},
 			args: args{
-				title:  "asdf",
-				author: "asdf",
-				copies: 2,
+				book:     models.Book{Title: "asdf", Author: "asdf", ISBN: "9780747532699"},
+				copies:   2,
+				location: "shelf a",
 			},
+			want:    bookId,
 			wantErr: false,
 			mockSetup: func() {
-				mock.ExpectExec(`(?i)insert into books.*`).
-					WithArgs("asdf", "asdf", 2).
-					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectBegin()
+				mock.ExpectQuery(`(?i)insert into titles.*`).
+					WithArgs("asdf", "asdf", "9780747532699", "", 0, "", "", "", "").
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(bookId))
+				mock.ExpectExec(`(?i)insert into copies.*`).
+					WithArgs(bookId, 2, "shelf a").
+					WillReturnResult(sqlmock.NewResult(2, 2))
+				mock.ExpectCommit()
 			},
 		},
 		{
@@ -54,15 +68,39 @@ func TestBookRepository_AddBook(t *testing.T) {
 				db: db,
 			},
 			args: args{
-				title:  "asdf",
-				author: "asdf",
+				book:   models.Book{Title: "asdf", Author: "asdf"},
 				copies: 2,
 			},
+			want:    "",
 			wantErr: true,
 			mockSetup: func() {
-				mock.ExpectExec(`(?i)insert into books.*`).
-					WithArgs("asdf", "asdf", 2).
+				mock.ExpectBegin()
+				mock.ExpectQuery(`(?i)insert into titles.*`).
+					WithArgs("asdf", "asdf", "", "", 0, "", "", "", "").
 					WillReturnError(errors.New("invalid add book"))
+				mock.ExpectRollback()
+			},
+		},
+		{
+			name: "copies insert fails",
+			fields: fields{
+				db: db,
+			},
+			args: args{
+				book:   models.Book{Title: "asdf", Author: "asdf"},
+				copies: 2,
+			},
+			want:    "",
+			wantErr: true,
+			mockSetup: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery(`(?i)insert into titles.*`).
+					WithArgs("asdf", "asdf", "", "", 0, "", "", "", "").
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(bookId))
+				mock.ExpectExec(`(?i)insert into copies.*`).
+					WithArgs(bookId, 2, "").
+					WillReturnError(errors.New("db error"))
+				mock.ExpectRollback()
 			},
 		},
 	}
@@ -72,8 +110,66 @@ func TestBookRepository_AddBook(t *testing.T) {
 				db: tt.fields.db,
 			}
 			tt.mockSetup()
-			if err := repo.AddBook(tt.args.title, tt.args.author, tt.args.copies); (err != nil) != tt.wantErr {
+			got, err := repo.AddBook(tt.args.book, tt.args.copies, tt.args.location)
+			if (err != nil) != tt.wantErr {
 				t.Errorf("AddBook() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("AddBook() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBookRepository_AddCopies(t *testing.T) {
+
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	bookId := uuid.New().String()
+
+	tests := []struct {
+		name      string
+		wantErr   bool
+		mockSetup func()
+	}{
+		{
+			name:    "valid add copies",
+			wantErr: false,
+			mockSetup: func() {
+				mock.ExpectExec(`(?i)insert into copies.*where exists`).
+					WithArgs(bookId, 3, "").
+					WillReturnResult(sqlmock.NewResult(3, 3))
+			},
+		},
+		{
+			name:    "book not found",
+			wantErr: true,
+			mockSetup: func() {
+				mock.ExpectExec(`(?i)insert into copies.*where exists`).
+					WithArgs(bookId, 3, "").
+					WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+		},
+		{
+			name:    "db error",
+			wantErr: true,
+			mockSetup: func() {
+				mock.ExpectExec(`(?i)insert into copies.*where exists`).
+					WithArgs(bookId, 3, "").
+					WillReturnError(errors.New("db error"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &BookRepository{
+				db: db,
+			}
+			tt.mockSetup()
+			if err := repo.AddCopies(bookId, 3, ""); (err != nil) != tt.wantErr {
+				t.Errorf("AddCopies() error = %v, wantErr %v", err, tt.wantErr)
 			}
 		})
 	}
@@ -85,32 +181,46 @@ func TestBookRepository_GetAllBooks(t *testing.T) {
 	defer db.Close()
 
 	book1 := models.Book{
-		ID:       uuid.New(),
-		Title:    "harry potter",
-		Author:   "jk rowling",
-		IssuedTo: nil,
+		ID:              uuid.New(),
+		Title:           "harry potter",
+		Author:          "jk rowling",
+		ItemType:        "book",
+		TotalCopies:     2,
+		AvailableCopies: 1,
 	}
 	book2 := models.Book{
-		ID:     uuid.New(),
-		Title:  "harry potter",
-		Author: "jk rowling",
-		IssuedTo: &models.User{
-			Email: "kaushik@a.com",
-		},
+		ID:              uuid.New(),
+		Title:           "harry potter",
+		Author:          "jk rowling",
+		ISBN:            "9780747532699",
+		Publisher:       "bloomsbury",
+		Year:            1997,
+		Language:        "en",
+		Subject:         "fantasy",
+		Description:     "a boy discovers he is a wizard",
+		ItemType:        "book",
+		TotalCopies:     1,
+		AvailableCopies: 0,
 	}
 
+	columns := []string{"id", "title", "author", "isbn", "publisher", "published_year", "language", "subject", "description", "item_type", "total", "available"}
+	firstPage := pagination.Request{Limit: pagination.DefaultLimit}
+
 	type fields struct {
 		db *sql.DB
 	}
 	type args struct {
 		title  string
 		author string
+		page   pagination.Request
 	}
 	tests := []struct {
 		name      string
 		fields    fields
 		args      args
 		want      []models.Book
+		wantTotal int
+		wantNext  bool
 		wantErr   bool
 		mockSetup func()
 	}{
@@ -120,16 +230,21 @@ func TestBookRepository_GetAllBooks(t *testing.T) {
 			args: args{
 				title:  book1.Title,
 				author: "",
+				page:   firstPage,
 			},
 			want: []models.Book{
 				book1,
 			},
-			wantErr: false,
+			wantTotal: 1,
+			wantErr:   false,
 			mockSetup: func() {
-				mock.ExpectQuery("(?i)select .* from books .* left join transactions .* left join users").
+				mock.ExpectQuery("(?i)select count\\(\\*\\) from titles").
 					WithArgs(book1.Title, "").
-					WillReturnRows(sqlmock.NewRows([]string{"id", "title", "author", "email"}).
-						AddRow(book1.ID, book1.Title, book1.Author, nil))
+					WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+				mock.ExpectQuery("(?i)select .* from titles .* left join copies .* order by b.title asc, b.id asc").
+					WithArgs(book1.Title, "", "", "", pagination.DefaultLimit+1).
+					WillReturnRows(sqlmock.NewRows(columns).
+						AddRow(book1.ID, book1.Title, book1.Author, nil, nil, nil, nil, nil, nil, "book", 2, 1))
 			},
 		},
 		{
@@ -138,47 +253,133 @@ func TestBookRepository_GetAllBooks(t *testing.T) {
 			args: args{
 				title:  book1.Title,
 				author: "",
+				page:   firstPage,
 			},
 			want:    nil,
 			wantErr: true,
 			mockSetup: func() {
-				mock.ExpectQuery("(?i)select .* from books .* left join transactions .* left join users").
+				mock.ExpectQuery("(?i)select count\\(\\*\\) from titles").
 					WithArgs(book1.Title, "").
+					WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+				mock.ExpectQuery("(?i)select .* from titles .* left join copies").
+					WithArgs(book1.Title, "", "", "", pagination.DefaultLimit+1).
 					WillReturnError(errors.New("error retrieving books"))
 			},
 		},
 		{
-			name:   "book issued to user",
+			name:   "connection lost while reading",
+			fields: fields{db: db},
+			args: args{
+				title:  book1.Title,
+				author: "",
+				page:   firstPage,
+			},
+			want:    nil,
+			wantErr: true,
+			mockSetup: func() {
+				mock.ExpectQuery("(?i)select count\\(\\*\\) from titles").
+					WithArgs(book1.Title, "").
+					WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(2))
+				mock.ExpectQuery("(?i)select .* from titles .* left join copies").
+					WithArgs(book1.Title, "", "", "", pagination.DefaultLimit+1).
+					WillReturnRows(sqlmock.NewRows(columns).
+						AddRow(book1.ID, book1.Title, book1.Author, nil, nil, nil, nil, nil, nil, "book", 2, 1).
+						AddRow(book2.ID, book2.Title, book2.Author, nil, nil, nil, nil, nil, nil, "book", 1, 0).
+						RowError(1, errors.New("connection reset")))
+			},
+		},
+		{
+			name:   "count error",
+			fields: fields{db: db},
+			args: args{
+				title:  book1.Title,
+				author: "",
+				page:   firstPage,
+			},
+			want:    nil,
+			wantErr: true,
+			mockSetup: func() {
+				mock.ExpectQuery("(?i)select count\\(\\*\\) from titles").
+					WithArgs(book1.Title, "").
+					WillReturnError(errors.New("error counting books"))
+			},
+		},
+		{
+			name:   "book with full details",
 			fields: fields{db: db},
 			args: args{
 				title:  book2.Title,
 				author: "",
+				page:   firstPage,
 			},
 			want: []models.Book{
 				book2,
 			},
-			wantErr: false,
+			wantTotal: 1,
+			wantErr:   false,
 			mockSetup: func() {
-				mock.ExpectQuery("(?i)select .* from books .* left join transactions .* left join users").
+				mock.ExpectQuery("(?i)select count\\(\\*\\) from titles").
 					WithArgs(book2.Title, "").
-					WillReturnRows(sqlmock.NewRows([]string{"id", "title", "author", "email"}).
-						AddRow(book2.ID, book2.Title, book2.Author, book2.IssuedTo.Email))
+					WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+				mock.ExpectQuery("(?i)select .* from titles .* left join copies").
+					WithArgs(book2.Title, "", "", "", pagination.DefaultLimit+1).
+					WillReturnRows(sqlmock.NewRows(columns).
+						AddRow(book2.ID, book2.Title, book2.Author, book2.ISBN, book2.Publisher, book2.Year, book2.Language, book2.Subject, book2.Description, "book", 1, 0))
+			},
+		},
+		{
+			name:   "more books than the limit",
+			fields: fields{db: db},
+			args: args{
+				title:  "",
+				author: "",
+				page:   pagination.Request{Limit: 1, Sort: "-author"},
+			},
+			want: []models.Book{
+				book1,
+			},
+			wantTotal: 2,
+			wantNext:  true,
+			wantErr:   false,
+			mockSetup: func() {
+				mock.ExpectQuery("(?i)select count\\(\\*\\) from titles").
+					WithArgs("", "").
+					WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(2))
+				mock.ExpectQuery("(?i)select .* from titles .* order by b.author desc, b.id desc").
+					WithArgs("", "", "", "", 2).
+					WillReturnRows(sqlmock.NewRows(columns).
+						AddRow(book1.ID, book1.Title, book1.Author, nil, nil, nil, nil, nil, nil, "book", 2, 1).
+						AddRow(book2.ID, book2.Title, book2.Author, book2.ISBN, book2.Publisher, book2.Year, book2.Language, book2.Subject, book2.Description, "book", 1, 0))
 			},
 		},
+		{
+			name:   "unknown sort",
+			fields: fields{db: db},
+			args: args{
+				page: pagination.Request{Sort: "isbn"},
+			},
+			want:      nil,
+			wantErr:   true,
+			mockSetup: func() {},
+		},
 		{
 			name:   "invalid field in books",
 			fields: fields{db: db},
 			args: args{
 				title:  book1.Title,
 				author: "",
+				page:   firstPage,
 			},
 			want:    nil,
 			wantErr: true,
 			mockSetup: func() {
-				mock.ExpectQuery("(?i)select .* from books .* left join transactions .* left join users").
+				mock.ExpectQuery("(?i)select count\\(\\*\\) from titles").
 					WithArgs(book1.Title, "").
-					WillReturnRows(sqlmock.NewRows([]string{"id", "title", "author", "email"}).
-						AddRow("asdfasd", book1.Title, book1.Author, nil))
+					WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+				mock.ExpectQuery("(?i)select .* from titles .* left join copies").
+					WithArgs(book1.Title, "", "", "", pagination.DefaultLimit+1).
+					WillReturnRows(sqlmock.NewRows(columns).
+						AddRow("asdfasd", book1.Title, book1.Author, nil, nil, nil, nil, nil, nil, "book", 2, 1))
 			},
 		},
 	}
@@ -188,13 +389,583 @@ func TestBookRepository_GetAllBooks(t *testing.T) {
 				db: tt.fields.db,
 			}
 			tt.mockSetup()
-			got, err := repo.GetAllBooks(tt.args.title, tt.args.author)
+			got, err := repo.GetAllBooks(tt.args.title, tt.args.author, tt.args.page)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("GetAllBooks() error = %v, wantErr %v", err, tt.wantErr)
 				return
 			}
+			if tt.wantErr {
+				return
+			}
+			if !reflect.DeepEqual(got.Items, tt.want) {
+				t.Errorf("GetAllBooks() got = %v, want %v", got.Items, tt.want)
+			}
+			if got.Total != tt.wantTotal {
+				t.Errorf("GetAllBooks() total = %v, want %v", got.Total, tt.wantTotal)
+			}
+			if (got.NextCursor != "") != tt.wantNext {
+				t.Errorf("GetAllBooks() next cursor = %q, wantNext %v", got.NextCursor, tt.wantNext)
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
+func TestBookRepository_GetCopies(t *testing.T) {
+
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	bookId := uuid.New()
+	copy1 := models.Copy{
+		ID:       uuid.New(),
+		BookID:   bookId,
+		Barcode:  "ABC123",
+		Status:   copystatus.Available,
+		Location: "shelf a",
+	}
+	copy2 := models.Copy{
+		ID:      uuid.New(),
+		BookID:  bookId,
+		Barcode: "DEF456",
+		Status:  copystatus.OnLoan,
+		IssuedTo: &models.User{
+			Email: "kaushik@a.com",
+		},
+	}
+	withdrawnAt := time.Now()
+	copy3 := models.Copy{
+		ID:              uuid.New(),
+		BookID:          bookId,
+		Barcode:         "GHI789",
+		Status:          copystatus.Withdrawn,
+		WithdrawnAt:     &withdrawnAt,
+		WithdrawnReason: "water damage",
+	}
+
+	columns := []string{"id", "title_id", "barcode", "location", "email", "withdrawn_at", "withdrawn_reason", "status"}
+
+	tests := []struct {
+		name      string
+		want      []models.Copy
+		wantErr   bool
+		mockSetup func()
+	}{
+		{
+			name:    "valid get copies",
+			want:    []models.Copy{copy1, copy2, copy3},
+			wantErr: false,
+			mockSetup: func() {
+				mock.ExpectQuery("(?i)select .* from copies .* left join transactions .* left join users").
+					WithArgs(bookId.String()).
+					WillReturnRows(sqlmock.NewRows(columns).
+						AddRow(copy1.ID, bookId, copy1.Barcode, copy1.Location, nil, nil, nil, "available").
+						AddRow(copy2.ID, bookId, copy2.Barcode, nil, copy2.IssuedTo.Email, nil, nil, "on_loan").
+						AddRow(copy3.ID, bookId, copy3.Barcode, nil, nil, withdrawnAt, copy3.WithdrawnReason, "withdrawn"))
+			},
+		},
+		{
+			name:    "db error",
+			want:    nil,
+			wantErr: true,
+			mockSetup: func() {
+				mock.ExpectQuery("(?i)select .* from copies").
+					WithArgs(bookId.String()).
+					WillReturnError(errors.New("db error"))
+			},
+		},
+		{
+			name:    "connection lost while reading",
+			want:    []models.Copy{copy1},
+			wantErr: true,
+			mockSetup: func() {
+				mock.ExpectQuery("(?i)select .* from copies").
+					WithArgs(bookId.String()).
+					WillReturnRows(sqlmock.NewRows(columns).
+						AddRow(copy1.ID, bookId, copy1.Barcode, copy1.Location, nil, nil, nil, "available").
+						AddRow(copy2.ID, bookId, copy2.Barcode, nil, copy2.IssuedTo.Email, nil, nil, "on_loan").
+						RowError(1, errors.New("connection reset")))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &BookRepository{
+				db: db,
+			}
+			tt.mockSetup()
+			got, err := repo.GetCopies(bookId.String())
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetCopies() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetCopies() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBookRepository_GetBookById(t *testing.T) {
+
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	book := models.Book{
+		ID:              uuid.New(),
+		Title:           "dune",
+		Author:          "frank herbert",
+		Year:            1965,
+		ItemType:        "book",
+		TotalCopies:     3,
+		AvailableCopies: 2,
+	}
+
+	columns := []string{"id", "title", "author", "isbn", "publisher", "published_year", "language", "subject", "description", "item_type", "total", "available"}
+
+	tests := []struct {
+		name      string
+		want      models.Book
+		wantErr   bool
+		mockSetup func()
+	}{
+		{
+			name:    "valid get book",
+			want:    book,
+			wantErr: false,
+			mockSetup: func() {
+				mock.ExpectQuery("(?i)select .* from titles .* where b.id = .*").
+					WithArgs(book.ID.String()).
+					WillReturnRows(sqlmock.NewRows(columns).
+						AddRow(book.ID, book.Title, book.Author, nil, nil, book.Year, nil, nil, nil, "book", 3, 2))
+			},
+		},
+		{
+			name:    "book not found",
+			want:    models.Book{},
+			wantErr: true,
+			mockSetup: func() {
+				mock.ExpectQuery("(?i)select .* from titles .* where b.id = .*").
+					WithArgs(book.ID.String()).
+					WillReturnError(sql.ErrNoRows)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &BookRepository{
+				db: db,
+			}
+			tt.mockSetup()
+			got, err := repo.GetBookById(book.ID.String())
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetBookById() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetBookById() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBookRepository_EachBook(t *testing.T) {
+
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	dune := models.Book{ID: uuid.New(), Title: "dune", Author: "frank herbert", ItemType: "book", TotalCopies: 1, AvailableCopies: 1}
+	emma := models.Book{ID: uuid.New(), Title: "emma", Author: "jane austen", ItemType: "book", TotalCopies: 2}
+
+	columns := []string{"id", "title", "author", "isbn", "publisher", "published_year", "language", "subject", "description", "item_type", "total", "available"}
+	stop := errors.New("stop")
+
+	tests := []struct {
+		name      string
+		fn        func(models.Book) error
+		want      []models.Book
+		wantErr   bool
+		mockSetup func()
+	}{
+		{
+			name:    "every title",
+			want:    []models.Book{dune, emma},
+			wantErr: false,
+			mockSetup: func() {
+				mock.ExpectQuery("(?i)select .* from titles .* order by b.title, b.id").
+					WillReturnRows(sqlmock.NewRows(columns).
+						AddRow(dune.ID, dune.Title, dune.Author, nil, nil, nil, nil, nil, nil, "book", 1, 1).
+						AddRow(emma.ID, emma.Title, emma.Author, nil, nil, nil, nil, nil, nil, "book", 2, 0))
+			},
+		},
+		{
+			name:    "callback error stops",
+			fn:      func(models.Book) error { return stop },
+			want:    []models.Book{dune},
+			wantErr: true,
+			mockSetup: func() {
+				mock.ExpectQuery("(?i)select .* from titles .*").
+					WillReturnRows(sqlmock.NewRows(columns).
+						AddRow(dune.ID, dune.Title, dune.Author, nil, nil, nil, nil, nil, nil, "book", 1, 1).
+						AddRow(emma.ID, emma.Title, emma.Author, nil, nil, nil, nil, nil, nil, "book", 2, 0))
+			},
+		},
+		{
+			name:    "query error",
+			wantErr: true,
+			mockSetup: func() {
+				mock.ExpectQuery("(?i)select .* from titles .*").
+					WillReturnError(errors.New("db error"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &BookRepository{
+				db: db,
+			}
+			tt.mockSetup()
+
+			var got []models.Book
+			err := repo.EachBook(func(book models.Book) error {
+				got = append(got, book)
+				if tt.fn != nil {
+					return tt.fn(book)
+				}
+				return nil
+			})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("EachBook() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
 			if !reflect.DeepEqual(got, tt.want) {
-				t.Errorf("GetAllBooks() got = %v, want %v", got, tt.want)
+				t.Errorf("EachBook() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBookRepository_UpdateBook(t *testing.T) {
+
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	book := models.Book{
+		ID:     uuid.New(),
+		Title:  "dune",
+		Author: "frank herbert",
+	}
+
+	tests := []struct {
+		name      string
+		wantErr   bool
+		wantKind  apperrors.Kind
+		mockSetup func()
+	}{
+		{
+			name:    "valid update",
+			wantErr: false,
+			mockSetup: func() {
+				mock.ExpectExec("(?i)update titles set .*").
+					WithArgs(book.ID, book.Title, book.Author, "", "", 0, "", "", "", "").
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+		},
+		{
+			name:     "book not found",
+			wantErr:  true,
+			wantKind: apperrors.KindNotFound,
+			mockSetup: func() {
+				mock.ExpectExec("(?i)update titles set .*").
+					WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+		},
+		{
+			name:     "isbn of another title",
+			wantErr:  true,
+			wantKind: apperrors.KindConflict,
+			mockSetup: func() {
+				mock.ExpectExec("(?i)update titles set .*").
+					WillReturnError(&pq.Error{Code: "23505"})
+			},
+		},
+		{
+			name:     "db error",
+			wantErr:  true,
+			wantKind: apperrors.KindInternal,
+			mockSetup: func() {
+				mock.ExpectExec("(?i)update titles set .*").
+					WillReturnError(errors.New("connection reset"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &BookRepository{
+				db: db,
+			}
+			tt.mockSetup()
+			err := repo.UpdateBook(book)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("UpdateBook() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && apperrors.KindOf(err) != tt.wantKind {
+				t.Errorf("UpdateBook() kind = %v, want %v", apperrors.KindOf(err), tt.wantKind)
+			}
+		})
+	}
+}
+
+func TestBookRepository_WithdrawBook(t *testing.T) {
+
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	bookId := uuid.New().String()
+
+	tests := []struct {
+		name      string
+		wantErr   bool
+		mockSetup func()
+	}{
+		{
+			name:    "valid withdraw",
+			wantErr: false,
+			mockSetup: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery("(?i)select exists.* from titles .* for update").
+					WithArgs(bookId).
+					WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+				mock.ExpectExec("(?i)update titles set withdrawn_at").
+					WithArgs(bookId, "duplicate entry").
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec("(?i)update copies set status = 'withdrawn'").
+					WithArgs(bookId, "duplicate entry").
+					WillReturnResult(sqlmock.NewResult(0, 2))
+				mock.ExpectExec("(?i)update holds set status = 'cancelled'").
+					WithArgs(bookId).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name:    "copy issued",
+			wantErr: true,
+			mockSetup: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery("(?i)select exists.* from titles .* for update").
+					WithArgs(bookId).
+					WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+				mock.ExpectRollback()
+			},
+		},
+		{
+			name:    "book not found",
+			wantErr: true,
+			mockSetup: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery("(?i)select exists.* from titles .* for update").
+					WithArgs(bookId).
+					WillReturnError(sql.ErrNoRows)
+				mock.ExpectRollback()
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &BookRepository{
+				db: db,
+			}
+			tt.mockSetup()
+			if err := repo.WithdrawBook(bookId, "duplicate entry"); (err != nil) != tt.wantErr {
+				t.Errorf("WithdrawBook() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("unmet expectations: %v", err)
+			}
+		})
+	}
+}
+
+func TestBookRepository_WithdrawCopy(t *testing.T) {
+
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	bookId := uuid.New().String()
+	copyId := uuid.New().String()
+
+	tests := []struct {
+		name      string
+		wantErr   bool
+		mockSetup func()
+	}{
+		{
+			name:    "valid withdraw",
+			wantErr: false,
+			mockSetup: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery("(?i)select exists.* from copies .* for update").
+					WithArgs(copyId, bookId).
+					WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+				mock.ExpectExec("(?i)update copies set status = 'withdrawn'").
+					WithArgs(copyId, "damaged").
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec("(?i)update holds set status = 'waiting'").
+					WithArgs(copyId).
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name:    "copy issued",
+			wantErr: true,
+			mockSetup: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery("(?i)select exists.* from copies .* for update").
+					WithArgs(copyId, bookId).
+					WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+				mock.ExpectRollback()
+			},
+		},
+		{
+			name:    "copy not found",
+			wantErr: true,
+			mockSetup: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery("(?i)select exists.* from copies .* for update").
+					WithArgs(copyId, bookId).
+					WillReturnError(sql.ErrNoRows)
+				mock.ExpectRollback()
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &BookRepository{
+				db: db,
+			}
+			tt.mockSetup()
+			if err := repo.WithdrawCopy(bookId, copyId, "damaged"); (err != nil) != tt.wantErr {
+				t.Errorf("WithdrawCopy() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("unmet expectations: %v", err)
+			}
+		})
+	}
+}
+
+func TestBookRepository_GetCopyById(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	c := models.Copy{
+		ID:       uuid.New(),
+		BookID:   uuid.New(),
+		Barcode:  "LIB-0001",
+		Status:   copystatus.Available,
+		Location: "shelf A",
+	}
+
+	tests := []struct {
+		name      string
+		want      models.Copy
+		wantErr   bool
+		mockSetup func()
+	}{
+		{
+			name:    "valid copy id",
+			want:    c,
+			wantErr: false,
+			mockSetup: func() {
+				mock.ExpectQuery("(?i)select .* from copies where id = .*").
+					WithArgs(c.ID.String()).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "title_id", "barcode", "status", "location"}).
+						AddRow(c.ID, c.BookID, c.Barcode, c.Status, c.Location))
+			},
+		},
+		{
+			name:    "copy not found",
+			wantErr: true,
+			mockSetup: func() {
+				mock.ExpectQuery("(?i)select .* from copies where id = .*").
+					WithArgs(c.ID.String()).
+					WillReturnError(sql.ErrNoRows)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &BookRepository{
+				db: db,
+			}
+			tt.mockSetup()
+			got, err := repo.GetCopyById(c.ID.String())
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetCopyById() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetCopyById() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBookRepository_GetCopyByBarcode(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	c := models.Copy{
+		ID:       uuid.New(),
+		BookID:   uuid.New(),
+		Barcode:  "LIB-0001",
+		Status:   copystatus.Available,
+		Location: "shelf A",
+	}
+
+	tests := []struct {
+		name      string
+		want      models.Copy
+		wantErr   bool
+		mockSetup func()
+	}{
+		{
+			name:    "valid barcode",
+			want:    c,
+			wantErr: false,
+			mockSetup: func() {
+				mock.ExpectQuery("(?i)select .* from copies where barcode = .*").
+					WithArgs(c.Barcode).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "title_id", "barcode", "status", "location"}).
+						AddRow(c.ID, c.BookID, c.Barcode, c.Status, c.Location))
+			},
+		},
+		{
+			name:    "copy not found",
+			wantErr: true,
+			mockSetup: func() {
+				mock.ExpectQuery("(?i)select .* from copies where barcode = .*").
+					WithArgs(c.Barcode).
+					WillReturnError(sql.ErrNoRows)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &BookRepository{
+				db: db,
+			}
+			tt.mockSetup()
+			got, err := repo.GetCopyByBarcode(c.Barcode)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetCopyByBarcode() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetCopyByBarcode() got = %v, want %v", got, tt.want)
 			}
 		})
 	}
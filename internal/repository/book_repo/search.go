@@ -0,0 +1,268 @@
+package bookrepo
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/Kaushik1766/LibraryManagement/internal/models"
+	"github.com/Kaushik1766/LibraryManagement/internal/pagination"
+)
+
+// maxFacetValues caps how many values of each facet are returned.
+const maxFacetValues = 10
+
+// searchHits matches titles against tsquery $1 and narrows them by $2
+// author, $3 subject, $4 language and $5 availability, all optional.
+const searchHits = `
+	with matches as (
+		select b.id, b.author, b.subject, b.language,
+		       ts_rank_cd(b.search, query) as rank,
+		       exists(
+		           select 1 from copies as c
+		           where c.title_id = b.id and c.status = 'available'
+		           and not exists(select 1 from transactions as t where t.copy_id = c.id and t.returned_at is null)
+		           and not exists(select 1 from holds as h where h.copy_id = c.id and h.status = 'ready')
+		       ) as available
+		from titles as b, to_tsquery('english', $1) as query
+		where b.withdrawn_at is null and b.search @@ query
+	),
+	hits as (
+		select * from matches
+		where ($2='' or author = $2)
+		and ($3='' or subject = $3)
+		and ($4='' or language = $4)
+		and ($5='' or available = cast($5 as boolean))
+	)
+`
+
+var searchSorts = map[string]pagination.Column[models.SearchHit]{
+	"rank": {
+		Expr: "hits.rank",
+		Type: "real",
+		Key:  func(hit models.SearchHit) string { return strconv.FormatFloat(hit.Rank, 'g', -1, 32) },
+	},
+	"title": {
+		Expr: "b.title",
+		Type: "text",
+		Key:  func(hit models.SearchHit) string { return hit.Book.Title },
+	},
+}
+
+// SearchBooks ranks the catalog against a search and counts the matches by
+// facet. Snippets highlight the matched words with <mark>.
+func (repo *BookRepository) SearchBooks(search models.BookSearch, page pagination.Request) (models.SearchResult, error) {
+	order, err := pagination.NewOrder(page, searchSorts, "-rank")
+	if err != nil {
+		return models.SearchResult{}, err
+	}
+
+	query := toTSQuery(search.Query)
+	if query == "" {
+		return models.SearchResult{Hits: order.Page(nil, hitId, 0)}, nil
+	}
+
+	args := []any{query, search.Author, search.Subject, search.Language, search.Available}
+
+	facets, total, err := repo.searchFacets(args)
+	if err != nil {
+		return models.SearchResult{}, err
+	}
+
+	args = append(append(args, order.AfterArgs()...), order.Fetch())
+	rows, err := repo.db.Query(searchHits+`
+	select `+bookColumns+`,
+	       hits.rank,
+	       ts_headline('english', concat_ws(' - ', b.title, b.subject, b.description), to_tsquery('english', $1),
+	                   'StartSel=<mark>, StopSel=</mark>, MaxFragments=2, MaxWords=30, MinWords=10')
+	from hits
+	join titles as b on b.id = hits.id
+	left join copies as c on c.title_id = b.id
+	where `+order.After("b.id", 6, 7)+`
+	group by b.id, hits.rank
+	order by `+order.By("b.id")+`
+	limit $8
+`, args...)
+	if err != nil {
+		return models.SearchResult{}, err
+	}
+	defer rows.Close()
+
+	var hits []models.SearchHit
+	for rows.Next() {
+		hit, err := scanHit(rows)
+		if err != nil {
+			return models.SearchResult{}, err
+		}
+		hits = append(hits, hit)
+	}
+	if err := rows.Err(); err != nil {
+		return models.SearchResult{}, err
+	}
+
+	return models.SearchResult{
+		Hits:   order.Page(hits, hitId, total),
+		Facets: facets,
+	}, nil
+}
+
+// searchFacets counts the hits per author, subject, language and
+// availability along with the overall total in a single pass.
+func (repo *BookRepository) searchFacets(args []any) (models.SearchFacets, int, error) {
+	facets := models.SearchFacets{
+		Authors:      []models.FacetCount{},
+		Subjects:     []models.FacetCount{},
+		Languages:    []models.FacetCount{},
+		Availability: []models.FacetCount{},
+	}
+
+	rows, err := repo.db.Query(searchHits+`
+	select case
+	           when grouping(author) = 0 then 'author'
+	           when grouping(subject) = 0 then 'subject'
+	           when grouping(language) = 0 then 'language'
+	           when grouping(available) = 0 then 'available'
+	           else 'total'
+	       end,
+	       coalesce(author, subject, language, cast(available as text), ''),
+	       count(*)
+	from hits
+	group by grouping sets ((), (author), (subject), (language), (available))
+	order by 3 desc, 2
+`, args...)
+	if err != nil {
+		return facets, 0, err
+	}
+	defer rows.Close()
+
+	var total int
+	for rows.Next() {
+		var facet string
+		var value models.FacetCount
+		if err := rows.Scan(&facet, &value.Value, &value.Count); err != nil {
+			return facets, 0, err
+		}
+
+		switch facet {
+		case "total":
+			total = value.Count
+		case "author":
+			facets.Authors = appendFacet(facets.Authors, value)
+		case "subject":
+			facets.Subjects = appendFacet(facets.Subjects, value)
+		case "language":
+			facets.Languages = appendFacet(facets.Languages, value)
+		case "available":
+			facets.Availability = appendFacet(facets.Availability, value)
+		}
+	}
+
+	return facets, total, rows.Err()
+}
+
+// appendFacet drops titles with no value for the facet and keeps at most
+// maxFacetValues values, rows arrive most common first.
+func appendFacet(values []models.FacetCount, value models.FacetCount) []models.FacetCount {
+	if value.Value == "" || len(values) >= maxFacetValues {
+		return values
+	}
+	return append(values, value)
+}
+
+func hitId(hit models.SearchHit) string {
+	return hit.Book.ID.String()
+}
+
+func scanHit(row scanner) (models.SearchHit, error) {
+	var hit models.SearchHit
+	book, err := scanBook(row, &hit.Rank, &hit.Snippet)
+	hit.Book = book
+	return hit, err
+}
+
+// toTSQuery turns a search box query into a to_tsquery expression. Words are
+// and-ed together, "quoted phrases" must appear in order, word* matches a
+// prefix and -word excludes titles containing it. Anything postgres would
+// read as an operator is dropped so user input can't produce a syntax error.
+func toTSQuery(q string) string {
+	var terms []string
+
+	for _, token := range tokenize(q) {
+		negate := !token.phrase && strings.HasPrefix(token.text, "-")
+		text := strings.TrimPrefix(token.text, "-")
+
+		prefix := !token.phrase && strings.HasSuffix(text, "*")
+		text = strings.TrimSuffix(text, "*")
+
+		words := lexemes(text)
+		if len(words) == 0 {
+			continue
+		}
+
+		term := strings.Join(words, " <-> ")
+		if prefix {
+			term += ":*"
+		}
+		if len(words) > 1 {
+			term = "(" + term + ")"
+		}
+		if negate {
+			term = "!" + term
+		}
+		terms = append(terms, term)
+	}
+
+	// a query of only exclusions would match nearly everything
+	if !slices.ContainsFunc(terms, func(term string) bool { return !strings.HasPrefix(term, "!") }) {
+		return ""
+	}
+
+	return strings.Join(terms, " & ")
+}
+
+type token struct {
+	text   string
+	phrase bool
+}
+
+func tokenize(q string) []token {
+	var tokens []token
+	for i, part := range strings.Split(q, `"`) {
+		// odd parts were inside quotes, an unclosed quote runs to the end
+		if i%2 == 1 {
+			tokens = append(tokens, token{text: part, phrase: true})
+			continue
+		}
+		for _, field := range strings.Fields(part) {
+			tokens = append(tokens, token{text: field})
+		}
+	}
+	return tokens
+}
+
+// lexemes splits text into the words postgres should see. ISBNs keep their
+// digits together so they match the index, which stores them without
+// hyphens.
+func lexemes(text string) []string {
+	if isISBN(text) {
+		return []string{strings.ReplaceAll(text, "-", "")}
+	}
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+func isISBN(text string) bool {
+	digits := 0
+	for _, r := range text {
+		switch {
+		case unicode.IsDigit(r):
+			digits++
+		case r == '-' || r == 'X' || r == 'x':
+		default:
+			return false
+		}
+	}
+	return digits >= 9
+}
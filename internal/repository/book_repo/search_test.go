@@ -0,0 +1,182 @@
+package bookrepo
+
+import (
+	"database/sql"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/Kaushik1766/LibraryManagement/internal/models"
+	"github.com/Kaushik1766/LibraryManagement/internal/pagination"
+	"github.com/google/uuid"
+)
+
+func TestToTSQuery(t *testing.T) {
+	tests := []struct {
+		name string
+		q    string
+		want string
+	}{
+		{name: "single word", q: "wizard", want: "wizard"},
+		{name: "words are and-ed", q: "Harry  Potter", want: "harry & potter"},
+		{name: "phrase", q: `"philosopher's stone" harry`, want: "(philosopher <-> s <-> stone) & harry"},
+		{name: "prefix", q: "wiz*", want: "wiz:*"},
+		{name: "exclusion", q: "potter -chamber", want: "potter & !chamber"},
+		{name: "only exclusions", q: "-chamber", want: ""},
+		{name: "isbn with hyphens", q: "978-0-7475-3269-9", want: "9780747532699"},
+		{name: "operators are dropped", q: "a&b | !c:*", want: "(a <-> b) & c:*"},
+		{name: "unclosed quote", q: `"half blood`, want: "(half <-> blood)"},
+		{name: "empty", q: "  ", want: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := toTSQuery(tt.q); got != tt.want {
+				t.Errorf("toTSQuery(%q) = %q, want %q", tt.q, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBookRepository_SearchBooks(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	book := models.Book{
+		ID:              uuid.New(),
+		Title:           "harry potter",
+		Author:          "jk rowling",
+		Language:        "en",
+		Subject:         "fantasy",
+		ItemType:        "book",
+		TotalCopies:     2,
+		AvailableCopies: 1,
+	}
+
+	facetColumns := []string{"facet", "value", "count"}
+	hitColumns := []string{"id", "title", "author", "isbn", "publisher", "published_year", "language", "subject", "description", "item_type", "total", "available", "rank", "snippet"}
+	firstPage := pagination.Request{Limit: pagination.DefaultLimit}
+
+	type fields struct {
+		db *sql.DB
+	}
+	type args struct {
+		search models.BookSearch
+		page   pagination.Request
+	}
+	tests := []struct {
+		name      string
+		fields    fields
+		args      args
+		want      models.SearchResult
+		wantErr   bool
+		mockSetup func()
+	}{
+		{
+			name:   "ranked hits with facets",
+			fields: fields{db: db},
+			args: args{
+				search: models.BookSearch{Query: "harry", Language: "en"},
+				page:   firstPage,
+			},
+			want: models.SearchResult{
+				Hits: pagination.Page[models.SearchHit]{
+					Items: []models.SearchHit{{Book: book, Rank: 0.5, Snippet: "<mark>harry</mark> potter"}},
+					Total: 1,
+				},
+				Facets: models.SearchFacets{
+					Authors:      []models.FacetCount{{Value: "jk rowling", Count: 1}},
+					Subjects:     []models.FacetCount{{Value: "fantasy", Count: 1}},
+					Languages:    []models.FacetCount{{Value: "en", Count: 1}},
+					Availability: []models.FacetCount{{Value: "true", Count: 1}},
+				},
+			},
+			wantErr: false,
+			mockSetup: func() {
+				mock.ExpectQuery("(?i)with matches as .* group by grouping sets").
+					WithArgs("harry", "", "", "en", "").
+					WillReturnRows(sqlmock.NewRows(facetColumns).
+						AddRow("total", "", 1).
+						AddRow("author", "jk rowling", 1).
+						AddRow("subject", "fantasy", 1).
+						AddRow("subject", "", 1).
+						AddRow("language", "en", 1).
+						AddRow("available", "true", 1))
+				mock.ExpectQuery("(?i)with matches as .* ts_headline.* order by hits.rank desc, b.id desc").
+					WithArgs("harry", "", "", "en", "", "", "", pagination.DefaultLimit+1).
+					WillReturnRows(sqlmock.NewRows(hitColumns).
+						AddRow(book.ID, book.Title, book.Author, nil, nil, nil, book.Language, book.Subject, nil, "book", 2, 1, 0.5, "<mark>harry</mark> potter"))
+			},
+		},
+		{
+			name:   "query with nothing searchable",
+			fields: fields{db: db},
+			args: args{
+				search: models.BookSearch{Query: "-potter"},
+				page:   firstPage,
+			},
+			want: models.SearchResult{
+				Hits: pagination.Page[models.SearchHit]{Items: []models.SearchHit{}},
+			},
+			wantErr:   false,
+			mockSetup: func() {},
+		},
+		{
+			name:   "facet query error",
+			fields: fields{db: db},
+			args: args{
+				search: models.BookSearch{Query: "harry"},
+				page:   firstPage,
+			},
+			wantErr: true,
+			mockSetup: func() {
+				mock.ExpectQuery("(?i)with matches as .* group by grouping sets").
+					WillReturnError(errors.New("db error"))
+			},
+		},
+		{
+			name:   "hit query error",
+			fields: fields{db: db},
+			args: args{
+				search: models.BookSearch{Query: "harry"},
+				page:   firstPage,
+			},
+			wantErr: true,
+			mockSetup: func() {
+				mock.ExpectQuery("(?i)with matches as .* group by grouping sets").
+					WillReturnRows(sqlmock.NewRows(facetColumns).AddRow("total", "", 1))
+				mock.ExpectQuery("(?i)with matches as .* ts_headline").
+					WillReturnError(errors.New("db error"))
+			},
+		},
+		{
+			name:   "unknown sort",
+			fields: fields{db: db},
+			args: args{
+				search: models.BookSearch{Query: "harry"},
+				page:   pagination.Request{Sort: "author"},
+			},
+			wantErr:   true,
+			mockSetup: func() {},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &BookRepository{
+				db: tt.fields.db,
+			}
+			tt.mockSetup()
+			got, err := repo.SearchBooks(tt.args.search, tt.args.page)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("SearchBooks() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("SearchBooks() got = %+v, want %+v", got, tt.want)
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("there were unfulfilled expectations: %s", err)
+			}
+		})
+	}
+}
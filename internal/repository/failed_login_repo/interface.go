@@ -0,0 +1,15 @@
+package failedloginrepo
+
+import (
+	"time"
+
+	"github.com/Kaushik1766/LibraryManagement/internal/models"
+	"github.com/Kaushik1766/LibraryManagement/internal/pagination"
+)
+
+//go:generate mockgen -source=interface.go -destination=../../../mocks/mock_failed_login_storage.go -package=mocks
+type FailedLoginStorage interface {
+	RecordFailedLogin(attempt models.FailedLogin) error
+	CountFromIP(ip string, since time.Time) (int, error)
+	GetFailedLogins(filter models.FailedLoginFilter, page pagination.Request) (pagination.Page[models.FailedLogin], error)
+}
@@ -0,0 +1,100 @@
+package failedloginrepo
+
+import (
+	"database/sql"
+	"time"
+
+	apperrors "github.com/Kaushik1766/LibraryManagement/internal/app_errors"
+	"github.com/Kaushik1766/LibraryManagement/internal/models"
+	"github.com/Kaushik1766/LibraryManagement/internal/pagination"
+	"github.com/google/uuid"
+)
+
+// failedLoginFilter narrows the audit log by $1 email and $2 ip, both
+// optional.
+const failedLoginFilter = `
+	where ($1='' or email = $1)
+	and ($2='' or ip = $2)
+`
+
+var failedLoginSorts = map[string]pagination.Column[models.FailedLogin]{
+	"attempted_at": {
+		Expr: "attempted_at",
+		Type: "timestamp",
+		Key:  func(f models.FailedLogin) string { return f.AttemptedAt.Format(time.RFC3339Nano) },
+	},
+}
+
+type FailedLoginRepository struct {
+	db *sql.DB
+}
+
+func NewFailedLoginRepository(db *sql.DB) *FailedLoginRepository {
+	return &FailedLoginRepository{
+		db: db,
+	}
+}
+
+func (repo *FailedLoginRepository) RecordFailedLogin(attempt models.FailedLogin) error {
+	_, err := repo.db.Exec(`
+		insert into failed_logins (email, user_id, ip, reason)
+		values ($1, $2, $3, $4)
+`, attempt.Email, attempt.UserID, attempt.IP, attempt.Reason)
+	return apperrors.FromPostgres(err)
+}
+
+// CountFromIP counts the failed logins from ip since a point in time, across
+// every account.
+func (repo *FailedLoginRepository) CountFromIP(ip string, since time.Time) (int, error) {
+	var count int
+	err := repo.db.QueryRow(`
+		select count(*) from failed_logins
+		where ip = $1 and attempted_at > $2
+`, ip, since).Scan(&count)
+	return count, err
+}
+
+// GetFailedLogins lists the audit log, newest first unless page asks
+// otherwise.
+func (repo *FailedLoginRepository) GetFailedLogins(filter models.FailedLoginFilter, page pagination.Request) (pagination.Page[models.FailedLogin], error) {
+	order, err := pagination.NewOrder(page, failedLoginSorts, "-attempted_at")
+	if err != nil {
+		return pagination.Page[models.FailedLogin]{}, err
+	}
+
+	var total int
+	err = repo.db.QueryRow(`select count(*) from failed_logins`+failedLoginFilter, filter.Email, filter.IP).Scan(&total)
+	if err != nil {
+		return pagination.Page[models.FailedLogin]{}, err
+	}
+
+	args := append([]any{filter.Email, filter.IP}, order.AfterArgs()...)
+	rows, err := repo.db.Query(`
+	select id, email, user_id, ip, reason, attempted_at from failed_logins`+failedLoginFilter+`
+	and `+order.After("id", 3, 4)+`
+	order by `+order.By("id")+`
+	limit $5
+`, append(args, order.Fetch())...)
+	if err != nil {
+		return pagination.Page[models.FailedLogin]{}, err
+	}
+	defer rows.Close()
+
+	var attempts []models.FailedLogin
+	for rows.Next() {
+		var attempt models.FailedLogin
+		var userId uuid.NullUUID
+		err := rows.Scan(&attempt.ID, &attempt.Email, &userId, &attempt.IP, &attempt.Reason, &attempt.AttemptedAt)
+		if err != nil {
+			return pagination.Page[models.FailedLogin]{}, err
+		}
+		if userId.Valid {
+			attempt.UserID = &userId.UUID
+		}
+		attempts = append(attempts, attempt)
+	}
+	if err := rows.Err(); err != nil {
+		return pagination.Page[models.FailedLogin]{}, err
+	}
+	return order.Page(attempts, func(f models.FailedLogin) string { return f.ID.String() }, total), nil
+}
@@ -0,0 +1,207 @@
+package failedloginrepo
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/Kaushik1766/LibraryManagement/internal/models"
+	"github.com/Kaushik1766/LibraryManagement/internal/pagination"
+	"github.com/google/uuid"
+)
+
+func TestNewFailedLoginRepository(t *testing.T) {
+	db, _, _ := sqlmock.New()
+	defer db.Close()
+
+	want := &FailedLoginRepository{db: db}
+	if got := NewFailedLoginRepository(db); !reflect.DeepEqual(got, want) {
+		t.Errorf("NewFailedLoginRepository() = %v, want %v", got, want)
+	}
+}
+
+func TestFailedLoginRepository_RecordFailedLogin(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	userId := uuid.New()
+	attempt := models.FailedLogin{
+		Email:  "kaushik@a.com",
+		UserID: &userId,
+		IP:     "203.0.113.7",
+		Reason: models.LoginInvalidPassword,
+	}
+
+	tests := []struct {
+		name      string
+		wantErr   bool
+		mockSetup func()
+	}{
+		{
+			name:    "valid record",
+			wantErr: false,
+			mockSetup: func() {
+				mock.ExpectExec("(?i)insert into failed_logins .*").
+					WithArgs("kaushik@a.com", &userId, "203.0.113.7", models.LoginInvalidPassword).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+			},
+		},
+		{
+			name:    "database error",
+			wantErr: true,
+			mockSetup: func() {
+				mock.ExpectExec("(?i)insert into failed_logins .*").WillReturnError(errors.New("database error"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &FailedLoginRepository{
+				db: db,
+			}
+			tt.mockSetup()
+			if err := repo.RecordFailedLogin(attempt); (err != nil) != tt.wantErr {
+				t.Errorf("RecordFailedLogin() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("unmet expectations: %v", err)
+			}
+		})
+	}
+}
+
+func TestFailedLoginRepository_CountFromIP(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	since := time.Now().Add(-15 * time.Minute)
+
+	tests := []struct {
+		name      string
+		want      int
+		wantErr   bool
+		mockSetup func()
+	}{
+		{
+			name:    "valid count",
+			want:    7,
+			wantErr: false,
+			mockSetup: func() {
+				mock.ExpectQuery("(?i)select count\\(\\*\\) from failed_logins where ip = \\$1 and attempted_at > \\$2").
+					WithArgs("203.0.113.7", since).
+					WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(7))
+			},
+		},
+		{
+			name:    "database error",
+			want:    0,
+			wantErr: true,
+			mockSetup: func() {
+				mock.ExpectQuery("(?i)select count\\(\\*\\) from failed_logins .*").WillReturnError(errors.New("database error"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &FailedLoginRepository{
+				db: db,
+			}
+			tt.mockSetup()
+			got, err := repo.CountFromIP("203.0.113.7", since)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("CountFromIP() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("CountFromIP() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFailedLoginRepository_GetFailedLogins(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	userId := uuid.New()
+	attempt1 := models.FailedLogin{
+		ID:          uuid.New(),
+		Email:       "kaushik@a.com",
+		UserID:      &userId,
+		IP:          "203.0.113.7",
+		Reason:      models.LoginInvalidPassword,
+		AttemptedAt: time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC),
+	}
+	attempt2 := models.FailedLogin{
+		ID:          uuid.New(),
+		Email:       "nobody@a.com",
+		IP:          "203.0.113.7",
+		Reason:      models.LoginUnknownEmail,
+		AttemptedAt: time.Date(2024, 3, 1, 8, 0, 0, 0, time.UTC),
+	}
+	columns := []string{"id", "email", "user_id", "ip", "reason", "attempted_at"}
+
+	tests := []struct {
+		name      string
+		filter    models.FailedLoginFilter
+		page      pagination.Request
+		want      []models.FailedLogin
+		wantTotal int
+		wantErr   bool
+		mockSetup func()
+	}{
+		{
+			name:      "newest first",
+			filter:    models.FailedLoginFilter{IP: "203.0.113.7"},
+			page:      pagination.Request{Limit: 10},
+			want:      []models.FailedLogin{attempt1, attempt2},
+			wantTotal: 2,
+			wantErr:   false,
+			mockSetup: func() {
+				mock.ExpectQuery("(?i)select count\\(\\*\\) from failed_logins .*").
+					WithArgs("", "203.0.113.7").
+					WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(2))
+				mock.ExpectQuery("(?i)select id, email, user_id, ip, reason, attempted_at from failed_logins .* order by attempted_at desc, id desc").
+					WithArgs("", "203.0.113.7", "", "", 11).
+					WillReturnRows(sqlmock.NewRows(columns).
+						AddRow(attempt1.ID, attempt1.Email, userId, attempt1.IP, attempt1.Reason, attempt1.AttemptedAt).
+						AddRow(attempt2.ID, attempt2.Email, nil, attempt2.IP, attempt2.Reason, attempt2.AttemptedAt))
+			},
+		},
+		{
+			name:      "invalid sort",
+			page:      pagination.Request{Sort: "email"},
+			wantErr:   true,
+			mockSetup: func() {},
+		},
+		{
+			name:    "database error",
+			page:    pagination.Request{},
+			wantErr: true,
+			mockSetup: func() {
+				mock.ExpectQuery("(?i)select count\\(\\*\\) from failed_logins .*").WillReturnError(errors.New("database error"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &FailedLoginRepository{
+				db: db,
+			}
+			tt.mockSetup()
+			got, err := repo.GetFailedLogins(tt.filter, tt.page)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetFailedLogins() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				return
+			}
+			if !reflect.DeepEqual(got.Items, tt.want) || got.Total != tt.wantTotal {
+				t.Errorf("GetFailedLogins() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
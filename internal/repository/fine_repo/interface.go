@@ -0,0 +1,15 @@
+package finerepo
+
+import (
+	"github.com/Kaushik1766/LibraryManagement/internal/models"
+	"github.com/Kaushik1766/LibraryManagement/internal/models/enums/paymentkind"
+)
+
+//go:generate mockgen -source=interface.go -destination=../../../mocks/mock_fine_storage.go -package=mocks
+type FineStorage interface {
+	AssessFine(transactionId string, perDay, cap int) error
+	AccrueFines(perDay, cap int) (int64, error)
+	GetFines(userId string) ([]models.Fine, error)
+	GetFineById(fineId string) (models.Fine, error)
+	RecordPayment(fineId string, kind paymentkind.PaymentKind, amount int, reason, recordedBy string) error
+}
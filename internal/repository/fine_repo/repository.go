@@ -0,0 +1,155 @@
+package finerepo
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+
+	apperrors "github.com/Kaushik1766/LibraryManagement/internal/app_errors"
+	"github.com/Kaushik1766/LibraryManagement/internal/models"
+	"github.com/Kaushik1766/LibraryManagement/internal/models/enums/paymentkind"
+)
+
+// assessFines upserts the fine for every overdue transaction matched by the
+// where clause. Fines are charged per started day and capped, and a fine that
+// was finalised on return is never touched again. The rates stamped on a loan
+// when it was issued win over $1 and $2.
+const assessFines = `
+	insert into fines (transaction_id, user_id, amount, days_overdue, final)
+	select t.id, t.user_id, least(d.days * coalesce(t.fine_per_day, $1), coalesce(t.fine_cap, $2)), d.days, t.returned_at is not null
+	from transactions as t
+	cross join lateral (
+	    select cast(ceil(extract(epoch from coalesce(t.returned_at, now()) - t.issued_till) / 86400) as int) as days
+	) as d
+	where d.days > 0 and %s
+	on conflict (transaction_id) do update
+	set amount = excluded.amount, days_overdue = excluded.days_overdue, final = excluded.final, assessed_at = now()
+	where not fines.final
+`
+
+const selectFines = `
+	select f.id, f.transaction_id, b.title, u.id, u.email, f.amount, f.days_overdue, f.final, f.assessed_at,
+	       coalesce((select sum(p.amount) from payments as p where p.fine_id = f.id and p.kind = 'payment'), 0),
+	       coalesce((select sum(p.amount) from payments as p where p.fine_id = f.id and p.kind = 'waiver'), 0)
+	from fines as f
+	left join transactions as t on f.transaction_id = t.id
+	left join copies as c on t.copy_id = c.id
+	left join titles as b on c.title_id = b.id
+	left join users as u on f.user_id = u.id
+`
+
+type FineRepository struct {
+	db *sql.DB
+}
+
+func NewFineRepository(db *sql.DB) *FineRepository {
+	return &FineRepository{
+		db: db,
+	}
+}
+
+// AssessFine computes the fine for a single transaction, typically right after
+// it was returned.
+func (repo *FineRepository) AssessFine(transactionId string, perDay, cap int) error {
+	_, err := repo.db.Exec(
+		fmt.Sprintf(assessFines, "t.id = cast($3 as uuid)"),
+		perDay, cap, transactionId)
+	return err
+}
+
+// AccrueFines brings the fine of every open overdue loan up to date and
+// returns how many fines changed.
+func (repo *FineRepository) AccrueFines(perDay, cap int) (int64, error) {
+	res, err := repo.db.Exec(
+		fmt.Sprintf(assessFines, "t.returned_at is null"),
+		perDay, cap)
+	if err != nil {
+		return 0, err
+	}
+
+	return res.RowsAffected()
+}
+
+// GetFines returns the fines of userId, or of every user when userId is empty.
+func (repo *FineRepository) GetFines(userId string) ([]models.Fine, error) {
+	var fines []models.Fine
+
+	rows, err := repo.db.Query(selectFines+`
+	where ($1='' or f.user_id = cast($1 as uuid))
+	order by f.assessed_at desc
+`, userId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		fine, err := scanFine(rows)
+		if err != nil {
+			return nil, err
+		}
+		fines = append(fines, fine)
+	}
+
+	return fines, rows.Err()
+}
+
+func (repo *FineRepository) GetFineById(fineId string) (models.Fine, error) {
+	fine, err := scanFine(repo.db.QueryRow(selectFines+`
+	where f.id = $1
+`, fineId))
+	if errors.Is(err, sql.ErrNoRows) {
+		return fine, apperrors.NotFound("fine_not_found", "fine not found")
+	}
+	return fine, apperrors.FromPostgres(err)
+}
+
+// RecordPayment adds a payment or waiver against a fine. It is refused when
+// the amount is more than what is still outstanding. The fine row is locked
+// until the payment is in, so concurrent payments can't both pass the check.
+func (repo *FineRepository) RecordPayment(fineId string, kind paymentkind.PaymentKind, amount int, reason, recordedBy string) error {
+	tx, err := repo.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	var outstanding int
+	err = tx.QueryRow(`
+		select f.amount - coalesce((select sum(p.amount) from payments as p where p.fine_id = f.id), 0)
+		from fines as f
+		where f.id = $1
+		for update
+`, fineId).Scan(&outstanding)
+	if errors.Is(err, sql.ErrNoRows) {
+		return apperrors.NotFound("fine_not_found", "fine not found")
+	}
+	if err != nil {
+		return apperrors.FromPostgres(err)
+	}
+	if amount > outstanding {
+		return apperrors.Conflict("amount_exceeds_balance", "amount exceeds outstanding balance").WithDetail("amount", fmt.Sprintf("at most %d", outstanding))
+	}
+
+	if _, err := tx.Exec(`
+		insert into payments (fine_id, kind, amount, reason, recorded_by)
+		values ($1, $2, $3, nullif($4, ''), $5)
+`, fineId, kind, amount, reason, recordedBy); err != nil {
+		return apperrors.FromPostgres(err)
+	}
+
+	return tx.Commit()
+}
+
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+func scanFine(row scanner) (models.Fine, error) {
+	var fine models.Fine
+	var title sql.NullString
+	err := row.Scan(&fine.ID, &fine.Transaction.ID, &title, &fine.User.ID, &fine.User.Email,
+		&fine.Amount, &fine.DaysOverdue, &fine.Final, &fine.AssessedAt, &fine.Paid, &fine.Waived)
+	fine.Transaction.Book.Title = title.String
+	return fine, err
+}
@@ -0,0 +1,359 @@
+package finerepo
+
+import (
+	"database/sql"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	apperrors "github.com/Kaushik1766/LibraryManagement/internal/app_errors"
+	"github.com/Kaushik1766/LibraryManagement/internal/models"
+	"github.com/Kaushik1766/LibraryManagement/internal/models/enums/paymentkind"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+)
+
+var fineColumns = []string{"id", "transaction_id", "title", "user_id", "email", "amount", "days_overdue", "final", "assessed_at", "paid", "waived"}
+
+func TestNewFineRepository(t *testing.T) {
+
+	db, _, _ := sqlmock.New()
+	defer db.Close()
+
+	type args struct {
+		db *sql.DB
+	}
+	tests := []struct {
+		name string
+		args args
+		want *FineRepository
+	}{
+		{
+			name: "valid",
+			args: args{
+				db: db,
+			},
+			want: &FineRepository{db: db},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NewFineRepository(tt.args.db); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("NewFineRepository() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFineRepository_AssessFine(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	transactionId := uuid.New().String()
+
+	tests := []struct {
+		name      string
+		wantErr   bool
+		mockSetup func()
+	}{
+		{
+			name:    "valid assess",
+			wantErr: false,
+			mockSetup: func() {
+				mock.ExpectExec("(?i)insert into fines .* t.id = cast\\(\\$3 as uuid\\) .*on conflict").
+					WithArgs(50, 2000, transactionId).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+		},
+		{
+			name:    "database error",
+			wantErr: true,
+			mockSetup: func() {
+				mock.ExpectExec("(?i)insert into fines .*").
+					WithArgs(50, 2000, transactionId).
+					WillReturnError(errors.New("database error"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &FineRepository{
+				db: db,
+			}
+			tt.mockSetup()
+			if err := repo.AssessFine(transactionId, 50, 2000); (err != nil) != tt.wantErr {
+				t.Errorf("AssessFine() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestFineRepository_AccrueFines(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	tests := []struct {
+		name      string
+		want      int64
+		wantErr   bool
+		mockSetup func()
+	}{
+		{
+			name:    "valid accrue",
+			want:    3,
+			wantErr: false,
+			mockSetup: func() {
+				mock.ExpectExec("(?i)insert into fines .* t.returned_at is null.*on conflict").
+					WithArgs(50, 2000).
+					WillReturnResult(sqlmock.NewResult(0, 3))
+			},
+		},
+		{
+			name:    "database error",
+			want:    0,
+			wantErr: true,
+			mockSetup: func() {
+				mock.ExpectExec("(?i)insert into fines .*").
+					WithArgs(50, 2000).
+					WillReturnError(errors.New("database error"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &FineRepository{
+				db: db,
+			}
+			tt.mockSetup()
+			got, err := repo.AccrueFines(50, 2000)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("AccrueFines() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("AccrueFines() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFineRepository_GetFines(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	fine := models.Fine{
+		ID:          uuid.New(),
+		Transaction: models.Transaction{ID: uuid.New(), Book: models.Book{Title: "harry potter"}},
+		User:        models.User{ID: uuid.New(), Email: "kaushik@a.com"},
+		Amount:      150,
+		Paid:        50,
+		Waived:      0,
+		DaysOverdue: 3,
+		Final:       true,
+		AssessedAt:  time.Now(),
+	}
+
+	tests := []struct {
+		name      string
+		want      []models.Fine
+		wantErr   bool
+		mockSetup func()
+	}{
+		{
+			name:    "valid get fines",
+			want:    []models.Fine{fine},
+			wantErr: false,
+			mockSetup: func() {
+				mock.ExpectQuery("(?i)select .* from fines .* left join transactions .* left join titles .* left join users").
+					WithArgs(fine.User.ID.String()).
+					WillReturnRows(sqlmock.NewRows(fineColumns).
+						AddRow(fine.ID, fine.Transaction.ID, "harry potter", fine.User.ID, fine.User.Email, 150, 3, true, fine.AssessedAt, 50, 0))
+			},
+		},
+		{
+			name:    "query error",
+			want:    nil,
+			wantErr: true,
+			mockSetup: func() {
+				mock.ExpectQuery("(?i)select .* from fines").
+					WithArgs(fine.User.ID.String()).
+					WillReturnError(errors.New("invalid query"))
+			},
+		},
+		{
+			name:    "invalid row",
+			want:    nil,
+			wantErr: true,
+			mockSetup: func() {
+				mock.ExpectQuery("(?i)select .* from fines").
+					WithArgs(fine.User.ID.String()).
+					WillReturnRows(sqlmock.NewRows(fineColumns).
+						AddRow("invalid-uuid", fine.Transaction.ID, "harry potter", fine.User.ID, fine.User.Email, 150, 3, true, fine.AssessedAt, 50, 0))
+			},
+		},
+		{
+			name:    "connection lost while reading",
+			want:    []models.Fine{fine},
+			wantErr: true,
+			mockSetup: func() {
+				mock.ExpectQuery("(?i)select .* from fines").
+					WithArgs(fine.User.ID.String()).
+					WillReturnRows(sqlmock.NewRows(fineColumns).
+						AddRow(fine.ID, fine.Transaction.ID, "harry potter", fine.User.ID, fine.User.Email, 150, 3, true, fine.AssessedAt, 50, 0).
+						AddRow(fine.ID, fine.Transaction.ID, "harry potter", fine.User.ID, fine.User.Email, 150, 3, true, fine.AssessedAt, 50, 0).
+						RowError(1, errors.New("connection reset")))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &FineRepository{
+				db: db,
+			}
+			tt.mockSetup()
+			got, err := repo.GetFines(fine.User.ID.String())
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetFines() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetFines() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFineRepository_GetFineById(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	fineId := uuid.New()
+
+	tests := []struct {
+		name      string
+		wantErr   bool
+		mockSetup func()
+	}{
+		{
+			name:    "valid get fine",
+			wantErr: false,
+			mockSetup: func() {
+				mock.ExpectQuery("(?i)select .* from fines .* where f.id = .*").
+					WithArgs(fineId.String()).
+					WillReturnRows(sqlmock.NewRows(fineColumns).
+						AddRow(fineId, uuid.New(), nil, uuid.New(), "kaushik@a.com", 150, 3, false, time.Now(), 0, 0))
+			},
+		},
+		{
+			name:    "fine not found",
+			wantErr: true,
+			mockSetup: func() {
+				mock.ExpectQuery("(?i)select .* from fines .* where f.id = .*").
+					WithArgs(fineId.String()).
+					WillReturnError(sql.ErrNoRows)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &FineRepository{
+				db: db,
+			}
+			tt.mockSetup()
+			got, err := repo.GetFineById(fineId.String())
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetFineById() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && got.ID != fineId {
+				t.Errorf("GetFineById() got = %v, want %v", got.ID, fineId)
+			}
+		})
+	}
+}
+
+func TestFineRepository_RecordPayment(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	fineId := uuid.New().String()
+	staffId := uuid.New().String()
+
+	tests := []struct {
+		name      string
+		wantErr   bool
+		wantKind  apperrors.Kind
+		mockSetup func()
+	}{
+		{
+			name:    "valid payment",
+			wantErr: false,
+			mockSetup: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery("(?i)select .* from fines as f where f.id = .* for update").
+					WithArgs(fineId).
+					WillReturnRows(sqlmock.NewRows([]string{"outstanding"}).AddRow(150))
+				mock.ExpectExec("(?i)insert into payments .*").
+					WithArgs(fineId, paymentkind.Waiver, 100, "damaged on arrival", staffId).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name:     "amount exceeds outstanding",
+			wantErr:  true,
+			wantKind: apperrors.KindConflict,
+			mockSetup: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery("(?i)select .* from fines as f where f.id = .* for update").
+					WithArgs(fineId).
+					WillReturnRows(sqlmock.NewRows([]string{"outstanding"}).AddRow(50))
+				mock.ExpectRollback()
+			},
+		},
+		{
+			name:     "fine not found",
+			wantErr:  true,
+			wantKind: apperrors.KindNotFound,
+			mockSetup: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery("(?i)select .* from fines as f where f.id = .* for update").
+					WithArgs(fineId).
+					WillReturnError(sql.ErrNoRows)
+				mock.ExpectRollback()
+			},
+		},
+		{
+			name:     "malformed id",
+			wantErr:  true,
+			wantKind: apperrors.KindValidation,
+			mockSetup: func() {
+				mock.ExpectBegin()
+				mock.ExpectQuery("(?i)select .* from fines as f where f.id = .* for update").
+					WithArgs(fineId).
+					WillReturnError(&pq.Error{Code: "22P02"})
+				mock.ExpectRollback()
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &FineRepository{
+				db: db,
+			}
+			tt.mockSetup()
+			err := repo.RecordPayment(fineId, paymentkind.Waiver, 100, "damaged on arrival", staffId)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("RecordPayment() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil && apperrors.KindOf(err) != tt.wantKind {
+				t.Errorf("RecordPayment() kind = %v, want %v", apperrors.KindOf(err), tt.wantKind)
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("unmet expectations: %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,14 @@
+package holdrepo
+
+import "github.com/Kaushik1766/LibraryManagement/internal/models"
+
+//go:generate mockgen -source=interface.go -destination=../../../mocks/mock_hold_storage.go -package=mocks
+type HoldStorage interface {
+	PlaceHold(bookId, userId string) (string, error)
+	GetActiveHolds(userId string) ([]models.Hold, error)
+	CancelHold(holdId, userId string) (string, error)
+	AllocateNext(copyId, pickupWindow string) (string, error)
+	FulfillHold(bookId, userId string) error
+	HasWaitingHolds(bookId string) (bool, error)
+	ExpireHolds() ([]string, error)
+}
@@ -0,0 +1,184 @@
+package holdrepo
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	apperrors "github.com/Kaushik1766/LibraryManagement/internal/app_errors"
+	"github.com/Kaushik1766/LibraryManagement/internal/models"
+	"github.com/google/uuid"
+)
+
+type HoldRepository struct {
+	db *sql.DB
+}
+
+func NewHoldRepository(db *sql.DB) *HoldRepository {
+	return &HoldRepository{
+		db: db,
+	}
+}
+
+// PlaceHold queues userId for title bookId. Holds are only accepted while no
+// copy of the title is on the shelf and the user doesn't already have one.
+func (repo *HoldRepository) PlaceHold(bookId, userId string) (string, error) {
+	var id string
+	err := repo.db.QueryRow(`
+		insert into holds (title_id, user_id)
+		select $1, $2
+		where exists(select 1 from titles where id = $1 and withdrawn_at is null)
+		and not exists(
+		    select 1 from copies as c
+		    where c.title_id = $1 and c.status = 'available'
+		    and not exists(select 1 from transactions where copy_id = c.id and returned_at is null)
+		    and not exists(select 1 from holds where copy_id = c.id and status = 'ready')
+		)
+		and not exists(
+		    select 1 from transactions as t join copies as c on t.copy_id = c.id
+		    where c.title_id = $1 and t.user_id = $2 and t.returned_at is null
+		)
+		and not exists(
+		    select 1 from holds where title_id = $1 and user_id = $2 and status in ('waiting', 'ready')
+		)
+		returning id
+`, bookId, userId).Scan(&id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", apperrors.Conflict("hold_not_allowed", "hold not allowed, book is available or already held by you")
+	}
+	if err != nil {
+		return "", apperrors.FromPostgres(err)
+	}
+	return id, nil
+}
+
+// GetActiveHolds returns waiting and ready holds for userId, or for every user
+// when userId is empty.
+func (repo *HoldRepository) GetActiveHolds(userId string) ([]models.Hold, error) {
+	var holds []models.Hold
+
+	rows, err := repo.db.Query(`
+	select h.id, h.status, h.placed_at, h.expires_at, b.id, b.title, h.copy_id, u.email,
+	       (select count(*) from holds as h2
+	                 where h2.title_id = h.title_id and h2.status = 'waiting' and h2.placed_at <= h.placed_at)
+	from holds as h
+	left join titles as b on h.title_id = b.id
+	left join users as u on h.user_id = u.id
+	where ($1='' or h.user_id = cast($1 as uuid))
+	and h.status in ('waiting', 'ready')
+	order by h.placed_at
+`, userId)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var hold models.Hold
+		var expiresAt sql.Null[time.Time]
+		var copyId uuid.NullUUID
+		err = rows.Scan(&hold.ID, &hold.Status, &hold.PlacedAt, &expiresAt, &hold.Book.ID, &hold.Book.Title, &copyId, &hold.User.Email, &hold.QueuePosition)
+		if err != nil {
+			return nil, err
+		}
+
+		if expiresAt.Valid {
+			hold.ExpiresAt = &expiresAt.V
+		}
+		if copyId.Valid {
+			hold.Copy = &models.Copy{ID: copyId.UUID, BookID: hold.Book.ID}
+		}
+
+		holds = append(holds, hold)
+	}
+
+	return holds, rows.Err()
+}
+
+// CancelHold cancels an active hold and returns the id of the copy that was
+// set aside for it, or an empty string if the hold was still waiting. An
+// empty userId lets staff cancel anyone's hold.
+func (repo *HoldRepository) CancelHold(holdId, userId string) (string, error) {
+	var copyId sql.NullString
+	err := repo.db.QueryRow(`
+		update holds set status = 'cancelled', closed_at = now()
+		where id = $1
+		and ($2='' or user_id = cast($2 as uuid))
+		and status in ('waiting', 'ready')
+		returning copy_id
+`, holdId, userId).Scan(&copyId)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", apperrors.NotFound("hold_not_found", "no active hold found")
+	}
+	return copyId.String, apperrors.FromPostgres(err)
+}
+
+// AllocateNext sets copyId aside for the oldest waiting hold on its title if
+// the copy is on the shelf and not already set aside. It returns the allocated
+// hold id, or an empty string when there was nothing to allocate.
+func (repo *HoldRepository) AllocateNext(copyId, pickupWindow string) (string, error) {
+	var id string
+	err := repo.db.QueryRow(`
+		update holds set status = 'ready', copy_id = $1, ready_at = now(), expires_at = now() + cast($2 as interval)
+		where id = (
+		    select h.id from holds as h
+		    join copies as c on c.title_id = h.title_id
+		    where c.id = $1 and h.status = 'waiting'
+		    order by h.placed_at
+		    limit 1
+		    for update of h skip locked
+		)
+		and exists(select 1 from copies where id = $1 and status = 'available')
+		and not exists(select 1 from holds where copy_id = $1 and status = 'ready')
+		and not exists(select 1 from transactions where copy_id = $1 and returned_at is null)
+		returning id
+`, copyId, pickupWindow).Scan(&id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", nil
+	}
+	return id, err
+}
+
+// FulfillHold closes the user's active hold on title bookId once they've been
+// issued a copy of it.
+func (repo *HoldRepository) FulfillHold(bookId, userId string) error {
+	_, err := repo.db.Exec(`
+		update holds set status = 'fulfilled', closed_at = now()
+		where title_id = $1 and user_id = $2 and status in ('waiting', 'ready')
+`, bookId, userId)
+	return err
+}
+
+func (repo *HoldRepository) HasWaitingHolds(bookId string) (bool, error) {
+	var exists bool
+	err := repo.db.QueryRow(`
+		select exists(select 1 from holds where title_id = $1 and status = 'waiting')
+`, bookId).Scan(&exists)
+	return exists, err
+}
+
+// ExpireHolds closes ready holds whose pickup deadline has passed and returns
+// the ids of the copies that became free.
+func (repo *HoldRepository) ExpireHolds() ([]string, error) {
+	var copyIds []string
+
+	rows, err := repo.db.Query(`
+		update holds set status = 'expired', closed_at = now()
+		where status = 'ready' and expires_at < now()
+		returning copy_id
+`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var copyId string
+		if err := rows.Scan(&copyId); err != nil {
+			return nil, err
+		}
+		copyIds = append(copyIds, copyId)
+	}
+
+	return copyIds, rows.Err()
+}
@@ -0,0 +1,453 @@
+package holdrepo
+
+import (
+	"database/sql"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/Kaushik1766/LibraryManagement/internal/models"
+	"github.com/Kaushik1766/LibraryManagement/internal/models/enums/holdstatus"
+	"github.com/google/uuid"
+)
+
+func TestNewHoldRepository(t *testing.T) {
+
+	db, _, _ := sqlmock.New()
+	defer db.Close()
+
+	type args struct {
+		db *sql.DB
+	}
+	tests := []struct {
+		name string
+		args args
+		want *HoldRepository
+	}{
+		{
+			name: "valid",
+			args: args{
+				db: db,
+			},
+			want: &HoldRepository{db: db},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NewHoldRepository(tt.args.db); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("NewHoldRepository() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHoldRepository_PlaceHold(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	holdId := uuid.New().String()
+	bookId := uuid.New().String()
+	userId := uuid.New().String()
+
+	tests := []struct {
+		name      string
+		want      string
+		wantErr   bool
+		mockSetup func()
+	}{
+		{
+			name:    "valid place hold",
+			want:    holdId,
+			wantErr: false,
+			mockSetup: func() {
+				mock.ExpectQuery("(?i)insert into holds .*").
+					WithArgs(bookId, userId).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(holdId))
+			},
+		},
+		{
+			name:    "book available or already held",
+			want:    "",
+			wantErr: true,
+			mockSetup: func() {
+				mock.ExpectQuery("(?i)insert into holds .*").
+					WithArgs(bookId, userId).
+					WillReturnError(sql.ErrNoRows)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &HoldRepository{
+				db: db,
+			}
+			tt.mockSetup()
+			got, err := repo.PlaceHold(bookId, userId)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("PlaceHold() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("PlaceHold() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHoldRepository_GetActiveHolds(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	expiresAt := time.Now().Add(48 * time.Hour)
+	hold1 := models.Hold{
+		ID:            uuid.New(),
+		Book:          models.Book{ID: uuid.New(), Title: "harry potter"},
+		User:          models.User{Email: "kaushik@a.com"},
+		Status:        holdstatus.Waiting,
+		QueuePosition: 2,
+		PlacedAt:      time.Now(),
+	}
+	dune := models.Book{ID: uuid.New(), Title: "dune"}
+	hold2 := models.Hold{
+		ID:            uuid.New(),
+		Book:          dune,
+		Copy:          &models.Copy{ID: uuid.New(), BookID: dune.ID},
+		User:          models.User{Email: "kaushik@a.com"},
+		Status:        holdstatus.Ready,
+		QueuePosition: 0,
+		PlacedAt:      time.Now(),
+		ExpiresAt:     &expiresAt,
+	}
+	columns := []string{"id", "status", "placed_at", "expires_at", "title_id", "title", "copy_id", "email", "position"}
+
+	tests := []struct {
+		name      string
+		userId    string
+		want      []models.Hold
+		wantErr   bool
+		mockSetup func()
+	}{
+		{
+			name:    "valid get holds",
+			userId:  uuid.New().String(),
+			want:    []models.Hold{hold1, hold2},
+			wantErr: false,
+			mockSetup: func() {
+				mock.ExpectQuery("(?i)select .* from holds .* left join titles .* left join users").
+					WillReturnRows(sqlmock.NewRows(columns).
+						AddRow(hold1.ID, hold1.Status, hold1.PlacedAt, nil, hold1.Book.ID, hold1.Book.Title, nil, hold1.User.Email, hold1.QueuePosition).
+						AddRow(hold2.ID, hold2.Status, hold2.PlacedAt, expiresAt, hold2.Book.ID, hold2.Book.Title, hold2.Copy.ID, hold2.User.Email, hold2.QueuePosition))
+			},
+		},
+		{
+			name:    "query error",
+			userId:  "",
+			want:    nil,
+			wantErr: true,
+			mockSetup: func() {
+				mock.ExpectQuery("(?i)select .* from holds .* left join titles .* left join users").
+					WillReturnError(errors.New("invalid query"))
+			},
+		},
+		{
+			name:    "invalid row",
+			userId:  "",
+			want:    nil,
+			wantErr: true,
+			mockSetup: func() {
+				mock.ExpectQuery("(?i)select .* from holds .* left join titles .* left join users").
+					WillReturnRows(sqlmock.NewRows(columns).
+						AddRow("invalid-uuid", hold1.Status, hold1.PlacedAt, nil, hold1.Book.ID, hold1.Book.Title, nil, hold1.User.Email, hold1.QueuePosition))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &HoldRepository{
+				db: db,
+			}
+			tt.mockSetup()
+			got, err := repo.GetActiveHolds(tt.userId)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetActiveHolds() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetActiveHolds() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHoldRepository_CancelHold(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	holdId := uuid.New().String()
+	copyId := uuid.New().String()
+
+	tests := []struct {
+		name      string
+		userId    string
+		want      string
+		wantErr   bool
+		mockSetup func()
+	}{
+		{
+			name:    "valid cancel",
+			userId:  uuid.New().String(),
+			want:    copyId,
+			wantErr: false,
+			mockSetup: func() {
+				mock.ExpectQuery("(?i)update holds set status = 'cancelled'.*").
+					WillReturnRows(sqlmock.NewRows([]string{"copy_id"}).AddRow(copyId))
+			},
+		},
+		{
+			name:    "cancel waiting hold",
+			userId:  uuid.New().String(),
+			want:    "",
+			wantErr: false,
+			mockSetup: func() {
+				mock.ExpectQuery("(?i)update holds set status = 'cancelled'.*").
+					WillReturnRows(sqlmock.NewRows([]string{"copy_id"}).AddRow(nil))
+			},
+		},
+		{
+			name:    "no active hold",
+			userId:  "",
+			want:    "",
+			wantErr: true,
+			mockSetup: func() {
+				mock.ExpectQuery("(?i)update holds set status = 'cancelled'.*").
+					WillReturnError(sql.ErrNoRows)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &HoldRepository{
+				db: db,
+			}
+			tt.mockSetup()
+			got, err := repo.CancelHold(holdId, tt.userId)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("CancelHold() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("CancelHold() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHoldRepository_AllocateNext(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	holdId := uuid.New().String()
+	bookId := uuid.New().String()
+
+	tests := []struct {
+		name      string
+		want      string
+		wantErr   bool
+		mockSetup func()
+	}{
+		{
+			name:    "allocates head of queue",
+			want:    holdId,
+			wantErr: false,
+			mockSetup: func() {
+				mock.ExpectQuery("(?i)update holds set status = 'ready'.*").
+					WithArgs(bookId, "2 days").
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(holdId))
+			},
+		},
+		{
+			name:    "empty queue",
+			want:    "",
+			wantErr: false,
+			mockSetup: func() {
+				mock.ExpectQuery("(?i)update holds set status = 'ready'.*").
+					WithArgs(bookId, "2 days").
+					WillReturnError(sql.ErrNoRows)
+			},
+		},
+		{
+			name:    "database error",
+			want:    "",
+			wantErr: true,
+			mockSetup: func() {
+				mock.ExpectQuery("(?i)update holds set status = 'ready'.*").
+					WithArgs(bookId, "2 days").
+					WillReturnError(errors.New("database error"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &HoldRepository{
+				db: db,
+			}
+			tt.mockSetup()
+			got, err := repo.AllocateNext(bookId, "2 days")
+			if (err != nil) != tt.wantErr {
+				t.Errorf("AllocateNext() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("AllocateNext() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHoldRepository_FulfillHold(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	tests := []struct {
+		name      string
+		wantErr   bool
+		mockSetup func()
+	}{
+		{
+			name:    "valid fulfill",
+			wantErr: false,
+			mockSetup: func() {
+				mock.ExpectExec("(?i)update holds set status = 'fulfilled'.*").WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+		},
+		{
+			name:    "database error",
+			wantErr: true,
+			mockSetup: func() {
+				mock.ExpectExec("(?i)update holds set status = 'fulfilled'.*").WillReturnError(errors.New("database error"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &HoldRepository{
+				db: db,
+			}
+			tt.mockSetup()
+			if err := repo.FulfillHold(uuid.New().String(), uuid.New().String()); (err != nil) != tt.wantErr {
+				t.Errorf("FulfillHold() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestHoldRepository_ExpireHolds(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	copyId := uuid.New().String()
+
+	tests := []struct {
+		name      string
+		want      []string
+		wantErr   bool
+		mockSetup func()
+	}{
+		{
+			name:    "expires overdue pickups",
+			want:    []string{copyId},
+			wantErr: false,
+			mockSetup: func() {
+				mock.ExpectQuery("(?i)update holds set status = 'expired'.*").
+					WillReturnRows(sqlmock.NewRows([]string{"copy_id"}).AddRow(copyId))
+			},
+		},
+		{
+			name:    "database error",
+			want:    nil,
+			wantErr: true,
+			mockSetup: func() {
+				mock.ExpectQuery("(?i)update holds set status = 'expired'.*").
+					WillReturnError(errors.New("database error"))
+			},
+		},
+		{
+			name:    "connection lost while reading",
+			want:    nil,
+			wantErr: true,
+			mockSetup: func() {
+				mock.ExpectQuery("(?i)update holds set status = 'expired'.*").
+					WillReturnRows(sqlmock.NewRows([]string{"copy_id"}).AddRow(copyId).RowError(0, errors.New("connection reset")))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &HoldRepository{
+				db: db,
+			}
+			tt.mockSetup()
+			got, err := repo.ExpireHolds()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ExpireHolds() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ExpireHolds() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHoldRepository_HasWaitingHolds(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	bookId := uuid.New().String()
+
+	tests := []struct {
+		name      string
+		want      bool
+		wantErr   bool
+		mockSetup func()
+	}{
+		{
+			name:    "queue present",
+			want:    true,
+			wantErr: false,
+			mockSetup: func() {
+				mock.ExpectQuery("(?i)select exists\\(select 1 from holds .*").
+					WithArgs(bookId).
+					WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+			},
+		},
+		{
+			name:    "database error",
+			want:    false,
+			wantErr: true,
+			mockSetup: func() {
+				mock.ExpectQuery("(?i)select exists\\(select 1 from holds .*").
+					WithArgs(bookId).
+					WillReturnError(errors.New("database error"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &HoldRepository{
+				db: db,
+			}
+			tt.mockSetup()
+			got, err := repo.HasWaitingHolds(bookId)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("HasWaitingHolds() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("HasWaitingHolds() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,11 @@
+package mfarepo
+
+//go:generate mockgen -source=interface.go -destination=../../../mocks/mock_mfa_storage.go -package=mocks
+type MFAStorage interface {
+	SetPendingSecret(userId, secret string) error
+	EnableMFA(userId string, codeHashes []string) error
+	DisableMFA(userId string) error
+	ClaimStep(userId string, step int64) (bool, error)
+	UseRecoveryCode(userId, codeHash string) error
+	ReplaceRecoveryCodes(userId string, codeHashes []string) error
+}
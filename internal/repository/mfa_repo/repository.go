@@ -0,0 +1,156 @@
+package mfarepo
+
+import (
+	"database/sql"
+
+	apperrors "github.com/Kaushik1766/LibraryManagement/internal/app_errors"
+)
+
+var errMFAEnabled = apperrors.Conflict("mfa_already_enabled", "mfa is already enabled")
+
+type MFARepository struct {
+	db *sql.DB
+}
+
+func NewMFARepository(db *sql.DB) *MFARepository {
+	return &MFARepository{
+		db: db,
+	}
+}
+
+// SetPendingSecret starts setting up MFA for userId with secret, replacing a
+// setup that was never confirmed. It fails once MFA is enabled.
+func (repo *MFARepository) SetPendingSecret(userId, secret string) error {
+	res, err := repo.db.Exec(`
+		update users set totp_secret = $2, totp_last_step = null
+		where id = $1 and mfa_enabled_at is null
+`, userId, secret)
+	if err != nil {
+		return apperrors.FromPostgres(err)
+	}
+
+	rowsAffected, _ := res.RowsAffected()
+	if rowsAffected == 0 {
+		return errMFAEnabled
+	}
+	return nil
+}
+
+// EnableMFA confirms the pending secret of userId and stores its recovery
+// codes.
+func (repo *MFARepository) EnableMFA(userId string, codeHashes []string) error {
+	tx, err := repo.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	res, err := tx.Exec(`
+		update users set mfa_enabled_at = now()
+		where id = $1 and totp_secret is not null and mfa_enabled_at is null
+`, userId)
+	if err != nil {
+		return apperrors.FromPostgres(err)
+	}
+	rowsAffected, _ := res.RowsAffected()
+	if rowsAffected == 0 {
+		return errMFAEnabled
+	}
+
+	if err := replaceCodes(tx, userId, codeHashes); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// DisableMFA turns MFA off for userId and drops its recovery codes.
+func (repo *MFARepository) DisableMFA(userId string) error {
+	tx, err := repo.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`delete from mfa_recovery_codes where user_id = $1`, userId); err != nil {
+		return apperrors.FromPostgres(err)
+	}
+
+	res, err := tx.Exec(`
+		update users set totp_secret = null, mfa_enabled_at = null, totp_last_step = null
+		where id = $1
+`, userId)
+	if err != nil {
+		return apperrors.FromPostgres(err)
+	}
+	rowsAffected, _ := res.RowsAffected()
+	if rowsAffected == 0 {
+		return apperrors.NotFound("user_not_found", "user not found")
+	}
+
+	return tx.Commit()
+}
+
+// ClaimStep records that the code for step was used by userId and reports
+// false when it, or a later one, already was. Concurrent callers can't both
+// claim.
+func (repo *MFARepository) ClaimStep(userId string, step int64) (bool, error) {
+	res, err := repo.db.Exec(`
+		update users set totp_last_step = $2
+		where id = $1 and (totp_last_step is null or totp_last_step < $2)
+`, userId, step)
+	if err != nil {
+		return false, apperrors.FromPostgres(err)
+	}
+
+	rowsAffected, _ := res.RowsAffected()
+	return rowsAffected > 0, nil
+}
+
+// UseRecoveryCode spends a recovery code of userId. Used and unknown codes
+// are not found.
+func (repo *MFARepository) UseRecoveryCode(userId, codeHash string) error {
+	res, err := repo.db.Exec(`
+		update mfa_recovery_codes set used_at = now()
+		where user_id = $1 and code_hash = $2 and used_at is null
+`, userId, codeHash)
+	if err != nil {
+		return apperrors.FromPostgres(err)
+	}
+
+	rowsAffected, _ := res.RowsAffected()
+	if rowsAffected == 0 {
+		return apperrors.NotFound("recovery_code_not_found", "recovery code not found")
+	}
+	return nil
+}
+
+// ReplaceRecoveryCodes swaps every recovery code of userId, used or not, for
+// a new set.
+func (repo *MFARepository) ReplaceRecoveryCodes(userId string, codeHashes []string) error {
+	tx, err := repo.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := replaceCodes(tx, userId, codeHashes); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func replaceCodes(tx *sql.Tx, userId string, codeHashes []string) error {
+	if _, err := tx.Exec(`delete from mfa_recovery_codes where user_id = $1`, userId); err != nil {
+		return apperrors.FromPostgres(err)
+	}
+
+	for _, codeHash := range codeHashes {
+		if _, err := tx.Exec(`
+			insert into mfa_recovery_codes (user_id, code_hash)
+			values ($1, $2)
+`, userId, codeHash); err != nil {
+			return apperrors.FromPostgres(err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,303 @@
+package mfarepo
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+)
+
+func TestNewMFARepository(t *testing.T) {
+	db, _, _ := sqlmock.New()
+	defer db.Close()
+
+	want := &MFARepository{db: db}
+	if got := NewMFARepository(db); !reflect.DeepEqual(got, want) {
+		t.Errorf("NewMFARepository() = %v, want %v", got, want)
+	}
+}
+
+func TestMFARepository_SetPendingSecret(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	userId := uuid.New().String()
+
+	tests := []struct {
+		name      string
+		wantErr   bool
+		mockSetup func()
+	}{
+		{
+			name:    "valid set secret",
+			wantErr: false,
+			mockSetup: func() {
+				mock.ExpectExec("(?i)update users set totp_secret = \\$2, totp_last_step = null where id = \\$1 and mfa_enabled_at is null").
+					WithArgs(userId, "SECRET").
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+		},
+		{
+			name:    "already enabled",
+			wantErr: true,
+			mockSetup: func() {
+				mock.ExpectExec("(?i)update users set totp_secret = .*").WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &MFARepository{
+				db: db,
+			}
+			tt.mockSetup()
+			if err := repo.SetPendingSecret(userId, "SECRET"); (err != nil) != tt.wantErr {
+				t.Errorf("SetPendingSecret() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestMFARepository_EnableMFA(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	userId := uuid.New().String()
+
+	tests := []struct {
+		name      string
+		wantErr   bool
+		mockSetup func()
+	}{
+		{
+			name:    "valid enable",
+			wantErr: false,
+			mockSetup: func() {
+				mock.ExpectBegin()
+				mock.ExpectExec("(?i)update users set mfa_enabled_at = now\\(\\) .*").WithArgs(userId).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec("(?i)delete from mfa_recovery_codes .*").WithArgs(userId).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec("(?i)insert into mfa_recovery_codes .*").WithArgs(userId, "hash1").WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectExec("(?i)insert into mfa_recovery_codes .*").WithArgs(userId, "hash2").WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name:    "nothing pending",
+			wantErr: true,
+			mockSetup: func() {
+				mock.ExpectBegin()
+				mock.ExpectExec("(?i)update users set mfa_enabled_at = .*").WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectRollback()
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &MFARepository{
+				db: db,
+			}
+			tt.mockSetup()
+			if err := repo.EnableMFA(userId, []string{"hash1", "hash2"}); (err != nil) != tt.wantErr {
+				t.Errorf("EnableMFA() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("unmet expectations: %v", err)
+			}
+		})
+	}
+}
+
+func TestMFARepository_DisableMFA(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	userId := uuid.New().String()
+
+	tests := []struct {
+		name      string
+		wantErr   bool
+		mockSetup func()
+	}{
+		{
+			name:    "valid disable",
+			wantErr: false,
+			mockSetup: func() {
+				mock.ExpectBegin()
+				mock.ExpectExec("(?i)delete from mfa_recovery_codes .*").WithArgs(userId).WillReturnResult(sqlmock.NewResult(0, 10))
+				mock.ExpectExec("(?i)update users set totp_secret = null, mfa_enabled_at = null.*").WithArgs(userId).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name:    "user not found",
+			wantErr: true,
+			mockSetup: func() {
+				mock.ExpectBegin()
+				mock.ExpectExec("(?i)delete from mfa_recovery_codes .*").WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec("(?i)update users set totp_secret = null.*").WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectRollback()
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &MFARepository{
+				db: db,
+			}
+			tt.mockSetup()
+			if err := repo.DisableMFA(userId); (err != nil) != tt.wantErr {
+				t.Errorf("DisableMFA() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("unmet expectations: %v", err)
+			}
+		})
+	}
+}
+
+func TestMFARepository_ClaimStep(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	userId := uuid.New().String()
+
+	tests := []struct {
+		name      string
+		want      bool
+		wantErr   bool
+		mockSetup func()
+	}{
+		{
+			name:    "new step",
+			want:    true,
+			wantErr: false,
+			mockSetup: func() {
+				mock.ExpectExec("(?i)update users set totp_last_step = \\$2 .*").
+					WithArgs(userId, int64(37037037)).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+		},
+		{
+			name:    "step already used",
+			want:    false,
+			wantErr: false,
+			mockSetup: func() {
+				mock.ExpectExec("(?i)update users set totp_last_step = .*").WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+		},
+		{
+			name:    "database error",
+			want:    false,
+			wantErr: true,
+			mockSetup: func() {
+				mock.ExpectExec("(?i)update users set totp_last_step = .*").WillReturnError(errors.New("database error"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &MFARepository{
+				db: db,
+			}
+			tt.mockSetup()
+			got, err := repo.ClaimStep(userId, 37037037)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ClaimStep() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("ClaimStep() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMFARepository_UseRecoveryCode(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	userId := uuid.New().String()
+
+	tests := []struct {
+		name      string
+		wantErr   bool
+		mockSetup func()
+	}{
+		{
+			name:    "valid use",
+			wantErr: false,
+			mockSetup: func() {
+				mock.ExpectExec("(?i)update mfa_recovery_codes set used_at = now\\(\\) .* and used_at is null").
+					WithArgs(userId, "hash").
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+		},
+		{
+			name:    "used or unknown",
+			wantErr: true,
+			mockSetup: func() {
+				mock.ExpectExec("(?i)update mfa_recovery_codes set used_at = .*").WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &MFARepository{
+				db: db,
+			}
+			tt.mockSetup()
+			if err := repo.UseRecoveryCode(userId, "hash"); (err != nil) != tt.wantErr {
+				t.Errorf("UseRecoveryCode() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestMFARepository_ReplaceRecoveryCodes(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	userId := uuid.New().String()
+
+	tests := []struct {
+		name      string
+		wantErr   bool
+		mockSetup func()
+	}{
+		{
+			name:    "valid replace",
+			wantErr: false,
+			mockSetup: func() {
+				mock.ExpectBegin()
+				mock.ExpectExec("(?i)delete from mfa_recovery_codes .*").WithArgs(userId).WillReturnResult(sqlmock.NewResult(0, 10))
+				mock.ExpectExec("(?i)insert into mfa_recovery_codes .*").WithArgs(userId, "hash1").WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name:    "database error",
+			wantErr: true,
+			mockSetup: func() {
+				mock.ExpectBegin()
+				mock.ExpectExec("(?i)delete from mfa_recovery_codes .*").WillReturnError(errors.New("database error"))
+				mock.ExpectRollback()
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &MFARepository{
+				db: db,
+			}
+			tt.mockSetup()
+			if err := repo.ReplaceRecoveryCodes(userId, []string{"hash1"}); (err != nil) != tt.wantErr {
+				t.Errorf("ReplaceRecoveryCodes() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("unmet expectations: %v", err)
+			}
+		})
+	}
+}
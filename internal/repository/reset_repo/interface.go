@@ -0,0 +1,10 @@
+package resetrepo
+
+import "time"
+
+//go:generate mockgen -source=interface.go -destination=../../../mocks/mock_reset_storage.go -package=mocks
+type ResetStorage interface {
+	CreateReset(userId, tokenHash string, expiresAt time.Time) error
+	UseReset(tokenHash string) (string, error)
+	PurgeExpired() (int64, error)
+}
@@ -0,0 +1,71 @@
+package resetrepo
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	apperrors "github.com/Kaushik1766/LibraryManagement/internal/app_errors"
+)
+
+type ResetRepository struct {
+	db *sql.DB
+}
+
+func NewResetRepository(db *sql.DB) *ResetRepository {
+	return &ResetRepository{
+		db: db,
+	}
+}
+
+// CreateReset stores a new reset token for userId and retires any earlier one
+// that wasn't used, so only the latest email works.
+func (repo *ResetRepository) CreateReset(userId, tokenHash string, expiresAt time.Time) error {
+	tx, err := repo.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`
+		update password_resets set used_at = now()
+		where user_id = $1 and used_at is null
+`, userId); err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec(`
+		insert into password_resets (user_id, token_hash, expires_at)
+		values ($1, $2, $3)
+`, userId, tokenHash, expiresAt); err != nil {
+		return apperrors.FromPostgres(err)
+	}
+
+	return tx.Commit()
+}
+
+// UseReset spends the token and returns whose it was. Used, expired and
+// unknown tokens are all not found, and a token can only be spent once even
+// by concurrent callers.
+func (repo *ResetRepository) UseReset(tokenHash string) (string, error) {
+	var userId string
+	err := repo.db.QueryRow(`
+		update password_resets set used_at = now()
+		where token_hash = $1 and used_at is null and expires_at > now()
+		returning user_id
+`, tokenHash).Scan(&userId)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", apperrors.NotFound("reset_not_found", "reset token not found")
+	}
+	return userId, err
+}
+
+// PurgeExpired drops tokens that can no longer be used and returns how many
+// went.
+func (repo *ResetRepository) PurgeExpired() (int64, error) {
+	res, err := repo.db.Exec(`delete from password_resets where expires_at < now()`)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
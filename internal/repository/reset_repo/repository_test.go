@@ -0,0 +1,152 @@
+package resetrepo
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	apperrors "github.com/Kaushik1766/LibraryManagement/internal/app_errors"
+	"github.com/google/uuid"
+)
+
+func TestNewResetRepository(t *testing.T) {
+	db, _, _ := sqlmock.New()
+	defer db.Close()
+
+	want := &ResetRepository{db: db}
+	if got := NewResetRepository(db); !reflect.DeepEqual(got, want) {
+		t.Errorf("NewResetRepository() = %v, want %v", got, want)
+	}
+}
+
+func TestResetRepository_CreateReset(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	userId := uuid.New().String()
+	expiresAt := time.Now().Add(time.Hour)
+
+	tests := []struct {
+		name      string
+		wantErr   bool
+		mockSetup func()
+	}{
+		{
+			name:    "valid create reset",
+			wantErr: false,
+			mockSetup: func() {
+				mock.ExpectBegin()
+				mock.ExpectExec("(?i)update password_resets set used_at .*").WithArgs(userId).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec("(?i)insert into password_resets .*").WithArgs(userId, "hash", expiresAt).WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name:    "database error",
+			wantErr: true,
+			mockSetup: func() {
+				mock.ExpectBegin()
+				mock.ExpectExec("(?i)update password_resets set used_at .*").WithArgs(userId).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectExec("(?i)insert into password_resets .*").WillReturnError(errors.New("database error"))
+				mock.ExpectRollback()
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &ResetRepository{
+				db: db,
+			}
+			tt.mockSetup()
+			if err := repo.CreateReset(userId, "hash", expiresAt); (err != nil) != tt.wantErr {
+				t.Errorf("CreateReset() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("unmet expectations: %v", err)
+			}
+		})
+	}
+}
+
+func TestResetRepository_UseReset(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	userId := uuid.New().String()
+
+	tests := []struct {
+		name      string
+		want      string
+		wantKind  apperrors.Kind
+		wantErr   bool
+		mockSetup func()
+	}{
+		{
+			name:    "valid use reset",
+			want:    userId,
+			wantErr: false,
+			mockSetup: func() {
+				mock.ExpectQuery("(?i)update password_resets set used_at .* returning user_id").WithArgs("hash").
+					WillReturnRows(sqlmock.NewRows([]string{"user_id"}).AddRow(userId))
+			},
+		},
+		{
+			name:     "used, expired or unknown token",
+			wantKind: apperrors.KindNotFound,
+			wantErr:  true,
+			mockSetup: func() {
+				mock.ExpectQuery("(?i)update password_resets set used_at .*").WithArgs("hash").
+					WillReturnRows(sqlmock.NewRows([]string{"user_id"}))
+			},
+		},
+		{
+			name:     "database error",
+			wantKind: apperrors.KindInternal,
+			wantErr:  true,
+			mockSetup: func() {
+				mock.ExpectQuery("(?i)update password_resets set used_at .*").WillReturnError(errors.New("database error"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &ResetRepository{
+				db: db,
+			}
+			tt.mockSetup()
+			got, err := repo.UseReset("hash")
+			if (err != nil) != tt.wantErr {
+				t.Errorf("UseReset() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err != nil && apperrors.KindOf(err) != tt.wantKind {
+				t.Errorf("UseReset() kind = %v, want %v", apperrors.KindOf(err), tt.wantKind)
+			}
+			if got != tt.want {
+				t.Errorf("UseReset() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestResetRepository_PurgeExpired(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	repo := &ResetRepository{
+		db: db,
+	}
+
+	mock.ExpectExec("(?i)delete from password_resets where expires_at .*").WillReturnResult(sqlmock.NewResult(0, 4))
+
+	got, err := repo.PurgeExpired()
+	if err != nil {
+		t.Errorf("PurgeExpired() error = %v", err)
+		return
+	}
+	if got != 4 {
+		t.Errorf("PurgeExpired() got = %v, want 4", got)
+	}
+}
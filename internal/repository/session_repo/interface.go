@@ -0,0 +1,16 @@
+package sessionrepo
+
+import "github.com/Kaushik1766/LibraryManagement/internal/models"
+
+//go:generate mockgen -source=interface.go -destination=../../../mocks/mock_session_storage.go -package=mocks
+type SessionStorage interface {
+	CreateSession(session models.Session) error
+	GetSessionByToken(tokenHash string) (models.Session, error)
+	GetSessionByAccessToken(jti string) (models.Session, error)
+	RotateSession(sessionId string, next models.Session) error
+	RevokeFamily(familyId string) error
+	RevokeUserSessions(userId string) error
+	ChangePassword(userId, password, familyId string) error
+	IsRevoked(jti string) (bool, error)
+	PurgeExpired() (int64, error)
+}
@@ -0,0 +1,192 @@
+package sessionrepo
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+
+	apperrors "github.com/Kaushik1766/LibraryManagement/internal/app_errors"
+	"github.com/Kaushik1766/LibraryManagement/internal/models"
+)
+
+const selectSessions = `
+		select id, user_id, family_id, token_hash, access_jti, access_expires_at, expires_at, rotated_at, revoked_at
+		from sessions
+`
+
+type SessionRepository struct {
+	db *sql.DB
+}
+
+func NewSessionRepository(db *sql.DB) *SessionRepository {
+	return &SessionRepository{
+		db: db,
+	}
+}
+
+func (repo *SessionRepository) CreateSession(session models.Session) error {
+	_, err := repo.db.Exec(`
+		insert into sessions (user_id, family_id, token_hash, access_jti, access_expires_at, expires_at)
+		values ($1, $2, $3, $4, $5, $6)
+`, session.UserID, session.FamilyID, session.TokenHash, session.AccessJTI, session.AccessExpiresAt, session.ExpiresAt)
+	return err
+}
+
+func (repo *SessionRepository) GetSessionByToken(tokenHash string) (models.Session, error) {
+	return scanSession(repo.db.QueryRow(selectSessions+`where token_hash = $1`, tokenHash))
+}
+
+func (repo *SessionRepository) GetSessionByAccessToken(jti string) (models.Session, error) {
+	return scanSession(repo.db.QueryRow(selectSessions+`where access_jti = $1`, jti))
+}
+
+// RotateSession retires sessionId and stores next in its place. Only one
+// caller can rotate a given session, a second attempt is refused.
+func (repo *SessionRepository) RotateSession(sessionId string, next models.Session) error {
+	tx, err := repo.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	res, err := tx.Exec(`
+		update sessions set rotated_at = now()
+		where id = $1 and rotated_at is null and revoked_at is null
+`, sessionId)
+	if err != nil {
+		return err
+	}
+	if rows, err := res.RowsAffected(); err != nil || rows == 0 {
+		return apperrors.Unauthorized("refresh_token_reused", "refresh token already used")
+	}
+
+	if _, err := tx.Exec(`
+		insert into sessions (user_id, family_id, token_hash, access_jti, access_expires_at, expires_at)
+		values ($1, $2, $3, $4, $5, $6)
+`, next.UserID, next.FamilyID, next.TokenHash, next.AccessJTI, next.AccessExpiresAt, next.ExpiresAt); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// RevokeFamily ends a login along with every token rotated from it. Access
+// tokens from the family that haven't expired yet go on the revocation list.
+func (repo *SessionRepository) RevokeFamily(familyId string) error {
+	_, err := repo.db.Exec(`
+		with revoked as (
+		    update sessions set revoked_at = now()
+		    where family_id = $1 and revoked_at is null
+		    returning access_jti, access_expires_at
+		)
+		insert into revoked_tokens (jti, expires_at)
+		select access_jti, access_expires_at from revoked where access_expires_at > now()
+		on conflict do nothing
+`, familyId)
+	return err
+}
+
+// RevokeUserSessions logs userId out of every device.
+func (repo *SessionRepository) RevokeUserSessions(userId string) error {
+	_, err := repo.db.Exec(`
+		with revoked as (
+		    update sessions set revoked_at = now()
+		    where user_id = $1 and revoked_at is null
+		    returning access_jti, access_expires_at
+		)
+		insert into revoked_tokens (jti, expires_at)
+		select access_jti, access_expires_at from revoked where access_expires_at > now()
+		on conflict do nothing
+`, userId)
+	return err
+}
+
+// ChangePassword sets the password of userId and logs them out of every
+// device but the login familyId belongs to. Both happen or neither does, so
+// a new password never leaves the other devices signed in.
+func (repo *SessionRepository) ChangePassword(userId, password, familyId string) error {
+	tx, err := repo.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	res, err := tx.Exec(`update users set password = $2 where id = $1`, userId, password)
+	if err != nil {
+		return apperrors.FromPostgres(err)
+	}
+	if rows, _ := res.RowsAffected(); rows == 0 {
+		return apperrors.NotFound("user_not_found", "user not found")
+	}
+
+	if _, err := tx.Exec(`
+		with revoked as (
+		    update sessions set revoked_at = now()
+		    where user_id = $1 and family_id <> $2 and revoked_at is null
+		    returning access_jti, access_expires_at
+		)
+		insert into revoked_tokens (jti, expires_at)
+		select access_jti, access_expires_at from revoked where access_expires_at > now()
+		on conflict do nothing
+`, userId, familyId); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+func (repo *SessionRepository) IsRevoked(jti string) (bool, error) {
+	var revoked bool
+	err := repo.db.QueryRow(`
+		select exists(select 1 from revoked_tokens where jti = $1)
+`, jti).Scan(&revoked)
+	return revoked, err
+}
+
+// PurgeExpired drops sessions and revocations that can no longer be used and
+// returns how many rows went.
+func (repo *SessionRepository) PurgeExpired() (int64, error) {
+	tx, err := repo.db.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	sessions, err := tx.Exec(`delete from sessions where expires_at < now()`)
+	if err != nil {
+		return 0, err
+	}
+	tokens, err := tx.Exec(`delete from revoked_tokens where expires_at < now()`)
+	if err != nil {
+		return 0, err
+	}
+
+	purgedSessions, _ := sessions.RowsAffected()
+	purgedTokens, _ := tokens.RowsAffected()
+
+	return purgedSessions + purgedTokens, tx.Commit()
+}
+
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+func scanSession(row scanner) (models.Session, error) {
+	var s models.Session
+	var rotatedAt, revokedAt sql.Null[time.Time]
+	err := row.Scan(&s.ID, &s.UserID, &s.FamilyID, &s.TokenHash, &s.AccessJTI, &s.AccessExpiresAt, &s.ExpiresAt, &rotatedAt, &revokedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return s, apperrors.NotFound("session_not_found", "session not found")
+	}
+	if err != nil {
+		return s, err
+	}
+
+	if rotatedAt.Valid {
+		s.RotatedAt = &rotatedAt.V
+	}
+	if revokedAt.Valid {
+		s.RevokedAt = &revokedAt.V
+	}
+	return s, nil
+}
@@ -0,0 +1,359 @@
+package sessionrepo
+
+import (
+	"database/sql"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/Kaushik1766/LibraryManagement/internal/models"
+	"github.com/google/uuid"
+)
+
+func TestNewSessionRepository(t *testing.T) {
+	db, _, _ := sqlmock.New()
+	defer db.Close()
+
+	want := &SessionRepository{db: db}
+	if got := NewSessionRepository(db); !reflect.DeepEqual(got, want) {
+		t.Errorf("NewSessionRepository() = %v, want %v", got, want)
+	}
+}
+
+func TestSessionRepository_CreateSession(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	session := models.Session{
+		UserID:          uuid.New(),
+		FamilyID:        uuid.New(),
+		TokenHash:       "hash",
+		AccessJTI:       uuid.New(),
+		AccessExpiresAt: time.Now().Add(time.Hour),
+		ExpiresAt:       time.Now().Add(time.Hour * 24),
+	}
+
+	tests := []struct {
+		name      string
+		wantErr   bool
+		mockSetup func()
+	}{
+		{
+			name:    "valid create session",
+			wantErr: false,
+			mockSetup: func() {
+				mock.ExpectExec("(?i)insert into sessions .*").
+					WithArgs(session.UserID, session.FamilyID, session.TokenHash, session.AccessJTI, session.AccessExpiresAt, session.ExpiresAt).
+					WillReturnResult(sqlmock.NewResult(1, 1))
+			},
+		},
+		{
+			name:    "database error",
+			wantErr: true,
+			mockSetup: func() {
+				mock.ExpectExec("(?i)insert into sessions .*").WillReturnError(errors.New("database error"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &SessionRepository{
+				db: db,
+			}
+			tt.mockSetup()
+			if err := repo.CreateSession(session); (err != nil) != tt.wantErr {
+				t.Errorf("CreateSession() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSessionRepository_GetSessionByToken(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	rotatedAt := time.Now()
+	session := models.Session{
+		ID:              uuid.New(),
+		UserID:          uuid.New(),
+		FamilyID:        uuid.New(),
+		TokenHash:       "hash",
+		AccessJTI:       uuid.New(),
+		AccessExpiresAt: time.Now().Add(time.Hour),
+		ExpiresAt:       time.Now().Add(time.Hour * 24),
+		RotatedAt:       &rotatedAt,
+	}
+
+	columns := []string{"id", "user_id", "family_id", "token_hash", "access_jti", "access_expires_at", "expires_at", "rotated_at", "revoked_at"}
+
+	tests := []struct {
+		name      string
+		want      models.Session
+		wantErr   bool
+		mockSetup func()
+	}{
+		{
+			name:    "valid get session",
+			want:    session,
+			wantErr: false,
+			mockSetup: func() {
+				mock.ExpectQuery("(?i)select .* from sessions where token_hash = .*").
+					WithArgs("hash").
+					WillReturnRows(sqlmock.NewRows(columns).AddRow(session.ID, session.UserID, session.FamilyID, session.TokenHash, session.AccessJTI, session.AccessExpiresAt, session.ExpiresAt, rotatedAt, nil))
+			},
+		},
+		{
+			name:    "session not found",
+			wantErr: true,
+			mockSetup: func() {
+				mock.ExpectQuery("(?i)select .* from sessions where token_hash = .*").
+					WithArgs("hash").
+					WillReturnError(sql.ErrNoRows)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &SessionRepository{
+				db: db,
+			}
+			tt.mockSetup()
+			got, err := repo.GetSessionByToken("hash")
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetSessionByToken() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetSessionByToken() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSessionRepository_RotateSession(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	sessionId := uuid.New().String()
+	next := models.Session{
+		UserID:          uuid.New(),
+		FamilyID:        uuid.New(),
+		TokenHash:       "next",
+		AccessJTI:       uuid.New(),
+		AccessExpiresAt: time.Now().Add(time.Hour),
+		ExpiresAt:       time.Now().Add(time.Hour * 24),
+	}
+
+	tests := []struct {
+		name      string
+		wantErr   bool
+		mockSetup func()
+	}{
+		{
+			name:    "valid rotation",
+			wantErr: false,
+			mockSetup: func() {
+				mock.ExpectBegin()
+				mock.ExpectExec("(?i)update sessions set rotated_at .*").WithArgs(sessionId).WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec("(?i)insert into sessions .*").WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name:    "already rotated",
+			wantErr: true,
+			mockSetup: func() {
+				mock.ExpectBegin()
+				mock.ExpectExec("(?i)update sessions set rotated_at .*").WithArgs(sessionId).WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectRollback()
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &SessionRepository{
+				db: db,
+			}
+			tt.mockSetup()
+			if err := repo.RotateSession(sessionId, next); (err != nil) != tt.wantErr {
+				t.Errorf("RotateSession() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("unmet expectations: %v", err)
+			}
+		})
+	}
+}
+
+func TestSessionRepository_RevokeFamily(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	repo := &SessionRepository{
+		db: db,
+	}
+	familyId := uuid.New().String()
+
+	mock.ExpectExec("(?i)with revoked as .*update sessions .*family_id = .*insert into revoked_tokens .*").
+		WithArgs(familyId).
+		WillReturnResult(sqlmock.NewResult(0, 2))
+	if err := repo.RevokeFamily(familyId); err != nil {
+		t.Errorf("RevokeFamily() error = %v", err)
+	}
+
+	userId := uuid.New().String()
+	mock.ExpectExec("(?i)with revoked as .*update sessions .*user_id = .*insert into revoked_tokens .*").
+		WithArgs(userId).
+		WillReturnError(errors.New("database error"))
+	if err := repo.RevokeUserSessions(userId); err == nil {
+		t.Errorf("RevokeUserSessions() expected error")
+	}
+}
+
+func TestSessionRepository_ChangePassword(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	userId := uuid.New().String()
+	familyId := uuid.New().String()
+
+	tests := []struct {
+		name      string
+		wantErr   bool
+		mockSetup func()
+	}{
+		{
+			name:    "password set and other devices signed out",
+			wantErr: false,
+			mockSetup: func() {
+				mock.ExpectBegin()
+				mock.ExpectExec("(?i)update users set password = .* where id = .*").
+					WithArgs(userId, "hash").
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec("(?i)with revoked as .*update sessions .*user_id = .*family_id <> .*insert into revoked_tokens .*").
+					WithArgs(userId, familyId).
+					WillReturnResult(sqlmock.NewResult(0, 3))
+				mock.ExpectCommit()
+			},
+		},
+		{
+			name:    "user not found",
+			wantErr: true,
+			mockSetup: func() {
+				mock.ExpectBegin()
+				mock.ExpectExec("(?i)update users set password = .*").
+					WithArgs(userId, "hash").
+					WillReturnResult(sqlmock.NewResult(0, 0))
+				mock.ExpectRollback()
+			},
+		},
+		{
+			name:    "revoking fails keeps the old password",
+			wantErr: true,
+			mockSetup: func() {
+				mock.ExpectBegin()
+				mock.ExpectExec("(?i)update users set password = .*").
+					WithArgs(userId, "hash").
+					WillReturnResult(sqlmock.NewResult(0, 1))
+				mock.ExpectExec("(?i)with revoked as .*update sessions .*").
+					WithArgs(userId, familyId).
+					WillReturnError(errors.New("database error"))
+				mock.ExpectRollback()
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &SessionRepository{
+				db: db,
+			}
+			tt.mockSetup()
+			if err := repo.ChangePassword(userId, "hash", familyId); (err != nil) != tt.wantErr {
+				t.Errorf("ChangePassword() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err := mock.ExpectationsWereMet(); err != nil {
+				t.Errorf("unmet expectations: %v", err)
+			}
+		})
+	}
+}
+
+func TestSessionRepository_IsRevoked(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	jti := uuid.New().String()
+
+	tests := []struct {
+		name      string
+		want      bool
+		wantErr   bool
+		mockSetup func()
+	}{
+		{
+			name:    "revoked",
+			want:    true,
+			wantErr: false,
+			mockSetup: func() {
+				mock.ExpectQuery("(?i)select exists.*revoked_tokens.*").WithArgs(jti).WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(true))
+			},
+		},
+		{
+			name:    "not revoked",
+			want:    false,
+			wantErr: false,
+			mockSetup: func() {
+				mock.ExpectQuery("(?i)select exists.*revoked_tokens.*").WithArgs(jti).WillReturnRows(sqlmock.NewRows([]string{"exists"}).AddRow(false))
+			},
+		},
+		{
+			name:    "database error",
+			want:    false,
+			wantErr: true,
+			mockSetup: func() {
+				mock.ExpectQuery("(?i)select exists.*revoked_tokens.*").WithArgs(jti).WillReturnError(errors.New("database error"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &SessionRepository{
+				db: db,
+			}
+			tt.mockSetup()
+			got, err := repo.IsRevoked(jti)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("IsRevoked() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("IsRevoked() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSessionRepository_PurgeExpired(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	repo := &SessionRepository{
+		db: db,
+	}
+
+	mock.ExpectBegin()
+	mock.ExpectExec("(?i)delete from sessions where expires_at .*").WillReturnResult(sqlmock.NewResult(0, 3))
+	mock.ExpectExec("(?i)delete from revoked_tokens where expires_at .*").WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectCommit()
+
+	got, err := repo.PurgeExpired()
+	if err != nil {
+		t.Errorf("PurgeExpired() error = %v", err)
+		return
+	}
+	if got != 5 {
+		t.Errorf("PurgeExpired() got = %v, want 5", got)
+	}
+}
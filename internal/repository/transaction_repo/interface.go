@@ -1,11 +1,21 @@
 package transactionrepo
 
-import "github.com/Kaushik1766/LibraryManagement/internal/models"
+import (
+	"time"
+
+	"github.com/Kaushik1766/LibraryManagement/internal/models"
+	"github.com/Kaushik1766/LibraryManagement/internal/pagination"
+)
 
 //go:generate mockgen -source=interface.go -destination=../../../mocks/mock_transaction_storage.go -package=mocks
 type TransactionStorage interface {
-	IssueBook(bookId, userId, issueFor string) (string, error)
-	ReturnBook(bookId, userId string) error
-	GetAllTransactions(dto models.GetTransactionRequestDTO) ([]models.Transaction, error)
-	GetOverDueTransactions(userId string) ([]models.Transaction, error)
+	IssueBook(bookId, userId string, terms models.LoanTerms) (string, error)
+	ReturnBook(bookId, userId string) (models.Transaction, error)
+	IssueCopy(copyId, userId, issuedBy string, terms models.LoanTerms) (models.Transaction, error)
+	ReturnCopy(copyId, userId, returnedBy string) (models.Transaction, error)
+	GetAllTransactions(dto models.GetTransactionRequestDTO, page pagination.Request) (pagination.Page[models.Transaction], error)
+	GetOverDueTransactions(userId string, page pagination.Request) (pagination.Page[models.Transaction], error)
+	GetTransactionById(transactionId string) (models.Transaction, error)
+	CountOpenLoans(userId, itemType string) (int, error)
+	RenewBook(transactionId, extendBy string, maxRenewals int) (time.Time, error)
 }
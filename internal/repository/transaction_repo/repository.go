@@ -3,11 +3,11 @@ package transactionrepo
 import (
 	"database/sql"
 	"errors"
-	"fmt"
-	"log"
 	"time"
 
+	apperrors "github.com/Kaushik1766/LibraryManagement/internal/app_errors"
 	"github.com/Kaushik1766/LibraryManagement/internal/models"
+	"github.com/Kaushik1766/LibraryManagement/internal/pagination"
 )
 
 type TransactionRepository struct {
@@ -20,105 +20,314 @@ func NewTransactionRepository(db *sql.DB) *TransactionRepository {
 	}
 }
 
-func (repo *TransactionRepository) IssueBook(bookId, userId, issueFor string) (string, error) {
+// IssueBook lends out a copy of title bookId. A copy set aside for the user's
+// own hold is preferred, copies set aside for someone else are never picked.
+func (repo *TransactionRepository) IssueBook(bookId, userId string, terms models.LoanTerms) (string, error) {
 	var id string
-	fmt.Println(issueFor)
 	err := repo.db.QueryRow(`
-		insert into transactions (book_id,user_id,issued_till)
-		select $1, $2, now() + cast($3 as interval)
-		where not exists(
-		    select 1 from transactions where book_id = $1 and returned_at is null
+		insert into transactions (copy_id,user_id,issued_till,fine_per_day,fine_cap)
+		select c.id, $2, now() + cast($3 as interval), $4, $5
+		from copies as c
+		where c.title_id = $1 and c.status = 'available'
+		and not exists(
+		    select 1 from transactions where copy_id = c.id and returned_at is null
 		)
+		and not exists(
+		    select 1 from holds where copy_id = c.id and status = 'ready' and user_id <> $2
+		)
+		and not exists(
+		    select 1 from transactions as t join copies as c1 on t.copy_id = c1.id
+		    where c1.title_id = $1 and t.user_id = $2 and t.returned_at is null
+		)
+		order by exists(
+		    select 1 from holds where copy_id = c.id and status = 'ready' and user_id = $2
+		) desc
+		limit 1
 		returning id
-`, bookId, userId, issueFor).Scan(&id)
+`, bookId, userId, terms.Period, terms.FinePerDay, terms.FineCap).Scan(&id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", apperrors.Conflict("book_not_available", "book not available")
+	}
 	if err != nil {
-		log.Println(err)
-		return "", errors.New("book not available")
+		return "", apperrors.FromPostgres(err)
 	}
 	return id, err
 }
 
-func (repo *TransactionRepository) ReturnBook(bookId, userId string) error {
-	res, err := repo.db.Exec(`update transactions set returned_at = $1 where book_id = $2 and user_id = $3 and returned_at is null`, time.Now(), bookId, userId)
-	if err != nil {
-		return err
+// ReturnBook closes the user's open loan of a copy of title bookId. The
+// returned transaction carries its id along with the title and copy ids.
+func (repo *TransactionRepository) ReturnBook(bookId, userId string) (models.Transaction, error) {
+	var tx models.Transaction
+	err := repo.db.QueryRow(`
+		update transactions as t set returned_at = $1
+		from copies as c
+		where c.id = t.copy_id and c.title_id = $2 and t.user_id = $3 and t.returned_at is null
+		returning t.id, c.title_id, t.copy_id
+`, time.Now(), bookId, userId).Scan(&tx.ID, &tx.Book.ID, &tx.Copy.ID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return tx, apperrors.Conflict("not_on_loan", "book already present nothing to return")
 	}
+	return tx, apperrors.FromPostgres(err)
+}
 
-	rowsAffected, _ := res.RowsAffected()
+// IssueCopy lends a specific copy to userId on behalf of staff member
+// issuedBy. The same availability rules as IssueBook apply.
+func (repo *TransactionRepository) IssueCopy(copyId, userId, issuedBy string, terms models.LoanTerms) (models.Transaction, error) {
+	var tx models.Transaction
+	err := repo.db.QueryRow(`
+		with issued as (
+		    insert into transactions (copy_id, user_id, issued_by, issued_till, fine_per_day, fine_cap)
+		    select c.id, $2, $3, now() + cast($4 as interval), $5, $6
+		    from copies as c
+		    where c.id = $1 and c.status = 'available'
+		    and not exists(
+		        select 1 from transactions where copy_id = c.id and returned_at is null
+		    )
+		    and not exists(
+		        select 1 from holds where copy_id = c.id and status = 'ready' and user_id <> $2
+		    )
+		    and not exists(
+		        select 1 from transactions as t join copies as c1 on t.copy_id = c1.id
+		        where c1.title_id = c.title_id and t.user_id = $2 and t.returned_at is null
+		    )
+		    returning id, copy_id
+		)
+		select i.id, c.title_id, i.copy_id from issued as i join copies as c on i.copy_id = c.id
+`, copyId, userId, issuedBy, terms.Period, terms.FinePerDay, terms.FineCap).Scan(&tx.ID, &tx.Book.ID, &tx.Copy.ID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return tx, apperrors.Conflict("copy_not_available", "copy not available")
+	}
+	if err != nil {
+		return tx, apperrors.FromPostgres(err)
+	}
+	return tx, nil
+}
 
-	if rowsAffected == 0 {
-		return errors.New("book already present nothing to return")
+// ReturnCopy checks a copy in on behalf of staff member returnedBy. An empty
+// userId accepts the copy back from whoever has it.
+func (repo *TransactionRepository) ReturnCopy(copyId, userId, returnedBy string) (models.Transaction, error) {
+	var tx models.Transaction
+	err := repo.db.QueryRow(`
+		update transactions as t set returned_at = $1, returned_by = $4
+		from copies as c
+		where c.id = t.copy_id and t.copy_id = $2
+		and ($3='' or t.user_id = cast($3 as uuid))
+		and t.returned_at is null
+		returning t.id, c.title_id, t.copy_id, t.user_id
+`, time.Now(), copyId, userId, returnedBy).Scan(&tx.ID, &tx.Book.ID, &tx.Copy.ID, &tx.User.ID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return tx, apperrors.Conflict("not_on_loan", "copy is not on loan")
 	}
-	return nil
+	return tx, apperrors.FromPostgres(err)
 }
 
-func (repo *TransactionRepository) GetAllTransactions(dto models.GetTransactionRequestDTO) ([]models.Transaction, error) {
-	var transactions []models.Transaction
+// transactionsOf selects the loans of user $5 issued between $1 and $2,
+// optionally narrowed by $3 returned, $4 title and $6 transaction id.
+const transactionsOf = `
+	from transactions as t
+	left join users as u on t.user_id = u.id
+	left join copies as c on t.copy_id = c.id
+	left join titles as b on c.title_id = b.id
+	left join users as ib on t.issued_by = ib.id
+	left join users as rb on t.returned_by = rb.id
+	where t.issued_at > $1 and t.issued_at < $2
+	and ($3='' or (returned_at is null) <> cast($3 as boolean))
+	and ($4='' or b.title ilike '%'||$4||'%')
+	and ($6='' or t.id = cast($6 as uuid))
+	and u.id = $5
+`
 
-	rows, err := repo.db.Query(`
-		select t.id, t.issued_at, t.returned_at, t.issued_till, b.id, b.title, u.email  from transactions as t
-		left join users as u on t.user_id = u.id
-		left join books as b on t.book_id = b.id
-		where t.issued_at > $1 and t.issued_at < $2 
-		and ($3='' or (returned_at is null) <> cast($3 as boolean))
-		and ($4='' or b.title ilike '%'||$4||'%')
-		and ($6='' or t.id = cast($6 as uuid))
-		and u.id = $5
-`, dto.StartTime, dto.EndTime, dto.Returned, dto.BookName, dto.UserId, dto.TransactionId)
+// overdueOf selects loans that are or were overdue, for user $1 or everyone.
+const overdueOf = `
+	from transactions as t
+	left join copies as c on t.copy_id = c.id
+	left join titles as b on c.title_id = b.id
+	where ($1='' or t.user_id = cast($1 as uuid))
+	and t.issued_till < now()
+	and (t.returned_at is null or t.returned_at > t.issued_till)
+`
+
+var transactionSorts = map[string]pagination.Column[models.Transaction]{
+	"issued_at": {
+		Expr: "t.issued_at",
+		Type: "timestamp",
+		Key:  func(tx models.Transaction) string { return tx.IssuedAt.Format(time.RFC3339Nano) },
+	},
+	"issued_till": {
+		Expr: "t.issued_till",
+		Type: "timestamp",
+		Key:  func(tx models.Transaction) string { return tx.IssuedTill.Format(time.RFC3339Nano) },
+	},
+}
+
+func transactionId(tx models.Transaction) string {
+	return tx.ID.String()
+}
 
+func (repo *TransactionRepository) GetAllTransactions(dto models.GetTransactionRequestDTO, page pagination.Request) (pagination.Page[models.Transaction], error) {
+	order, err := pagination.NewOrder(page, transactionSorts, "-issued_at")
 	if err != nil {
-		return nil, err
+		return pagination.Page[models.Transaction]{}, err
 	}
 
+	args := []any{dto.StartTime, dto.EndTime, dto.Returned, dto.BookName, dto.UserId, dto.TransactionId}
+
+	var total int
+	err = repo.db.QueryRow(`select count(*)`+transactionsOf, args...).Scan(&total)
+	if err != nil {
+		return pagination.Page[models.Transaction]{}, apperrors.FromPostgres(err)
+	}
+
+	args = append(append(args, order.AfterArgs()...), order.Fetch())
+	rows, err := repo.db.Query(`
+		select t.id, t.issued_at, t.returned_at, t.issued_till, b.id, c.id, b.title, u.email, ib.email, rb.email
+`+transactionsOf+`
+		and `+order.After("t.id", 7, 8)+`
+		order by `+order.By("t.id")+`
+		limit $9
+`, args...)
+	if err != nil {
+		return pagination.Page[models.Transaction]{}, err
+	}
+	defer rows.Close()
+
+	var transactions []models.Transaction
 	for rows.Next() {
 		var tx models.Transaction
 		var returnedAt sql.Null[time.Time]
-		err = rows.Scan(&tx.ID, &tx.IssuedAt, &returnedAt, &tx.IssuedTill, &tx.Book.ID, &tx.Book.Title, &tx.User.Email)
+		var issuedBy, returnedBy sql.NullString
+		err = rows.Scan(&tx.ID, &tx.IssuedAt, &returnedAt, &tx.IssuedTill, &tx.Book.ID, &tx.Copy.ID, &tx.Book.Title, &tx.User.Email, &issuedBy, &returnedBy)
 		if err != nil {
-			return nil, err
+			return pagination.Page[models.Transaction]{}, err
 		}
 
 		if returnedAt.Valid {
 			tx.ReturnedAt = &returnedAt.V
 		}
+		if issuedBy.Valid {
+			tx.IssuedBy = &models.User{Email: issuedBy.String}
+		}
+		if returnedBy.Valid {
+			tx.ReturnedBy = &models.User{Email: returnedBy.String}
+		}
 
 		transactions = append(transactions, tx)
 	}
+	if err := rows.Err(); err != nil {
+		return pagination.Page[models.Transaction]{}, err
+	}
 
-	return transactions, nil
+	return order.Page(transactions, transactionId, total), nil
 }
 
-func (repo *TransactionRepository) GetOverDueTransactions(userId string) ([]models.Transaction, error) {
-	var transactions []models.Transaction
+func (repo *TransactionRepository) GetOverDueTransactions(userId string, page pagination.Request) (pagination.Page[models.Transaction], error) {
+	order, err := pagination.NewOrder(page, transactionSorts, "issued_till")
+	if err != nil {
+		return pagination.Page[models.Transaction]{}, err
+	}
+
+	var total int
+	err = repo.db.QueryRow(`select count(*)`+overdueOf, userId).Scan(&total)
+	if err != nil {
+		return pagination.Page[models.Transaction]{}, err
+	}
 
+	args := append(append([]any{userId}, order.AfterArgs()...), order.Fetch())
 	rows, err := repo.db.Query(`
-	select t.id, b.id, b.title, t.issued_at, t.issued_till, t.returned_at from transactions as t
-	left join books as b
-	on t.book_id = b.id
-	where ($1='' or t.user_id = cast($1 as uuid) )
-	and t.issued_till < now()
-	and (t.returned_at is null or t.returned_at>t.issued_till)
-`, userId)
+	select t.id, b.id, c.id, b.title, t.issued_at, t.issued_till, t.returned_at
+`+overdueOf+`
+	and `+order.After("t.id", 2, 3)+`
+	order by `+order.By("t.id")+`
+	limit $4
+`, args...)
 	if err != nil {
-		return nil, err
+		return pagination.Page[models.Transaction]{}, err
 	}
+	defer rows.Close()
 
+	var transactions []models.Transaction
 	for rows.Next() {
 		var tx models.Transaction
 		var returnedAt sql.Null[time.Time]
-		err = rows.Scan(&tx.ID, &tx.Book.ID, &tx.Book.Title, &tx.IssuedAt, &tx.IssuedTill, &returnedAt)
+		err = rows.Scan(&tx.ID, &tx.Book.ID, &tx.Copy.ID, &tx.Book.Title, &tx.IssuedAt, &tx.IssuedTill, &returnedAt)
 		if err != nil {
-			return nil, err
+			return pagination.Page[models.Transaction]{}, err
 		}
 
 		if returnedAt.Valid {
 			tx.ReturnedAt = &returnedAt.V
-		} else {
-			tx.ReturnedAt = nil
 		}
 
 		transactions = append(transactions, tx)
 	}
+	if err := rows.Err(); err != nil {
+		return pagination.Page[models.Transaction]{}, err
+	}
 
-	return transactions, nil
+	return order.Page(transactions, transactionId, total), nil
+}
+
+func (repo *TransactionRepository) GetTransactionById(transactionId string) (models.Transaction, error) {
+	var tx models.Transaction
+	var returnedAt sql.Null[time.Time]
+
+	err := repo.db.QueryRow(`
+	select t.id, c.title_id, b.item_type, t.copy_id, t.user_id, t.issued_at, t.issued_till, t.returned_at,
+	       (select count(*) from renewals as r where r.transaction_id = t.id)
+	from transactions as t
+	join copies as c on t.copy_id = c.id
+	join titles as b on c.title_id = b.id
+	where t.id = $1
+`, transactionId).Scan(&tx.ID, &tx.Book.ID, &tx.Book.ItemType, &tx.Copy.ID, &tx.User.ID, &tx.IssuedAt, &tx.IssuedTill, &returnedAt, &tx.Renewals)
+	if errors.Is(err, sql.ErrNoRows) {
+		return tx, apperrors.NotFound("transaction_not_found", "transaction not found")
+	}
+	if err != nil {
+		return tx, apperrors.FromPostgres(err)
+	}
+
+	if returnedAt.Valid {
+		tx.ReturnedAt = &returnedAt.V
+	}
+
+	return tx, nil
+}
+
+// CountOpenLoans counts the loans userId hasn't returned yet, only those of
+// titles of itemType unless it is empty.
+func (repo *TransactionRepository) CountOpenLoans(userId, itemType string) (int, error) {
+	var count int
+	err := repo.db.QueryRow(`
+	select count(*)
+	from transactions as t
+	join copies as c on t.copy_id = c.id
+	join titles as b on c.title_id = b.id
+	where t.user_id = $1 and t.returned_at is null
+	and ($2='' or b.item_type = $2)
+`, userId, itemType).Scan(&count)
+	return count, apperrors.FromPostgres(err)
+}
+
+// RenewBook pushes the due date of an open, not yet overdue loan back by
+// extendBy and records the renewal. The renewal limit is checked in the same
+// statement so concurrent renewals can't overshoot it.
+func (repo *TransactionRepository) RenewBook(transactionId, extendBy string, maxRenewals int) (time.Time, error) {
+	var renewedTill time.Time
+	err := repo.db.QueryRow(`
+		with renewed as (
+		    update transactions set issued_till = issued_till + cast($2 as interval)
+		    where id = $1
+		    and returned_at is null
+		    and issued_till >= now()
+		    and (select count(*) from renewals where transaction_id = $1) < $3
+		    returning id, issued_till, issued_till - cast($2 as interval) as previous_till
+		)
+		insert into renewals (transaction_id, previous_till, renewed_till)
+		select id, previous_till, issued_till from renewed
+		returning renewed_till
+`, transactionId, extendBy, maxRenewals).Scan(&renewedTill)
+	if errors.Is(err, sql.ErrNoRows) {
+		return renewedTill, apperrors.Conflict("renewal_not_allowed", "loan cannot be renewed")
+	}
+	return renewedTill, apperrors.FromPostgres(err)
 }
@@ -8,8 +8,11 @@ import (
 	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
+	apperrors "github.com/Kaushik1766/LibraryManagement/internal/app_errors"
 	"github.com/Kaushik1766/LibraryManagement/internal/models"
+	"github.com/Kaushik1766/LibraryManagement/internal/pagination"
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 )
 
 func TestNewTransactionRepository(t *testing.T) {
@@ -68,6 +71,8 @@ func TestTransactionRepository_GetAllTransactions(t *testing.T) {
 		IssuedAt:   time.Now(),
 		IssuedTill: time.Now().AddDate(0, 0, 1),
 		ReturnedAt: &now,
+		IssuedBy:   &models.User{Email: "librarian@a.com"},
+		ReturnedBy: &models.User{Email: "staff@a.com"},
 	}
 	type fields struct {
 		db *sql.DB
@@ -101,7 +106,8 @@ func TestTransactionRepository_GetAllTransactions(t *testing.T) {
 			want:    []models.Transaction{transaction1},
 			wantErr: false,
 			mockSetup: func() {
-				mock.ExpectQuery("(?i)select .* from transactions .* left join users .* left join books .*").WillReturnRows(sqlmock.NewRows([]string{"id", "issuedAt", "returnedAt", "issuedTill", "bookId", "title", "email"}).AddRow(transaction1.ID, transaction1.IssuedAt, transaction1.ReturnedAt, transaction1.IssuedTill, transaction1.Book.ID, transaction1.Book.Title, transaction1.User.Email))
+				mock.ExpectQuery("(?i)select count\\(\\*\\) from transactions").WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+				mock.ExpectQuery("(?i)select .* from transactions .* left join users .* left join copies .* left join titles .*").WillReturnRows(sqlmock.NewRows([]string{"id", "issuedAt", "returnedAt", "issuedTill", "bookId", "copyId", "title", "email", "issuedBy", "returnedBy"}).AddRow(transaction1.ID, transaction1.IssuedAt, transaction1.ReturnedAt, transaction1.IssuedTill, transaction1.Book.ID, transaction1.Copy.ID, transaction1.Book.Title, transaction1.User.Email, nil, nil))
 			},
 		},
 		{
@@ -122,7 +128,8 @@ func TestTransactionRepository_GetAllTransactions(t *testing.T) {
 			want:    nil,
 			wantErr: true,
 			mockSetup: func() {
-				mock.ExpectQuery("(?i)select .* from transactions .* left join users .* left join books .*").WillReturnError(errors.New("invalid query"))
+				mock.ExpectQuery("(?i)select count\\(\\*\\) from transactions").WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+				mock.ExpectQuery("(?i)select .* from transactions .* left join users .* left join copies .* left join titles .*").WillReturnError(errors.New("invalid query"))
 			},
 		},
 		{
@@ -143,7 +150,8 @@ func TestTransactionRepository_GetAllTransactions(t *testing.T) {
 			want:    nil,
 			wantErr: true,
 			mockSetup: func() {
-				mock.ExpectQuery("(?i)select .* from transactions .* left join users .* left join books .*").WillReturnRows(sqlmock.NewRows([]string{"id", "issuedAt", "returnedAt", "issuedTill", "bookId", "title", "email"}).AddRow("dd", transaction2.IssuedAt, transaction2.ReturnedAt, transaction2.IssuedTill, transaction2.Book.ID, transaction2.Book.Title, transaction2.User.Email))
+				mock.ExpectQuery("(?i)select count\\(\\*\\) from transactions").WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+				mock.ExpectQuery("(?i)select .* from transactions .* left join users .* left join copies .* left join titles .*").WillReturnRows(sqlmock.NewRows([]string{"id", "issuedAt", "returnedAt", "issuedTill", "bookId", "copyId", "title", "email", "issuedBy", "returnedBy"}).AddRow("dd", transaction2.IssuedAt, transaction2.ReturnedAt, transaction2.IssuedTill, transaction2.Book.ID, transaction2.Copy.ID, transaction2.Book.Title, transaction2.User.Email, nil, nil))
 			},
 		},
 		{
@@ -164,7 +172,8 @@ func TestTransactionRepository_GetAllTransactions(t *testing.T) {
 			want:    []models.Transaction{transaction2},
 			wantErr: false,
 			mockSetup: func() {
-				mock.ExpectQuery("(?i)select .* from transactions .* left join users .* left join books .*").WillReturnRows(sqlmock.NewRows([]string{"id", "issuedAt", "returnedAt", "issuedTill", "bookId", "title", "email"}).AddRow(transaction2.ID, transaction2.IssuedAt, transaction2.ReturnedAt, transaction2.IssuedTill, transaction2.Book.ID, transaction2.Book.Title, transaction2.User.Email))
+				mock.ExpectQuery("(?i)select count\\(\\*\\) from transactions").WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+				mock.ExpectQuery("(?i)select .* from transactions .* left join users .* left join copies .* left join titles .*").WillReturnRows(sqlmock.NewRows([]string{"id", "issuedAt", "returnedAt", "issuedTill", "bookId", "copyId", "title", "email", "issuedBy", "returnedBy"}).AddRow(transaction2.ID, transaction2.IssuedAt, transaction2.ReturnedAt, transaction2.IssuedTill, transaction2.Book.ID, transaction2.Copy.ID, transaction2.Book.Title, transaction2.User.Email, transaction2.IssuedBy.Email, transaction2.ReturnedBy.Email))
 			},
 		},
 	}
@@ -174,13 +183,19 @@ func TestTransactionRepository_GetAllTransactions(t *testing.T) {
 				db: tt.fields.db,
 			}
 			tt.mockSetup()
-			got, err := repo.GetAllTransactions(tt.args.dto)
+			got, err := repo.GetAllTransactions(tt.args.dto, pagination.Request{})
 			if (err != nil) != tt.wantErr {
 				t.Errorf("GetAllTransactions() error = %v, wantErr %v", err, tt.wantErr)
 				return
 			}
-			if !reflect.DeepEqual(got, tt.want) {
-				t.Errorf("GetAllTransactions() got = %v, want %v", got, tt.want)
+			if tt.wantErr {
+				return
+			}
+			if !reflect.DeepEqual(got.Items, tt.want) {
+				t.Errorf("GetAllTransactions() got = %v, want %v", got.Items, tt.want)
+			}
+			if got.Total != len(tt.want) {
+				t.Errorf("GetAllTransactions() total = %v, want %v", got.Total, len(tt.want))
 			}
 		})
 	}
@@ -213,6 +228,7 @@ func TestTransactionRepository_GetOverDueTransactions(t *testing.T) {
 	}
 	type args struct {
 		userId string
+		page   pagination.Request
 	}
 	tests := []struct {
 		name      string
@@ -233,7 +249,8 @@ func TestTransactionRepository_GetOverDueTransactions(t *testing.T) {
 			want:    []models.Transaction{transaction1},
 			wantErr: false,
 			mockSetup: func() {
-				mock.ExpectQuery("(?i)select .* from transactions .* left join books .*").WillReturnRows(sqlmock.NewRows([]string{"id", "bookId", "title", "issued_at", "issued_till", "returned_at"}).AddRow(transaction1.ID, transaction1.Book.ID, transaction1.Book.Title, transaction1.IssuedAt, transaction1.IssuedTill, transaction1.ReturnedAt))
+				mock.ExpectQuery("(?i)select count\\(\\*\\) from transactions").WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+				mock.ExpectQuery("(?i)select .* from transactions .* left join copies .* left join titles .*").WillReturnRows(sqlmock.NewRows([]string{"id", "bookId", "copyId", "title", "issued_at", "issued_till", "returned_at"}).AddRow(transaction1.ID, transaction1.Book.ID, transaction1.Copy.ID, transaction1.Book.Title, transaction1.IssuedAt, transaction1.IssuedTill, transaction1.ReturnedAt))
 			},
 		},
 		{
@@ -247,7 +264,8 @@ func TestTransactionRepository_GetOverDueTransactions(t *testing.T) {
 			want:    nil,
 			wantErr: true,
 			mockSetup: func() {
-				mock.ExpectQuery("(?i)select .* from transactions .* left join books .*").WillReturnError(errors.New("invalid query"))
+				mock.ExpectQuery("(?i)select count\\(\\*\\) from transactions").WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+				mock.ExpectQuery("(?i)select .* from transactions .* left join copies .* left join titles .*").WillReturnError(errors.New("invalid query"))
 			},
 		},
 		{
@@ -261,7 +279,8 @@ func TestTransactionRepository_GetOverDueTransactions(t *testing.T) {
 			want:    []models.Transaction{transaction2},
 			wantErr: false,
 			mockSetup: func() {
-				mock.ExpectQuery("(?i)select .* from transactions .* left join books .*").WillReturnRows(sqlmock.NewRows([]string{"id", "bookId", "title", "issued_at", "issued_till", "returned_at"}).AddRow(transaction2.ID, transaction2.Book.ID, transaction2.Book.Title, transaction2.IssuedAt, transaction2.IssuedTill, transaction2.ReturnedAt))
+				mock.ExpectQuery("(?i)select count\\(\\*\\) from transactions").WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+				mock.ExpectQuery("(?i)select .* from transactions .* left join copies .* left join titles .*").WillReturnRows(sqlmock.NewRows([]string{"id", "bookId", "copyId", "title", "issued_at", "issued_till", "returned_at"}).AddRow(transaction2.ID, transaction2.Book.ID, transaction2.Copy.ID, transaction2.Book.Title, transaction2.IssuedAt, transaction2.IssuedTill, transaction2.ReturnedAt))
 			},
 		},
 		{
@@ -275,9 +294,40 @@ func TestTransactionRepository_GetOverDueTransactions(t *testing.T) {
 			want:    []models.Transaction{transaction1},
 			wantErr: false,
 			mockSetup: func() {
-				mock.ExpectQuery("(?i)select .* from transactions .* left join books .*").WillReturnRows(sqlmock.NewRows([]string{"id", "bookId", "title", "issued_at", "issued_till", "returned_at"}).AddRow(transaction1.ID, transaction1.Book.ID, transaction1.Book.Title, transaction1.IssuedAt, transaction1.IssuedTill, transaction1.ReturnedAt))
+				mock.ExpectQuery("(?i)select count\\(\\*\\) from transactions").WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+				mock.ExpectQuery("(?i)select .* from transactions .* left join copies .* left join titles .*").WillReturnRows(sqlmock.NewRows([]string{"id", "bookId", "copyId", "title", "issued_at", "issued_till", "returned_at"}).AddRow(transaction1.ID, transaction1.Book.ID, transaction1.Copy.ID, transaction1.Book.Title, transaction1.IssuedAt, transaction1.IssuedTill, transaction1.ReturnedAt))
+			},
+		},
+		{
+			name: "more overdue loans than the limit",
+			fields: fields{
+				db: db,
+			},
+			args: args{
+				userId: "",
+				page:   pagination.Request{Limit: 1},
+			},
+			want:    []models.Transaction{transaction1},
+			wantErr: false,
+			mockSetup: func() {
+				mock.ExpectQuery("(?i)select count\\(\\*\\) from transactions").WithArgs("").WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+				mock.ExpectQuery("(?i)select .* from transactions .* order by t.issued_till asc, t.id asc limit").WithArgs("", "", "", 2).WillReturnRows(sqlmock.NewRows([]string{"id", "bookId", "copyId", "title", "issued_at", "issued_till", "returned_at"}).
+					AddRow(transaction1.ID, transaction1.Book.ID, transaction1.Copy.ID, transaction1.Book.Title, transaction1.IssuedAt, transaction1.IssuedTill, transaction1.ReturnedAt).
+					AddRow(transaction2.ID, transaction2.Book.ID, transaction2.Copy.ID, transaction2.Book.Title, transaction2.IssuedAt, transaction2.IssuedTill, transaction2.ReturnedAt))
 			},
 		},
+		{
+			name: "invalid cursor",
+			fields: fields{
+				db: db,
+			},
+			args: args{
+				page: pagination.Request{Cursor: "not-a-cursor"},
+			},
+			want:      nil,
+			wantErr:   true,
+			mockSetup: func() {},
+		},
 		{
 			name: "scan error",
 			fields: fields{
@@ -289,7 +339,8 @@ func TestTransactionRepository_GetOverDueTransactions(t *testing.T) {
 			want:    nil,
 			wantErr: true,
 			mockSetup: func() {
-				mock.ExpectQuery("(?i)select .* from transactions .* left join books .*").WillReturnRows(sqlmock.NewRows([]string{"id", "bookId", "title", "issued_at", "issued_till", "returned_at"}).AddRow("invalid-uuid", transaction1.Book.ID, transaction1.Book.Title, transaction1.IssuedAt, transaction1.IssuedTill, transaction1.ReturnedAt))
+				mock.ExpectQuery("(?i)select count\\(\\*\\) from transactions").WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+				mock.ExpectQuery("(?i)select .* from transactions .* left join copies .* left join titles .*").WillReturnRows(sqlmock.NewRows([]string{"id", "bookId", "copyId", "title", "issued_at", "issued_till", "returned_at"}).AddRow("invalid-uuid", transaction1.Book.ID, transaction1.Copy.ID, transaction1.Book.Title, transaction1.IssuedAt, transaction1.IssuedTill, transaction1.ReturnedAt))
 			},
 		},
 	}
@@ -299,13 +350,19 @@ func TestTransactionRepository_GetOverDueTransactions(t *testing.T) {
 				db: tt.fields.db,
 			}
 			tt.mockSetup()
-			got, err := repo.GetOverDueTransactions(tt.args.userId)
+			got, err := repo.GetOverDueTransactions(tt.args.userId, tt.args.page)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("GetOverDueTransactions() error = %v, wantErr %v", err, tt.wantErr)
 				return
 			}
-			if !reflect.DeepEqual(got, tt.want) {
-				t.Errorf("GetOverDueTransactions() got = %v, want %v", got, tt.want)
+			if tt.wantErr {
+				return
+			}
+			if !reflect.DeepEqual(got.Items, tt.want) {
+				t.Errorf("GetOverDueTransactions() got = %v, want %v", got.Items, tt.want)
+			}
+			if got.Total != len(tt.want) {
+				t.Errorf("GetOverDueTransactions() total = %v, want %v", got.Total, len(tt.want))
 			}
 		})
 	}
@@ -318,15 +375,15 @@ func TestTransactionRepository_IssueBook(t *testing.T) {
 	transactionId := uuid.New().String()
 	bookId := uuid.New().String()
 	userId := uuid.New().String()
-	issueFor := "7 days"
+	terms := models.LoanTerms{Period: "7 days", FinePerDay: 50, FineCap: 2000}
 
 	type fields struct {
 		db *sql.DB
 	}
 	type args struct {
-		bookId   string
-		userId   string
-		issueFor string
+		bookId string
+		userId string
+		terms  models.LoanTerms
 	}
 	tests := []struct {
 		name      string
@@ -342,14 +399,16 @@ func TestTransactionRepository_IssueBook(t *testing.T) {
 				db: db,
 			},
 			args: args{
-				bookId:   bookId,
-				userId:   userId,
-				issueFor: issueFor,
+				bookId: bookId,
+				userId: userId,
+				terms:  terms,
 			},
 			want:    transactionId,
 			wantErr: false,
 			mockSetup: func() {
-				mock.ExpectQuery("(?i)insert into transactions .*").WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(transactionId))
+				mock.ExpectQuery("(?i)insert into transactions .*").
+					WithArgs(bookId, userId, "7 days", 50, 2000).
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(transactionId))
 			},
 		},
 		{
@@ -358,9 +417,9 @@ func TestTransactionRepository_IssueBook(t *testing.T) {
 				db: db,
 			},
 			args: args{
-				bookId:   bookId,
-				userId:   userId,
-				issueFor: issueFor,
+				bookId: bookId,
+				userId: userId,
+				terms:  terms,
 			},
 			want:    "",
 			wantErr: true,
@@ -374,9 +433,9 @@ func TestTransactionRepository_IssueBook(t *testing.T) {
 				db: db,
 			},
 			args: args{
-				bookId:   bookId,
-				userId:   userId,
-				issueFor: issueFor,
+				bookId: bookId,
+				userId: userId,
+				terms:  terms,
 			},
 			want:    "",
 			wantErr: true,
@@ -391,7 +450,7 @@ func TestTransactionRepository_IssueBook(t *testing.T) {
 				db: tt.fields.db,
 			}
 			tt.mockSetup()
-			got, err := repo.IssueBook(tt.args.bookId, tt.args.userId, tt.args.issueFor)
+			got, err := repo.IssueBook(tt.args.bookId, tt.args.userId, tt.args.terms)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("IssueBook() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -409,6 +468,11 @@ func TestTransactionRepository_ReturnBook(t *testing.T) {
 
 	bookId := uuid.New().String()
 	userId := uuid.New().String()
+	transaction := models.Transaction{
+		ID:   uuid.New(),
+		Book: models.Book{ID: uuid.MustParse(bookId)},
+		Copy: models.Copy{ID: uuid.New()},
+	}
 
 	type fields struct {
 		db *sql.DB
@@ -421,6 +485,7 @@ func TestTransactionRepository_ReturnBook(t *testing.T) {
 		name      string
 		fields    fields
 		args      args
+		want      models.Transaction
 		wantErr   bool
 		mockSetup func()
 	}{
@@ -433,9 +498,10 @@ func TestTransactionRepository_ReturnBook(t *testing.T) {
 				bookId: bookId,
 				userId: userId,
 			},
+			want:    transaction,
 			wantErr: false,
 			mockSetup: func() {
-				mock.ExpectExec("(?i)update transactions .*").WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectQuery("(?i)update transactions .*").WillReturnRows(sqlmock.NewRows([]string{"id", "title_id", "copy_id"}).AddRow(transaction.ID, transaction.Book.ID, transaction.Copy.ID))
 			},
 		},
 		{
@@ -449,7 +515,7 @@ func TestTransactionRepository_ReturnBook(t *testing.T) {
 			},
 			wantErr: true,
 			mockSetup: func() {
-				mock.ExpectExec("(?i)update transactions .*").WillReturnResult(sqlmock.NewResult(1, 0))
+				mock.ExpectQuery("(?i)update transactions .*").WillReturnError(sql.ErrNoRows)
 			},
 		},
 		{
@@ -463,7 +529,7 @@ func TestTransactionRepository_ReturnBook(t *testing.T) {
 			},
 			wantErr: true,
 			mockSetup: func() {
-				mock.ExpectExec("(?i)update transactions .*").WillReturnError(errors.New("database error"))
+				mock.ExpectQuery("(?i)update transactions .*").WillReturnError(errors.New("database error"))
 			},
 		},
 	}
@@ -473,9 +539,347 @@ func TestTransactionRepository_ReturnBook(t *testing.T) {
 				db: tt.fields.db,
 			}
 			tt.mockSetup()
-			if err := repo.ReturnBook(tt.args.bookId, tt.args.userId); (err != nil) != tt.wantErr {
+			got, err := repo.ReturnBook(tt.args.bookId, tt.args.userId)
+			if (err != nil) != tt.wantErr {
 				t.Errorf("ReturnBook() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ReturnBook() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTransactionRepository_IssueCopy(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	copyId := uuid.New().String()
+	userId := uuid.New().String()
+	staffId := uuid.New().String()
+	transaction := models.Transaction{
+		ID:   uuid.New(),
+		Book: models.Book{ID: uuid.New()},
+		Copy: models.Copy{ID: uuid.MustParse(copyId)},
+	}
+	terms := models.LoanTerms{Period: "7 days", FinePerDay: 100, FineCap: 500}
+
+	tests := []struct {
+		name      string
+		want      models.Transaction
+		wantErr   bool
+		mockSetup func()
+	}{
+		{
+			name:    "valid issue copy",
+			want:    transaction,
+			wantErr: false,
+			mockSetup: func() {
+				mock.ExpectQuery("(?i)with issued as .*insert into transactions .*").
+					WithArgs(copyId, userId, staffId, "7 days", 100, 500).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "title_id", "copy_id"}).AddRow(transaction.ID, transaction.Book.ID, transaction.Copy.ID))
+			},
+		},
+		{
+			name:    "copy not available",
+			wantErr: true,
+			mockSetup: func() {
+				mock.ExpectQuery("(?i)with issued as .*insert into transactions .*").WillReturnError(sql.ErrNoRows)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &TransactionRepository{
+				db: db,
+			}
+			tt.mockSetup()
+			got, err := repo.IssueCopy(copyId, userId, staffId, terms)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("IssueCopy() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("IssueCopy() got = %v, want %v", got, tt.want)
 			}
 		})
 	}
 }
+
+func TestTransactionRepository_ReturnCopy(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	copyId := uuid.New().String()
+	userId := uuid.New().String()
+	staffId := uuid.New().String()
+	transaction := models.Transaction{
+		ID:   uuid.New(),
+		Book: models.Book{ID: uuid.New()},
+		Copy: models.Copy{ID: uuid.MustParse(copyId)},
+		User: models.User{ID: uuid.MustParse(userId)},
+	}
+
+	tests := []struct {
+		name      string
+		userId    string
+		want      models.Transaction
+		wantErr   bool
+		mockSetup func()
+	}{
+		{
+			name:    "return from any borrower",
+			userId:  "",
+			want:    transaction,
+			wantErr: false,
+			mockSetup: func() {
+				mock.ExpectQuery("(?i)update transactions .*").
+					WithArgs(sqlmock.AnyArg(), copyId, "", staffId).
+					WillReturnRows(sqlmock.NewRows([]string{"id", "title_id", "copy_id", "user_id"}).AddRow(transaction.ID, transaction.Book.ID, transaction.Copy.ID, transaction.User.ID))
+			},
+		},
+		{
+			name:    "copy not on loan",
+			userId:  userId,
+			wantErr: true,
+			mockSetup: func() {
+				mock.ExpectQuery("(?i)update transactions .*").WillReturnError(sql.ErrNoRows)
+			},
+		},
+		{
+			name:    "database error",
+			userId:  userId,
+			wantErr: true,
+			mockSetup: func() {
+				mock.ExpectQuery("(?i)update transactions .*").WillReturnError(errors.New("database error"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &TransactionRepository{
+				db: db,
+			}
+			tt.mockSetup()
+			got, err := repo.ReturnCopy(copyId, tt.userId, staffId)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ReturnCopy() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ReturnCopy() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTransactionRepository_GetTransactionById(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	now := time.Now()
+	transaction := models.Transaction{
+		ID:         uuid.New(),
+		Book:       models.Book{ID: uuid.New(), ItemType: "dvd"},
+		Copy:       models.Copy{ID: uuid.New()},
+		User:       models.User{ID: uuid.New()},
+		IssuedAt:   now,
+		IssuedTill: now.AddDate(0, 0, 1),
+		ReturnedAt: &now,
+		Renewals:   1,
+	}
+	columns := []string{"id", "title_id", "item_type", "copy_id", "user_id", "issued_at", "issued_till", "returned_at", "renewals"}
+
+	tests := []struct {
+		name      string
+		want      models.Transaction
+		wantErr   bool
+		wantKind  apperrors.Kind
+		mockSetup func()
+	}{
+		{
+			name:    "valid get transaction",
+			want:    transaction,
+			wantErr: false,
+			mockSetup: func() {
+				mock.ExpectQuery("(?i)select .* from transactions as t join copies as c .* where t.id = .*").
+					WithArgs(transaction.ID.String()).
+					WillReturnRows(sqlmock.NewRows(columns).AddRow(transaction.ID, transaction.Book.ID, "dvd", transaction.Copy.ID, transaction.User.ID, now, transaction.IssuedTill, now, 1))
+			},
+		},
+		{
+			name:     "transaction not found",
+			want:     models.Transaction{},
+			wantErr:  true,
+			wantKind: apperrors.KindNotFound,
+			mockSetup: func() {
+				mock.ExpectQuery("(?i)select .* from transactions as t join copies as c .* where t.id = .*").
+					WithArgs(transaction.ID.String()).
+					WillReturnError(sql.ErrNoRows)
+			},
+		},
+		{
+			name:     "malformed id",
+			want:     models.Transaction{},
+			wantErr:  true,
+			wantKind: apperrors.KindValidation,
+			mockSetup: func() {
+				mock.ExpectQuery("(?i)select .* from transactions as t join copies as c .* where t.id = .*").
+					WithArgs(transaction.ID.String()).
+					WillReturnError(&pq.Error{Code: "22P02"})
+			},
+		},
+		{
+			name:     "database error",
+			want:     models.Transaction{},
+			wantErr:  true,
+			wantKind: apperrors.KindInternal,
+			mockSetup: func() {
+				mock.ExpectQuery("(?i)select .* from transactions as t join copies as c .* where t.id = .*").
+					WithArgs(transaction.ID.String()).
+					WillReturnError(errors.New("database error"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &TransactionRepository{
+				db: db,
+			}
+			tt.mockSetup()
+			got, err := repo.GetTransactionById(transaction.ID.String())
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetTransactionById() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err != nil && apperrors.KindOf(err) != tt.wantKind {
+				t.Errorf("GetTransactionById() kind = %v, want %v", apperrors.KindOf(err), tt.wantKind)
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetTransactionById() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTransactionRepository_RenewBook(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	transactionId := uuid.New().String()
+	renewedTill := time.Now().AddDate(0, 0, 7)
+
+	tests := []struct {
+		name      string
+		want      time.Time
+		wantErr   bool
+		mockSetup func()
+	}{
+		{
+			name:    "valid renew",
+			want:    renewedTill,
+			wantErr: false,
+			mockSetup: func() {
+				mock.ExpectQuery("(?i)with renewed as \\(\\s*update transactions .* insert into renewals .*").
+					WithArgs(transactionId, "7 days", 2).
+					WillReturnRows(sqlmock.NewRows([]string{"renewed_till"}).AddRow(renewedTill))
+			},
+		},
+		{
+			name:    "loan not renewable",
+			want:    time.Time{},
+			wantErr: true,
+			mockSetup: func() {
+				mock.ExpectQuery("(?i)with renewed as \\(\\s*update transactions .* insert into renewals .*").
+					WithArgs(transactionId, "7 days", 2).
+					WillReturnError(sql.ErrNoRows)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &TransactionRepository{
+				db: db,
+			}
+			tt.mockSetup()
+			got, err := repo.RenewBook(transactionId, "7 days", 2)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("RenewBook() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("RenewBook() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTransactionRepository_CountOpenLoans(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	userId := uuid.New().String()
+
+	tests := []struct {
+		name      string
+		itemType  string
+		want      int
+		wantErr   bool
+		mockSetup func()
+	}{
+		{
+			name:     "every open loan",
+			itemType: "",
+			want:     3,
+			wantErr:  false,
+			mockSetup: func() {
+				mock.ExpectQuery("(?i)select count\\(\\*\\) from transactions as t .* where t.user_id = .* and t.returned_at is null").
+					WithArgs(userId, "").
+					WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(3))
+			},
+		},
+		{
+			name:     "open loans of one item type",
+			itemType: "dvd",
+			want:     1,
+			wantErr:  false,
+			mockSetup: func() {
+				mock.ExpectQuery("(?i)select count\\(\\*\\) from transactions as t .* b.item_type = .*").
+					WithArgs(userId, "dvd").
+					WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+			},
+		},
+		{
+			name:     "database error",
+			itemType: "",
+			want:     0,
+			wantErr:  true,
+			mockSetup: func() {
+				mock.ExpectQuery("(?i)select count\\(\\*\\) from transactions as t .*").
+					WithArgs(userId, "").
+					WillReturnError(errors.New("database error"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &TransactionRepository{
+				db: db,
+			}
+			tt.mockSetup()
+			got, err := repo.CountOpenLoans(userId, tt.itemType)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("CountOpenLoans() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("CountOpenLoans() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if err := mock.ExpectationsWereMet(); err != nil {
+		t.Errorf("there were unfulfilled expectations: %s", err)
+	}
+}
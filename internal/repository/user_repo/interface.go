@@ -1,9 +1,27 @@
 package userrepo
 
-import "github.com/Kaushik1766/LibraryManagement/internal/models"
+import (
+	"time"
+
+	"github.com/Kaushik1766/LibraryManagement/internal/models"
+	"github.com/Kaushik1766/LibraryManagement/internal/models/enums/roles"
+	"github.com/Kaushik1766/LibraryManagement/internal/pagination"
+)
 
 //go:generate mockgen -source=interface.go -destination=../../../mocks/mock_user_storage.go -package=mocks
 type UserStorage interface {
-	AddUser(name, email, password string) error
+	AddUser(name, email, password string) (string, error)
 	GetUserByEmail(email string) (models.User, error)
+	GetUserById(userId string) (models.User, error)
+	GetUserByCardNumber(cardNumber string) (models.User, error)
+	GetUsers(filter models.UserFilter, page pagination.Request) (pagination.Page[models.User], error)
+	UpdateUser(user models.User) error
+	SetPassword(userId, password string) error
+	SetRole(userId string, role roles.UserRoles) error
+	SetActive(userId string, active bool) error
+	MarkEmailVerified(userId, email string) error
+	ClaimVerificationEmail(userId string, interval time.Duration) (bool, error)
+	RecordLoginFailure(userId string) (int, error)
+	LockUntil(userId string, until time.Time) error
+	ClearLoginFailures(userId string) error
 }
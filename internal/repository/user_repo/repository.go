@@ -2,10 +2,34 @@ package userrepo
 
 import (
 	"database/sql"
+	"errors"
+	"time"
 
+	apperrors "github.com/Kaushik1766/LibraryManagement/internal/app_errors"
 	"github.com/Kaushik1766/LibraryManagement/internal/models"
+	"github.com/Kaushik1766/LibraryManagement/internal/models/enums/roles"
+	"github.com/Kaushik1766/LibraryManagement/internal/pagination"
 )
 
+const selectUsers = `select id, name, email, password, role, card_number, membership_expires_at, deactivated_at, email_verified_at, failed_login_count, locked_until, totp_secret, mfa_enabled_at, created_at from users`
+
+// userFilter narrows users by $1 query, $2 role and $3 active, all optional.
+const userFilter = `
+	where ($1='' or name ilike '%'||$1||'%' or email ilike '%'||$1||'%' or card_number = $1)
+	and (cast($2 as int) is null or role = $2)
+	and (cast($3 as boolean) is null or (deactivated_at is null) = $3)
+`
+
+var userSorts = map[string]pagination.Column[models.User]{
+	"name":  {Expr: "name", Type: "text", Key: func(u models.User) string { return u.Name }},
+	"email": {Expr: "email", Type: "text", Key: func(u models.User) string { return u.Email }},
+	"created_at": {
+		Expr: "created_at",
+		Type: "timestamp",
+		Key:  func(u models.User) string { return u.CreatedAt.Format(time.RFC3339Nano) },
+	},
+}
+
 type UserRepository struct {
 	db *sql.DB
 }
@@ -16,16 +40,252 @@ func NewUserRepository(db *sql.DB) *UserRepository {
 	}
 }
 
-func (u UserRepository) AddUser(name, email, password string) error {
-	_, err := u.db.Exec(`insert into users(name, email, password) values($1,$2,$3)`, name, email, password)
-	return err
+// AddUser registers an account with an unverified email and returns its id.
+func (u UserRepository) AddUser(name, email, password string) (string, error) {
+	var id string
+	err := u.db.QueryRow(`insert into users(name, email, password) values($1,$2,$3) returning id`, name, email, password).Scan(&id)
+	if apperrors.KindOf(apperrors.FromPostgres(err)) == apperrors.KindConflict {
+		return "", apperrors.Conflict("email_taken", "email already registered").WithDetail("email", "already registered")
+	}
+	return id, apperrors.FromPostgres(err)
 }
 
 func (u UserRepository) GetUserByEmail(email string) (models.User, error) {
-	row := u.db.QueryRow(`select * from users where email = $1`, email)
+	row := u.db.QueryRow(selectUsers+` where email = $1`, email)
+	return scanUser(row)
+}
+
+func (u UserRepository) GetUserById(userId string) (models.User, error) {
+	row := u.db.QueryRow(selectUsers+` where id = $1`, userId)
+	return scanUser(row)
+}
+
+func (u UserRepository) GetUserByCardNumber(cardNumber string) (models.User, error) {
+	row := u.db.QueryRow(selectUsers+` where card_number = $1`, cardNumber)
+	return scanUser(row)
+}
+
+func (u UserRepository) GetUsers(filter models.UserFilter, page pagination.Request) (pagination.Page[models.User], error) {
+	order, err := pagination.NewOrder(page, userSorts, "name")
+	if err != nil {
+		return pagination.Page[models.User]{}, err
+	}
+
+	var total int
+	err = u.db.QueryRow(`select count(*) from users`+userFilter, filter.Query, filter.Role, filter.Active).Scan(&total)
+	if err != nil {
+		return pagination.Page[models.User]{}, err
+	}
+
+	args := append([]any{filter.Query, filter.Role, filter.Active}, order.AfterArgs()...)
+	rows, err := u.db.Query(selectUsers+userFilter+`
+	and `+order.After("id", 4, 5)+`
+	order by `+order.By("id")+`
+	limit $6
+`, append(args, order.Fetch())...)
+	if err != nil {
+		return pagination.Page[models.User]{}, err
+	}
+	defer rows.Close()
+
+	var users []models.User
+	for rows.Next() {
+		user, err := scanUser(rows)
+		if err != nil {
+			return pagination.Page[models.User]{}, err
+		}
+		users = append(users, user)
+	}
+	if err := rows.Err(); err != nil {
+		return pagination.Page[models.User]{}, err
+	}
+	return order.Page(users, func(u models.User) string { return u.ID.String() }, total), nil
+}
+
+// UpdateUser saves the profile of user. The role, password and whether the
+// account is active have their own methods. A new email has to be verified
+// again.
+func (u UserRepository) UpdateUser(user models.User) error {
+	res, err := u.db.Exec(`
+		update users set name = $2, email = $3, membership_expires_at = $4,
+		    email_verified_at = case when email = $3 then email_verified_at end,
+		    verification_sent_at = case when email = $3 then verification_sent_at end
+		where id = $1
+`, user.ID, user.Name, user.Email, user.MembershipExpiresAt)
+	if apperrors.KindOf(apperrors.FromPostgres(err)) == apperrors.KindConflict {
+		return apperrors.Conflict("email_taken", "email already registered").WithDetail("email", "already registered")
+	}
+	if err != nil {
+		return apperrors.FromPostgres(err)
+	}
+
+	rowsAffected, _ := res.RowsAffected()
+	if rowsAffected == 0 {
+		return apperrors.NotFound("user_not_found", "user not found")
+	}
+	return nil
+}
+
+// SetPassword stores a new password hash for userId.
+func (u UserRepository) SetPassword(userId, password string) error {
+	res, err := u.db.Exec(`update users set password = $2 where id = $1`, userId, password)
+	if err != nil {
+		return apperrors.FromPostgres(err)
+	}
+
+	rowsAffected, _ := res.RowsAffected()
+	if rowsAffected == 0 {
+		return apperrors.NotFound("user_not_found", "user not found")
+	}
+	return nil
+}
+
+func (u UserRepository) SetRole(userId string, role roles.UserRoles) error {
+	res, err := u.db.Exec(`update users set role = $2 where id = $1`, userId, role)
+	if err != nil {
+		return apperrors.FromPostgres(err)
+	}
+
+	rowsAffected, _ := res.RowsAffected()
+	if rowsAffected == 0 {
+		return apperrors.NotFound("user_not_found", "user not found")
+	}
+	return nil
+}
 
+// SetActive deactivates or reactivates userId. Deactivating an account that
+// already is keeps the time it was first deactivated.
+func (u UserRepository) SetActive(userId string, active bool) error {
+	res, err := u.db.Exec(`
+		update users set deactivated_at = case when $2 then null else coalesce(deactivated_at, now()) end
+		where id = $1
+`, userId, active)
+	if err != nil {
+		return apperrors.FromPostgres(err)
+	}
+
+	rowsAffected, _ := res.RowsAffected()
+	if rowsAffected == 0 {
+		return apperrors.NotFound("user_not_found", "user not found")
+	}
+	return nil
+}
+
+// MarkEmailVerified confirms the email of userId, as long as it is still
+// email. Confirming again keeps the first time.
+func (u UserRepository) MarkEmailVerified(userId, email string) error {
+	res, err := u.db.Exec(`
+		update users set email_verified_at = coalesce(email_verified_at, now())
+		where id = $1 and email = $2
+`, userId, email)
+	if err != nil {
+		return apperrors.FromPostgres(err)
+	}
+
+	rowsAffected, _ := res.RowsAffected()
+	if rowsAffected == 0 {
+		return apperrors.NotFound("user_not_found", "user not found")
+	}
+	return nil
+}
+
+// ClaimVerificationEmail records that a verification email is going out to
+// userId and reports false when one already went within interval or the
+// email is verified. Concurrent callers can't both claim.
+func (u UserRepository) ClaimVerificationEmail(userId string, interval time.Duration) (bool, error) {
+	res, err := u.db.Exec(`
+		update users set verification_sent_at = now()
+		where id = $1 and email_verified_at is null
+		and (verification_sent_at is null or verification_sent_at < now() - make_interval(secs => $2))
+`, userId, interval.Seconds())
+	if err != nil {
+		return false, apperrors.FromPostgres(err)
+	}
+
+	rowsAffected, _ := res.RowsAffected()
+	return rowsAffected > 0, nil
+}
+
+// RecordLoginFailure counts a failed login against userId and returns how
+// many there have been in a row.
+func (u UserRepository) RecordLoginFailure(userId string) (int, error) {
+	var failures int
+	err := u.db.QueryRow(`
+		update users set failed_login_count = failed_login_count + 1
+		where id = $1
+		returning failed_login_count
+`, userId).Scan(&failures)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, apperrors.NotFound("user_not_found", "user not found")
+	}
+	return failures, err
+}
+
+// LockUntil stops logins to userId before until. A lock that already runs
+// longer is kept.
+func (u UserRepository) LockUntil(userId string, until time.Time) error {
+	res, err := u.db.Exec(`
+		update users set locked_until = greatest(locked_until, $2)
+		where id = $1
+`, userId, until)
+	if err != nil {
+		return apperrors.FromPostgres(err)
+	}
+
+	rowsAffected, _ := res.RowsAffected()
+	if rowsAffected == 0 {
+		return apperrors.NotFound("user_not_found", "user not found")
+	}
+	return nil
+}
+
+// ClearLoginFailures forgets the failed logins of userId and lifts its lock.
+func (u UserRepository) ClearLoginFailures(userId string) error {
+	res, err := u.db.Exec(`
+		update users set failed_login_count = 0, locked_until = null
+		where id = $1
+`, userId)
+	if err != nil {
+		return apperrors.FromPostgres(err)
+	}
+
+	rowsAffected, _ := res.RowsAffected()
+	if rowsAffected == 0 {
+		return apperrors.NotFound("user_not_found", "user not found")
+	}
+	return nil
+}
+
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+func scanUser(row scanner) (models.User, error) {
 	var user models.User
+	var membershipExpiresAt, deactivatedAt, emailVerifiedAt, lockedUntil, mfaEnabledAt sql.Null[time.Time]
+	var totpSecret sql.Null[string]
 
-	err := row.Scan(&user.ID, &user.Name, &user.Email, &user.Password, &user.Role)
+	err := row.Scan(&user.ID, &user.Name, &user.Email, &user.Password, &user.Role, &user.CardNumber,
+		&membershipExpiresAt, &deactivatedAt, &emailVerifiedAt, &user.FailedLoginCount, &lockedUntil,
+		&totpSecret, &mfaEnabledAt, &user.CreatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return user, apperrors.NotFound("user_not_found", "user not found")
+	}
+	if membershipExpiresAt.Valid {
+		user.MembershipExpiresAt = &membershipExpiresAt.V
+	}
+	if deactivatedAt.Valid {
+		user.DeactivatedAt = &deactivatedAt.V
+	}
+	if emailVerifiedAt.Valid {
+		user.EmailVerifiedAt = &emailVerifiedAt.V
+	}
+	if lockedUntil.Valid {
+		user.LockedUntil = &lockedUntil.V
+	}
+	user.TOTPSecret = totpSecret.V
+	if mfaEnabledAt.Valid {
+		user.MFAEnabledAt = &mfaEnabledAt.V
+	}
 	return user, err
 }
@@ -5,11 +5,14 @@ import (
 	"errors"
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/Kaushik1766/LibraryManagement/internal/models"
 	"github.com/Kaushik1766/LibraryManagement/internal/models/enums/roles"
+	"github.com/Kaushik1766/LibraryManagement/internal/pagination"
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 )
 
 func TestNewUserRepository(t *testing.T) {
@@ -77,7 +80,9 @@ func TestUserRepository_AddUser(t *testing.T) {
 			},
 			wantErr: false,
 			mockSetup: func() {
-				mock.ExpectExec("(?i)insert into users.*").WillReturnResult(sqlmock.NewResult(1, 1))
+				mock.ExpectQuery("(?i)insert into users.* returning id").
+					WithArgs("kaushik", "kaushik@a.com", "123").
+					WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("550e8400-e29b-41d4-a716-446655440000"))
 			},
 		},
 		{
@@ -92,7 +97,7 @@ func TestUserRepository_AddUser(t *testing.T) {
 			},
 			wantErr: true,
 			mockSetup: func() {
-				mock.ExpectExec("(?i)insert into users.*").WillReturnError(errors.New("database error"))
+				mock.ExpectQuery("(?i)insert into users.*").WillReturnError(errors.New("database error"))
 			},
 		},
 	}
@@ -102,8 +107,13 @@ func TestUserRepository_AddUser(t *testing.T) {
 				db: tt.fields.db,
 			}
 			tt.mockSetup()
-			if err := u.AddUser(tt.args.name, tt.args.email, tt.args.password); (err != nil) != tt.wantErr {
+			got, err := u.AddUser(tt.args.name, tt.args.email, tt.args.password)
+			if (err != nil) != tt.wantErr {
 				t.Errorf("UserRepository.AddUser() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && got == "" {
+				t.Errorf("UserRepository.AddUser() returned no id")
 			}
 		})
 	}
@@ -114,11 +124,13 @@ func TestUserRepository_GetUserByEmail(t *testing.T) {
 	defer db.Close()
 
 	user1 := models.User{
-		ID:       uuid.New(),
-		Name:     "kaushik",
-		Email:    "kaushik@a.com",
-		Password: "123",
-		Role:     roles.Customer,
+		ID:         uuid.New(),
+		Name:       "kaushik",
+		Email:      "kaushik@a.com",
+		Password:   "123",
+		Role:       roles.Customer,
+		CardNumber: "LC00000001",
+		CreatedAt:  time.Date(2024, 1, 2, 10, 0, 0, 0, time.UTC),
 	}
 
 	type fields struct {
@@ -146,7 +158,7 @@ func TestUserRepository_GetUserByEmail(t *testing.T) {
 			want:    user1,
 			wantErr: false,
 			mockSetup: func() {
-				mock.ExpectQuery("(?i)select .* from users .*").WillReturnRows(sqlmock.NewRows([]string{"id", "name", "email", "password", "role"}).AddRow(user1.ID, user1.Name, user1.Email, user1.Password, user1.Role))
+				mock.ExpectQuery("(?i)select .* from users .*").WillReturnRows(sqlmock.NewRows([]string{"id", "name", "email", "password", "role", "card_number", "membership_expires_at", "deactivated_at", "email_verified_at", "failed_login_count", "locked_until", "totp_secret", "mfa_enabled_at", "created_at"}).AddRow(user1.ID, user1.Name, user1.Email, user1.Password, user1.Role, user1.CardNumber, nil, nil, nil, 0, nil, nil, nil, user1.CreatedAt))
 			},
 		},
 		{
@@ -188,7 +200,7 @@ func TestUserRepository_GetUserByEmail(t *testing.T) {
 			want:    models.User{},
 			wantErr: true,
 			mockSetup: func() {
-				mock.ExpectQuery("(?i)select .* from users .*").WillReturnRows(sqlmock.NewRows([]string{"id", "name", "email", "password", "role"}).AddRow("invalid-uuid", user1.Name, user1.Email, user1.Password, user1.Role))
+				mock.ExpectQuery("(?i)select .* from users .*").WillReturnRows(sqlmock.NewRows([]string{"id", "name", "email", "password", "role", "card_number", "membership_expires_at", "deactivated_at", "email_verified_at", "failed_login_count", "locked_until", "totp_secret", "mfa_enabled_at", "created_at"}).AddRow("invalid-uuid", user1.Name, user1.Email, user1.Password, user1.Role, user1.CardNumber, nil, nil, nil, 0, nil, nil, nil, user1.CreatedAt))
 			},
 		},
 	}
@@ -209,3 +221,656 @@ func TestUserRepository_GetUserByEmail(t *testing.T) {
 		})
 	}
 }
+
+func TestUserRepository_GetUserById(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	user1 := models.User{
+		ID:         uuid.New(),
+		Name:       "kaushik",
+		Email:      "kaushik@a.com",
+		Password:   "123",
+		Role:       roles.Customer,
+		CardNumber: "LC00000001",
+		CreatedAt:  time.Date(2024, 1, 2, 10, 0, 0, 0, time.UTC),
+	}
+	columns := []string{"id", "name", "email", "password", "role", "card_number", "membership_expires_at", "deactivated_at", "email_verified_at", "failed_login_count", "locked_until", "totp_secret", "mfa_enabled_at", "created_at"}
+
+	tests := []struct {
+		name      string
+		want      models.User
+		wantErr   bool
+		mockSetup func()
+	}{
+		{
+			name:    "valid get user",
+			want:    user1,
+			wantErr: false,
+			mockSetup: func() {
+				mock.ExpectQuery("(?i)select .* from users where id = .*").
+					WithArgs(user1.ID.String()).
+					WillReturnRows(sqlmock.NewRows(columns).AddRow(user1.ID, user1.Name, user1.Email, user1.Password, user1.Role, user1.CardNumber, nil, nil, nil, 0, nil, nil, nil, user1.CreatedAt))
+			},
+		},
+		{
+			name:    "user not found",
+			want:    models.User{},
+			wantErr: true,
+			mockSetup: func() {
+				mock.ExpectQuery("(?i)select .* from users where id = .*").
+					WithArgs(user1.ID.String()).
+					WillReturnError(sql.ErrNoRows)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u := &UserRepository{
+				db: db,
+			}
+			tt.mockSetup()
+			got, err := u.GetUserById(user1.ID.String())
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetUserById() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetUserById() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUserRepository_GetUserByCardNumber(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	user1 := models.User{
+		ID:         uuid.New(),
+		Name:       "kaushik",
+		Email:      "kaushik@a.com",
+		Password:   "123",
+		Role:       roles.Customer,
+		CardNumber: "LC00000001",
+		CreatedAt:  time.Date(2024, 1, 2, 10, 0, 0, 0, time.UTC),
+	}
+	columns := []string{"id", "name", "email", "password", "role", "card_number", "membership_expires_at", "deactivated_at", "email_verified_at", "failed_login_count", "locked_until", "totp_secret", "mfa_enabled_at", "created_at"}
+
+	tests := []struct {
+		name      string
+		want      models.User
+		wantErr   bool
+		mockSetup func()
+	}{
+		{
+			name:    "valid get user",
+			want:    user1,
+			wantErr: false,
+			mockSetup: func() {
+				mock.ExpectQuery("(?i)select .* from users where card_number = .*").
+					WithArgs(user1.CardNumber).
+					WillReturnRows(sqlmock.NewRows(columns).AddRow(user1.ID, user1.Name, user1.Email, user1.Password, user1.Role, user1.CardNumber, nil, nil, nil, 0, nil, nil, nil, user1.CreatedAt))
+			},
+		},
+		{
+			name:    "user not found",
+			want:    models.User{},
+			wantErr: true,
+			mockSetup: func() {
+				mock.ExpectQuery("(?i)select .* from users where card_number = .*").
+					WithArgs(user1.CardNumber).
+					WillReturnError(sql.ErrNoRows)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u := &UserRepository{
+				db: db,
+			}
+			tt.mockSetup()
+			got, err := u.GetUserByCardNumber(user1.CardNumber)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetUserByCardNumber() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetUserByCardNumber() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUserRepository_GetUsers(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	expiresAt := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	user1 := models.User{
+		ID:                  uuid.New(),
+		Name:                "kaushik",
+		Email:               "kaushik@a.com",
+		Password:            "123",
+		Role:                roles.Customer,
+		CardNumber:          "LC00000001",
+		MembershipExpiresAt: &expiresAt,
+		CreatedAt:           time.Date(2024, 1, 2, 10, 0, 0, 0, time.UTC),
+	}
+	user2 := models.User{
+		ID:         uuid.New(),
+		Name:       "kaveri",
+		Email:      "kaveri@a.com",
+		Password:   "456",
+		Role:       roles.Customer,
+		CardNumber: "LC00000002",
+		CreatedAt:  time.Date(2024, 1, 3, 10, 0, 0, 0, time.UTC),
+	}
+	columns := []string{"id", "name", "email", "password", "role", "card_number", "membership_expires_at", "deactivated_at", "email_verified_at", "failed_login_count", "locked_until", "totp_secret", "mfa_enabled_at", "created_at"}
+	customer := roles.Customer
+	active := true
+
+	tests := []struct {
+		name      string
+		filter    models.UserFilter
+		page      pagination.Request
+		want      []models.User
+		wantTotal int
+		wantNext  bool
+		wantErr   bool
+		mockSetup func()
+	}{
+		{
+			name:      "valid get users",
+			filter:    models.UserFilter{Query: "ka", Role: &customer, Active: &active},
+			page:      pagination.Request{Limit: 1},
+			want:      []models.User{user1},
+			wantTotal: 2,
+			wantNext:  true,
+			wantErr:   false,
+			mockSetup: func() {
+				mock.ExpectQuery("(?i)select count\\(\\*\\) from users").
+					WithArgs("ka", int64(roles.Customer), true).
+					WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(2))
+				mock.ExpectQuery("(?i)select .* from users .* order by name asc, id asc").
+					WithArgs("ka", int64(roles.Customer), true, "", "", 2).
+					WillReturnRows(sqlmock.NewRows(columns).
+						AddRow(user1.ID, user1.Name, user1.Email, user1.Password, user1.Role, user1.CardNumber, expiresAt, nil, nil, 0, nil, nil, nil, user1.CreatedAt).
+						AddRow(user2.ID, user2.Name, user2.Email, user2.Password, user2.Role, user2.CardNumber, nil, nil, nil, 0, nil, nil, nil, user2.CreatedAt))
+			},
+		},
+		{
+			name:      "no filter",
+			filter:    models.UserFilter{},
+			page:      pagination.Request{Limit: pagination.DefaultLimit, Sort: "-created_at"},
+			want:      []models.User{user2},
+			wantTotal: 1,
+			wantErr:   false,
+			mockSetup: func() {
+				mock.ExpectQuery("(?i)select count\\(\\*\\) from users").
+					WithArgs("", nil, nil).
+					WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+				mock.ExpectQuery("(?i)select .* from users .* order by created_at desc, id desc").
+					WithArgs("", nil, nil, "", "", pagination.DefaultLimit+1).
+					WillReturnRows(sqlmock.NewRows(columns).
+						AddRow(user2.ID, user2.Name, user2.Email, user2.Password, user2.Role, user2.CardNumber, nil, nil, nil, 0, nil, nil, nil, user2.CreatedAt))
+			},
+		},
+		{
+			name:      "invalid sort",
+			filter:    models.UserFilter{},
+			page:      pagination.Request{Sort: "password"},
+			wantErr:   true,
+			mockSetup: func() {},
+		},
+		{
+			name:    "database error",
+			filter:  models.UserFilter{},
+			page:    pagination.Request{},
+			wantErr: true,
+			mockSetup: func() {
+				mock.ExpectQuery("(?i)select count\\(\\*\\) from users").
+					WillReturnError(errors.New("database error"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u := &UserRepository{
+				db: db,
+			}
+			tt.mockSetup()
+			got, err := u.GetUsers(tt.filter, tt.page)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetUsers() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				return
+			}
+			if !reflect.DeepEqual(got.Items, tt.want) {
+				t.Errorf("GetUsers() got = %v, want %v", got.Items, tt.want)
+			}
+			if got.Total != tt.wantTotal {
+				t.Errorf("GetUsers() total = %v, want %v", got.Total, tt.wantTotal)
+			}
+			if (got.NextCursor != "") != tt.wantNext {
+				t.Errorf("GetUsers() next cursor = %q, want next %v", got.NextCursor, tt.wantNext)
+			}
+		})
+	}
+}
+
+func TestUserRepository_UpdateUser(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	user := models.User{ID: uuid.New(), Name: "kaushik", Email: "kaushik@a.com"}
+
+	tests := []struct {
+		name      string
+		wantErr   bool
+		mockSetup func()
+	}{
+		{
+			name:    "valid update",
+			wantErr: false,
+			mockSetup: func() {
+				mock.ExpectExec("(?i)update users set name = .* email_verified_at = case when email = \\$3 .*").
+					WithArgs(user.ID, user.Name, user.Email, nil).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+		},
+		{
+			name:    "user not found",
+			wantErr: true,
+			mockSetup: func() {
+				mock.ExpectExec("(?i)update users set name = .*").WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+		},
+		{
+			name:    "email taken",
+			wantErr: true,
+			mockSetup: func() {
+				mock.ExpectExec("(?i)update users set name = .*").WillReturnError(&pq.Error{Code: "23505"})
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u := &UserRepository{
+				db: db,
+			}
+			tt.mockSetup()
+			if err := u.UpdateUser(user); (err != nil) != tt.wantErr {
+				t.Errorf("UpdateUser() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestUserRepository_SetRole(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	userId := uuid.New().String()
+
+	tests := []struct {
+		name      string
+		wantErr   bool
+		mockSetup func()
+	}{
+		{
+			name:    "valid set role",
+			wantErr: false,
+			mockSetup: func() {
+				mock.ExpectExec("(?i)update users set role = .*").
+					WithArgs(userId, int64(roles.Librarian)).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+		},
+		{
+			name:    "user not found",
+			wantErr: true,
+			mockSetup: func() {
+				mock.ExpectExec("(?i)update users set role = .*").WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u := &UserRepository{
+				db: db,
+			}
+			tt.mockSetup()
+			if err := u.SetRole(userId, roles.Librarian); (err != nil) != tt.wantErr {
+				t.Errorf("SetRole() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestUserRepository_SetActive(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	userId := uuid.New().String()
+
+	tests := []struct {
+		name      string
+		active    bool
+		wantErr   bool
+		mockSetup func()
+	}{
+		{
+			name:    "deactivate",
+			active:  false,
+			wantErr: false,
+			mockSetup: func() {
+				mock.ExpectExec("(?i)update users set deactivated_at = .*").
+					WithArgs(userId, false).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+		},
+		{
+			name:    "reactivate",
+			active:  true,
+			wantErr: false,
+			mockSetup: func() {
+				mock.ExpectExec("(?i)update users set deactivated_at = .*").
+					WithArgs(userId, true).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+		},
+		{
+			name:    "user not found",
+			active:  false,
+			wantErr: true,
+			mockSetup: func() {
+				mock.ExpectExec("(?i)update users set deactivated_at = .*").WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u := &UserRepository{
+				db: db,
+			}
+			tt.mockSetup()
+			if err := u.SetActive(userId, tt.active); (err != nil) != tt.wantErr {
+				t.Errorf("SetActive() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestUserRepository_SetPassword(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	userId := uuid.New().String()
+
+	tests := []struct {
+		name      string
+		wantErr   bool
+		mockSetup func()
+	}{
+		{
+			name:    "valid set password",
+			wantErr: false,
+			mockSetup: func() {
+				mock.ExpectExec("(?i)update users set password = .*").
+					WithArgs(userId, "hash").
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+		},
+		{
+			name:    "user not found",
+			wantErr: true,
+			mockSetup: func() {
+				mock.ExpectExec("(?i)update users set password = .*").WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u := &UserRepository{
+				db: db,
+			}
+			tt.mockSetup()
+			if err := u.SetPassword(userId, "hash"); (err != nil) != tt.wantErr {
+				t.Errorf("SetPassword() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestUserRepository_MarkEmailVerified(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	userId := uuid.New().String()
+
+	tests := []struct {
+		name      string
+		wantErr   bool
+		mockSetup func()
+	}{
+		{
+			name:    "valid verify",
+			wantErr: false,
+			mockSetup: func() {
+				mock.ExpectExec("(?i)update users set email_verified_at = .* where id = \\$1 and email = \\$2").
+					WithArgs(userId, "kaushik@a.com").
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+		},
+		{
+			name:    "email changed since",
+			wantErr: true,
+			mockSetup: func() {
+				mock.ExpectExec("(?i)update users set email_verified_at = .*").WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u := &UserRepository{
+				db: db,
+			}
+			tt.mockSetup()
+			if err := u.MarkEmailVerified(userId, "kaushik@a.com"); (err != nil) != tt.wantErr {
+				t.Errorf("MarkEmailVerified() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestUserRepository_ClaimVerificationEmail(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	userId := uuid.New().String()
+
+	tests := []struct {
+		name      string
+		want      bool
+		wantErr   bool
+		mockSetup func()
+	}{
+		{
+			name:    "nothing sent recently",
+			want:    true,
+			wantErr: false,
+			mockSetup: func() {
+				mock.ExpectExec("(?i)update users set verification_sent_at = now\\(\\) .*").
+					WithArgs(userId, float64(300)).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+		},
+		{
+			name:    "sent recently or verified",
+			want:    false,
+			wantErr: false,
+			mockSetup: func() {
+				mock.ExpectExec("(?i)update users set verification_sent_at = now\\(\\) .*").WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+		},
+		{
+			name:    "database error",
+			want:    false,
+			wantErr: true,
+			mockSetup: func() {
+				mock.ExpectExec("(?i)update users set verification_sent_at = .*").WillReturnError(errors.New("database error"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u := &UserRepository{
+				db: db,
+			}
+			tt.mockSetup()
+			got, err := u.ClaimVerificationEmail(userId, 5*time.Minute)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ClaimVerificationEmail() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("ClaimVerificationEmail() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUserRepository_RecordLoginFailure(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	userId := uuid.New().String()
+
+	tests := []struct {
+		name      string
+		want      int
+		wantErr   bool
+		mockSetup func()
+	}{
+		{
+			name:    "valid record",
+			want:    4,
+			wantErr: false,
+			mockSetup: func() {
+				mock.ExpectQuery("(?i)update users set failed_login_count = failed_login_count \\+ 1 .* returning failed_login_count").
+					WithArgs(userId).
+					WillReturnRows(sqlmock.NewRows([]string{"failed_login_count"}).AddRow(4))
+			},
+		},
+		{
+			name:    "user not found",
+			want:    0,
+			wantErr: true,
+			mockSetup: func() {
+				mock.ExpectQuery("(?i)update users set failed_login_count = .*").WillReturnError(sql.ErrNoRows)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u := &UserRepository{
+				db: db,
+			}
+			tt.mockSetup()
+			got, err := u.RecordLoginFailure(userId)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("RecordLoginFailure() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("RecordLoginFailure() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUserRepository_LockUntil(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	userId := uuid.New().String()
+	until := time.Now().Add(15 * time.Minute)
+
+	tests := []struct {
+		name      string
+		wantErr   bool
+		mockSetup func()
+	}{
+		{
+			name:    "valid lock",
+			wantErr: false,
+			mockSetup: func() {
+				mock.ExpectExec("(?i)update users set locked_until = greatest\\(locked_until, \\$2\\) .*").
+					WithArgs(userId, until).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+		},
+		{
+			name:    "user not found",
+			wantErr: true,
+			mockSetup: func() {
+				mock.ExpectExec("(?i)update users set locked_until = .*").WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u := &UserRepository{
+				db: db,
+			}
+			tt.mockSetup()
+			if err := u.LockUntil(userId, until); (err != nil) != tt.wantErr {
+				t.Errorf("LockUntil() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestUserRepository_ClearLoginFailures(t *testing.T) {
+	db, mock, _ := sqlmock.New()
+	defer db.Close()
+
+	userId := uuid.New().String()
+
+	tests := []struct {
+		name      string
+		wantErr   bool
+		mockSetup func()
+	}{
+		{
+			name:    "valid clear",
+			wantErr: false,
+			mockSetup: func() {
+				mock.ExpectExec("(?i)update users set failed_login_count = 0, locked_until = null .*").
+					WithArgs(userId).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			},
+		},
+		{
+			name:    "user not found",
+			wantErr: true,
+			mockSetup: func() {
+				mock.ExpectExec("(?i)update users set failed_login_count = 0.*").WillReturnResult(sqlmock.NewResult(0, 0))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u := &UserRepository{
+				db: db,
+			}
+			tt.mockSetup()
+			if err := u.ClearLoginFailures(userId); (err != nil) != tt.wantErr {
+				t.Errorf("ClearLoginFailures() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -1,9 +1,31 @@
 package authservice
 
-import "github.com/Kaushik1766/LibraryManagement/internal/models"
+import (
+	"context"
+
+	"github.com/Kaushik1766/LibraryManagement/internal/models"
+)
 
 //go:generate mockgen -source=interface.go -destination=../../../mocks/mock_auth_manager.go -package=mocks
 type AuthManager interface {
-	Login(loginReq models.LoginDTO) (string, error)
+	Login(loginReq models.LoginDTO, clientIP string) (models.TokenPairDTO, error)
 	Signup(signupReq models.SignupDTO) error
+	VerifyEmail(req models.VerifyEmailDTO) error
+	ResendVerification(ctx context.Context) error
+	Refresh(refreshToken string) (models.TokenPairDTO, error)
+	Logout(ctx context.Context) error
+	LogoutAll(ctx context.Context) error
+	GetProfile(ctx context.Context) (models.ProfileDTO, error)
+	UpdateProfile(ctx context.Context, req models.UpdateProfileDTO) (models.ProfileDTO, error)
+	ChangePassword(ctx context.Context, req models.ChangePasswordDTO) error
+	ForgotPassword(req models.ForgotPasswordDTO) error
+	ResetPassword(req models.ResetPasswordDTO) error
+	VerifyMFA(req models.VerifyMFADTO, clientIP string) (models.TokenPairDTO, error)
+	SetupMFA(ctx context.Context) (models.MFASetupDTO, error)
+	SetupMFAForLogin(req models.MFATokenDTO) (models.MFASetupDTO, error)
+	ConfirmMFA(ctx context.Context, req models.MFACodeDTO) (models.RecoveryCodesDTO, error)
+	DisableMFA(ctx context.Context, req models.DisableMFADTO) error
+	RegenerateRecoveryCodes(ctx context.Context, req models.MFACodeDTO) (models.RecoveryCodesDTO, error)
+	JWKS() models.JWKSDTO
+	Wait()
 }
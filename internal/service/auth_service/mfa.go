@@ -0,0 +1,353 @@
+package authservice
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base32"
+	"errors"
+	"log"
+	"strings"
+	"time"
+
+	apperrors "github.com/Kaushik1766/LibraryManagement/internal/app_errors"
+	"github.com/Kaushik1766/LibraryManagement/internal/models"
+	"github.com/Kaushik1766/LibraryManagement/internal/totp"
+	"golang.org/x/crypto/bcrypt"
+)
+
+var (
+	errInvalidMFAToken = apperrors.Unauthorized("invalid_mfa_token", "mfa token is invalid or expired")
+	errInvalidMFACode  = apperrors.Unauthorized("invalid_mfa_code", "invalid authentication code")
+	errMFAEnabled      = apperrors.Conflict("mfa_already_enabled", "mfa is already enabled")
+	errMFANotEnabled   = apperrors.Conflict("mfa_not_enabled", "mfa is not enabled")
+	errMFANotSetUp     = apperrors.Conflict("mfa_not_set_up", "mfa setup hasn't been started")
+	errMFARequired     = apperrors.Forbidden("mfa_required", "mfa is required for your role")
+)
+
+// What an mfa token lets its holder do: enter a code for an account that has
+// MFA, or set it up for one whose role requires it.
+const (
+	challengeVerify = "verify"
+	challengeEnroll = "enroll"
+)
+
+const (
+	recoveryCodeCount = 10
+	// totpSkew is how many periods either side of now a code is accepted
+	// for, allowing for clocks that drift.
+	totpSkew = 1
+)
+
+var recoveryEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// SetupMFA starts setting up MFA for the caller and returns the secret to add
+// to an authenticator app. Nothing changes until ConfirmMFA gets a code.
+func (service *AuthService) SetupMFA(ctx context.Context) (models.MFASetupDTO, error) {
+	userCtx, ok := ctx.Value("user").(models.UserJwt)
+	if !ok {
+		return models.MFASetupDTO{}, apperrors.ErrInvalidUser
+	}
+
+	user, err := service.userRepo.GetUserById(userCtx.Subject)
+	if err != nil {
+		return models.MFASetupDTO{}, err
+	}
+
+	if user.MFAEnabledAt != nil {
+		return models.MFASetupDTO{}, errMFAEnabled
+	}
+
+	return service.startSetup(user)
+}
+
+// SetupMFAForLogin is SetupMFA for a login whose role requires MFA before it
+// was ever set up. The login finishes with VerifyMFA.
+func (service *AuthService) SetupMFAForLogin(req models.MFATokenDTO) (models.MFASetupDTO, error) {
+	userId, kind, ok := service.parseChallenge(req.MFAToken)
+	if !ok || kind != challengeEnroll {
+		return models.MFASetupDTO{}, errInvalidMFAToken
+	}
+
+	user, err := service.userRepo.GetUserById(userId)
+	if err != nil {
+		return models.MFASetupDTO{}, err
+	}
+
+	if user.MFAEnabledAt != nil {
+		return models.MFASetupDTO{}, errMFAEnabled
+	}
+
+	return service.startSetup(user)
+}
+
+// ConfirmMFA turns MFA on for the caller once a code shows the authenticator
+// was set up, and returns the recovery codes. They are not shown again.
+func (service *AuthService) ConfirmMFA(ctx context.Context, req models.MFACodeDTO) (models.RecoveryCodesDTO, error) {
+	userCtx, ok := ctx.Value("user").(models.UserJwt)
+	if !ok {
+		return models.RecoveryCodesDTO{}, apperrors.ErrInvalidUser
+	}
+
+	user, err := service.userRepo.GetUserById(userCtx.Subject)
+	if err != nil {
+		return models.RecoveryCodesDTO{}, err
+	}
+
+	if user.MFAEnabledAt != nil {
+		return models.RecoveryCodesDTO{}, errMFAEnabled
+	}
+	if user.TOTPSecret == "" {
+		return models.RecoveryCodesDTO{}, errMFANotSetUp
+	}
+
+	codes, err := service.enableMFA(user, req.Code)
+	if err != nil {
+		return models.RecoveryCodesDTO{}, err
+	}
+	return models.RecoveryCodesDTO{RecoveryCodes: codes}, nil
+}
+
+// VerifyMFA finishes a login that Login answered with an mfa token. A wrong
+// code counts as a failed login. For a login that had to set up MFA the code
+// confirms the setup, and the recovery codes come back with the tokens.
+func (service *AuthService) VerifyMFA(req models.VerifyMFADTO, clientIP string) (models.TokenPairDTO, error) {
+	if req.MFAToken == "" || req.Code == "" {
+		return models.TokenPairDTO{}, apperrors.Validation("missing_fields", "mfa token and code cant be empty")
+	}
+
+	userId, kind, ok := service.parseChallenge(req.MFAToken)
+	if !ok {
+		return models.TokenPairDTO{}, errInvalidMFAToken
+	}
+
+	if err := service.checkIP(clientIP); err != nil {
+		return models.TokenPairDTO{}, err
+	}
+
+	user, err := service.userRepo.GetUserById(userId)
+	if err != nil {
+		return models.TokenPairDTO{}, err
+	}
+
+	if err := service.checkLocked(user, clientIP); err != nil {
+		return models.TokenPairDTO{}, err
+	}
+
+	if user.DeactivatedAt != nil {
+		return models.TokenPairDTO{}, errDeactivated
+	}
+
+	var recoveryCodes []string
+	if kind == challengeEnroll {
+		if user.MFAEnabledAt != nil || user.TOTPSecret == "" {
+			return models.TokenPairDTO{}, errMFANotSetUp
+		}
+
+		recoveryCodes, err = service.enableMFA(user, req.Code)
+	} else {
+		// mfa was turned off since the password was checked
+		if user.MFAEnabledAt == nil {
+			return models.TokenPairDTO{}, errInvalidMFAToken
+		}
+
+		err = service.checkCode(user, req.Code)
+	}
+	if errors.Is(err, errInvalidMFACode) {
+		service.loginFailed(user, clientIP, models.LoginInvalidMFACode)
+	}
+	if err != nil {
+		return models.TokenPairDTO{}, err
+	}
+
+	tokens, err := service.finishLogin(user)
+	if err != nil {
+		return models.TokenPairDTO{}, err
+	}
+	tokens.RecoveryCodes = recoveryCodes
+	return tokens, nil
+}
+
+// DisableMFA turns MFA off for the caller, who has to enter their password
+// and a code again. Roles that require MFA can't turn it off.
+func (service *AuthService) DisableMFA(ctx context.Context, req models.DisableMFADTO) error {
+	userCtx, ok := ctx.Value("user").(models.UserJwt)
+	if !ok {
+		return apperrors.ErrInvalidUser
+	}
+
+	if req.Password == "" || req.Code == "" {
+		return apperrors.Validation("missing_fields", "password and code cant be empty")
+	}
+
+	user, err := service.userRepo.GetUserById(userCtx.Subject)
+	if err != nil {
+		return err
+	}
+
+	if user.MFAEnabledAt == nil {
+		return errMFANotEnabled
+	}
+	if service.cfg.MFA.Required(user.Role) {
+		return errMFARequired
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(req.Password)); err != nil {
+		return apperrors.Unauthorized("invalid_credentials", "invalid password").WithDetail("password", "does not match")
+	}
+
+	if err := service.checkCode(user, req.Code); err != nil {
+		return err
+	}
+
+	return service.mfaRepo.DisableMFA(user.ID.String())
+}
+
+// RegenerateRecoveryCodes replaces the caller's recovery codes, used or not,
+// once a current code is entered.
+func (service *AuthService) RegenerateRecoveryCodes(ctx context.Context, req models.MFACodeDTO) (models.RecoveryCodesDTO, error) {
+	userCtx, ok := ctx.Value("user").(models.UserJwt)
+	if !ok {
+		return models.RecoveryCodesDTO{}, apperrors.ErrInvalidUser
+	}
+
+	user, err := service.userRepo.GetUserById(userCtx.Subject)
+	if err != nil {
+		return models.RecoveryCodesDTO{}, err
+	}
+
+	if user.MFAEnabledAt == nil {
+		return models.RecoveryCodesDTO{}, errMFANotEnabled
+	}
+
+	if err := service.checkCode(user, req.Code); err != nil {
+		return models.RecoveryCodesDTO{}, err
+	}
+
+	codes, hashes, err := newRecoveryCodes()
+	if err != nil {
+		return models.RecoveryCodesDTO{}, err
+	}
+
+	if err := service.mfaRepo.ReplaceRecoveryCodes(user.ID.String(), hashes); err != nil {
+		return models.RecoveryCodesDTO{}, err
+	}
+	return models.RecoveryCodesDTO{RecoveryCodes: codes}, nil
+}
+
+// mfaChallenge is what Login answers instead of tokens when user has to enter
+// a code, or set MFA up, first.
+func (service *AuthService) mfaChallenge(user models.User) (models.TokenPairDTO, bool) {
+	expiresAt := time.Now().Add(service.cfg.MFA.ChallengeTTL)
+
+	switch {
+	case user.MFAEnabledAt != nil:
+		return models.TokenPairDTO{
+			MFAToken: service.sign("mfa-challenge", expiresAt, user.ID.String(), challengeVerify),
+		}, true
+	case service.cfg.MFA.Required(user.Role):
+		return models.TokenPairDTO{
+			MFAToken:              service.sign("mfa-challenge", expiresAt, user.ID.String(), challengeEnroll),
+			MFAEnrollmentRequired: true,
+		}, true
+	}
+	return models.TokenPairDTO{}, false
+}
+
+// parseChallenge checks a token from mfaChallenge and returns the user and
+// what it was issued for.
+func (service *AuthService) parseChallenge(token string) (string, string, bool) {
+	fields, ok := service.parseSigned("mfa-challenge", token, time.Now(), 2)
+	if !ok {
+		return "", "", false
+	}
+	return fields[0], fields[1], true
+}
+
+// startSetup gives user a new pending secret, replacing one that was never
+// confirmed.
+func (service *AuthService) startSetup(user models.User) (models.MFASetupDTO, error) {
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		return models.MFASetupDTO{}, err
+	}
+
+	if err := service.mfaRepo.SetPendingSecret(user.ID.String(), secret); err != nil {
+		return models.MFASetupDTO{}, err
+	}
+
+	return models.MFASetupDTO{
+		Secret: secret,
+		URI:    totp.URI(service.cfg.MFA.Issuer, user.Email, secret),
+	}, nil
+}
+
+// enableMFA confirms the pending secret of user with an authenticator code
+// and returns the new recovery codes.
+func (service *AuthService) enableMFA(user models.User, code string) ([]string, error) {
+	step, ok := totp.Validate(user.TOTPSecret, strings.TrimSpace(code), time.Now(), totpSkew)
+	if !ok {
+		return nil, errInvalidMFACode
+	}
+
+	codes, hashes, err := newRecoveryCodes()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := service.mfaRepo.EnableMFA(user.ID.String(), hashes); err != nil {
+		return nil, err
+	}
+
+	// the code that confirmed the setup can't sign in as well
+	if _, err := service.mfaRepo.ClaimStep(user.ID.String(), step); err != nil {
+		log.Println(err)
+	}
+	return codes, nil
+}
+
+// checkCode accepts an authenticator code not used before or an unused
+// recovery code of user, spending it.
+func (service *AuthService) checkCode(user models.User, code string) error {
+	code = strings.TrimSpace(code)
+
+	if step, ok := totp.Validate(user.TOTPSecret, code, time.Now(), totpSkew); ok {
+		claimed, err := service.mfaRepo.ClaimStep(user.ID.String(), step)
+		if err != nil {
+			return err
+		}
+		if !claimed {
+			return errInvalidMFACode
+		}
+		return nil
+	}
+
+	err := service.mfaRepo.UseRecoveryCode(user.ID.String(), hashToken(normalizeRecoveryCode(code)))
+	if apperrors.KindOf(err) == apperrors.KindNotFound {
+		return errInvalidMFACode
+	}
+	return err
+}
+
+// newRecoveryCodes returns a set of recovery codes to show once and the
+// hashes to store.
+func newRecoveryCodes() ([]string, []string, error) {
+	codes := make([]string, 0, recoveryCodeCount)
+	hashes := make([]string, 0, recoveryCodeCount)
+
+	for range recoveryCodeCount {
+		raw := make([]byte, 5)
+		if _, err := rand.Read(raw); err != nil {
+			return nil, nil, err
+		}
+		code := strings.ToLower(recoveryEncoding.EncodeToString(raw))
+		codes = append(codes, code[:4]+"-"+code[4:])
+		hashes = append(hashes, hashToken(code))
+	}
+	return codes, hashes, nil
+}
+
+// normalizeRecoveryCode undoes the ways a recovery code is likely to be
+// retyped, in another case or without the dash.
+func normalizeRecoveryCode(code string) string {
+	return strings.ToLower(strings.NewReplacer("-", "", " ", "").Replace(code))
+}
@@ -0,0 +1,539 @@
+package authservice
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	apperrors "github.com/Kaushik1766/LibraryManagement/internal/app_errors"
+	"github.com/Kaushik1766/LibraryManagement/internal/models"
+	"github.com/Kaushik1766/LibraryManagement/internal/models/enums/roles"
+	"github.com/Kaushik1766/LibraryManagement/internal/totp"
+	"github.com/Kaushik1766/LibraryManagement/mocks"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"go.uber.org/mock/gomock"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const testTOTPSecret = "GEZDGNBVGY3TQOJQGEZDGNBVGY3TQOJQ"
+
+// currentCode is the code an authenticator app shows for testTOTPSecret now.
+func currentCode(t *testing.T) string {
+	code, err := totp.Code(testTOTPSecret, totp.Step(time.Now()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return code
+}
+
+// wrongCode is a well formed code that isn't accepted for testTOTPSecret now.
+func wrongCode(t *testing.T) string {
+	for n := 0; ; n++ {
+		code := strconv.Itoa(100000 + n)
+		if _, ok := totp.Validate(testTOTPSecret, code, time.Now(), totpSkew); !ok {
+			return code
+		}
+	}
+}
+
+func TestAuthService_LoginMFA(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUserRepo := mocks.NewMockUserStorage(ctrl)
+	mockFailedLoginRepo := mocks.NewMockFailedLoginStorage(ctrl)
+
+	hash, _ := bcrypt.GenerateFromPassword([]byte("123"), bcrypt.MinCost)
+	enabledAt := time.Now().Add(-time.Hour)
+	user := models.User{ID: uuid.New(), Email: "kaushik@a.com", Password: string(hash), Role: roles.Customer}
+	const ip = "203.0.113.7"
+
+	cfg := testAuthConfig
+	cfg.MFA.RequiredRoles = []string{"librarian"}
+
+	tests := []struct {
+		name           string
+		user           func() models.User
+		wantEnrollment bool
+	}{
+		{
+			name: "mfa enabled asks for a code",
+			user: func() models.User {
+				enabled := user
+				enabled.TOTPSecret = testTOTPSecret
+				enabled.MFAEnabledAt = &enabledAt
+				// failures are only cleared once the code is entered
+				enabled.FailedLoginCount = 2
+				return enabled
+			},
+		},
+		{
+			name: "required role without mfa has to set it up",
+			user: func() models.User {
+				librarian := user
+				librarian.Role = roles.Librarian
+				return librarian
+			},
+			wantEnrollment: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := &AuthService{
+				userRepo:        mockUserRepo,
+				failedLoginRepo: mockFailedLoginRepo,
+				cfg:             cfg,
+			}
+			mockFailedLoginRepo.EXPECT().CountFromIP(ip, gomock.Any()).Return(0, nil)
+			mockUserRepo.EXPECT().GetUserByEmail("kaushik@a.com").Return(tt.user(), nil)
+
+			got, err := service.Login(models.LoginDTO{Email: "kaushik@a.com", Password: "123"}, ip)
+			if err != nil {
+				t.Fatalf("Login() error = %v", err)
+			}
+			if got.AccessToken != "" || got.RefreshToken != "" {
+				t.Errorf("Login() issued tokens before the code was entered")
+			}
+			if got.MFAEnrollmentRequired != tt.wantEnrollment {
+				t.Errorf("Login() MFAEnrollmentRequired = %v, want %v", got.MFAEnrollmentRequired, tt.wantEnrollment)
+			}
+			userId, _, ok := service.parseChallenge(got.MFAToken)
+			if !ok || userId != user.ID.String() {
+				t.Errorf("Login() mfa token = %q, not a challenge for the user", got.MFAToken)
+			}
+		})
+	}
+}
+
+func TestAuthService_VerifyMFA(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUserRepo := mocks.NewMockUserStorage(ctrl)
+	mockSessionRepo := mocks.NewMockSessionStorage(ctrl)
+	mockFailedLoginRepo := mocks.NewMockFailedLoginStorage(ctrl)
+	mockMFARepo := mocks.NewMockMFAStorage(ctrl)
+
+	service := &AuthService{
+		userRepo:        mockUserRepo,
+		sessionRepo:     mockSessionRepo,
+		failedLoginRepo: mockFailedLoginRepo,
+		mfaRepo:         mockMFARepo,
+		keys:            testKeys,
+		cfg:             testAuthConfig,
+	}
+
+	enabledAt := time.Now().Add(-time.Hour)
+	userId := uuid.New()
+	user := models.User{
+		ID:               userId,
+		Email:            "kaushik@a.com",
+		TOTPSecret:       testTOTPSecret,
+		MFAEnabledAt:     &enabledAt,
+		FailedLoginCount: 1,
+	}
+	pending := user
+	pending.MFAEnabledAt = nil
+	pending.FailedLoginCount = 0
+
+	expiresAt := time.Now().Add(time.Minute)
+	verifyToken := service.sign("mfa-challenge", expiresAt, userId.String(), challengeVerify)
+	enrollToken := service.sign("mfa-challenge", expiresAt, userId.String(), challengeEnroll)
+	const ip = "203.0.113.7"
+
+	tests := []struct {
+		name              string
+		req               models.VerifyMFADTO
+		wantErr           bool
+		wantCode          string
+		wantRecoveryCodes bool
+		mockSetup         func()
+	}{
+		{
+			name:    "valid code signs in",
+			req:     models.VerifyMFADTO{MFAToken: verifyToken, Code: currentCode(t)},
+			wantErr: false,
+			mockSetup: func() {
+				mockFailedLoginRepo.EXPECT().CountFromIP(ip, gomock.Any()).Return(0, nil)
+				mockUserRepo.EXPECT().GetUserById(userId.String()).Return(user, nil)
+				mockMFARepo.EXPECT().ClaimStep(userId.String(), gomock.Any()).Return(true, nil)
+				mockUserRepo.EXPECT().ClearLoginFailures(userId.String()).Return(nil)
+				mockSessionRepo.EXPECT().CreateSession(gomock.Any()).Return(nil)
+			},
+		},
+		{
+			name:    "recovery code signs in",
+			req:     models.VerifyMFADTO{MFAToken: verifyToken, Code: "ABCD-2345"},
+			wantErr: false,
+			mockSetup: func() {
+				mockFailedLoginRepo.EXPECT().CountFromIP(ip, gomock.Any()).Return(0, nil)
+				mockUserRepo.EXPECT().GetUserById(userId.String()).Return(user, nil)
+				mockMFARepo.EXPECT().UseRecoveryCode(userId.String(), hashToken("abcd2345")).Return(nil)
+				mockUserRepo.EXPECT().ClearLoginFailures(userId.String()).Return(nil)
+				mockSessionRepo.EXPECT().CreateSession(gomock.Any()).Return(nil)
+			},
+		},
+		{
+			name:     "wrong code counts as a failed login",
+			req:      models.VerifyMFADTO{MFAToken: verifyToken, Code: wrongCode(t)},
+			wantErr:  true,
+			wantCode: "invalid_mfa_code",
+			mockSetup: func() {
+				mockFailedLoginRepo.EXPECT().CountFromIP(ip, gomock.Any()).Return(0, nil)
+				mockUserRepo.EXPECT().GetUserById(userId.String()).Return(user, nil)
+				mockMFARepo.EXPECT().UseRecoveryCode(userId.String(), gomock.Any()).
+					Return(apperrors.NotFound("recovery_code_not_found", "recovery code not found"))
+				mockFailedLoginRepo.EXPECT().RecordFailedLogin(models.FailedLogin{
+					Email:  "kaushik@a.com",
+					UserID: &userId,
+					IP:     ip,
+					Reason: models.LoginInvalidMFACode,
+				}).Return(nil)
+				mockUserRepo.EXPECT().RecordLoginFailure(userId.String()).Return(2, nil)
+			},
+		},
+		{
+			name:     "code already used",
+			req:      models.VerifyMFADTO{MFAToken: verifyToken, Code: currentCode(t)},
+			wantErr:  true,
+			wantCode: "invalid_mfa_code",
+			mockSetup: func() {
+				mockFailedLoginRepo.EXPECT().CountFromIP(ip, gomock.Any()).Return(0, nil)
+				mockUserRepo.EXPECT().GetUserById(userId.String()).Return(user, nil)
+				mockMFARepo.EXPECT().ClaimStep(userId.String(), gomock.Any()).Return(false, nil)
+				mockFailedLoginRepo.EXPECT().RecordFailedLogin(gomock.Any()).Return(nil)
+				mockUserRepo.EXPECT().RecordLoginFailure(userId.String()).Return(2, nil)
+			},
+		},
+		{
+			name:              "enrollment confirms the setup",
+			req:               models.VerifyMFADTO{MFAToken: enrollToken, Code: currentCode(t)},
+			wantErr:           false,
+			wantRecoveryCodes: true,
+			mockSetup: func() {
+				mockFailedLoginRepo.EXPECT().CountFromIP(ip, gomock.Any()).Return(0, nil)
+				mockUserRepo.EXPECT().GetUserById(userId.String()).Return(pending, nil)
+				mockMFARepo.EXPECT().EnableMFA(userId.String(), gomock.Len(recoveryCodeCount)).Return(nil)
+				mockMFARepo.EXPECT().ClaimStep(userId.String(), totp.Step(time.Now())).Return(true, nil)
+				mockSessionRepo.EXPECT().CreateSession(gomock.Any()).Return(nil)
+			},
+		},
+		{
+			name:     "enrollment before the setup",
+			req:      models.VerifyMFADTO{MFAToken: enrollToken, Code: currentCode(t)},
+			wantErr:  true,
+			wantCode: "mfa_not_set_up",
+			mockSetup: func() {
+				notStarted := pending
+				notStarted.TOTPSecret = ""
+				mockFailedLoginRepo.EXPECT().CountFromIP(ip, gomock.Any()).Return(0, nil)
+				mockUserRepo.EXPECT().GetUserById(userId.String()).Return(notStarted, nil)
+			},
+		},
+		{
+			name:      "tampered token",
+			req:       models.VerifyMFADTO{MFAToken: verifyToken + "x", Code: currentCode(t)},
+			wantErr:   true,
+			wantCode:  "invalid_mfa_token",
+			mockSetup: func() {},
+		},
+		{
+			name:      "expired token",
+			req:       models.VerifyMFADTO{MFAToken: service.sign("mfa-challenge", time.Now().Add(-time.Second), userId.String(), challengeVerify), Code: currentCode(t)},
+			wantErr:   true,
+			wantCode:  "invalid_mfa_token",
+			mockSetup: func() {},
+		},
+		{
+			name:      "email verification token",
+			req:       models.VerifyMFADTO{MFAToken: service.signVerification(userId.String(), challengeVerify, expiresAt), Code: currentCode(t)},
+			wantErr:   true,
+			wantCode:  "invalid_mfa_token",
+			mockSetup: func() {},
+		},
+		{
+			name:      "missing code",
+			req:       models.VerifyMFADTO{MFAToken: verifyToken},
+			wantErr:   true,
+			wantCode:  "missing_fields",
+			mockSetup: func() {},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockSetup()
+			got, err := service.VerifyMFA(tt.req, ip)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("VerifyMFA() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			var appErr *apperrors.Error
+			if tt.wantCode != "" && (!errors.As(err, &appErr) || appErr.Code != tt.wantCode) {
+				t.Errorf("VerifyMFA() error = %v, want code %v", err, tt.wantCode)
+			}
+			if !tt.wantErr && (got.AccessToken == "" || got.RefreshToken == "") {
+				t.Errorf("VerifyMFA() = %+v, want tokens", got)
+			}
+			if (len(got.RecoveryCodes) > 0) != tt.wantRecoveryCodes {
+				t.Errorf("VerifyMFA() recovery codes = %v, want some %v", got.RecoveryCodes, tt.wantRecoveryCodes)
+			}
+		})
+	}
+}
+
+func TestAuthService_SetupMFA(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUserRepo := mocks.NewMockUserStorage(ctrl)
+	mockMFARepo := mocks.NewMockMFAStorage(ctrl)
+
+	service := &AuthService{userRepo: mockUserRepo, mfaRepo: mockMFARepo, cfg: testAuthConfig}
+
+	user := models.User{ID: uuid.New(), Email: "kaushik@a.com"}
+	enabledAt := time.Now()
+	ctx := context.WithValue(context.Background(), "user", models.UserJwt{
+		RegisteredClaims: jwt.RegisteredClaims{Subject: user.ID.String()},
+	})
+
+	t.Run("returns a new secret", func(t *testing.T) {
+		var stored string
+		mockUserRepo.EXPECT().GetUserById(user.ID.String()).Return(user, nil)
+		mockMFARepo.EXPECT().SetPendingSecret(user.ID.String(), gomock.Any()).
+			DoAndReturn(func(userId, secret string) error {
+				stored = secret
+				return nil
+			})
+
+		got, err := service.SetupMFA(ctx)
+		if err != nil {
+			t.Fatalf("SetupMFA() error = %v", err)
+		}
+		if got.Secret == "" || got.Secret != stored {
+			t.Errorf("SetupMFA() secret = %q, stored %q", got.Secret, stored)
+		}
+		if !strings.HasPrefix(got.URI, "otpauth://totp/Library:kaushik@a.com?") {
+			t.Errorf("SetupMFA() uri = %q", got.URI)
+		}
+	})
+
+	t.Run("already enabled", func(t *testing.T) {
+		enabled := user
+		enabled.MFAEnabledAt = &enabledAt
+		mockUserRepo.EXPECT().GetUserById(user.ID.String()).Return(enabled, nil)
+
+		if _, err := service.SetupMFA(ctx); !errors.Is(err, errMFAEnabled) {
+			t.Errorf("SetupMFA() error = %v, want %v", err, errMFAEnabled)
+		}
+	})
+
+	t.Run("login needs an enrollment token", func(t *testing.T) {
+		token := service.sign("mfa-challenge", time.Now().Add(time.Minute), user.ID.String(), challengeVerify)
+
+		if _, err := service.SetupMFAForLogin(models.MFATokenDTO{MFAToken: token}); !errors.Is(err, errInvalidMFAToken) {
+			t.Errorf("SetupMFAForLogin() error = %v, want %v", err, errInvalidMFAToken)
+		}
+	})
+
+	t.Run("login with an enrollment token", func(t *testing.T) {
+		token := service.sign("mfa-challenge", time.Now().Add(time.Minute), user.ID.String(), challengeEnroll)
+		mockUserRepo.EXPECT().GetUserById(user.ID.String()).Return(user, nil)
+		mockMFARepo.EXPECT().SetPendingSecret(user.ID.String(), gomock.Any()).Return(nil)
+
+		if got, err := service.SetupMFAForLogin(models.MFATokenDTO{MFAToken: token}); err != nil || got.Secret == "" {
+			t.Errorf("SetupMFAForLogin() = %+v, %v", got, err)
+		}
+	})
+}
+
+func TestAuthService_ConfirmMFA(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUserRepo := mocks.NewMockUserStorage(ctrl)
+	mockMFARepo := mocks.NewMockMFAStorage(ctrl)
+
+	user := models.User{ID: uuid.New(), TOTPSecret: testTOTPSecret}
+	ctx := context.WithValue(context.Background(), "user", models.UserJwt{
+		RegisteredClaims: jwt.RegisteredClaims{Subject: user.ID.String()},
+	})
+
+	tests := []struct {
+		name      string
+		req       models.MFACodeDTO
+		wantErr   error
+		mockSetup func()
+	}{
+		{
+			name:    "valid code enables mfa",
+			req:     models.MFACodeDTO{Code: currentCode(t)},
+			wantErr: nil,
+			mockSetup: func() {
+				mockUserRepo.EXPECT().GetUserById(user.ID.String()).Return(user, nil)
+				mockMFARepo.EXPECT().EnableMFA(user.ID.String(), gomock.Len(recoveryCodeCount)).Return(nil)
+				mockMFARepo.EXPECT().ClaimStep(user.ID.String(), gomock.Any()).Return(true, nil)
+			},
+		},
+		{
+			name:    "wrong code",
+			req:     models.MFACodeDTO{Code: wrongCode(t)},
+			wantErr: errInvalidMFACode,
+			mockSetup: func() {
+				mockUserRepo.EXPECT().GetUserById(user.ID.String()).Return(user, nil)
+			},
+		},
+		{
+			name:    "setup not started",
+			req:     models.MFACodeDTO{Code: currentCode(t)},
+			wantErr: errMFANotSetUp,
+			mockSetup: func() {
+				mockUserRepo.EXPECT().GetUserById(user.ID.String()).Return(models.User{ID: user.ID}, nil)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := &AuthService{userRepo: mockUserRepo, mfaRepo: mockMFARepo, cfg: testAuthConfig}
+			tt.mockSetup()
+			got, err := service.ConfirmMFA(ctx, tt.req)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("ConfirmMFA() error = %v, want %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr == nil && len(got.RecoveryCodes) != recoveryCodeCount {
+				t.Errorf("ConfirmMFA() recovery codes = %v", got.RecoveryCodes)
+			}
+		})
+	}
+}
+
+func TestAuthService_DisableMFA(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUserRepo := mocks.NewMockUserStorage(ctrl)
+	mockMFARepo := mocks.NewMockMFAStorage(ctrl)
+
+	hash, _ := bcrypt.GenerateFromPassword([]byte("123"), bcrypt.MinCost)
+	enabledAt := time.Now().Add(-time.Hour)
+	user := models.User{ID: uuid.New(), Password: string(hash), Role: roles.Customer, TOTPSecret: testTOTPSecret, MFAEnabledAt: &enabledAt}
+	ctx := context.WithValue(context.Background(), "user", models.UserJwt{
+		RegisteredClaims: jwt.RegisteredClaims{Subject: user.ID.String()},
+	})
+
+	cfg := testAuthConfig
+	cfg.MFA.RequiredRoles = []string{"staff"}
+
+	tests := []struct {
+		name      string
+		req       models.DisableMFADTO
+		wantErr   bool
+		mockSetup func()
+	}{
+		{
+			name:    "valid password and code",
+			req:     models.DisableMFADTO{Password: "123", Code: currentCode(t)},
+			wantErr: false,
+			mockSetup: func() {
+				mockUserRepo.EXPECT().GetUserById(user.ID.String()).Return(user, nil)
+				mockMFARepo.EXPECT().ClaimStep(user.ID.String(), gomock.Any()).Return(true, nil)
+				mockMFARepo.EXPECT().DisableMFA(user.ID.String()).Return(nil)
+			},
+		},
+		{
+			name:    "wrong password",
+			req:     models.DisableMFADTO{Password: "125", Code: currentCode(t)},
+			wantErr: true,
+			mockSetup: func() {
+				mockUserRepo.EXPECT().GetUserById(user.ID.String()).Return(user, nil)
+			},
+		},
+		{
+			name:    "required for the role",
+			req:     models.DisableMFADTO{Password: "123", Code: currentCode(t)},
+			wantErr: true,
+			mockSetup: func() {
+				staff := user
+				staff.Role = roles.Staff
+				mockUserRepo.EXPECT().GetUserById(user.ID.String()).Return(staff, nil)
+			},
+		},
+		{
+			name:    "not enabled",
+			req:     models.DisableMFADTO{Password: "123", Code: currentCode(t)},
+			wantErr: true,
+			mockSetup: func() {
+				mockUserRepo.EXPECT().GetUserById(user.ID.String()).Return(models.User{ID: user.ID, Password: string(hash)}, nil)
+			},
+		},
+		{
+			name:      "missing code",
+			req:       models.DisableMFADTO{Password: "123"},
+			wantErr:   true,
+			mockSetup: func() {},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := &AuthService{userRepo: mockUserRepo, mfaRepo: mockMFARepo, cfg: cfg}
+			tt.mockSetup()
+			if err := service.DisableMFA(ctx, tt.req); (err != nil) != tt.wantErr {
+				t.Errorf("DisableMFA() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestAuthService_RegenerateRecoveryCodes(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUserRepo := mocks.NewMockUserStorage(ctrl)
+	mockMFARepo := mocks.NewMockMFAStorage(ctrl)
+
+	service := &AuthService{userRepo: mockUserRepo, mfaRepo: mockMFARepo, cfg: testAuthConfig}
+
+	enabledAt := time.Now().Add(-time.Hour)
+	user := models.User{ID: uuid.New(), TOTPSecret: testTOTPSecret, MFAEnabledAt: &enabledAt}
+	ctx := context.WithValue(context.Background(), "user", models.UserJwt{
+		RegisteredClaims: jwt.RegisteredClaims{Subject: user.ID.String()},
+	})
+
+	t.Run("replaces the codes", func(t *testing.T) {
+		var hashes []string
+		mockUserRepo.EXPECT().GetUserById(user.ID.String()).Return(user, nil)
+		mockMFARepo.EXPECT().ClaimStep(user.ID.String(), gomock.Any()).Return(true, nil)
+		mockMFARepo.EXPECT().ReplaceRecoveryCodes(user.ID.String(), gomock.Any()).
+			DoAndReturn(func(userId string, codeHashes []string) error {
+				hashes = codeHashes
+				return nil
+			})
+
+		got, err := service.RegenerateRecoveryCodes(ctx, models.MFACodeDTO{Code: currentCode(t)})
+		if err != nil {
+			t.Fatalf("RegenerateRecoveryCodes() error = %v", err)
+		}
+		if len(got.RecoveryCodes) != recoveryCodeCount || len(hashes) != recoveryCodeCount {
+			t.Fatalf("RegenerateRecoveryCodes() = %v codes, %v hashes", len(got.RecoveryCodes), len(hashes))
+		}
+		for i, code := range got.RecoveryCodes {
+			if hashToken(normalizeRecoveryCode(strings.ToUpper(code))) != hashes[i] {
+				t.Errorf("recovery code %q doesn't match its stored hash", code)
+			}
+		}
+	})
+
+	t.Run("wrong code", func(t *testing.T) {
+		mockUserRepo.EXPECT().GetUserById(user.ID.String()).Return(user, nil)
+		mockMFARepo.EXPECT().UseRecoveryCode(user.ID.String(), gomock.Any()).
+			Return(apperrors.NotFound("recovery_code_not_found", "recovery code not found"))
+
+		if _, err := service.RegenerateRecoveryCodes(ctx, models.MFACodeDTO{Code: wrongCode(t)}); !errors.Is(err, errInvalidMFACode) {
+			t.Errorf("RegenerateRecoveryCodes() error = %v, want %v", err, errInvalidMFACode)
+		}
+	})
+}
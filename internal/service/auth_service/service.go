@@ -1,67 +1,730 @@
 package authservice
 
 import (
-	"errors"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"log"
 	"net/mail"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
 	"time"
 
+	apperrors "github.com/Kaushik1766/LibraryManagement/internal/app_errors"
 	"github.com/Kaushik1766/LibraryManagement/internal/config"
+	jwtkeys "github.com/Kaushik1766/LibraryManagement/internal/jwt_keys"
+	"github.com/Kaushik1766/LibraryManagement/internal/mailer"
 	"github.com/Kaushik1766/LibraryManagement/internal/models"
+	failedloginrepo "github.com/Kaushik1766/LibraryManagement/internal/repository/failed_login_repo"
+	mfarepo "github.com/Kaushik1766/LibraryManagement/internal/repository/mfa_repo"
+	resetrepo "github.com/Kaushik1766/LibraryManagement/internal/repository/reset_repo"
+	sessionrepo "github.com/Kaushik1766/LibraryManagement/internal/repository/session_repo"
 	userrepo "github.com/Kaushik1766/LibraryManagement/internal/repository/user_repo"
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
 	"golang.org/x/crypto/bcrypt"
 )
 
+var (
+	errDeactivated        = apperrors.Forbidden("account_deactivated", "account is deactivated")
+	errInvalidCredentials = apperrors.Unauthorized("invalid_credentials", "invalid email or password")
+)
+
+// dummyHash is compared against when a login names no account, so it takes
+// as long as a wrong password. It has the cost passwords are hashed with.
+var dummyHash = []byte("$2a$12$jPb74vp9rN5VTTKsg4yCaerbyKUw5ykfzvKtt1RyQICKmnl3Xwx9i")
+
+var (
+	errInvalidResetToken   = apperrors.Unauthorized("invalid_reset_token", "reset token is invalid or expired")
+	errInvalidVerification = apperrors.Unauthorized("invalid_verification_token", "verification link is invalid or expired")
+	errVerificationSent    = apperrors.TooManyRequests("verification_rate_limited", "a verification email was sent recently, try again later")
+)
+
 type AuthService struct {
-	userRepo userrepo.UserStorage
+	userRepo        userrepo.UserStorage
+	sessionRepo     sessionrepo.SessionStorage
+	resetRepo       resetrepo.ResetStorage
+	failedLoginRepo failedloginrepo.FailedLoginStorage
+	mfaRepo         mfarepo.MFAStorage
+	mailer          mailer.Mailer
+	keys            *jwtkeys.KeySet
+	cfg             config.AuthConfig
+
+	// background counts the reset mails still being sent
+	background sync.WaitGroup
 }
 
-func NewAuthService(userRepo userrepo.UserStorage) *AuthService {
+func NewAuthService(userRepo userrepo.UserStorage, sessionRepo sessionrepo.SessionStorage, resetRepo resetrepo.ResetStorage, failedLoginRepo failedloginrepo.FailedLoginStorage, mfaRepo mfarepo.MFAStorage, mailer mailer.Mailer, keys *jwtkeys.KeySet, cfg config.AuthConfig) *AuthService {
 	return &AuthService{
-		userRepo: userRepo,
+		userRepo:        userRepo,
+		sessionRepo:     sessionRepo,
+		resetRepo:       resetRepo,
+		failedLoginRepo: failedLoginRepo,
+		mfaRepo:         mfaRepo,
+		mailer:          mailer,
+		keys:            keys,
+		cfg:             cfg,
 	}
 }
 
-func (service *AuthService) Login(loginReq models.LoginDTO) (string, error) {
+// Login trades an email and password for a token pair. A wrong password and
+// an unknown email get the same answer and take as long, so logins can't be
+// used to find out who has an account. Failed logins are recorded, and
+// accounts and addresses that fail too often have to wait before trying
+// again.
+func (service *AuthService) Login(loginReq models.LoginDTO, clientIP string) (models.TokenPairDTO, error) {
 	if loginReq.Email == "" || loginReq.Password == "" {
-		return "", errors.New("email or password cant be empty")
+		return models.TokenPairDTO{}, apperrors.Validation("missing_credentials", "email or password cant be empty")
 	}
 	_, err := mail.ParseAddress(loginReq.Email)
 	if err != nil {
-		return "", errors.New("invalid email address")
+		return models.TokenPairDTO{}, apperrors.Validation("invalid_email", "invalid email address").WithDetail("email", "not a valid address")
+	}
+
+	if err := service.checkIP(clientIP); err != nil {
+		return models.TokenPairDTO{}, err
 	}
 
 	user, err := service.userRepo.GetUserByEmail(loginReq.Email)
+	if apperrors.KindOf(err) == apperrors.KindNotFound {
+		bcrypt.CompareHashAndPassword(dummyHash, []byte(loginReq.Password))
+		service.recordFailedLogin(loginReq.Email, nil, clientIP, models.LoginUnknownEmail)
+		return models.TokenPairDTO{}, errInvalidCredentials
+	}
 	if err != nil {
-		return "", err
+		return models.TokenPairDTO{}, err
+	}
+
+	if err := service.checkLocked(user, clientIP); err != nil {
+		return models.TokenPairDTO{}, err
 	}
 
 	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(loginReq.Password)); err != nil {
-		return "", errors.New("invalid password")
+		service.loginFailed(user, clientIP, models.LoginInvalidPassword)
+		return models.TokenPairDTO{}, errInvalidCredentials
+	}
+
+	if user.DeactivatedAt != nil {
+		return models.TokenPairDTO{}, errDeactivated
+	}
+
+	// the password alone doesn't finish a login that needs a code, and the
+	// failures so far keep counting against guessing the code
+	if challenge, ok := service.mfaChallenge(user); ok {
+		return challenge, nil
+	}
+
+	return service.finishLogin(user)
+}
+
+// Signup registers an unverified account and mails it a verification link.
+// The account exists even if the mail fails, the link can be sent again.
+func (service *AuthService) Signup(signupReq models.SignupDTO) error {
+	if signupReq.Name == "" || signupReq.Password == "" || signupReq.Email == "" {
+		return apperrors.Validation("missing_fields", "name, email or password cant be empty")
+	}
+	if _, err := mail.ParseAddress(signupReq.Email); err != nil {
+		return apperrors.Validation("invalid_email", "invalid email address").WithDetail("email", "not a valid address")
+	}
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(signupReq.Password), 12)
+	if err != nil {
+		return apperrors.Validation("password_too_long", "password too long").WithDetail("password", "at most 72 bytes")
+	}
+
+	userId, err := service.userRepo.AddUser(signupReq.Name, signupReq.Email, string(hashedPassword))
+	if err != nil {
+		return err
+	}
+
+	if err := service.sendVerification(userId, signupReq.Name, signupReq.Email); err != nil {
+		log.Println(err)
+	}
+	return nil
+}
+
+// VerifyEmail confirms an address with the link from sendVerification. A
+// link for an address the account no longer has is refused.
+func (service *AuthService) VerifyEmail(req models.VerifyEmailDTO) error {
+	if req.Token == "" {
+		return apperrors.Validation("missing_fields", "token cant be empty")
+	}
+
+	userId, email, err := service.parseVerification(req.Token, time.Now())
+	if err != nil {
+		return err
+	}
+
+	err = service.userRepo.MarkEmailVerified(userId, email)
+	if apperrors.KindOf(err) == apperrors.KindNotFound {
+		return errInvalidVerification
+	}
+	return err
+}
+
+// ResendVerification mails the caller a new verification link, at most once
+// per resend interval.
+func (service *AuthService) ResendVerification(ctx context.Context) error {
+	userCtx, ok := ctx.Value("user").(models.UserJwt)
+	if !ok {
+		return apperrors.ErrInvalidUser
+	}
+
+	user, err := service.userRepo.GetUserById(userCtx.Subject)
+	if err != nil {
+		return err
+	}
+
+	if user.EmailVerifiedAt != nil {
+		return apperrors.Conflict("already_verified", "email already verified")
+	}
+
+	return service.sendVerification(user.ID.String(), user.Name, user.Email)
+}
+
+// Refresh trades a refresh token for a new token pair. Each refresh token
+// works once, presenting one that was already rotated revokes the whole login
+// since either the client or an attacker is replaying it.
+func (service *AuthService) Refresh(refreshToken string) (models.TokenPairDTO, error) {
+	if refreshToken == "" {
+		return models.TokenPairDTO{}, apperrors.Validation("missing_refresh_token", "refresh token cant be empty")
+	}
+
+	session, err := service.sessionRepo.GetSessionByToken(hashToken(refreshToken))
+	if err != nil {
+		return models.TokenPairDTO{}, apperrors.Unauthorized("invalid_refresh_token", "invalid refresh token")
+	}
+
+	if session.RevokedAt != nil {
+		return models.TokenPairDTO{}, apperrors.Unauthorized("invalid_refresh_token", "invalid refresh token")
+	}
+
+	if session.RotatedAt != nil {
+		if err := service.sessionRepo.RevokeFamily(session.FamilyID.String()); err != nil {
+			log.Println(err)
+		}
+		return models.TokenPairDTO{}, apperrors.Unauthorized("refresh_token_reused", "refresh token reuse detected")
+	}
+
+	if session.ExpiresAt.Before(time.Now()) {
+		return models.TokenPairDTO{}, apperrors.Unauthorized("refresh_token_expired", "refresh token expired")
+	}
+
+	// the role may have changed since the last token was minted
+	user, err := service.userRepo.GetUserById(session.UserID.String())
+	if err != nil {
+		return models.TokenPairDTO{}, err
+	}
+
+	if user.DeactivatedAt != nil {
+		return models.TokenPairDTO{}, errDeactivated
+	}
+
+	tokens, next, err := service.issueTokens(user, session.FamilyID)
+	if err != nil {
+		return models.TokenPairDTO{}, err
+	}
+	// rotating doesn't extend the login past its original lifetime
+	next.ExpiresAt = session.ExpiresAt
+
+	if err := service.sessionRepo.RotateSession(session.ID.String(), next); err != nil {
+		return models.TokenPairDTO{}, err
+	}
+	return tokens, nil
+}
+
+// Logout ends the login the caller's access token belongs to.
+func (service *AuthService) Logout(ctx context.Context) error {
+	userCtx, ok := ctx.Value("user").(models.UserJwt)
+	if !ok {
+		return apperrors.ErrInvalidUser
+	}
+
+	session, err := service.sessionRepo.GetSessionByAccessToken(userCtx.ID)
+	if err != nil {
+		return err
+	}
+
+	return service.sessionRepo.RevokeFamily(session.FamilyID.String())
+}
+
+// LogoutAll ends every login of the caller, on any device.
+func (service *AuthService) LogoutAll(ctx context.Context) error {
+	userCtx, ok := ctx.Value("user").(models.UserJwt)
+	if !ok {
+		return apperrors.ErrInvalidUser
+	}
+
+	return service.sessionRepo.RevokeUserSessions(userCtx.Subject)
+}
+
+func (service *AuthService) GetProfile(ctx context.Context) (models.ProfileDTO, error) {
+	userCtx, ok := ctx.Value("user").(models.UserJwt)
+	if !ok {
+		return models.ProfileDTO{}, apperrors.ErrInvalidUser
+	}
+
+	user, err := service.userRepo.GetUserById(userCtx.Subject)
+	if err != nil {
+		return models.ProfileDTO{}, err
+	}
+
+	return toProfileDTO(user), nil
+}
+
+// UpdateProfile changes the caller's own name or email.
+func (service *AuthService) UpdateProfile(ctx context.Context, req models.UpdateProfileDTO) (models.ProfileDTO, error) {
+	userCtx, ok := ctx.Value("user").(models.UserJwt)
+	if !ok {
+		return models.ProfileDTO{}, apperrors.ErrInvalidUser
+	}
+
+	user, err := service.userRepo.GetUserById(userCtx.Subject)
+	if err != nil {
+		return models.ProfileDTO{}, err
+	}
+
+	if req.Name != nil {
+		user.Name = strings.TrimSpace(*req.Name)
+		if user.Name == "" {
+			return models.ProfileDTO{}, apperrors.Validation("invalid_input", "invalid input").WithDetail("name", "required")
+		}
+	}
+	emailChanged := false
+	if req.Email != nil {
+		if _, err := mail.ParseAddress(*req.Email); err != nil {
+			return models.ProfileDTO{}, apperrors.Validation("invalid_email", "invalid email address").WithDetail("email", "not a valid address")
+		}
+		emailChanged = *req.Email != user.Email
+		user.Email = *req.Email
+	}
+
+	if err := service.userRepo.UpdateUser(user); err != nil {
+		return models.ProfileDTO{}, err
+	}
+
+	// a new address has to be verified again
+	if emailChanged {
+		user.EmailVerifiedAt = nil
+		if err := service.sendVerification(user.ID.String(), user.Name, user.Email); err != nil {
+			log.Println(err)
+		}
+	}
+
+	return toProfileDTO(user), nil
+}
+
+// ChangePassword replaces the caller's password once the current one is
+// confirmed, and signs out every other device. The login making the change
+// stays signed in.
+func (service *AuthService) ChangePassword(ctx context.Context, req models.ChangePasswordDTO) error {
+	userCtx, ok := ctx.Value("user").(models.UserJwt)
+	if !ok {
+		return apperrors.ErrInvalidUser
+	}
+
+	if req.CurrentPassword == "" || req.NewPassword == "" {
+		return apperrors.Validation("missing_fields", "current and new password cant be empty")
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, models.UserJwt{
+	user, err := service.userRepo.GetUserById(userCtx.Subject)
+	if err != nil {
+		return err
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(req.CurrentPassword)); err != nil {
+		return apperrors.Unauthorized("invalid_credentials", "invalid password").WithDetail("current_password", "does not match")
+	}
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(req.NewPassword), 12)
+	if err != nil {
+		return apperrors.Validation("password_too_long", "password too long").WithDetail("new_password", "at most 72 bytes")
+	}
+
+	// the login to keep has to be known before anything changes
+	session, err := service.sessionRepo.GetSessionByAccessToken(userCtx.ID)
+	if err != nil {
+		return err
+	}
+
+	return service.sessionRepo.ChangePassword(userCtx.Subject, string(hashedPassword), session.FamilyID.String())
+}
+
+// ForgotPassword mails a single use reset link to the account registered
+// with the email. Unknown and deactivated addresses get the same answer as
+// registered ones, and the link is stored and mailed in the background, so
+// neither the response nor how long it takes says whether an address has an
+// account.
+func (service *AuthService) ForgotPassword(req models.ForgotPasswordDTO) error {
+	if _, err := mail.ParseAddress(req.Email); err != nil {
+		return apperrors.Validation("invalid_email", "invalid email address").WithDetail("email", "not a valid address")
+	}
+
+	user, err := service.userRepo.GetUserByEmail(req.Email)
+	if apperrors.KindOf(err) == apperrors.KindNotFound {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	if user.DeactivatedAt != nil {
+		return nil
+	}
+
+	service.background.Add(1)
+	go func() {
+		defer service.background.Done()
+		if err := service.sendReset(user); err != nil {
+			log.Println(err)
+		}
+	}()
+	return nil
+}
+
+// Wait blocks until the reset links ForgotPassword handed to the background
+// are stored and mailed.
+func (service *AuthService) Wait() {
+	service.background.Wait()
+}
+
+func (service *AuthService) sendReset(user models.User) error {
+	token, err := newToken()
+	if err != nil {
+		return err
+	}
+	expiresAt := time.Now().Add(service.cfg.PasswordResetTTL)
+
+	if err := service.resetRepo.CreateReset(user.ID.String(), hashToken(token), expiresAt); err != nil {
+		return err
+	}
+
+	body := fmt.Sprintf("Hi %s,\n\n"+
+		"Someone asked to reset the password of your library account. If it was you, use this to choose a new one:\n\n"+
+		"%s\n\n"+
+		"It works once and expires at %s. If you didn't ask for a reset you can ignore this email.\n",
+		user.Name, link(service.cfg.PasswordResetURL, token), expiresAt.UTC().Format(time.RFC1123))
+
+	return service.mailer.Send(user.Email, "Reset your library password", body)
+}
+
+// ResetPassword sets a new password with a token from ForgotPassword and
+// signs the account out everywhere. The token can't be used again.
+func (service *AuthService) ResetPassword(req models.ResetPasswordDTO) error {
+	if req.Token == "" || req.NewPassword == "" {
+		return apperrors.Validation("missing_fields", "token and new password cant be empty")
+	}
+
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(req.NewPassword), 12)
+	if err != nil {
+		return apperrors.Validation("password_too_long", "password too long").WithDetail("new_password", "at most 72 bytes")
+	}
+
+	userId, err := service.resetRepo.UseReset(hashToken(req.Token))
+	if apperrors.KindOf(err) == apperrors.KindNotFound {
+		return errInvalidResetToken
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := service.userRepo.SetPassword(userId, string(hashedPassword)); err != nil {
+		return err
+	}
+
+	// whoever got locked out by guessing can't know the new password either
+	if err := service.userRepo.ClearLoginFailures(userId); err != nil {
+		return err
+	}
+
+	return service.sessionRepo.RevokeUserSessions(userId)
+}
+
+// JWKS publishes the public keys access tokens are verified with, for other
+// services to check them.
+func (service *AuthService) JWKS() models.JWKSDTO {
+	return service.keys.JWKS()
+}
+
+// finishLogin signs user in once every factor has been checked, and forgets
+// the failed logins before it.
+func (service *AuthService) finishLogin(user models.User) (models.TokenPairDTO, error) {
+	if user.FailedLoginCount > 0 {
+		if err := service.userRepo.ClearLoginFailures(user.ID.String()); err != nil {
+			log.Println(err)
+		}
+	}
+
+	tokens, session, err := service.issueTokens(user, uuid.New())
+	if err != nil {
+		return models.TokenPairDTO{}, err
+	}
+
+	if err := service.sessionRepo.CreateSession(session); err != nil {
+		return models.TokenPairDTO{}, err
+	}
+	return tokens, nil
+}
+
+// checkIP refuses logins from an address that failed too often lately.
+func (service *AuthService) checkIP(clientIP string) error {
+	lockout := service.cfg.Lockout
+	if lockout.IPMaxFailures == 0 {
+		return nil
+	}
+
+	failures, err := service.failedLoginRepo.CountFromIP(clientIP, time.Now().Add(-lockout.IPWindow))
+	if err != nil {
+		return err
+	}
+	if failures >= lockout.IPMaxFailures {
+		return tooManyAttempts(lockout.IPWindow)
+	}
+	return nil
+}
+
+// checkLocked refuses logins to an account that failed too often lately,
+// without looking at what was entered.
+func (service *AuthService) checkLocked(user models.User, clientIP string) error {
+	now := time.Now()
+	if user.LockedUntil == nil || !user.LockedUntil.After(now) {
+		return nil
+	}
+
+	service.recordFailedLogin(user.Email, &user.ID, clientIP, models.LoginLocked)
+	return tooManyAttempts(user.LockedUntil.Sub(now))
+}
+
+// loginFailed counts a wrong password or code against user and locks the
+// account for a while when it has failed too often.
+func (service *AuthService) loginFailed(user models.User, clientIP, reason string) {
+	service.recordFailedLogin(user.Email, &user.ID, clientIP, reason)
+
+	failures, err := service.userRepo.RecordLoginFailure(user.ID.String())
+	if err != nil {
+		log.Println(err)
+		return
+	}
+
+	if wait := lockFor(service.cfg.Lockout, failures); wait > 0 {
+		if err := service.userRepo.LockUntil(user.ID.String(), time.Now().Add(wait)); err != nil {
+			log.Println(err)
+		}
+	}
+}
+
+// recordFailedLogin adds a refused login to the audit log. Failing to record
+// it doesn't change the answer the client gets.
+func (service *AuthService) recordFailedLogin(email string, userId *uuid.UUID, ip, reason string) {
+	err := service.failedLoginRepo.RecordFailedLogin(models.FailedLogin{
+		Email:  email,
+		UserID: userId,
+		IP:     ip,
+		Reason: reason,
+	})
+	if err != nil {
+		log.Println(err)
+	}
+}
+
+// lockFor is how long an account that has failed to log in failures times in
+// a row has to wait before its password is checked again. The wait doubles
+// with every failure past DelayAfter and is LockFor from LockAfter on.
+func lockFor(lockout config.LockoutConfig, failures int) time.Duration {
+	if lockout.LockAfter > 0 && failures >= lockout.LockAfter {
+		return lockout.LockFor
+	}
+	if lockout.DelayAfter == 0 || failures < lockout.DelayAfter {
+		return 0
+	}
+
+	wait := lockout.Delay << min(failures-lockout.DelayAfter, 20)
+	if lockout.LockFor > 0 && wait > lockout.LockFor {
+		return lockout.LockFor
+	}
+	return wait
+}
+
+func tooManyAttempts(wait time.Duration) *apperrors.Error {
+	seconds := int(wait.Round(time.Second).Seconds())
+	return apperrors.TooManyRequests("too_many_attempts", "too many failed logins, try again later").
+		WithDetail("retry_after", strconv.Itoa(max(seconds, 1)))
+}
+
+// sendVerification mails a link confirming email belongs to userId, unless
+// one already went out within the resend interval.
+func (service *AuthService) sendVerification(userId, name, email string) error {
+	claimed, err := service.userRepo.ClaimVerificationEmail(userId, service.cfg.VerificationResendInterval)
+	if err != nil {
+		return err
+	}
+	if !claimed {
+		return errVerificationSent
+	}
+
+	expiresAt := time.Now().Add(service.cfg.EmailVerificationTTL)
+	token := service.signVerification(userId, email, expiresAt)
+
+	body := fmt.Sprintf("Hi %s,\n\n"+
+		"Please confirm this is your email address to start borrowing from the library:\n\n"+
+		"%s\n\n"+
+		"The link expires at %s. If you didn't sign up you can ignore this email.\n",
+		name, link(service.cfg.EmailVerificationURL, token), expiresAt.UTC().Format(time.RFC1123))
+
+	return service.mailer.Send(email, "Confirm your email address", body)
+}
+
+// signVerification returns a token vouching that email belonged to userId.
+// It is signed rather than stored, so nothing has to be cleaned up and a
+// changed email makes older links useless.
+func (service *AuthService) signVerification(userId, email string, expiresAt time.Time) string {
+	return service.sign("email-verification", expiresAt, userId, email)
+}
+
+// parseVerification checks a token from signVerification and returns the
+// user and email it vouches for.
+func (service *AuthService) parseVerification(token string, now time.Time) (string, string, error) {
+	fields, ok := service.parseSigned("email-verification", token, now, 2)
+	if !ok {
+		return "", "", errInvalidVerification
+	}
+	return fields[0], fields[1], nil
+}
+
+// sign returns a token carrying fields until expiresAt. Every purpose signs
+// with a key of its own derived from the jwt secret, so nothing else signed
+// with the secret passes for it.
+func (service *AuthService) sign(purpose string, expiresAt time.Time, fields ...string) string {
+	fields = append(fields, strconv.FormatInt(expiresAt.Unix(), 10))
+	payload := base64.RawURLEncoding.EncodeToString([]byte(strings.Join(fields, "\n")))
+	return payload + "." + base64.RawURLEncoding.EncodeToString(service.mac(purpose, payload))
+}
+
+// parseSigned checks a token from sign for purpose and returns the count
+// fields it carries.
+func (service *AuthService) parseSigned(purpose, token string, now time.Time, count int) ([]string, bool) {
+	payload, signature, ok := strings.Cut(token, ".")
+	if !ok {
+		return nil, false
+	}
+
+	mac, err := base64.RawURLEncoding.DecodeString(signature)
+	if err != nil || !hmac.Equal(mac, service.mac(purpose, payload)) {
+		return nil, false
+	}
+
+	raw, err := base64.RawURLEncoding.DecodeString(payload)
+	if err != nil {
+		return nil, false
+	}
+	fields := strings.Split(string(raw), "\n")
+	if len(fields) != count+1 {
+		return nil, false
+	}
+
+	expiresAt, err := strconv.ParseInt(fields[count], 10, 64)
+	if err != nil || !now.Before(time.Unix(expiresAt, 0)) {
+		return nil, false
+	}
+	return fields[:count], true
+}
+
+func (service *AuthService) mac(purpose, payload string) []byte {
+	mac := hmac.New(sha256.New, []byte(purpose+":"+service.cfg.JWTSecret))
+	mac.Write([]byte(payload))
+	return mac.Sum(nil)
+}
+
+// link is what an email points at, base with the token added or the bare
+// token when no page is configured.
+func link(base, token string) string {
+	if base == "" {
+		return token
+	}
+
+	u, err := url.Parse(base)
+	if err != nil {
+		return token
+	}
+	query := u.Query()
+	query.Set("token", token)
+	u.RawQuery = query.Encode()
+	return u.String()
+}
+
+// issueTokens mints an access token and a refresh token for user and returns
+// the session to store for the refresh token.
+func (service *AuthService) issueTokens(user models.User, familyId uuid.UUID) (models.TokenPairDTO, models.Session, error) {
+	now := time.Now()
+	jti := uuid.New()
+
+	accessToken, err := service.keys.Sign(models.UserJwt{
 		RegisteredClaims: jwt.RegisteredClaims{
-			ID:        user.ID.String(),
-			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour * 2)),
-			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			ID:        jti.String(),
+			Issuer:    service.cfg.JWT.Issuer,
+			Subject:   user.ID.String(),
+			Audience:  jwt.ClaimStrings{service.cfg.JWT.Audience},
+			ExpiresAt: jwt.NewNumericDate(now.Add(service.cfg.AccessTokenTTL)),
+			IssuedAt:  jwt.NewNumericDate(now),
 		},
 		Email: user.Email,
 		Role:  user.Role,
 	})
+	if err != nil {
+		return models.TokenPairDTO{}, models.Session{}, err
+	}
+
+	refreshToken, err := newToken()
+	if err != nil {
+		return models.TokenPairDTO{}, models.Session{}, err
+	}
+
+	session := models.Session{
+		UserID:          user.ID,
+		FamilyID:        familyId,
+		TokenHash:       hashToken(refreshToken),
+		AccessJTI:       jti,
+		AccessExpiresAt: now.Add(service.cfg.AccessTokenTTL),
+		ExpiresAt:       now.Add(service.cfg.RefreshTokenTTL),
+	}
 
-	return token.SignedString([]byte(config.JWTSecret))
+	return models.TokenPairDTO{AccessToken: accessToken, RefreshToken: refreshToken}, session, nil
 }
 
-func (service *AuthService) Signup(signupReq models.SignupDTO) error {
-	if signupReq.Name == "" || signupReq.Password == "" || signupReq.Email == "" {
-		return errors.New("name, email or password cant be empty")
+func toProfileDTO(user models.User) models.ProfileDTO {
+	dto := models.ProfileDTO{
+		ID:            user.ID.String(),
+		Name:          user.Name,
+		Email:         user.Email,
+		Role:          user.Role.String(),
+		CardNumber:    user.CardNumber,
+		EmailVerified: user.EmailVerifiedAt != nil,
+		MFAEnabled:    user.MFAEnabledAt != nil,
+	}
+	if user.MembershipExpiresAt != nil {
+		dto.MembershipExpiresAt = user.MembershipExpiresAt.String()
 	}
+	return dto
+}
 
-	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(signupReq.Password), 12)
-	if err != nil {
-		return errors.New("password too long")
+// newToken returns 32 random bytes encoded for use in urls.
+func newToken() (string, error) {
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return "", err
 	}
+	return base64.RawURLEncoding.EncodeToString(raw), nil
+}
 
-	return service.userRepo.AddUser(signupReq.Name, signupReq.Email, string(hashedPassword))
+// hashToken is how refresh and reset tokens are stored, so a leaked table
+// can't be replayed.
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
 }
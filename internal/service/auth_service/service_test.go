@@ -1,12 +1,25 @@
 package authservice
 
 import (
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
 	"errors"
 	"reflect"
+	"strings"
 	"testing"
+	"time"
 
+	apperrors "github.com/Kaushik1766/LibraryManagement/internal/app_errors"
 	"github.com/Kaushik1766/LibraryManagement/internal/config"
+	jwtkeys "github.com/Kaushik1766/LibraryManagement/internal/jwt_keys"
+	"github.com/Kaushik1766/LibraryManagement/internal/mailer"
 	"github.com/Kaushik1766/LibraryManagement/internal/models"
+	"github.com/Kaushik1766/LibraryManagement/internal/models/enums/roles"
+	failedloginrepo "github.com/Kaushik1766/LibraryManagement/internal/repository/failed_login_repo"
+	mfarepo "github.com/Kaushik1766/LibraryManagement/internal/repository/mfa_repo"
+	resetrepo "github.com/Kaushik1766/LibraryManagement/internal/repository/reset_repo"
+	sessionrepo "github.com/Kaushik1766/LibraryManagement/internal/repository/session_repo"
 	userrepo "github.com/Kaushik1766/LibraryManagement/internal/repository/user_repo"
 	"github.com/Kaushik1766/LibraryManagement/mocks"
 	"github.com/golang-jwt/jwt/v5"
@@ -15,15 +28,43 @@ import (
 	"golang.org/x/crypto/bcrypt"
 )
 
+var testAuthConfig = func() config.AuthConfig {
+	cfg := config.Default().Auth
+	cfg.JWTSecret = "auth-service-test-secret"
+	return cfg
+}()
+
+var testKeys = func() *jwtkeys.KeySet {
+	_, private, _ := ed25519.GenerateKey(rand.Reader)
+	key, _ := jwtkeys.NewKey("test", private)
+	keys, _ := jwtkeys.NewKeySet("test", key)
+	return keys
+}()
+
 func TestAuthService_Login(t *testing.T) {
 
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
 	mockUserRepo := mocks.NewMockUserStorage(ctrl)
+	mockSessionRepo := mocks.NewMockSessionStorage(ctrl)
+	mockFailedLoginRepo := mocks.NewMockFailedLoginStorage(ctrl)
+
+	hash, _ := bcrypt.GenerateFromPassword([]byte("123"), bcrypt.MinCost)
+	userId := uuid.New()
+	user := models.User{
+		ID:       userId,
+		Name:     "kaushik",
+		Password: string(hash),
+		Email:    "kaushik@a.com",
+		Role:     0,
+	}
+	const ip = "203.0.113.7"
 
 	type fields struct {
-		userRepo userrepo.UserStorage
+		userRepo        userrepo.UserStorage
+		sessionRepo     sessionrepo.SessionStorage
+		failedLoginRepo failedloginrepo.FailedLoginStorage
 	}
 	type args struct {
 		loginReq models.LoginDTO
@@ -32,24 +73,26 @@ func TestAuthService_Login(t *testing.T) {
 		name        string
 		fields      fields
 		args        args
-		checkOutput func(string) bool
+		checkOutput func(models.TokenPairDTO) bool
 		wantErr     bool
+		wantCode    string
 		mockSetup   func()
 	}{
 		{
 			name:   "valid login",
-			fields: fields{userRepo: mockUserRepo},
+			fields: fields{userRepo: mockUserRepo, sessionRepo: mockSessionRepo, failedLoginRepo: mockFailedLoginRepo},
 			args: args{
 				loginReq: models.LoginDTO{
 					Email:    "kaushik@a.com",
 					Password: "123",
 				},
 			},
-			checkOutput: func(token string) bool {
+			checkOutput: func(tokens models.TokenPairDTO) bool {
 				var userJwt models.UserJwt
-				jwtToken, err := jwt.ParseWithClaims(token, &userJwt, func(token *jwt.Token) (any, error) {
-					return []byte(config.JWTSecret), nil
-				})
+				jwtToken, err := jwt.ParseWithClaims(tokens.AccessToken, &userJwt, testKeys.Keyfunc,
+					jwt.WithIssuer(testAuthConfig.JWT.Issuer),
+					jwt.WithAudience(testAuthConfig.JWT.Audience),
+				)
 
 				if err != nil {
 					return false
@@ -59,7 +102,11 @@ func TestAuthService_Login(t *testing.T) {
 					return false
 				}
 
-				if userJwt.Email != "kaushik@a.com" {
+				if userJwt.Email != "kaushik@a.com" || userJwt.ID == "" {
+					return false
+				}
+
+				if tokens.RefreshToken == "" {
 					return false
 				}
 
@@ -67,71 +114,204 @@ func TestAuthService_Login(t *testing.T) {
 			},
 			wantErr: false,
 			mockSetup: func() {
-				mockUserRepo.EXPECT().GetUserByEmail("kaushik@a.com").Return(models.User{
-					ID:   uuid.New(),
-					Name: "kaushik",
-					Password: func() string {
-						hash, _ := bcrypt.GenerateFromPassword([]byte("123"), 12)
-						return string(hash)
-					}(),
-					Email: "kaushik@a.com",
-					Role:  0,
-				}, nil)
+				mockFailedLoginRepo.EXPECT().CountFromIP(ip, gomock.Any()).Return(0, nil)
+				mockUserRepo.EXPECT().GetUserByEmail("kaushik@a.com").Return(user, nil)
+				mockSessionRepo.EXPECT().CreateSession(gomock.Any()).Return(nil)
+			},
+		},
+		{
+			name:   "valid login clears earlier failures",
+			fields: fields{userRepo: mockUserRepo, sessionRepo: mockSessionRepo, failedLoginRepo: mockFailedLoginRepo},
+			args: args{
+				loginReq: models.LoginDTO{
+					Email:    "kaushik@a.com",
+					Password: "123",
+				},
+			},
+			checkOutput: func(tokens models.TokenPairDTO) bool {
+				return tokens.AccessToken != ""
+			},
+			wantErr: false,
+			mockSetup: func() {
+				lockedUntil := time.Now().Add(-time.Minute)
+				failed := user
+				failed.FailedLoginCount = 4
+				failed.LockedUntil = &lockedUntil
+				mockFailedLoginRepo.EXPECT().CountFromIP(ip, gomock.Any()).Return(0, nil)
+				mockUserRepo.EXPECT().GetUserByEmail("kaushik@a.com").Return(failed, nil)
+				mockUserRepo.EXPECT().ClearLoginFailures(userId.String()).Return(nil)
+				mockSessionRepo.EXPECT().CreateSession(gomock.Any()).Return(nil)
 			},
 		},
 		{
 			name:   "invalid login",
-			fields: fields{userRepo: mockUserRepo},
+			fields: fields{userRepo: mockUserRepo, sessionRepo: mockSessionRepo, failedLoginRepo: mockFailedLoginRepo},
 			args: args{
 				loginReq: models.LoginDTO{
 					Email:    "kaushik@a.com",
 					Password: "123",
 				},
 			},
-			checkOutput: func(token string) bool {
+			checkOutput: func(tokens models.TokenPairDTO) bool {
 				return true
 			},
 			wantErr: true,
 			mockSetup: func() {
+				mockFailedLoginRepo.EXPECT().CountFromIP(ip, gomock.Any()).Return(0, nil)
 				mockUserRepo.EXPECT().GetUserByEmail("kaushik@a.com").Return(models.User{}, errors.New("db error"))
 			},
 		},
+		{
+			name:   "deactivated account",
+			fields: fields{userRepo: mockUserRepo, sessionRepo: mockSessionRepo, failedLoginRepo: mockFailedLoginRepo},
+			args: args{
+				loginReq: models.LoginDTO{
+					Email:    "kaushik@a.com",
+					Password: "123",
+				},
+			},
+			checkOutput: func(tokens models.TokenPairDTO) bool {
+				return true
+			},
+			wantErr:  true,
+			wantCode: "account_deactivated",
+			mockSetup: func() {
+				deactivatedAt := time.Now().Add(-time.Hour)
+				deactivated := user
+				deactivated.DeactivatedAt = &deactivatedAt
+				mockFailedLoginRepo.EXPECT().CountFromIP(ip, gomock.Any()).Return(0, nil)
+				mockUserRepo.EXPECT().GetUserByEmail("kaushik@a.com").Return(deactivated, nil)
+			},
+		},
 		{
 			name:   "wrong password",
-			fields: fields{userRepo: mockUserRepo},
+			fields: fields{userRepo: mockUserRepo, sessionRepo: mockSessionRepo, failedLoginRepo: mockFailedLoginRepo},
 			args: args{
 				loginReq: models.LoginDTO{
 					Email:    "kaushik@a.com",
 					Password: "125",
 				},
 			},
-			checkOutput: func(token string) bool {
+			checkOutput: func(tokens models.TokenPairDTO) bool {
 				return true
 			},
-			wantErr: true,
+			wantErr:  true,
+			wantCode: "invalid_credentials",
 			mockSetup: func() {
-				mockUserRepo.EXPECT().GetUserByEmail("kaushik@a.com").Return(models.User{
-					ID:   uuid.New(),
-					Name: "kaushik",
-					Password: func() string {
-						hash, _ := bcrypt.GenerateFromPassword([]byte("123"), 12)
-						return string(hash)
-					}(),
-					Email: "kaushik@a.com",
-					Role:  0,
-				}, nil)
+				mockFailedLoginRepo.EXPECT().CountFromIP(ip, gomock.Any()).Return(0, nil)
+				mockUserRepo.EXPECT().GetUserByEmail("kaushik@a.com").Return(user, nil)
+				mockFailedLoginRepo.EXPECT().RecordFailedLogin(models.FailedLogin{
+					Email:  "kaushik@a.com",
+					UserID: &userId,
+					IP:     ip,
+					Reason: models.LoginInvalidPassword,
+				}).Return(nil)
+				mockUserRepo.EXPECT().RecordLoginFailure(userId.String()).Return(1, nil)
+			},
+		},
+		{
+			name:   "wrong password past the delay threshold",
+			fields: fields{userRepo: mockUserRepo, sessionRepo: mockSessionRepo, failedLoginRepo: mockFailedLoginRepo},
+			args: args{
+				loginReq: models.LoginDTO{
+					Email:    "kaushik@a.com",
+					Password: "125",
+				},
+			},
+			checkOutput: func(tokens models.TokenPairDTO) bool {
+				return true
+			},
+			wantErr:  true,
+			wantCode: "invalid_credentials",
+			mockSetup: func() {
+				mockFailedLoginRepo.EXPECT().CountFromIP(ip, gomock.Any()).Return(0, nil)
+				mockUserRepo.EXPECT().GetUserByEmail("kaushik@a.com").Return(user, nil)
+				mockFailedLoginRepo.EXPECT().RecordFailedLogin(gomock.Any()).Return(nil)
+				mockUserRepo.EXPECT().RecordLoginFailure(userId.String()).Return(testAuthConfig.Lockout.DelayAfter, nil)
+				mockUserRepo.EXPECT().LockUntil(userId.String(), gomock.Any()).Return(nil)
+			},
+		},
+		{
+			name:   "unknown email",
+			fields: fields{userRepo: mockUserRepo, sessionRepo: mockSessionRepo, failedLoginRepo: mockFailedLoginRepo},
+			args: args{
+				loginReq: models.LoginDTO{
+					Email:    "nobody@a.com",
+					Password: "125",
+				},
+			},
+			checkOutput: func(tokens models.TokenPairDTO) bool {
+				return true
+			},
+			wantErr:  true,
+			wantCode: "invalid_credentials",
+			mockSetup: func() {
+				mockFailedLoginRepo.EXPECT().CountFromIP(ip, gomock.Any()).Return(0, nil)
+				mockUserRepo.EXPECT().GetUserByEmail("nobody@a.com").Return(models.User{}, apperrors.NotFound("user_not_found", "user not found"))
+				mockFailedLoginRepo.EXPECT().RecordFailedLogin(models.FailedLogin{
+					Email:  "nobody@a.com",
+					IP:     ip,
+					Reason: models.LoginUnknownEmail,
+				}).Return(nil)
+			},
+		},
+		{
+			name:   "locked account",
+			fields: fields{userRepo: mockUserRepo, sessionRepo: mockSessionRepo, failedLoginRepo: mockFailedLoginRepo},
+			args: args{
+				loginReq: models.LoginDTO{
+					Email:    "kaushik@a.com",
+					Password: "123",
+				},
+			},
+			checkOutput: func(tokens models.TokenPairDTO) bool {
+				return true
+			},
+			wantErr:  true,
+			wantCode: "too_many_attempts",
+			mockSetup: func() {
+				lockedUntil := time.Now().Add(10 * time.Minute)
+				locked := user
+				locked.FailedLoginCount = 10
+				locked.LockedUntil = &lockedUntil
+				mockFailedLoginRepo.EXPECT().CountFromIP(ip, gomock.Any()).Return(0, nil)
+				mockUserRepo.EXPECT().GetUserByEmail("kaushik@a.com").Return(locked, nil)
+				mockFailedLoginRepo.EXPECT().RecordFailedLogin(models.FailedLogin{
+					Email:  "kaushik@a.com",
+					UserID: &userId,
+					IP:     ip,
+					Reason: models.LoginLocked,
+				}).Return(nil)
+			},
+		},
+		{
+			name:   "too many failures from the address",
+			fields: fields{userRepo: mockUserRepo, sessionRepo: mockSessionRepo, failedLoginRepo: mockFailedLoginRepo},
+			args: args{
+				loginReq: models.LoginDTO{
+					Email:    "kaushik@a.com",
+					Password: "123",
+				},
+			},
+			checkOutput: func(tokens models.TokenPairDTO) bool {
+				return true
+			},
+			wantErr:  true,
+			wantCode: "too_many_attempts",
+			mockSetup: func() {
+				mockFailedLoginRepo.EXPECT().CountFromIP(ip, gomock.Any()).Return(testAuthConfig.Lockout.IPMaxFailures, nil)
 			},
 		},
 		{
 			name:   "invalid email",
-			fields: fields{userRepo: mockUserRepo},
+			fields: fields{userRepo: mockUserRepo, sessionRepo: mockSessionRepo, failedLoginRepo: mockFailedLoginRepo},
 			args: args{
 				loginReq: models.LoginDTO{
 					Email:    "kaushika.com",
 					Password: "125",
 				},
 			},
-			checkOutput: func(token string) bool {
+			checkOutput: func(tokens models.TokenPairDTO) bool {
 				return true
 			},
 			wantErr: true,
@@ -140,14 +320,14 @@ func TestAuthService_Login(t *testing.T) {
 		},
 		{
 			name:   "incomplete fields",
-			fields: fields{userRepo: mockUserRepo},
+			fields: fields{userRepo: mockUserRepo, sessionRepo: mockSessionRepo, failedLoginRepo: mockFailedLoginRepo},
 			args: args{
 				loginReq: models.LoginDTO{
 					Email:    "",
 					Password: "125",
 				},
 			},
-			checkOutput: func(token string) bool {
+			checkOutput: func(tokens models.TokenPairDTO) bool {
 				return true
 			},
 			wantErr: true,
@@ -158,14 +338,22 @@ func TestAuthService_Login(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			service := &AuthService{
-				userRepo: tt.fields.userRepo,
+				userRepo:        tt.fields.userRepo,
+				sessionRepo:     tt.fields.sessionRepo,
+				failedLoginRepo: tt.fields.failedLoginRepo,
+				keys:            testKeys,
+				cfg:             testAuthConfig,
 			}
 			tt.mockSetup()
-			got, err := service.Login(tt.args.loginReq)
+			got, err := service.Login(tt.args.loginReq, ip)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("Login() error = %v, wantErr %v", err, tt.wantErr)
 				return
 			}
+			var appErr *apperrors.Error
+			if tt.wantCode != "" && (!errors.As(err, &appErr) || appErr.Code != tt.wantCode) {
+				t.Errorf("Login() error = %v, want code %v", err, tt.wantCode)
+			}
 			if !tt.checkOutput(got) {
 				t.Errorf("invalid jwt")
 			}
@@ -173,11 +361,49 @@ func TestAuthService_Login(t *testing.T) {
 	}
 }
 
+func TestLockFor(t *testing.T) {
+	lockout := config.LockoutConfig{
+		DelayAfter: 3,
+		Delay:      time.Second,
+		LockAfter:  10,
+		LockFor:    15 * time.Minute,
+	}
+
+	tests := []struct {
+		name     string
+		lockout  config.LockoutConfig
+		failures int
+		want     time.Duration
+	}{
+		{name: "below the delay threshold", lockout: lockout, failures: 2, want: 0},
+		{name: "at the delay threshold", lockout: lockout, failures: 3, want: time.Second},
+		{name: "doubles with each failure", lockout: lockout, failures: 5, want: 4 * time.Second},
+		{name: "locked at the lock threshold", lockout: lockout, failures: 10, want: 15 * time.Minute},
+		{name: "stays locked past it", lockout: lockout, failures: 40, want: 15 * time.Minute},
+		{
+			name:     "delay capped at the lock",
+			lockout:  config.LockoutConfig{DelayAfter: 1, Delay: time.Minute, LockFor: 5 * time.Minute},
+			failures: 60,
+			want:     5 * time.Minute,
+		},
+		{name: "turned off", lockout: config.LockoutConfig{}, failures: 100, want: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := lockFor(tt.lockout, tt.failures); got != tt.want {
+				t.Errorf("lockFor() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
 func TestAuthService_Signup(t *testing.T) {
 
 	ctrl := gomock.NewController(t)
 
 	mockUserRepo := mocks.NewMockUserStorage(ctrl)
+	mockMailer := mocks.NewMockMailer(ctrl)
+	userId := uuid.New().String()
 
 	type fields struct {
 		userRepo userrepo.UserStorage
@@ -204,7 +430,55 @@ func TestAuthService_Signup(t *testing.T) {
 			},
 			wantErr: false,
 			mockSetup: func() {
-				mockUserRepo.EXPECT().AddUser(gomock.Any(), gomock.Any(), gomock.Any()).Return(nil)
+				mockUserRepo.EXPECT().AddUser("kaushik", "kaushik@a.com", gomock.Any()).Return(userId, nil)
+				mockUserRepo.EXPECT().ClaimVerificationEmail(userId, testAuthConfig.VerificationResendInterval).Return(true, nil)
+				mockMailer.EXPECT().Send("kaushik@a.com", gomock.Any(), gomock.Any()).Return(nil)
+			},
+		},
+		{
+			name:   "mail failure still signs up",
+			fields: fields{userRepo: mockUserRepo},
+			args: args{
+				signupReq: models.SignupDTO{
+					Name:     "kaushik",
+					Email:    "kaushik@a.com",
+					Password: "123",
+				},
+			},
+			wantErr: false,
+			mockSetup: func() {
+				mockUserRepo.EXPECT().AddUser("kaushik", "kaushik@a.com", gomock.Any()).Return(userId, nil)
+				mockUserRepo.EXPECT().ClaimVerificationEmail(userId, gomock.Any()).Return(true, nil)
+				mockMailer.EXPECT().Send("kaushik@a.com", gomock.Any(), gomock.Any()).Return(errors.New("connection refused"))
+			},
+		},
+		{
+			name:   "invalid email",
+			fields: fields{userRepo: mockUserRepo},
+			args: args{
+				signupReq: models.SignupDTO{
+					Name:     "kaushik",
+					Email:    "kaushik",
+					Password: "123",
+				},
+			},
+			wantErr: true,
+			mockSetup: func() {
+			},
+		},
+		{
+			name:   "email taken",
+			fields: fields{userRepo: mockUserRepo},
+			args: args{
+				signupReq: models.SignupDTO{
+					Name:     "kaushik",
+					Email:    "kaushik@a.com",
+					Password: "123",
+				},
+			},
+			wantErr: true,
+			mockSetup: func() {
+				mockUserRepo.EXPECT().AddUser("kaushik", "kaushik@a.com", gomock.Any()).Return("", errors.New("email already registered"))
 			},
 		},
 		{
@@ -246,6 +520,8 @@ func TestAuthService_Signup(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			service := &AuthService{
 				userRepo: tt.fields.userRepo,
+				mailer:   mockMailer,
+				cfg:      testAuthConfig,
 			}
 			tt.mockSetup()
 			if err := service.Signup(tt.args.signupReq); (err != nil) != tt.wantErr {
@@ -259,8 +535,18 @@ func TestNewAuthService(t *testing.T) {
 	ctrl := gomock.NewController(t)
 
 	mockUserRepo := mocks.NewMockUserStorage(ctrl)
+	mockSessionRepo := mocks.NewMockSessionStorage(ctrl)
+	mockResetRepo := mocks.NewMockResetStorage(ctrl)
+	mockFailedLoginRepo := mocks.NewMockFailedLoginStorage(ctrl)
+	mockMFARepo := mocks.NewMockMFAStorage(ctrl)
+	mockMailer := mocks.NewMockMailer(ctrl)
 	type args struct {
-		userRepo userrepo.UserStorage
+		userRepo        userrepo.UserStorage
+		sessionRepo     sessionrepo.SessionStorage
+		resetRepo       resetrepo.ResetStorage
+		failedLoginRepo failedloginrepo.FailedLoginStorage
+		mfaRepo         mfarepo.MFAStorage
+		mailer          mailer.Mailer
 	}
 	tests := []struct {
 		name string
@@ -269,15 +555,839 @@ func TestNewAuthService(t *testing.T) {
 	}{
 		{
 			name: "valid",
-			args: args{userRepo: mockUserRepo},
-			want: &AuthService{userRepo: mockUserRepo},
+			args: args{userRepo: mockUserRepo, sessionRepo: mockSessionRepo, resetRepo: mockResetRepo, failedLoginRepo: mockFailedLoginRepo, mfaRepo: mockMFARepo, mailer: mockMailer},
+			want: &AuthService{userRepo: mockUserRepo, sessionRepo: mockSessionRepo, resetRepo: mockResetRepo, failedLoginRepo: mockFailedLoginRepo, mfaRepo: mockMFARepo, mailer: mockMailer, keys: testKeys, cfg: testAuthConfig},
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if got := NewAuthService(tt.args.userRepo); !reflect.DeepEqual(got, tt.want) {
+			if got := NewAuthService(tt.args.userRepo, tt.args.sessionRepo, tt.args.resetRepo, tt.args.failedLoginRepo, tt.args.mfaRepo, tt.args.mailer, testKeys, testAuthConfig); !reflect.DeepEqual(got, tt.want) {
 				t.Errorf("NewAuthService() = %v, want %v", got, tt.want)
 			}
 		})
 	}
 }
+
+func TestAuthService_Refresh(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUserRepo := mocks.NewMockUserStorage(ctrl)
+	mockSessionRepo := mocks.NewMockSessionStorage(ctrl)
+
+	user := models.User{ID: uuid.New(), Email: "kaushik@a.com", Role: roles.Librarian}
+	rotatedAt := time.Now().Add(-time.Minute)
+	session := models.Session{
+		ID:        uuid.New(),
+		UserID:    user.ID,
+		FamilyID:  uuid.New(),
+		TokenHash: hashToken("refresh"),
+		ExpiresAt: time.Now().Add(time.Hour),
+	}
+
+	tests := []struct {
+		name         string
+		refreshToken string
+		wantErr      bool
+		mockSetup    func()
+	}{
+		{
+			name:         "valid refresh rotates the token",
+			refreshToken: "refresh",
+			wantErr:      false,
+			mockSetup: func() {
+				mockSessionRepo.EXPECT().GetSessionByToken(hashToken("refresh")).Return(session, nil)
+				mockUserRepo.EXPECT().GetUserById(user.ID.String()).Return(user, nil)
+				mockSessionRepo.EXPECT().RotateSession(session.ID.String(), gomock.Any()).
+					DoAndReturn(func(sessionId string, next models.Session) error {
+						if next.FamilyID != session.FamilyID || next.TokenHash == session.TokenHash || !next.ExpiresAt.Equal(session.ExpiresAt) {
+							t.Errorf("RotateSession() got unexpected session %v", next)
+						}
+						return nil
+					})
+			},
+		},
+		{
+			name:         "empty token",
+			refreshToken: "",
+			wantErr:      true,
+			mockSetup:    func() {},
+		},
+		{
+			name:         "unknown token",
+			refreshToken: "refresh",
+			wantErr:      true,
+			mockSetup: func() {
+				mockSessionRepo.EXPECT().GetSessionByToken(hashToken("refresh")).Return(models.Session{}, errors.New("session not found"))
+			},
+		},
+		{
+			name:         "reused token revokes the family",
+			refreshToken: "refresh",
+			wantErr:      true,
+			mockSetup: func() {
+				reused := session
+				reused.RotatedAt = &rotatedAt
+				mockSessionRepo.EXPECT().GetSessionByToken(hashToken("refresh")).Return(reused, nil)
+				mockSessionRepo.EXPECT().RevokeFamily(session.FamilyID.String()).Return(nil)
+			},
+		},
+		{
+			name:         "revoked token",
+			refreshToken: "refresh",
+			wantErr:      true,
+			mockSetup: func() {
+				revoked := session
+				revoked.RevokedAt = &rotatedAt
+				mockSessionRepo.EXPECT().GetSessionByToken(hashToken("refresh")).Return(revoked, nil)
+			},
+		},
+		{
+			name:         "expired token",
+			refreshToken: "refresh",
+			wantErr:      true,
+			mockSetup: func() {
+				expired := session
+				expired.ExpiresAt = time.Now().Add(-time.Hour)
+				mockSessionRepo.EXPECT().GetSessionByToken(hashToken("refresh")).Return(expired, nil)
+			},
+		},
+		{
+			name:         "deactivated account",
+			refreshToken: "refresh",
+			wantErr:      true,
+			mockSetup: func() {
+				deactivated := user
+				deactivated.DeactivatedAt = &rotatedAt
+				mockSessionRepo.EXPECT().GetSessionByToken(hashToken("refresh")).Return(session, nil)
+				mockUserRepo.EXPECT().GetUserById(user.ID.String()).Return(deactivated, nil)
+			},
+		},
+		{
+			name:         "concurrent rotation",
+			refreshToken: "refresh",
+			wantErr:      true,
+			mockSetup: func() {
+				mockSessionRepo.EXPECT().GetSessionByToken(hashToken("refresh")).Return(session, nil)
+				mockUserRepo.EXPECT().GetUserById(user.ID.String()).Return(user, nil)
+				mockSessionRepo.EXPECT().RotateSession(session.ID.String(), gomock.Any()).Return(errors.New("refresh token already used"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := &AuthService{
+				userRepo:    mockUserRepo,
+				sessionRepo: mockSessionRepo,
+				keys:        testKeys,
+				cfg:         testAuthConfig,
+			}
+			tt.mockSetup()
+			got, err := service.Refresh(tt.refreshToken)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Refresh() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && (got.AccessToken == "" || got.RefreshToken == "" || got.RefreshToken == tt.refreshToken) {
+				t.Errorf("Refresh() got = %v", got)
+			}
+		})
+	}
+}
+
+func TestAuthService_Logout(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSessionRepo := mocks.NewMockSessionStorage(ctrl)
+
+	jti := uuid.New().String()
+	userId := uuid.New().String()
+	familyId := uuid.New()
+	ctx := context.WithValue(context.Background(), "user", models.UserJwt{
+		RegisteredClaims: jwt.RegisteredClaims{ID: jti, Subject: userId},
+	})
+
+	tests := []struct {
+		name      string
+		ctx       context.Context
+		wantErr   bool
+		mockSetup func()
+	}{
+		{
+			name:    "valid logout",
+			ctx:     ctx,
+			wantErr: false,
+			mockSetup: func() {
+				mockSessionRepo.EXPECT().GetSessionByAccessToken(jti).Return(models.Session{FamilyID: familyId}, nil)
+				mockSessionRepo.EXPECT().RevokeFamily(familyId.String()).Return(nil)
+			},
+		},
+		{
+			name:    "session not found",
+			ctx:     ctx,
+			wantErr: true,
+			mockSetup: func() {
+				mockSessionRepo.EXPECT().GetSessionByAccessToken(jti).Return(models.Session{}, errors.New("session not found"))
+			},
+		},
+		{
+			name:      "no user in context",
+			ctx:       context.Background(),
+			wantErr:   true,
+			mockSetup: func() {},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := &AuthService{
+				sessionRepo: mockSessionRepo,
+				cfg:         testAuthConfig,
+			}
+			tt.mockSetup()
+			if err := service.Logout(tt.ctx); (err != nil) != tt.wantErr {
+				t.Errorf("Logout() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestAuthService_LogoutAll(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSessionRepo := mocks.NewMockSessionStorage(ctrl)
+
+	userId := uuid.New().String()
+	ctx := context.WithValue(context.Background(), "user", models.UserJwt{
+		RegisteredClaims: jwt.RegisteredClaims{Subject: userId},
+	})
+
+	mockSessionRepo.EXPECT().RevokeUserSessions(userId).Return(nil)
+
+	service := &AuthService{
+		sessionRepo: mockSessionRepo,
+		cfg:         testAuthConfig,
+	}
+	if err := service.LogoutAll(ctx); err != nil {
+		t.Errorf("LogoutAll() error = %v", err)
+	}
+	if err := service.LogoutAll(context.Background()); err == nil {
+		t.Errorf("LogoutAll() without a user in context error = nil")
+	}
+}
+
+func TestAuthService_GetProfile(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUserRepo := mocks.NewMockUserStorage(ctrl)
+
+	expiresAt := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	user := models.User{
+		ID:                  uuid.New(),
+		Name:                "kaushik",
+		Email:               "kaushik@a.com",
+		Password:            "hash",
+		Role:                roles.Customer,
+		CardNumber:          "LC00000001",
+		MembershipExpiresAt: &expiresAt,
+	}
+	ctx := context.WithValue(context.Background(), "user", models.UserJwt{
+		RegisteredClaims: jwt.RegisteredClaims{Subject: user.ID.String()},
+	})
+
+	tests := []struct {
+		name      string
+		ctx       context.Context
+		want      models.ProfileDTO
+		wantErr   bool
+		mockSetup func()
+	}{
+		{
+			name: "valid profile",
+			ctx:  ctx,
+			want: models.ProfileDTO{
+				ID:                  user.ID.String(),
+				Name:                "kaushik",
+				Email:               "kaushik@a.com",
+				Role:                "Customer",
+				CardNumber:          "LC00000001",
+				MembershipExpiresAt: expiresAt.String(),
+			},
+			wantErr: false,
+			mockSetup: func() {
+				mockUserRepo.EXPECT().GetUserById(user.ID.String()).Return(user, nil)
+			},
+		},
+		{
+			name:      "invalid user context",
+			ctx:       context.Background(),
+			wantErr:   true,
+			mockSetup: func() {},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := &AuthService{
+				userRepo: mockUserRepo,
+				cfg:      testAuthConfig,
+			}
+			tt.mockSetup()
+			got, err := service.GetProfile(tt.ctx)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetProfile() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetProfile() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAuthService_UpdateProfile(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUserRepo := mocks.NewMockUserStorage(ctrl)
+	mockMailer := mocks.NewMockMailer(ctrl)
+
+	verifiedAt := time.Now()
+	user := models.User{ID: uuid.New(), Name: "kaushik", Email: "kaushik@a.com", Role: roles.Customer, EmailVerifiedAt: &verifiedAt}
+	ctx := context.WithValue(context.Background(), "user", models.UserJwt{
+		RegisteredClaims: jwt.RegisteredClaims{Subject: user.ID.String()},
+	})
+	name := "kaushik s"
+	email := "kaushik@b.com"
+	blank := " "
+	badEmail := "kaushik"
+
+	tests := []struct {
+		name         string
+		req          models.UpdateProfileDTO
+		wantVerified bool
+		wantErr      bool
+		mockSetup    func()
+	}{
+		{
+			name:         "new email has to be verified",
+			req:          models.UpdateProfileDTO{Name: &name, Email: &email},
+			wantVerified: false,
+			wantErr:      false,
+			mockSetup: func() {
+				mockUserRepo.EXPECT().GetUserById(user.ID.String()).Return(user, nil)
+				updated := user
+				updated.Name = name
+				updated.Email = email
+				mockUserRepo.EXPECT().UpdateUser(updated).Return(nil)
+				mockUserRepo.EXPECT().ClaimVerificationEmail(user.ID.String(), gomock.Any()).Return(true, nil)
+				mockMailer.EXPECT().Send(email, gomock.Any(), gomock.Any()).Return(nil)
+			},
+		},
+		{
+			name:         "name change keeps verification",
+			req:          models.UpdateProfileDTO{Name: &name},
+			wantVerified: true,
+			wantErr:      false,
+			mockSetup: func() {
+				mockUserRepo.EXPECT().GetUserById(user.ID.String()).Return(user, nil)
+				mockUserRepo.EXPECT().UpdateUser(gomock.Any()).Return(nil)
+			},
+		},
+		{
+			name:    "blank name",
+			req:     models.UpdateProfileDTO{Name: &blank},
+			wantErr: true,
+			mockSetup: func() {
+				mockUserRepo.EXPECT().GetUserById(user.ID.String()).Return(user, nil)
+			},
+		},
+		{
+			name:    "invalid email",
+			req:     models.UpdateProfileDTO{Email: &badEmail},
+			wantErr: true,
+			mockSetup: func() {
+				mockUserRepo.EXPECT().GetUserById(user.ID.String()).Return(user, nil)
+			},
+		},
+		{
+			name:    "email taken",
+			req:     models.UpdateProfileDTO{Email: &email},
+			wantErr: true,
+			mockSetup: func() {
+				mockUserRepo.EXPECT().GetUserById(user.ID.String()).Return(user, nil)
+				mockUserRepo.EXPECT().UpdateUser(gomock.Any()).Return(errors.New("email already registered"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := &AuthService{
+				userRepo: mockUserRepo,
+				mailer:   mockMailer,
+				cfg:      testAuthConfig,
+			}
+			tt.mockSetup()
+			got, err := service.UpdateProfile(ctx, tt.req)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("UpdateProfile() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err == nil && got.EmailVerified != tt.wantVerified {
+				t.Errorf("UpdateProfile() email verified = %v, want %v", got.EmailVerified, tt.wantVerified)
+			}
+		})
+	}
+}
+
+func TestAuthService_ChangePassword(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUserRepo := mocks.NewMockUserStorage(ctrl)
+	mockSessionRepo := mocks.NewMockSessionStorage(ctrl)
+
+	hash, _ := bcrypt.GenerateFromPassword([]byte("old password"), bcrypt.MinCost)
+	user := models.User{ID: uuid.New(), Password: string(hash)}
+	jti := uuid.New().String()
+	familyId := uuid.New()
+	ctx := context.WithValue(context.Background(), "user", models.UserJwt{
+		RegisteredClaims: jwt.RegisteredClaims{ID: jti, Subject: user.ID.String()},
+	})
+
+	tests := []struct {
+		name      string
+		req       models.ChangePasswordDTO
+		wantErr   bool
+		mockSetup func()
+	}{
+		{
+			name:    "valid change signs out other devices",
+			req:     models.ChangePasswordDTO{CurrentPassword: "old password", NewPassword: "new password"},
+			wantErr: false,
+			mockSetup: func() {
+				mockUserRepo.EXPECT().GetUserById(user.ID.String()).Return(user, nil)
+				mockSessionRepo.EXPECT().GetSessionByAccessToken(jti).Return(models.Session{FamilyID: familyId}, nil)
+				mockSessionRepo.EXPECT().ChangePassword(user.ID.String(), gomock.Any(), familyId.String()).
+					DoAndReturn(func(userId, password, familyId string) error {
+						if bcrypt.CompareHashAndPassword([]byte(password), []byte("new password")) != nil {
+							t.Errorf("ChangePassword() got a hash that doesn't match the new password")
+						}
+						return nil
+					})
+			},
+		},
+		{
+			name:      "missing new password",
+			req:       models.ChangePasswordDTO{CurrentPassword: "old password"},
+			wantErr:   true,
+			mockSetup: func() {},
+		},
+		{
+			name:    "wrong current password",
+			req:     models.ChangePasswordDTO{CurrentPassword: "guess", NewPassword: "new password"},
+			wantErr: true,
+			mockSetup: func() {
+				mockUserRepo.EXPECT().GetUserById(user.ID.String()).Return(user, nil)
+			},
+		},
+		{
+			name:    "repository error",
+			req:     models.ChangePasswordDTO{CurrentPassword: "old password", NewPassword: "new password"},
+			wantErr: true,
+			mockSetup: func() {
+				mockUserRepo.EXPECT().GetUserById(user.ID.String()).Return(user, nil)
+				mockSessionRepo.EXPECT().GetSessionByAccessToken(jti).Return(models.Session{FamilyID: familyId}, nil)
+				mockSessionRepo.EXPECT().ChangePassword(user.ID.String(), gomock.Any(), familyId.String()).Return(errors.New("database error"))
+			},
+		},
+		{
+			name:    "unknown session changes nothing",
+			req:     models.ChangePasswordDTO{CurrentPassword: "old password", NewPassword: "new password"},
+			wantErr: true,
+			mockSetup: func() {
+				mockUserRepo.EXPECT().GetUserById(user.ID.String()).Return(user, nil)
+				mockSessionRepo.EXPECT().GetSessionByAccessToken(jti).Return(models.Session{}, errors.New("session not found"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := &AuthService{
+				userRepo:    mockUserRepo,
+				sessionRepo: mockSessionRepo,
+				cfg:         testAuthConfig,
+			}
+			tt.mockSetup()
+			if err := service.ChangePassword(ctx, tt.req); (err != nil) != tt.wantErr {
+				t.Errorf("ChangePassword() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestAuthService_ForgotPassword(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUserRepo := mocks.NewMockUserStorage(ctrl)
+	mockResetRepo := mocks.NewMockResetStorage(ctrl)
+	mockMailer := mocks.NewMockMailer(ctrl)
+
+	user := models.User{ID: uuid.New(), Name: "kaushik", Email: "kaushik@a.com"}
+	deactivatedAt := time.Now()
+
+	cfg := testAuthConfig
+	cfg.PasswordResetURL = "https://library.example.com/reset-password"
+
+	tests := []struct {
+		name      string
+		email     string
+		wantErr   bool
+		mockSetup func()
+	}{
+		{
+			name:    "registered email gets a link",
+			email:   user.Email,
+			wantErr: false,
+			mockSetup: func() {
+				var tokenHash string
+				mockUserRepo.EXPECT().GetUserByEmail(user.Email).Return(user, nil)
+				mockResetRepo.EXPECT().CreateReset(user.ID.String(), gomock.Any(), gomock.Any()).
+					DoAndReturn(func(userId, hash string, expiresAt time.Time) error {
+						tokenHash = hash
+						if expiresAt.Before(time.Now().Add(cfg.PasswordResetTTL - time.Minute)) {
+							t.Errorf("CreateReset() expiresAt = %v, want about %v from now", expiresAt, cfg.PasswordResetTTL)
+						}
+						return nil
+					})
+				mockMailer.EXPECT().Send(user.Email, gomock.Any(), gomock.Any()).
+					DoAndReturn(func(to, subject, body string) error {
+						_, token, ok := strings.Cut(body, cfg.PasswordResetURL+"?token=")
+						if !ok {
+							t.Errorf("Send() body = %q, want a reset link", body)
+							return nil
+						}
+						token, _, _ = strings.Cut(token, "\n")
+						if hashToken(token) != tokenHash {
+							t.Errorf("Send() mailed a token that wasn't stored")
+						}
+						return nil
+					})
+			},
+		},
+		{
+			name:    "unknown email looks the same",
+			email:   "nobody@a.com",
+			wantErr: false,
+			mockSetup: func() {
+				mockUserRepo.EXPECT().GetUserByEmail("nobody@a.com").Return(models.User{}, apperrors.NotFound("user_not_found", "user not found"))
+			},
+		},
+		{
+			name:    "deactivated account gets nothing",
+			email:   user.Email,
+			wantErr: false,
+			mockSetup: func() {
+				deactivated := user
+				deactivated.DeactivatedAt = &deactivatedAt
+				mockUserRepo.EXPECT().GetUserByEmail(user.Email).Return(deactivated, nil)
+			},
+		},
+		{
+			name:    "mail failure is not reported",
+			email:   user.Email,
+			wantErr: false,
+			mockSetup: func() {
+				mockUserRepo.EXPECT().GetUserByEmail(user.Email).Return(user, nil)
+				mockResetRepo.EXPECT().CreateReset(user.ID.String(), gomock.Any(), gomock.Any()).Return(nil)
+				mockMailer.EXPECT().Send(user.Email, gomock.Any(), gomock.Any()).Return(errors.New("connection refused"))
+			},
+		},
+		{
+			name:      "invalid email",
+			email:     "kaushik",
+			wantErr:   true,
+			mockSetup: func() {},
+		},
+		{
+			name:    "storing the link fails quietly",
+			email:   user.Email,
+			wantErr: false,
+			mockSetup: func() {
+				mockUserRepo.EXPECT().GetUserByEmail(user.Email).Return(user, nil)
+				mockResetRepo.EXPECT().CreateReset(user.ID.String(), gomock.Any(), gomock.Any()).Return(errors.New("database error"))
+			},
+		},
+		{
+			name:    "repository error",
+			email:   user.Email,
+			wantErr: true,
+			mockSetup: func() {
+				mockUserRepo.EXPECT().GetUserByEmail(user.Email).Return(models.User{}, errors.New("database error"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := &AuthService{
+				userRepo:  mockUserRepo,
+				resetRepo: mockResetRepo,
+				mailer:    mockMailer,
+				cfg:       cfg,
+			}
+			tt.mockSetup()
+			if err := service.ForgotPassword(models.ForgotPasswordDTO{Email: tt.email}); (err != nil) != tt.wantErr {
+				t.Errorf("ForgotPassword() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			service.Wait()
+		})
+	}
+}
+
+func TestAuthService_ResetPassword(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUserRepo := mocks.NewMockUserStorage(ctrl)
+	mockSessionRepo := mocks.NewMockSessionStorage(ctrl)
+	mockResetRepo := mocks.NewMockResetStorage(ctrl)
+
+	userId := uuid.New().String()
+
+	tests := []struct {
+		name      string
+		req       models.ResetPasswordDTO
+		wantKind  apperrors.Kind
+		wantErr   bool
+		mockSetup func()
+	}{
+		{
+			name:    "valid reset signs out everywhere",
+			req:     models.ResetPasswordDTO{Token: "token", NewPassword: "new password"},
+			wantErr: false,
+			mockSetup: func() {
+				mockResetRepo.EXPECT().UseReset(hashToken("token")).Return(userId, nil)
+				mockUserRepo.EXPECT().SetPassword(userId, gomock.Any()).
+					DoAndReturn(func(userId, password string) error {
+						if bcrypt.CompareHashAndPassword([]byte(password), []byte("new password")) != nil {
+							t.Errorf("SetPassword() got a hash that doesn't match the new password")
+						}
+						return nil
+					})
+				mockUserRepo.EXPECT().ClearLoginFailures(userId).Return(nil)
+				mockSessionRepo.EXPECT().RevokeUserSessions(userId).Return(nil)
+			},
+		},
+		{
+			name:      "missing token",
+			req:       models.ResetPasswordDTO{NewPassword: "new password"},
+			wantKind:  apperrors.KindValidation,
+			wantErr:   true,
+			mockSetup: func() {},
+		},
+		{
+			name:     "used or expired token",
+			req:      models.ResetPasswordDTO{Token: "token", NewPassword: "new password"},
+			wantKind: apperrors.KindUnauthorized,
+			wantErr:  true,
+			mockSetup: func() {
+				mockResetRepo.EXPECT().UseReset(hashToken("token")).Return("", apperrors.NotFound("reset_not_found", "reset token not found"))
+			},
+		},
+		{
+			name:     "repository error",
+			req:      models.ResetPasswordDTO{Token: "token", NewPassword: "new password"},
+			wantKind: apperrors.KindInternal,
+			wantErr:  true,
+			mockSetup: func() {
+				mockResetRepo.EXPECT().UseReset(hashToken("token")).Return(userId, nil)
+				mockUserRepo.EXPECT().SetPassword(userId, gomock.Any()).Return(errors.New("database error"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := &AuthService{
+				userRepo:    mockUserRepo,
+				sessionRepo: mockSessionRepo,
+				resetRepo:   mockResetRepo,
+				cfg:         testAuthConfig,
+			}
+			tt.mockSetup()
+			err := service.ResetPassword(tt.req)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ResetPassword() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err != nil && apperrors.KindOf(err) != tt.wantKind {
+				t.Errorf("ResetPassword() kind = %v, want %v", apperrors.KindOf(err), tt.wantKind)
+			}
+		})
+	}
+}
+
+func TestAuthService_VerifyEmail(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUserRepo := mocks.NewMockUserStorage(ctrl)
+
+	service := &AuthService{
+		userRepo: mockUserRepo,
+		cfg:      testAuthConfig,
+	}
+	userId := uuid.New().String()
+	token := service.signVerification(userId, "kaushik@a.com", time.Now().Add(time.Hour))
+	expired := service.signVerification(userId, "kaushik@a.com", time.Now().Add(-time.Minute))
+
+	otherCfg := testAuthConfig
+	otherCfg.JWTSecret = "another-secret-entirely"
+	other := &AuthService{cfg: otherCfg}
+	forged := other.signVerification(userId, "kaushik@a.com", time.Now().Add(time.Hour))
+
+	tests := []struct {
+		name      string
+		token     string
+		wantKind  apperrors.Kind
+		wantErr   bool
+		mockSetup func()
+	}{
+		{
+			name:    "valid token",
+			token:   token,
+			wantErr: false,
+			mockSetup: func() {
+				mockUserRepo.EXPECT().MarkEmailVerified(userId, "kaushik@a.com").Return(nil)
+			},
+		},
+		{
+			name:     "email changed since",
+			token:    token,
+			wantKind: apperrors.KindUnauthorized,
+			wantErr:  true,
+			mockSetup: func() {
+				mockUserRepo.EXPECT().MarkEmailVerified(userId, "kaushik@a.com").Return(apperrors.NotFound("user_not_found", "user not found"))
+			},
+		},
+		{
+			name:      "expired token",
+			token:     expired,
+			wantKind:  apperrors.KindUnauthorized,
+			wantErr:   true,
+			mockSetup: func() {},
+		},
+		{
+			name:      "signed with another key",
+			token:     forged,
+			wantKind:  apperrors.KindUnauthorized,
+			wantErr:   true,
+			mockSetup: func() {},
+		},
+		{
+			name:      "tampered payload",
+			token:     "x" + token,
+			wantKind:  apperrors.KindUnauthorized,
+			wantErr:   true,
+			mockSetup: func() {},
+		},
+		{
+			name:      "missing token",
+			token:     "",
+			wantKind:  apperrors.KindValidation,
+			wantErr:   true,
+			mockSetup: func() {},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.mockSetup()
+			err := service.VerifyEmail(models.VerifyEmailDTO{Token: tt.token})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("VerifyEmail() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err != nil && apperrors.KindOf(err) != tt.wantKind {
+				t.Errorf("VerifyEmail() kind = %v, want %v", apperrors.KindOf(err), tt.wantKind)
+			}
+		})
+	}
+}
+
+func TestAuthService_ResendVerification(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUserRepo := mocks.NewMockUserStorage(ctrl)
+	mockMailer := mocks.NewMockMailer(ctrl)
+
+	user := models.User{ID: uuid.New(), Name: "kaushik", Email: "kaushik@a.com"}
+	verifiedAt := time.Now()
+	ctx := context.WithValue(context.Background(), "user", models.UserJwt{
+		RegisteredClaims: jwt.RegisteredClaims{Subject: user.ID.String()},
+	})
+
+	cfg := testAuthConfig
+	cfg.EmailVerificationURL = "https://library.example.com/verify"
+
+	tests := []struct {
+		name      string
+		wantKind  apperrors.Kind
+		wantErr   bool
+		mockSetup func()
+	}{
+		{
+			name:    "valid resend",
+			wantErr: false,
+			mockSetup: func() {
+				mockUserRepo.EXPECT().GetUserById(user.ID.String()).Return(user, nil)
+				mockUserRepo.EXPECT().ClaimVerificationEmail(user.ID.String(), cfg.VerificationResendInterval).Return(true, nil)
+				mockMailer.EXPECT().Send(user.Email, gomock.Any(), gomock.Any()).
+					DoAndReturn(func(to, subject, body string) error {
+						if !strings.Contains(body, cfg.EmailVerificationURL+"?token=") {
+							t.Errorf("Send() body = %q, want a verification link", body)
+						}
+						return nil
+					})
+			},
+		},
+		{
+			name:     "sent recently",
+			wantKind: apperrors.KindTooManyRequests,
+			wantErr:  true,
+			mockSetup: func() {
+				mockUserRepo.EXPECT().GetUserById(user.ID.String()).Return(user, nil)
+				mockUserRepo.EXPECT().ClaimVerificationEmail(user.ID.String(), gomock.Any()).Return(false, nil)
+			},
+		},
+		{
+			name:     "already verified",
+			wantKind: apperrors.KindConflict,
+			wantErr:  true,
+			mockSetup: func() {
+				verified := user
+				verified.EmailVerifiedAt = &verifiedAt
+				mockUserRepo.EXPECT().GetUserById(user.ID.String()).Return(verified, nil)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := &AuthService{
+				userRepo: mockUserRepo,
+				mailer:   mockMailer,
+				cfg:      cfg,
+			}
+			tt.mockSetup()
+			err := service.ResendVerification(ctx)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ResendVerification() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if err != nil && apperrors.KindOf(err) != tt.wantKind {
+				t.Errorf("ResendVerification() kind = %v, want %v", apperrors.KindOf(err), tt.wantKind)
+			}
+		})
+	}
+}
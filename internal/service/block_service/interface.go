@@ -0,0 +1,14 @@
+package blockservice
+
+import (
+	"context"
+
+	"github.com/Kaushik1766/LibraryManagement/internal/models"
+)
+
+//go:generate mockgen -source=interface.go -destination=../../../mocks/mock_block_manager.go -package=mocks
+type BlockManager interface {
+	GetBlocks(ctx context.Context, userId string) ([]models.BlockDTO, error)
+	PlaceBlock(ctx context.Context, userId string, req models.PlaceBlockDTO) (string, error)
+	LiftBlock(ctx context.Context, userId, blockId string) error
+}
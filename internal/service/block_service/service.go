@@ -0,0 +1,132 @@
+package blockservice
+
+import (
+	"context"
+	"time"
+	"unicode/utf8"
+
+	apperrors "github.com/Kaushik1766/LibraryManagement/internal/app_errors"
+	"github.com/Kaushik1766/LibraryManagement/internal/config"
+	"github.com/Kaushik1766/LibraryManagement/internal/models"
+	patronblocks "github.com/Kaushik1766/LibraryManagement/internal/patron_blocks"
+	"github.com/Kaushik1766/LibraryManagement/internal/rbac"
+	blockrepo "github.com/Kaushik1766/LibraryManagement/internal/repository/block_repo"
+	userrepo "github.com/Kaushik1766/LibraryManagement/internal/repository/user_repo"
+	"github.com/google/uuid"
+)
+
+type BlockService struct {
+	blockRepo blockrepo.BlockStorage
+	userRepo  userrepo.UserStorage
+	blocks    *patronblocks.Checker
+}
+
+func NewBlockService(blockRepo blockrepo.BlockStorage, userRepo userrepo.UserStorage, policy config.CirculationConfig) *BlockService {
+	return &BlockService{
+		blockRepo: blockRepo,
+		userRepo:  userRepo,
+		blocks:    patronblocks.NewChecker(blockRepo, policy),
+	}
+}
+
+// GetBlocks lists every block on a patron, manual and automatic. Patrons can
+// only see their own.
+func (service *BlockService) GetBlocks(ctx context.Context, userId string) ([]models.BlockDTO, error) {
+	userCtx, ok := ctx.Value("user").(models.UserJwt)
+	if !ok {
+		return nil, apperrors.ErrInvalidUser
+	}
+
+	if userId != userCtx.Subject && !rbac.Can(userCtx.Role, rbac.PatronView) {
+		return nil, apperrors.ErrUnauthorisedUser
+	}
+
+	if _, err := uuid.Parse(userId); err != nil {
+		return nil, apperrors.Validation("invalid_id", "invalid user id").WithDetail("user_id", "must be a uuid")
+	}
+
+	blocks, err := service.blocks.Blocks(userId)
+	if err != nil {
+		return nil, err
+	}
+
+	dtos := make([]models.BlockDTO, 0, len(blocks))
+	for _, block := range blocks {
+		dto := models.BlockDTO{
+			Kind:   string(block.Kind),
+			Reason: block.Reason,
+		}
+		if block.ID != uuid.Nil {
+			dto.ID = block.ID.String()
+			dto.PlacedAt = block.PlacedAt.String()
+		}
+		if block.PlacedBy != nil && rbac.Can(userCtx.Role, rbac.PatronView) {
+			dto.PlacedBy = block.PlacedBy.Email
+		}
+		if block.ExpiresAt != nil {
+			dto.ExpiresAt = block.ExpiresAt.String()
+		}
+		dtos = append(dtos, dto)
+	}
+
+	return dtos, nil
+}
+
+// PlaceBlock stops a patron from borrowing until the block expires or is
+// lifted, and returns the block id.
+func (service *BlockService) PlaceBlock(ctx context.Context, userId string, req models.PlaceBlockDTO) (string, error) {
+	userCtx, ok := ctx.Value("user").(models.UserJwt)
+	if !ok {
+		return "", apperrors.ErrInvalidUser
+	}
+
+	if !rbac.Can(userCtx.Role, rbac.PatronBlock) {
+		return "", apperrors.ErrUnauthorisedUser
+	}
+
+	if _, err := uuid.Parse(userId); err != nil {
+		return "", apperrors.Validation("invalid_id", "invalid user id").WithDetail("user_id", "must be a uuid")
+	}
+
+	if req.Reason == "" || utf8.RuneCountInString(req.Reason) > 255 {
+		return "", apperrors.Validation("missing_reason", "reason is required to block a patron").WithDetail("reason", "1 to 255 characters")
+	}
+
+	var expiresAt *time.Time
+	if req.ExpiresAt != "" {
+		parsed, err := time.Parse(time.RFC3339, req.ExpiresAt)
+		if err != nil {
+			return "", apperrors.Validation("invalid_expiry", "invalid expiry").WithDetail("expires_at", "must be an RFC 3339 time")
+		}
+		if !parsed.After(time.Now()) {
+			return "", apperrors.Validation("invalid_expiry", "expiry must be in the future").WithDetail("expires_at", "must be in the future")
+		}
+		expiresAt = &parsed
+	}
+
+	if _, err := service.userRepo.GetUserById(userId); err != nil {
+		return "", err
+	}
+
+	return service.blockRepo.PlaceBlock(userId, req.Reason, expiresAt, userCtx.Subject)
+}
+
+func (service *BlockService) LiftBlock(ctx context.Context, userId, blockId string) error {
+	userCtx, ok := ctx.Value("user").(models.UserJwt)
+	if !ok {
+		return apperrors.ErrInvalidUser
+	}
+
+	if !rbac.Can(userCtx.Role, rbac.PatronBlock) {
+		return apperrors.ErrUnauthorisedUser
+	}
+
+	if _, err := uuid.Parse(userId); err != nil {
+		return apperrors.Validation("invalid_id", "invalid user id").WithDetail("user_id", "must be a uuid")
+	}
+	if _, err := uuid.Parse(blockId); err != nil {
+		return apperrors.Validation("invalid_id", "invalid block id").WithDetail("block_id", "must be a uuid")
+	}
+
+	return service.blockRepo.LiftBlock(userId, blockId, userCtx.Subject)
+}
@@ -0,0 +1,331 @@
+package blockservice
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/Kaushik1766/LibraryManagement/internal/config"
+	"github.com/Kaushik1766/LibraryManagement/internal/models"
+	"github.com/Kaushik1766/LibraryManagement/internal/models/enums/blockkind"
+	"github.com/Kaushik1766/LibraryManagement/internal/models/enums/roles"
+	patronblocks "github.com/Kaushik1766/LibraryManagement/internal/patron_blocks"
+	"github.com/Kaushik1766/LibraryManagement/mocks"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"go.uber.org/mock/gomock"
+)
+
+var testPolicy = config.Default().Circulation
+
+func TestNewBlockService(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockBlockRepo := mocks.NewMockBlockStorage(ctrl)
+	mockUserRepo := mocks.NewMockUserStorage(ctrl)
+
+	want := &BlockService{
+		blockRepo: mockBlockRepo,
+		userRepo:  mockUserRepo,
+		blocks:    patronblocks.NewChecker(mockBlockRepo, testPolicy),
+	}
+	if got := NewBlockService(mockBlockRepo, mockUserRepo, testPolicy); !reflect.DeepEqual(got, want) {
+		t.Errorf("NewBlockService() = %v, want %v", got, want)
+	}
+}
+
+func TestBlockService_GetBlocks(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockBlockRepo := mocks.NewMockBlockStorage(ctrl)
+
+	patronId := uuid.New().String()
+	patronCtx := context.WithValue(context.Background(), "user", models.UserJwt{
+		RegisteredClaims: jwt.RegisteredClaims{Subject: patronId},
+		Role:             roles.Customer,
+	})
+	librarianCtx := context.WithValue(context.Background(), "user", models.UserJwt{
+		RegisteredClaims: jwt.RegisteredClaims{Subject: uuid.New().String()},
+		Role:             roles.Librarian,
+	})
+	placedAt := time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC)
+	manual := models.Block{
+		ID:       uuid.New(),
+		Kind:     blockkind.Manual,
+		Reason:   "lost a book",
+		PlacedBy: &models.User{Email: "librarian@example.com"},
+		PlacedAt: placedAt,
+	}
+
+	tests := []struct {
+		name      string
+		ctx       context.Context
+		userId    string
+		want      []models.BlockDTO
+		wantErr   bool
+		mockSetup func()
+	}{
+		{
+			name:   "patron sees own blocks without staff",
+			ctx:    patronCtx,
+			userId: patronId,
+			want: []models.BlockDTO{
+				{ID: manual.ID.String(), Kind: "manual", Reason: "lost a book", PlacedAt: placedAt.String()},
+				{Kind: "overdue", Reason: "4 overdue loans reach the limit of 3"},
+			},
+			wantErr: false,
+			mockSetup: func() {
+				mockBlockRepo.EXPECT().GetActiveBlocks(patronId).Return([]models.Block{manual}, nil)
+				mockBlockRepo.EXPECT().GetStanding(patronId).Return(models.Standing{OverdueLoans: 4}, nil)
+			},
+		},
+		{
+			name:   "staff sees who placed the block",
+			ctx:    librarianCtx,
+			userId: patronId,
+			want: []models.BlockDTO{
+				{ID: manual.ID.String(), Kind: "manual", Reason: "lost a book", PlacedBy: "librarian@example.com", PlacedAt: placedAt.String()},
+			},
+			wantErr: false,
+			mockSetup: func() {
+				mockBlockRepo.EXPECT().GetActiveBlocks(patronId).Return([]models.Block{manual}, nil)
+				mockBlockRepo.EXPECT().GetStanding(patronId).Return(models.Standing{}, nil)
+			},
+		},
+		{
+			name:      "patron cannot see other patrons",
+			ctx:       patronCtx,
+			userId:    uuid.New().String(),
+			wantErr:   true,
+			mockSetup: func() {},
+		},
+		{
+			name:      "invalid user id",
+			ctx:       librarianCtx,
+			userId:    "not-a-uuid",
+			wantErr:   true,
+			mockSetup: func() {},
+		},
+		{
+			name:      "invalid user context",
+			ctx:       context.Background(),
+			userId:    patronId,
+			wantErr:   true,
+			mockSetup: func() {},
+		},
+		{
+			name:    "repository error",
+			ctx:     librarianCtx,
+			userId:  patronId,
+			wantErr: true,
+			mockSetup: func() {
+				mockBlockRepo.EXPECT().GetActiveBlocks(patronId).Return(nil, errors.New("database error"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := &BlockService{
+				blockRepo: mockBlockRepo,
+				blocks:    patronblocks.NewChecker(mockBlockRepo, testPolicy),
+			}
+			tt.mockSetup()
+			got, err := service.GetBlocks(tt.ctx, tt.userId)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetBlocks() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetBlocks() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBlockService_PlaceBlock(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockBlockRepo := mocks.NewMockBlockStorage(ctrl)
+	mockUserRepo := mocks.NewMockUserStorage(ctrl)
+
+	staffId := uuid.New().String()
+	librarianCtx := context.WithValue(context.Background(), "user", models.UserJwt{
+		RegisteredClaims: jwt.RegisteredClaims{Subject: staffId},
+		Role:             roles.Librarian,
+	})
+	staffCtx := context.WithValue(context.Background(), "user", models.UserJwt{
+		RegisteredClaims: jwt.RegisteredClaims{Subject: staffId},
+		Role:             roles.Staff,
+	})
+	patronId := uuid.New().String()
+	blockId := uuid.New().String()
+	tomorrow := time.Now().Add(24 * time.Hour).UTC().Truncate(time.Second)
+
+	tests := []struct {
+		name      string
+		ctx       context.Context
+		userId    string
+		req       models.PlaceBlockDTO
+		want      string
+		wantErr   bool
+		mockSetup func()
+	}{
+		{
+			name:    "valid block",
+			ctx:     staffCtx,
+			userId:  patronId,
+			req:     models.PlaceBlockDTO{Reason: "lost a book"},
+			want:    blockId,
+			wantErr: false,
+			mockSetup: func() {
+				mockUserRepo.EXPECT().GetUserById(patronId).Return(models.User{}, nil)
+				mockBlockRepo.EXPECT().PlaceBlock(patronId, "lost a book", nil, staffId).Return(blockId, nil)
+			},
+		},
+		{
+			name:    "block with expiry",
+			ctx:     staffCtx,
+			userId:  patronId,
+			req:     models.PlaceBlockDTO{Reason: "lost a book", ExpiresAt: tomorrow.Format(time.RFC3339)},
+			want:    blockId,
+			wantErr: false,
+			mockSetup: func() {
+				mockUserRepo.EXPECT().GetUserById(patronId).Return(models.User{}, nil)
+				mockBlockRepo.EXPECT().PlaceBlock(patronId, "lost a book", &tomorrow, staffId).Return(blockId, nil)
+			},
+		},
+		{
+			name:      "librarian cannot block",
+			ctx:       librarianCtx,
+			userId:    patronId,
+			req:       models.PlaceBlockDTO{Reason: "lost a book"},
+			wantErr:   true,
+			mockSetup: func() {},
+		},
+		{
+			name:      "missing reason",
+			ctx:       staffCtx,
+			userId:    patronId,
+			req:       models.PlaceBlockDTO{},
+			wantErr:   true,
+			mockSetup: func() {},
+		},
+		{
+			name:      "expiry in the past",
+			ctx:       staffCtx,
+			userId:    patronId,
+			req:       models.PlaceBlockDTO{Reason: "lost a book", ExpiresAt: "2020-01-01T00:00:00Z"},
+			wantErr:   true,
+			mockSetup: func() {},
+		},
+		{
+			name:      "invalid expiry",
+			ctx:       staffCtx,
+			userId:    patronId,
+			req:       models.PlaceBlockDTO{Reason: "lost a book", ExpiresAt: "next week"},
+			wantErr:   true,
+			mockSetup: func() {},
+		},
+		{
+			name:    "unknown patron",
+			ctx:     staffCtx,
+			userId:  patronId,
+			req:     models.PlaceBlockDTO{Reason: "lost a book"},
+			wantErr: true,
+			mockSetup: func() {
+				mockUserRepo.EXPECT().GetUserById(patronId).Return(models.User{}, errors.New("user not found"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := &BlockService{
+				blockRepo: mockBlockRepo,
+				userRepo:  mockUserRepo,
+			}
+			tt.mockSetup()
+			got, err := service.PlaceBlock(tt.ctx, tt.userId, tt.req)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("PlaceBlock() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("PlaceBlock() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBlockService_LiftBlock(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockBlockRepo := mocks.NewMockBlockStorage(ctrl)
+
+	staffId := uuid.New().String()
+	staffCtx := context.WithValue(context.Background(), "user", models.UserJwt{
+		RegisteredClaims: jwt.RegisteredClaims{Subject: staffId},
+		Role:             roles.Staff,
+	})
+	patronId := uuid.New().String()
+	blockId := uuid.New().String()
+
+	tests := []struct {
+		name      string
+		ctx       context.Context
+		blockId   string
+		wantErr   bool
+		mockSetup func()
+	}{
+		{
+			name:    "valid lift",
+			ctx:     staffCtx,
+			blockId: blockId,
+			wantErr: false,
+			mockSetup: func() {
+				mockBlockRepo.EXPECT().LiftBlock(patronId, blockId, staffId).Return(nil)
+			},
+		},
+		{
+			name:    "customer cannot lift",
+			ctx:     context.WithValue(context.Background(), "user", models.UserJwt{Role: roles.Customer}),
+			blockId: blockId,
+			wantErr: true,
+			mockSetup: func() {
+			},
+		},
+		{
+			name:    "invalid block id",
+			ctx:     staffCtx,
+			blockId: "not-a-uuid",
+			wantErr: true,
+			mockSetup: func() {
+			},
+		},
+		{
+			name:    "block not found",
+			ctx:     staffCtx,
+			blockId: blockId,
+			wantErr: true,
+			mockSetup: func() {
+				mockBlockRepo.EXPECT().LiftBlock(patronId, blockId, staffId).Return(errors.New("block not found"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := &BlockService{
+				blockRepo: mockBlockRepo,
+			}
+			tt.mockSetup()
+			if err := service.LiftBlock(tt.ctx, patronId, tt.blockId); (err != nil) != tt.wantErr {
+				t.Errorf("LiftBlock() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
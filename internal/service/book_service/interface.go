@@ -2,12 +2,25 @@ package bookservice
 
 import (
 	"context"
+	"io"
 
+	"github.com/Kaushik1766/LibraryManagement/internal/marc"
 	"github.com/Kaushik1766/LibraryManagement/internal/models"
+	"github.com/Kaushik1766/LibraryManagement/internal/pagination"
 )
 
 //go:generate mockgen -source=interface.go -destination=../../../mocks/mock_book_manager.go -package=mocks
 type BookManager interface {
-	AddBook(ctx context.Context, bookReq models.AddBookDTO) error
-	GetAllBooks(ctx context.Context, title, author string) ([]models.BookDTO, error)
+	AddBook(ctx context.Context, bookReq models.AddBookDTO) (string, error)
+	ImportBooks(ctx context.Context, format string, data io.Reader, dryRun bool) (models.ImportReport, error)
+	GetAllBooks(ctx context.Context, title, author string, page pagination.Request) (pagination.Page[models.BookDTO], error)
+	SearchBooks(ctx context.Context, search models.BookSearch, page pagination.Request) (models.SearchResultDTO, error)
+	GetBook(ctx context.Context, bookId string) (models.BookDTO, error)
+	ExportBook(ctx context.Context, bookId string) (marc.Record, error)
+	ExportCatalog(ctx context.Context, enc marc.Encoder) error
+	UpdateBook(ctx context.Context, bookId string, req models.UpdateBookDTO) (models.BookDTO, error)
+	WithdrawBook(ctx context.Context, bookId, reason string) error
+	GetCopies(ctx context.Context, bookId string) ([]models.CopyDTO, error)
+	AddCopies(ctx context.Context, bookId string, req models.AddCopiesDTO) error
+	WithdrawCopy(ctx context.Context, bookId, copyId, reason string) error
 }
@@ -2,11 +2,17 @@ package bookservice
 
 import (
 	"context"
-	"errors"
+	"io"
+	"strings"
 
+	apperrors "github.com/Kaushik1766/LibraryManagement/internal/app_errors"
+	catalogimport "github.com/Kaushik1766/LibraryManagement/internal/catalog_import"
+	"github.com/Kaushik1766/LibraryManagement/internal/marc"
 	"github.com/Kaushik1766/LibraryManagement/internal/models"
-	"github.com/Kaushik1766/LibraryManagement/internal/models/enums/roles"
+	"github.com/Kaushik1766/LibraryManagement/internal/pagination"
+	"github.com/Kaushik1766/LibraryManagement/internal/rbac"
 	bookrepo "github.com/Kaushik1766/LibraryManagement/internal/repository/book_repo"
+	"github.com/google/uuid"
 )
 
 type BookService struct {
@@ -19,64 +25,346 @@ func NewBookService(bookRepo bookrepo.BookStorage) *BookService {
 	}
 }
 
-func (service *BookService) AddBook(ctx context.Context, bookReq models.AddBookDTO) error {
-	if bookReq.Title == "" || bookReq.Author == "" || bookReq.Copies <= 0 {
-		return errors.New("invalid input")
+func (service *BookService) AddBook(ctx context.Context, bookReq models.AddBookDTO) (string, error) {
+	bookReq.ItemType = strings.ToLower(strings.TrimSpace(bookReq.ItemType))
+	if bookReq.Title == "" || bookReq.Author == "" || bookReq.Copies <= 0 || bookReq.Year < 0 || len(bookReq.ItemType) > 32 {
+		return "", apperrors.Validation("invalid_input", "invalid input")
 	}
 
 	userCtx, ok := ctx.Value("user").(models.UserJwt)
 	if !ok {
-		return errors.New("invalid context")
+		return "", apperrors.ErrInvalidUser
 	}
 
-	if userCtx.Role != roles.Staff {
-		return errors.New("unauthorised user")
+	if !rbac.Can(userCtx.Role, rbac.BookCreate) {
+		return "", apperrors.ErrUnauthorisedUser
 	}
 
-	return service.bookRepo.AddBook(bookReq.Title, bookReq.Author, bookReq.Copies)
+	book := models.Book{
+		Title:       bookReq.Title,
+		Author:      bookReq.Author,
+		ISBN:        bookReq.ISBN,
+		Publisher:   bookReq.Publisher,
+		Year:        bookReq.Year,
+		Language:    bookReq.Language,
+		Subject:     bookReq.Subject,
+		Description: bookReq.Description,
+		ItemType:    bookReq.ItemType,
+	}
+
+	return service.bookRepo.AddBook(book, bookReq.Copies, bookReq.Location)
 }
 
-func (service *BookService) GetAllBooks(ctx context.Context, title, author string) ([]models.BookDTO, error) {
+// ImportBooks adds the titles in a CSV or JSON lines file. Every row is
+// checked before anything is written and the whole file goes in one
+// transaction, or none of it does.
+func (service *BookService) ImportBooks(ctx context.Context, format string, data io.Reader, dryRun bool) (models.ImportReport, error) {
 	userCtx, ok := ctx.Value("user").(models.UserJwt)
 	if !ok {
-		return nil, errors.New("invalid context")
+		return models.ImportReport{}, apperrors.ErrInvalidUser
 	}
 
-	books, err := service.bookRepo.GetAllBooks(title, author)
+	if !rbac.Can(userCtx.Role, rbac.BookImport) {
+		return models.ImportReport{}, apperrors.ErrUnauthorisedUser
+	}
+
+	importFormat, err := catalogimport.FormatOf(format)
 	if err != nil {
-		return nil, err
+		return models.ImportReport{}, err
+	}
+
+	rows, err := catalogimport.Parse(importFormat, data)
+	if err != nil {
+		return models.ImportReport{}, err
+	}
+
+	return catalogimport.Run(service.bookRepo, rows, dryRun)
+}
+
+func (service *BookService) GetAllBooks(ctx context.Context, title, author string, page pagination.Request) (pagination.Page[models.BookDTO], error) {
+	_, ok := ctx.Value("user").(models.UserJwt)
+	if !ok {
+		return pagination.Page[models.BookDTO]{}, apperrors.ErrInvalidUser
 	}
 
-	var bookResponse []models.BookDTO
-	if userCtx.Role == roles.Staff {
-		for _, val := range books {
-			if val.IssuedTo == nil {
-				bookResponse = append(bookResponse, models.BookDTO{
-					ID:       val.ID.String(),
-					Title:    val.Title,
-					Author:   val.Author,
-					IssuedTo: "none",
-				})
-			} else {
-				bookResponse = append(bookResponse, models.BookDTO{
-					ID:       val.ID.String(),
-					Title:    val.Title,
-					Author:   val.Author,
-					IssuedTo: val.IssuedTo.Email,
-				})
+	books, err := service.bookRepo.GetAllBooks(title, author, page)
+	if err != nil {
+		return pagination.Page[models.BookDTO]{}, err
+	}
+
+	return pagination.Map(books, toBookDTO), nil
+}
+
+// SearchBooks runs a ranked catalog search. Facet filters narrow the hits and
+// the facet counts alike.
+func (service *BookService) SearchBooks(ctx context.Context, search models.BookSearch, page pagination.Request) (models.SearchResultDTO, error) {
+	_, ok := ctx.Value("user").(models.UserJwt)
+	if !ok {
+		return models.SearchResultDTO{}, apperrors.ErrInvalidUser
+	}
+
+	if strings.TrimSpace(search.Query) == "" {
+		return models.SearchResultDTO{}, apperrors.Validation("missing_query", "search query is required").WithDetail("q", "required")
+	}
+
+	if search.Available != "" && search.Available != "true" && search.Available != "false" {
+		return models.SearchResultDTO{}, apperrors.Validation("invalid_input", "invalid input").WithDetail("available", "must be true or false")
+	}
+
+	result, err := service.bookRepo.SearchBooks(search, page)
+	if err != nil {
+		return models.SearchResultDTO{}, err
+	}
+
+	return models.SearchResultDTO{
+		Page: pagination.Map(result.Hits, func(hit models.SearchHit) models.SearchHitDTO {
+			return models.SearchHitDTO{
+				BookDTO: toBookDTO(hit.Book),
+				Rank:    hit.Rank,
+				Snippet: hit.Snippet,
 			}
+		}),
+		Facets: result.Facets,
+	}, nil
+}
+
+func (service *BookService) GetBook(ctx context.Context, bookId string) (models.BookDTO, error) {
+	_, ok := ctx.Value("user").(models.UserJwt)
+	if !ok {
+		return models.BookDTO{}, apperrors.ErrInvalidUser
+	}
+
+	if _, err := uuid.Parse(bookId); err != nil {
+		return models.BookDTO{}, apperrors.Validation("invalid_id", "invalid book id").WithDetail("book_id", "required")
+	}
+
+	book, err := service.bookRepo.GetBookById(bookId)
+	if err != nil {
+		return models.BookDTO{}, err
+	}
+
+	return toBookDTO(book), nil
+}
+
+// ExportBook returns the MARC record of a title.
+func (service *BookService) ExportBook(ctx context.Context, bookId string) (marc.Record, error) {
+	_, ok := ctx.Value("user").(models.UserJwt)
+	if !ok {
+		return marc.Record{}, apperrors.ErrInvalidUser
+	}
+
+	if _, err := uuid.Parse(bookId); err != nil {
+		return marc.Record{}, apperrors.Validation("invalid_id", "invalid book id").WithDetail("book_id", "required")
+	}
+
+	book, err := service.bookRepo.GetBookById(bookId)
+	if err != nil {
+		return marc.Record{}, err
+	}
+
+	return marc.FromBook(book), nil
+}
+
+// ExportCatalog writes a MARC record for every title to enc. The caller
+// closes enc once this returns without an error.
+func (service *BookService) ExportCatalog(ctx context.Context, enc marc.Encoder) error {
+	userCtx, ok := ctx.Value("user").(models.UserJwt)
+	if !ok {
+		return apperrors.ErrInvalidUser
+	}
+
+	if !rbac.Can(userCtx.Role, rbac.BookExport) {
+		return apperrors.ErrUnauthorisedUser
+	}
+
+	return service.bookRepo.EachBook(func(book models.Book) error {
+		return enc.Encode(marc.FromBook(book))
+	})
+}
+
+// UpdateBook applies the fields present in req to the title and returns the
+// updated record.
+func (service *BookService) UpdateBook(ctx context.Context, bookId string, req models.UpdateBookDTO) (models.BookDTO, error) {
+	userCtx, ok := ctx.Value("user").(models.UserJwt)
+	if !ok {
+		return models.BookDTO{}, apperrors.ErrInvalidUser
+	}
+
+	if !rbac.Can(userCtx.Role, rbac.BookUpdate) {
+		return models.BookDTO{}, apperrors.ErrUnauthorisedUser
+	}
+
+	if _, err := uuid.Parse(bookId); err != nil {
+		return models.BookDTO{}, apperrors.Validation("invalid_id", "invalid book id").WithDetail("book_id", "required")
+	}
+
+	book, err := service.bookRepo.GetBookById(bookId)
+	if err != nil {
+		return models.BookDTO{}, err
+	}
+
+	if req.Title != nil {
+		book.Title = *req.Title
+	}
+	if req.Author != nil {
+		book.Author = *req.Author
+	}
+	if req.ISBN != nil {
+		book.ISBN = *req.ISBN
+	}
+	if req.Publisher != nil {
+		book.Publisher = *req.Publisher
+	}
+	if req.Year != nil {
+		book.Year = *req.Year
+	}
+	if req.Language != nil {
+		book.Language = *req.Language
+	}
+	if req.Subject != nil {
+		book.Subject = *req.Subject
+	}
+	if req.Description != nil {
+		book.Description = *req.Description
+	}
+	if req.ItemType != nil {
+		book.ItemType = strings.ToLower(strings.TrimSpace(*req.ItemType))
+		if book.ItemType == "" || len(book.ItemType) > 32 {
+			return models.BookDTO{}, apperrors.Validation("invalid_input", "invalid input").WithDetail("item_type", "must be 1 to 32 characters")
 		}
-	} else {
-		for _, val := range books {
-			if val.IssuedTo == nil {
-				bookResponse = append(bookResponse, models.BookDTO{
-					ID:     val.ID.String(),
-					Title:  val.Title,
-					Author: val.Author,
-				})
-			}
+	}
+
+	if book.Title == "" || book.Author == "" || book.Year < 0 {
+		return models.BookDTO{}, apperrors.Validation("invalid_input", "invalid input")
+	}
+
+	if err := service.bookRepo.UpdateBook(book); err != nil {
+		return models.BookDTO{}, err
+	}
+
+	return toBookDTO(book), nil
+}
+
+// WithdrawBook retires a title and all of its copies. Loan history is kept.
+func (service *BookService) WithdrawBook(ctx context.Context, bookId, reason string) error {
+	userCtx, ok := ctx.Value("user").(models.UserJwt)
+	if !ok {
+		return apperrors.ErrInvalidUser
+	}
+
+	if !rbac.Can(userCtx.Role, rbac.BookDelete) {
+		return apperrors.ErrUnauthorisedUser
+	}
+
+	if _, err := uuid.Parse(bookId); err != nil {
+		return apperrors.Validation("invalid_id", "invalid book id").WithDetail("book_id", "required")
+	}
+
+	if reason == "" {
+		return apperrors.Validation("missing_reason", "reason is required").WithDetail("reason", "required")
+	}
+
+	return service.bookRepo.WithdrawBook(bookId, reason)
+}
+
+// GetCopies lists the physical copies of a title. Who holds a copy on loan is
+// only shown to users allowed to view patrons.
+func (service *BookService) GetCopies(ctx context.Context, bookId string) ([]models.CopyDTO, error) {
+	userCtx, ok := ctx.Value("user").(models.UserJwt)
+	if !ok {
+		return nil, apperrors.ErrInvalidUser
+	}
+
+	if _, err := uuid.Parse(bookId); err != nil {
+		return nil, apperrors.Validation("invalid_id", "invalid book id").WithDetail("book_id", "required")
+	}
+
+	copies, err := service.bookRepo.GetCopies(bookId)
+	if err != nil {
+		return nil, err
+	}
+
+	var copyResponse []models.CopyDTO
+	for _, val := range copies {
+		dto := models.CopyDTO{
+			ID:       val.ID.String(),
+			BookID:   val.BookID.String(),
+			Barcode:  val.Barcode,
+			Status:   string(val.Status),
+			Location: val.Location,
+		}
+		if rbac.Can(userCtx.Role, rbac.PatronView) && val.IssuedTo != nil {
+			dto.IssuedTo = val.IssuedTo.Email
+		}
+		if val.WithdrawnAt != nil {
+			dto.WithdrawnAt = val.WithdrawnAt.String()
+			dto.WithdrawnReason = val.WithdrawnReason
 		}
+		copyResponse = append(copyResponse, dto)
+	}
+
+	return copyResponse, nil
+}
+
+func (service *BookService) AddCopies(ctx context.Context, bookId string, req models.AddCopiesDTO) error {
+	if req.Copies <= 0 {
+		return apperrors.Validation("invalid_input", "invalid input")
+	}
+
+	if _, err := uuid.Parse(bookId); err != nil {
+		return apperrors.Validation("invalid_id", "invalid book id").WithDetail("book_id", "required")
+	}
+
+	userCtx, ok := ctx.Value("user").(models.UserJwt)
+	if !ok {
+		return apperrors.ErrInvalidUser
+	}
+
+	if !rbac.Can(userCtx.Role, rbac.BookCreate) {
+		return apperrors.ErrUnauthorisedUser
 	}
 
-	return bookResponse, nil
+	return service.bookRepo.AddCopies(bookId, req.Copies, req.Location)
+}
+
+func (service *BookService) WithdrawCopy(ctx context.Context, bookId, copyId, reason string) error {
+	userCtx, ok := ctx.Value("user").(models.UserJwt)
+	if !ok {
+		return apperrors.ErrInvalidUser
+	}
+
+	if !rbac.Can(userCtx.Role, rbac.BookDelete) {
+		return apperrors.ErrUnauthorisedUser
+	}
+
+	if _, err := uuid.Parse(bookId); err != nil {
+		return apperrors.Validation("invalid_id", "invalid book id").WithDetail("book_id", "required")
+	}
+
+	if _, err := uuid.Parse(copyId); err != nil {
+		return apperrors.Validation("invalid_id", "invalid copy id").WithDetail("copy_id", "required")
+	}
+
+	if reason == "" {
+		return apperrors.Validation("missing_reason", "reason is required").WithDetail("reason", "required")
+	}
+
+	return service.bookRepo.WithdrawCopy(bookId, copyId, reason)
+}
+
+func toBookDTO(book models.Book) models.BookDTO {
+	return models.BookDTO{
+		ID:              book.ID.String(),
+		Title:           book.Title,
+		Author:          book.Author,
+		ISBN:            book.ISBN,
+		Publisher:       book.Publisher,
+		Year:            book.Year,
+		Language:        book.Language,
+		Subject:         book.Subject,
+		Description:     book.Description,
+		ItemType:        book.ItemType,
+		TotalCopies:     book.TotalCopies,
+		AvailableCopies: book.AvailableCopies,
+	}
 }
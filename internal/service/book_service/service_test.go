@@ -1,13 +1,19 @@
 package bookservice
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"reflect"
+	"strings"
 	"testing"
 
+	"github.com/Kaushik1766/LibraryManagement/internal/marc"
 	"github.com/Kaushik1766/LibraryManagement/internal/models"
+	"github.com/Kaushik1766/LibraryManagement/internal/models/enums/copystatus"
+	"github.com/Kaushik1766/LibraryManagement/internal/models/enums/importstatus"
 	"github.com/Kaushik1766/LibraryManagement/internal/models/enums/roles"
+	"github.com/Kaushik1766/LibraryManagement/internal/pagination"
 	bookrepo "github.com/Kaushik1766/LibraryManagement/internal/repository/book_repo"
 	"github.com/Kaushik1766/LibraryManagement/mocks"
 	"github.com/google/uuid"
@@ -96,7 +102,7 @@ func TestBookService_AddBook(t *testing.T) {
 			},
 			wantErr: false,
 			mockSetup: func() {
-				mockBookRepo.EXPECT().AddBook("asdfa", "adfsadf", 4).Return(nil)
+				mockBookRepo.EXPECT().AddBook(models.Book{Title: "asdfa", Author: "adfsadf"}, 4, "").Return("book-id", nil)
 			},
 		},
 	}
@@ -106,7 +112,7 @@ func TestBookService_AddBook(t *testing.T) {
 				bookRepo: tt.fields.bookRepo,
 			}
 			tt.mockSetup()
-			if err := service.AddBook(tt.args.ctx, tt.args.bookReq); (err != nil) != tt.wantErr {
+			if _, err := service.AddBook(tt.args.ctx, tt.args.bookReq); (err != nil) != tt.wantErr {
 				t.Errorf("AddBook() error = %v, wantErr %v", err, tt.wantErr)
 			}
 		})
@@ -117,21 +123,23 @@ func TestBookService_GetAllBooks(t *testing.T) {
 
 	ctrl := gomock.NewController(t)
 	mockBookRepo := mocks.NewMockBookStorage(ctrl)
+	page := pagination.Request{Limit: 1, Sort: "title"}
 
 	book1 := models.Book{
-		ID:       uuid.New(),
-		Title:    "asdf",
-		Author:   "asdf",
-		IssuedTo: nil,
+		ID:              uuid.New(),
+		Title:           "asdf",
+		Author:          "asdf",
+		TotalCopies:     2,
+		AvailableCopies: 1,
 	}
 
 	book2 := models.Book{
-		ID:     uuid.New(),
-		Title:  "asdf",
-		Author: "asdf",
-		IssuedTo: &models.User{
-			Email: "kaushik@a.com",
-		},
+		ID:              uuid.New(),
+		Title:           "asdf",
+		Author:          "asdf",
+		ISBN:            "9780747532699",
+		TotalCopies:     1,
+		AvailableCopies: 0,
 	}
 	type fields struct {
 		bookRepo bookrepo.BookStorage
@@ -145,7 +153,7 @@ func TestBookService_GetAllBooks(t *testing.T) {
 		name      string
 		fields    fields
 		args      args
-		want      []models.BookDTO
+		want      pagination.Page[models.BookDTO]
 		wantErr   bool
 		setupMock func()
 	}{
@@ -157,7 +165,7 @@ func TestBookService_GetAllBooks(t *testing.T) {
 				title:  "asdf",
 				author: "asdf",
 			},
-			want:    nil,
+			want:    pagination.Page[models.BookDTO]{},
 			wantErr: true,
 			setupMock: func() {
 			},
@@ -170,10 +178,10 @@ func TestBookService_GetAllBooks(t *testing.T) {
 				title:  "asdf",
 				author: "asdf",
 			},
-			want:    nil,
+			want:    pagination.Page[models.BookDTO]{},
 			wantErr: true,
 			setupMock: func() {
-				mockBookRepo.EXPECT().GetAllBooks(gomock.Any(), gomock.Any()).Return(nil, errors.New("db error"))
+				mockBookRepo.EXPECT().GetAllBooks(gomock.Any(), gomock.Any(), page).Return(pagination.Page[models.Book]{}, errors.New("db error"))
 			},
 		},
 		{
@@ -184,18 +192,20 @@ func TestBookService_GetAllBooks(t *testing.T) {
 				title:  "asdf",
 				author: "asdf",
 			},
-			want: []models.BookDTO{
+			want: pagination.Page[models.BookDTO]{Items: []models.BookDTO{
 				{
-					ID:     book1.ID.String(),
-					Title:  book1.Title,
-					Author: book1.Author,
+					ID:              book1.ID.String(),
+					Title:           book1.Title,
+					Author:          book1.Author,
+					TotalCopies:     2,
+					AvailableCopies: 1,
 				},
-			},
+			}},
 			wantErr: false,
 			setupMock: func() {
-				mockBookRepo.EXPECT().GetAllBooks(gomock.Any(), gomock.Any()).Return([]models.Book{
+				mockBookRepo.EXPECT().GetAllBooks(gomock.Any(), gomock.Any(), page).Return(pagination.Page[models.Book]{Items: []models.Book{
 					book1,
-				}, nil)
+				}}, nil)
 			},
 		},
 		{
@@ -206,26 +216,29 @@ func TestBookService_GetAllBooks(t *testing.T) {
 				title:  "asdf",
 				author: "asdf",
 			},
-			want: []models.BookDTO{
+			want: pagination.Page[models.BookDTO]{Items: []models.BookDTO{
 				{
-					ID:       book1.ID.String(),
-					Title:    book1.Title,
-					Author:   book1.Author,
-					IssuedTo: "none",
+					ID:              book1.ID.String(),
+					Title:           book1.Title,
+					Author:          book1.Author,
+					TotalCopies:     2,
+					AvailableCopies: 1,
 				},
 				{
-					ID:       book2.ID.String(),
-					Title:    book2.Title,
-					Author:   book2.Author,
-					IssuedTo: book2.IssuedTo.Email,
+					ID:              book2.ID.String(),
+					Title:           book2.Title,
+					Author:          book2.Author,
+					ISBN:            book2.ISBN,
+					TotalCopies:     1,
+					AvailableCopies: 0,
 				},
-			},
+			}, NextCursor: "next", Total: 3},
 			wantErr: false,
 			setupMock: func() {
-				mockBookRepo.EXPECT().GetAllBooks(gomock.Any(), gomock.Any()).Return([]models.Book{
+				mockBookRepo.EXPECT().GetAllBooks(gomock.Any(), gomock.Any(), page).Return(pagination.Page[models.Book]{Items: []models.Book{
 					book1,
 					book2,
-				}, nil)
+				}, NextCursor: "next", Total: 3}, nil)
 			},
 		},
 	}
@@ -235,7 +248,7 @@ func TestBookService_GetAllBooks(t *testing.T) {
 				bookRepo: tt.fields.bookRepo,
 			}
 			tt.setupMock()
-			got, err := service.GetAllBooks(tt.args.ctx, tt.args.title, tt.args.author)
+			got, err := service.GetAllBooks(tt.args.ctx, tt.args.title, tt.args.author, page)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("GetAllBooks() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -247,6 +260,774 @@ func TestBookService_GetAllBooks(t *testing.T) {
 	}
 }
 
+func TestBookService_GetCopies(t *testing.T) {
+
+	ctrl := gomock.NewController(t)
+	mockBookRepo := mocks.NewMockBookStorage(ctrl)
+
+	bookId := uuid.New()
+	copies := []models.Copy{
+		{
+			ID:      uuid.New(),
+			BookID:  bookId,
+			Barcode: "ABC123",
+			Status:  copystatus.OnLoan,
+			IssuedTo: &models.User{
+				Email: "kaushik@a.com",
+			},
+		},
+	}
+
+	tests := []struct {
+		name      string
+		ctx       context.Context
+		bookId    string
+		want      []models.CopyDTO
+		wantErr   bool
+		setupMock func()
+	}{
+		{
+			name:      "invalid context",
+			ctx:       context.Background(),
+			bookId:    bookId.String(),
+			want:      nil,
+			wantErr:   true,
+			setupMock: func() {},
+		},
+		{
+			name:      "invalid book id",
+			ctx:       context.WithValue(context.Background(), "user", models.UserJwt{Role: roles.Customer}),
+			bookId:    "asdf",
+			want:      nil,
+			wantErr:   true,
+			setupMock: func() {},
+		},
+		{
+			name:   "customer doesn't see borrower",
+			ctx:    context.WithValue(context.Background(), "user", models.UserJwt{Role: roles.Customer}),
+			bookId: bookId.String(),
+			want: []models.CopyDTO{
+				{
+					ID:      copies[0].ID.String(),
+					BookID:  bookId.String(),
+					Barcode: "ABC123",
+					Status:  "on_loan",
+				},
+			},
+			wantErr: false,
+			setupMock: func() {
+				mockBookRepo.EXPECT().GetCopies(bookId.String()).Return(copies, nil)
+			},
+		},
+		{
+			name:   "staff sees borrower",
+			ctx:    context.WithValue(context.Background(), "user", models.UserJwt{Role: roles.Staff}),
+			bookId: bookId.String(),
+			want: []models.CopyDTO{
+				{
+					ID:       copies[0].ID.String(),
+					BookID:   bookId.String(),
+					Barcode:  "ABC123",
+					Status:   "on_loan",
+					IssuedTo: "kaushik@a.com",
+				},
+			},
+			wantErr: false,
+			setupMock: func() {
+				mockBookRepo.EXPECT().GetCopies(bookId.String()).Return(copies, nil)
+			},
+		},
+		{
+			name:    "repo error",
+			ctx:     context.WithValue(context.Background(), "user", models.UserJwt{Role: roles.Staff}),
+			bookId:  bookId.String(),
+			want:    nil,
+			wantErr: true,
+			setupMock: func() {
+				mockBookRepo.EXPECT().GetCopies(bookId.String()).Return(nil, errors.New("db error"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := &BookService{
+				bookRepo: mockBookRepo,
+			}
+			tt.setupMock()
+			got, err := service.GetCopies(tt.ctx, tt.bookId)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetCopies() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetCopies() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBookService_AddCopies(t *testing.T) {
+
+	ctrl := gomock.NewController(t)
+	mockBookRepo := mocks.NewMockBookStorage(ctrl)
+
+	bookId := uuid.New().String()
+
+	tests := []struct {
+		name      string
+		ctx       context.Context
+		req       models.AddCopiesDTO
+		wantErr   bool
+		setupMock func()
+	}{
+		{
+			name:      "invalid input",
+			ctx:       context.WithValue(context.Background(), "user", models.UserJwt{Role: roles.Staff}),
+			req:       models.AddCopiesDTO{Copies: 0},
+			wantErr:   true,
+			setupMock: func() {},
+		},
+		{
+			name:      "unauthorised user",
+			ctx:       context.WithValue(context.Background(), "user", models.UserJwt{Role: roles.Customer}),
+			req:       models.AddCopiesDTO{Copies: 2},
+			wantErr:   true,
+			setupMock: func() {},
+		},
+		{
+			name:    "authorised user",
+			ctx:     context.WithValue(context.Background(), "user", models.UserJwt{Role: roles.Staff}),
+			req:     models.AddCopiesDTO{Copies: 2, Location: "shelf a"},
+			wantErr: false,
+			setupMock: func() {
+				mockBookRepo.EXPECT().AddCopies(bookId, 2, "shelf a").Return(nil)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := &BookService{
+				bookRepo: mockBookRepo,
+			}
+			tt.setupMock()
+			if err := service.AddCopies(tt.ctx, bookId, tt.req); (err != nil) != tt.wantErr {
+				t.Errorf("AddCopies() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestBookService_SearchBooks(t *testing.T) {
+
+	ctrl := gomock.NewController(t)
+	mockBookRepo := mocks.NewMockBookStorage(ctrl)
+
+	book := models.Book{
+		ID:              uuid.New(),
+		Title:           "dune",
+		Author:          "frank herbert",
+		Subject:         "science fiction",
+		TotalCopies:     2,
+		AvailableCopies: 1,
+	}
+	facets := models.SearchFacets{
+		Authors:      []models.FacetCount{{Value: "frank herbert", Count: 1}},
+		Subjects:     []models.FacetCount{{Value: "science fiction", Count: 1}},
+		Languages:    []models.FacetCount{},
+		Availability: []models.FacetCount{{Value: "true", Count: 1}},
+	}
+	customer := context.WithValue(context.Background(), "user", models.UserJwt{Role: roles.Customer})
+
+	tests := []struct {
+		name      string
+		ctx       context.Context
+		search    models.BookSearch
+		want      models.SearchResultDTO
+		wantErr   bool
+		setupMock func()
+	}{
+		{
+			name:      "invalid context",
+			ctx:       context.Background(),
+			search:    models.BookSearch{Query: "dune"},
+			wantErr:   true,
+			setupMock: func() {},
+		},
+		{
+			name:      "empty query",
+			ctx:       customer,
+			search:    models.BookSearch{Query: "  "},
+			wantErr:   true,
+			setupMock: func() {},
+		},
+		{
+			name:      "invalid availability",
+			ctx:       customer,
+			search:    models.BookSearch{Query: "dune", Available: "maybe"},
+			wantErr:   true,
+			setupMock: func() {},
+		},
+		{
+			name:   "valid search",
+			ctx:    customer,
+			search: models.BookSearch{Query: "dune", Available: "true"},
+			want: models.SearchResultDTO{
+				Page: pagination.Page[models.SearchHitDTO]{
+					Items: []models.SearchHitDTO{{
+						BookDTO: models.BookDTO{
+							ID:              book.ID.String(),
+							Title:           "dune",
+							Author:          "frank herbert",
+							Subject:         "science fiction",
+							TotalCopies:     2,
+							AvailableCopies: 1,
+						},
+						Rank:    0.3,
+						Snippet: "<mark>dune</mark>",
+					}},
+					NextCursor: "next",
+					Total:      4,
+				},
+				Facets: facets,
+			},
+			wantErr: false,
+			setupMock: func() {
+				mockBookRepo.EXPECT().SearchBooks(models.BookSearch{Query: "dune", Available: "true"}, pagination.Request{}).Return(models.SearchResult{
+					Hits: pagination.Page[models.SearchHit]{
+						Items:      []models.SearchHit{{Book: book, Rank: 0.3, Snippet: "<mark>dune</mark>"}},
+						NextCursor: "next",
+						Total:      4,
+					},
+					Facets: facets,
+				}, nil)
+			},
+		},
+		{
+			name:    "repo error",
+			ctx:     customer,
+			search:  models.BookSearch{Query: "dune"},
+			wantErr: true,
+			setupMock: func() {
+				mockBookRepo.EXPECT().SearchBooks(gomock.Any(), gomock.Any()).Return(models.SearchResult{}, errors.New("db error"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := &BookService{
+				bookRepo: mockBookRepo,
+			}
+			tt.setupMock()
+			got, err := service.SearchBooks(tt.ctx, tt.search, pagination.Request{})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("SearchBooks() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("SearchBooks() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBookService_GetBook(t *testing.T) {
+
+	ctrl := gomock.NewController(t)
+	mockBookRepo := mocks.NewMockBookStorage(ctrl)
+
+	book := models.Book{
+		ID:              uuid.New(),
+		Title:           "dune",
+		Author:          "frank herbert",
+		TotalCopies:     2,
+		AvailableCopies: 1,
+	}
+
+	tests := []struct {
+		name      string
+		ctx       context.Context
+		bookId    string
+		want      models.BookDTO
+		wantErr   bool
+		setupMock func()
+	}{
+		{
+			name:      "invalid context",
+			ctx:       context.Background(),
+			bookId:    book.ID.String(),
+			want:      models.BookDTO{},
+			wantErr:   true,
+			setupMock: func() {},
+		},
+		{
+			name:      "invalid book id",
+			ctx:       context.WithValue(context.Background(), "user", models.UserJwt{Role: roles.Customer}),
+			bookId:    "asdf",
+			want:      models.BookDTO{},
+			wantErr:   true,
+			setupMock: func() {},
+		},
+		{
+			name:   "valid get book",
+			ctx:    context.WithValue(context.Background(), "user", models.UserJwt{Role: roles.Customer}),
+			bookId: book.ID.String(),
+			want: models.BookDTO{
+				ID:              book.ID.String(),
+				Title:           "dune",
+				Author:          "frank herbert",
+				TotalCopies:     2,
+				AvailableCopies: 1,
+			},
+			wantErr: false,
+			setupMock: func() {
+				mockBookRepo.EXPECT().GetBookById(book.ID.String()).Return(book, nil)
+			},
+		},
+		{
+			name:    "book not found",
+			ctx:     context.WithValue(context.Background(), "user", models.UserJwt{Role: roles.Customer}),
+			bookId:  book.ID.String(),
+			want:    models.BookDTO{},
+			wantErr: true,
+			setupMock: func() {
+				mockBookRepo.EXPECT().GetBookById(book.ID.String()).Return(models.Book{}, errors.New("book not found"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := &BookService{
+				bookRepo: mockBookRepo,
+			}
+			tt.setupMock()
+			got, err := service.GetBook(tt.ctx, tt.bookId)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetBook() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetBook() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBookService_UpdateBook(t *testing.T) {
+
+	ctrl := gomock.NewController(t)
+	mockBookRepo := mocks.NewMockBookStorage(ctrl)
+
+	book := models.Book{
+		ID:     uuid.New(),
+		Title:  "dnue",
+		Author: "frank herbert",
+		Year:   1965,
+	}
+	title := "dune"
+	empty := ""
+
+	tests := []struct {
+		name      string
+		ctx       context.Context
+		req       models.UpdateBookDTO
+		want      models.BookDTO
+		wantErr   bool
+		setupMock func()
+	}{
+		{
+			name:      "unauthorised user",
+			ctx:       context.WithValue(context.Background(), "user", models.UserJwt{Role: roles.Customer}),
+			req:       models.UpdateBookDTO{Title: &title},
+			want:      models.BookDTO{},
+			wantErr:   true,
+			setupMock: func() {},
+		},
+		{
+			name: "only given fields change",
+			ctx:  context.WithValue(context.Background(), "user", models.UserJwt{Role: roles.Staff}),
+			req:  models.UpdateBookDTO{Title: &title},
+			want: models.BookDTO{
+				ID:     book.ID.String(),
+				Title:  "dune",
+				Author: "frank herbert",
+				Year:   1965,
+			},
+			wantErr: false,
+			setupMock: func() {
+				mockBookRepo.EXPECT().GetBookById(book.ID.String()).Return(book, nil)
+				mockBookRepo.EXPECT().UpdateBook(models.Book{
+					ID:     book.ID,
+					Title:  "dune",
+					Author: "frank herbert",
+					Year:   1965,
+				}).Return(nil)
+			},
+		},
+		{
+			name:    "title cannot be cleared",
+			ctx:     context.WithValue(context.Background(), "user", models.UserJwt{Role: roles.Staff}),
+			req:     models.UpdateBookDTO{Title: &empty},
+			want:    models.BookDTO{},
+			wantErr: true,
+			setupMock: func() {
+				mockBookRepo.EXPECT().GetBookById(book.ID.String()).Return(book, nil)
+			},
+		},
+		{
+			name:    "repo error",
+			ctx:     context.WithValue(context.Background(), "user", models.UserJwt{Role: roles.Staff}),
+			req:     models.UpdateBookDTO{Title: &title},
+			want:    models.BookDTO{},
+			wantErr: true,
+			setupMock: func() {
+				mockBookRepo.EXPECT().GetBookById(book.ID.String()).Return(book, nil)
+				mockBookRepo.EXPECT().UpdateBook(gomock.Any()).Return(errors.New("db error"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := &BookService{
+				bookRepo: mockBookRepo,
+			}
+			tt.setupMock()
+			got, err := service.UpdateBook(tt.ctx, book.ID.String(), tt.req)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("UpdateBook() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("UpdateBook() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBookService_WithdrawBook(t *testing.T) {
+
+	ctrl := gomock.NewController(t)
+	mockBookRepo := mocks.NewMockBookStorage(ctrl)
+
+	bookId := uuid.New().String()
+
+	tests := []struct {
+		name      string
+		ctx       context.Context
+		reason    string
+		wantErr   bool
+		setupMock func()
+	}{
+		{
+			name:      "unauthorised user",
+			ctx:       context.WithValue(context.Background(), "user", models.UserJwt{Role: roles.Customer}),
+			reason:    "duplicate",
+			wantErr:   true,
+			setupMock: func() {},
+		},
+		{
+			name:      "missing reason",
+			ctx:       context.WithValue(context.Background(), "user", models.UserJwt{Role: roles.Staff}),
+			reason:    "",
+			wantErr:   true,
+			setupMock: func() {},
+		},
+		{
+			name:    "valid withdraw",
+			ctx:     context.WithValue(context.Background(), "user", models.UserJwt{Role: roles.Staff}),
+			reason:  "duplicate",
+			wantErr: false,
+			setupMock: func() {
+				mockBookRepo.EXPECT().WithdrawBook(bookId, "duplicate").Return(nil)
+			},
+		},
+		{
+			name:    "copy still issued",
+			ctx:     context.WithValue(context.Background(), "user", models.UserJwt{Role: roles.Staff}),
+			reason:  "duplicate",
+			wantErr: true,
+			setupMock: func() {
+				mockBookRepo.EXPECT().WithdrawBook(bookId, "duplicate").Return(errors.New("book has copies issued and cannot be withdrawn"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := &BookService{
+				bookRepo: mockBookRepo,
+			}
+			tt.setupMock()
+			if err := service.WithdrawBook(tt.ctx, bookId, tt.reason); (err != nil) != tt.wantErr {
+				t.Errorf("WithdrawBook() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestBookService_WithdrawCopy(t *testing.T) {
+
+	ctrl := gomock.NewController(t)
+	mockBookRepo := mocks.NewMockBookStorage(ctrl)
+
+	bookId := uuid.New().String()
+	copyId := uuid.New().String()
+
+	tests := []struct {
+		name      string
+		ctx       context.Context
+		copyId    string
+		wantErr   bool
+		setupMock func()
+	}{
+		{
+			name:      "unauthorised user",
+			ctx:       context.WithValue(context.Background(), "user", models.UserJwt{Role: roles.Customer}),
+			copyId:    copyId,
+			wantErr:   true,
+			setupMock: func() {},
+		},
+		{
+			name:      "invalid copy id",
+			ctx:       context.WithValue(context.Background(), "user", models.UserJwt{Role: roles.Staff}),
+			copyId:    "asdf",
+			wantErr:   true,
+			setupMock: func() {},
+		},
+		{
+			name:    "valid withdraw",
+			ctx:     context.WithValue(context.Background(), "user", models.UserJwt{Role: roles.Staff}),
+			copyId:  copyId,
+			wantErr: false,
+			setupMock: func() {
+				mockBookRepo.EXPECT().WithdrawCopy(bookId, copyId, "damaged").Return(nil)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := &BookService{
+				bookRepo: mockBookRepo,
+			}
+			tt.setupMock()
+			if err := service.WithdrawCopy(tt.ctx, bookId, tt.copyId, "damaged"); (err != nil) != tt.wantErr {
+				t.Errorf("WithdrawCopy() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestBookService_ImportBooks(t *testing.T) {
+
+	ctrl := gomock.NewController(t)
+	mockBookRepo := mocks.NewMockBookStorage(ctrl)
+
+	staff := context.WithValue(context.Background(), "user", models.UserJwt{Role: roles.Staff})
+	csvFile := "title,author,isbn\nharry potter,jk rowling,978-0-7475-3269-9\n"
+
+	tests := []struct {
+		name      string
+		ctx       context.Context
+		format    string
+		data      string
+		want      models.ImportReport
+		wantErr   bool
+		setupMock func()
+	}{
+		{
+			name:      "librarian cannot import",
+			ctx:       context.WithValue(context.Background(), "user", models.UserJwt{Role: roles.Librarian}),
+			format:    "csv",
+			data:      csvFile,
+			wantErr:   true,
+			setupMock: func() {},
+		},
+		{
+			name:      "unknown format",
+			ctx:       staff,
+			format:    "xml",
+			data:      csvFile,
+			wantErr:   true,
+			setupMock: func() {},
+		},
+		{
+			name:    "valid import",
+			ctx:     staff,
+			format:  "text/csv",
+			data:    csvFile,
+			wantErr: false,
+			want: models.ImportReport{
+				Committed: true,
+				Total:     1,
+				Imported:  1,
+				Rows:      []models.ImportRowResult{{Line: 2, Status: importstatus.Imported, BookID: "1"}},
+			},
+			setupMock: func() {
+				mockBookRepo.EXPECT().ImportBooks([]models.ImportRow{{
+					Line: 2,
+					Book: models.AddBookDTO{Title: "harry potter", Author: "jk rowling", ISBN: "978-0-7475-3269-9", Copies: 1},
+				}}, false).Return([]models.ImportRowResult{{Line: 2, Status: importstatus.Imported, BookID: "1"}}, nil)
+			},
+		},
+		{
+			name:    "repository error",
+			ctx:     staff,
+			format:  "csv",
+			data:    csvFile,
+			wantErr: true,
+			setupMock: func() {
+				mockBookRepo.EXPECT().ImportBooks(gomock.Any(), false).Return(nil, errors.New("db error"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := &BookService{
+				bookRepo: mockBookRepo,
+			}
+			tt.setupMock()
+			got, err := service.ImportBooks(tt.ctx, tt.format, strings.NewReader(tt.data), false)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ImportBooks() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ImportBooks() got = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBookService_ExportBook(t *testing.T) {
+
+	ctrl := gomock.NewController(t)
+	mockBookRepo := mocks.NewMockBookStorage(ctrl)
+
+	book := models.Book{ID: uuid.New(), Title: "dune", Author: "frank herbert"}
+
+	tests := []struct {
+		name      string
+		ctx       context.Context
+		bookId    string
+		want      marc.Record
+		wantErr   bool
+		setupMock func()
+	}{
+		{
+			name:      "no user",
+			ctx:       context.Background(),
+			bookId:    book.ID.String(),
+			wantErr:   true,
+			setupMock: func() {},
+		},
+		{
+			name:      "invalid book id",
+			ctx:       context.WithValue(context.Background(), "user", models.UserJwt{Role: roles.Customer}),
+			bookId:    "asdf",
+			wantErr:   true,
+			setupMock: func() {},
+		},
+		{
+			name:    "valid export",
+			ctx:     context.WithValue(context.Background(), "user", models.UserJwt{Role: roles.Customer}),
+			bookId:  book.ID.String(),
+			want:    marc.FromBook(book),
+			wantErr: false,
+			setupMock: func() {
+				mockBookRepo.EXPECT().GetBookById(book.ID.String()).Return(book, nil)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := &BookService{
+				bookRepo: mockBookRepo,
+			}
+			tt.setupMock()
+			got, err := service.ExportBook(tt.ctx, tt.bookId)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ExportBook() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ExportBook() got = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestBookService_ExportCatalog(t *testing.T) {
+
+	ctrl := gomock.NewController(t)
+	mockBookRepo := mocks.NewMockBookStorage(ctrl)
+
+	books := []models.Book{
+		{ID: uuid.New(), Title: "dune", Author: "frank herbert"},
+		{ID: uuid.New(), Title: "emma", Author: "jane austen"},
+	}
+
+	tests := []struct {
+		name        string
+		ctx         context.Context
+		wantRecords int
+		wantErr     bool
+		setupMock   func()
+	}{
+		{
+			name:      "customer cannot export",
+			ctx:       context.WithValue(context.Background(), "user", models.UserJwt{Role: roles.Customer}),
+			wantErr:   true,
+			setupMock: func() {},
+		},
+		{
+			name:        "every title",
+			ctx:         context.WithValue(context.Background(), "user", models.UserJwt{Role: roles.Librarian}),
+			wantRecords: 2,
+			wantErr:     false,
+			setupMock: func() {
+				mockBookRepo.EXPECT().EachBook(gomock.Any()).DoAndReturn(func(fn func(models.Book) error) error {
+					for _, book := range books {
+						if err := fn(book); err != nil {
+							return err
+						}
+					}
+					return nil
+				})
+			},
+		},
+		{
+			name:    "repository error",
+			ctx:     context.WithValue(context.Background(), "user", models.UserJwt{Role: roles.Librarian}),
+			wantErr: true,
+			setupMock: func() {
+				mockBookRepo.EXPECT().EachBook(gomock.Any()).Return(errors.New("db error"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := &BookService{
+				bookRepo: mockBookRepo,
+			}
+			tt.setupMock()
+
+			var buf bytes.Buffer
+			err := service.ExportCatalog(tt.ctx, marc.NewEncoder(marc.ISO2709, &buf))
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ExportCatalog() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+
+			records := 0
+			dec := marc.NewDecoder(marc.ISO2709, &buf)
+			for _, err := dec.Decode(); err == nil; _, err = dec.Decode() {
+				records++
+			}
+			if records != tt.wantRecords {
+				t.Errorf("ExportCatalog() wrote %d records, want %d", records, tt.wantRecords)
+			}
+		})
+	}
+}
+
 func TestNewBookService(t *testing.T) {
 
 	ctrl := gomock.NewController(t)
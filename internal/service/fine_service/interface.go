@@ -0,0 +1,15 @@
+package fineservice
+
+import (
+	"context"
+
+	"github.com/Kaushik1766/LibraryManagement/internal/models"
+)
+
+//go:generate mockgen -source=interface.go -destination=../../../mocks/mock_fine_manager.go -package=mocks
+type FineManager interface {
+	GetFines(ctx context.Context, userId string) (models.FineBalanceDTO, error)
+	RecordPayment(ctx context.Context, fineId string, amount int) error
+	WaiveFine(ctx context.Context, fineId string, amount int, reason string) error
+	AccrueFines() (int64, error)
+}
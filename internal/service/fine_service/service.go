@@ -0,0 +1,142 @@
+package fineservice
+
+import (
+	"context"
+
+	apperrors "github.com/Kaushik1766/LibraryManagement/internal/app_errors"
+	"github.com/Kaushik1766/LibraryManagement/internal/config"
+	"github.com/Kaushik1766/LibraryManagement/internal/models"
+	"github.com/Kaushik1766/LibraryManagement/internal/models/enums/paymentkind"
+	"github.com/Kaushik1766/LibraryManagement/internal/rbac"
+	finerepo "github.com/Kaushik1766/LibraryManagement/internal/repository/fine_repo"
+)
+
+type FineService struct {
+	fineRepo finerepo.FineStorage
+	policy   config.CirculationConfig
+}
+
+func NewFineService(fineRepo finerepo.FineStorage, policy config.CirculationConfig) *FineService {
+	return &FineService{
+		fineRepo: fineRepo,
+		policy:   policy,
+	}
+}
+
+// GetFines returns the caller's fines and outstanding balance. Staff can look
+// at a specific user's fines, or everyone's when userId is empty.
+func (service *FineService) GetFines(ctx context.Context, userId string) (models.FineBalanceDTO, error) {
+	userCtx, ok := ctx.Value("user").(models.UserJwt)
+	if !ok {
+		return models.FineBalanceDTO{}, apperrors.ErrInvalidUser
+	}
+
+	if !rbac.Can(userCtx.Role, rbac.FinesViewAll) {
+		userId = userCtx.Subject
+	}
+
+	fines, err := service.fineRepo.GetFines(userId)
+	if err != nil {
+		return models.FineBalanceDTO{}, err
+	}
+
+	var balance models.FineBalanceDTO
+	for _, val := range fines {
+		outstanding := val.Amount - val.Paid - val.Waived
+
+		var status string
+		switch {
+		case !val.Final:
+			status = "accruing"
+		case outstanding > 0:
+			status = "outstanding"
+		default:
+			status = "settled"
+		}
+
+		dto := models.FineDTO{
+			ID:            val.ID.String(),
+			TransactionID: val.Transaction.ID.String(),
+			BookName:      val.Transaction.Book.Title,
+			DaysOverdue:   val.DaysOverdue,
+			Amount:        val.Amount,
+			Paid:          val.Paid,
+			Waived:        val.Waived,
+			Outstanding:   outstanding,
+			Status:        status,
+		}
+		if rbac.Can(userCtx.Role, rbac.PatronView) {
+			dto.UserEmail = val.User.Email
+		}
+
+		balance.Outstanding += outstanding
+		balance.Fines = append(balance.Fines, dto)
+	}
+
+	return balance, nil
+}
+
+func (service *FineService) RecordPayment(ctx context.Context, fineId string, amount int) error {
+	userCtx, ok := ctx.Value("user").(models.UserJwt)
+	if !ok {
+		return apperrors.ErrInvalidUser
+	}
+
+	if !rbac.Can(userCtx.Role, rbac.FinesCollect) {
+		return apperrors.ErrUnauthorisedUser
+	}
+
+	if fineId == "" {
+		return apperrors.Validation("invalid_id", "invalid fine id").WithDetail("fine_id", "required")
+	}
+
+	if amount <= 0 {
+		return apperrors.Validation("invalid_amount", "amount must be positive").WithDetail("amount", "must be positive")
+	}
+
+	return service.fineRepo.RecordPayment(fineId, paymentkind.Payment, amount, "", userCtx.Subject)
+}
+
+// WaiveFine writes off amount from a fine, or everything still outstanding
+// when amount is zero. A reason is always required.
+func (service *FineService) WaiveFine(ctx context.Context, fineId string, amount int, reason string) error {
+	userCtx, ok := ctx.Value("user").(models.UserJwt)
+	if !ok {
+		return apperrors.ErrInvalidUser
+	}
+
+	if !rbac.Can(userCtx.Role, rbac.FinesWaive) {
+		return apperrors.ErrUnauthorisedUser
+	}
+
+	if fineId == "" {
+		return apperrors.Validation("invalid_id", "invalid fine id").WithDetail("fine_id", "required")
+	}
+
+	if reason == "" {
+		return apperrors.Validation("missing_reason", "reason is required to waive a fine").WithDetail("reason", "required")
+	}
+
+	if amount < 0 {
+		return apperrors.Validation("invalid_amount", "amount cant be negative").WithDetail("amount", "cant be negative")
+	}
+
+	if amount == 0 {
+		fine, err := service.fineRepo.GetFineById(fineId)
+		if err != nil {
+			return err
+		}
+
+		amount = fine.Amount - fine.Paid - fine.Waived
+		if amount <= 0 {
+			return apperrors.Conflict("fine_settled", "fine is already settled")
+		}
+	}
+
+	return service.fineRepo.RecordPayment(fineId, paymentkind.Waiver, amount, reason, userCtx.Subject)
+}
+
+// AccrueFines recomputes fines on loans that are still out and overdue.
+func (service *FineService) AccrueFines() (int64, error) {
+	return service.fineRepo.AccrueFines(service.policy.FinePerDay, service.policy.FineCap)
+}
@@ -0,0 +1,410 @@
+package fineservice
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/Kaushik1766/LibraryManagement/internal/config"
+	"github.com/Kaushik1766/LibraryManagement/internal/models"
+	"github.com/Kaushik1766/LibraryManagement/internal/models/enums/paymentkind"
+	"github.com/Kaushik1766/LibraryManagement/internal/models/enums/roles"
+	finerepo "github.com/Kaushik1766/LibraryManagement/internal/repository/fine_repo"
+	"github.com/Kaushik1766/LibraryManagement/mocks"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"go.uber.org/mock/gomock"
+)
+
+var testPolicy = config.Default().Circulation
+
+func TestNewFineService(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockFineRepo := mocks.NewMockFineStorage(ctrl)
+
+	type args struct {
+		fineRepo finerepo.FineStorage
+	}
+	tests := []struct {
+		name string
+		args args
+		want *FineService
+	}{
+		{
+			name: "valid",
+			args: args{
+				fineRepo: mockFineRepo,
+			},
+			want: &FineService{
+				fineRepo: mockFineRepo,
+				policy:   testPolicy,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NewFineService(tt.args.fineRepo, testPolicy); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("NewFineService() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFineService_GetFines(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockFineRepo := mocks.NewMockFineStorage(ctrl)
+
+	customerId := uuid.New().String()
+	settled := models.Fine{
+		ID:          uuid.New(),
+		Transaction: models.Transaction{ID: uuid.New(), Book: models.Book{Title: "dune"}},
+		User:        models.User{Email: "kaushik@a.com"},
+		Amount:      100,
+		Paid:        60,
+		Waived:      40,
+		DaysOverdue: 2,
+		Final:       true,
+		AssessedAt:  time.Now(),
+	}
+	accruing := models.Fine{
+		ID:          uuid.New(),
+		Transaction: models.Transaction{ID: uuid.New(), Book: models.Book{Title: "harry potter"}},
+		User:        models.User{Email: "kaushik@a.com"},
+		Amount:      150,
+		DaysOverdue: 3,
+		Final:       false,
+		AssessedAt:  time.Now(),
+	}
+
+	tests := []struct {
+		name      string
+		ctx       context.Context
+		userId    string
+		want      models.FineBalanceDTO
+		wantErr   bool
+		mockSetup func()
+	}{
+		{
+			name: "customer sees own balance",
+			ctx: context.WithValue(context.Background(), "user", models.UserJwt{
+				RegisteredClaims: jwt.RegisteredClaims{Subject: customerId},
+				Role:             roles.Customer,
+			}),
+			userId: "someone-else",
+			want: models.FineBalanceDTO{
+				Outstanding: 150,
+				Fines: []models.FineDTO{
+					{
+						ID:            settled.ID.String(),
+						TransactionID: settled.Transaction.ID.String(),
+						BookName:      "dune",
+						DaysOverdue:   2,
+						Amount:        100,
+						Paid:          60,
+						Waived:        40,
+						Outstanding:   0,
+						Status:        "settled",
+					},
+					{
+						ID:            accruing.ID.String(),
+						TransactionID: accruing.Transaction.ID.String(),
+						BookName:      "harry potter",
+						DaysOverdue:   3,
+						Amount:        150,
+						Outstanding:   150,
+						Status:        "accruing",
+					},
+				},
+			},
+			wantErr: false,
+			mockSetup: func() {
+				mockFineRepo.EXPECT().GetFines(customerId).Return([]models.Fine{settled, accruing}, nil)
+			},
+		},
+		{
+			name: "staff looks up a user",
+			ctx: context.WithValue(context.Background(), "user", models.UserJwt{
+				Role: roles.Staff,
+			}),
+			userId: customerId,
+			want: models.FineBalanceDTO{
+				Outstanding: 150,
+				Fines: []models.FineDTO{
+					{
+						ID:            accruing.ID.String(),
+						TransactionID: accruing.Transaction.ID.String(),
+						BookName:      "harry potter",
+						UserEmail:     "kaushik@a.com",
+						DaysOverdue:   3,
+						Amount:        150,
+						Outstanding:   150,
+						Status:        "accruing",
+					},
+				},
+			},
+			wantErr: false,
+			mockSetup: func() {
+				mockFineRepo.EXPECT().GetFines(customerId).Return([]models.Fine{accruing}, nil)
+			},
+		},
+		{
+			name:    "invalid user context",
+			ctx:     context.Background(),
+			want:    models.FineBalanceDTO{},
+			wantErr: true,
+			mockSetup: func() {
+			},
+		},
+		{
+			name: "repository error",
+			ctx: context.WithValue(context.Background(), "user", models.UserJwt{
+				Role: roles.Staff,
+			}),
+			want:    models.FineBalanceDTO{},
+			wantErr: true,
+			mockSetup: func() {
+				mockFineRepo.EXPECT().GetFines("").Return(nil, errors.New("db error"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := &FineService{
+				fineRepo: mockFineRepo,
+				policy:   testPolicy,
+			}
+			tt.mockSetup()
+			got, err := service.GetFines(tt.ctx, tt.userId)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("FineService.GetFines() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("FineService.GetFines() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFineService_RecordPayment(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockFineRepo := mocks.NewMockFineStorage(ctrl)
+
+	staffId := uuid.New().String()
+	fineId := uuid.New().String()
+	staffCtx := context.WithValue(context.Background(), "user", models.UserJwt{
+		RegisteredClaims: jwt.RegisteredClaims{Subject: staffId},
+		Role:             roles.Staff,
+	})
+
+	tests := []struct {
+		name      string
+		ctx       context.Context
+		fineId    string
+		amount    int
+		wantErr   bool
+		mockSetup func()
+	}{
+		{
+			name:    "valid payment",
+			ctx:     staffCtx,
+			fineId:  fineId,
+			amount:  100,
+			wantErr: false,
+			mockSetup: func() {
+				mockFineRepo.EXPECT().RecordPayment(fineId, paymentkind.Payment, 100, "", staffId).Return(nil)
+			},
+		},
+		{
+			name:    "invalid user context",
+			ctx:     context.Background(),
+			fineId:  fineId,
+			amount:  100,
+			wantErr: true,
+			mockSetup: func() {
+			},
+		},
+		{
+			name: "customer cannot record payment",
+			ctx: context.WithValue(context.Background(), "user", models.UserJwt{
+				Role: roles.Customer,
+			}),
+			fineId:  fineId,
+			amount:  100,
+			wantErr: true,
+			mockSetup: func() {
+			},
+		},
+		{
+			name:    "invalid fine id",
+			ctx:     staffCtx,
+			fineId:  "",
+			amount:  100,
+			wantErr: true,
+			mockSetup: func() {
+			},
+		},
+		{
+			name:    "non positive amount",
+			ctx:     staffCtx,
+			fineId:  fineId,
+			amount:  0,
+			wantErr: true,
+			mockSetup: func() {
+			},
+		},
+		{
+			name:    "overpayment",
+			ctx:     staffCtx,
+			fineId:  fineId,
+			amount:  5000,
+			wantErr: true,
+			mockSetup: func() {
+				mockFineRepo.EXPECT().RecordPayment(fineId, paymentkind.Payment, 5000, "", staffId).Return(errors.New("amount exceeds outstanding balance"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := &FineService{
+				fineRepo: mockFineRepo,
+				policy:   testPolicy,
+			}
+			tt.mockSetup()
+			if err := service.RecordPayment(tt.ctx, tt.fineId, tt.amount); (err != nil) != tt.wantErr {
+				t.Errorf("FineService.RecordPayment() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestFineService_WaiveFine(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockFineRepo := mocks.NewMockFineStorage(ctrl)
+
+	staffId := uuid.New().String()
+	fineId := uuid.New().String()
+	staffCtx := context.WithValue(context.Background(), "user", models.UserJwt{
+		RegisteredClaims: jwt.RegisteredClaims{Subject: staffId},
+		Role:             roles.Staff,
+	})
+
+	tests := []struct {
+		name      string
+		ctx       context.Context
+		amount    int
+		reason    string
+		wantErr   bool
+		mockSetup func()
+	}{
+		{
+			name:    "partial waive",
+			ctx:     staffCtx,
+			amount:  50,
+			reason:  "first offence",
+			wantErr: false,
+			mockSetup: func() {
+				mockFineRepo.EXPECT().RecordPayment(fineId, paymentkind.Waiver, 50, "first offence", staffId).Return(nil)
+			},
+		},
+		{
+			name: "librarian cannot waive",
+			ctx: context.WithValue(context.Background(), "user", models.UserJwt{
+				Role: roles.Librarian,
+			}),
+			amount:    50,
+			reason:    "first offence",
+			wantErr:   true,
+			mockSetup: func() {},
+		},
+		{
+			name:    "full waive of outstanding balance",
+			ctx:     staffCtx,
+			amount:  0,
+			reason:  "system outage",
+			wantErr: false,
+			mockSetup: func() {
+				mockFineRepo.EXPECT().GetFineById(fineId).Return(models.Fine{Amount: 300, Paid: 100, Waived: 50}, nil)
+				mockFineRepo.EXPECT().RecordPayment(fineId, paymentkind.Waiver, 150, "system outage", staffId).Return(nil)
+			},
+		},
+		{
+			name:    "fine already settled",
+			ctx:     staffCtx,
+			amount:  0,
+			reason:  "system outage",
+			wantErr: true,
+			mockSetup: func() {
+				mockFineRepo.EXPECT().GetFineById(fineId).Return(models.Fine{Amount: 300, Paid: 300}, nil)
+			},
+		},
+		{
+			name:    "missing reason",
+			ctx:     staffCtx,
+			amount:  50,
+			reason:  "",
+			wantErr: true,
+			mockSetup: func() {
+			},
+		},
+		{
+			name:    "negative amount",
+			ctx:     staffCtx,
+			amount:  -5,
+			reason:  "oops",
+			wantErr: true,
+			mockSetup: func() {
+			},
+		},
+		{
+			name: "customer cannot waive",
+			ctx: context.WithValue(context.Background(), "user", models.UserJwt{
+				Role: roles.Customer,
+			}),
+			amount:  50,
+			reason:  "please",
+			wantErr: true,
+			mockSetup: func() {
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := &FineService{
+				fineRepo: mockFineRepo,
+				policy:   testPolicy,
+			}
+			tt.mockSetup()
+			if err := service.WaiveFine(tt.ctx, fineId, tt.amount, tt.reason); (err != nil) != tt.wantErr {
+				t.Errorf("FineService.WaiveFine() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestFineService_AccrueFines(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockFineRepo := mocks.NewMockFineStorage(ctrl)
+	mockFineRepo.EXPECT().AccrueFines(50, 2000).Return(int64(4), nil)
+
+	service := &FineService{
+		fineRepo: mockFineRepo,
+		policy:   testPolicy,
+	}
+	got, err := service.AccrueFines()
+	if err != nil || got != 4 {
+		t.Errorf("FineService.AccrueFines() = %v, %v, want 4, nil", got, err)
+	}
+}
@@ -0,0 +1,15 @@
+package holdservice
+
+import (
+	"context"
+
+	"github.com/Kaushik1766/LibraryManagement/internal/models"
+)
+
+//go:generate mockgen -source=interface.go -destination=../../../mocks/mock_hold_manager.go -package=mocks
+type HoldManager interface {
+	PlaceHold(ctx context.Context, bookId string) (string, error)
+	GetHolds(ctx context.Context) ([]models.HoldDTO, error)
+	CancelHold(ctx context.Context, holdId string) error
+	ProcessExpiredHolds() (int, error)
+}
@@ -0,0 +1,130 @@
+package holdservice
+
+import (
+	"context"
+	"log"
+
+	apperrors "github.com/Kaushik1766/LibraryManagement/internal/app_errors"
+	"github.com/Kaushik1766/LibraryManagement/internal/config"
+	"github.com/Kaushik1766/LibraryManagement/internal/models"
+	"github.com/Kaushik1766/LibraryManagement/internal/rbac"
+	holdrepo "github.com/Kaushik1766/LibraryManagement/internal/repository/hold_repo"
+)
+
+type HoldService struct {
+	holdRepo holdrepo.HoldStorage
+	policy   config.CirculationConfig
+}
+
+func NewHoldService(holdRepo holdrepo.HoldStorage, policy config.CirculationConfig) *HoldService {
+	return &HoldService{
+		holdRepo: holdRepo,
+		policy:   policy,
+	}
+}
+
+// PlaceHold returns hold id with error
+func (service *HoldService) PlaceHold(ctx context.Context, bookId string) (string, error) {
+	userCtx, ok := ctx.Value("user").(models.UserJwt)
+	if !ok {
+		return "", apperrors.ErrInvalidUser
+	}
+
+	if !rbac.Can(userCtx.Role, rbac.HoldPlace) {
+		return "", apperrors.Forbidden("forbidden", "staff cant place hold")
+	}
+
+	if bookId == "" {
+		return "", apperrors.Validation("invalid_id", "invalid book id").WithDetail("book_id", "required")
+	}
+
+	return service.holdRepo.PlaceHold(bookId, userCtx.Subject)
+}
+
+func (service *HoldService) GetHolds(ctx context.Context) ([]models.HoldDTO, error) {
+	userCtx, ok := ctx.Value("user").(models.UserJwt)
+	if !ok {
+		return nil, apperrors.ErrInvalidUser
+	}
+
+	var holds []models.Hold
+	var err error
+	if rbac.Can(userCtx.Role, rbac.HoldManage) {
+		// hold managers can see the queue of every user
+		holds, err = service.holdRepo.GetActiveHolds("")
+	} else {
+		holds, err = service.holdRepo.GetActiveHolds(userCtx.Subject)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	var holdDto []models.HoldDTO
+	for _, val := range holds {
+		dto := models.HoldDTO{
+			ID:            val.ID.String(),
+			BookID:        val.Book.ID.String(),
+			BookName:      val.Book.Title,
+			Status:        string(val.Status),
+			QueuePosition: val.QueuePosition,
+			PlacedAt:      val.PlacedAt.String(),
+		}
+		if rbac.Can(userCtx.Role, rbac.PatronView) {
+			dto.UserEmail = val.User.Email
+		}
+		if val.Copy != nil {
+			dto.CopyID = val.Copy.ID.String()
+		}
+		if val.ExpiresAt != nil {
+			dto.PickupBy = val.ExpiresAt.String()
+		}
+		holdDto = append(holdDto, dto)
+	}
+
+	return holdDto, nil
+}
+
+func (service *HoldService) CancelHold(ctx context.Context, holdId string) error {
+	userCtx, ok := ctx.Value("user").(models.UserJwt)
+	if !ok {
+		return apperrors.ErrInvalidUser
+	}
+
+	if holdId == "" {
+		return apperrors.Validation("invalid_id", "invalid hold id").WithDetail("hold_id", "required")
+	}
+
+	userId := userCtx.Subject
+	if rbac.Can(userCtx.Role, rbac.HoldManage) {
+		userId = ""
+	}
+
+	copyId, err := service.holdRepo.CancelHold(holdId, userId)
+	if err != nil {
+		return err
+	}
+
+	// if the cancelled hold had a copy set aside it goes to the next patron
+	if copyId == "" {
+		return nil
+	}
+	_, err = service.holdRepo.AllocateNext(copyId, service.policy.HoldPickupWindow)
+	return err
+}
+
+// ProcessExpiredHolds expires uncollected holds, rolls each freed copy over to
+// the next patron in its title's queue and returns how many holds expired.
+func (service *HoldService) ProcessExpiredHolds() (int, error) {
+	copyIds, err := service.holdRepo.ExpireHolds()
+	if err != nil {
+		return 0, err
+	}
+
+	for _, copyId := range copyIds {
+		if _, err := service.holdRepo.AllocateNext(copyId, service.policy.HoldPickupWindow); err != nil {
+			log.Println(err)
+		}
+	}
+
+	return len(copyIds), nil
+}
@@ -0,0 +1,412 @@
+package holdservice
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/Kaushik1766/LibraryManagement/internal/config"
+	"github.com/Kaushik1766/LibraryManagement/internal/models"
+	"github.com/Kaushik1766/LibraryManagement/internal/models/enums/holdstatus"
+	"github.com/Kaushik1766/LibraryManagement/internal/models/enums/roles"
+	holdrepo "github.com/Kaushik1766/LibraryManagement/internal/repository/hold_repo"
+	"github.com/Kaushik1766/LibraryManagement/mocks"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"go.uber.org/mock/gomock"
+)
+
+var testPolicy = config.Default().Circulation
+
+func TestNewHoldService(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockHoldRepo := mocks.NewMockHoldStorage(ctrl)
+
+	type args struct {
+		holdRepo holdrepo.HoldStorage
+	}
+	tests := []struct {
+		name string
+		args args
+		want *HoldService
+	}{
+		{
+			name: "valid",
+			args: args{
+				holdRepo: mockHoldRepo,
+			},
+			want: &HoldService{
+				holdRepo: mockHoldRepo,
+				policy:   testPolicy,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NewHoldService(tt.args.holdRepo, testPolicy); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("NewHoldService() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHoldService_PlaceHold(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockHoldRepo := mocks.NewMockHoldStorage(ctrl)
+
+	type args struct {
+		ctx    context.Context
+		bookId string
+	}
+	tests := []struct {
+		name      string
+		args      args
+		want      string
+		wantErr   bool
+		mockSetup func()
+	}{
+		{
+			name: "valid place hold",
+			args: args{
+				ctx: context.WithValue(context.Background(), "user", models.UserJwt{
+					Email: "customer@example.com",
+					Role:  roles.Customer,
+				}),
+				bookId: "550e8400-e29b-41d4-a716-446655440000",
+			},
+			want:    "550e8400-e29b-41d4-a716-446655440001",
+			wantErr: false,
+			mockSetup: func() {
+				mockHoldRepo.EXPECT().PlaceHold("550e8400-e29b-41d4-a716-446655440000", gomock.Any()).Return("550e8400-e29b-41d4-a716-446655440001", nil)
+			},
+		},
+		{
+			name: "invalid user context",
+			args: args{
+				ctx:    context.Background(),
+				bookId: "550e8400-e29b-41d4-a716-446655440000",
+			},
+			want:    "",
+			wantErr: true,
+			mockSetup: func() {
+			},
+		},
+		{
+			name: "staff cannot place hold",
+			args: args{
+				ctx: context.WithValue(context.Background(), "user", models.UserJwt{
+					Email: "staff@example.com",
+					Role:  roles.Staff,
+				}),
+				bookId: "550e8400-e29b-41d4-a716-446655440000",
+			},
+			want:    "",
+			wantErr: true,
+			mockSetup: func() {
+			},
+		},
+		{
+			name: "invalid book id",
+			args: args{
+				ctx: context.WithValue(context.Background(), "user", models.UserJwt{
+					Role: roles.Customer,
+				}),
+				bookId: "",
+			},
+			want:    "",
+			wantErr: true,
+			mockSetup: func() {
+			},
+		},
+		{
+			name: "repository error",
+			args: args{
+				ctx: context.WithValue(context.Background(), "user", models.UserJwt{
+					Role: roles.Customer,
+				}),
+				bookId: "550e8400-e29b-41d4-a716-446655440000",
+			},
+			want:    "",
+			wantErr: true,
+			mockSetup: func() {
+				mockHoldRepo.EXPECT().PlaceHold(gomock.Any(), gomock.Any()).Return("", errors.New("hold not allowed"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := &HoldService{
+				holdRepo: mockHoldRepo,
+				policy:   testPolicy,
+			}
+			tt.mockSetup()
+			got, err := service.PlaceHold(tt.args.ctx, tt.args.bookId)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("HoldService.PlaceHold() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("HoldService.PlaceHold() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHoldService_GetHolds(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockHoldRepo := mocks.NewMockHoldStorage(ctrl)
+
+	userId := uuid.New().String()
+	expiresAt := time.Now().Add(48 * time.Hour)
+	hold := models.Hold{
+		ID:        uuid.New(),
+		Book:      models.Book{ID: uuid.New(), Title: "harry potter"},
+		User:      models.User{Email: "kaushik@a.com"},
+		Status:    holdstatus.Ready,
+		PlacedAt:  time.Now(),
+		ExpiresAt: &expiresAt,
+	}
+
+	tests := []struct {
+		name      string
+		ctx       context.Context
+		want      []models.HoldDTO
+		wantErr   bool
+		mockSetup func()
+	}{
+		{
+			name: "customer sees own holds",
+			ctx: context.WithValue(context.Background(), "user", models.UserJwt{
+				RegisteredClaims: jwt.RegisteredClaims{Subject: userId},
+				Role:             roles.Customer,
+			}),
+			want: []models.HoldDTO{
+				{
+					ID:       hold.ID.String(),
+					BookID:   hold.Book.ID.String(),
+					BookName: hold.Book.Title,
+					Status:   "ready",
+					PlacedAt: hold.PlacedAt.String(),
+					PickupBy: expiresAt.String(),
+				},
+			},
+			wantErr: false,
+			mockSetup: func() {
+				mockHoldRepo.EXPECT().GetActiveHolds(userId).Return([]models.Hold{hold}, nil)
+			},
+		},
+		{
+			name: "staff sees every hold",
+			ctx: context.WithValue(context.Background(), "user", models.UserJwt{
+				Role: roles.Staff,
+			}),
+			want: []models.HoldDTO{
+				{
+					ID:        hold.ID.String(),
+					BookID:    hold.Book.ID.String(),
+					BookName:  hold.Book.Title,
+					UserEmail: hold.User.Email,
+					Status:    "ready",
+					PlacedAt:  hold.PlacedAt.String(),
+					PickupBy:  expiresAt.String(),
+				},
+			},
+			wantErr: false,
+			mockSetup: func() {
+				mockHoldRepo.EXPECT().GetActiveHolds("").Return([]models.Hold{hold}, nil)
+			},
+		},
+		{
+			name:    "invalid user context",
+			ctx:     context.Background(),
+			want:    nil,
+			wantErr: true,
+			mockSetup: func() {
+			},
+		},
+		{
+			name: "repository error",
+			ctx: context.WithValue(context.Background(), "user", models.UserJwt{
+				Role: roles.Customer,
+			}),
+			want:    nil,
+			wantErr: true,
+			mockSetup: func() {
+				mockHoldRepo.EXPECT().GetActiveHolds(gomock.Any()).Return(nil, errors.New("db error"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := &HoldService{
+				holdRepo: mockHoldRepo,
+				policy:   testPolicy,
+			}
+			tt.mockSetup()
+			got, err := service.GetHolds(tt.ctx)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("HoldService.GetHolds() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("HoldService.GetHolds() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHoldService_CancelHold(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockHoldRepo := mocks.NewMockHoldStorage(ctrl)
+
+	holdId := uuid.New().String()
+	copyId := uuid.New().String()
+
+	tests := []struct {
+		name      string
+		ctx       context.Context
+		holdId    string
+		wantErr   bool
+		mockSetup func()
+	}{
+		{
+			name: "customer cancels own hold",
+			ctx: context.WithValue(context.Background(), "user", models.UserJwt{
+				Role: roles.Customer,
+			}),
+			holdId:  holdId,
+			wantErr: false,
+			mockSetup: func() {
+				mockHoldRepo.EXPECT().CancelHold(holdId, gomock.Any()).Return(copyId, nil)
+				mockHoldRepo.EXPECT().AllocateNext(copyId, "2 days").Return("", nil)
+			},
+		},
+		{
+			name: "staff cancels any hold",
+			ctx: context.WithValue(context.Background(), "user", models.UserJwt{
+				Role: roles.Staff,
+			}),
+			holdId:  holdId,
+			wantErr: false,
+			mockSetup: func() {
+				mockHoldRepo.EXPECT().CancelHold(holdId, "").Return(copyId, nil)
+				mockHoldRepo.EXPECT().AllocateNext(copyId, "2 days").Return(uuid.New().String(), nil)
+			},
+		},
+		{
+			name: "cancelling a waiting hold frees no copy",
+			ctx: context.WithValue(context.Background(), "user", models.UserJwt{
+				Role: roles.Customer,
+			}),
+			holdId:  holdId,
+			wantErr: false,
+			mockSetup: func() {
+				mockHoldRepo.EXPECT().CancelHold(holdId, gomock.Any()).Return("", nil)
+			},
+		},
+		{
+			name:    "invalid user context",
+			ctx:     context.Background(),
+			holdId:  holdId,
+			wantErr: true,
+			mockSetup: func() {
+			},
+		},
+		{
+			name: "invalid hold id",
+			ctx: context.WithValue(context.Background(), "user", models.UserJwt{
+				Role: roles.Customer,
+			}),
+			holdId:  "",
+			wantErr: true,
+			mockSetup: func() {
+			},
+		},
+		{
+			name: "no active hold",
+			ctx: context.WithValue(context.Background(), "user", models.UserJwt{
+				Role: roles.Customer,
+			}),
+			holdId:  holdId,
+			wantErr: true,
+			mockSetup: func() {
+				mockHoldRepo.EXPECT().CancelHold(holdId, gomock.Any()).Return("", errors.New("no active hold found"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := &HoldService{
+				holdRepo: mockHoldRepo,
+				policy:   testPolicy,
+			}
+			tt.mockSetup()
+			if err := service.CancelHold(tt.ctx, tt.holdId); (err != nil) != tt.wantErr {
+				t.Errorf("HoldService.CancelHold() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestHoldService_ProcessExpiredHolds(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockHoldRepo := mocks.NewMockHoldStorage(ctrl)
+
+	bookId1 := uuid.New().String()
+	bookId2 := uuid.New().String()
+
+	tests := []struct {
+		name      string
+		want      int
+		wantErr   bool
+		mockSetup func()
+	}{
+		{
+			name:    "rolls expired holds to next patron",
+			want:    2,
+			wantErr: false,
+			mockSetup: func() {
+				mockHoldRepo.EXPECT().ExpireHolds().Return([]string{bookId1, bookId2}, nil)
+				mockHoldRepo.EXPECT().AllocateNext(bookId1, "2 days").Return(uuid.New().String(), nil)
+				mockHoldRepo.EXPECT().AllocateNext(bookId2, "2 days").Return("", errors.New("db error"))
+			},
+		},
+		{
+			name:    "repository error",
+			want:    0,
+			wantErr: true,
+			mockSetup: func() {
+				mockHoldRepo.EXPECT().ExpireHolds().Return(nil, errors.New("db error"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := &HoldService{
+				holdRepo: mockHoldRepo,
+				policy:   testPolicy,
+			}
+			tt.mockSetup()
+			got, err := service.ProcessExpiredHolds()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("HoldService.ProcessExpiredHolds() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("HoldService.ProcessExpiredHolds() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -4,12 +4,16 @@ import (
 	"context"
 
 	"github.com/Kaushik1766/LibraryManagement/internal/models"
+	"github.com/Kaushik1766/LibraryManagement/internal/pagination"
 )
 
 //go:generate mockgen -source=interface.go -destination=../../../mocks/mock_transaction_manager.go -package=mocks
 type TransactionManager interface {
 	IssueBook(ctx context.Context, bookId, issueFor string) (string, error)
 	ReturnBook(ctx context.Context, bookId string) error
-	GetTransactions(ctx context.Context, dto models.GetTransactionRequestDTO) ([]models.TransactionDTO, error)
-	GetOverdueTransactions(ctx context.Context) ([]models.OverdueTransactionDTO, error)
+	RenewBook(ctx context.Context, transactionId string) (models.RenewalDTO, error)
+	GetTransactions(ctx context.Context, dto models.GetTransactionRequestDTO, page pagination.Request) (pagination.Page[models.TransactionDTO], error)
+	GetOverdueTransactions(ctx context.Context, page pagination.Request) (pagination.Page[models.OverdueTransactionDTO], error)
+	DeskIssue(ctx context.Context, req models.DeskIssueDTO) (string, error)
+	DeskReturn(ctx context.Context, req models.DeskReturnDTO) error
 }
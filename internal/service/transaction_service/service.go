@@ -2,113 +2,244 @@ package transactionservice
 
 import (
 	"context"
-	"errors"
+	"log"
+	"strings"
 	"time"
 
+	apperrors "github.com/Kaushik1766/LibraryManagement/internal/app_errors"
+	"github.com/Kaushik1766/LibraryManagement/internal/config"
+	loanpolicy "github.com/Kaushik1766/LibraryManagement/internal/loan_policy"
 	"github.com/Kaushik1766/LibraryManagement/internal/models"
-	"github.com/Kaushik1766/LibraryManagement/internal/models/enums/roles"
+	"github.com/Kaushik1766/LibraryManagement/internal/pagination"
+	patronblocks "github.com/Kaushik1766/LibraryManagement/internal/patron_blocks"
+	"github.com/Kaushik1766/LibraryManagement/internal/rbac"
+	blockrepo "github.com/Kaushik1766/LibraryManagement/internal/repository/block_repo"
 	bookrepo "github.com/Kaushik1766/LibraryManagement/internal/repository/book_repo"
+	finerepo "github.com/Kaushik1766/LibraryManagement/internal/repository/fine_repo"
+	holdrepo "github.com/Kaushik1766/LibraryManagement/internal/repository/hold_repo"
 	transactionrepo "github.com/Kaushik1766/LibraryManagement/internal/repository/transaction_repo"
+	userrepo "github.com/Kaushik1766/LibraryManagement/internal/repository/user_repo"
+	"github.com/google/uuid"
 )
 
 type TransactionService struct {
 	bookRepo        bookrepo.BookStorage
 	transactionRepo transactionrepo.TransactionStorage
+	holdRepo        holdrepo.HoldStorage
+	fineRepo        finerepo.FineStorage
+	userRepo        userrepo.UserStorage
+	policy          config.CirculationConfig
+	loans           *loanpolicy.Engine
+	blocks          *patronblocks.Checker
 }
 
-func (service *TransactionService) GetOverdueTransactions(ctx context.Context) ([]models.OverdueTransactionDTO, error) {
+func (service *TransactionService) GetOverdueTransactions(ctx context.Context, page pagination.Request) (pagination.Page[models.OverdueTransactionDTO], error) {
 	userCtx, ok := ctx.Value("user").(models.UserJwt)
 
 	if !ok {
-		return nil, errors.New("invalid user")
+		return pagination.Page[models.OverdueTransactionDTO]{}, apperrors.ErrInvalidUser
 	}
 
-	var overdueTransactions []models.Transaction
+	var overdueTransactions pagination.Page[models.Transaction]
 	var err error
-	if userCtx.Role == roles.Staff {
-		// staff can get overdue transactions of all users
-		overdueTransactions, err = service.transactionRepo.GetOverDueTransactions("")
+	if rbac.Can(userCtx.Role, rbac.LoanViewAll) {
+		// staff with loan:view-all get overdue transactions of all users
+		overdueTransactions, err = service.transactionRepo.GetOverDueTransactions("", page)
 	} else {
-		overdueTransactions, err = service.transactionRepo.GetOverDueTransactions(userCtx.ID)
+		overdueTransactions, err = service.transactionRepo.GetOverDueTransactions(userCtx.Subject, page)
 	}
 	if err != nil {
-		return nil, err
+		return pagination.Page[models.OverdueTransactionDTO]{}, err
 	}
 
-	var overdueDto []models.OverdueTransactionDTO
-	for _, val := range overdueTransactions {
+	return pagination.Map(overdueTransactions, func(val models.Transaction) models.OverdueTransactionDTO {
 		var returnedAt string
 		if val.ReturnedAt == nil {
 			returnedAt = "not yet returned"
 		} else {
 			returnedAt = val.ReturnedAt.String()
 		}
-		overdueDto = append(overdueDto, models.OverdueTransactionDTO{
+		return models.OverdueTransactionDTO{
 			ID:         val.ID.String(),
 			BookID:     val.Book.ID.String(),
+			CopyID:     val.Copy.ID.String(),
 			BookName:   val.Book.Title,
 			IssuedAt:   val.IssuedAt.String(),
 			IssuedTill: val.IssuedTill.String(),
 			ReturnedAt: returnedAt,
-		})
-	}
-
-	return overdueDto, nil
+		}
+	}), nil
 }
 
-func NewTransactionService(bookRepo bookrepo.BookStorage, transactionRepo transactionrepo.TransactionStorage) *TransactionService {
+func NewTransactionService(bookRepo bookrepo.BookStorage, transactionRepo transactionrepo.TransactionStorage, holdRepo holdrepo.HoldStorage, fineRepo finerepo.FineStorage, userRepo userrepo.UserStorage, blockRepo blockrepo.BlockStorage, policy config.CirculationConfig) *TransactionService {
 	return &TransactionService{
 		bookRepo:        bookRepo,
 		transactionRepo: transactionRepo,
+		holdRepo:        holdRepo,
+		fineRepo:        fineRepo,
+		userRepo:        userRepo,
+		policy:          policy,
+		loans:           loanpolicy.New(policy),
+		blocks:          patronblocks.NewChecker(blockRepo, policy),
 	}
 }
 
-// IssueBook returns transaction id with error
+// IssueBook returns transaction id with error. Patrons who haven't verified
+// their email and blocked patrons can't borrow, and the loan policy for the
+// user's role and the title's item type decides whether they can borrow it
+// and for how long.
 func (service *TransactionService) IssueBook(ctx context.Context, bookId, issueFor string) (string, error) {
 	userCtx, ok := ctx.Value("user").(models.UserJwt)
 	if !ok {
-		return "", errors.New("invalid user")
+		return "", apperrors.ErrInvalidUser
+	}
+
+	if !rbac.Can(userCtx.Role, rbac.LoanBorrow) {
+		return "", apperrors.Forbidden("forbidden", "staff cant issue book")
+	}
+
+	if _, err := uuid.Parse(bookId); err != nil {
+		return "", apperrors.Validation("invalid_id", "invalid book id").WithDetail("book_id", "required")
+	}
+
+	user, err := service.userRepo.GetUserById(userCtx.Subject)
+	if err != nil {
+		return "", err
+	}
+	if user.EmailVerifiedAt == nil {
+		return "", apperrors.Forbidden("email_not_verified", "verify your email address before borrowing")
 	}
 
-	if userCtx.Role != roles.Customer {
-		return "", errors.New("staff cant issue book")
+	if err := service.blocks.Check(userCtx.Subject); err != nil {
+		return "", err
+	}
+
+	book, err := service.bookRepo.GetBookById(bookId)
+	if err != nil {
+		return "", err
 	}
 
-	if bookId == "" {
-		return "", errors.New("invalid book id")
+	terms, err := service.loanTerms(userCtx.Subject, service.loans.For(userCtx.Role, book.ItemType), issueFor)
+	if err != nil {
+		return "", err
+	}
+
+	transactionId, err := service.transactionRepo.IssueBook(bookId, userCtx.Subject, terms)
+	if err != nil {
+		return "", err
 	}
 
-	if issueFor == "" {
-		issueFor = "1 day"
+	// picking up a held copy closes the hold
+	if err := service.holdRepo.FulfillHold(bookId, userCtx.Subject); err != nil {
+		log.Println(err)
 	}
 
-	return service.transactionRepo.IssueBook(bookId, userCtx.ID, issueFor)
+	return transactionId, nil
 }
 
 func (service *TransactionService) ReturnBook(ctx context.Context, bookId string) error {
 	userCtx, ok := ctx.Value("user").(models.UserJwt)
 	if !ok {
-		return errors.New("invalid user")
+		return apperrors.ErrInvalidUser
 	}
 
-	if userCtx.Role != roles.Customer {
-		return errors.New("staff cant return book")
+	if !rbac.Can(userCtx.Role, rbac.LoanBorrow) {
+		return apperrors.Forbidden("forbidden", "staff cant return book")
 	}
 
-	if bookId == "" {
-		return errors.New("invalid book id")
+	if _, err := uuid.Parse(bookId); err != nil {
+		return apperrors.Validation("invalid_id", "invalid book id").WithDetail("book_id", "required")
 	}
 
-	return service.transactionRepo.ReturnBook(bookId, userCtx.ID)
+	transaction, err := service.transactionRepo.ReturnBook(bookId, userCtx.Subject)
+	if err != nil {
+		return err
+	}
+
+	// the return itself already succeeded so failures from here on are only logged
+	if err := service.fineRepo.AssessFine(transaction.ID.String(), service.policy.FinePerDay, service.policy.FineCap); err != nil {
+		log.Println(err)
+	}
+
+	// the copy is back on the shelf, set it aside for the next patron in line
+	if _, err := service.holdRepo.AllocateNext(transaction.Copy.ID.String(), service.policy.HoldPickupWindow); err != nil {
+		log.Println(err)
+	}
+
+	return nil
 }
 
-func (service *TransactionService) GetTransactions(ctx context.Context, dto models.GetTransactionRequestDTO) ([]models.TransactionDTO, error) {
+// RenewBook extends an open loan by the renewal period of its loan policy.
+// Loans that are overdue, out of renewals or wanted by another patron can't
+// be renewed, nor can any loan of a blocked patron.
+func (service *TransactionService) RenewBook(ctx context.Context, transactionId string) (models.RenewalDTO, error) {
 	userCtx, ok := ctx.Value("user").(models.UserJwt)
 	if !ok {
-		return nil, errors.New("invalid user")
+		return models.RenewalDTO{}, apperrors.ErrInvalidUser
+	}
+
+	if !rbac.Can(userCtx.Role, rbac.LoanBorrow) {
+		return models.RenewalDTO{}, apperrors.Forbidden("forbidden", "staff cant renew book")
 	}
 
-	dto.UserId = userCtx.ID
+	if _, err := uuid.Parse(transactionId); err != nil {
+		return models.RenewalDTO{}, apperrors.Validation("invalid_id", "invalid transaction id").WithDetail("transaction_id", "required")
+	}
+
+	transaction, err := service.transactionRepo.GetTransactionById(transactionId)
+	if err != nil {
+		return models.RenewalDTO{}, err
+	}
+
+	if transaction.User.ID.String() != userCtx.Subject {
+		return models.RenewalDTO{}, apperrors.NotFound("transaction_not_found", "transaction not found")
+	}
+
+	if transaction.ReturnedAt != nil {
+		return models.RenewalDTO{}, apperrors.Conflict("already_returned", "book already returned")
+	}
+
+	if transaction.IssuedTill.Before(time.Now()) {
+		return models.RenewalDTO{}, apperrors.Conflict("renewal_not_allowed", "loan is overdue and cannot be renewed")
+	}
+
+	if err := service.blocks.Check(userCtx.Subject); err != nil {
+		return models.RenewalDTO{}, err
+	}
+
+	loanPolicy := service.loans.For(userCtx.Role, transaction.Book.ItemType)
+	if err := loanPolicy.CheckRenewal(transaction.Renewals); err != nil {
+		return models.RenewalDTO{}, err
+	}
+
+	held, err := service.holdRepo.HasWaitingHolds(transaction.Book.ID.String())
+	if err != nil {
+		return models.RenewalDTO{}, err
+	}
+	if held {
+		return models.RenewalDTO{}, apperrors.Conflict("renewal_not_allowed", "book has pending holds and cannot be renewed")
+	}
+
+	issuedTill, err := service.transactionRepo.RenewBook(transactionId, config.FormatPeriod(loanPolicy.RenewalPeriod), loanPolicy.MaxRenewals)
+	if err != nil {
+		return models.RenewalDTO{}, err
+	}
+
+	return models.RenewalDTO{
+		TransactionID: transactionId,
+		IssuedTill:    issuedTill.String(),
+		RenewalsUsed:  transaction.Renewals + 1,
+		RenewalsLeft:  loanPolicy.MaxRenewals - transaction.Renewals - 1,
+	}, nil
+}
+
+func (service *TransactionService) GetTransactions(ctx context.Context, dto models.GetTransactionRequestDTO, page pagination.Request) (pagination.Page[models.TransactionDTO], error) {
+	userCtx, ok := ctx.Value("user").(models.UserJwt)
+	if !ok {
+		return pagination.Page[models.TransactionDTO]{}, apperrors.ErrInvalidUser
+	}
+
+	dto.UserId = userCtx.Subject
 
 	if dto.StartTime == "" {
 		dto.StartTime = time.Now().AddDate(0, -1, 0).Format(time.RFC3339)
@@ -118,14 +249,12 @@ func (service *TransactionService) GetTransactions(ctx context.Context, dto mode
 		dto.EndTime = time.Now().Format(time.RFC3339)
 	}
 
-	transactions, err := service.transactionRepo.GetAllTransactions(dto)
+	transactions, err := service.transactionRepo.GetAllTransactions(dto, page)
 	if err != nil {
-		return nil, err
+		return pagination.Page[models.TransactionDTO]{}, err
 	}
 
-	var txDto []models.TransactionDTO
-	for _, val := range transactions {
-
+	return pagination.Map(transactions, func(val models.Transaction) models.TransactionDTO {
 		var returnedAt string
 		if val.ReturnedAt == nil {
 			returnedAt = "not returned yet"
@@ -133,16 +262,188 @@ func (service *TransactionService) GetTransactions(ctx context.Context, dto mode
 			returnedAt = val.ReturnedAt.String()
 		}
 
-		txDto = append(txDto, models.TransactionDTO{
+		dto := models.TransactionDTO{
 			ID:         val.ID.String(),
 			BookID:     val.Book.ID.String(),
+			CopyID:     val.Copy.ID.String(),
 			BookName:   val.Book.Title,
 			UserEmail:  val.User.Email,
 			IssuedAt:   val.IssuedAt.String(),
 			IssuedTill: val.IssuedTill.String(),
 			ReturnedAt: returnedAt,
-		})
+		}
+		if val.IssuedBy != nil {
+			dto.IssuedBy = val.IssuedBy.Email
+		}
+		if val.ReturnedBy != nil {
+			dto.ReturnedBy = val.ReturnedBy.Email
+		}
+		return dto
+	}), nil
+}
+
+// DeskIssue lends a copy to a patron at the circulation desk. The acting staff
+// member is recorded on the transaction.
+func (service *TransactionService) DeskIssue(ctx context.Context, req models.DeskIssueDTO) (string, error) {
+	userCtx, ok := ctx.Value("user").(models.UserJwt)
+	if !ok {
+		return "", apperrors.ErrInvalidUser
+	}
+
+	if !rbac.Can(userCtx.Role, rbac.LoanIssueForOthers) {
+		return "", apperrors.ErrUnauthorisedUser
+	}
+
+	if req.Patron == "" || req.Copy == "" {
+		return "", apperrors.Validation("missing_fields", "patron and copy are required")
+	}
+
+	patron, err := service.findPatron(req.Patron)
+	if err != nil {
+		return "", err
+	}
+
+	if patron.DeactivatedAt != nil {
+		return "", apperrors.Conflict("patron_deactivated", "patron account is deactivated")
+	}
+
+	if !rbac.Can(patron.Role, rbac.LoanBorrow) {
+		return "", apperrors.Conflict("patron_cannot_borrow", "user cant borrow books")
+	}
+
+	if patron.EmailVerifiedAt == nil {
+		return "", apperrors.Conflict("email_not_verified", "patron has not verified their email address")
+	}
+
+	if err := service.blocks.Check(patron.ID.String()); err != nil {
+		return "", err
+	}
+
+	found, err := service.findCopy(req.Copy)
+	if err != nil {
+		return "", err
+	}
+
+	book, err := service.bookRepo.GetBookById(found.BookID.String())
+	if err != nil {
+		return "", err
+	}
+
+	// the desk lends on the patron's terms, not those of the staff member
+	terms, err := service.loanTerms(patron.ID.String(), service.loans.For(patron.Role, book.ItemType), req.IssueFor)
+	if err != nil {
+		return "", err
+	}
+
+	transaction, err := service.transactionRepo.IssueCopy(found.ID.String(), patron.ID.String(), userCtx.Subject, terms)
+	if err != nil {
+		return "", err
+	}
+
+	if err := service.holdRepo.FulfillHold(transaction.Book.ID.String(), patron.ID.String()); err != nil {
+		log.Println(err)
+	}
+
+	return transaction.ID.String(), nil
+}
+
+// DeskReturn checks a copy in at the circulation desk. Fines and hold
+// allocation happen the same way as for a self service return.
+func (service *TransactionService) DeskReturn(ctx context.Context, req models.DeskReturnDTO) error {
+	userCtx, ok := ctx.Value("user").(models.UserJwt)
+	if !ok {
+		return apperrors.ErrInvalidUser
+	}
+
+	if !rbac.Can(userCtx.Role, rbac.LoanIssueForOthers) {
+		return apperrors.ErrUnauthorisedUser
+	}
+
+	if req.Copy == "" {
+		return apperrors.Validation("missing_fields", "copy is required").WithDetail("copy", "required")
+	}
+
+	var patronId string
+	if req.Patron != "" {
+		patron, err := service.findPatron(req.Patron)
+		if err != nil {
+			return err
+		}
+		patronId = patron.ID.String()
+	}
+
+	found, err := service.findCopy(req.Copy)
+	if err != nil {
+		return err
+	}
+	copyId := found.ID.String()
+
+	transaction, err := service.transactionRepo.ReturnCopy(copyId, patronId, userCtx.Subject)
+	if err != nil {
+		return err
+	}
+
+	if err := service.fineRepo.AssessFine(transaction.ID.String(), service.policy.FinePerDay, service.policy.FineCap); err != nil {
+		log.Println(err)
+	}
+
+	if _, err := service.holdRepo.AllocateNext(copyId, service.policy.HoldPickupWindow); err != nil {
+		log.Println(err)
+	}
+
+	return nil
+}
+
+// findPatron looks a user up by id, email or library card number.
+func (service *TransactionService) findPatron(identifier string) (models.User, error) {
+	if _, err := uuid.Parse(identifier); err == nil {
+		return service.userRepo.GetUserById(identifier)
+	}
+
+	if strings.Contains(identifier, "@") {
+		return service.userRepo.GetUserByEmail(identifier)
+	}
+
+	return service.userRepo.GetUserByCardNumber(identifier)
+}
+
+// findCopy looks a copy up by id or barcode.
+func (service *TransactionService) findCopy(identifier string) (models.Copy, error) {
+	if _, err := uuid.Parse(identifier); err == nil {
+		return service.bookRepo.GetCopyById(identifier)
+	}
+
+	return service.bookRepo.GetCopyByBarcode(identifier)
+}
+
+// loanTerms checks a new loan for userId against policy and fixes its period
+// and fine rates. An empty issueFor gets the policy's default period.
+func (service *TransactionService) loanTerms(userId string, policy loanpolicy.Policy, issueFor string) (models.LoanTerms, error) {
+	period, err := policy.LoanPeriodFor(issueFor)
+	if err != nil {
+		return models.LoanTerms{}, err
+	}
+
+	openLoans, err := service.transactionRepo.CountOpenLoans(userId, "")
+	if err != nil {
+		return models.LoanTerms{}, err
+	}
+
+	openItemLoans := 0
+	if policy.HasItemLimit {
+		openItemLoans, err = service.transactionRepo.CountOpenLoans(userId, policy.ItemType)
+		if err != nil {
+			return models.LoanTerms{}, err
+		}
+	}
+
+	if err := policy.CheckLoans(openLoans, openItemLoans); err != nil {
+		return models.LoanTerms{}, err
 	}
 
-	return txDto, nil
+	return models.LoanTerms{
+		Period:     config.FormatPeriod(period),
+		FinePerDay: policy.FinePerDay,
+		FineCap:    policy.FineCap,
+	}, nil
 }
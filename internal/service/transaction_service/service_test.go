@@ -7,25 +7,65 @@ import (
 	"testing"
 	"time"
 
+	"github.com/Kaushik1766/LibraryManagement/internal/config"
+	loanpolicy "github.com/Kaushik1766/LibraryManagement/internal/loan_policy"
 	"github.com/Kaushik1766/LibraryManagement/internal/models"
+	"github.com/Kaushik1766/LibraryManagement/internal/models/enums/blockkind"
 	"github.com/Kaushik1766/LibraryManagement/internal/models/enums/roles"
+	"github.com/Kaushik1766/LibraryManagement/internal/pagination"
+	patronblocks "github.com/Kaushik1766/LibraryManagement/internal/patron_blocks"
+	blockrepo "github.com/Kaushik1766/LibraryManagement/internal/repository/block_repo"
 	bookrepo "github.com/Kaushik1766/LibraryManagement/internal/repository/book_repo"
+	finerepo "github.com/Kaushik1766/LibraryManagement/internal/repository/fine_repo"
+	holdrepo "github.com/Kaushik1766/LibraryManagement/internal/repository/hold_repo"
 	transactionrepo "github.com/Kaushik1766/LibraryManagement/internal/repository/transaction_repo"
+	userrepo "github.com/Kaushik1766/LibraryManagement/internal/repository/user_repo"
 	"github.com/Kaushik1766/LibraryManagement/mocks"
+	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
 	"go.uber.org/mock/gomock"
 )
 
+var testPolicy = func() config.CirculationConfig {
+	policy := config.Default().Circulation
+	none, one, ten := 0, 1, 10
+	policy.Rules = []config.LoanRule{
+		{ItemType: "reference", MaxLoans: &none},
+		{ItemType: "dvd", MaxLoans: &ten, RenewalPeriod: "3 days", MaxRenewals: &one},
+	}
+	return policy
+}()
+
+// notBlocked expects a block check of userId that finds nothing.
+func notBlocked(blockRepo *mocks.MockBlockStorage, userId any) {
+	blockRepo.EXPECT().GetActiveBlocks(userId).Return(nil, nil)
+	blockRepo.EXPECT().GetStanding(userId).Return(models.Standing{}, nil)
+}
+
+// verified expects the borrower to be looked up and have a verified email.
+func verified(userRepo *mocks.MockUserStorage, userId any) {
+	verifiedAt := time.Now()
+	userRepo.EXPECT().GetUserById(userId).Return(models.User{EmailVerifiedAt: &verifiedAt}, nil)
+}
+
+func loanTerms(period string) models.LoanTerms {
+	return models.LoanTerms{Period: period, FinePerDay: testPolicy.FinePerDay, FineCap: testPolicy.FineCap}
+}
+
 func TestTransactionService_GetOverdueTransactions(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
 	mockBookRepo := mocks.NewMockBookStorage(ctrl)
 	mockTransactionRepo := mocks.NewMockTransactionStorage(ctrl)
+	mockHoldRepo := mocks.NewMockHoldStorage(ctrl)
+	mockFineRepo := mocks.NewMockFineStorage(ctrl)
 
 	type fields struct {
 		bookRepo        bookrepo.BookStorage
 		transactionRepo transactionrepo.TransactionStorage
+		holdRepo        holdrepo.HoldStorage
+		fineRepo        finerepo.FineStorage
 	}
 	type args struct {
 		ctx context.Context
@@ -34,7 +74,7 @@ func TestTransactionService_GetOverdueTransactions(t *testing.T) {
 		name      string
 		fields    fields
 		args      args
-		want      []models.OverdueTransactionDTO
+		want      pagination.Page[models.OverdueTransactionDTO]
 		wantErr   bool
 		mockSetup func()
 	}{
@@ -43,6 +83,8 @@ func TestTransactionService_GetOverdueTransactions(t *testing.T) {
 			fields: fields{
 				bookRepo:        mockBookRepo,
 				transactionRepo: mockTransactionRepo,
+				holdRepo:        mockHoldRepo,
+				fineRepo:        mockFineRepo,
 			},
 			args: args{
 				ctx: context.WithValue(context.Background(), "user", models.UserJwt{
@@ -50,28 +92,30 @@ func TestTransactionService_GetOverdueTransactions(t *testing.T) {
 					Role:  roles.Staff,
 				}),
 			},
-			want: []models.OverdueTransactionDTO{
+			want: pagination.Page[models.OverdueTransactionDTO]{Items: []models.OverdueTransactionDTO{
 				{
 					ID:         "550e8400-e29b-41d4-a716-446655440000",
 					BookID:     "550e8400-e29b-41d4-a716-446655440001",
+					CopyID:     "6ba7b810-9dad-11d1-80b4-00c04fd43001",
 					BookName:   "Test Book",
 					IssuedAt:   "2025-08-30 03:00:43 +0530 IST",
 					IssuedTill: "2025-09-04 03:00:43 +0530 IST",
 					ReturnedAt: "not yet returned",
 				},
-			},
+			}},
 			wantErr: false,
 			mockSetup: func() {
-				mockTransactionRepo.EXPECT().GetOverDueTransactions("").Return([]models.Transaction{
+				mockTransactionRepo.EXPECT().GetOverDueTransactions("", pagination.Request{}).Return(pagination.Page[models.Transaction]{Items: []models.Transaction{
 					{
 						ID:         uuid.MustParse("550e8400-e29b-41d4-a716-446655440000"),
 						Book:       models.Book{ID: uuid.MustParse("550e8400-e29b-41d4-a716-446655440001"), Title: "Test Book"},
+						Copy:       models.Copy{ID: uuid.MustParse("6ba7b810-9dad-11d1-80b4-00c04fd43001")},
 						User:       models.User{Email: "user@example.com"},
 						IssuedAt:   time.Date(2025, 8, 30, 3, 0, 43, 0, time.FixedZone("IST", 19800)),
 						IssuedTill: time.Date(2025, 9, 4, 3, 0, 43, 0, time.FixedZone("IST", 19800)),
 						ReturnedAt: nil,
 					},
-				}, nil)
+				}}, nil)
 			},
 		},
 		{
@@ -79,6 +123,8 @@ func TestTransactionService_GetOverdueTransactions(t *testing.T) {
 			fields: fields{
 				bookRepo:        mockBookRepo,
 				transactionRepo: mockTransactionRepo,
+				holdRepo:        mockHoldRepo,
+				fineRepo:        mockFineRepo,
 			},
 			args: args{
 				ctx: context.WithValue(context.Background(), "user", models.UserJwt{
@@ -86,28 +132,30 @@ func TestTransactionService_GetOverdueTransactions(t *testing.T) {
 					Role:  roles.Customer,
 				}),
 			},
-			want: []models.OverdueTransactionDTO{
+			want: pagination.Page[models.OverdueTransactionDTO]{Items: []models.OverdueTransactionDTO{
 				{
 					ID:         "550e8400-e29b-41d4-a716-446655440002",
 					BookID:     "550e8400-e29b-41d4-a716-446655440003",
+					CopyID:     "6ba7b810-9dad-11d1-80b4-00c04fd43003",
 					BookName:   "Test Book",
 					IssuedAt:   "2025-08-30 03:00:43 +0530 IST",
 					IssuedTill: "2025-09-04 03:00:43 +0530 IST",
 					ReturnedAt: "not yet returned",
 				},
-			},
+			}},
 			wantErr: false,
 			mockSetup: func() {
-				mockTransactionRepo.EXPECT().GetOverDueTransactions(gomock.Any()).Return([]models.Transaction{
+				mockTransactionRepo.EXPECT().GetOverDueTransactions(gomock.Any(), pagination.Request{}).Return(pagination.Page[models.Transaction]{Items: []models.Transaction{
 					{
 						ID:         uuid.MustParse("550e8400-e29b-41d4-a716-446655440002"),
 						Book:       models.Book{ID: uuid.MustParse("550e8400-e29b-41d4-a716-446655440003"), Title: "Test Book"},
+						Copy:       models.Copy{ID: uuid.MustParse("6ba7b810-9dad-11d1-80b4-00c04fd43003")},
 						User:       models.User{Email: "customer@example.com"},
 						IssuedAt:   time.Date(2025, 8, 30, 3, 0, 43, 0, time.FixedZone("IST", 19800)),
 						IssuedTill: time.Date(2025, 9, 4, 3, 0, 43, 0, time.FixedZone("IST", 19800)),
 						ReturnedAt: nil,
 					},
-				}, nil)
+				}}, nil)
 			},
 		},
 		{
@@ -115,11 +163,13 @@ func TestTransactionService_GetOverdueTransactions(t *testing.T) {
 			fields: fields{
 				bookRepo:        mockBookRepo,
 				transactionRepo: mockTransactionRepo,
+				holdRepo:        mockHoldRepo,
+				fineRepo:        mockFineRepo,
 			},
 			args: args{
 				ctx: context.Background(),
 			},
-			want:    nil,
+			want:    pagination.Page[models.OverdueTransactionDTO]{},
 			wantErr: true,
 			mockSetup: func() {
 			},
@@ -129,6 +179,8 @@ func TestTransactionService_GetOverdueTransactions(t *testing.T) {
 			fields: fields{
 				bookRepo:        mockBookRepo,
 				transactionRepo: mockTransactionRepo,
+				holdRepo:        mockHoldRepo,
+				fineRepo:        mockFineRepo,
 			},
 			args: args{
 				ctx: context.WithValue(context.Background(), "user", models.UserJwt{
@@ -136,10 +188,10 @@ func TestTransactionService_GetOverdueTransactions(t *testing.T) {
 					Role:  roles.Staff,
 				}),
 			},
-			want:    nil,
+			want:    pagination.Page[models.OverdueTransactionDTO]{},
 			wantErr: true,
 			mockSetup: func() {
-				mockTransactionRepo.EXPECT().GetOverDueTransactions("").Return(nil, errors.New("repository error"))
+				mockTransactionRepo.EXPECT().GetOverDueTransactions("", pagination.Request{}).Return(pagination.Page[models.Transaction]{}, errors.New("repository error"))
 			},
 		},
 	}
@@ -148,9 +200,13 @@ func TestTransactionService_GetOverdueTransactions(t *testing.T) {
 			service := &TransactionService{
 				bookRepo:        tt.fields.bookRepo,
 				transactionRepo: tt.fields.transactionRepo,
+				holdRepo:        tt.fields.holdRepo,
+				fineRepo:        tt.fields.fineRepo,
+				policy:          testPolicy,
+				loans:           loanpolicy.New(testPolicy),
 			}
 			tt.mockSetup()
-			got, err := service.GetOverdueTransactions(tt.args.ctx)
+			got, err := service.GetOverdueTransactions(tt.args.ctx, pagination.Request{})
 			if (err != nil) != tt.wantErr {
 				t.Errorf("TransactionService.GetOverdueTransactions() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -168,10 +224,18 @@ func TestNewTransactionService(t *testing.T) {
 
 	mockBookRepo := mocks.NewMockBookStorage(ctrl)
 	mockTransactionRepo := mocks.NewMockTransactionStorage(ctrl)
+	mockHoldRepo := mocks.NewMockHoldStorage(ctrl)
+	mockFineRepo := mocks.NewMockFineStorage(ctrl)
+	mockUserRepo := mocks.NewMockUserStorage(ctrl)
+	mockBlockRepo := mocks.NewMockBlockStorage(ctrl)
 
 	type args struct {
 		bookRepo        bookrepo.BookStorage
 		transactionRepo transactionrepo.TransactionStorage
+		holdRepo        holdrepo.HoldStorage
+		fineRepo        finerepo.FineStorage
+		userRepo        userrepo.UserStorage
+		blockRepo       blockrepo.BlockStorage
 	}
 	tests := []struct {
 		name string
@@ -183,16 +247,26 @@ func TestNewTransactionService(t *testing.T) {
 			args: args{
 				bookRepo:        mockBookRepo,
 				transactionRepo: mockTransactionRepo,
+				holdRepo:        mockHoldRepo,
+				fineRepo:        mockFineRepo,
+				userRepo:        mockUserRepo,
+				blockRepo:       mockBlockRepo,
 			},
 			want: &TransactionService{
 				bookRepo:        mockBookRepo,
 				transactionRepo: mockTransactionRepo,
+				holdRepo:        mockHoldRepo,
+				fineRepo:        mockFineRepo,
+				userRepo:        mockUserRepo,
+				policy:          testPolicy,
+				loans:           loanpolicy.New(testPolicy),
+				blocks:          patronblocks.NewChecker(mockBlockRepo, testPolicy),
 			},
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if got := NewTransactionService(tt.args.bookRepo, tt.args.transactionRepo); !reflect.DeepEqual(got, tt.want) {
+			if got := NewTransactionService(tt.args.bookRepo, tt.args.transactionRepo, tt.args.holdRepo, tt.args.fineRepo, tt.args.userRepo, tt.args.blockRepo, testPolicy); !reflect.DeepEqual(got, tt.want) {
 				t.Errorf("NewTransactionService() = %v, want %v", got, tt.want)
 			}
 		})
@@ -205,10 +279,16 @@ func TestTransactionService_IssueBook(t *testing.T) {
 
 	mockBookRepo := mocks.NewMockBookStorage(ctrl)
 	mockTransactionRepo := mocks.NewMockTransactionStorage(ctrl)
+	mockBlockRepo := mocks.NewMockBlockStorage(ctrl)
+	mockHoldRepo := mocks.NewMockHoldStorage(ctrl)
+	mockFineRepo := mocks.NewMockFineStorage(ctrl)
+	mockUserRepo := mocks.NewMockUserStorage(ctrl)
 
 	type fields struct {
 		bookRepo        bookrepo.BookStorage
 		transactionRepo transactionrepo.TransactionStorage
+		holdRepo        holdrepo.HoldStorage
+		fineRepo        finerepo.FineStorage
 	}
 	type args struct {
 		ctx      context.Context
@@ -228,6 +308,8 @@ func TestTransactionService_IssueBook(t *testing.T) {
 			fields: fields{
 				bookRepo:        mockBookRepo,
 				transactionRepo: mockTransactionRepo,
+				holdRepo:        mockHoldRepo,
+				fineRepo:        mockFineRepo,
 			},
 			args: args{
 				ctx: context.WithValue(context.Background(), "user", models.UserJwt{
@@ -240,7 +322,12 @@ func TestTransactionService_IssueBook(t *testing.T) {
 			want:    "550e8400-e29b-41d4-a716-446655440004",
 			wantErr: false,
 			mockSetup: func() {
-				mockTransactionRepo.EXPECT().IssueBook(gomock.Any(), gomock.Any(), "7 days").Return("550e8400-e29b-41d4-a716-446655440004", nil)
+				verified(mockUserRepo, gomock.Any())
+				notBlocked(mockBlockRepo, gomock.Any())
+				mockBookRepo.EXPECT().GetBookById(gomock.Any()).Return(models.Book{ItemType: "book"}, nil)
+				mockTransactionRepo.EXPECT().CountOpenLoans(gomock.Any(), "").Return(0, nil)
+				mockTransactionRepo.EXPECT().IssueBook(gomock.Any(), gomock.Any(), loanTerms("7 days")).Return("550e8400-e29b-41d4-a716-446655440004", nil)
+				mockHoldRepo.EXPECT().FulfillHold(gomock.Any(), gomock.Any()).Return(nil)
 			},
 		},
 		{
@@ -248,6 +335,8 @@ func TestTransactionService_IssueBook(t *testing.T) {
 			fields: fields{
 				bookRepo:        mockBookRepo,
 				transactionRepo: mockTransactionRepo,
+				holdRepo:        mockHoldRepo,
+				fineRepo:        mockFineRepo,
 			},
 			args: args{
 				ctx: context.WithValue(context.Background(), "user", models.UserJwt{
@@ -260,7 +349,58 @@ func TestTransactionService_IssueBook(t *testing.T) {
 			want:    "550e8400-e29b-41d4-a716-446655440005",
 			wantErr: false,
 			mockSetup: func() {
-				mockTransactionRepo.EXPECT().IssueBook(gomock.Any(), gomock.Any(), "1 day").Return("550e8400-e29b-41d4-a716-446655440005", nil)
+				verified(mockUserRepo, gomock.Any())
+				notBlocked(mockBlockRepo, gomock.Any())
+				mockBookRepo.EXPECT().GetBookById(gomock.Any()).Return(models.Book{ItemType: "book"}, nil)
+				mockTransactionRepo.EXPECT().CountOpenLoans(gomock.Any(), "").Return(4, nil)
+				mockTransactionRepo.EXPECT().IssueBook(gomock.Any(), gomock.Any(), loanTerms("1 day")).Return("550e8400-e29b-41d4-a716-446655440005", nil)
+				mockHoldRepo.EXPECT().FulfillHold(gomock.Any(), gomock.Any()).Return(nil)
+			},
+		},
+		{
+			name: "patron blocked by staff",
+			fields: fields{
+				bookRepo:        mockBookRepo,
+				transactionRepo: mockTransactionRepo,
+				holdRepo:        mockHoldRepo,
+				fineRepo:        mockFineRepo,
+			},
+			args: args{
+				ctx: context.WithValue(context.Background(), "user", models.UserJwt{
+					Email: "customer@example.com",
+					Role:  roles.Customer,
+				}),
+				bookId:   uuid.New().String(),
+				issueFor: "7 days",
+			},
+			want:    "",
+			wantErr: true,
+			mockSetup: func() {
+				verified(mockUserRepo, gomock.Any())
+				mockBlockRepo.EXPECT().GetActiveBlocks(gomock.Any()).Return([]models.Block{{ID: uuid.New(), Kind: blockkind.Manual, Reason: "lost a book"}}, nil)
+				mockBlockRepo.EXPECT().GetStanding(gomock.Any()).Return(models.Standing{}, nil)
+			},
+		},
+		{
+			name: "email not verified",
+			fields: fields{
+				bookRepo:        mockBookRepo,
+				transactionRepo: mockTransactionRepo,
+				holdRepo:        mockHoldRepo,
+				fineRepo:        mockFineRepo,
+			},
+			args: args{
+				ctx: context.WithValue(context.Background(), "user", models.UserJwt{
+					Email: "customer@example.com",
+					Role:  roles.Customer,
+				}),
+				bookId:   uuid.New().String(),
+				issueFor: "7 days",
+			},
+			want:    "",
+			wantErr: true,
+			mockSetup: func() {
+				mockUserRepo.EXPECT().GetUserById(gomock.Any()).Return(models.User{}, nil)
 			},
 		},
 		{
@@ -268,6 +408,8 @@ func TestTransactionService_IssueBook(t *testing.T) {
 			fields: fields{
 				bookRepo:        mockBookRepo,
 				transactionRepo: mockTransactionRepo,
+				holdRepo:        mockHoldRepo,
+				fineRepo:        mockFineRepo,
 			},
 			args: args{
 				ctx:      context.Background(),
@@ -284,6 +426,8 @@ func TestTransactionService_IssueBook(t *testing.T) {
 			fields: fields{
 				bookRepo:        mockBookRepo,
 				transactionRepo: mockTransactionRepo,
+				holdRepo:        mockHoldRepo,
+				fineRepo:        mockFineRepo,
 			},
 			args: args{
 				ctx: context.WithValue(context.Background(), "user", models.UserJwt{
@@ -303,6 +447,8 @@ func TestTransactionService_IssueBook(t *testing.T) {
 			fields: fields{
 				bookRepo:        mockBookRepo,
 				transactionRepo: mockTransactionRepo,
+				holdRepo:        mockHoldRepo,
+				fineRepo:        mockFineRepo,
 			},
 			args: args{
 				ctx: context.WithValue(context.Background(), "user", models.UserJwt{
@@ -317,11 +463,60 @@ func TestTransactionService_IssueBook(t *testing.T) {
 			mockSetup: func() {
 			},
 		},
+		{
+			name: "malformed book id",
+			fields: fields{
+				bookRepo:        mockBookRepo,
+				transactionRepo: mockTransactionRepo,
+				holdRepo:        mockHoldRepo,
+				fineRepo:        mockFineRepo,
+			},
+			args: args{
+				ctx: context.WithValue(context.Background(), "user", models.UserJwt{
+					Email: "customer@example.com",
+					Role:  roles.Customer,
+				}),
+				bookId:   "not-a-uuid",
+				issueFor: "7 days",
+			},
+			want:    "",
+			wantErr: true,
+			mockSetup: func() {
+			},
+		},
 		{
 			name: "repository error",
 			fields: fields{
 				bookRepo:        mockBookRepo,
 				transactionRepo: mockTransactionRepo,
+				holdRepo:        mockHoldRepo,
+				fineRepo:        mockFineRepo,
+			},
+			args: args{
+				ctx: context.WithValue(context.Background(), "user", models.UserJwt{
+					Email: "customer@example.com",
+					Role:  roles.Customer,
+				}),
+				bookId:   uuid.New().String(),
+				issueFor: "7 days",
+			},
+			want:    "",
+			wantErr: true,
+			mockSetup: func() {
+				verified(mockUserRepo, gomock.Any())
+				notBlocked(mockBlockRepo, gomock.Any())
+				mockBookRepo.EXPECT().GetBookById(gomock.Any()).Return(models.Book{ItemType: "book"}, nil)
+				mockTransactionRepo.EXPECT().CountOpenLoans(gomock.Any(), "").Return(0, nil)
+				mockTransactionRepo.EXPECT().IssueBook(gomock.Any(), gomock.Any(), loanTerms("7 days")).Return("", errors.New("repository error"))
+			},
+		},
+		{
+			name: "loan limit reached",
+			fields: fields{
+				bookRepo:        mockBookRepo,
+				transactionRepo: mockTransactionRepo,
+				holdRepo:        mockHoldRepo,
+				fineRepo:        mockFineRepo,
 			},
 			args: args{
 				ctx: context.WithValue(context.Background(), "user", models.UserJwt{
@@ -334,7 +529,86 @@ func TestTransactionService_IssueBook(t *testing.T) {
 			want:    "",
 			wantErr: true,
 			mockSetup: func() {
-				mockTransactionRepo.EXPECT().IssueBook(gomock.Any(), gomock.Any(), "7 days").Return("", errors.New("repository error"))
+				verified(mockUserRepo, gomock.Any())
+				notBlocked(mockBlockRepo, gomock.Any())
+				mockBookRepo.EXPECT().GetBookById(gomock.Any()).Return(models.Book{ItemType: "book"}, nil)
+				mockTransactionRepo.EXPECT().CountOpenLoans(gomock.Any(), "").Return(testPolicy.MaxLoans, nil)
+			},
+		},
+		{
+			name: "loan period above the maximum",
+			fields: fields{
+				bookRepo:        mockBookRepo,
+				transactionRepo: mockTransactionRepo,
+				holdRepo:        mockHoldRepo,
+				fineRepo:        mockFineRepo,
+			},
+			args: args{
+				ctx: context.WithValue(context.Background(), "user", models.UserJwt{
+					Email: "customer@example.com",
+					Role:  roles.Customer,
+				}),
+				bookId:   uuid.New().String(),
+				issueFor: "60 days",
+			},
+			want:    "",
+			wantErr: true,
+			mockSetup: func() {
+				verified(mockUserRepo, gomock.Any())
+				notBlocked(mockBlockRepo, gomock.Any())
+				mockBookRepo.EXPECT().GetBookById(gomock.Any()).Return(models.Book{ItemType: "book"}, nil)
+			},
+		},
+		{
+			name: "item type that cant be borrowed",
+			fields: fields{
+				bookRepo:        mockBookRepo,
+				transactionRepo: mockTransactionRepo,
+				holdRepo:        mockHoldRepo,
+				fineRepo:        mockFineRepo,
+			},
+			args: args{
+				ctx: context.WithValue(context.Background(), "user", models.UserJwt{
+					Email: "customer@example.com",
+					Role:  roles.Customer,
+				}),
+				bookId:   uuid.New().String(),
+				issueFor: "",
+			},
+			want:    "",
+			wantErr: true,
+			mockSetup: func() {
+				verified(mockUserRepo, gomock.Any())
+				notBlocked(mockBlockRepo, gomock.Any())
+				mockBookRepo.EXPECT().GetBookById(gomock.Any()).Return(models.Book{ItemType: "reference"}, nil)
+				mockTransactionRepo.EXPECT().CountOpenLoans(gomock.Any(), "").Return(0, nil)
+				mockTransactionRepo.EXPECT().CountOpenLoans(gomock.Any(), "reference").Return(0, nil)
+			},
+		},
+		{
+			name: "item type rule cant pass the overall limit",
+			fields: fields{
+				bookRepo:        mockBookRepo,
+				transactionRepo: mockTransactionRepo,
+				holdRepo:        mockHoldRepo,
+				fineRepo:        mockFineRepo,
+			},
+			args: args{
+				ctx: context.WithValue(context.Background(), "user", models.UserJwt{
+					Email: "customer@example.com",
+					Role:  roles.Customer,
+				}),
+				bookId:   uuid.New().String(),
+				issueFor: "",
+			},
+			want:    "",
+			wantErr: true,
+			mockSetup: func() {
+				verified(mockUserRepo, gomock.Any())
+				notBlocked(mockBlockRepo, gomock.Any())
+				mockBookRepo.EXPECT().GetBookById(gomock.Any()).Return(models.Book{ItemType: "dvd"}, nil)
+				mockTransactionRepo.EXPECT().CountOpenLoans(gomock.Any(), "").Return(testPolicy.MaxLoans, nil)
+				mockTransactionRepo.EXPECT().CountOpenLoans(gomock.Any(), "dvd").Return(0, nil)
 			},
 		},
 	}
@@ -343,6 +617,12 @@ func TestTransactionService_IssueBook(t *testing.T) {
 			service := &TransactionService{
 				bookRepo:        tt.fields.bookRepo,
 				transactionRepo: tt.fields.transactionRepo,
+				holdRepo:        tt.fields.holdRepo,
+				fineRepo:        tt.fields.fineRepo,
+				userRepo:        mockUserRepo,
+				policy:          testPolicy,
+				loans:           loanpolicy.New(testPolicy),
+				blocks:          patronblocks.NewChecker(mockBlockRepo, testPolicy),
 			}
 			tt.mockSetup()
 			got, err := service.IssueBook(tt.args.ctx, tt.args.bookId, tt.args.issueFor)
@@ -363,10 +643,14 @@ func TestTransactionService_ReturnBook(t *testing.T) {
 
 	mockBookRepo := mocks.NewMockBookStorage(ctrl)
 	mockTransactionRepo := mocks.NewMockTransactionStorage(ctrl)
+	mockHoldRepo := mocks.NewMockHoldStorage(ctrl)
+	mockFineRepo := mocks.NewMockFineStorage(ctrl)
 
 	type fields struct {
 		bookRepo        bookrepo.BookStorage
 		transactionRepo transactionrepo.TransactionStorage
+		holdRepo        holdrepo.HoldStorage
+		fineRepo        finerepo.FineStorage
 	}
 	type args struct {
 		ctx    context.Context
@@ -384,6 +668,8 @@ func TestTransactionService_ReturnBook(t *testing.T) {
 			fields: fields{
 				bookRepo:        mockBookRepo,
 				transactionRepo: mockTransactionRepo,
+				holdRepo:        mockHoldRepo,
+				fineRepo:        mockFineRepo,
 			},
 			args: args{
 				ctx: context.WithValue(context.Background(), "user", models.UserJwt{
@@ -394,7 +680,12 @@ func TestTransactionService_ReturnBook(t *testing.T) {
 			},
 			wantErr: false,
 			mockSetup: func() {
-				mockTransactionRepo.EXPECT().ReturnBook(gomock.Any(), gomock.Any()).Return(nil)
+				mockTransactionRepo.EXPECT().ReturnBook(gomock.Any(), gomock.Any()).Return(models.Transaction{
+					ID:   uuid.MustParse("550e8400-e29b-41d4-a716-446655440008"),
+					Copy: models.Copy{ID: uuid.MustParse("6ba7b810-9dad-11d1-80b4-00c04fd43008")},
+				}, nil)
+				mockFineRepo.EXPECT().AssessFine("550e8400-e29b-41d4-a716-446655440008", 50, 2000).Return(nil)
+				mockHoldRepo.EXPECT().AllocateNext("6ba7b810-9dad-11d1-80b4-00c04fd43008", "2 days").Return("550e8400-e29b-41d4-a716-446655440009", nil)
 			},
 		},
 		{
@@ -402,6 +693,8 @@ func TestTransactionService_ReturnBook(t *testing.T) {
 			fields: fields{
 				bookRepo:        mockBookRepo,
 				transactionRepo: mockTransactionRepo,
+				holdRepo:        mockHoldRepo,
+				fineRepo:        mockFineRepo,
 			},
 			args: args{
 				ctx:    context.Background(),
@@ -416,6 +709,8 @@ func TestTransactionService_ReturnBook(t *testing.T) {
 			fields: fields{
 				bookRepo:        mockBookRepo,
 				transactionRepo: mockTransactionRepo,
+				holdRepo:        mockHoldRepo,
+				fineRepo:        mockFineRepo,
 			},
 			args: args{
 				ctx: context.WithValue(context.Background(), "user", models.UserJwt{
@@ -433,6 +728,8 @@ func TestTransactionService_ReturnBook(t *testing.T) {
 			fields: fields{
 				bookRepo:        mockBookRepo,
 				transactionRepo: mockTransactionRepo,
+				holdRepo:        mockHoldRepo,
+				fineRepo:        mockFineRepo,
 			},
 			args: args{
 				ctx: context.WithValue(context.Background(), "user", models.UserJwt{
@@ -445,11 +742,32 @@ func TestTransactionService_ReturnBook(t *testing.T) {
 			mockSetup: func() {
 			},
 		},
+		{
+			name: "malformed book id",
+			fields: fields{
+				bookRepo:        mockBookRepo,
+				transactionRepo: mockTransactionRepo,
+				holdRepo:        mockHoldRepo,
+				fineRepo:        mockFineRepo,
+			},
+			args: args{
+				ctx: context.WithValue(context.Background(), "user", models.UserJwt{
+					Email: "customer@example.com",
+					Role:  roles.Customer,
+				}),
+				bookId: "not-a-uuid",
+			},
+			wantErr: true,
+			mockSetup: func() {
+			},
+		},
 		{
 			name: "repository error",
 			fields: fields{
 				bookRepo:        mockBookRepo,
 				transactionRepo: mockTransactionRepo,
+				holdRepo:        mockHoldRepo,
+				fineRepo:        mockFineRepo,
 			},
 			args: args{
 				ctx: context.WithValue(context.Background(), "user", models.UserJwt{
@@ -460,7 +778,7 @@ func TestTransactionService_ReturnBook(t *testing.T) {
 			},
 			wantErr: true,
 			mockSetup: func() {
-				mockTransactionRepo.EXPECT().ReturnBook(gomock.Any(), gomock.Any()).Return(errors.New("repository error"))
+				mockTransactionRepo.EXPECT().ReturnBook(gomock.Any(), gomock.Any()).Return(models.Transaction{}, errors.New("repository error"))
 			},
 		},
 	}
@@ -469,6 +787,10 @@ func TestTransactionService_ReturnBook(t *testing.T) {
 			service := &TransactionService{
 				bookRepo:        tt.fields.bookRepo,
 				transactionRepo: tt.fields.transactionRepo,
+				holdRepo:        tt.fields.holdRepo,
+				fineRepo:        tt.fields.fineRepo,
+				policy:          testPolicy,
+				loans:           loanpolicy.New(testPolicy),
 			}
 			tt.mockSetup()
 			if err := service.ReturnBook(tt.args.ctx, tt.args.bookId); (err != nil) != tt.wantErr {
@@ -484,10 +806,14 @@ func TestTransactionService_GetTransactions(t *testing.T) {
 
 	mockBookRepo := mocks.NewMockBookStorage(ctrl)
 	mockTransactionRepo := mocks.NewMockTransactionStorage(ctrl)
+	mockHoldRepo := mocks.NewMockHoldStorage(ctrl)
+	mockFineRepo := mocks.NewMockFineStorage(ctrl)
 
 	type fields struct {
 		bookRepo        bookrepo.BookStorage
 		transactionRepo transactionrepo.TransactionStorage
+		holdRepo        holdrepo.HoldStorage
+		fineRepo        finerepo.FineStorage
 	}
 	type args struct {
 		ctx context.Context
@@ -497,7 +823,7 @@ func TestTransactionService_GetTransactions(t *testing.T) {
 		name      string
 		fields    fields
 		args      args
-		want      []models.TransactionDTO
+		want      pagination.Page[models.TransactionDTO]
 		wantErr   bool
 		mockSetup func()
 	}{
@@ -506,6 +832,8 @@ func TestTransactionService_GetTransactions(t *testing.T) {
 			fields: fields{
 				bookRepo:        mockBookRepo,
 				transactionRepo: mockTransactionRepo,
+				holdRepo:        mockHoldRepo,
+				fineRepo:        mockFineRepo,
 			},
 			args: args{
 				ctx: context.WithValue(context.Background(), "user", models.UserJwt{
@@ -517,29 +845,31 @@ func TestTransactionService_GetTransactions(t *testing.T) {
 					EndTime:   time.Now().Format(time.RFC3339),
 				},
 			},
-			want: []models.TransactionDTO{
+			want: pagination.Page[models.TransactionDTO]{Items: []models.TransactionDTO{
 				{
 					ID:         "550e8400-e29b-41d4-a716-446655440006",
 					BookID:     "550e8400-e29b-41d4-a716-446655440007",
+					CopyID:     "6ba7b810-9dad-11d1-80b4-00c04fd43007",
 					BookName:   "Test Book",
 					UserEmail:  "customer@example.com",
 					IssuedAt:   "2025-09-04 03:00:43 +0530 IST",
 					IssuedTill: "2025-09-11 03:00:43 +0530 IST",
 					ReturnedAt: "not returned yet",
 				},
-			},
+			}},
 			wantErr: false,
 			mockSetup: func() {
-				mockTransactionRepo.EXPECT().GetAllTransactions(gomock.Any()).Return([]models.Transaction{
+				mockTransactionRepo.EXPECT().GetAllTransactions(gomock.Any(), pagination.Request{}).Return(pagination.Page[models.Transaction]{Items: []models.Transaction{
 					{
 						ID:         uuid.MustParse("550e8400-e29b-41d4-a716-446655440006"),
 						Book:       models.Book{ID: uuid.MustParse("550e8400-e29b-41d4-a716-446655440007"), Title: "Test Book"},
+						Copy:       models.Copy{ID: uuid.MustParse("6ba7b810-9dad-11d1-80b4-00c04fd43007")},
 						User:       models.User{Email: "customer@example.com"},
 						IssuedAt:   time.Date(2025, 9, 4, 3, 0, 43, 0, time.FixedZone("IST", 19800)),
 						IssuedTill: time.Date(2025, 9, 11, 3, 0, 43, 0, time.FixedZone("IST", 19800)),
 						ReturnedAt: nil,
 					},
-				}, nil)
+				}}, nil)
 			},
 		},
 		{
@@ -547,6 +877,8 @@ func TestTransactionService_GetTransactions(t *testing.T) {
 			fields: fields{
 				bookRepo:        mockBookRepo,
 				transactionRepo: mockTransactionRepo,
+				holdRepo:        mockHoldRepo,
+				fineRepo:        mockFineRepo,
 			},
 			args: args{
 				ctx: context.WithValue(context.Background(), "user", models.UserJwt{
@@ -555,30 +887,32 @@ func TestTransactionService_GetTransactions(t *testing.T) {
 				}),
 				dto: models.GetTransactionRequestDTO{},
 			},
-			want: []models.TransactionDTO{
+			want: pagination.Page[models.TransactionDTO]{Items: []models.TransactionDTO{
 				{
 					ID:         "550e8400-e29b-41d4-a716-446655440008",
 					BookID:     "550e8400-e29b-41d4-a716-446655440009",
+					CopyID:     "6ba7b810-9dad-11d1-80b4-00c04fd43009",
 					BookName:   "Test Book",
 					UserEmail:  "customer@example.com",
 					IssuedAt:   "2025-09-04 03:00:43 +0530 IST",
 					IssuedTill: "2025-09-11 03:00:43 +0530 IST",
 					ReturnedAt: "2025-09-10 03:00:43 +0530 IST",
 				},
-			},
+			}},
 			wantErr: false,
 			mockSetup: func() {
 				returnedAt := time.Date(2025, 9, 10, 3, 0, 43, 0, time.FixedZone("IST", 19800))
-				mockTransactionRepo.EXPECT().GetAllTransactions(gomock.Any()).Return([]models.Transaction{
+				mockTransactionRepo.EXPECT().GetAllTransactions(gomock.Any(), pagination.Request{}).Return(pagination.Page[models.Transaction]{Items: []models.Transaction{
 					{
 						ID:         uuid.MustParse("550e8400-e29b-41d4-a716-446655440008"),
 						Book:       models.Book{ID: uuid.MustParse("550e8400-e29b-41d4-a716-446655440009"), Title: "Test Book"},
+						Copy:       models.Copy{ID: uuid.MustParse("6ba7b810-9dad-11d1-80b4-00c04fd43009")},
 						User:       models.User{Email: "customer@example.com"},
 						IssuedAt:   time.Date(2025, 9, 4, 3, 0, 43, 0, time.FixedZone("IST", 19800)),
 						IssuedTill: time.Date(2025, 9, 11, 3, 0, 43, 0, time.FixedZone("IST", 19800)),
 						ReturnedAt: &returnedAt,
 					},
-				}, nil)
+				}}, nil)
 			},
 		},
 		{
@@ -586,12 +920,14 @@ func TestTransactionService_GetTransactions(t *testing.T) {
 			fields: fields{
 				bookRepo:        mockBookRepo,
 				transactionRepo: mockTransactionRepo,
+				holdRepo:        mockHoldRepo,
+				fineRepo:        mockFineRepo,
 			},
 			args: args{
 				ctx: context.Background(),
 				dto: models.GetTransactionRequestDTO{},
 			},
-			want:    nil,
+			want:    pagination.Page[models.TransactionDTO]{},
 			wantErr: true,
 			mockSetup: func() {
 			},
@@ -601,6 +937,8 @@ func TestTransactionService_GetTransactions(t *testing.T) {
 			fields: fields{
 				bookRepo:        mockBookRepo,
 				transactionRepo: mockTransactionRepo,
+				holdRepo:        mockHoldRepo,
+				fineRepo:        mockFineRepo,
 			},
 			args: args{
 				ctx: context.WithValue(context.Background(), "user", models.UserJwt{
@@ -609,10 +947,10 @@ func TestTransactionService_GetTransactions(t *testing.T) {
 				}),
 				dto: models.GetTransactionRequestDTO{},
 			},
-			want:    nil,
+			want:    pagination.Page[models.TransactionDTO]{},
 			wantErr: true,
 			mockSetup: func() {
-				mockTransactionRepo.EXPECT().GetAllTransactions(gomock.Any()).Return(nil, errors.New("repository error"))
+				mockTransactionRepo.EXPECT().GetAllTransactions(gomock.Any(), pagination.Request{}).Return(pagination.Page[models.Transaction]{}, errors.New("repository error"))
 			},
 		},
 	}
@@ -621,9 +959,13 @@ func TestTransactionService_GetTransactions(t *testing.T) {
 			service := &TransactionService{
 				bookRepo:        tt.fields.bookRepo,
 				transactionRepo: tt.fields.transactionRepo,
+				holdRepo:        tt.fields.holdRepo,
+				fineRepo:        tt.fields.fineRepo,
+				policy:          testPolicy,
+				loans:           loanpolicy.New(testPolicy),
 			}
 			tt.mockSetup()
-			got, err := service.GetTransactions(tt.args.ctx, tt.args.dto)
+			got, err := service.GetTransactions(tt.args.ctx, tt.args.dto, pagination.Request{})
 			if (err != nil) != tt.wantErr {
 				t.Errorf("TransactionService.GetTransactions() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -634,3 +976,513 @@ func TestTransactionService_GetTransactions(t *testing.T) {
 		})
 	}
 }
+
+func TestTransactionService_RenewBook(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockTransactionRepo := mocks.NewMockTransactionStorage(ctrl)
+	mockBlockRepo := mocks.NewMockBlockStorage(ctrl)
+	mockHoldRepo := mocks.NewMockHoldStorage(ctrl)
+	mockFineRepo := mocks.NewMockFineStorage(ctrl)
+
+	userId := uuid.New()
+	transactionId := uuid.New().String()
+	customerCtx := context.WithValue(context.Background(), "user", models.UserJwt{
+		RegisteredClaims: jwt.RegisteredClaims{Subject: userId.String()},
+		Role:             roles.Customer,
+	})
+	now := time.Now()
+	renewedTill := now.AddDate(0, 0, 8)
+
+	openLoan := func(renewals int) models.Transaction {
+		return models.Transaction{
+			ID:         uuid.MustParse(transactionId),
+			Book:       models.Book{ID: uuid.New()},
+			User:       models.User{ID: userId},
+			IssuedAt:   now.AddDate(0, 0, -6),
+			IssuedTill: now.AddDate(0, 0, 1),
+			Renewals:   renewals,
+		}
+	}
+
+	tests := []struct {
+		name          string
+		ctx           context.Context
+		transactionId string
+		want          models.RenewalDTO
+		wantErr       bool
+		mockSetup     func()
+	}{
+		{
+			name:          "valid renew",
+			ctx:           customerCtx,
+			transactionId: transactionId,
+			want: models.RenewalDTO{
+				TransactionID: transactionId,
+				IssuedTill:    renewedTill.String(),
+				RenewalsUsed:  1,
+				RenewalsLeft:  1,
+			},
+			wantErr: false,
+			mockSetup: func() {
+				mockTransactionRepo.EXPECT().GetTransactionById(transactionId).Return(openLoan(0), nil)
+				notBlocked(mockBlockRepo, userId.String())
+				mockHoldRepo.EXPECT().HasWaitingHolds(gomock.Any()).Return(false, nil)
+				mockTransactionRepo.EXPECT().RenewBook(transactionId, "7 days", 2).Return(renewedTill, nil)
+			},
+		},
+		{
+			name:          "renewal terms of the item type",
+			ctx:           customerCtx,
+			transactionId: transactionId,
+			want: models.RenewalDTO{
+				TransactionID: transactionId,
+				IssuedTill:    renewedTill.String(),
+				RenewalsUsed:  1,
+				RenewalsLeft:  0,
+			},
+			wantErr: false,
+			mockSetup: func() {
+				loan := openLoan(0)
+				loan.Book.ItemType = "dvd"
+				mockTransactionRepo.EXPECT().GetTransactionById(transactionId).Return(loan, nil)
+				notBlocked(mockBlockRepo, userId.String())
+				mockHoldRepo.EXPECT().HasWaitingHolds(gomock.Any()).Return(false, nil)
+				mockTransactionRepo.EXPECT().RenewBook(transactionId, "3 days", 1).Return(renewedTill, nil)
+			},
+		},
+		{
+			name:          "renewal limit of the item type reached",
+			ctx:           customerCtx,
+			transactionId: transactionId,
+			wantErr:       true,
+			mockSetup: func() {
+				loan := openLoan(1)
+				loan.Book.ItemType = "dvd"
+				mockTransactionRepo.EXPECT().GetTransactionById(transactionId).Return(loan, nil)
+				notBlocked(mockBlockRepo, userId.String())
+			},
+		},
+		{
+			name:          "invalid user context",
+			ctx:           context.Background(),
+			transactionId: transactionId,
+			wantErr:       true,
+			mockSetup: func() {
+			},
+		},
+		{
+			name: "staff cannot renew",
+			ctx: context.WithValue(context.Background(), "user", models.UserJwt{
+				Role: roles.Staff,
+			}),
+			transactionId: transactionId,
+			wantErr:       true,
+			mockSetup: func() {
+			},
+		},
+		{
+			name:          "invalid transaction id",
+			ctx:           customerCtx,
+			transactionId: "",
+			wantErr:       true,
+			mockSetup: func() {
+			},
+		},
+		{
+			name:          "transaction not found",
+			ctx:           customerCtx,
+			transactionId: transactionId,
+			wantErr:       true,
+			mockSetup: func() {
+				mockTransactionRepo.EXPECT().GetTransactionById(transactionId).Return(models.Transaction{}, errors.New("transaction not found"))
+			},
+		},
+		{
+			name:          "someone else's loan",
+			ctx:           customerCtx,
+			transactionId: transactionId,
+			wantErr:       true,
+			mockSetup: func() {
+				loan := openLoan(0)
+				loan.User.ID = uuid.New()
+				mockTransactionRepo.EXPECT().GetTransactionById(transactionId).Return(loan, nil)
+			},
+		},
+		{
+			name:          "already returned",
+			ctx:           customerCtx,
+			transactionId: transactionId,
+			wantErr:       true,
+			mockSetup: func() {
+				loan := openLoan(0)
+				loan.ReturnedAt = &now
+				mockTransactionRepo.EXPECT().GetTransactionById(transactionId).Return(loan, nil)
+			},
+		},
+		{
+			name:          "overdue loan",
+			ctx:           customerCtx,
+			transactionId: transactionId,
+			wantErr:       true,
+			mockSetup: func() {
+				loan := openLoan(0)
+				loan.IssuedTill = now.AddDate(0, 0, -1)
+				mockTransactionRepo.EXPECT().GetTransactionById(transactionId).Return(loan, nil)
+			},
+		},
+		{
+			name:          "patron blocked by unpaid fines",
+			ctx:           customerCtx,
+			transactionId: transactionId,
+			wantErr:       true,
+			mockSetup: func() {
+				mockTransactionRepo.EXPECT().GetTransactionById(transactionId).Return(openLoan(0), nil)
+				mockBlockRepo.EXPECT().GetActiveBlocks(userId.String()).Return(nil, nil)
+				mockBlockRepo.EXPECT().GetStanding(userId.String()).Return(models.Standing{UnpaidFines: 1500}, nil)
+			},
+		},
+		{
+			name:          "renewal limit reached",
+			ctx:           customerCtx,
+			transactionId: transactionId,
+			wantErr:       true,
+			mockSetup: func() {
+				mockTransactionRepo.EXPECT().GetTransactionById(transactionId).Return(openLoan(2), nil)
+				notBlocked(mockBlockRepo, userId.String())
+			},
+		},
+		{
+			name:          "pending holds",
+			ctx:           customerCtx,
+			transactionId: transactionId,
+			wantErr:       true,
+			mockSetup: func() {
+				mockTransactionRepo.EXPECT().GetTransactionById(transactionId).Return(openLoan(1), nil)
+				notBlocked(mockBlockRepo, userId.String())
+				mockHoldRepo.EXPECT().HasWaitingHolds(gomock.Any()).Return(true, nil)
+			},
+		},
+		{
+			name:          "repository error on renew",
+			ctx:           customerCtx,
+			transactionId: transactionId,
+			wantErr:       true,
+			mockSetup: func() {
+				mockTransactionRepo.EXPECT().GetTransactionById(transactionId).Return(openLoan(1), nil)
+				notBlocked(mockBlockRepo, userId.String())
+				mockHoldRepo.EXPECT().HasWaitingHolds(gomock.Any()).Return(false, nil)
+				mockTransactionRepo.EXPECT().RenewBook(transactionId, "7 days", 2).Return(time.Time{}, errors.New("loan cannot be renewed"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := &TransactionService{
+				transactionRepo: mockTransactionRepo,
+				holdRepo:        mockHoldRepo,
+				fineRepo:        mockFineRepo,
+				policy:          testPolicy,
+				loans:           loanpolicy.New(testPolicy),
+				blocks:          patronblocks.NewChecker(mockBlockRepo, testPolicy),
+			}
+			tt.mockSetup()
+			got, err := service.RenewBook(tt.ctx, tt.transactionId)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("TransactionService.RenewBook() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("TransactionService.RenewBook() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTransactionService_DeskIssue(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockBookRepo := mocks.NewMockBookStorage(ctrl)
+	mockTransactionRepo := mocks.NewMockTransactionStorage(ctrl)
+	mockBlockRepo := mocks.NewMockBlockStorage(ctrl)
+	mockHoldRepo := mocks.NewMockHoldStorage(ctrl)
+	mockUserRepo := mocks.NewMockUserStorage(ctrl)
+
+	staffId := uuid.New().String()
+	librarianCtx := context.WithValue(context.Background(), "user", models.UserJwt{
+		RegisteredClaims: jwt.RegisteredClaims{Subject: staffId},
+		Role:             roles.Librarian,
+	})
+	verifiedAt := time.Now()
+	patron := models.User{ID: uuid.New(), Email: "patron@example.com", Role: roles.Customer, CardNumber: "LC00000042", EmailVerifiedAt: &verifiedAt}
+	copyId := uuid.New()
+	transaction := models.Transaction{
+		ID:   uuid.New(),
+		Book: models.Book{ID: uuid.New()},
+		Copy: models.Copy{ID: copyId},
+	}
+	issuable := models.Copy{ID: copyId, BookID: transaction.Book.ID}
+	book := models.Book{ID: transaction.Book.ID, ItemType: "book"}
+
+	tests := []struct {
+		name      string
+		ctx       context.Context
+		req       models.DeskIssueDTO
+		want      string
+		wantErr   bool
+		mockSetup func()
+	}{
+		{
+			name:    "issue by card number and barcode",
+			ctx:     librarianCtx,
+			req:     models.DeskIssueDTO{Patron: "LC00000042", Copy: "ABC123"},
+			want:    transaction.ID.String(),
+			wantErr: false,
+			mockSetup: func() {
+				mockUserRepo.EXPECT().GetUserByCardNumber("LC00000042").Return(patron, nil)
+				mockBookRepo.EXPECT().GetCopyByBarcode("ABC123").Return(issuable, nil)
+				notBlocked(mockBlockRepo, gomock.Any())
+				mockBookRepo.EXPECT().GetBookById(book.ID.String()).Return(book, nil)
+				mockTransactionRepo.EXPECT().CountOpenLoans(patron.ID.String(), "").Return(0, nil)
+				mockTransactionRepo.EXPECT().IssueCopy(copyId.String(), patron.ID.String(), staffId, loanTerms("1 day")).Return(transaction, nil)
+				mockHoldRepo.EXPECT().FulfillHold(transaction.Book.ID.String(), patron.ID.String()).Return(nil)
+			},
+		},
+		{
+			name:    "issue by email and copy id",
+			ctx:     librarianCtx,
+			req:     models.DeskIssueDTO{Patron: "patron@example.com", Copy: copyId.String(), IssueFor: "14 days"},
+			want:    transaction.ID.String(),
+			wantErr: false,
+			mockSetup: func() {
+				mockUserRepo.EXPECT().GetUserByEmail("patron@example.com").Return(patron, nil)
+				mockBookRepo.EXPECT().GetCopyById(copyId.String()).Return(issuable, nil)
+				notBlocked(mockBlockRepo, gomock.Any())
+				mockBookRepo.EXPECT().GetBookById(book.ID.String()).Return(book, nil)
+				mockTransactionRepo.EXPECT().CountOpenLoans(patron.ID.String(), "").Return(1, nil)
+				mockTransactionRepo.EXPECT().IssueCopy(copyId.String(), patron.ID.String(), staffId, loanTerms("14 days")).Return(transaction, nil)
+				mockHoldRepo.EXPECT().FulfillHold(transaction.Book.ID.String(), patron.ID.String()).Return(nil)
+			},
+		},
+		{
+			name:    "patron with expired membership",
+			ctx:     librarianCtx,
+			req:     models.DeskIssueDTO{Patron: "LC00000042", Copy: "ABC123"},
+			wantErr: true,
+			mockSetup: func() {
+				expired := time.Now().Add(-24 * time.Hour)
+				mockUserRepo.EXPECT().GetUserByCardNumber("LC00000042").Return(patron, nil)
+				mockBlockRepo.EXPECT().GetActiveBlocks(patron.ID.String()).Return(nil, nil)
+				mockBlockRepo.EXPECT().GetStanding(patron.ID.String()).Return(models.Standing{MembershipExpiresAt: &expired}, nil)
+			},
+		},
+		{
+			name: "customer cannot use the desk",
+			ctx: context.WithValue(context.Background(), "user", models.UserJwt{
+				Role: roles.Customer,
+			}),
+			req:       models.DeskIssueDTO{Patron: "LC00000042", Copy: "ABC123"},
+			want:      "",
+			wantErr:   true,
+			mockSetup: func() {},
+		},
+		{
+			name:      "missing copy",
+			ctx:       librarianCtx,
+			req:       models.DeskIssueDTO{Patron: "LC00000042"},
+			want:      "",
+			wantErr:   true,
+			mockSetup: func() {},
+		},
+		{
+			name:    "patron cannot borrow",
+			ctx:     librarianCtx,
+			req:     models.DeskIssueDTO{Patron: patron.ID.String(), Copy: "ABC123"},
+			want:    "",
+			wantErr: true,
+			mockSetup: func() {
+				mockUserRepo.EXPECT().GetUserById(patron.ID.String()).Return(models.User{ID: patron.ID, Role: roles.Staff}, nil)
+			},
+		},
+		{
+			name:    "deactivated patron",
+			ctx:     librarianCtx,
+			req:     models.DeskIssueDTO{Patron: "LC00000042", Copy: "ABC123"},
+			want:    "",
+			wantErr: true,
+			mockSetup: func() {
+				deactivated := patron
+				deactivatedAt := time.Now()
+				deactivated.DeactivatedAt = &deactivatedAt
+				mockUserRepo.EXPECT().GetUserByCardNumber("LC00000042").Return(deactivated, nil)
+			},
+		},
+		{
+			name:    "patron with unverified email",
+			ctx:     librarianCtx,
+			req:     models.DeskIssueDTO{Patron: "LC00000042", Copy: "ABC123"},
+			want:    "",
+			wantErr: true,
+			mockSetup: func() {
+				unverified := patron
+				unverified.EmailVerifiedAt = nil
+				mockUserRepo.EXPECT().GetUserByCardNumber("LC00000042").Return(unverified, nil)
+			},
+		},
+		{
+			name:    "copy not available",
+			ctx:     librarianCtx,
+			req:     models.DeskIssueDTO{Patron: "LC00000042", Copy: copyId.String()},
+			want:    "",
+			wantErr: true,
+			mockSetup: func() {
+				mockUserRepo.EXPECT().GetUserByCardNumber("LC00000042").Return(patron, nil)
+				mockBookRepo.EXPECT().GetCopyById(copyId.String()).Return(issuable, nil)
+				notBlocked(mockBlockRepo, gomock.Any())
+				mockBookRepo.EXPECT().GetBookById(book.ID.String()).Return(book, nil)
+				mockTransactionRepo.EXPECT().CountOpenLoans(patron.ID.String(), "").Return(0, nil)
+				mockTransactionRepo.EXPECT().IssueCopy(copyId.String(), patron.ID.String(), staffId, loanTerms("1 day")).Return(models.Transaction{}, errors.New("copy not available"))
+			},
+		},
+		{
+			name:    "patron at the loan limit",
+			ctx:     librarianCtx,
+			req:     models.DeskIssueDTO{Patron: "LC00000042", Copy: "ABC123"},
+			want:    "",
+			wantErr: true,
+			mockSetup: func() {
+				mockUserRepo.EXPECT().GetUserByCardNumber("LC00000042").Return(patron, nil)
+				mockBookRepo.EXPECT().GetCopyByBarcode("ABC123").Return(issuable, nil)
+				notBlocked(mockBlockRepo, gomock.Any())
+				mockBookRepo.EXPECT().GetBookById(book.ID.String()).Return(book, nil)
+				mockTransactionRepo.EXPECT().CountOpenLoans(patron.ID.String(), "").Return(testPolicy.MaxLoans, nil)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := &TransactionService{
+				bookRepo:        mockBookRepo,
+				transactionRepo: mockTransactionRepo,
+				holdRepo:        mockHoldRepo,
+				userRepo:        mockUserRepo,
+				policy:          testPolicy,
+				loans:           loanpolicy.New(testPolicy),
+				blocks:          patronblocks.NewChecker(mockBlockRepo, testPolicy),
+			}
+			tt.mockSetup()
+			got, err := service.DeskIssue(tt.ctx, tt.req)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("TransactionService.DeskIssue() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("TransactionService.DeskIssue() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTransactionService_DeskReturn(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockBookRepo := mocks.NewMockBookStorage(ctrl)
+	mockTransactionRepo := mocks.NewMockTransactionStorage(ctrl)
+	mockHoldRepo := mocks.NewMockHoldStorage(ctrl)
+	mockFineRepo := mocks.NewMockFineStorage(ctrl)
+	mockUserRepo := mocks.NewMockUserStorage(ctrl)
+
+	staffId := uuid.New().String()
+	staffCtx := context.WithValue(context.Background(), "user", models.UserJwt{
+		RegisteredClaims: jwt.RegisteredClaims{Subject: staffId},
+		Role:             roles.Staff,
+	})
+	patron := models.User{ID: uuid.New(), Email: "patron@example.com", Role: roles.Customer}
+	copyId := uuid.New()
+	transaction := models.Transaction{
+		ID:   uuid.New(),
+		Copy: models.Copy{ID: copyId},
+	}
+
+	tests := []struct {
+		name      string
+		ctx       context.Context
+		req       models.DeskReturnDTO
+		wantErr   bool
+		mockSetup func()
+	}{
+		{
+			name:    "check in by barcode from anyone",
+			ctx:     staffCtx,
+			req:     models.DeskReturnDTO{Copy: "ABC123"},
+			wantErr: false,
+			mockSetup: func() {
+				mockBookRepo.EXPECT().GetCopyByBarcode("ABC123").Return(models.Copy{ID: copyId}, nil)
+				mockTransactionRepo.EXPECT().ReturnCopy(copyId.String(), "", staffId).Return(transaction, nil)
+				mockFineRepo.EXPECT().AssessFine(transaction.ID.String(), 50, 2000).Return(nil)
+				mockHoldRepo.EXPECT().AllocateNext(copyId.String(), "2 days").Return("", nil)
+			},
+		},
+		{
+			name:    "check in for a specific patron",
+			ctx:     staffCtx,
+			req:     models.DeskReturnDTO{Patron: "patron@example.com", Copy: copyId.String()},
+			wantErr: false,
+			mockSetup: func() {
+				mockUserRepo.EXPECT().GetUserByEmail("patron@example.com").Return(patron, nil)
+				mockBookRepo.EXPECT().GetCopyById(copyId.String()).Return(models.Copy{ID: copyId}, nil)
+				mockTransactionRepo.EXPECT().ReturnCopy(copyId.String(), patron.ID.String(), staffId).Return(transaction, nil)
+				mockFineRepo.EXPECT().AssessFine(transaction.ID.String(), 50, 2000).Return(nil)
+				mockHoldRepo.EXPECT().AllocateNext(copyId.String(), "2 days").Return("", nil)
+			},
+		},
+		{
+			name: "customer cannot use the desk",
+			ctx: context.WithValue(context.Background(), "user", models.UserJwt{
+				Role: roles.Customer,
+			}),
+			req:       models.DeskReturnDTO{Copy: "ABC123"},
+			wantErr:   true,
+			mockSetup: func() {},
+		},
+		{
+			name:    "unknown barcode",
+			ctx:     staffCtx,
+			req:     models.DeskReturnDTO{Copy: "ZZZ999"},
+			wantErr: true,
+			mockSetup: func() {
+				mockBookRepo.EXPECT().GetCopyByBarcode("ZZZ999").Return(models.Copy{}, errors.New("copy not found"))
+			},
+		},
+		{
+			name:    "copy not on loan",
+			ctx:     staffCtx,
+			req:     models.DeskReturnDTO{Copy: copyId.String()},
+			wantErr: true,
+			mockSetup: func() {
+				mockBookRepo.EXPECT().GetCopyById(copyId.String()).Return(models.Copy{ID: copyId}, nil)
+				mockTransactionRepo.EXPECT().ReturnCopy(copyId.String(), "", staffId).Return(models.Transaction{}, errors.New("copy is not on loan"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := &TransactionService{
+				bookRepo:        mockBookRepo,
+				transactionRepo: mockTransactionRepo,
+				holdRepo:        mockHoldRepo,
+				fineRepo:        mockFineRepo,
+				userRepo:        mockUserRepo,
+				policy:          testPolicy,
+				loans:           loanpolicy.New(testPolicy),
+			}
+			tt.mockSetup()
+			if err := service.DeskReturn(tt.ctx, tt.req); (err != nil) != tt.wantErr {
+				t.Errorf("TransactionService.DeskReturn() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,21 @@
+package userservice
+
+import (
+	"context"
+
+	"github.com/Kaushik1766/LibraryManagement/internal/models"
+	"github.com/Kaushik1766/LibraryManagement/internal/pagination"
+)
+
+//go:generate mockgen -source=interface.go -destination=../../../mocks/mock_user_manager.go -package=mocks
+type UserManager interface {
+	GetUsers(ctx context.Context, query, role, status string, page pagination.Request) (pagination.Page[models.UserDTO], error)
+	GetUser(ctx context.Context, userId string) (models.UserDTO, error)
+	UpdateUser(ctx context.Context, userId string, req models.UpdateUserDTO) (models.UserDTO, error)
+	ChangeRole(ctx context.Context, userId string, req models.ChangeRoleDTO) (models.UserDTO, error)
+	DeactivateUser(ctx context.Context, userId string) error
+	ReactivateUser(ctx context.Context, userId string) error
+	UnlockUser(ctx context.Context, userId string) error
+	ResetMFA(ctx context.Context, userId string) error
+	GetFailedLogins(ctx context.Context, email, ip string, page pagination.Request) (pagination.Page[models.FailedLoginDTO], error)
+}
@@ -0,0 +1,368 @@
+package userservice
+
+import (
+	"context"
+	"log"
+	"net/mail"
+	"strings"
+	"time"
+
+	apperrors "github.com/Kaushik1766/LibraryManagement/internal/app_errors"
+	"github.com/Kaushik1766/LibraryManagement/internal/models"
+	"github.com/Kaushik1766/LibraryManagement/internal/models/enums/roles"
+	"github.com/Kaushik1766/LibraryManagement/internal/pagination"
+	"github.com/Kaushik1766/LibraryManagement/internal/rbac"
+	failedloginrepo "github.com/Kaushik1766/LibraryManagement/internal/repository/failed_login_repo"
+	mfarepo "github.com/Kaushik1766/LibraryManagement/internal/repository/mfa_repo"
+	sessionrepo "github.com/Kaushik1766/LibraryManagement/internal/repository/session_repo"
+	userrepo "github.com/Kaushik1766/LibraryManagement/internal/repository/user_repo"
+	"github.com/google/uuid"
+)
+
+type UserService struct {
+	userRepo        userrepo.UserStorage
+	sessionRepo     sessionrepo.SessionStorage
+	failedLoginRepo failedloginrepo.FailedLoginStorage
+	mfaRepo         mfarepo.MFAStorage
+}
+
+func NewUserService(userRepo userrepo.UserStorage, sessionRepo sessionrepo.SessionStorage, failedLoginRepo failedloginrepo.FailedLoginStorage, mfaRepo mfarepo.MFAStorage) *UserService {
+	return &UserService{
+		userRepo:        userRepo,
+		sessionRepo:     sessionRepo,
+		failedLoginRepo: failedLoginRepo,
+		mfaRepo:         mfaRepo,
+	}
+}
+
+// GetUsers lists accounts matching query, role and status, which is active
+// or deactivated. Empty ones match everyone.
+func (service *UserService) GetUsers(ctx context.Context, query, role, status string, page pagination.Request) (pagination.Page[models.UserDTO], error) {
+	userCtx, ok := ctx.Value("user").(models.UserJwt)
+	if !ok {
+		return pagination.Page[models.UserDTO]{}, apperrors.ErrInvalidUser
+	}
+
+	if !rbac.Can(userCtx.Role, rbac.PatronView) {
+		return pagination.Page[models.UserDTO]{}, apperrors.ErrUnauthorisedUser
+	}
+
+	filter := models.UserFilter{Query: strings.TrimSpace(query)}
+
+	if role != "" {
+		parsed, ok := roles.Parse(role)
+		if !ok {
+			return pagination.Page[models.UserDTO]{}, invalidRole()
+		}
+		filter.Role = &parsed
+	}
+
+	switch status {
+	case "":
+	case "active", "deactivated":
+		active := status == "active"
+		filter.Active = &active
+	default:
+		return pagination.Page[models.UserDTO]{}, apperrors.Validation("invalid_status", "invalid status").
+			WithDetail("status", "must be active or deactivated")
+	}
+
+	users, err := service.userRepo.GetUsers(filter, page)
+	if err != nil {
+		return pagination.Page[models.UserDTO]{}, err
+	}
+
+	return pagination.Map(users, toUserDTO), nil
+}
+
+func (service *UserService) GetUser(ctx context.Context, userId string) (models.UserDTO, error) {
+	userCtx, ok := ctx.Value("user").(models.UserJwt)
+	if !ok {
+		return models.UserDTO{}, apperrors.ErrInvalidUser
+	}
+
+	if !rbac.Can(userCtx.Role, rbac.PatronView) {
+		return models.UserDTO{}, apperrors.ErrUnauthorisedUser
+	}
+
+	user, err := service.findUser(userId)
+	if err != nil {
+		return models.UserDTO{}, err
+	}
+
+	return toUserDTO(user), nil
+}
+
+// UpdateUser changes the name, email or membership expiry of an account.
+// Staff can only edit patrons.
+func (service *UserService) UpdateUser(ctx context.Context, userId string, req models.UpdateUserDTO) (models.UserDTO, error) {
+	userCtx, ok := ctx.Value("user").(models.UserJwt)
+	if !ok {
+		return models.UserDTO{}, apperrors.ErrInvalidUser
+	}
+
+	if !rbac.Can(userCtx.Role, rbac.UsersUpdate) {
+		return models.UserDTO{}, apperrors.ErrUnauthorisedUser
+	}
+
+	user, err := service.findUser(userId)
+	if err != nil {
+		return models.UserDTO{}, err
+	}
+
+	if !canEdit(userCtx.Role, user) {
+		return models.UserDTO{}, apperrors.ErrUnauthorisedUser
+	}
+
+	if req.Name != nil {
+		user.Name = strings.TrimSpace(*req.Name)
+		if user.Name == "" {
+			return models.UserDTO{}, apperrors.Validation("invalid_input", "invalid input").WithDetail("name", "required")
+		}
+	}
+	if req.Email != nil {
+		if _, err := mail.ParseAddress(*req.Email); err != nil {
+			return models.UserDTO{}, apperrors.Validation("invalid_email", "invalid email address").WithDetail("email", "not a valid address")
+		}
+		// the owner has to verify a new address again
+		if *req.Email != user.Email {
+			user.EmailVerifiedAt = nil
+		}
+		user.Email = *req.Email
+	}
+	if req.MembershipExpiresAt != nil {
+		user.MembershipExpiresAt = nil
+		if *req.MembershipExpiresAt != "" {
+			expiresAt, err := time.Parse(time.RFC3339, *req.MembershipExpiresAt)
+			if err != nil {
+				return models.UserDTO{}, apperrors.Validation("invalid_input", "invalid input").
+					WithDetail("membership_expires_at", "must be an RFC 3339 time")
+			}
+			user.MembershipExpiresAt = &expiresAt
+		}
+	}
+
+	if err := service.userRepo.UpdateUser(user); err != nil {
+		return models.UserDTO{}, err
+	}
+
+	return toUserDTO(user), nil
+}
+
+// ChangeRole gives an account another role and signs it out everywhere, so
+// tokens minted for the old role stop working.
+func (service *UserService) ChangeRole(ctx context.Context, userId string, req models.ChangeRoleDTO) (models.UserDTO, error) {
+	userCtx, ok := ctx.Value("user").(models.UserJwt)
+	if !ok {
+		return models.UserDTO{}, apperrors.ErrInvalidUser
+	}
+
+	if !rbac.Can(userCtx.Role, rbac.UsersManage) {
+		return models.UserDTO{}, apperrors.ErrUnauthorisedUser
+	}
+
+	role, ok := roles.Parse(req.Role)
+	if !ok {
+		return models.UserDTO{}, invalidRole()
+	}
+
+	user, err := service.findUser(userId)
+	if err != nil {
+		return models.UserDTO{}, err
+	}
+
+	if user.ID.String() == userCtx.Subject {
+		return models.UserDTO{}, apperrors.Conflict("cannot_change_self", "you cant change your own role")
+	}
+
+	if err := service.userRepo.SetRole(userId, role); err != nil {
+		return models.UserDTO{}, err
+	}
+	user.Role = role
+
+	if err := service.sessionRepo.RevokeUserSessions(userId); err != nil {
+		log.Println(err)
+	}
+
+	return toUserDTO(user), nil
+}
+
+// DeactivateUser stops an account from logging in and ends its logins. Its
+// loans, holds and fines are kept.
+func (service *UserService) DeactivateUser(ctx context.Context, userId string) error {
+	userCtx, ok := ctx.Value("user").(models.UserJwt)
+	if !ok {
+		return apperrors.ErrInvalidUser
+	}
+
+	if !rbac.Can(userCtx.Role, rbac.UsersUpdate) {
+		return apperrors.ErrUnauthorisedUser
+	}
+
+	user, err := service.findUser(userId)
+	if err != nil {
+		return err
+	}
+
+	if !canEdit(userCtx.Role, user) {
+		return apperrors.ErrUnauthorisedUser
+	}
+
+	if user.ID.String() == userCtx.Subject {
+		return apperrors.Conflict("cannot_change_self", "you cant deactivate your own account")
+	}
+
+	if err := service.userRepo.SetActive(userId, false); err != nil {
+		return err
+	}
+
+	return service.sessionRepo.RevokeUserSessions(userId)
+}
+
+func (service *UserService) ReactivateUser(ctx context.Context, userId string) error {
+	userCtx, ok := ctx.Value("user").(models.UserJwt)
+	if !ok {
+		return apperrors.ErrInvalidUser
+	}
+
+	if !rbac.Can(userCtx.Role, rbac.UsersUpdate) {
+		return apperrors.ErrUnauthorisedUser
+	}
+
+	user, err := service.findUser(userId)
+	if err != nil {
+		return err
+	}
+
+	if !canEdit(userCtx.Role, user) {
+		return apperrors.ErrUnauthorisedUser
+	}
+
+	return service.userRepo.SetActive(userId, true)
+}
+
+// UnlockUser lifts the lock failed logins put on an account and starts
+// counting its failures from zero.
+func (service *UserService) UnlockUser(ctx context.Context, userId string) error {
+	userCtx, ok := ctx.Value("user").(models.UserJwt)
+	if !ok {
+		return apperrors.ErrInvalidUser
+	}
+
+	if !rbac.Can(userCtx.Role, rbac.UsersManage) {
+		return apperrors.ErrUnauthorisedUser
+	}
+
+	if _, err := uuid.Parse(userId); err != nil {
+		return apperrors.Validation("invalid_id", "invalid user id").WithDetail("user_id", "must be a uuid")
+	}
+
+	return service.userRepo.ClearLoginFailures(userId)
+}
+
+// ResetMFA turns MFA off for an account that lost its authenticator and its
+// recovery codes, and signs it out everywhere. It can set MFA up again on its
+// next login.
+func (service *UserService) ResetMFA(ctx context.Context, userId string) error {
+	userCtx, ok := ctx.Value("user").(models.UserJwt)
+	if !ok {
+		return apperrors.ErrInvalidUser
+	}
+
+	if !rbac.Can(userCtx.Role, rbac.UsersManage) {
+		return apperrors.ErrUnauthorisedUser
+	}
+
+	user, err := service.findUser(userId)
+	if err != nil {
+		return err
+	}
+
+	if user.ID.String() == userCtx.Subject {
+		return apperrors.Conflict("cannot_change_self", "you cant reset your own mfa")
+	}
+
+	if err := service.mfaRepo.DisableMFA(userId); err != nil {
+		return err
+	}
+
+	return service.sessionRepo.RevokeUserSessions(userId)
+}
+
+// GetFailedLogins lists refused logins, optionally only those for email or
+// from ip.
+func (service *UserService) GetFailedLogins(ctx context.Context, email, ip string, page pagination.Request) (pagination.Page[models.FailedLoginDTO], error) {
+	userCtx, ok := ctx.Value("user").(models.UserJwt)
+	if !ok {
+		return pagination.Page[models.FailedLoginDTO]{}, apperrors.ErrInvalidUser
+	}
+
+	if !rbac.Can(userCtx.Role, rbac.UsersManage) {
+		return pagination.Page[models.FailedLoginDTO]{}, apperrors.ErrUnauthorisedUser
+	}
+
+	filter := models.FailedLoginFilter{Email: strings.TrimSpace(email), IP: strings.TrimSpace(ip)}
+
+	attempts, err := service.failedLoginRepo.GetFailedLogins(filter, page)
+	if err != nil {
+		return pagination.Page[models.FailedLoginDTO]{}, err
+	}
+
+	return pagination.Map(attempts, toFailedLoginDTO), nil
+}
+
+func (service *UserService) findUser(userId string) (models.User, error) {
+	if _, err := uuid.Parse(userId); err != nil {
+		return models.User{}, apperrors.Validation("invalid_id", "invalid user id").WithDetail("user_id", "must be a uuid")
+	}
+	return service.userRepo.GetUserById(userId)
+}
+
+// canEdit reports whether role may edit target. Staff look after patrons,
+// other accounts need UsersManage.
+func canEdit(role roles.UserRoles, target models.User) bool {
+	return rbac.Can(role, rbac.UsersManage) || target.Role == roles.Customer
+}
+
+func invalidRole() *apperrors.Error {
+	return apperrors.Validation("invalid_role", "invalid role").
+		WithDetail("role", "must be one of customer, librarian, staff, admin")
+}
+
+func toUserDTO(user models.User) models.UserDTO {
+	dto := models.UserDTO{
+		ID:            user.ID.String(),
+		Name:          user.Name,
+		Email:         user.Email,
+		Role:          user.Role.String(),
+		CardNumber:    user.CardNumber,
+		Active:        user.DeactivatedAt == nil,
+		EmailVerified: user.EmailVerifiedAt != nil,
+		MFAEnabled:    user.MFAEnabledAt != nil,
+		CreatedAt:     user.CreatedAt.String(),
+	}
+	if user.MembershipExpiresAt != nil {
+		dto.MembershipExpiresAt = user.MembershipExpiresAt.String()
+	}
+	if user.DeactivatedAt != nil {
+		dto.DeactivatedAt = user.DeactivatedAt.String()
+	}
+	if user.LockedUntil != nil && user.LockedUntil.After(time.Now()) {
+		dto.LockedUntil = user.LockedUntil.String()
+	}
+	return dto
+}
+
+func toFailedLoginDTO(attempt models.FailedLogin) models.FailedLoginDTO {
+	dto := models.FailedLoginDTO{
+		ID:          attempt.ID.String(),
+		Email:       attempt.Email,
+		IP:          attempt.IP,
+		Reason:      attempt.Reason,
+		AttemptedAt: attempt.AttemptedAt.String(),
+	}
+	if attempt.UserID != nil {
+		dto.UserID = attempt.UserID.String()
+	}
+	return dto
+}
@@ -0,0 +1,692 @@
+package userservice
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/Kaushik1766/LibraryManagement/internal/models"
+	"github.com/Kaushik1766/LibraryManagement/internal/models/enums/roles"
+	"github.com/Kaushik1766/LibraryManagement/internal/pagination"
+	"github.com/Kaushik1766/LibraryManagement/mocks"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+	"go.uber.org/mock/gomock"
+)
+
+func userCtx(role roles.UserRoles, subject string) context.Context {
+	return context.WithValue(context.Background(), "user", models.UserJwt{
+		RegisteredClaims: jwt.RegisteredClaims{Subject: subject},
+		Role:             role,
+	})
+}
+
+func TestNewUserService(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUserRepo := mocks.NewMockUserStorage(ctrl)
+	mockSessionRepo := mocks.NewMockSessionStorage(ctrl)
+	mockFailedLoginRepo := mocks.NewMockFailedLoginStorage(ctrl)
+	mockMFARepo := mocks.NewMockMFAStorage(ctrl)
+
+	want := &UserService{userRepo: mockUserRepo, sessionRepo: mockSessionRepo, failedLoginRepo: mockFailedLoginRepo, mfaRepo: mockMFARepo}
+	if got := NewUserService(mockUserRepo, mockSessionRepo, mockFailedLoginRepo, mockMFARepo); !reflect.DeepEqual(got, want) {
+		t.Errorf("NewUserService() = %v, want %v", got, want)
+	}
+}
+
+func TestUserService_GetUsers(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUserRepo := mocks.NewMockUserStorage(ctrl)
+
+	createdAt := time.Date(2024, 1, 2, 10, 0, 0, 0, time.UTC)
+	deactivatedAt := time.Date(2024, 2, 1, 0, 0, 0, 0, time.UTC)
+	patron := models.User{
+		ID:            uuid.New(),
+		Name:          "kaushik",
+		Email:         "kaushik@a.com",
+		Role:          roles.Customer,
+		CardNumber:    "LC00000001",
+		DeactivatedAt: &deactivatedAt,
+		CreatedAt:     createdAt,
+	}
+	customer := roles.Customer
+	inactive := false
+
+	tests := []struct {
+		name      string
+		ctx       context.Context
+		query     string
+		role      string
+		status    string
+		want      pagination.Page[models.UserDTO]
+		wantErr   bool
+		mockSetup func()
+	}{
+		{
+			name:   "filtered list",
+			ctx:    userCtx(roles.Librarian, ""),
+			query:  " kaushik ",
+			role:   "customer",
+			status: "deactivated",
+			want: pagination.Page[models.UserDTO]{
+				Items: []models.UserDTO{{
+					ID:            patron.ID.String(),
+					Name:          "kaushik",
+					Email:         "kaushik@a.com",
+					Role:          "Customer",
+					CardNumber:    "LC00000001",
+					Active:        false,
+					DeactivatedAt: deactivatedAt.String(),
+					CreatedAt:     createdAt.String(),
+				}},
+				Total: 1,
+			},
+			wantErr: false,
+			mockSetup: func() {
+				mockUserRepo.EXPECT().GetUsers(models.UserFilter{Query: "kaushik", Role: &customer, Active: &inactive}, gomock.Any()).
+					Return(pagination.Page[models.User]{Items: []models.User{patron}, Total: 1}, nil)
+			},
+		},
+		{
+			name:      "customer cannot list users",
+			ctx:       userCtx(roles.Customer, ""),
+			wantErr:   true,
+			mockSetup: func() {},
+		},
+		{
+			name:      "unknown role",
+			ctx:       userCtx(roles.Librarian, ""),
+			role:      "wizard",
+			wantErr:   true,
+			mockSetup: func() {},
+		},
+		{
+			name:      "unknown status",
+			ctx:       userCtx(roles.Librarian, ""),
+			status:    "asleep",
+			wantErr:   true,
+			mockSetup: func() {},
+		},
+		{
+			name:      "invalid user context",
+			ctx:       context.Background(),
+			wantErr:   true,
+			mockSetup: func() {},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := &UserService{
+				userRepo: mockUserRepo,
+			}
+			tt.mockSetup()
+			got, err := service.GetUsers(tt.ctx, tt.query, tt.role, tt.status, pagination.Request{})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetUsers() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetUsers() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUserService_GetUser(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUserRepo := mocks.NewMockUserStorage(ctrl)
+
+	userId := uuid.New()
+
+	tests := []struct {
+		name      string
+		ctx       context.Context
+		userId    string
+		wantErr   bool
+		mockSetup func()
+	}{
+		{
+			name:    "valid get user",
+			ctx:     userCtx(roles.Librarian, ""),
+			userId:  userId.String(),
+			wantErr: false,
+			mockSetup: func() {
+				mockUserRepo.EXPECT().GetUserById(userId.String()).Return(models.User{ID: userId}, nil)
+			},
+		},
+		{
+			name:      "invalid user id",
+			ctx:       userCtx(roles.Librarian, ""),
+			userId:    "not-a-uuid",
+			wantErr:   true,
+			mockSetup: func() {},
+		},
+		{
+			name:      "customer cannot look up users",
+			ctx:       userCtx(roles.Customer, ""),
+			userId:    userId.String(),
+			wantErr:   true,
+			mockSetup: func() {},
+		},
+		{
+			name:    "user not found",
+			ctx:     userCtx(roles.Librarian, ""),
+			userId:  userId.String(),
+			wantErr: true,
+			mockSetup: func() {
+				mockUserRepo.EXPECT().GetUserById(userId.String()).Return(models.User{}, errors.New("user not found"))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := &UserService{
+				userRepo: mockUserRepo,
+			}
+			tt.mockSetup()
+			got, err := service.GetUser(tt.ctx, tt.userId)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetUser() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && got.ID != tt.userId {
+				t.Errorf("GetUser() got = %v, want id %v", got, tt.userId)
+			}
+		})
+	}
+}
+
+func TestUserService_UpdateUser(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUserRepo := mocks.NewMockUserStorage(ctrl)
+
+	patron := models.User{ID: uuid.New(), Name: "kaushik", Email: "kaushik@a.com", Role: roles.Customer}
+	librarian := models.User{ID: uuid.New(), Name: "kaveri", Email: "kaveri@a.com", Role: roles.Librarian}
+	expiresAt := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	name := "kaushik s"
+	email := "kaushik@b.com"
+	badEmail := "kaushik"
+	expiry := expiresAt.Format(time.RFC3339)
+	noExpiry := ""
+
+	tests := []struct {
+		name      string
+		ctx       context.Context
+		user      models.User
+		req       models.UpdateUserDTO
+		wantErr   bool
+		mockSetup func()
+	}{
+		{
+			name:    "staff update patron, new email is unverified",
+			ctx:     userCtx(roles.Staff, ""),
+			user:    patron,
+			req:     models.UpdateUserDTO{Name: &name, Email: &email, MembershipExpiresAt: &expiry},
+			wantErr: false,
+			mockSetup: func() {
+				verifiedPatron := patron
+				verifiedPatron.EmailVerifiedAt = &expiresAt
+				mockUserRepo.EXPECT().GetUserById(patron.ID.String()).Return(verifiedPatron, nil)
+				updated := patron
+				updated.Name = name
+				updated.Email = email
+				updated.MembershipExpiresAt = &expiresAt
+				mockUserRepo.EXPECT().UpdateUser(updated).Return(nil)
+			},
+		},
+		{
+			name:    "clear membership expiry",
+			ctx:     userCtx(roles.Staff, ""),
+			user:    patron,
+			req:     models.UpdateUserDTO{MembershipExpiresAt: &noExpiry},
+			wantErr: false,
+			mockSetup: func() {
+				expiring := patron
+				expiring.MembershipExpiresAt = &expiresAt
+				mockUserRepo.EXPECT().GetUserById(patron.ID.String()).Return(expiring, nil)
+				mockUserRepo.EXPECT().UpdateUser(patron).Return(nil)
+			},
+		},
+		{
+			name:    "staff cannot update librarian",
+			ctx:     userCtx(roles.Staff, ""),
+			user:    librarian,
+			req:     models.UpdateUserDTO{Name: &name},
+			wantErr: true,
+			mockSetup: func() {
+				mockUserRepo.EXPECT().GetUserById(librarian.ID.String()).Return(librarian, nil)
+			},
+		},
+		{
+			name:    "admin update librarian",
+			ctx:     userCtx(roles.Admin, ""),
+			user:    librarian,
+			req:     models.UpdateUserDTO{Name: &name},
+			wantErr: false,
+			mockSetup: func() {
+				mockUserRepo.EXPECT().GetUserById(librarian.ID.String()).Return(librarian, nil)
+				mockUserRepo.EXPECT().UpdateUser(gomock.Any()).Return(nil)
+			},
+		},
+		{
+			name:    "invalid email",
+			ctx:     userCtx(roles.Staff, ""),
+			user:    patron,
+			req:     models.UpdateUserDTO{Email: &badEmail},
+			wantErr: true,
+			mockSetup: func() {
+				mockUserRepo.EXPECT().GetUserById(patron.ID.String()).Return(patron, nil)
+			},
+		},
+		{
+			name:      "librarian cannot update users",
+			ctx:       userCtx(roles.Librarian, ""),
+			user:      patron,
+			req:       models.UpdateUserDTO{Name: &name},
+			wantErr:   true,
+			mockSetup: func() {},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := &UserService{
+				userRepo: mockUserRepo,
+			}
+			tt.mockSetup()
+			_, err := service.UpdateUser(tt.ctx, tt.user.ID.String(), tt.req)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("UpdateUser() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestUserService_ChangeRole(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUserRepo := mocks.NewMockUserStorage(ctrl)
+	mockSessionRepo := mocks.NewMockSessionStorage(ctrl)
+
+	adminId := uuid.New()
+	patron := models.User{ID: uuid.New(), Role: roles.Customer}
+
+	tests := []struct {
+		name      string
+		ctx       context.Context
+		userId    string
+		req       models.ChangeRoleDTO
+		want      string
+		wantErr   bool
+		mockSetup func()
+	}{
+		{
+			name:    "promote patron",
+			ctx:     userCtx(roles.Admin, adminId.String()),
+			userId:  patron.ID.String(),
+			req:     models.ChangeRoleDTO{Role: "librarian"},
+			want:    "Librarian",
+			wantErr: false,
+			mockSetup: func() {
+				mockUserRepo.EXPECT().GetUserById(patron.ID.String()).Return(patron, nil)
+				mockUserRepo.EXPECT().SetRole(patron.ID.String(), roles.Librarian).Return(nil)
+				mockSessionRepo.EXPECT().RevokeUserSessions(patron.ID.String()).Return(nil)
+			},
+		},
+		{
+			name:      "staff cannot change roles",
+			ctx:       userCtx(roles.Staff, ""),
+			userId:    patron.ID.String(),
+			req:       models.ChangeRoleDTO{Role: "librarian"},
+			wantErr:   true,
+			mockSetup: func() {},
+		},
+		{
+			name:      "unknown role",
+			ctx:       userCtx(roles.Admin, adminId.String()),
+			userId:    patron.ID.String(),
+			req:       models.ChangeRoleDTO{Role: "wizard"},
+			wantErr:   true,
+			mockSetup: func() {},
+		},
+		{
+			name:    "own role",
+			ctx:     userCtx(roles.Admin, adminId.String()),
+			userId:  adminId.String(),
+			req:     models.ChangeRoleDTO{Role: "customer"},
+			wantErr: true,
+			mockSetup: func() {
+				mockUserRepo.EXPECT().GetUserById(adminId.String()).Return(models.User{ID: adminId, Role: roles.Admin}, nil)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := &UserService{
+				userRepo:    mockUserRepo,
+				sessionRepo: mockSessionRepo,
+			}
+			tt.mockSetup()
+			got, err := service.ChangeRole(tt.ctx, tt.userId, tt.req)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ChangeRole() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got.Role != tt.want {
+				t.Errorf("ChangeRole() role = %v, want %v", got.Role, tt.want)
+			}
+		})
+	}
+}
+
+func TestUserService_DeactivateUser(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUserRepo := mocks.NewMockUserStorage(ctrl)
+	mockSessionRepo := mocks.NewMockSessionStorage(ctrl)
+
+	staffId := uuid.New()
+	patron := models.User{ID: uuid.New(), Role: roles.Customer}
+	admin := models.User{ID: uuid.New(), Role: roles.Admin}
+
+	tests := []struct {
+		name      string
+		ctx       context.Context
+		userId    string
+		wantErr   bool
+		mockSetup func()
+	}{
+		{
+			name:    "deactivate patron ends their logins",
+			ctx:     userCtx(roles.Staff, staffId.String()),
+			userId:  patron.ID.String(),
+			wantErr: false,
+			mockSetup: func() {
+				mockUserRepo.EXPECT().GetUserById(patron.ID.String()).Return(patron, nil)
+				mockUserRepo.EXPECT().SetActive(patron.ID.String(), false).Return(nil)
+				mockSessionRepo.EXPECT().RevokeUserSessions(patron.ID.String()).Return(nil)
+			},
+		},
+		{
+			name:    "staff cannot deactivate admin",
+			ctx:     userCtx(roles.Staff, staffId.String()),
+			userId:  admin.ID.String(),
+			wantErr: true,
+			mockSetup: func() {
+				mockUserRepo.EXPECT().GetUserById(admin.ID.String()).Return(admin, nil)
+			},
+		},
+		{
+			name:    "own account",
+			ctx:     userCtx(roles.Admin, admin.ID.String()),
+			userId:  admin.ID.String(),
+			wantErr: true,
+			mockSetup: func() {
+				mockUserRepo.EXPECT().GetUserById(admin.ID.String()).Return(admin, nil)
+			},
+		},
+		{
+			name:      "customer cannot deactivate",
+			ctx:       userCtx(roles.Customer, ""),
+			userId:    patron.ID.String(),
+			wantErr:   true,
+			mockSetup: func() {},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := &UserService{
+				userRepo:    mockUserRepo,
+				sessionRepo: mockSessionRepo,
+			}
+			tt.mockSetup()
+			if err := service.DeactivateUser(tt.ctx, tt.userId); (err != nil) != tt.wantErr {
+				t.Errorf("DeactivateUser() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestUserService_ReactivateUser(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUserRepo := mocks.NewMockUserStorage(ctrl)
+
+	patron := models.User{ID: uuid.New(), Role: roles.Customer}
+
+	tests := []struct {
+		name      string
+		ctx       context.Context
+		wantErr   bool
+		mockSetup func()
+	}{
+		{
+			name:    "reactivate patron",
+			ctx:     userCtx(roles.Staff, ""),
+			wantErr: false,
+			mockSetup: func() {
+				mockUserRepo.EXPECT().GetUserById(patron.ID.String()).Return(patron, nil)
+				mockUserRepo.EXPECT().SetActive(patron.ID.String(), true).Return(nil)
+			},
+		},
+		{
+			name:      "librarian cannot reactivate",
+			ctx:       userCtx(roles.Librarian, ""),
+			wantErr:   true,
+			mockSetup: func() {},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := &UserService{
+				userRepo: mockUserRepo,
+			}
+			tt.mockSetup()
+			if err := service.ReactivateUser(tt.ctx, patron.ID.String()); (err != nil) != tt.wantErr {
+				t.Errorf("ReactivateUser() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestUserService_UnlockUser(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUserRepo := mocks.NewMockUserStorage(ctrl)
+
+	userId := uuid.New().String()
+
+	tests := []struct {
+		name      string
+		ctx       context.Context
+		userId    string
+		wantErr   bool
+		mockSetup func()
+	}{
+		{
+			name:    "unlock account",
+			ctx:     userCtx(roles.Admin, ""),
+			userId:  userId,
+			wantErr: false,
+			mockSetup: func() {
+				mockUserRepo.EXPECT().ClearLoginFailures(userId).Return(nil)
+			},
+		},
+		{
+			name:      "staff cannot unlock",
+			ctx:       userCtx(roles.Staff, ""),
+			userId:    userId,
+			wantErr:   true,
+			mockSetup: func() {},
+		},
+		{
+			name:      "invalid id",
+			ctx:       userCtx(roles.Admin, ""),
+			userId:    "abc",
+			wantErr:   true,
+			mockSetup: func() {},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := &UserService{
+				userRepo: mockUserRepo,
+			}
+			tt.mockSetup()
+			if err := service.UnlockUser(tt.ctx, tt.userId); (err != nil) != tt.wantErr {
+				t.Errorf("UnlockUser() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestUserService_ResetMFA(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockUserRepo := mocks.NewMockUserStorage(ctrl)
+	mockSessionRepo := mocks.NewMockSessionStorage(ctrl)
+	mockMFARepo := mocks.NewMockMFAStorage(ctrl)
+
+	enabledAt := time.Now()
+	librarian := models.User{ID: uuid.New(), Role: roles.Librarian, MFAEnabledAt: &enabledAt}
+	admin := models.User{ID: uuid.New(), Role: roles.Admin}
+
+	tests := []struct {
+		name      string
+		ctx       context.Context
+		userId    string
+		wantErr   bool
+		mockSetup func()
+	}{
+		{
+			name:    "reset ends their logins",
+			ctx:     userCtx(roles.Admin, admin.ID.String()),
+			userId:  librarian.ID.String(),
+			wantErr: false,
+			mockSetup: func() {
+				mockUserRepo.EXPECT().GetUserById(librarian.ID.String()).Return(librarian, nil)
+				mockMFARepo.EXPECT().DisableMFA(librarian.ID.String()).Return(nil)
+				mockSessionRepo.EXPECT().RevokeUserSessions(librarian.ID.String()).Return(nil)
+			},
+		},
+		{
+			name:    "own account",
+			ctx:     userCtx(roles.Admin, admin.ID.String()),
+			userId:  admin.ID.String(),
+			wantErr: true,
+			mockSetup: func() {
+				mockUserRepo.EXPECT().GetUserById(admin.ID.String()).Return(admin, nil)
+			},
+		},
+		{
+			name:      "staff cannot reset",
+			ctx:       userCtx(roles.Staff, ""),
+			userId:    librarian.ID.String(),
+			wantErr:   true,
+			mockSetup: func() {},
+		},
+		{
+			name:      "invalid id",
+			ctx:       userCtx(roles.Admin, admin.ID.String()),
+			userId:    "abc",
+			wantErr:   true,
+			mockSetup: func() {},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := &UserService{
+				userRepo:    mockUserRepo,
+				sessionRepo: mockSessionRepo,
+				mfaRepo:     mockMFARepo,
+			}
+			tt.mockSetup()
+			if err := service.ResetMFA(tt.ctx, tt.userId); (err != nil) != tt.wantErr {
+				t.Errorf("ResetMFA() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestUserService_GetFailedLogins(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockFailedLoginRepo := mocks.NewMockFailedLoginStorage(ctrl)
+
+	attemptedAt := time.Date(2024, 3, 1, 9, 0, 0, 0, time.UTC)
+	userId := uuid.New()
+	attempt := models.FailedLogin{
+		ID:          uuid.New(),
+		Email:       "kaushik@a.com",
+		UserID:      &userId,
+		IP:          "203.0.113.7",
+		Reason:      models.LoginInvalidPassword,
+		AttemptedAt: attemptedAt,
+	}
+
+	tests := []struct {
+		name      string
+		ctx       context.Context
+		want      pagination.Page[models.FailedLoginDTO]
+		wantErr   bool
+		mockSetup func()
+	}{
+		{
+			name: "list failed logins",
+			ctx:  userCtx(roles.Admin, ""),
+			want: pagination.Page[models.FailedLoginDTO]{
+				Items: []models.FailedLoginDTO{{
+					ID:          attempt.ID.String(),
+					Email:       "kaushik@a.com",
+					UserID:      userId.String(),
+					IP:          "203.0.113.7",
+					Reason:      models.LoginInvalidPassword,
+					AttemptedAt: attemptedAt.String(),
+				}},
+				Total: 1,
+			},
+			wantErr: false,
+			mockSetup: func() {
+				mockFailedLoginRepo.EXPECT().GetFailedLogins(models.FailedLoginFilter{Email: "kaushik@a.com"}, gomock.Any()).
+					Return(pagination.Page[models.FailedLogin]{Items: []models.FailedLogin{attempt}, Total: 1}, nil)
+			},
+		},
+		{
+			name:      "librarian cannot list",
+			ctx:       userCtx(roles.Librarian, ""),
+			wantErr:   true,
+			mockSetup: func() {},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := &UserService{
+				failedLoginRepo: mockFailedLoginRepo,
+			}
+			tt.mockSetup()
+			got, err := service.GetFailedLogins(tt.ctx, " kaushik@a.com ", "", pagination.Request{})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GetFailedLogins() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("GetFailedLogins() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
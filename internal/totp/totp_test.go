@@ -0,0 +1,102 @@
+package totp
+
+import (
+	"encoding/base32"
+	"net/url"
+	"testing"
+	"time"
+)
+
+// rfcSecret is the SHA1 key of the RFC 6238 test vectors.
+var rfcSecret = base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString([]byte("12345678901234567890"))
+
+func TestCode(t *testing.T) {
+	// the RFC lists 8 digit codes, these are their last 6 digits
+	tests := []struct {
+		name string
+		unix int64
+		want string
+	}{
+		{name: "59", unix: 59, want: "287082"},
+		{name: "1111111109", unix: 1111111109, want: "081804"},
+		{name: "1111111111", unix: 1111111111, want: "050471"},
+		{name: "1234567890", unix: 1234567890, want: "005924"},
+		{name: "2000000000", unix: 2000000000, want: "279037"},
+		{name: "20000000000", unix: 20000000000, want: "353130"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Code(rfcSecret, Step(time.Unix(tt.unix, 0)))
+			if err != nil {
+				t.Fatalf("Code() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Code() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCode_InvalidSecret(t *testing.T) {
+	if _, err := Code("not base32!", 1); err == nil {
+		t.Errorf("Code() error = nil, want an error")
+	}
+}
+
+func TestValidate(t *testing.T) {
+	now := time.Unix(1111111111, 0)
+	step := Step(now)
+	previous, _ := Code(rfcSecret, step-1)
+	tooOld, _ := Code(rfcSecret, step-2)
+
+	tests := []struct {
+		name     string
+		code     string
+		wantStep int64
+		wantOk   bool
+	}{
+		{name: "current period", code: "050471", wantStep: step, wantOk: true},
+		{name: "previous period", code: previous, wantStep: step - 1, wantOk: true},
+		{name: "outside the skew", code: tooOld, wantOk: false},
+		{name: "wrong code", code: "000000", wantOk: false},
+		{name: "wrong length", code: "50471", wantOk: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotStep, gotOk := Validate(rfcSecret, tt.code, now, 1)
+			if gotOk != tt.wantOk || gotStep != tt.wantStep {
+				t.Errorf("Validate() = %v, %v, want %v, %v", gotStep, gotOk, tt.wantStep, tt.wantOk)
+			}
+		})
+	}
+}
+
+func TestGenerateSecret(t *testing.T) {
+	first, err := GenerateSecret()
+	if err != nil {
+		t.Fatalf("GenerateSecret() error = %v", err)
+	}
+	second, _ := GenerateSecret()
+
+	if len(first) != 32 || first == second {
+		t.Errorf("GenerateSecret() = %v, %v, want two different 32 character secrets", first, second)
+	}
+	if _, err := Code(first, 1); err != nil {
+		t.Errorf("GenerateSecret() made a secret Code can't use: %v", err)
+	}
+}
+
+func TestURI(t *testing.T) {
+	got, err := url.Parse(URI("City Library", "kaushik@a.com", "JBSWY3DPEHPK3PXP"))
+	if err != nil {
+		t.Fatalf("URI() isn't a url: %v", err)
+	}
+
+	if got.Scheme != "otpauth" || got.Host != "totp" || got.Path != "/City Library:kaushik@a.com" {
+		t.Errorf("URI() = %v, want an otpauth totp uri labelled City Library:kaushik@a.com", got)
+	}
+	query := got.Query()
+	if query.Get("secret") != "JBSWY3DPEHPK3PXP" || query.Get("issuer") != "City Library" || query.Get("digits") != "6" || query.Get("period") != "30" {
+		t.Errorf("URI() query = %v", query)
+	}
+}
@@ -2,16 +2,80 @@ package weberrors
 
 import (
 	"encoding/json"
+	"errors"
+	"log"
 	"net/http"
+	"strings"
+
+	apperrors "github.com/Kaushik1766/LibraryManagement/internal/app_errors"
 )
 
+// RequestIDHeader carries the id of the request on the response, error bodies
+// repeat it so reports can be matched with logs.
+const RequestIDHeader = "X-Request-ID"
+
 type WebError struct {
-	Message string `json:"message"`
+	Code      string            `json:"code"`
+	Message   string            `json:"message"`
+	Details   map[string]string `json:"details,omitempty"`
+	RequestID string            `json:"request_id,omitempty"`
 }
 
+// SendError writes err as json. Domain errors carry their own status, code is
+// only used for anything else. Messages of untyped server errors are logged
+// rather than sent since they can leak internals.
 func SendError(err error, code int, w http.ResponseWriter) {
+	body := WebError{
+		Message:   err.Error(),
+		RequestID: w.Header().Get(RequestIDHeader),
+	}
+
+	var appErr *apperrors.Error
+	if errors.As(err, &appErr) {
+		code = Status(appErr.Kind)
+		body.Code = appErr.Code
+		body.Message = appErr.Message
+		body.Details = appErr.Details
+	} else {
+		body.Code = statusCode(code)
+	}
+
+	if code >= http.StatusInternalServerError {
+		log.Printf("request %s: %v\n", body.RequestID, err)
+		if appErr == nil {
+			body.Message = "internal server error"
+		}
+	}
+
 	w.WriteHeader(code)
-	_ = json.NewEncoder(w).Encode(WebError{
-		Message: err.Error(),
-	})
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+// Status maps a domain error kind to its http status.
+func Status(kind apperrors.Kind) int {
+	switch kind {
+	case apperrors.KindValidation:
+		return http.StatusBadRequest
+	case apperrors.KindNotFound:
+		return http.StatusNotFound
+	case apperrors.KindConflict:
+		return http.StatusConflict
+	case apperrors.KindUnauthorized:
+		return http.StatusUnauthorized
+	case apperrors.KindForbidden:
+		return http.StatusForbidden
+	case apperrors.KindTooManyRequests:
+		return http.StatusTooManyRequests
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// statusCode turns an http status into an error code, 404 becomes not_found.
+func statusCode(status int) string {
+	text := http.StatusText(status)
+	if text == "" {
+		return "error"
+	}
+	return strings.ReplaceAll(strings.ToLower(text), " ", "_")
 }
@@ -3,10 +3,13 @@ package weberrors
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"strings"
 	"testing"
+
+	apperrors "github.com/Kaushik1766/LibraryManagement/internal/app_errors"
 )
 
 func TestSendError(t *testing.T) {
@@ -29,7 +32,7 @@ func TestSendError(t *testing.T) {
 				w:    httptest.NewRecorder(),
 			},
 			expectedStatus: http.StatusBadRequest,
-			expectedBody:   `{"message":"invalid request data"}`,
+			expectedBody:   `{"code":"bad_request","message":"invalid request data"}`,
 		},
 		{
 			name: "send error with 401 unauthorized",
@@ -39,7 +42,7 @@ func TestSendError(t *testing.T) {
 				w:    httptest.NewRecorder(),
 			},
 			expectedStatus: http.StatusUnauthorized,
-			expectedBody:   `{"message":"authentication required"}`,
+			expectedBody:   `{"code":"unauthorized","message":"authentication required"}`,
 		},
 		{
 			name: "send error with 403 forbidden",
@@ -49,7 +52,7 @@ func TestSendError(t *testing.T) {
 				w:    httptest.NewRecorder(),
 			},
 			expectedStatus: http.StatusForbidden,
-			expectedBody:   `{"message":"access denied"}`,
+			expectedBody:   `{"code":"forbidden","message":"access denied"}`,
 		},
 		{
 			name: "send error with 404 not found",
@@ -59,7 +62,7 @@ func TestSendError(t *testing.T) {
 				w:    httptest.NewRecorder(),
 			},
 			expectedStatus: http.StatusNotFound,
-			expectedBody:   `{"message":"resource not found"}`,
+			expectedBody:   `{"code":"not_found","message":"resource not found"}`,
 		},
 		{
 			name: "send error with 500 internal server error",
@@ -69,7 +72,7 @@ func TestSendError(t *testing.T) {
 				w:    httptest.NewRecorder(),
 			},
 			expectedStatus: http.StatusInternalServerError,
-			expectedBody:   `{"message":"database connection failed"}`,
+			expectedBody:   `{"code":"internal_server_error","message":"internal server error"}`,
 		},
 		{
 			name: "send error with custom error message",
@@ -79,7 +82,7 @@ func TestSendError(t *testing.T) {
 				w:    httptest.NewRecorder(),
 			},
 			expectedStatus: http.StatusNotFound,
-			expectedBody:   `{"message":"book with ID 550e8400-e29b-41d4-a716-446655440000 not found"}`,
+			expectedBody:   `{"code":"not_found","message":"book with ID 550e8400-e29b-41d4-a716-446655440000 not found"}`,
 		},
 		{
 			name: "send error with empty error message",
@@ -89,7 +92,7 @@ func TestSendError(t *testing.T) {
 				w:    httptest.NewRecorder(),
 			},
 			expectedStatus: http.StatusBadRequest,
-			expectedBody:   `{"message":""}`,
+			expectedBody:   `{"code":"bad_request","message":""}`,
 		},
 	}
 	for _, tt := range tests {
@@ -110,10 +113,6 @@ func TestSendError(t *testing.T) {
 				if err := json.Unmarshal([]byte(body), &webErr); err != nil {
 					t.Errorf("SendError() response is not valid JSON: %v", err)
 				}
-
-				if webErr.Message != tt.args.err.Error() {
-					t.Errorf("SendError() message = %v, want %v", webErr.Message, tt.args.err.Error())
-				}
 			}
 		})
 	}
@@ -128,28 +127,28 @@ func TestWebError_JSONStructure(t *testing.T) {
 		{
 			name:     "simple message",
 			message:  "error occurred",
-			expected: `{"message":"error occurred"}`,
+			expected: `{"code":"error","message":"error occurred"}`,
 		},
 		{
 			name:     "message with special characters",
 			message:  "error: invalid input @ test.com",
-			expected: `{"message":"error: invalid input @ test.com"}`,
+			expected: `{"code":"error","message":"error: invalid input @ test.com"}`,
 		},
 		{
 			name:     "message with quotes",
 			message:  `error: "field" is required`,
-			expected: `{"message":"error: \"field\" is required"}`,
+			expected: `{"code":"error","message":"error: \"field\" is required"}`,
 		},
 		{
 			name:     "empty message",
 			message:  "",
-			expected: `{"message":""}`,
+			expected: `{"code":"error","message":""}`,
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			webErr := WebError{Message: tt.message}
+			webErr := WebError{Code: "error", Message: tt.message}
 			data, err := json.Marshal(webErr)
 			if err != nil {
 				t.Errorf("Failed to marshal WebError: %v", err)
@@ -163,3 +162,68 @@ func TestWebError_JSONStructure(t *testing.T) {
 		})
 	}
 }
+
+func TestSendError_AppError(t *testing.T) {
+	tests := []struct {
+		name           string
+		err            error
+		requestID      string
+		expectedStatus int
+		expectedBody   string
+	}{
+		{
+			name:           "validation error with details",
+			err:            apperrors.Validation("invalid_id", "invalid book id").WithDetail("book_id", "required"),
+			expectedStatus: http.StatusBadRequest,
+			expectedBody:   `{"code":"invalid_id","message":"invalid book id","details":{"book_id":"required"}}`,
+		},
+		{
+			name:           "not found ignores the fallback status",
+			err:            apperrors.NotFound("book_not_found", "book not found"),
+			expectedStatus: http.StatusNotFound,
+			expectedBody:   `{"code":"book_not_found","message":"book not found"}`,
+		},
+		{
+			name:           "wrapped conflict",
+			err:            fmt.Errorf("issuing: %w", apperrors.Conflict("book_not_available", "book not available")),
+			expectedStatus: http.StatusConflict,
+			expectedBody:   `{"code":"book_not_available","message":"book not available"}`,
+		},
+		{
+			name:           "forbidden",
+			err:            apperrors.ErrUnauthorisedUser,
+			expectedStatus: http.StatusForbidden,
+			expectedBody:   `{"code":"forbidden","message":"unauthorised user"}`,
+		},
+		{
+			name:           "rate limited",
+			err:            apperrors.TooManyRequests("verification_rate_limited", "verification email sent recently"),
+			expectedStatus: http.StatusTooManyRequests,
+			expectedBody:   `{"code":"verification_rate_limited","message":"verification email sent recently"}`,
+		},
+		{
+			name:           "request id is echoed",
+			err:            apperrors.ErrInvalidUser,
+			requestID:      "req-123",
+			expectedStatus: http.StatusUnauthorized,
+			expectedBody:   `{"code":"invalid_user","message":"invalid user","request_id":"req-123"}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			if tt.requestID != "" {
+				w.Header().Set(RequestIDHeader, tt.requestID)
+			}
+
+			SendError(tt.err, http.StatusInternalServerError, w)
+
+			if w.Code != tt.expectedStatus {
+				t.Errorf("SendError() status code = %v, want %v", w.Code, tt.expectedStatus)
+			}
+			if body := strings.TrimSpace(w.Body.String()); body != tt.expectedBody {
+				t.Errorf("SendError() body = %v, want %v", body, tt.expectedBody)
+			}
+		})
+	}
+}
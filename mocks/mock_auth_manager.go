@@ -10,6 +10,7 @@
 package mocks
 
 import (
+	context "context"
 	reflect "reflect"
 
 	models "github.com/Kaushik1766/LibraryManagement/internal/models"
@@ -40,19 +41,221 @@ func (m *MockAuthManager) EXPECT() *MockAuthManagerMockRecorder {
 	return m.recorder
 }
 
+// ChangePassword mocks base method.
+func (m *MockAuthManager) ChangePassword(ctx context.Context, req models.ChangePasswordDTO) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ChangePassword", ctx, req)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ChangePassword indicates an expected call of ChangePassword.
+func (mr *MockAuthManagerMockRecorder) ChangePassword(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ChangePassword", reflect.TypeOf((*MockAuthManager)(nil).ChangePassword), ctx, req)
+}
+
+// ConfirmMFA mocks base method.
+func (m *MockAuthManager) ConfirmMFA(ctx context.Context, req models.MFACodeDTO) (models.RecoveryCodesDTO, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ConfirmMFA", ctx, req)
+	ret0, _ := ret[0].(models.RecoveryCodesDTO)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ConfirmMFA indicates an expected call of ConfirmMFA.
+func (mr *MockAuthManagerMockRecorder) ConfirmMFA(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ConfirmMFA", reflect.TypeOf((*MockAuthManager)(nil).ConfirmMFA), ctx, req)
+}
+
+// DisableMFA mocks base method.
+func (m *MockAuthManager) DisableMFA(ctx context.Context, req models.DisableMFADTO) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DisableMFA", ctx, req)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DisableMFA indicates an expected call of DisableMFA.
+func (mr *MockAuthManagerMockRecorder) DisableMFA(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DisableMFA", reflect.TypeOf((*MockAuthManager)(nil).DisableMFA), ctx, req)
+}
+
+// ForgotPassword mocks base method.
+func (m *MockAuthManager) ForgotPassword(req models.ForgotPasswordDTO) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ForgotPassword", req)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ForgotPassword indicates an expected call of ForgotPassword.
+func (mr *MockAuthManagerMockRecorder) ForgotPassword(req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ForgotPassword", reflect.TypeOf((*MockAuthManager)(nil).ForgotPassword), req)
+}
+
+// GetProfile mocks base method.
+func (m *MockAuthManager) GetProfile(ctx context.Context) (models.ProfileDTO, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetProfile", ctx)
+	ret0, _ := ret[0].(models.ProfileDTO)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetProfile indicates an expected call of GetProfile.
+func (mr *MockAuthManagerMockRecorder) GetProfile(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetProfile", reflect.TypeOf((*MockAuthManager)(nil).GetProfile), ctx)
+}
+
+// JWKS mocks base method.
+func (m *MockAuthManager) JWKS() models.JWKSDTO {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "JWKS")
+	ret0, _ := ret[0].(models.JWKSDTO)
+	return ret0
+}
+
+// JWKS indicates an expected call of JWKS.
+func (mr *MockAuthManagerMockRecorder) JWKS() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "JWKS", reflect.TypeOf((*MockAuthManager)(nil).JWKS))
+}
+
 // Login mocks base method.
-func (m *MockAuthManager) Login(loginReq models.LoginDTO) (string, error) {
+func (m *MockAuthManager) Login(loginReq models.LoginDTO, clientIP string) (models.TokenPairDTO, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Login", loginReq)
-	ret0, _ := ret[0].(string)
+	ret := m.ctrl.Call(m, "Login", loginReq, clientIP)
+	ret0, _ := ret[0].(models.TokenPairDTO)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Login indicates an expected call of Login.
-func (mr *MockAuthManagerMockRecorder) Login(loginReq any) *gomock.Call {
+func (mr *MockAuthManagerMockRecorder) Login(loginReq, clientIP any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Login", reflect.TypeOf((*MockAuthManager)(nil).Login), loginReq, clientIP)
+}
+
+// Logout mocks base method.
+func (m *MockAuthManager) Logout(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Logout", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Logout indicates an expected call of Logout.
+func (mr *MockAuthManagerMockRecorder) Logout(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Logout", reflect.TypeOf((*MockAuthManager)(nil).Logout), ctx)
+}
+
+// LogoutAll mocks base method.
+func (m *MockAuthManager) LogoutAll(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LogoutAll", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// LogoutAll indicates an expected call of LogoutAll.
+func (mr *MockAuthManagerMockRecorder) LogoutAll(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LogoutAll", reflect.TypeOf((*MockAuthManager)(nil).LogoutAll), ctx)
+}
+
+// Refresh mocks base method.
+func (m *MockAuthManager) Refresh(refreshToken string) (models.TokenPairDTO, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Refresh", refreshToken)
+	ret0, _ := ret[0].(models.TokenPairDTO)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Refresh indicates an expected call of Refresh.
+func (mr *MockAuthManagerMockRecorder) Refresh(refreshToken any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Login", reflect.TypeOf((*MockAuthManager)(nil).Login), loginReq)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Refresh", reflect.TypeOf((*MockAuthManager)(nil).Refresh), refreshToken)
+}
+
+// RegenerateRecoveryCodes mocks base method.
+func (m *MockAuthManager) RegenerateRecoveryCodes(ctx context.Context, req models.MFACodeDTO) (models.RecoveryCodesDTO, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RegenerateRecoveryCodes", ctx, req)
+	ret0, _ := ret[0].(models.RecoveryCodesDTO)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RegenerateRecoveryCodes indicates an expected call of RegenerateRecoveryCodes.
+func (mr *MockAuthManagerMockRecorder) RegenerateRecoveryCodes(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegenerateRecoveryCodes", reflect.TypeOf((*MockAuthManager)(nil).RegenerateRecoveryCodes), ctx, req)
+}
+
+// ResendVerification mocks base method.
+func (m *MockAuthManager) ResendVerification(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResendVerification", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ResendVerification indicates an expected call of ResendVerification.
+func (mr *MockAuthManagerMockRecorder) ResendVerification(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResendVerification", reflect.TypeOf((*MockAuthManager)(nil).ResendVerification), ctx)
+}
+
+// ResetPassword mocks base method.
+func (m *MockAuthManager) ResetPassword(req models.ResetPasswordDTO) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResetPassword", req)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ResetPassword indicates an expected call of ResetPassword.
+func (mr *MockAuthManagerMockRecorder) ResetPassword(req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResetPassword", reflect.TypeOf((*MockAuthManager)(nil).ResetPassword), req)
+}
+
+// SetupMFA mocks base method.
+func (m *MockAuthManager) SetupMFA(ctx context.Context) (models.MFASetupDTO, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetupMFA", ctx)
+	ret0, _ := ret[0].(models.MFASetupDTO)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetupMFA indicates an expected call of SetupMFA.
+func (mr *MockAuthManagerMockRecorder) SetupMFA(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetupMFA", reflect.TypeOf((*MockAuthManager)(nil).SetupMFA), ctx)
+}
+
+// SetupMFAForLogin mocks base method.
+func (m *MockAuthManager) SetupMFAForLogin(req models.MFATokenDTO) (models.MFASetupDTO, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetupMFAForLogin", req)
+	ret0, _ := ret[0].(models.MFASetupDTO)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SetupMFAForLogin indicates an expected call of SetupMFAForLogin.
+func (mr *MockAuthManagerMockRecorder) SetupMFAForLogin(req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetupMFAForLogin", reflect.TypeOf((*MockAuthManager)(nil).SetupMFAForLogin), req)
 }
 
 // Signup mocks base method.
@@ -68,3 +271,59 @@ func (mr *MockAuthManagerMockRecorder) Signup(signupReq any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Signup", reflect.TypeOf((*MockAuthManager)(nil).Signup), signupReq)
 }
+
+// UpdateProfile mocks base method.
+func (m *MockAuthManager) UpdateProfile(ctx context.Context, req models.UpdateProfileDTO) (models.ProfileDTO, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateProfile", ctx, req)
+	ret0, _ := ret[0].(models.ProfileDTO)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateProfile indicates an expected call of UpdateProfile.
+func (mr *MockAuthManagerMockRecorder) UpdateProfile(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateProfile", reflect.TypeOf((*MockAuthManager)(nil).UpdateProfile), ctx, req)
+}
+
+// VerifyEmail mocks base method.
+func (m *MockAuthManager) VerifyEmail(req models.VerifyEmailDTO) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VerifyEmail", req)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// VerifyEmail indicates an expected call of VerifyEmail.
+func (mr *MockAuthManagerMockRecorder) VerifyEmail(req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerifyEmail", reflect.TypeOf((*MockAuthManager)(nil).VerifyEmail), req)
+}
+
+// VerifyMFA mocks base method.
+func (m *MockAuthManager) VerifyMFA(req models.VerifyMFADTO, clientIP string) (models.TokenPairDTO, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "VerifyMFA", req, clientIP)
+	ret0, _ := ret[0].(models.TokenPairDTO)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// VerifyMFA indicates an expected call of VerifyMFA.
+func (mr *MockAuthManagerMockRecorder) VerifyMFA(req, clientIP any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "VerifyMFA", reflect.TypeOf((*MockAuthManager)(nil).VerifyMFA), req, clientIP)
+}
+
+// Wait mocks base method.
+func (m *MockAuthManager) Wait() {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Wait")
+}
+
+// Wait indicates an expected call of Wait.
+func (mr *MockAuthManagerMockRecorder) Wait() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Wait", reflect.TypeOf((*MockAuthManager)(nil).Wait))
+}
@@ -0,0 +1,86 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: interface.go
+//
+// Generated by this command:
+//
+//	mockgen -source=interface.go -destination=../../../mocks/mock_block_manager.go -package=mocks
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	models "github.com/Kaushik1766/LibraryManagement/internal/models"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockBlockManager is a mock of BlockManager interface.
+type MockBlockManager struct {
+	ctrl     *gomock.Controller
+	recorder *MockBlockManagerMockRecorder
+	isgomock struct{}
+}
+
+// MockBlockManagerMockRecorder is the mock recorder for MockBlockManager.
+type MockBlockManagerMockRecorder struct {
+	mock *MockBlockManager
+}
+
+// NewMockBlockManager creates a new mock instance.
+func NewMockBlockManager(ctrl *gomock.Controller) *MockBlockManager {
+	mock := &MockBlockManager{ctrl: ctrl}
+	mock.recorder = &MockBlockManagerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockBlockManager) EXPECT() *MockBlockManagerMockRecorder {
+	return m.recorder
+}
+
+// GetBlocks mocks base method.
+func (m *MockBlockManager) GetBlocks(ctx context.Context, userId string) ([]models.BlockDTO, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBlocks", ctx, userId)
+	ret0, _ := ret[0].([]models.BlockDTO)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBlocks indicates an expected call of GetBlocks.
+func (mr *MockBlockManagerMockRecorder) GetBlocks(ctx, userId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBlocks", reflect.TypeOf((*MockBlockManager)(nil).GetBlocks), ctx, userId)
+}
+
+// LiftBlock mocks base method.
+func (m *MockBlockManager) LiftBlock(ctx context.Context, userId, blockId string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LiftBlock", ctx, userId, blockId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// LiftBlock indicates an expected call of LiftBlock.
+func (mr *MockBlockManagerMockRecorder) LiftBlock(ctx, userId, blockId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LiftBlock", reflect.TypeOf((*MockBlockManager)(nil).LiftBlock), ctx, userId, blockId)
+}
+
+// PlaceBlock mocks base method.
+func (m *MockBlockManager) PlaceBlock(ctx context.Context, userId string, req models.PlaceBlockDTO) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PlaceBlock", ctx, userId, req)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PlaceBlock indicates an expected call of PlaceBlock.
+func (mr *MockBlockManagerMockRecorder) PlaceBlock(ctx, userId, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PlaceBlock", reflect.TypeOf((*MockBlockManager)(nil).PlaceBlock), ctx, userId, req)
+}
@@ -0,0 +1,101 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: interface.go
+//
+// Generated by this command:
+//
+//	mockgen -source=interface.go -destination=../../../mocks/mock_block_storage.go -package=mocks
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	reflect "reflect"
+	time "time"
+
+	models "github.com/Kaushik1766/LibraryManagement/internal/models"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockBlockStorage is a mock of BlockStorage interface.
+type MockBlockStorage struct {
+	ctrl     *gomock.Controller
+	recorder *MockBlockStorageMockRecorder
+	isgomock struct{}
+}
+
+// MockBlockStorageMockRecorder is the mock recorder for MockBlockStorage.
+type MockBlockStorageMockRecorder struct {
+	mock *MockBlockStorage
+}
+
+// NewMockBlockStorage creates a new mock instance.
+func NewMockBlockStorage(ctrl *gomock.Controller) *MockBlockStorage {
+	mock := &MockBlockStorage{ctrl: ctrl}
+	mock.recorder = &MockBlockStorageMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockBlockStorage) EXPECT() *MockBlockStorageMockRecorder {
+	return m.recorder
+}
+
+// GetActiveBlocks mocks base method.
+func (m *MockBlockStorage) GetActiveBlocks(userId string) ([]models.Block, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetActiveBlocks", userId)
+	ret0, _ := ret[0].([]models.Block)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetActiveBlocks indicates an expected call of GetActiveBlocks.
+func (mr *MockBlockStorageMockRecorder) GetActiveBlocks(userId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetActiveBlocks", reflect.TypeOf((*MockBlockStorage)(nil).GetActiveBlocks), userId)
+}
+
+// GetStanding mocks base method.
+func (m *MockBlockStorage) GetStanding(userId string) (models.Standing, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetStanding", userId)
+	ret0, _ := ret[0].(models.Standing)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetStanding indicates an expected call of GetStanding.
+func (mr *MockBlockStorageMockRecorder) GetStanding(userId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStanding", reflect.TypeOf((*MockBlockStorage)(nil).GetStanding), userId)
+}
+
+// LiftBlock mocks base method.
+func (m *MockBlockStorage) LiftBlock(userId, blockId, liftedBy string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LiftBlock", userId, blockId, liftedBy)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// LiftBlock indicates an expected call of LiftBlock.
+func (mr *MockBlockStorageMockRecorder) LiftBlock(userId, blockId, liftedBy any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LiftBlock", reflect.TypeOf((*MockBlockStorage)(nil).LiftBlock), userId, blockId, liftedBy)
+}
+
+// PlaceBlock mocks base method.
+func (m *MockBlockStorage) PlaceBlock(userId, reason string, expiresAt *time.Time, placedBy string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PlaceBlock", userId, reason, expiresAt, placedBy)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PlaceBlock indicates an expected call of PlaceBlock.
+func (mr *MockBlockStorageMockRecorder) PlaceBlock(userId, reason, expiresAt, placedBy any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PlaceBlock", reflect.TypeOf((*MockBlockStorage)(nil).PlaceBlock), userId, reason, expiresAt, placedBy)
+}
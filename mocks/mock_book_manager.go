@@ -11,9 +11,12 @@ package mocks
 
 import (
 	context "context"
+	io "io"
 	reflect "reflect"
 
+	marc "github.com/Kaushik1766/LibraryManagement/internal/marc"
 	models "github.com/Kaushik1766/LibraryManagement/internal/models"
+	pagination "github.com/Kaushik1766/LibraryManagement/internal/pagination"
 	gomock "go.uber.org/mock/gomock"
 )
 
@@ -42,11 +45,12 @@ func (m *MockBookManager) EXPECT() *MockBookManagerMockRecorder {
 }
 
 // AddBook mocks base method.
-func (m *MockBookManager) AddBook(ctx context.Context, bookReq models.AddBookDTO) error {
+func (m *MockBookManager) AddBook(ctx context.Context, bookReq models.AddBookDTO) (string, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "AddBook", ctx, bookReq)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
 // AddBook indicates an expected call of AddBook.
@@ -55,17 +59,163 @@ func (mr *MockBookManagerMockRecorder) AddBook(ctx, bookReq any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddBook", reflect.TypeOf((*MockBookManager)(nil).AddBook), ctx, bookReq)
 }
 
+// AddCopies mocks base method.
+func (m *MockBookManager) AddCopies(ctx context.Context, bookId string, req models.AddCopiesDTO) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddCopies", ctx, bookId, req)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddCopies indicates an expected call of AddCopies.
+func (mr *MockBookManagerMockRecorder) AddCopies(ctx, bookId, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddCopies", reflect.TypeOf((*MockBookManager)(nil).AddCopies), ctx, bookId, req)
+}
+
+// ExportBook mocks base method.
+func (m *MockBookManager) ExportBook(ctx context.Context, bookId string) (marc.Record, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExportBook", ctx, bookId)
+	ret0, _ := ret[0].(marc.Record)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExportBook indicates an expected call of ExportBook.
+func (mr *MockBookManagerMockRecorder) ExportBook(ctx, bookId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExportBook", reflect.TypeOf((*MockBookManager)(nil).ExportBook), ctx, bookId)
+}
+
+// ExportCatalog mocks base method.
+func (m *MockBookManager) ExportCatalog(ctx context.Context, enc marc.Encoder) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExportCatalog", ctx, enc)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ExportCatalog indicates an expected call of ExportCatalog.
+func (mr *MockBookManagerMockRecorder) ExportCatalog(ctx, enc any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExportCatalog", reflect.TypeOf((*MockBookManager)(nil).ExportCatalog), ctx, enc)
+}
+
 // GetAllBooks mocks base method.
-func (m *MockBookManager) GetAllBooks(ctx context.Context, title, author string) ([]models.BookDTO, error) {
+func (m *MockBookManager) GetAllBooks(ctx context.Context, title, author string, page pagination.Request) (pagination.Page[models.BookDTO], error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetAllBooks", ctx, title, author)
-	ret0, _ := ret[0].([]models.BookDTO)
+	ret := m.ctrl.Call(m, "GetAllBooks", ctx, title, author, page)
+	ret0, _ := ret[0].(pagination.Page[models.BookDTO])
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetAllBooks indicates an expected call of GetAllBooks.
-func (mr *MockBookManagerMockRecorder) GetAllBooks(ctx, title, author any) *gomock.Call {
+func (mr *MockBookManagerMockRecorder) GetAllBooks(ctx, title, author, page any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllBooks", reflect.TypeOf((*MockBookManager)(nil).GetAllBooks), ctx, title, author, page)
+}
+
+// GetBook mocks base method.
+func (m *MockBookManager) GetBook(ctx context.Context, bookId string) (models.BookDTO, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBook", ctx, bookId)
+	ret0, _ := ret[0].(models.BookDTO)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBook indicates an expected call of GetBook.
+func (mr *MockBookManagerMockRecorder) GetBook(ctx, bookId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBook", reflect.TypeOf((*MockBookManager)(nil).GetBook), ctx, bookId)
+}
+
+// GetCopies mocks base method.
+func (m *MockBookManager) GetCopies(ctx context.Context, bookId string) ([]models.CopyDTO, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCopies", ctx, bookId)
+	ret0, _ := ret[0].([]models.CopyDTO)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCopies indicates an expected call of GetCopies.
+func (mr *MockBookManagerMockRecorder) GetCopies(ctx, bookId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCopies", reflect.TypeOf((*MockBookManager)(nil).GetCopies), ctx, bookId)
+}
+
+// ImportBooks mocks base method.
+func (m *MockBookManager) ImportBooks(ctx context.Context, format string, data io.Reader, dryRun bool) (models.ImportReport, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ImportBooks", ctx, format, data, dryRun)
+	ret0, _ := ret[0].(models.ImportReport)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ImportBooks indicates an expected call of ImportBooks.
+func (mr *MockBookManagerMockRecorder) ImportBooks(ctx, format, data, dryRun any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ImportBooks", reflect.TypeOf((*MockBookManager)(nil).ImportBooks), ctx, format, data, dryRun)
+}
+
+// SearchBooks mocks base method.
+func (m *MockBookManager) SearchBooks(ctx context.Context, search models.BookSearch, page pagination.Request) (models.SearchResultDTO, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SearchBooks", ctx, search, page)
+	ret0, _ := ret[0].(models.SearchResultDTO)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SearchBooks indicates an expected call of SearchBooks.
+func (mr *MockBookManagerMockRecorder) SearchBooks(ctx, search, page any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchBooks", reflect.TypeOf((*MockBookManager)(nil).SearchBooks), ctx, search, page)
+}
+
+// UpdateBook mocks base method.
+func (m *MockBookManager) UpdateBook(ctx context.Context, bookId string, req models.UpdateBookDTO) (models.BookDTO, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateBook", ctx, bookId, req)
+	ret0, _ := ret[0].(models.BookDTO)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateBook indicates an expected call of UpdateBook.
+func (mr *MockBookManagerMockRecorder) UpdateBook(ctx, bookId, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateBook", reflect.TypeOf((*MockBookManager)(nil).UpdateBook), ctx, bookId, req)
+}
+
+// WithdrawBook mocks base method.
+func (m *MockBookManager) WithdrawBook(ctx context.Context, bookId, reason string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WithdrawBook", ctx, bookId, reason)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// WithdrawBook indicates an expected call of WithdrawBook.
+func (mr *MockBookManagerMockRecorder) WithdrawBook(ctx, bookId, reason any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WithdrawBook", reflect.TypeOf((*MockBookManager)(nil).WithdrawBook), ctx, bookId, reason)
+}
+
+// WithdrawCopy mocks base method.
+func (m *MockBookManager) WithdrawCopy(ctx context.Context, bookId, copyId, reason string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WithdrawCopy", ctx, bookId, copyId, reason)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// WithdrawCopy indicates an expected call of WithdrawCopy.
+func (mr *MockBookManagerMockRecorder) WithdrawCopy(ctx, bookId, copyId, reason any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllBooks", reflect.TypeOf((*MockBookManager)(nil).GetAllBooks), ctx, title, author)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WithdrawCopy", reflect.TypeOf((*MockBookManager)(nil).WithdrawCopy), ctx, bookId, copyId, reason)
 }
@@ -13,6 +13,7 @@ import (
 	reflect "reflect"
 
 	models "github.com/Kaushik1766/LibraryManagement/internal/models"
+	pagination "github.com/Kaushik1766/LibraryManagement/internal/pagination"
 	gomock "go.uber.org/mock/gomock"
 )
 
@@ -41,30 +42,191 @@ func (m *MockBookStorage) EXPECT() *MockBookStorageMockRecorder {
 }
 
 // AddBook mocks base method.
-func (m *MockBookStorage) AddBook(title, author string, copies int) error {
+func (m *MockBookStorage) AddBook(book models.Book, copies int, location string) (string, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "AddBook", title, author, copies)
+	ret := m.ctrl.Call(m, "AddBook", book, copies, location)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AddBook indicates an expected call of AddBook.
+func (mr *MockBookStorageMockRecorder) AddBook(book, copies, location any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddBook", reflect.TypeOf((*MockBookStorage)(nil).AddBook), book, copies, location)
+}
+
+// AddCopies mocks base method.
+func (m *MockBookStorage) AddCopies(bookId string, copies int, location string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddCopies", bookId, copies, location)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// AddBook indicates an expected call of AddBook.
-func (mr *MockBookStorageMockRecorder) AddBook(title, author, copies any) *gomock.Call {
+// AddCopies indicates an expected call of AddCopies.
+func (mr *MockBookStorageMockRecorder) AddCopies(bookId, copies, location any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddBook", reflect.TypeOf((*MockBookStorage)(nil).AddBook), title, author, copies)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddCopies", reflect.TypeOf((*MockBookStorage)(nil).AddCopies), bookId, copies, location)
+}
+
+// EachBook mocks base method.
+func (m *MockBookStorage) EachBook(fn func(models.Book) error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EachBook", fn)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// EachBook indicates an expected call of EachBook.
+func (mr *MockBookStorageMockRecorder) EachBook(fn any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EachBook", reflect.TypeOf((*MockBookStorage)(nil).EachBook), fn)
 }
 
 // GetAllBooks mocks base method.
-func (m *MockBookStorage) GetAllBooks(title, author string) ([]models.Book, error) {
+func (m *MockBookStorage) GetAllBooks(title, author string, page pagination.Request) (pagination.Page[models.Book], error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetAllBooks", title, author)
-	ret0, _ := ret[0].([]models.Book)
+	ret := m.ctrl.Call(m, "GetAllBooks", title, author, page)
+	ret0, _ := ret[0].(pagination.Page[models.Book])
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetAllBooks indicates an expected call of GetAllBooks.
-func (mr *MockBookStorageMockRecorder) GetAllBooks(title, author any) *gomock.Call {
+func (mr *MockBookStorageMockRecorder) GetAllBooks(title, author, page any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllBooks", reflect.TypeOf((*MockBookStorage)(nil).GetAllBooks), title, author, page)
+}
+
+// GetBookById mocks base method.
+func (m *MockBookStorage) GetBookById(bookId string) (models.Book, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBookById", bookId)
+	ret0, _ := ret[0].(models.Book)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBookById indicates an expected call of GetBookById.
+func (mr *MockBookStorageMockRecorder) GetBookById(bookId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBookById", reflect.TypeOf((*MockBookStorage)(nil).GetBookById), bookId)
+}
+
+// GetCopies mocks base method.
+func (m *MockBookStorage) GetCopies(bookId string) ([]models.Copy, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCopies", bookId)
+	ret0, _ := ret[0].([]models.Copy)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCopies indicates an expected call of GetCopies.
+func (mr *MockBookStorageMockRecorder) GetCopies(bookId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCopies", reflect.TypeOf((*MockBookStorage)(nil).GetCopies), bookId)
+}
+
+// GetCopyByBarcode mocks base method.
+func (m *MockBookStorage) GetCopyByBarcode(barcode string) (models.Copy, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCopyByBarcode", barcode)
+	ret0, _ := ret[0].(models.Copy)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCopyByBarcode indicates an expected call of GetCopyByBarcode.
+func (mr *MockBookStorageMockRecorder) GetCopyByBarcode(barcode any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCopyByBarcode", reflect.TypeOf((*MockBookStorage)(nil).GetCopyByBarcode), barcode)
+}
+
+// GetCopyById mocks base method.
+func (m *MockBookStorage) GetCopyById(copyId string) (models.Copy, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCopyById", copyId)
+	ret0, _ := ret[0].(models.Copy)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCopyById indicates an expected call of GetCopyById.
+func (mr *MockBookStorageMockRecorder) GetCopyById(copyId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCopyById", reflect.TypeOf((*MockBookStorage)(nil).GetCopyById), copyId)
+}
+
+// ImportBooks mocks base method.
+func (m *MockBookStorage) ImportBooks(rows []models.ImportRow, dryRun bool) ([]models.ImportRowResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ImportBooks", rows, dryRun)
+	ret0, _ := ret[0].([]models.ImportRowResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ImportBooks indicates an expected call of ImportBooks.
+func (mr *MockBookStorageMockRecorder) ImportBooks(rows, dryRun any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ImportBooks", reflect.TypeOf((*MockBookStorage)(nil).ImportBooks), rows, dryRun)
+}
+
+// SearchBooks mocks base method.
+func (m *MockBookStorage) SearchBooks(search models.BookSearch, page pagination.Request) (models.SearchResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SearchBooks", search, page)
+	ret0, _ := ret[0].(models.SearchResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SearchBooks indicates an expected call of SearchBooks.
+func (mr *MockBookStorageMockRecorder) SearchBooks(search, page any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchBooks", reflect.TypeOf((*MockBookStorage)(nil).SearchBooks), search, page)
+}
+
+// UpdateBook mocks base method.
+func (m *MockBookStorage) UpdateBook(book models.Book) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateBook", book)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateBook indicates an expected call of UpdateBook.
+func (mr *MockBookStorageMockRecorder) UpdateBook(book any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateBook", reflect.TypeOf((*MockBookStorage)(nil).UpdateBook), book)
+}
+
+// WithdrawBook mocks base method.
+func (m *MockBookStorage) WithdrawBook(bookId, reason string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WithdrawBook", bookId, reason)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// WithdrawBook indicates an expected call of WithdrawBook.
+func (mr *MockBookStorageMockRecorder) WithdrawBook(bookId, reason any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WithdrawBook", reflect.TypeOf((*MockBookStorage)(nil).WithdrawBook), bookId, reason)
+}
+
+// WithdrawCopy mocks base method.
+func (m *MockBookStorage) WithdrawCopy(bookId, copyId, reason string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WithdrawCopy", bookId, copyId, reason)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// WithdrawCopy indicates an expected call of WithdrawCopy.
+func (mr *MockBookStorageMockRecorder) WithdrawCopy(bookId, copyId, reason any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllBooks", reflect.TypeOf((*MockBookStorage)(nil).GetAllBooks), title, author)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WithdrawCopy", reflect.TypeOf((*MockBookStorage)(nil).WithdrawCopy), bookId, copyId, reason)
 }
@@ -0,0 +1,87 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: interface.go
+//
+// Generated by this command:
+//
+//	mockgen -source=interface.go -destination=../../../mocks/mock_failed_login_storage.go -package=mocks
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	reflect "reflect"
+	time "time"
+
+	models "github.com/Kaushik1766/LibraryManagement/internal/models"
+	pagination "github.com/Kaushik1766/LibraryManagement/internal/pagination"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockFailedLoginStorage is a mock of FailedLoginStorage interface.
+type MockFailedLoginStorage struct {
+	ctrl     *gomock.Controller
+	recorder *MockFailedLoginStorageMockRecorder
+	isgomock struct{}
+}
+
+// MockFailedLoginStorageMockRecorder is the mock recorder for MockFailedLoginStorage.
+type MockFailedLoginStorageMockRecorder struct {
+	mock *MockFailedLoginStorage
+}
+
+// NewMockFailedLoginStorage creates a new mock instance.
+func NewMockFailedLoginStorage(ctrl *gomock.Controller) *MockFailedLoginStorage {
+	mock := &MockFailedLoginStorage{ctrl: ctrl}
+	mock.recorder = &MockFailedLoginStorageMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockFailedLoginStorage) EXPECT() *MockFailedLoginStorageMockRecorder {
+	return m.recorder
+}
+
+// CountFromIP mocks base method.
+func (m *MockFailedLoginStorage) CountFromIP(ip string, since time.Time) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountFromIP", ip, since)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountFromIP indicates an expected call of CountFromIP.
+func (mr *MockFailedLoginStorageMockRecorder) CountFromIP(ip, since any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountFromIP", reflect.TypeOf((*MockFailedLoginStorage)(nil).CountFromIP), ip, since)
+}
+
+// GetFailedLogins mocks base method.
+func (m *MockFailedLoginStorage) GetFailedLogins(filter models.FailedLoginFilter, page pagination.Request) (pagination.Page[models.FailedLogin], error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFailedLogins", filter, page)
+	ret0, _ := ret[0].(pagination.Page[models.FailedLogin])
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFailedLogins indicates an expected call of GetFailedLogins.
+func (mr *MockFailedLoginStorageMockRecorder) GetFailedLogins(filter, page any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFailedLogins", reflect.TypeOf((*MockFailedLoginStorage)(nil).GetFailedLogins), filter, page)
+}
+
+// RecordFailedLogin mocks base method.
+func (m *MockFailedLoginStorage) RecordFailedLogin(attempt models.FailedLogin) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordFailedLogin", attempt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecordFailedLogin indicates an expected call of RecordFailedLogin.
+func (mr *MockFailedLoginStorageMockRecorder) RecordFailedLogin(attempt any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordFailedLogin", reflect.TypeOf((*MockFailedLoginStorage)(nil).RecordFailedLogin), attempt)
+}
@@ -0,0 +1,100 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: interface.go
+//
+// Generated by this command:
+//
+//	mockgen -source=interface.go -destination=../../../mocks/mock_fine_manager.go -package=mocks
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	models "github.com/Kaushik1766/LibraryManagement/internal/models"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockFineManager is a mock of FineManager interface.
+type MockFineManager struct {
+	ctrl     *gomock.Controller
+	recorder *MockFineManagerMockRecorder
+	isgomock struct{}
+}
+
+// MockFineManagerMockRecorder is the mock recorder for MockFineManager.
+type MockFineManagerMockRecorder struct {
+	mock *MockFineManager
+}
+
+// NewMockFineManager creates a new mock instance.
+func NewMockFineManager(ctrl *gomock.Controller) *MockFineManager {
+	mock := &MockFineManager{ctrl: ctrl}
+	mock.recorder = &MockFineManagerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockFineManager) EXPECT() *MockFineManagerMockRecorder {
+	return m.recorder
+}
+
+// AccrueFines mocks base method.
+func (m *MockFineManager) AccrueFines() (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AccrueFines")
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AccrueFines indicates an expected call of AccrueFines.
+func (mr *MockFineManagerMockRecorder) AccrueFines() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AccrueFines", reflect.TypeOf((*MockFineManager)(nil).AccrueFines))
+}
+
+// GetFines mocks base method.
+func (m *MockFineManager) GetFines(ctx context.Context, userId string) (models.FineBalanceDTO, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFines", ctx, userId)
+	ret0, _ := ret[0].(models.FineBalanceDTO)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFines indicates an expected call of GetFines.
+func (mr *MockFineManagerMockRecorder) GetFines(ctx, userId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFines", reflect.TypeOf((*MockFineManager)(nil).GetFines), ctx, userId)
+}
+
+// RecordPayment mocks base method.
+func (m *MockFineManager) RecordPayment(ctx context.Context, fineId string, amount int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordPayment", ctx, fineId, amount)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecordPayment indicates an expected call of RecordPayment.
+func (mr *MockFineManagerMockRecorder) RecordPayment(ctx, fineId, amount any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordPayment", reflect.TypeOf((*MockFineManager)(nil).RecordPayment), ctx, fineId, amount)
+}
+
+// WaiveFine mocks base method.
+func (m *MockFineManager) WaiveFine(ctx context.Context, fineId string, amount int, reason string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "WaiveFine", ctx, fineId, amount, reason)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// WaiveFine indicates an expected call of WaiveFine.
+func (mr *MockFineManagerMockRecorder) WaiveFine(ctx, fineId, amount, reason any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WaiveFine", reflect.TypeOf((*MockFineManager)(nil).WaiveFine), ctx, fineId, amount, reason)
+}
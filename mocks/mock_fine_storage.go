@@ -0,0 +1,115 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: interface.go
+//
+// Generated by this command:
+//
+//	mockgen -source=interface.go -destination=../../../mocks/mock_fine_storage.go -package=mocks
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	reflect "reflect"
+
+	models "github.com/Kaushik1766/LibraryManagement/internal/models"
+	paymentkind "github.com/Kaushik1766/LibraryManagement/internal/models/enums/paymentkind"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockFineStorage is a mock of FineStorage interface.
+type MockFineStorage struct {
+	ctrl     *gomock.Controller
+	recorder *MockFineStorageMockRecorder
+	isgomock struct{}
+}
+
+// MockFineStorageMockRecorder is the mock recorder for MockFineStorage.
+type MockFineStorageMockRecorder struct {
+	mock *MockFineStorage
+}
+
+// NewMockFineStorage creates a new mock instance.
+func NewMockFineStorage(ctrl *gomock.Controller) *MockFineStorage {
+	mock := &MockFineStorage{ctrl: ctrl}
+	mock.recorder = &MockFineStorageMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockFineStorage) EXPECT() *MockFineStorageMockRecorder {
+	return m.recorder
+}
+
+// AccrueFines mocks base method.
+func (m *MockFineStorage) AccrueFines(perDay, cap int) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AccrueFines", perDay, cap)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AccrueFines indicates an expected call of AccrueFines.
+func (mr *MockFineStorageMockRecorder) AccrueFines(perDay, cap any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AccrueFines", reflect.TypeOf((*MockFineStorage)(nil).AccrueFines), perDay, cap)
+}
+
+// AssessFine mocks base method.
+func (m *MockFineStorage) AssessFine(transactionId string, perDay, cap int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AssessFine", transactionId, perDay, cap)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AssessFine indicates an expected call of AssessFine.
+func (mr *MockFineStorageMockRecorder) AssessFine(transactionId, perDay, cap any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AssessFine", reflect.TypeOf((*MockFineStorage)(nil).AssessFine), transactionId, perDay, cap)
+}
+
+// GetFineById mocks base method.
+func (m *MockFineStorage) GetFineById(fineId string) (models.Fine, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFineById", fineId)
+	ret0, _ := ret[0].(models.Fine)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFineById indicates an expected call of GetFineById.
+func (mr *MockFineStorageMockRecorder) GetFineById(fineId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFineById", reflect.TypeOf((*MockFineStorage)(nil).GetFineById), fineId)
+}
+
+// GetFines mocks base method.
+func (m *MockFineStorage) GetFines(userId string) ([]models.Fine, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFines", userId)
+	ret0, _ := ret[0].([]models.Fine)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFines indicates an expected call of GetFines.
+func (mr *MockFineStorageMockRecorder) GetFines(userId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFines", reflect.TypeOf((*MockFineStorage)(nil).GetFines), userId)
+}
+
+// RecordPayment mocks base method.
+func (m *MockFineStorage) RecordPayment(fineId string, kind paymentkind.PaymentKind, amount int, reason, recordedBy string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordPayment", fineId, kind, amount, reason, recordedBy)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecordPayment indicates an expected call of RecordPayment.
+func (mr *MockFineStorageMockRecorder) RecordPayment(fineId, kind, amount, reason, recordedBy any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordPayment", reflect.TypeOf((*MockFineStorage)(nil).RecordPayment), fineId, kind, amount, reason, recordedBy)
+}
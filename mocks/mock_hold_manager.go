@@ -0,0 +1,101 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: interface.go
+//
+// Generated by this command:
+//
+//	mockgen -source=interface.go -destination=../../../mocks/mock_hold_manager.go -package=mocks
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	models "github.com/Kaushik1766/LibraryManagement/internal/models"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockHoldManager is a mock of HoldManager interface.
+type MockHoldManager struct {
+	ctrl     *gomock.Controller
+	recorder *MockHoldManagerMockRecorder
+	isgomock struct{}
+}
+
+// MockHoldManagerMockRecorder is the mock recorder for MockHoldManager.
+type MockHoldManagerMockRecorder struct {
+	mock *MockHoldManager
+}
+
+// NewMockHoldManager creates a new mock instance.
+func NewMockHoldManager(ctrl *gomock.Controller) *MockHoldManager {
+	mock := &MockHoldManager{ctrl: ctrl}
+	mock.recorder = &MockHoldManagerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockHoldManager) EXPECT() *MockHoldManagerMockRecorder {
+	return m.recorder
+}
+
+// CancelHold mocks base method.
+func (m *MockHoldManager) CancelHold(ctx context.Context, holdId string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CancelHold", ctx, holdId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CancelHold indicates an expected call of CancelHold.
+func (mr *MockHoldManagerMockRecorder) CancelHold(ctx, holdId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CancelHold", reflect.TypeOf((*MockHoldManager)(nil).CancelHold), ctx, holdId)
+}
+
+// GetHolds mocks base method.
+func (m *MockHoldManager) GetHolds(ctx context.Context) ([]models.HoldDTO, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetHolds", ctx)
+	ret0, _ := ret[0].([]models.HoldDTO)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetHolds indicates an expected call of GetHolds.
+func (mr *MockHoldManagerMockRecorder) GetHolds(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetHolds", reflect.TypeOf((*MockHoldManager)(nil).GetHolds), ctx)
+}
+
+// PlaceHold mocks base method.
+func (m *MockHoldManager) PlaceHold(ctx context.Context, bookId string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PlaceHold", ctx, bookId)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PlaceHold indicates an expected call of PlaceHold.
+func (mr *MockHoldManagerMockRecorder) PlaceHold(ctx, bookId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PlaceHold", reflect.TypeOf((*MockHoldManager)(nil).PlaceHold), ctx, bookId)
+}
+
+// ProcessExpiredHolds mocks base method.
+func (m *MockHoldManager) ProcessExpiredHolds() (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ProcessExpiredHolds")
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ProcessExpiredHolds indicates an expected call of ProcessExpiredHolds.
+func (mr *MockHoldManagerMockRecorder) ProcessExpiredHolds() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ProcessExpiredHolds", reflect.TypeOf((*MockHoldManager)(nil).ProcessExpiredHolds))
+}
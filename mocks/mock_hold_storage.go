@@ -0,0 +1,145 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: interface.go
+//
+// Generated by this command:
+//
+//	mockgen -source=interface.go -destination=../../../mocks/mock_hold_storage.go -package=mocks
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	reflect "reflect"
+
+	models "github.com/Kaushik1766/LibraryManagement/internal/models"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockHoldStorage is a mock of HoldStorage interface.
+type MockHoldStorage struct {
+	ctrl     *gomock.Controller
+	recorder *MockHoldStorageMockRecorder
+	isgomock struct{}
+}
+
+// MockHoldStorageMockRecorder is the mock recorder for MockHoldStorage.
+type MockHoldStorageMockRecorder struct {
+	mock *MockHoldStorage
+}
+
+// NewMockHoldStorage creates a new mock instance.
+func NewMockHoldStorage(ctrl *gomock.Controller) *MockHoldStorage {
+	mock := &MockHoldStorage{ctrl: ctrl}
+	mock.recorder = &MockHoldStorageMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockHoldStorage) EXPECT() *MockHoldStorageMockRecorder {
+	return m.recorder
+}
+
+// AllocateNext mocks base method.
+func (m *MockHoldStorage) AllocateNext(copyId, pickupWindow string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AllocateNext", copyId, pickupWindow)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AllocateNext indicates an expected call of AllocateNext.
+func (mr *MockHoldStorageMockRecorder) AllocateNext(copyId, pickupWindow any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AllocateNext", reflect.TypeOf((*MockHoldStorage)(nil).AllocateNext), copyId, pickupWindow)
+}
+
+// CancelHold mocks base method.
+func (m *MockHoldStorage) CancelHold(holdId, userId string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CancelHold", holdId, userId)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CancelHold indicates an expected call of CancelHold.
+func (mr *MockHoldStorageMockRecorder) CancelHold(holdId, userId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CancelHold", reflect.TypeOf((*MockHoldStorage)(nil).CancelHold), holdId, userId)
+}
+
+// ExpireHolds mocks base method.
+func (m *MockHoldStorage) ExpireHolds() ([]string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExpireHolds")
+	ret0, _ := ret[0].([]string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExpireHolds indicates an expected call of ExpireHolds.
+func (mr *MockHoldStorageMockRecorder) ExpireHolds() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExpireHolds", reflect.TypeOf((*MockHoldStorage)(nil).ExpireHolds))
+}
+
+// FulfillHold mocks base method.
+func (m *MockHoldStorage) FulfillHold(bookId, userId string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FulfillHold", bookId, userId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// FulfillHold indicates an expected call of FulfillHold.
+func (mr *MockHoldStorageMockRecorder) FulfillHold(bookId, userId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FulfillHold", reflect.TypeOf((*MockHoldStorage)(nil).FulfillHold), bookId, userId)
+}
+
+// GetActiveHolds mocks base method.
+func (m *MockHoldStorage) GetActiveHolds(userId string) ([]models.Hold, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetActiveHolds", userId)
+	ret0, _ := ret[0].([]models.Hold)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetActiveHolds indicates an expected call of GetActiveHolds.
+func (mr *MockHoldStorageMockRecorder) GetActiveHolds(userId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetActiveHolds", reflect.TypeOf((*MockHoldStorage)(nil).GetActiveHolds), userId)
+}
+
+// HasWaitingHolds mocks base method.
+func (m *MockHoldStorage) HasWaitingHolds(bookId string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "HasWaitingHolds", bookId)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// HasWaitingHolds indicates an expected call of HasWaitingHolds.
+func (mr *MockHoldStorageMockRecorder) HasWaitingHolds(bookId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "HasWaitingHolds", reflect.TypeOf((*MockHoldStorage)(nil).HasWaitingHolds), bookId)
+}
+
+// PlaceHold mocks base method.
+func (m *MockHoldStorage) PlaceHold(bookId, userId string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PlaceHold", bookId, userId)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PlaceHold indicates an expected call of PlaceHold.
+func (mr *MockHoldStorageMockRecorder) PlaceHold(bookId, userId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PlaceHold", reflect.TypeOf((*MockHoldStorage)(nil).PlaceHold), bookId, userId)
+}
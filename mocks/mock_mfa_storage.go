@@ -0,0 +1,125 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: interface.go
+//
+// Generated by this command:
+//
+//	mockgen -source=interface.go -destination=../../../mocks/mock_mfa_storage.go -package=mocks
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	reflect "reflect"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockMFAStorage is a mock of MFAStorage interface.
+type MockMFAStorage struct {
+	ctrl     *gomock.Controller
+	recorder *MockMFAStorageMockRecorder
+	isgomock struct{}
+}
+
+// MockMFAStorageMockRecorder is the mock recorder for MockMFAStorage.
+type MockMFAStorageMockRecorder struct {
+	mock *MockMFAStorage
+}
+
+// NewMockMFAStorage creates a new mock instance.
+func NewMockMFAStorage(ctrl *gomock.Controller) *MockMFAStorage {
+	mock := &MockMFAStorage{ctrl: ctrl}
+	mock.recorder = &MockMFAStorageMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockMFAStorage) EXPECT() *MockMFAStorageMockRecorder {
+	return m.recorder
+}
+
+// ClaimStep mocks base method.
+func (m *MockMFAStorage) ClaimStep(userId string, step int64) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimStep", userId, step)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimStep indicates an expected call of ClaimStep.
+func (mr *MockMFAStorageMockRecorder) ClaimStep(userId, step any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimStep", reflect.TypeOf((*MockMFAStorage)(nil).ClaimStep), userId, step)
+}
+
+// DisableMFA mocks base method.
+func (m *MockMFAStorage) DisableMFA(userId string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DisableMFA", userId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DisableMFA indicates an expected call of DisableMFA.
+func (mr *MockMFAStorageMockRecorder) DisableMFA(userId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DisableMFA", reflect.TypeOf((*MockMFAStorage)(nil).DisableMFA), userId)
+}
+
+// EnableMFA mocks base method.
+func (m *MockMFAStorage) EnableMFA(userId string, codeHashes []string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "EnableMFA", userId, codeHashes)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// EnableMFA indicates an expected call of EnableMFA.
+func (mr *MockMFAStorageMockRecorder) EnableMFA(userId, codeHashes any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "EnableMFA", reflect.TypeOf((*MockMFAStorage)(nil).EnableMFA), userId, codeHashes)
+}
+
+// ReplaceRecoveryCodes mocks base method.
+func (m *MockMFAStorage) ReplaceRecoveryCodes(userId string, codeHashes []string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReplaceRecoveryCodes", userId, codeHashes)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReplaceRecoveryCodes indicates an expected call of ReplaceRecoveryCodes.
+func (mr *MockMFAStorageMockRecorder) ReplaceRecoveryCodes(userId, codeHashes any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReplaceRecoveryCodes", reflect.TypeOf((*MockMFAStorage)(nil).ReplaceRecoveryCodes), userId, codeHashes)
+}
+
+// SetPendingSecret mocks base method.
+func (m *MockMFAStorage) SetPendingSecret(userId, secret string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetPendingSecret", userId, secret)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetPendingSecret indicates an expected call of SetPendingSecret.
+func (mr *MockMFAStorageMockRecorder) SetPendingSecret(userId, secret any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetPendingSecret", reflect.TypeOf((*MockMFAStorage)(nil).SetPendingSecret), userId, secret)
+}
+
+// UseRecoveryCode mocks base method.
+func (m *MockMFAStorage) UseRecoveryCode(userId, codeHash string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UseRecoveryCode", userId, codeHash)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UseRecoveryCode indicates an expected call of UseRecoveryCode.
+func (mr *MockMFAStorageMockRecorder) UseRecoveryCode(userId, codeHash any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UseRecoveryCode", reflect.TypeOf((*MockMFAStorage)(nil).UseRecoveryCode), userId, codeHash)
+}
@@ -0,0 +1,85 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: interface.go
+//
+// Generated by this command:
+//
+//	mockgen -source=interface.go -destination=../../../mocks/mock_reset_storage.go -package=mocks
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	reflect "reflect"
+	time "time"
+
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockResetStorage is a mock of ResetStorage interface.
+type MockResetStorage struct {
+	ctrl     *gomock.Controller
+	recorder *MockResetStorageMockRecorder
+	isgomock struct{}
+}
+
+// MockResetStorageMockRecorder is the mock recorder for MockResetStorage.
+type MockResetStorageMockRecorder struct {
+	mock *MockResetStorage
+}
+
+// NewMockResetStorage creates a new mock instance.
+func NewMockResetStorage(ctrl *gomock.Controller) *MockResetStorage {
+	mock := &MockResetStorage{ctrl: ctrl}
+	mock.recorder = &MockResetStorageMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockResetStorage) EXPECT() *MockResetStorageMockRecorder {
+	return m.recorder
+}
+
+// CreateReset mocks base method.
+func (m *MockResetStorage) CreateReset(userId, tokenHash string, expiresAt time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateReset", userId, tokenHash, expiresAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateReset indicates an expected call of CreateReset.
+func (mr *MockResetStorageMockRecorder) CreateReset(userId, tokenHash, expiresAt any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateReset", reflect.TypeOf((*MockResetStorage)(nil).CreateReset), userId, tokenHash, expiresAt)
+}
+
+// PurgeExpired mocks base method.
+func (m *MockResetStorage) PurgeExpired() (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeExpired")
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PurgeExpired indicates an expected call of PurgeExpired.
+func (mr *MockResetStorageMockRecorder) PurgeExpired() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeExpired", reflect.TypeOf((*MockResetStorage)(nil).PurgeExpired))
+}
+
+// UseReset mocks base method.
+func (m *MockResetStorage) UseReset(tokenHash string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UseReset", tokenHash)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UseReset indicates an expected call of UseReset.
+func (mr *MockResetStorageMockRecorder) UseReset(tokenHash any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UseReset", reflect.TypeOf((*MockResetStorage)(nil).UseReset), tokenHash)
+}
@@ -0,0 +1,171 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: interface.go
+//
+// Generated by this command:
+//
+//	mockgen -source=interface.go -destination=../../../mocks/mock_session_storage.go -package=mocks
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	reflect "reflect"
+
+	models "github.com/Kaushik1766/LibraryManagement/internal/models"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockSessionStorage is a mock of SessionStorage interface.
+type MockSessionStorage struct {
+	ctrl     *gomock.Controller
+	recorder *MockSessionStorageMockRecorder
+	isgomock struct{}
+}
+
+// MockSessionStorageMockRecorder is the mock recorder for MockSessionStorage.
+type MockSessionStorageMockRecorder struct {
+	mock *MockSessionStorage
+}
+
+// NewMockSessionStorage creates a new mock instance.
+func NewMockSessionStorage(ctrl *gomock.Controller) *MockSessionStorage {
+	mock := &MockSessionStorage{ctrl: ctrl}
+	mock.recorder = &MockSessionStorageMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSessionStorage) EXPECT() *MockSessionStorageMockRecorder {
+	return m.recorder
+}
+
+// ChangePassword mocks base method.
+func (m *MockSessionStorage) ChangePassword(userId, password, familyId string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ChangePassword", userId, password, familyId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ChangePassword indicates an expected call of ChangePassword.
+func (mr *MockSessionStorageMockRecorder) ChangePassword(userId, password, familyId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ChangePassword", reflect.TypeOf((*MockSessionStorage)(nil).ChangePassword), userId, password, familyId)
+}
+
+// CreateSession mocks base method.
+func (m *MockSessionStorage) CreateSession(session models.Session) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateSession", session)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateSession indicates an expected call of CreateSession.
+func (mr *MockSessionStorageMockRecorder) CreateSession(session any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateSession", reflect.TypeOf((*MockSessionStorage)(nil).CreateSession), session)
+}
+
+// GetSessionByAccessToken mocks base method.
+func (m *MockSessionStorage) GetSessionByAccessToken(jti string) (models.Session, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSessionByAccessToken", jti)
+	ret0, _ := ret[0].(models.Session)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSessionByAccessToken indicates an expected call of GetSessionByAccessToken.
+func (mr *MockSessionStorageMockRecorder) GetSessionByAccessToken(jti any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSessionByAccessToken", reflect.TypeOf((*MockSessionStorage)(nil).GetSessionByAccessToken), jti)
+}
+
+// GetSessionByToken mocks base method.
+func (m *MockSessionStorage) GetSessionByToken(tokenHash string) (models.Session, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSessionByToken", tokenHash)
+	ret0, _ := ret[0].(models.Session)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetSessionByToken indicates an expected call of GetSessionByToken.
+func (mr *MockSessionStorageMockRecorder) GetSessionByToken(tokenHash any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSessionByToken", reflect.TypeOf((*MockSessionStorage)(nil).GetSessionByToken), tokenHash)
+}
+
+// IsRevoked mocks base method.
+func (m *MockSessionStorage) IsRevoked(jti string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsRevoked", jti)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IsRevoked indicates an expected call of IsRevoked.
+func (mr *MockSessionStorageMockRecorder) IsRevoked(jti any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsRevoked", reflect.TypeOf((*MockSessionStorage)(nil).IsRevoked), jti)
+}
+
+// PurgeExpired mocks base method.
+func (m *MockSessionStorage) PurgeExpired() (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeExpired")
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PurgeExpired indicates an expected call of PurgeExpired.
+func (mr *MockSessionStorageMockRecorder) PurgeExpired() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeExpired", reflect.TypeOf((*MockSessionStorage)(nil).PurgeExpired))
+}
+
+// RevokeFamily mocks base method.
+func (m *MockSessionStorage) RevokeFamily(familyId string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeFamily", familyId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeFamily indicates an expected call of RevokeFamily.
+func (mr *MockSessionStorageMockRecorder) RevokeFamily(familyId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeFamily", reflect.TypeOf((*MockSessionStorage)(nil).RevokeFamily), familyId)
+}
+
+// RevokeUserSessions mocks base method.
+func (m *MockSessionStorage) RevokeUserSessions(userId string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RevokeUserSessions", userId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RevokeUserSessions indicates an expected call of RevokeUserSessions.
+func (mr *MockSessionStorageMockRecorder) RevokeUserSessions(userId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RevokeUserSessions", reflect.TypeOf((*MockSessionStorage)(nil).RevokeUserSessions), userId)
+}
+
+// RotateSession mocks base method.
+func (m *MockSessionStorage) RotateSession(sessionId string, next models.Session) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RotateSession", sessionId, next)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RotateSession indicates an expected call of RotateSession.
+func (mr *MockSessionStorageMockRecorder) RotateSession(sessionId, next any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RotateSession", reflect.TypeOf((*MockSessionStorage)(nil).RotateSession), sessionId, next)
+}
@@ -14,6 +14,7 @@ import (
 	reflect "reflect"
 
 	models "github.com/Kaushik1766/LibraryManagement/internal/models"
+	pagination "github.com/Kaushik1766/LibraryManagement/internal/pagination"
 	gomock "go.uber.org/mock/gomock"
 )
 
@@ -41,34 +42,63 @@ func (m *MockTransactionManager) EXPECT() *MockTransactionManagerMockRecorder {
 	return m.recorder
 }
 
+// DeskIssue mocks base method.
+func (m *MockTransactionManager) DeskIssue(ctx context.Context, req models.DeskIssueDTO) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeskIssue", ctx, req)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeskIssue indicates an expected call of DeskIssue.
+func (mr *MockTransactionManagerMockRecorder) DeskIssue(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeskIssue", reflect.TypeOf((*MockTransactionManager)(nil).DeskIssue), ctx, req)
+}
+
+// DeskReturn mocks base method.
+func (m *MockTransactionManager) DeskReturn(ctx context.Context, req models.DeskReturnDTO) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeskReturn", ctx, req)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeskReturn indicates an expected call of DeskReturn.
+func (mr *MockTransactionManagerMockRecorder) DeskReturn(ctx, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeskReturn", reflect.TypeOf((*MockTransactionManager)(nil).DeskReturn), ctx, req)
+}
+
 // GetOverdueTransactions mocks base method.
-func (m *MockTransactionManager) GetOverdueTransactions(ctx context.Context) ([]models.OverdueTransactionDTO, error) {
+func (m *MockTransactionManager) GetOverdueTransactions(ctx context.Context, page pagination.Request) (pagination.Page[models.OverdueTransactionDTO], error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetOverdueTransactions", ctx)
-	ret0, _ := ret[0].([]models.OverdueTransactionDTO)
+	ret := m.ctrl.Call(m, "GetOverdueTransactions", ctx, page)
+	ret0, _ := ret[0].(pagination.Page[models.OverdueTransactionDTO])
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetOverdueTransactions indicates an expected call of GetOverdueTransactions.
-func (mr *MockTransactionManagerMockRecorder) GetOverdueTransactions(ctx any) *gomock.Call {
+func (mr *MockTransactionManagerMockRecorder) GetOverdueTransactions(ctx, page any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOverdueTransactions", reflect.TypeOf((*MockTransactionManager)(nil).GetOverdueTransactions), ctx)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOverdueTransactions", reflect.TypeOf((*MockTransactionManager)(nil).GetOverdueTransactions), ctx, page)
 }
 
 // GetTransactions mocks base method.
-func (m *MockTransactionManager) GetTransactions(ctx context.Context, dto models.GetTransactionRequestDTO) ([]models.TransactionDTO, error) {
+func (m *MockTransactionManager) GetTransactions(ctx context.Context, dto models.GetTransactionRequestDTO, page pagination.Request) (pagination.Page[models.TransactionDTO], error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetTransactions", ctx, dto)
-	ret0, _ := ret[0].([]models.TransactionDTO)
+	ret := m.ctrl.Call(m, "GetTransactions", ctx, dto, page)
+	ret0, _ := ret[0].(pagination.Page[models.TransactionDTO])
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetTransactions indicates an expected call of GetTransactions.
-func (mr *MockTransactionManagerMockRecorder) GetTransactions(ctx, dto any) *gomock.Call {
+func (mr *MockTransactionManagerMockRecorder) GetTransactions(ctx, dto, page any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTransactions", reflect.TypeOf((*MockTransactionManager)(nil).GetTransactions), ctx, dto)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTransactions", reflect.TypeOf((*MockTransactionManager)(nil).GetTransactions), ctx, dto, page)
 }
 
 // IssueBook mocks base method.
@@ -86,6 +116,21 @@ func (mr *MockTransactionManagerMockRecorder) IssueBook(ctx, bookId, issueFor an
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IssueBook", reflect.TypeOf((*MockTransactionManager)(nil).IssueBook), ctx, bookId, issueFor)
 }
 
+// RenewBook mocks base method.
+func (m *MockTransactionManager) RenewBook(ctx context.Context, transactionId string) (models.RenewalDTO, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RenewBook", ctx, transactionId)
+	ret0, _ := ret[0].(models.RenewalDTO)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RenewBook indicates an expected call of RenewBook.
+func (mr *MockTransactionManagerMockRecorder) RenewBook(ctx, transactionId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RenewBook", reflect.TypeOf((*MockTransactionManager)(nil).RenewBook), ctx, transactionId)
+}
+
 // ReturnBook mocks base method.
 func (m *MockTransactionManager) ReturnBook(ctx context.Context, bookId string) error {
 	m.ctrl.T.Helper()
@@ -11,8 +11,10 @@ package mocks
 
 import (
 	reflect "reflect"
+	time "time"
 
 	models "github.com/Kaushik1766/LibraryManagement/internal/models"
+	pagination "github.com/Kaushik1766/LibraryManagement/internal/pagination"
 	gomock "go.uber.org/mock/gomock"
 )
 
@@ -40,57 +42,118 @@ func (m *MockTransactionStorage) EXPECT() *MockTransactionStorageMockRecorder {
 	return m.recorder
 }
 
+// CountOpenLoans mocks base method.
+func (m *MockTransactionStorage) CountOpenLoans(userId, itemType string) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountOpenLoans", userId, itemType)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountOpenLoans indicates an expected call of CountOpenLoans.
+func (mr *MockTransactionStorageMockRecorder) CountOpenLoans(userId, itemType any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountOpenLoans", reflect.TypeOf((*MockTransactionStorage)(nil).CountOpenLoans), userId, itemType)
+}
+
 // GetAllTransactions mocks base method.
-func (m *MockTransactionStorage) GetAllTransactions(dto models.GetTransactionRequestDTO) ([]models.Transaction, error) {
+func (m *MockTransactionStorage) GetAllTransactions(dto models.GetTransactionRequestDTO, page pagination.Request) (pagination.Page[models.Transaction], error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetAllTransactions", dto)
-	ret0, _ := ret[0].([]models.Transaction)
+	ret := m.ctrl.Call(m, "GetAllTransactions", dto, page)
+	ret0, _ := ret[0].(pagination.Page[models.Transaction])
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetAllTransactions indicates an expected call of GetAllTransactions.
-func (mr *MockTransactionStorageMockRecorder) GetAllTransactions(dto any) *gomock.Call {
+func (mr *MockTransactionStorageMockRecorder) GetAllTransactions(dto, page any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllTransactions", reflect.TypeOf((*MockTransactionStorage)(nil).GetAllTransactions), dto)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAllTransactions", reflect.TypeOf((*MockTransactionStorage)(nil).GetAllTransactions), dto, page)
 }
 
 // GetOverDueTransactions mocks base method.
-func (m *MockTransactionStorage) GetOverDueTransactions(userId string) ([]models.Transaction, error) {
+func (m *MockTransactionStorage) GetOverDueTransactions(userId string, page pagination.Request) (pagination.Page[models.Transaction], error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "GetOverDueTransactions", userId)
-	ret0, _ := ret[0].([]models.Transaction)
+	ret := m.ctrl.Call(m, "GetOverDueTransactions", userId, page)
+	ret0, _ := ret[0].(pagination.Page[models.Transaction])
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // GetOverDueTransactions indicates an expected call of GetOverDueTransactions.
-func (mr *MockTransactionStorageMockRecorder) GetOverDueTransactions(userId any) *gomock.Call {
+func (mr *MockTransactionStorageMockRecorder) GetOverDueTransactions(userId, page any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOverDueTransactions", reflect.TypeOf((*MockTransactionStorage)(nil).GetOverDueTransactions), userId)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOverDueTransactions", reflect.TypeOf((*MockTransactionStorage)(nil).GetOverDueTransactions), userId, page)
+}
+
+// GetTransactionById mocks base method.
+func (m *MockTransactionStorage) GetTransactionById(transactionId string) (models.Transaction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTransactionById", transactionId)
+	ret0, _ := ret[0].(models.Transaction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTransactionById indicates an expected call of GetTransactionById.
+func (mr *MockTransactionStorageMockRecorder) GetTransactionById(transactionId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTransactionById", reflect.TypeOf((*MockTransactionStorage)(nil).GetTransactionById), transactionId)
 }
 
 // IssueBook mocks base method.
-func (m *MockTransactionStorage) IssueBook(bookId, userId, issueFor string) (string, error) {
+func (m *MockTransactionStorage) IssueBook(bookId, userId string, terms models.LoanTerms) (string, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "IssueBook", bookId, userId, issueFor)
+	ret := m.ctrl.Call(m, "IssueBook", bookId, userId, terms)
 	ret0, _ := ret[0].(string)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // IssueBook indicates an expected call of IssueBook.
-func (mr *MockTransactionStorageMockRecorder) IssueBook(bookId, userId, issueFor any) *gomock.Call {
+func (mr *MockTransactionStorageMockRecorder) IssueBook(bookId, userId, terms any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IssueBook", reflect.TypeOf((*MockTransactionStorage)(nil).IssueBook), bookId, userId, terms)
+}
+
+// IssueCopy mocks base method.
+func (m *MockTransactionStorage) IssueCopy(copyId, userId, issuedBy string, terms models.LoanTerms) (models.Transaction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IssueCopy", copyId, userId, issuedBy, terms)
+	ret0, _ := ret[0].(models.Transaction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// IssueCopy indicates an expected call of IssueCopy.
+func (mr *MockTransactionStorageMockRecorder) IssueCopy(copyId, userId, issuedBy, terms any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IssueCopy", reflect.TypeOf((*MockTransactionStorage)(nil).IssueCopy), copyId, userId, issuedBy, terms)
+}
+
+// RenewBook mocks base method.
+func (m *MockTransactionStorage) RenewBook(transactionId, extendBy string, maxRenewals int) (time.Time, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RenewBook", transactionId, extendBy, maxRenewals)
+	ret0, _ := ret[0].(time.Time)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RenewBook indicates an expected call of RenewBook.
+func (mr *MockTransactionStorageMockRecorder) RenewBook(transactionId, extendBy, maxRenewals any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IssueBook", reflect.TypeOf((*MockTransactionStorage)(nil).IssueBook), bookId, userId, issueFor)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RenewBook", reflect.TypeOf((*MockTransactionStorage)(nil).RenewBook), transactionId, extendBy, maxRenewals)
 }
 
 // ReturnBook mocks base method.
-func (m *MockTransactionStorage) ReturnBook(bookId, userId string) error {
+func (m *MockTransactionStorage) ReturnBook(bookId, userId string) (models.Transaction, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "ReturnBook", bookId, userId)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret0, _ := ret[0].(models.Transaction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
 // ReturnBook indicates an expected call of ReturnBook.
@@ -98,3 +161,18 @@ func (mr *MockTransactionStorageMockRecorder) ReturnBook(bookId, userId any) *go
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReturnBook", reflect.TypeOf((*MockTransactionStorage)(nil).ReturnBook), bookId, userId)
 }
+
+// ReturnCopy mocks base method.
+func (m *MockTransactionStorage) ReturnCopy(copyId, userId, returnedBy string) (models.Transaction, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReturnCopy", copyId, userId, returnedBy)
+	ret0, _ := ret[0].(models.Transaction)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReturnCopy indicates an expected call of ReturnCopy.
+func (mr *MockTransactionStorageMockRecorder) ReturnCopy(copyId, userId, returnedBy any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReturnCopy", reflect.TypeOf((*MockTransactionStorage)(nil).ReturnCopy), copyId, userId, returnedBy)
+}
@@ -0,0 +1,174 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: interface.go
+//
+// Generated by this command:
+//
+//	mockgen -source=interface.go -destination=../../../mocks/mock_user_manager.go -package=mocks
+//
+
+// Package mocks is a generated GoMock package.
+package mocks
+
+import (
+	context "context"
+	reflect "reflect"
+
+	models "github.com/Kaushik1766/LibraryManagement/internal/models"
+	pagination "github.com/Kaushik1766/LibraryManagement/internal/pagination"
+	gomock "go.uber.org/mock/gomock"
+)
+
+// MockUserManager is a mock of UserManager interface.
+type MockUserManager struct {
+	ctrl     *gomock.Controller
+	recorder *MockUserManagerMockRecorder
+	isgomock struct{}
+}
+
+// MockUserManagerMockRecorder is the mock recorder for MockUserManager.
+type MockUserManagerMockRecorder struct {
+	mock *MockUserManager
+}
+
+// NewMockUserManager creates a new mock instance.
+func NewMockUserManager(ctrl *gomock.Controller) *MockUserManager {
+	mock := &MockUserManager{ctrl: ctrl}
+	mock.recorder = &MockUserManagerMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockUserManager) EXPECT() *MockUserManagerMockRecorder {
+	return m.recorder
+}
+
+// ChangeRole mocks base method.
+func (m *MockUserManager) ChangeRole(ctx context.Context, userId string, req models.ChangeRoleDTO) (models.UserDTO, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ChangeRole", ctx, userId, req)
+	ret0, _ := ret[0].(models.UserDTO)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ChangeRole indicates an expected call of ChangeRole.
+func (mr *MockUserManagerMockRecorder) ChangeRole(ctx, userId, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ChangeRole", reflect.TypeOf((*MockUserManager)(nil).ChangeRole), ctx, userId, req)
+}
+
+// DeactivateUser mocks base method.
+func (m *MockUserManager) DeactivateUser(ctx context.Context, userId string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeactivateUser", ctx, userId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeactivateUser indicates an expected call of DeactivateUser.
+func (mr *MockUserManagerMockRecorder) DeactivateUser(ctx, userId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeactivateUser", reflect.TypeOf((*MockUserManager)(nil).DeactivateUser), ctx, userId)
+}
+
+// GetFailedLogins mocks base method.
+func (m *MockUserManager) GetFailedLogins(ctx context.Context, email, ip string, page pagination.Request) (pagination.Page[models.FailedLoginDTO], error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetFailedLogins", ctx, email, ip, page)
+	ret0, _ := ret[0].(pagination.Page[models.FailedLoginDTO])
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetFailedLogins indicates an expected call of GetFailedLogins.
+func (mr *MockUserManagerMockRecorder) GetFailedLogins(ctx, email, ip, page any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetFailedLogins", reflect.TypeOf((*MockUserManager)(nil).GetFailedLogins), ctx, email, ip, page)
+}
+
+// GetUser mocks base method.
+func (m *MockUserManager) GetUser(ctx context.Context, userId string) (models.UserDTO, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUser", ctx, userId)
+	ret0, _ := ret[0].(models.UserDTO)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUser indicates an expected call of GetUser.
+func (mr *MockUserManagerMockRecorder) GetUser(ctx, userId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUser", reflect.TypeOf((*MockUserManager)(nil).GetUser), ctx, userId)
+}
+
+// GetUsers mocks base method.
+func (m *MockUserManager) GetUsers(ctx context.Context, query, role, status string, page pagination.Request) (pagination.Page[models.UserDTO], error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUsers", ctx, query, role, status, page)
+	ret0, _ := ret[0].(pagination.Page[models.UserDTO])
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUsers indicates an expected call of GetUsers.
+func (mr *MockUserManagerMockRecorder) GetUsers(ctx, query, role, status, page any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUsers", reflect.TypeOf((*MockUserManager)(nil).GetUsers), ctx, query, role, status, page)
+}
+
+// ReactivateUser mocks base method.
+func (m *MockUserManager) ReactivateUser(ctx context.Context, userId string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReactivateUser", ctx, userId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReactivateUser indicates an expected call of ReactivateUser.
+func (mr *MockUserManagerMockRecorder) ReactivateUser(ctx, userId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReactivateUser", reflect.TypeOf((*MockUserManager)(nil).ReactivateUser), ctx, userId)
+}
+
+// ResetMFA mocks base method.
+func (m *MockUserManager) ResetMFA(ctx context.Context, userId string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResetMFA", ctx, userId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ResetMFA indicates an expected call of ResetMFA.
+func (mr *MockUserManagerMockRecorder) ResetMFA(ctx, userId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResetMFA", reflect.TypeOf((*MockUserManager)(nil).ResetMFA), ctx, userId)
+}
+
+// UnlockUser mocks base method.
+func (m *MockUserManager) UnlockUser(ctx context.Context, userId string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UnlockUser", ctx, userId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UnlockUser indicates an expected call of UnlockUser.
+func (mr *MockUserManagerMockRecorder) UnlockUser(ctx, userId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnlockUser", reflect.TypeOf((*MockUserManager)(nil).UnlockUser), ctx, userId)
+}
+
+// UpdateUser mocks base method.
+func (m *MockUserManager) UpdateUser(ctx context.Context, userId string, req models.UpdateUserDTO) (models.UserDTO, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateUser", ctx, userId, req)
+	ret0, _ := ret[0].(models.UserDTO)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateUser indicates an expected call of UpdateUser.
+func (mr *MockUserManagerMockRecorder) UpdateUser(ctx, userId, req any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUser", reflect.TypeOf((*MockUserManager)(nil).UpdateUser), ctx, userId, req)
+}
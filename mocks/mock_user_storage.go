@@ -11,8 +11,11 @@ package mocks
 
 import (
 	reflect "reflect"
+	time "time"
 
 	models "github.com/Kaushik1766/LibraryManagement/internal/models"
+	roles "github.com/Kaushik1766/LibraryManagement/internal/models/enums/roles"
+	pagination "github.com/Kaushik1766/LibraryManagement/internal/pagination"
 	gomock "go.uber.org/mock/gomock"
 )
 
@@ -41,11 +44,12 @@ func (m *MockUserStorage) EXPECT() *MockUserStorageMockRecorder {
 }
 
 // AddUser mocks base method.
-func (m *MockUserStorage) AddUser(name, email, password string) error {
+func (m *MockUserStorage) AddUser(name, email, password string) (string, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "AddUser", name, email, password)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
 // AddUser indicates an expected call of AddUser.
@@ -54,6 +58,50 @@ func (mr *MockUserStorageMockRecorder) AddUser(name, email, password any) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddUser", reflect.TypeOf((*MockUserStorage)(nil).AddUser), name, email, password)
 }
 
+// ClaimVerificationEmail mocks base method.
+func (m *MockUserStorage) ClaimVerificationEmail(userId string, interval time.Duration) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimVerificationEmail", userId, interval)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimVerificationEmail indicates an expected call of ClaimVerificationEmail.
+func (mr *MockUserStorageMockRecorder) ClaimVerificationEmail(userId, interval any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimVerificationEmail", reflect.TypeOf((*MockUserStorage)(nil).ClaimVerificationEmail), userId, interval)
+}
+
+// ClearLoginFailures mocks base method.
+func (m *MockUserStorage) ClearLoginFailures(userId string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClearLoginFailures", userId)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ClearLoginFailures indicates an expected call of ClearLoginFailures.
+func (mr *MockUserStorageMockRecorder) ClearLoginFailures(userId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClearLoginFailures", reflect.TypeOf((*MockUserStorage)(nil).ClearLoginFailures), userId)
+}
+
+// GetUserByCardNumber mocks base method.
+func (m *MockUserStorage) GetUserByCardNumber(cardNumber string) (models.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserByCardNumber", cardNumber)
+	ret0, _ := ret[0].(models.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserByCardNumber indicates an expected call of GetUserByCardNumber.
+func (mr *MockUserStorageMockRecorder) GetUserByCardNumber(cardNumber any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserByCardNumber", reflect.TypeOf((*MockUserStorage)(nil).GetUserByCardNumber), cardNumber)
+}
+
 // GetUserByEmail mocks base method.
 func (m *MockUserStorage) GetUserByEmail(email string) (models.User, error) {
 	m.ctrl.T.Helper()
@@ -68,3 +116,132 @@ func (mr *MockUserStorageMockRecorder) GetUserByEmail(email any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserByEmail", reflect.TypeOf((*MockUserStorage)(nil).GetUserByEmail), email)
 }
+
+// GetUserById mocks base method.
+func (m *MockUserStorage) GetUserById(userId string) (models.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserById", userId)
+	ret0, _ := ret[0].(models.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserById indicates an expected call of GetUserById.
+func (mr *MockUserStorageMockRecorder) GetUserById(userId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserById", reflect.TypeOf((*MockUserStorage)(nil).GetUserById), userId)
+}
+
+// GetUsers mocks base method.
+func (m *MockUserStorage) GetUsers(filter models.UserFilter, page pagination.Request) (pagination.Page[models.User], error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUsers", filter, page)
+	ret0, _ := ret[0].(pagination.Page[models.User])
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUsers indicates an expected call of GetUsers.
+func (mr *MockUserStorageMockRecorder) GetUsers(filter, page any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUsers", reflect.TypeOf((*MockUserStorage)(nil).GetUsers), filter, page)
+}
+
+// LockUntil mocks base method.
+func (m *MockUserStorage) LockUntil(userId string, until time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LockUntil", userId, until)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// LockUntil indicates an expected call of LockUntil.
+func (mr *MockUserStorageMockRecorder) LockUntil(userId, until any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LockUntil", reflect.TypeOf((*MockUserStorage)(nil).LockUntil), userId, until)
+}
+
+// MarkEmailVerified mocks base method.
+func (m *MockUserStorage) MarkEmailVerified(userId, email string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkEmailVerified", userId, email)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkEmailVerified indicates an expected call of MarkEmailVerified.
+func (mr *MockUserStorageMockRecorder) MarkEmailVerified(userId, email any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkEmailVerified", reflect.TypeOf((*MockUserStorage)(nil).MarkEmailVerified), userId, email)
+}
+
+// RecordLoginFailure mocks base method.
+func (m *MockUserStorage) RecordLoginFailure(userId string) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordLoginFailure", userId)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RecordLoginFailure indicates an expected call of RecordLoginFailure.
+func (mr *MockUserStorageMockRecorder) RecordLoginFailure(userId any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordLoginFailure", reflect.TypeOf((*MockUserStorage)(nil).RecordLoginFailure), userId)
+}
+
+// SetActive mocks base method.
+func (m *MockUserStorage) SetActive(userId string, active bool) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetActive", userId, active)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetActive indicates an expected call of SetActive.
+func (mr *MockUserStorageMockRecorder) SetActive(userId, active any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetActive", reflect.TypeOf((*MockUserStorage)(nil).SetActive), userId, active)
+}
+
+// SetPassword mocks base method.
+func (m *MockUserStorage) SetPassword(userId, password string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetPassword", userId, password)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetPassword indicates an expected call of SetPassword.
+func (mr *MockUserStorageMockRecorder) SetPassword(userId, password any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetPassword", reflect.TypeOf((*MockUserStorage)(nil).SetPassword), userId, password)
+}
+
+// SetRole mocks base method.
+func (m *MockUserStorage) SetRole(userId string, role roles.UserRoles) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SetRole", userId, role)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SetRole indicates an expected call of SetRole.
+func (mr *MockUserStorageMockRecorder) SetRole(userId, role any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SetRole", reflect.TypeOf((*MockUserStorage)(nil).SetRole), userId, role)
+}
+
+// UpdateUser mocks base method.
+func (m *MockUserStorage) UpdateUser(user models.User) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateUser", user)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateUser indicates an expected call of UpdateUser.
+func (mr *MockUserStorageMockRecorder) UpdateUser(user any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUser", reflect.TypeOf((*MockUserStorage)(nil).UpdateUser), user)
+}
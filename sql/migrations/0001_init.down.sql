@@ -0,0 +1,3 @@
+drop table if exists transactions;
+drop table if exists books;
+drop table if exists users;
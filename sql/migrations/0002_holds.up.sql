@@ -0,0 +1,16 @@
+create table if not exists holds(
+    id uuid primary key default uuid_generate_v4(),
+    book_id uuid references books(id) not null ,
+    user_id uuid references users(id) not null ,
+    status varchar(20) not null default 'waiting',
+    placed_at timestamp default now() not null ,
+    ready_at timestamp default null,
+    expires_at timestamp default null,
+    closed_at timestamp default null
+);
+
+-- a patron can only be in a book's queue once at a time
+create unique index if not exists holds_active_user_book
+    on holds(book_id, user_id) where status in ('waiting', 'ready');
+
+create index if not exists holds_queue on holds(book_id, placed_at) where status = 'waiting';
@@ -0,0 +1,9 @@
+create table if not exists renewals(
+    id uuid primary key default uuid_generate_v4(),
+    transaction_id uuid references transactions(id) not null ,
+    renewed_at timestamp default now() not null ,
+    previous_till timestamp not null ,
+    renewed_till timestamp not null
+);
+
+create index if not exists renewals_transaction on renewals(transaction_id);
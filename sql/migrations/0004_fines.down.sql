@@ -0,0 +1,2 @@
+drop table if exists payments;
+drop table if exists fines;
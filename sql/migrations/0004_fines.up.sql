@@ -0,0 +1,26 @@
+-- amounts are stored in the smallest currency unit
+
+create table if not exists fines(
+    id uuid primary key default uuid_generate_v4(),
+    transaction_id uuid references transactions(id) unique not null ,
+    user_id uuid references users(id) not null ,
+    amount int not null default 0,
+    days_overdue int not null default 0,
+    final boolean not null default false,
+    assessed_at timestamp default now() not null
+);
+
+create index if not exists fines_user on fines(user_id);
+
+create table if not exists payments(
+    id uuid primary key default uuid_generate_v4(),
+    fine_id uuid references fines(id) not null ,
+    kind varchar(10) not null ,
+    amount int not null ,
+    reason varchar(255) default null,
+    recorded_by uuid references users(id) not null ,
+    recorded_at timestamp default now() not null ,
+    constraint check_amount check ( amount > 0 )
+);
+
+create index if not exists payments_fine on payments(fine_id);
@@ -0,0 +1,31 @@
+drop index if exists holds_active_user_title;
+drop index if exists holds_queue;
+
+-- waiting holds go back to pointing at some copy of the title
+update holds as h set copy_id = (
+    select c.id from copies as c where c.title_id = h.title_id limit 1
+) where h.copy_id is null;
+alter table holds rename column copy_id to book_id;
+alter table holds alter column book_id set not null;
+alter table holds drop column title_id;
+
+create unique index if not exists holds_active_user_book
+    on holds(book_id, user_id) where status in ('waiting', 'ready');
+create index if not exists holds_queue on holds(book_id, placed_at) where status = 'waiting';
+
+alter table transactions rename column copy_id to book_id;
+
+alter table copies add column title varchar(255);
+alter table copies add column author varchar(255);
+update copies as c set title = t.title, author = t.author from titles as t where t.id = c.title_id;
+alter table copies alter column title set not null;
+alter table copies alter column author set not null;
+
+drop index if exists copies_title;
+alter table copies drop column title_id;
+alter table copies drop column barcode;
+alter table copies drop column status;
+alter table copies drop column location;
+alter table copies rename to books;
+
+drop table if exists titles;
@@ -0,0 +1,49 @@
+-- books held one row per physical copy with the bibliographic data repeated on
+-- each row. split it into titles (the work/edition) and copies (the items).
+
+create table if not exists titles(
+    id uuid primary key default uuid_generate_v4(),
+    title varchar(255) not null ,
+    author varchar(255) not null ,
+    isbn varchar(17) unique default null,
+    publisher varchar(255) default null,
+    published_year int default null,
+    language varchar(35) default null
+);
+
+insert into titles (title, author)
+select distinct title, author from books;
+
+alter table books rename to copies;
+alter table copies add column title_id uuid references titles(id);
+update copies as c set title_id = t.id from titles as t where t.title = c.title and t.author = c.author;
+alter table copies alter column title_id set not null;
+alter table copies drop column title;
+alter table copies drop column author;
+
+alter table copies add column barcode varchar(32) unique;
+update copies set barcode = upper(substr(replace(cast(id as text), '-', ''), 1, 12));
+alter table copies alter column barcode set not null;
+
+-- whether a copy is on loan or set aside for a hold is derived from
+-- transactions and holds, status only tracks whether it can circulate at all
+alter table copies add column status varchar(20) not null default 'available';
+alter table copies add column location varchar(255) default null;
+
+create index if not exists copies_title on copies(title_id);
+
+alter table transactions rename column book_id to copy_id;
+
+-- holds are placed on a title and only point at a copy once one is set aside
+alter table holds add column title_id uuid references titles(id);
+update holds as h set title_id = c.title_id from copies as c where c.id = h.book_id;
+alter table holds alter column title_id set not null;
+alter table holds rename column book_id to copy_id;
+alter table holds alter column copy_id drop not null;
+update holds set copy_id = null where status = 'waiting';
+
+drop index if exists holds_active_user_book;
+drop index if exists holds_queue;
+create unique index if not exists holds_active_user_title
+    on holds(title_id, user_id) where status in ('waiting', 'ready');
+create index if not exists holds_queue on holds(title_id, placed_at) where status = 'waiting';
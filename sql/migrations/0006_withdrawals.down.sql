@@ -0,0 +1,7 @@
+update copies set status = 'available' where status = 'withdrawn';
+
+alter table copies drop column withdrawn_reason;
+alter table copies drop column withdrawn_at;
+
+alter table titles drop column withdrawn_reason;
+alter table titles drop column withdrawn_at;
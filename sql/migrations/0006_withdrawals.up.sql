@@ -0,0 +1,8 @@
+-- titles and copies are never deleted so that loan, hold and fine history
+-- keeps pointing at something. withdrawing takes them out of circulation.
+
+alter table titles add column withdrawn_at timestamp default null;
+alter table titles add column withdrawn_reason varchar(255) default null;
+
+alter table copies add column withdrawn_at timestamp default null;
+alter table copies add column withdrawn_reason varchar(255) default null;
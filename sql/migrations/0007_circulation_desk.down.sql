@@ -0,0 +1,5 @@
+alter table transactions drop column returned_by;
+alter table transactions drop column issued_by;
+
+alter table users drop column card_number;
+drop sequence if exists library_card_seq;
@@ -0,0 +1,10 @@
+-- every patron gets a library card number the desk can look them up by.
+-- the default is volatile so existing users each get their own number.
+create sequence if not exists library_card_seq;
+alter table users add column card_number varchar(20) unique
+    default 'LC' || lpad(cast(nextval('library_card_seq') as text), 8, '0');
+alter table users alter column card_number set not null;
+
+-- who checked a copy in or out at the desk, null for self service
+alter table transactions add column issued_by uuid references users(id) default null;
+alter table transactions add column returned_by uuid references users(id) default null;
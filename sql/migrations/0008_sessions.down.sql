@@ -0,0 +1,2 @@
+drop table if exists revoked_tokens;
+drop table if exists sessions;
@@ -0,0 +1,25 @@
+-- one row per refresh token. rotating a token marks the old row and inserts
+-- a new one in the same family, so a rotated token showing up again means
+-- it was stolen and the whole family gets revoked.
+create table if not exists sessions(
+    id uuid primary key default uuid_generate_v4(),
+    user_id uuid references users(id) not null ,
+    family_id uuid not null ,
+    token_hash varchar(64) unique not null ,
+    access_jti uuid not null ,
+    access_expires_at timestamp not null ,
+    created_at timestamp default now() not null ,
+    expires_at timestamp not null ,
+    rotated_at timestamp default null,
+    revoked_at timestamp default null
+);
+
+create index if not exists sessions_user on sessions(user_id) where revoked_at is null;
+create index if not exists sessions_family on sessions(family_id);
+create unique index if not exists sessions_access_jti on sessions(access_jti);
+
+-- access tokens revoked before they expire, keyed on the jti claim
+create table if not exists revoked_tokens(
+    jti uuid primary key,
+    expires_at timestamp not null
+);
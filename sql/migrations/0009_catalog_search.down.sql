@@ -0,0 +1,6 @@
+drop index if exists titles_subject;
+drop index if exists titles_search;
+
+alter table titles drop column search;
+alter table titles drop column description;
+alter table titles drop column subject;
@@ -0,0 +1,15 @@
+alter table titles add column subject varchar(255) default null;
+alter table titles add column description text default null;
+
+-- weighted document for catalog search. isbns are indexed without hyphens
+-- with the simple config so they match exactly rather than being stemmed.
+alter table titles add column search tsvector generated always as (
+    setweight(to_tsvector('english', title), 'A') ||
+    setweight(to_tsvector('simple', replace(coalesce(isbn, ''), '-', '')), 'A') ||
+    setweight(to_tsvector('english', author), 'B') ||
+    setweight(to_tsvector('english', coalesce(subject, '')), 'B') ||
+    setweight(to_tsvector('english', coalesce(description, '')), 'C')
+) stored;
+
+create index if not exists titles_search on titles using gin(search);
+create index if not exists titles_subject on titles(subject);
@@ -0,0 +1,5 @@
+alter table transactions drop column fine_cap;
+alter table transactions drop column fine_per_day;
+
+drop index if exists titles_item_type;
+alter table titles drop column item_type;
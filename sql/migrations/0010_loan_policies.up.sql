@@ -0,0 +1,8 @@
+-- the item type picks which loan rules apply to a title's copies
+alter table titles add column item_type varchar(32) not null default 'book';
+create index if not exists titles_item_type on titles(item_type);
+
+-- fine rates of the loan policy in force when the loan was issued, null for
+-- loans issued before policies existed which use the configured defaults
+alter table transactions add column fine_per_day int default null check (fine_per_day >= 0);
+alter table transactions add column fine_cap int default null check (fine_cap >= 0);
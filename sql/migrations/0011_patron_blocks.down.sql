@@ -0,0 +1,3 @@
+drop table if exists patron_blocks;
+
+alter table users drop column membership_expires_at;
@@ -0,0 +1,17 @@
+-- a patron whose membership has run out can't borrow, null never expires
+alter table users add column membership_expires_at timestamp default null;
+
+-- blocks placed by staff. blocks for overdue loans, unpaid fines and expired
+-- memberships are worked out when they are checked and never stored.
+create table if not exists patron_blocks(
+    id uuid primary key default uuid_generate_v4(),
+    user_id uuid references users(id) not null ,
+    reason varchar(255) not null ,
+    placed_by uuid references users(id) not null ,
+    placed_at timestamp default now() not null ,
+    expires_at timestamp default null,
+    lifted_by uuid references users(id) default null,
+    lifted_at timestamp default null
+);
+
+create index if not exists patron_blocks_active on patron_blocks(user_id) where lifted_at is null;
@@ -0,0 +1,3 @@
+alter table users drop column created_at;
+
+alter table users drop column deactivated_at;
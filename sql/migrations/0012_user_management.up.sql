@@ -0,0 +1,3 @@
+-- deactivated users can't log in, their loans and fines are kept
+alter table users add column deactivated_at timestamp default null;
+alter table users add column created_at timestamp default now() not null;
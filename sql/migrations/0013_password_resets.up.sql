@@ -0,0 +1,12 @@
+-- password reset tokens, stored hashed. a token works once and a new request
+-- retires the ones before it.
+create table if not exists password_resets(
+    id uuid primary key default uuid_generate_v4(),
+    user_id uuid references users(id) not null ,
+    token_hash varchar(64) unique not null ,
+    created_at timestamp default now() not null ,
+    expires_at timestamp not null ,
+    used_at timestamp default null
+);
+
+create index if not exists password_resets_user on password_resets(user_id) where used_at is null;
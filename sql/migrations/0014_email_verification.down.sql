@@ -0,0 +1,6 @@
+alter table users drop column verification_sent_at;
+
+alter table users drop column email_verified_at;
+
+-- users.email stays at varchar(254), narrowing it would fail on or truncate
+-- any longer address stored since
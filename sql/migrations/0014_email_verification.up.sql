@@ -0,0 +1,10 @@
+-- 20 characters was too short for plenty of real addresses
+alter table users alter column email type varchar(254);
+
+-- new accounts start unverified and can't borrow until the address is
+-- confirmed. verification_sent_at rate limits resending the link.
+alter table users add column email_verified_at timestamp default null;
+alter table users add column verification_sent_at timestamp default null;
+
+-- accounts from before verification existed are trusted
+update users set email_verified_at = created_at;
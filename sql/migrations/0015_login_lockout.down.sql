@@ -0,0 +1,5 @@
+drop table if exists failed_logins;
+
+alter table users drop column locked_until;
+
+alter table users drop column failed_login_count;
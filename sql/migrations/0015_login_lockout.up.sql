@@ -0,0 +1,18 @@
+-- failed logins in a row since the last good one. the password of an account
+-- isn't checked again before locked_until.
+alter table users add column failed_login_count int default 0 not null;
+alter table users add column locked_until timestamp default null;
+
+-- every refused login, kept for auditing. user_id is null when the email
+-- matched no account.
+create table if not exists failed_logins(
+    id uuid primary key default uuid_generate_v4(),
+    email varchar(254) not null ,
+    user_id uuid references users(id) default null,
+    ip varchar(64) not null ,
+    reason varchar(32) not null ,
+    attempted_at timestamp default now() not null
+);
+
+create index if not exists failed_logins_ip on failed_logins(ip, attempted_at);
+create index if not exists failed_logins_email on failed_logins(email, attempted_at);
@@ -0,0 +1,7 @@
+drop table if exists mfa_recovery_codes;
+
+alter table users drop column totp_last_step;
+
+alter table users drop column mfa_enabled_at;
+
+alter table users drop column totp_secret;
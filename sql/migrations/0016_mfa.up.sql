@@ -0,0 +1,16 @@
+-- authenticator app codes as a second factor. totp_secret is set when setup
+-- starts and mfa_enabled_at once the first code confirms it. totp_last_step
+-- is the period of the last accepted code, so a code can't be used twice.
+alter table users add column totp_secret varchar(64) default null;
+alter table users add column mfa_enabled_at timestamp default null;
+alter table users add column totp_last_step bigint default null;
+
+-- single use codes for signing in without the authenticator, stored hashed
+create table if not exists mfa_recovery_codes(
+    id uuid primary key default uuid_generate_v4(),
+    user_id uuid references users(id) not null ,
+    code_hash varchar(64) not null ,
+    used_at timestamp default null
+);
+
+create index if not exists mfa_recovery_codes_user on mfa_recovery_codes(user_id, code_hash) where used_at is null;